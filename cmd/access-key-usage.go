@@ -0,0 +1,71 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// accessKeyUsage - when and from where an access key last
+// authenticated a request.
+type accessKeyUsage struct {
+	LastUsed time.Time `json:"lastUsed"`
+	SourceIP string    `json:"sourceIP"`
+	// Server the request was sent to.
+	Node string `json:"node,omitempty"`
+}
+
+// accessKeyTracker - last use of the access keys authenticating the
+// requests handled by this server, kept in memory only.
+type accessKeyTracker struct {
+	mutex sync.Mutex
+	keys  map[string]accessKeyUsage
+}
+
+// Last use of the access keys on this server.
+var globalAccessKeyUsage = &accessKeyTracker{keys: make(map[string]accessKeyUsage)}
+
+// Record - records that accessKey authenticated a request sent from
+// remoteAddr.
+func (t *accessKeyTracker) Record(accessKey, remoteAddr string) {
+	if accessKey == "" {
+		return
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	t.mutex.Lock()
+	t.keys[accessKey] = accessKeyUsage{
+		LastUsed: time.Now().UTC(),
+		SourceIP: host,
+	}
+	t.mutex.Unlock()
+}
+
+// List - returns the last use of the access keys, keyed by access key.
+func (t *accessKeyTracker) List() map[string]accessKeyUsage {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	keys := make(map[string]accessKeyUsage, len(t.keys))
+	for accessKey, usage := range t.keys {
+		keys[accessKey] = usage
+	}
+	return keys
+}
@@ -19,11 +19,16 @@ package cmd
 import (
 	"encoding/json"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/minio/minio-go/pkg/set"
 )
 
 const (
@@ -36,6 +41,7 @@ type mgmtQueryKey string
 // Only valid query params for list/clear locks management APIs.
 const (
 	mgmtBucket    mgmtQueryKey = "bucket"
+	mgmtNewBucket mgmtQueryKey = "new-bucket"
 	mgmtObject    mgmtQueryKey = "object"
 	mgmtPrefix    mgmtQueryKey = "prefix"
 	mgmtOlderThan mgmtQueryKey = "older-than"
@@ -43,6 +49,16 @@ const (
 	mgmtMarker    mgmtQueryKey = "marker"
 	mgmtMaxKey    mgmtQueryKey = "max-key"
 	mgmtDryRun    mgmtQueryKey = "dry-run"
+	mgmtState     mgmtQueryKey = "state"
+	mgmtCreds     mgmtQueryKey = "credentials"
+	mgmtCount     mgmtQueryKey = "count"
+	mgmtDays      mgmtQueryKey = "days"
+	mgmtAccessKey mgmtQueryKey = "accessKey"
+	mgmtName      mgmtQueryKey = "name"
+	mgmtQuota     mgmtQueryKey = "quota"
+	mgmtRequestID mgmtQueryKey = "id"
+	mgmtUpload    mgmtQueryKey = "uploadLimit"
+	mgmtDownload  mgmtQueryKey = "downloadLimit"
 )
 
 // ServiceStatusHandler - GET /?service
@@ -68,6 +84,35 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
+// ServiceHealthHandler - GET /?service
+// HTTP header x-minio-operation: health
+// ----------
+// Summarizes in one document the servers online, the disks online,
+// offline and unwritable, the objects waiting to be healed, the locks
+// held and the requests served by all the servers, along with the
+// disk space and the data usage, for dashboards and alerting.
+func (adminAPI adminAPIHandlers) ServiceHealthHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(getClusterHealth(objLayer, globalAdminPeers))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal cluster health into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // ServiceRestartHandler - POST /?service
 // HTTP header x-minio-operation: restart
 // ----------
@@ -273,6 +318,115 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
+// Number of locks returned by top locks when count is not provided.
+const defaultTopLocksCount = 10
+
+// TopLocksHandler - GET /?lock&count=10
+// - count is an optional query parameter
+// HTTP header x-minio-operation: top
+// ---------
+// Lists the count oldest locks held across all the servers, to find
+// the operations stuck on a lock.
+func (adminAPI adminAPIHandlers) TopLocksHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	count := defaultTopLocksCount
+	if countStr := r.URL.Query().Get(string(mgmtCount)); countStr != "" {
+		var err error
+		count, err = strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			writeErrorResponse(w, ErrInvalidLockCount, r.URL)
+			return
+		}
+	}
+
+	topLocks, err := listPeerTopLocksInfo(globalAdminPeers, count)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to fetch lock information from remote nodes.")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(topLocks)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal lock information into json.")
+		return
+	}
+
+	// Reply with the oldest locks, as json.
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ListRequestsHandler - GET /?request
+// HTTP header x-minio-operation: list
+// ---------
+// Lists the S3 API requests being served by all the servers, oldest
+// first, with the bytes they transferred so far.
+func (adminAPI adminAPIHandlers) ListRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	requests, err := listPeerRequests(globalAdminPeers)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to fetch the requests from remote nodes.")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(requests)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal the requests into json.")
+		return
+	}
+
+	// Reply with the requests being served, as json.
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// KillRequestHandler - POST /?request&id=14F1E4B2D36E8C10
+// HTTP header x-minio-operation: kill
+// ---------
+// Kills the S3 API request with the ID, as returned in its
+// x-amz-request-id response header, on the server serving it. The rest
+// of its request body can not be read and the rest of its response is
+// not sent.
+func (adminAPI adminAPIHandlers) KillRequestHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	requestID := r.URL.Query().Get(string(mgmtRequestID))
+	if requestID == "" {
+		writeErrorResponse(w, ErrInvalidQueryParams, r.URL)
+		return
+	}
+
+	killed, errs := killPeerRequest(globalAdminPeers, requestID)
+	for peer, err := range errs {
+		errorIf(err, "Unable to kill request %s on peer %s.", requestID, peer)
+	}
+	if !killed {
+		if len(errs) != 0 {
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+		writeErrorResponse(w, ErrAdminNoSuchRequest, r.URL)
+		return
+	}
+	writeSuccessResponseHeadersOnly(w)
+}
+
 // validateHealQueryParams - Validates query params for heal list management API.
 func validateHealQueryParams(vars url.Values) (string, string, string, string, int, APIErrorCode) {
 	bucket := vars.Get(string(mgmtBucket))
@@ -334,7 +488,7 @@ func (adminAPI adminAPIHandlers) ListObjectsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Get the list objects to be healed.
-	objectInfos, err := objLayer.ListObjectsHeal(bucket, prefix, marker, delimiter, maxKey)
+	objectInfos, err := objLayer.ListObjectsHeal(r.Context(), bucket, prefix, marker, delimiter, maxKey)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -378,7 +532,7 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Heal the given bucket.
-	err := objLayer.HealBucket(bucket)
+	err := objLayer.HealBucket(r.Context(), bucket)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -425,7 +579,7 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Check if object exists.
-	if _, err := objLayer.GetObjectInfo(bucket, object); err != nil {
+	if _, err := objLayer.GetObjectInfo(r.Context(), bucket, object); err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -436,7 +590,7 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	err := objLayer.HealObject(bucket, object)
+	err := objLayer.HealObject(r.Context(), bucket, object)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -445,3 +599,844 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 	// Return 200 on success.
 	writeSuccessResponseHeadersOnly(w)
 }
+
+// GetServerModeHandler - GET /?mode
+// HTTP header x-minio-operation: get
+// ----------
+// Returns the read-only and maintenance modes in effect on this
+// server and its buckets.
+func (adminAPI adminAPIHandlers) GetServerModeHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalServerModes.Info())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal server modes into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// GetListingInfoHandler - GET /?listing
+// HTTP header x-minio-operation: info
+// ----------
+// Returns the directory listings limits of this server along with the
+// listings running and waiting to run, per bucket.
+func (adminAPI adminAPIHandlers) GetListingInfoHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalListingLimiter.Info())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal listing info into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// GetDataUsageInfoHandler - GET /?data-usage[&bucket=mybucket]
+// HTTP header x-minio-operation: info
+// ----------
+// Returns the object count, total size and size histogram of all the
+// buckets, or only the given bucket, and of their first level
+// prefixes, as of the last data usage crawl. The usage of the owners
+// of the objects is returned for all the buckets only.
+func (adminAPI adminAPIHandlers) GetDataUsageInfoHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if bucket != "" && !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+
+	info, err := readDataUsage(objLayer)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		errorIf(err, "Failed to read data usage.")
+		return
+	}
+	if bucket != "" {
+		usage, ok := info.Buckets[bucket]
+		// Owners are accounted over all the buckets.
+		info.Objects, info.Size, info.Buckets, info.Owners = usage.Objects, usage.Size, nil, nil
+		if ok {
+			info.Buckets = map[string]bucketUsageInfo{bucket: usage}
+		}
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal data usage into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// MonitorBandwidthHandler - GET /?bandwidth[&bucket=mybucket]
+// HTTP header x-minio-operation: monitor
+// ----------
+// Streams every second the bytes per second sent from and received
+// into the buckets, or only the given bucket, by the S3 API requests
+// this server served over the last seconds.
+func (adminAPI adminAPIHandlers) MonitorBandwidthHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if bucket != "" && !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	writeBandwidthReports(w, r, globalBandwidthMonitor, bucket, bandwidthReportInterval)
+}
+
+// GetBucketExpiryHandler - GET /?expiry&bucket=mybucket
+// HTTP header x-minio-operation: get
+// ----------
+// Returns the number of days after which the objects of the bucket
+// are removed, 0 when they are kept.
+func (adminAPI adminAPIHandlers) GetBucketExpiryHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	if _, err := objLayer.GetBucketInfo(r.Context(), bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	expiry, err := readBucketExpiry(bucket, objLayer)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(expiry)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket expiry into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetBucketExpiryHandler - POST /?expiry&bucket=mybucket&days=N
+// HTTP header x-minio-operation: set
+// ----------
+// Removes the objects of the bucket once they are older than N days,
+// 0 keeps them. Expired objects are removed in the background every
+// hour.
+func (adminAPI adminAPIHandlers) SetBucketExpiryHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	days, err := strconv.Atoi(vars.Get(string(mgmtDays)))
+	if err != nil || days < 0 {
+		writeErrorResponse(w, ErrAdminInvalidExpiryDays, r.URL)
+		return
+	}
+	if _, err = objLayer.GetBucketInfo(r.Context(), bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if err = writeBucketExpiry(bucket, bucketExpiry{Days: days}, objLayer); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// CachePrewarmHandler - POST /?cache&bucket=mybucket&prefix=p1&prefix=p2
+// HTTP header x-minio-operation: prewarm
+// ----------
+// Starts caching the objects of the bucket under the prefixes, all the
+// objects of the bucket when no prefix is given, in the background.
+// Objects not fitting in the space left in the cache are skipped. Only
+// the cache of the server receiving the request is pre-warmed.
+func (adminAPI adminAPIHandlers) CachePrewarmHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+	cacher, ok := getObjectCacher(objLayer)
+	if !ok {
+		writeErrorResponse(w, ErrAdminCacheDisabled, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	prefixes := vars[string(mgmtPrefix)]
+	for _, prefix := range prefixes {
+		if !IsValidObjectPrefix(prefix) {
+			writeErrorResponse(w, ErrInvalidObjectName, r.URL)
+			return
+		}
+	}
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	if _, err := objLayer.GetBucketInfo(r.Context(), bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if !globalCachePrewarm.Start(objLayer, cacher, bucket, prefixes) {
+		writeErrorResponse(w, ErrAdminCachePrewarmInProgress, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalCachePrewarm.Status(cacher))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal cache pre-warm status into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// CachePrewarmStatusHandler - GET /?cache
+// HTTP header x-minio-operation: status
+// ----------
+// Returns the progress of the last pre-warm of the object cache along
+// with the space used in the cache.
+func (adminAPI adminAPIHandlers) CachePrewarmStatusHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+	cacher, ok := getObjectCacher(objLayer)
+	if !ok {
+		writeErrorResponse(w, ErrAdminCacheDisabled, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalCachePrewarm.Status(cacher))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal cache pre-warm status into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetServerModeHandler - POST /?mode&state=mode[&bucket=mybucket]
+// HTTP header x-minio-operation: set
+// ----------
+// Switches the whole server, or only the given bucket, to normal,
+// read-only or maintenance mode. In a distributed setup, the mode
+// is set on all the servers in the cluster.
+func (adminAPI adminAPIHandlers) SetServerModeHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	mode := serverMode(vars.Get(string(mgmtState)))
+	if bucket != "" && !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	if !mode.isValid() {
+		writeErrorResponse(w, ErrAdminInvalidServerMode, r.URL)
+		return
+	}
+
+	for peer, err := range setServerModeOnPeers(globalAdminPeers, bucket, mode) {
+		errorIf(err, "Unable to set server mode on peer %s.", peer)
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// PromoteStandbyHandler - POST /?mode
+// HTTP header x-minio-operation: promote
+// ----------
+// Promotes the standby servers of the cluster, serving all requests
+// from then on, once they reloaded the bucket metadata. Returns the
+// addresses of the promoted servers.
+func (adminAPI adminAPIHandlers) PromoteStandbyHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	promoted, errs := promoteStandbyOnPeers(globalAdminPeers)
+	for peer, err := range errs {
+		errorIf(err, "Unable to promote standby peer %s.", peer)
+	}
+
+	jsonBytes, err := json.Marshal(struct {
+		Promoted []string `json:"promoted"`
+	}{promoted})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal promoted servers into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ReloadConfigHandler - POST /?config[&credentials=true]
+// HTTP header x-minio-operation: reload
+// ----------
+// Re-reads and applies the config on all the servers in the cluster
+// without restarting them. Credentials are reloaded only when
+// credentials=true is passed.
+func (adminAPI adminAPIHandlers) ReloadConfigHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	reloadCredentials := false
+	if value := r.URL.Query().Get(string(mgmtCreds)); value != "" {
+		var err error
+		if reloadCredentials, err = strconv.ParseBool(value); err != nil {
+			writeErrorResponse(w, ErrInvalidQueryParams, r.URL)
+			return
+		}
+	}
+
+	errs := reloadConfigOnPeers(globalAdminPeers, reloadCredentials)
+	for peer, err := range errs {
+		errorIf(err, "Unable to reload config on peer %s.", peer)
+	}
+	if _, ok := errs[globalAdminPeers[0].addr]; ok {
+		writeErrorResponse(w, ErrAdminConfigReloadFailed, r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// renameBucketPolicy - updates the resources of a bucket policy
+// moved from srcBucket to dstBucket.
+func renameBucketPolicy(policy *bucketPolicy, srcBucket, dstBucket string) {
+	srcResource := bucketARNPrefix + srcBucket
+	for i, statement := range policy.Statements {
+		resources := set.NewStringSet()
+		for resource := range statement.Resources {
+			if resource == srcResource || strings.HasPrefix(resource, srcResource+slashSeparator) {
+				resource = bucketARNPrefix + dstBucket + strings.TrimPrefix(resource, srcResource)
+			}
+			resources.Add(resource)
+		}
+		policy.Statements[i].Resources = resources
+	}
+}
+
+// RenameBucketHandler - POST /?rename&bucket=mybucket&new-bucket=newbucket
+// HTTP header x-minio-operation: bucket
+// ----------
+// Renames a bucket along with its objects, incomplete uploads and
+// configs, without copying any data. The new bucket should not exist.
+func (adminAPI adminAPIHandlers) RenameBucketHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	srcBucket := vars.Get(string(mgmtBucket))
+	dstBucket := vars.Get(string(mgmtNewBucket))
+	if !IsValidBucketName(srcBucket) || !IsValidBucketName(dstBucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	if srcBucket == dstBucket {
+		writeErrorResponse(w, ErrBucketAlreadyOwnedByYou, r.URL)
+		return
+	}
+	// Renames modify both buckets, which neither the server nor
+	// the buckets modes may prevent.
+	for _, bucket := range []string{srcBucket, dstBucket} {
+		if s3Error := globalServerModes.checkRequest(bucket, true); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Hold locks on both buckets, always in the same order.
+	lockBuckets := []string{srcBucket, dstBucket}
+	sort.Strings(lockBuckets)
+	for _, bucket := range lockBuckets {
+		bucketLock := globalNSMutex.NewNSLock(r.Context(), bucket, "")
+		bucketLock.Lock()
+		defer bucketLock.Unlock()
+	}
+
+	// Bucket names are unique across federated deployments.
+	if s3Error := checkBucketDNS(dstBucket); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := objLayer.RenameBucket(r.Context(), srcBucket, dstBucket); err != nil {
+		errorIf(err, "Unable to rename bucket %s to %s.", srcBucket, dstBucket)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// The bucket policy moved along with the bucket, its resources
+	// are updated to the new bucket name.
+	if policy, err := readBucketPolicy(dstBucket, objLayer); err == nil {
+		renameBucketPolicy(policy, srcBucket, dstBucket)
+		pCh := policyChange{BktPolicy: policy}
+		errorIf(persistAndNotifyBucketPolicyChange(dstBucket, pCh, objLayer),
+			"Unable to update bucket policy of %s.", dstBucket)
+		S3PeersUpdateBucketPolicy(srcBucket, policyChange{IsRemove: true})
+	}
+
+	// Move the bucket notification config on all peers.
+	if ncfg, err := loadNotificationConfig(dstBucket, objLayer); err == nil && ncfg != nil {
+		S3PeersUpdateBucketNotification(dstBucket, ncfg)
+		S3PeersUpdateBucketNotification(srcBucket, nil)
+	}
+
+	// Move the bucket integrity config on all peers.
+	if config, err := readBucketIntegrity(dstBucket, objLayer); err == nil && config.RequireContentMD5 {
+		S3PeersUpdateBucketIntegrity(dstBucket, &config)
+		S3PeersUpdateBucketIntegrity(srcBucket, nil)
+	}
+
+	// Move the bucket bandwidth config on all peers.
+	if config, err := readBucketBandwidth(dstBucket, objLayer); err == nil && !config.isUnlimited() {
+		S3PeersUpdateBucketBandwidth(dstBucket, &config)
+		S3PeersUpdateBucketBandwidth(srcBucket, nil)
+	}
+
+	// Share links are signed for the old bucket, drop them - ignore any errors.
+	_ = removeShareLinks(dstBucket, objLayer)
+	S3PeersUpdateBucketShareLinks(srcBucket, nil)
+
+	// Listeners are connected to the old bucket, drop them - ignore any errors.
+	_ = removeListenerConfig(dstBucket, objLayer)
+	S3PeersUpdateBucketListener(srcBucket, nil)
+
+	// Move the bucket DNS records, if published.
+	if globalBucketDNS != nil {
+		errorIf(globalBucketDNS.Delete(srcBucket), "Unable to delete DNS records of bucket %s.", srcBucket)
+		errorIf(globalBucketDNS.Put(dstBucket), "Unable to publish DNS records of bucket %s.", dstBucket)
+	}
+
+	// Return 200 on success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// addUserReq - body of add user requests.
+type addUserReq struct {
+	SecretKey string `json:"secretKey"`
+}
+
+// toAdminIAMErrCode - converts the errors of IAM updates.
+func toAdminIAMErrCode(err error) APIErrorCode {
+	switch err {
+	case errNoSuchUser:
+		return ErrAdminNoSuchUser
+	case errNoSuchPolicy:
+		return ErrAdminNoSuchPolicy
+	case errCannedPolicy:
+		return ErrAdminCannedPolicy
+	case errInvalidIAMAccessKey:
+		return ErrAdminInvalidAccessKey
+	}
+	return toAPIErrorCode(err)
+}
+
+// updateIAM - applies update to the IAM users and policies, then
+// reloads them on all the servers in the cluster.
+func updateIAM(w http.ResponseWriter, r *http.Request, update func(objLayer ObjectLayer) error) {
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+	if err := update(objLayer); err != nil {
+		writeErrorResponse(w, toAdminIAMErrCode(err), r.URL)
+		return
+	}
+	for peer, err := range reloadIAMOnPeers(globalAdminPeers) {
+		errorIf(err, "Unable to reload IAM users and policies on peer %s.", peer)
+	}
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// AddUserHandler - POST /?user&accessKey=myuser
+// HTTP header x-minio-operation: add
+// ----------
+// Adds an enabled IAM user with the secret key in the JSON body, or
+// changes the secret key of an existing user. Users have no access
+// until policies are attached to them.
+func (adminAPI adminAPIHandlers) AddUserHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	if !isIAMAccessKeyValid(accessKey) || accessKey == serverConfig.GetCredential().AccessKey {
+		writeErrorResponse(w, ErrAdminInvalidAccessKey, r.URL)
+		return
+	}
+	var req addUserReq
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxAccessPolicySize)).Decode(&req); err != nil {
+		writeErrorResponse(w, ErrInvalidRequestBody, r.URL)
+		return
+	}
+	if !isSecretKeyValid(req.SecretKey) {
+		writeErrorResponse(w, ErrAdminInvalidSecretKey, r.URL)
+		return
+	}
+
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.SetUser(objLayer, accessKey, req.SecretKey)
+	})
+}
+
+// RemoveUserHandler - POST /?user&accessKey=myuser
+// HTTP header x-minio-operation: remove
+// ----------
+// Removes an IAM user.
+func (adminAPI adminAPIHandlers) RemoveUserHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.RemoveUser(objLayer, accessKey)
+	})
+}
+
+// SetUserStatusHandler - POST /?user&accessKey=myuser&state=enabled|disabled
+// HTTP header x-minio-operation: set-status
+// ----------
+// Enables or disables an IAM user, disabled users can not
+// authenticate until enabled again.
+func (adminAPI adminAPIHandlers) SetUserStatusHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	status := vars.Get(string(mgmtState))
+	if status != iamUserEnabled && status != iamUserDisabled {
+		writeErrorResponse(w, ErrAdminInvalidUserStatus, r.URL)
+		return
+	}
+
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.SetUserStatus(objLayer, accessKey, status)
+	})
+}
+
+// SetUserQuotaHandler - POST /?user&accessKey=myuser&quota=1073741824
+// HTTP header x-minio-operation: set-quota
+// ----------
+// Sets the storage quota of an IAM user in bytes, 0 for unlimited.
+// Uploads of the user are rejected once the objects it owns, as of
+// the last data usage crawl, would exceed its quota.
+func (adminAPI adminAPIHandlers) SetUserQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	quota, err := strconv.ParseInt(vars.Get(string(mgmtQuota)), 10, 64)
+	if err != nil || quota < 0 {
+		writeErrorResponse(w, ErrAdminInvalidUserQuota, r.URL)
+		return
+	}
+
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.SetUserQuota(objLayer, accessKey, quota)
+	})
+}
+
+// SetUserBandwidthHandler - POST /?user&accessKey=myuser&uploadLimit=1048576&downloadLimit=0
+// HTTP header x-minio-operation: set-bandwidth
+// ----------
+// Sets the bandwidth limits of the transfers of an IAM user in bytes
+// per second, 0 for unlimited. They apply on top of the bandwidth
+// limits of the buckets, to every server separately.
+func (adminAPI adminAPIHandlers) SetUserBandwidthHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	var bandwidth BandwidthConfiguration
+	var uploadErr, downloadErr error
+	if upload := vars.Get(string(mgmtUpload)); upload != "" {
+		bandwidth.UploadLimit, uploadErr = strconv.ParseInt(upload, 10, 64)
+	}
+	if download := vars.Get(string(mgmtDownload)); download != "" {
+		bandwidth.DownloadLimit, downloadErr = strconv.ParseInt(download, 10, 64)
+	}
+	if uploadErr != nil || downloadErr != nil || bandwidth.UploadLimit < 0 || bandwidth.DownloadLimit < 0 {
+		writeErrorResponse(w, ErrAdminInvalidUserBandwidth, r.URL)
+		return
+	}
+
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.SetUserBandwidth(objLayer, accessKey, bandwidth)
+	})
+}
+
+// ListUsersHandler - GET /?user
+// HTTP header x-minio-operation: list
+// ----------
+// Lists the status, the policies, the quota and the usage of the IAM
+// users, keyed by their access keys.
+func (adminAPI adminAPIHandlers) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalIAMSys.ListUsers())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal IAM users into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AccessKeysLastUsedHandler - GET /?user
+// HTTP header x-minio-operation: last-used
+// ----------
+// Lists when and from which source IP the access keys, of the IAM users
+// and of the server, last authenticated a request on any of the servers,
+// keyed by access key. Access keys unused since the servers started are
+// not listed.
+func (adminAPI adminAPIHandlers) AccessKeysLastUsedHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	lastUsed, err := listPeerAccessKeysLastUsed(globalAdminPeers)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to fetch the last use of access keys from remote nodes.")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(lastUsed)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal the last use of access keys into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AddPolicyHandler - POST /?policy&name=mypolicy
+// HTTP header x-minio-operation: add
+// ----------
+// Adds or replaces the IAM policy in the body, in the format of
+// bucket policies without principals. Canned policies can not be
+// replaced.
+func (adminAPI adminAPIHandlers) AddPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	name := r.URL.Query().Get(string(mgmtName))
+	if name == "" {
+		writeErrorResponse(w, ErrInvalidQueryParams, r.URL)
+		return
+	}
+	policy, err := parseIAMPolicy(io.LimitReader(r.Body, maxAccessPolicySize))
+	if err != nil {
+		errorIf(err, "Unable to parse IAM policy %s.", name)
+		writeErrorResponse(w, ErrMalformedPolicy, r.URL)
+		return
+	}
+
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.SetPolicy(objLayer, name, policy)
+	})
+}
+
+// RemovePolicyHandler - POST /?policy&name=mypolicy
+// HTTP header x-minio-operation: remove
+// ----------
+// Removes an IAM policy, detaching it from all the users.
+func (adminAPI adminAPIHandlers) RemovePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	name := r.URL.Query().Get(string(mgmtName))
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.RemovePolicy(objLayer, name)
+	})
+}
+
+// ListPoliciesHandler - GET /?policy
+// HTTP header x-minio-operation: list
+// ----------
+// Lists the canned and added IAM policies, keyed by their names.
+func (adminAPI adminAPIHandlers) ListPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalIAMSys.ListPolicies())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal IAM policies into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AttachPolicyHandler - POST /?policy&name=mypolicy&accessKey=myuser
+// HTTP header x-minio-operation: attach
+// ----------
+// Attaches an IAM policy to a user, the requests of the user are
+// allowed when allowed by any of its policies.
+func (adminAPI adminAPIHandlers) AttachPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	name := vars.Get(string(mgmtName))
+	accessKey := vars.Get(string(mgmtAccessKey))
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.AttachPolicy(objLayer, accessKey, name)
+	})
+}
+
+// DetachPolicyHandler - POST /?policy&name=mypolicy&accessKey=myuser
+// HTTP header x-minio-operation: detach
+// ----------
+// Detaches an IAM policy from a user.
+func (adminAPI adminAPIHandlers) DetachPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	name := vars.Get(string(mgmtName))
+	accessKey := vars.Get(string(mgmtAccessKey))
+	updateIAM(w, r, func(objLayer ObjectLayer) error {
+		return globalIAMSys.DetachPolicy(objLayer, accessKey, name)
+	})
+}
@@ -18,15 +18,19 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	router "github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/objcache"
 )
 
 // cmdType - Represents different service subcomands like status, stop
@@ -220,6 +224,84 @@ func TestServiceSetCreds(t *testing.T) {
 	testServicesCmdHandler(setCreds, map[string]interface{}{"username": "minio", "password": "minio123"}, t)
 }
 
+// Test for the cluster health management REST API.
+func TestServiceHealthHandler(t *testing.T) {
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, xlDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("failed to initialize XL based object layer - %v.", err)
+	}
+	defer removeRoots(xlDirs)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	objectLock := globalNSMutex.NewNSLock(context.Background(), "mybucket", "myobject")
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	getHealth := func() clusterHealth {
+		req, err := newTestRequest("GET", "/?service", 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct health request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "health")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign health request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+		}
+		var health clusterHealth
+		if err = json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+			t.Fatalf("Failed to unmarshal health - %v", err)
+		}
+		return health
+	}
+
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	health := getHealth()
+	if health.Status != healthStatusOK || health.NodesOnline != 1 || health.NodesOffline != 0 ||
+		health.DisksOnline != 16 || health.DisksOffline != 0 || health.WriteLocks != 1 {
+		t.Fatalf("Unexpected health %#v", health)
+	}
+	if len(health.Servers) != 1 || !health.Servers[0].Online || health.Servers[0].Addr != globalMinioAddr {
+		t.Fatalf("Unexpected servers %#v", health.Servers)
+	}
+
+	// A server not answering is reported offline.
+	initGlobalAdminPeers(append(eps, &url.URL{Scheme: "http", Host: "127.0.0.1:1"}))
+	health = getHealth()
+	if health.Status != healthStatusDegraded || health.NodesOnline != 1 || health.NodesOffline != 1 {
+		t.Fatalf("Unexpected health %#v", health)
+	}
+	if offline := health.Servers[1]; offline.Online || offline.Addr != "127.0.0.1:1" || offline.Error == "" {
+		t.Fatalf("Expected an offline server, got %#v", offline)
+	}
+}
+
 // mkLockQueryVal - helper function to build lock query param.
 func mkLockQueryVal(bucket, prefix, relTimeStr string) url.Values {
 	qVal := url.Values{}
@@ -397,6 +479,219 @@ func TestClearLocksHandler(t *testing.T) {
 	}
 }
 
+// Test for top locks management REST API.
+func TestTopLocksHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	// Hold locks, the oldest one first.
+	for _, object := range []string{"oldest", "newest"} {
+		objectLock := globalNSMutex.NewNSLock(context.Background(), "mybucket", object)
+		objectLock.Lock()
+		defer objectLock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	testCases := []struct {
+		count          string
+		expectedStatus int
+		expectedLocks  []string
+	}{
+		{"", http.StatusOK, []string{"oldest", "newest"}},
+		{"1", http.StatusOK, []string{"oldest"}},
+		{"0", http.StatusBadRequest, nil},
+		{"many", http.StatusBadRequest, nil},
+	}
+	for i, test := range testCases {
+		req, err := newTestRequest("GET", "/?lock&count="+test.count, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct top locks request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "top")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d - Failed to sign top locks request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if test.expectedStatus != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d", i+1, test.expectedStatus, rec.Code)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var topLocks []TopLockInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &topLocks); err != nil {
+			t.Fatalf("Test %d - Failed to unmarshal top locks - %v", i+1, err)
+		}
+		var objects []string
+		for _, lockInfo := range topLocks {
+			if lockInfo.Node != globalMinioAddr || lockInfo.LockType != debugWLockStr {
+				t.Errorf("Test %d - Unexpected lock %#v", i+1, lockInfo)
+			}
+			objects = append(objects, lockInfo.Object)
+		}
+		if strings.Join(objects, ",") != strings.Join(test.expectedLocks, ",") {
+			t.Errorf("Test %d - Expected locks %v but received %v", i+1, test.expectedLocks, objects)
+		}
+	}
+}
+
+func TestAccessKeysLastUsedHandler(t *testing.T) {
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	globalAccessKeyUsage.Record("myuser", "10.0.0.7:49152")
+
+	req, err := newTestRequest("GET", "/?user", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct last used request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "last-used")
+	req.RemoteAddr = "10.0.0.8:49152"
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign last used request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var lastUsed map[string]accessKeyUsage
+	if err = json.Unmarshal(rec.Body.Bytes(), &lastUsed); err != nil {
+		t.Fatalf("Failed to unmarshal the last use of access keys - %v", err)
+	}
+	expectedIPs := map[string]string{
+		"myuser":       "10.0.0.7",
+		cred.AccessKey: "10.0.0.8",
+	}
+	if len(lastUsed) != len(expectedIPs) {
+		t.Fatalf("Expected the last use of %d access keys but received %v", len(expectedIPs), lastUsed)
+	}
+	for accessKey, sourceIP := range expectedIPs {
+		usage := lastUsed[accessKey]
+		if usage.SourceIP != sourceIP || usage.Node != globalMinioAddr || usage.LastUsed.IsZero() {
+			t.Errorf("Unexpected last use of %s %#v", accessKey, usage)
+		}
+	}
+}
+
+func TestRequestsHandlers(t *testing.T) {
+	resetTestGlobals()
+	// Initializing NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	req, err := http.NewRequest("GET", "http://localhost:9000/mybucket/myobject", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, r, inflight := globalInflightRequests.start(httptest.NewRecorder(), req, requestInfo{API: "GetObject", RequestID: "REQ1"})
+	defer globalInflightRequests.finish(inflight)
+
+	adminRequest := func(method, query, op string) *httptest.ResponseRecorder {
+		req, err := newTestRequest(method, "/?"+query, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, op)
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := adminRequest("GET", "request", "list")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	var list []InflightRequestInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to unmarshal the requests - %v", err)
+	}
+	if len(list) != 1 || list[0].RequestID != "REQ1" || list[0].API != "GetObject" || list[0].Node != globalMinioAddr {
+		t.Fatalf("Unexpected requests %v", list)
+	}
+
+	testCases := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{"request", http.StatusBadRequest},
+		{"request&id=REQ2", http.StatusNotFound},
+		{"request&id=REQ1", http.StatusOK},
+	}
+	for i, test := range testCases {
+		if rec = adminRequest("POST", test.query, "kill"); rec.Code != test.expectedStatus {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d", i+1, test.expectedStatus, rec.Code)
+		}
+	}
+	if r.Context().Err() == nil {
+		t.Fatal("Expected the killed request to be canceled")
+	}
+}
+
 // Test for lock query param validation helper function.
 func TestValidateLockQueryParams(t *testing.T) {
 	// reset globals.
@@ -570,13 +865,13 @@ func TestListObjectsHealHandler(t *testing.T) {
 	}
 	defer removeRoots(xlDirs)
 
-	err = objLayer.MakeBucket("mybucket")
+	err = objLayer.MakeBucket(context.Background(), "mybucket")
 	if err != nil {
 		t.Fatalf("Failed to make bucket - %v", err)
 	}
 
 	// Delete bucket after running all test cases.
-	defer objLayer.DeleteBucket("mybucket")
+	defer objLayer.DeleteBucket(context.Background(), "mybucket")
 
 	// Make objLayer available to all internal services via globalObjectAPI.
 	globalObjLayerMutex.Lock()
@@ -718,13 +1013,13 @@ func TestHealBucketHandler(t *testing.T) {
 	}
 	defer removeRoots(xlDirs)
 
-	err = objLayer.MakeBucket("mybucket")
+	err = objLayer.MakeBucket(context.Background(), "mybucket")
 	if err != nil {
 		t.Fatalf("Failed to make bucket - %v", err)
 	}
 
 	// Delete bucket after running all test cases.
-	defer objLayer.DeleteBucket("mybucket")
+	defer objLayer.DeleteBucket(context.Background(), "mybucket")
 
 	// Make objLayer available to all internal services via globalObjectAPI.
 	globalObjLayerMutex.Lock()
@@ -809,20 +1104,20 @@ func TestHealObjectHandler(t *testing.T) {
 	// Create an object myobject under bucket mybucket.
 	bucketName := "mybucket"
 	objName := "myobject"
-	err = objLayer.MakeBucket(bucketName)
+	err = objLayer.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		t.Fatalf("Failed to make bucket %s - %v", bucketName, err)
 	}
 
-	_, err = objLayer.PutObject(bucketName, objName, int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
+	_, err = objLayer.PutObject(context.Background(), bucketName, objName, int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
 	if err != nil {
 		t.Fatalf("Failed to create %s - %v", objName, err)
 	}
 
 	// Delete bucket and object after running all test cases.
 	defer func(objLayer ObjectLayer, bucketName, objName string) {
-		objLayer.DeleteObject(bucketName, objName)
-		objLayer.DeleteBucket(bucketName)
+		objLayer.DeleteObject(context.Background(), bucketName, objName)
+		objLayer.DeleteBucket(context.Background(), bucketName)
 	}(objLayer, bucketName, objName)
 
 	// Make objLayer available to all internal services via globalObjectAPI.
@@ -905,3 +1200,924 @@ func TestHealObjectHandler(t *testing.T) {
 		}
 	}
 }
+
+// Test for set and get server mode management REST APIs.
+func TestServerModeHandlers(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	defer resetGlobalServerModes()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	testCases := []struct {
+		bucket         string
+		mode           string
+		expectedStatus int
+	}{
+		// Test 1 - server wide read-only mode.
+		{"", "read-only", http.StatusOK},
+		// Test 2 - bucket maintenance mode.
+		{"mybucket", "maintenance", http.StatusOK},
+		// Test 3 - invalid mode.
+		{"mybucket", "invalid-mode", http.StatusBadRequest},
+		// Test 4 - invalid bucket name.
+		{`invalid\\Bucket`, "read-only", http.StatusBadRequest},
+	}
+
+	cred := serverConfig.GetCredential()
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("mode", "")
+		queryVal.Set(string(mgmtState), test.mode)
+		if test.bucket != "" {
+			queryVal.Set(string(mgmtBucket), test.bucket)
+		}
+		req, err := newTestRequest("POST", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct set server mode request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "set")
+
+		err = signRequestV4(req, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to sign set server mode request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if test.expectedStatus != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d", i+1, test.expectedStatus, rec.Code)
+		}
+	}
+
+	req, err := newTestRequest("GET", "/?mode", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct get server mode request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "get")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign get server mode request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var info ServerModeInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to unmarshal server modes - %v", err)
+	}
+	if info.Server != serverModeReadOnly || info.Buckets["mybucket"] != serverModeMaintenance {
+		t.Errorf("Unexpected server modes %#v", info)
+	}
+}
+
+// Tests promoting the standby servers through the admin API.
+func TestPromoteStandbyHandler(t *testing.T) {
+	resetTestGlobals()
+	defer resetGlobalServerModes()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatalf("failed to initialize FS based object layer - %v.", err)
+	}
+	defer removeAll(fsDir)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	globalServerModes.SetStandby(true)
+	if apiErr := globalServerModes.checkRequest("mybucket", true); apiErr != ErrStandbyReadOnly {
+		t.Fatalf("Expected writes to be refused by a standby, got %v", apiErr)
+	}
+
+	// Promotes the standby servers, returns the promoted ones.
+	promote := func() []string {
+		req, err := newTestRequest("POST", "/?mode", 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct promote standby request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "promote")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign promote standby request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+		}
+		var promoted struct {
+			Promoted []string `json:"promoted"`
+		}
+		if err = json.Unmarshal(rec.Body.Bytes(), &promoted); err != nil {
+			t.Fatalf("Failed to unmarshal promoted servers - %v", err)
+		}
+		return promoted.Promoted
+	}
+
+	if promoted := promote(); len(promoted) != 1 || promoted[0] != globalMinioAddr {
+		t.Fatalf("Expected %s to be promoted, got %v", globalMinioAddr, promoted)
+	}
+	if globalServerModes.IsStandby() {
+		t.Fatal("Expected the server not to be a standby once promoted")
+	}
+	if apiErr := globalServerModes.checkRequest("mybucket", true); apiErr != ErrNone {
+		t.Fatalf("Expected writes to be served once promoted, got %v", apiErr)
+	}
+
+	// Nothing left to promote.
+	if promoted := promote(); len(promoted) != 0 {
+		t.Fatalf("Expected no server to be promoted, got %v", promoted)
+	}
+}
+
+// Test for listing info management REST API.
+func TestGetListingInfoHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	globalListingLimiter.Acquire("mybucket")
+	defer globalListingLimiter.Release("mybucket")
+
+	req, err := newTestRequest("GET", "/?listing", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct listing info request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "info")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign listing info request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var info listingInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to unmarshal listing info - %v", err)
+	}
+	if info.MaxListings != defaultListConcurrency || info.Buckets["mybucket"].Active != 1 {
+		t.Errorf("Unexpected listing info %#v", info)
+	}
+}
+
+// Test for data usage management REST API.
+func TestGetDataUsageInfoHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initializing NSLock.
+	initNSLock(false)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatalf("failed to initialize FS based object layer - %v.", err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	// Make objLayer available to all internal services via globalObjectAPI.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	saved := dataUsageInfo{
+		LastUpdate: time.Now().UTC(),
+		Objects:    3,
+		Size:       30,
+		Buckets: map[string]bucketUsageInfo{
+			"mybucket": {dataUsageEntry: dataUsageEntry{Objects: 2, Size: 20}},
+			"another":  {dataUsageEntry: dataUsageEntry{Objects: 1, Size: 10}},
+		},
+	}
+	if err = writeDataUsage(objLayer, saved); err != nil {
+		t.Fatalf("Unable to save data usage - %v", err)
+	}
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	testCases := []struct {
+		bucket     string
+		statusCode int
+		objects    uint64
+		buckets    int
+	}{
+		{"", http.StatusOK, 3, 2},
+		{"mybucket", http.StatusOK, 2, 1},
+		{"missing", http.StatusOK, 0, 0},
+		{"in", http.StatusBadRequest, 0, 0},
+	}
+	for i, testCase := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("data-usage", "")
+		if testCase.bucket != "" {
+			queryVal.Set(string(mgmtBucket), testCase.bucket)
+		}
+		req, err := newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct data usage request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "info")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign data usage request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: Expected HTTP status code %d but received %d", i+1, testCase.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var info dataUsageInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+			t.Fatalf("Failed to unmarshal data usage - %v", err)
+		}
+		if info.Objects != testCase.objects || len(info.Buckets) != testCase.buckets {
+			t.Errorf("Test %d: Unexpected data usage %#v", i+1, info)
+		}
+	}
+}
+
+// Test for bucket expiry management REST API.
+func TestBucketExpiryHandlers(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initializing NSLock.
+	initNSLock(false)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatalf("failed to initialize FS based object layer - %v.", err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	// Make objLayer available to all internal services via globalObjectAPI.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	if err = objLayer.MakeBucket(context.Background(), "mybucket"); err != nil {
+		t.Fatalf("Unable to create bucket - %v", err)
+	}
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	expiryRequest := func(method, op string, queryVal url.Values) *httptest.ResponseRecorder {
+		queryVal.Set("expiry", "")
+		req, err := newTestRequest(method, "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct bucket expiry request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, op)
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign bucket expiry request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	setCases := []struct {
+		bucket     string
+		days       string
+		statusCode int
+	}{
+		{"mybucket", "7", http.StatusOK},
+		{"missing", "7", http.StatusNotFound},
+		{"in", "7", http.StatusBadRequest},
+		{"mybucket", "-1", http.StatusBadRequest},
+		{"mybucket", "week", http.StatusBadRequest},
+	}
+	for i, testCase := range setCases {
+		rec := expiryRequest("POST", "set", url.Values{
+			string(mgmtBucket): []string{testCase.bucket},
+			string(mgmtDays):   []string{testCase.days},
+		})
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: Expected HTTP status code %d but received %d", i+1, testCase.statusCode, rec.Code)
+		}
+	}
+
+	rec := expiryRequest("GET", "get", url.Values{string(mgmtBucket): []string{"mybucket"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	var expiry bucketExpiry
+	if err = json.Unmarshal(rec.Body.Bytes(), &expiry); err != nil {
+		t.Fatalf("Failed to unmarshal bucket expiry - %v", err)
+	}
+	if expiry.Days != 7 {
+		t.Errorf("Expected an expiry of 7 days, got %d", expiry.Days)
+	}
+	if rec = expiryRequest("GET", "get", url.Values{string(mgmtBucket): []string{"missing"}}); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected HTTP status code %d but received %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// Test for bandwidth monitoring management REST API.
+func TestMonitorBandwidthHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	globalBandwidthMonitor.add("mybucket", 1000, 0)
+	globalBandwidthMonitor.add("another", 0, 1000)
+
+	req, err := newTestRequest("GET", "/?bandwidth&bucket=mybucket", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct bandwidth monitor request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "monitor")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign bandwidth monitor request - %v", err)
+	}
+	// The client goes away after the first report.
+	ctx, cancel := context.WithCancel(req.Context())
+	cancel()
+	rec := httptest.NewRecorder()
+	adminRouter.ServeHTTP(rec, req.WithContext(ctx))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var report bandwidthReport
+	if err = json.Unmarshal(bytes.TrimSuffix(rec.Body.Bytes(), crlf), &report); err != nil {
+		t.Fatalf("Failed to unmarshal bandwidth report - %v", err)
+	}
+	if len(report.Buckets) != 1 || report.Buckets["mybucket"].SentPerSecond == 0 {
+		t.Errorf("Unexpected bandwidth report %#v", report)
+	}
+}
+
+// Test for config reload management REST API.
+func TestReloadConfigHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	serverConfig.SetRegion("us-west-1")
+	if err = serverConfig.Save(); err != nil {
+		t.Fatal(err)
+	}
+	serverConfig.SetRegion(globalMinioDefaultRegion)
+
+	testCases := []struct {
+		credentials    string
+		expectedStatus int
+	}{
+		// Test 1 - reload keeping credentials.
+		{"", http.StatusOK},
+		// Test 2 - reload including credentials.
+		{"true", http.StatusOK},
+		// Test 3 - invalid credentials flag.
+		{"sometimes", http.StatusBadRequest},
+	}
+
+	cred := serverConfig.GetCredential()
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("config", "")
+		if test.credentials != "" {
+			queryVal.Set(string(mgmtCreds), test.credentials)
+		}
+		req, err := newTestRequest("POST", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct reload config request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "reload")
+
+		err = signRequestV4(req, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to sign reload config request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if test.expectedStatus != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d", i+1, test.expectedStatus, rec.Code)
+		}
+	}
+
+	if serverConfig.GetRegion() != "us-west-1" {
+		t.Fatalf("Expected reloaded region us-west-1, got %s", serverConfig.GetRegion())
+	}
+}
+
+// TestRenameBucketHandler - Test for RenameBucketHandler.
+func TestRenameBucketHandler(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testRenameBucketHandler)
+}
+
+func testRenameBucketHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	for _, bucket := range []string{"mybucket", "existingbucket"} {
+		if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("%s: Failed to make bucket - %v", instanceType, err)
+		}
+	}
+	objects := map[string]string{
+		"object":        "hello",
+		"prefix/object": "world",
+	}
+	for object, data := range objects {
+		_, err := obj.PutObject(context.Background(), "mybucket", object, int64(len(data)), bytes.NewBufferString(data), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Failed to put object - %v", instanceType, err)
+		}
+	}
+	uploadID, err := obj.NewMultipartUpload(context.Background(), "mybucket", "upload", nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to start upload - %v", instanceType, err)
+	}
+	policy := &bucketPolicy{
+		Version:    "1.0",
+		Statements: getReadOnlyStatement("mybucket", ""),
+	}
+	if err = writeBucketPolicy("mybucket", obj, policy); err != nil {
+		t.Fatalf("%s: Failed to write bucket policy - %v", instanceType, err)
+	}
+
+	// Make objLayer available to all internal services via globalObjectAPI.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	// Read-only buckets can not be renamed, nor renamed into.
+	defer resetGlobalServerModes()
+	globalServerModes = newServerModes()
+	globalServerModes.Set("readonlybucket", serverModeReadOnly)
+	globalServerModes.Set("frozenbucket", serverModeReadOnly)
+
+	testCases := []struct {
+		bucket     string
+		newBucket  string
+		statusCode int
+	}{
+		// 1. Invalid new bucket name.
+		{"mybucket", `invalid\\Bucket`, http.StatusBadRequest},
+		// 2. Bucket not found.
+		{"bucketnotfound", "newbucket", http.StatusNotFound},
+		// 3. New bucket already exists.
+		{"mybucket", "existingbucket", http.StatusConflict},
+		// 4. Read-only bucket.
+		{"readonlybucket", "otherbucket", http.StatusServiceUnavailable},
+		// 5. Read-only new bucket.
+		{"mybucket", "frozenbucket", http.StatusServiceUnavailable},
+		// 6. Valid test case.
+		{"mybucket", "newbucket", http.StatusOK},
+	}
+	cred := serverConfig.GetCredential()
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("rename", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+		queryVal.Set(string(mgmtNewBucket), test.newBucket)
+
+		req, err := newTestRequest("POST", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct rename bucket request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "bucket")
+
+		err = signRequestV4(req, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to sign rename bucket request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Fatalf("Test %d: %s: Expected HTTP status code %d but received %d", i+1, instanceType, test.statusCode, rec.Code)
+		}
+	}
+
+	if _, err = obj.GetBucketInfo(context.Background(), "mybucket"); !isErrBucketNotFound(err) {
+		t.Fatalf("%s: Expected mybucket to be gone, got %v", instanceType, err)
+	}
+	for object, data := range objects {
+		var buffer bytes.Buffer
+		if err = obj.GetObject(context.Background(), "newbucket", object, 0, int64(len(data)), &buffer); err != nil {
+			t.Fatalf("%s: Failed to get renamed object %s - %v", instanceType, object, err)
+		}
+		if buffer.String() != data {
+			t.Errorf("%s: Expected %s to contain %q, got %q", instanceType, object, data, buffer.String())
+		}
+	}
+	uploads, err := obj.ListMultipartUploads(context.Background(), "newbucket", "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s: Failed to list uploads - %v", instanceType, err)
+	}
+	if len(uploads.Uploads) != 1 || uploads.Uploads[0].UploadID != uploadID {
+		t.Errorf("%s: Expected upload %s to be renamed, got %v", instanceType, uploadID, uploads.Uploads)
+	}
+	policy, err = readBucketPolicy("newbucket", obj)
+	if err != nil {
+		t.Fatalf("%s: Failed to read renamed bucket policy - %v", instanceType, err)
+	}
+	for _, statement := range policy.Statements {
+		for resource := range statement.Resources {
+			if !strings.HasPrefix(resource, bucketARNPrefix+"newbucket") {
+				t.Errorf("%s: Expected policy resource on newbucket, got %s", instanceType, resource)
+			}
+		}
+	}
+}
+
+// Test for pre-warming the object cache through the admin API.
+func TestCachePrewarmHandlers(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initializing NSLock.
+	initNSLock(false)
+
+	objLayer, xlDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("failed to initialize XL based object layer - %v.", err)
+	}
+	defer removeRoots(xlDirs)
+
+	// Cache objects up to 100KiB.
+	xl := objLayer.(*xlObjects)
+	xl.objCacheEnabled = true
+	xl.objCache = objcache.New(1024*1024, objcache.NoExpiry)
+
+	// Make objLayer available to all internal services via globalObjectAPI.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	if err = objLayer.MakeBucket(context.Background(), "mybucket"); err != nil {
+		t.Fatalf("Unable to create bucket - %v", err)
+	}
+	objects := map[string]int{
+		"data/a":     10,
+		"data/b":     20,
+		"data/empty": 0,
+		"data/large": 200 * 1024,
+		"other/c":    10,
+	}
+	for object, size := range objects {
+		data := bytes.Repeat([]byte("a"), size)
+		if _, err = objLayer.PutObject(context.Background(), "mybucket", object, int64(size), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("Unable to create object %s - %v", object, err)
+		}
+	}
+	// Writes cache the objects, start from an empty cache.
+	xl.objCache = objcache.New(1024*1024, objcache.NoExpiry)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	cacheRequest := func(method, op string, queryVal url.Values) *httptest.ResponseRecorder {
+		queryVal.Set("cache", "")
+		req, err := newTestRequest(method, "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct cache request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, op)
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign cache request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	getStatus := func() cachePrewarmStatus {
+		rec := cacheRequest("GET", "status", url.Values{})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+		}
+		var status cachePrewarmStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to unmarshal cache pre-warm status - %v", err)
+		}
+		return status
+	}
+
+	if status := getStatus(); status.Running || status.CacheMaxSize != 1024*1024 {
+		t.Fatalf("Unexpected status before pre-warming %+v", status)
+	}
+
+	failCases := []struct {
+		bucket     string
+		statusCode int
+	}{
+		{"missing", http.StatusNotFound},
+		{"in", http.StatusBadRequest},
+	}
+	for i, testCase := range failCases {
+		rec := cacheRequest("POST", "prewarm", url.Values{string(mgmtBucket): []string{testCase.bucket}})
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: Expected HTTP status code %d but received %d", i+1, testCase.statusCode, rec.Code)
+		}
+	}
+
+	rec := cacheRequest("POST", "prewarm", url.Values{
+		string(mgmtBucket): []string{"mybucket"},
+		string(mgmtPrefix): []string{"data/"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	status := getStatus()
+	for deadline := time.Now().Add(10 * time.Second); status.Running && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		status = getStatus()
+	}
+	if status.Running || status.Error != "" {
+		t.Fatalf("Expected the pre-warm to succeed, got %+v", status)
+	}
+	if status.Objects != 4 || status.Cached != 2 || status.CachedBytes != 30 ||
+		status.Skipped != 2 || status.SkippedBytes != 200*1024 || status.Failed != 0 {
+		t.Errorf("Unexpected progress of the pre-warm %+v", status)
+	}
+	if status.CacheSize != 30 || status.CacheEntries != 2 {
+		t.Errorf("Unexpected cache usage after the pre-warm %+v", status)
+	}
+	if xl.isCached("mybucket", "other/c", time.Time{}) {
+		t.Errorf("Expected objects outside of the prefixes not to be cached")
+	}
+
+	xl.objCacheEnabled = false
+	if rec = cacheRequest("GET", "status", url.Values{}); rec.Code != http.StatusNotImplemented {
+		t.Errorf("Expected HTTP status code %d but received %d", http.StatusNotImplemented, rec.Code)
+	}
+}
+
+// Test for IAM user and policy management REST API.
+func TestIAMHandlers(t *testing.T) {
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+
+	// Initializing NSLock.
+	initNSLock(false)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatalf("failed to initialize FS based object layer - %v.", err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	// Make objLayer available to all internal services via globalObjectAPI.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Setup admin mgmt REST API handlers.
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	iamRequest := func(method, resource, op string, queryVal url.Values, body []byte) *httptest.ResponseRecorder {
+		queryVal.Set(resource, "")
+		req, err := newTestRequest(method, "/?"+queryVal.Encode(), int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to construct IAM request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, op)
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign IAM request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	user := func(accessKey string) url.Values {
+		return url.Values{string(mgmtAccessKey): []string{accessKey}}
+	}
+	policy := func(name, accessKey string) url.Values {
+		queryVal := url.Values{string(mgmtName): []string{name}}
+		if accessKey != "" {
+			queryVal.Set(string(mgmtAccessKey), accessKey)
+		}
+		return queryVal
+	}
+	policyJSON := []byte(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`)
+
+	testCases := []struct {
+		method, resource, op string
+		queryVal             url.Values
+		body                 []byte
+		statusCode           int
+	}{
+		// Test 1 - add a user.
+		{"POST", "user", "add", user("myuser"), []byte(`{"secretKey":"mysecretkey"}`), http.StatusOK},
+		// Test 2 - secret key too short.
+		{"POST", "user", "add", user("otheruser"), []byte(`{"secretKey":"short"}`), http.StatusBadRequest},
+		// Test 3 - invalid body.
+		{"POST", "user", "add", user("otheruser"), []byte(`{"secretKey":`), http.StatusBadRequest},
+		// Test 4 - access keys of the server credentials are reserved.
+		{"POST", "user", "add", user(serverConfig.GetCredential().AccessKey), []byte(`{"secretKey":"mysecretkey"}`), http.StatusBadRequest},
+		// Test 5 - add a policy.
+		{"POST", "policy", "add", policy("mypolicy", ""), policyJSON, http.StatusOK},
+		// Test 6 - malformed policy.
+		{"POST", "policy", "add", policy("badpolicy", ""), []byte(`{"Version":"2012-10-17"}`), http.StatusBadRequest},
+		// Test 7 - canned policies can not be replaced.
+		{"POST", "policy", "add", policy("readonly", ""), policyJSON, http.StatusBadRequest},
+		// Test 8 - attach policies.
+		{"POST", "policy", "attach", policy("mypolicy", "myuser"), nil, http.StatusOK},
+		{"POST", "policy", "attach", policy("readonly", "myuser"), nil, http.StatusOK},
+		// Test 10 - attach missing policy and to missing user.
+		{"POST", "policy", "attach", policy("missing", "myuser"), nil, http.StatusNotFound},
+		{"POST", "policy", "attach", policy("mypolicy", "missing"), nil, http.StatusNotFound},
+		// Test 12 - detach a policy.
+		{"POST", "policy", "detach", policy("readonly", "myuser"), nil, http.StatusOK},
+		{"POST", "policy", "detach", policy("readonly", "myuser"), nil, http.StatusNotFound},
+		// Test 14 - disable a user.
+		{"POST", "user", "set-status", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtState): []string{"disabled"}}, nil, http.StatusOK},
+		{"POST", "user", "set-status", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtState): []string{"gone"}}, nil, http.StatusBadRequest},
+		{"POST", "user", "set-status", url.Values{string(mgmtAccessKey): []string{"missing"}, string(mgmtState): []string{"enabled"}}, nil, http.StatusNotFound},
+		// Test 17 - set the quota of a user.
+		{"POST", "user", "set-quota", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtQuota): []string{"1048576"}}, nil, http.StatusOK},
+		{"POST", "user", "set-quota", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtQuota): []string{"-1"}}, nil, http.StatusBadRequest},
+		{"POST", "user", "set-quota", url.Values{string(mgmtAccessKey): []string{"missing"}, string(mgmtQuota): []string{"0"}}, nil, http.StatusNotFound},
+		// Test 20 - set the bandwidth limits of a user.
+		{"POST", "user", "set-bandwidth", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtUpload): []string{"1048576"}}, nil, http.StatusOK},
+		{"POST", "user", "set-bandwidth", url.Values{string(mgmtAccessKey): []string{"myuser"}, string(mgmtDownload): []string{"-1"}}, nil, http.StatusBadRequest},
+		{"POST", "user", "set-bandwidth", url.Values{string(mgmtAccessKey): []string{"missing"}, string(mgmtUpload): []string{"0"}}, nil, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := iamRequest(testCase.method, testCase.resource, testCase.op, testCase.queryVal, testCase.body)
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: Expected HTTP status code %d but received %d", i+1, testCase.statusCode, rec.Code)
+		}
+	}
+
+	rec := iamRequest("GET", "user", "list", url.Values{}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	var users map[string]iamUserInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &users); err != nil {
+		t.Fatalf("Failed to unmarshal users - %v", err)
+	}
+	if len(users) != 1 || users["myuser"].Status != iamUserDisabled ||
+		len(users["myuser"].Policies) != 1 || users["myuser"].Policies[0] != "mypolicy" ||
+		users["myuser"].Quota != 1048576 || users["myuser"].Bandwidth == nil ||
+		users["myuser"].Bandwidth.UploadLimit != 1048576 {
+		t.Errorf("Unexpected users %v", users)
+	}
+	if strings.Contains(rec.Body.String(), "mysecretkey") {
+		t.Errorf("Expected secret keys not to be listed")
+	}
+
+	rec = iamRequest("GET", "policy", "list", url.Values{}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	var policies map[string]json.RawMessage
+	if err = json.Unmarshal(rec.Body.Bytes(), &policies); err != nil {
+		t.Fatalf("Failed to unmarshal policies - %v", err)
+	}
+	for _, name := range []string{"readonly", "writeonly", "readwrite", "mypolicy"} {
+		if _, ok := policies[name]; !ok {
+			t.Errorf("Expected policy %s to be listed", name)
+		}
+	}
+
+	// Removing a policy detaches it from the users.
+	if rec = iamRequest("POST", "policy", "remove", policy("mypolicy", ""), nil); rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	if policies := globalIAMSys.ListUsers()["myuser"].Policies; len(policies) != 0 {
+		t.Errorf("Expected removed policy to be detached, got %v", policies)
+	}
+	if rec = iamRequest("POST", "policy", "remove", policy("readwrite", ""), nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected HTTP status code %d but received %d", http.StatusBadRequest, rec.Code)
+	}
+
+	// Users and policies are persisted.
+	if err = globalIAMSys.Load(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := globalIAMSys.ListUsers()["myuser"]; !ok {
+		t.Errorf("Expected myuser to be persisted")
+	}
+
+	if rec = iamRequest("POST", "user", "remove", user("myuser"), nil); rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+	if rec = iamRequest("POST", "user", "remove", user("myuser"), nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected HTTP status code %d but received %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -33,6 +33,8 @@ func registerAdminRouter(mux *router.Router) {
 
 	// Service status
 	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.ServiceStatusHandler)
+	// Cluster health summary
+	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "health").HandlerFunc(adminAPI.ServiceHealthHandler)
 
 	// Service restart
 	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "restart").HandlerFunc(adminAPI.ServiceRestartHandler)
@@ -43,9 +45,18 @@ func registerAdminRouter(mux *router.Router) {
 
 	// List Locks
 	adminRouter.Methods("GET").Queries("lock", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListLocksHandler)
+	// Oldest locks held
+	adminRouter.Methods("GET").Queries("lock", "").Headers(minioAdminOpHeader, "top").HandlerFunc(adminAPI.TopLocksHandler)
 	// Clear locks
 	adminRouter.Methods("POST").Queries("lock", "").Headers(minioAdminOpHeader, "clear").HandlerFunc(adminAPI.ClearLocksHandler)
 
+	/// Request operations
+
+	// List the requests being served.
+	adminRouter.Methods("GET").Queries("request", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListRequestsHandler)
+	// Kill a request being served.
+	adminRouter.Methods("POST").Queries("request", "").Headers(minioAdminOpHeader, "kill").HandlerFunc(adminAPI.KillRequestHandler)
+
 	/// Heal operations
 
 	// List Objects needing heal.
@@ -54,4 +65,82 @@ func registerAdminRouter(mux *router.Router) {
 	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "bucket").HandlerFunc(adminAPI.HealBucketHandler)
 	// Heal Objects.
 	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "object").HandlerFunc(adminAPI.HealObjectHandler)
+
+	/// Rename operations
+
+	// Rename Buckets.
+	adminRouter.Methods("POST").Queries("rename", "").Headers(minioAdminOpHeader, "bucket").HandlerFunc(adminAPI.RenameBucketHandler)
+
+	/// Server mode operations
+
+	// Get read-only and maintenance modes.
+	adminRouter.Methods("GET").Queries("mode", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetServerModeHandler)
+	// Set read-only and maintenance modes.
+	adminRouter.Methods("POST").Queries("mode", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetServerModeHandler)
+	// Promote standby servers.
+	adminRouter.Methods("POST").Queries("mode", "").Headers(minioAdminOpHeader, "promote").HandlerFunc(adminAPI.PromoteStandbyHandler)
+
+	/// Listing operations
+
+	// Get listing limits and queue depth.
+	adminRouter.Methods("GET").Queries("listing", "").Headers(minioAdminOpHeader, "info").HandlerFunc(adminAPI.GetListingInfoHandler)
+
+	/// Data usage operations
+
+	// Get data usage of the buckets and their prefixes.
+	adminRouter.Methods("GET").Queries("data-usage", "").Headers(minioAdminOpHeader, "info").HandlerFunc(adminAPI.GetDataUsageInfoHandler)
+
+	/// Bandwidth operations
+
+	// Stream the throughput of the buckets.
+	adminRouter.Methods("GET").Queries("bandwidth", "").Headers(minioAdminOpHeader, "monitor").HandlerFunc(adminAPI.MonitorBandwidthHandler)
+
+	/// Bucket expiry operations
+
+	// Get the expiry of the objects of a bucket.
+	adminRouter.Methods("GET").Queries("expiry", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetBucketExpiryHandler)
+	// Set the expiry of the objects of a bucket.
+	adminRouter.Methods("POST").Queries("expiry", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetBucketExpiryHandler)
+
+	/// Object cache operations
+
+	// Pre-warm the object cache with the objects under prefixes.
+	adminRouter.Methods("POST").Queries("cache", "").Headers(minioAdminOpHeader, "prewarm").HandlerFunc(adminAPI.CachePrewarmHandler)
+	// Get the progress of the pre-warm and the object cache usage.
+	adminRouter.Methods("GET").Queries("cache", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.CachePrewarmStatusHandler)
+
+	/// IAM user operations
+
+	// Add a user or change its secret key.
+	adminRouter.Methods("POST").Queries("user", "").Headers(minioAdminOpHeader, "add").HandlerFunc(adminAPI.AddUserHandler)
+	// Remove a user.
+	adminRouter.Methods("POST").Queries("user", "").Headers(minioAdminOpHeader, "remove").HandlerFunc(adminAPI.RemoveUserHandler)
+	// Enable or disable a user.
+	adminRouter.Methods("POST").Queries("user", "").Headers(minioAdminOpHeader, "set-status").HandlerFunc(adminAPI.SetUserStatusHandler)
+	// Set the storage quota of a user.
+	adminRouter.Methods("POST").Queries("user", "").Headers(minioAdminOpHeader, "set-quota").HandlerFunc(adminAPI.SetUserQuotaHandler)
+	// Set the bandwidth limits of a user.
+	adminRouter.Methods("POST").Queries("user", "").Headers(minioAdminOpHeader, "set-bandwidth").HandlerFunc(adminAPI.SetUserBandwidthHandler)
+	// List users.
+	adminRouter.Methods("GET").Queries("user", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListUsersHandler)
+	// List the last use of the access keys.
+	adminRouter.Methods("GET").Queries("user", "").Headers(minioAdminOpHeader, "last-used").HandlerFunc(adminAPI.AccessKeysLastUsedHandler)
+
+	/// IAM policy operations
+
+	// Add or replace a policy.
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "add").HandlerFunc(adminAPI.AddPolicyHandler)
+	// Remove a policy.
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "remove").HandlerFunc(adminAPI.RemovePolicyHandler)
+	// List canned and added policies.
+	adminRouter.Methods("GET").Queries("policy", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListPoliciesHandler)
+	// Attach a policy to a user.
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "attach").HandlerFunc(adminAPI.AttachPolicyHandler)
+	// Detach a policy from a user.
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "detach").HandlerFunc(adminAPI.DetachPolicyHandler)
+
+	/// Config operations
+
+	// Reload config.
+	adminRouter.Methods("POST").Queries("config", "").Headers(minioAdminOpHeader, "reload").HandlerFunc(adminAPI.ReloadConfigHandler)
 }
@@ -19,6 +19,7 @@ package cmd
 import (
 	"net/url"
 	"path"
+	"sort"
 	"sync"
 	"time"
 )
@@ -38,6 +39,15 @@ type remoteAdminClient struct {
 type adminCmdRunner interface {
 	Restart() error
 	ListLocks(bucket, prefix string, relTime time.Duration) ([]VolumeLockInfo, error)
+	TopLocks(count int) ([]TopLockInfo, error)
+	SetServerMode(bucket string, mode serverMode) error
+	ReloadConfig(reloadCredentials bool) error
+	ReloadIAM() error
+	AccessKeysLastUsed() (map[string]accessKeyUsage, error)
+	ListRequests() ([]InflightRequestInfo, error)
+	KillRequest(requestID string) (bool, error)
+	ServerHealth() (serverHealth, error)
+	PromoteStandby() (bool, error)
 }
 
 // Restart - Sends a message over channel to the go-routine
@@ -52,6 +62,56 @@ func (lc localAdminClient) ListLocks(bucket, prefix string, relTime time.Duratio
 	return listLocksInfo(bucket, prefix, relTime), nil
 }
 
+// TopLocks - Fetches the oldest locks from local lock instrumentation.
+func (lc localAdminClient) TopLocks(count int) ([]TopLockInfo, error) {
+	return listTopLocksInfo(count), nil
+}
+
+// SetServerMode - Sets the operating mode of this server or bucket.
+func (lc localAdminClient) SetServerMode(bucket string, mode serverMode) error {
+	globalServerModes.Set(bucket, mode)
+	return nil
+}
+
+// ReloadConfig - Reloads the config of this server.
+func (lc localAdminClient) ReloadConfig(reloadCredentials bool) error {
+	return reloadConfig(reloadCredentials)
+}
+
+// ReloadIAM - Reloads the IAM users and policies of this server.
+func (lc localAdminClient) ReloadIAM() error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	return globalIAMSys.Load(objAPI)
+}
+
+// AccessKeysLastUsed - Fetches the last use of the access keys on this server.
+func (lc localAdminClient) AccessKeysLastUsed() (map[string]accessKeyUsage, error) {
+	return globalAccessKeyUsage.List(), nil
+}
+
+// ListRequests - Fetches the requests being served by this server.
+func (lc localAdminClient) ListRequests() ([]InflightRequestInfo, error) {
+	return globalInflightRequests.List(), nil
+}
+
+// KillRequest - Kills the request of this server with requestID.
+func (lc localAdminClient) KillRequest(requestID string) (bool, error) {
+	return globalInflightRequests.Kill(requestID), nil
+}
+
+// ServerHealth - Fetches the health of this server.
+func (lc localAdminClient) ServerHealth() (serverHealth, error) {
+	return getLocalServerHealth(), nil
+}
+
+// PromoteStandby - Promotes this server if it is a standby.
+func (lc localAdminClient) PromoteStandby() (bool, error) {
+	return promoteStandby()
+}
+
 // Restart - Sends restart command to remote server via RPC.
 func (rc remoteAdminClient) Restart() error {
 	args := AuthRPCArgs{}
@@ -73,6 +133,107 @@ func (rc remoteAdminClient) ListLocks(bucket, prefix string, relTime time.Durati
 	return reply.volLocks, nil
 }
 
+// TopLocks - Sends top locks command to remote server via RPC.
+func (rc remoteAdminClient) TopLocks(count int) ([]TopLockInfo, error) {
+	args := TopLocksArgs{Count: count}
+	var reply TopLocksReply
+	if err := rc.Call("Admin.TopLocks", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Locks, nil
+}
+
+// SetServerMode - Sends set server mode command to remote server via RPC.
+func (rc remoteAdminClient) SetServerMode(bucket string, mode serverMode) error {
+	args := SetServerModeArgs{
+		Bucket: bucket,
+		Mode:   string(mode),
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.SetServerMode", &args, &reply)
+}
+
+// ReloadConfig - Sends reload config command to remote server via RPC.
+func (rc remoteAdminClient) ReloadConfig(reloadCredentials bool) error {
+	args := ReloadConfigArgs{
+		ReloadCredentials: reloadCredentials,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.ReloadConfig", &args, &reply)
+}
+
+// ReloadIAM - Sends reload IAM command to remote server via RPC.
+func (rc remoteAdminClient) ReloadIAM() error {
+	args := AuthRPCArgs{}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.ReloadIAM", &args, &reply)
+}
+
+// AccessKeysLastUsed - Sends access keys last used command to remote server via RPC.
+func (rc remoteAdminClient) AccessKeysLastUsed() (map[string]accessKeyUsage, error) {
+	if err := rc.checkCapability(rpcCapAccessKeysLastUsed); err != nil {
+		return nil, err
+	}
+	args := AuthRPCArgs{}
+	var reply AccessKeysLastUsedReply
+	if err := rc.Call("Admin.AccessKeysLastUsed", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Keys, nil
+}
+
+// ListRequests - Sends list requests command to remote server via RPC.
+func (rc remoteAdminClient) ListRequests() ([]InflightRequestInfo, error) {
+	if err := rc.checkCapability(rpcCapInflightRequests); err != nil {
+		return nil, err
+	}
+	args := AuthRPCArgs{}
+	var reply ListRequestsReply
+	if err := rc.Call("Admin.ListRequests", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Requests, nil
+}
+
+// KillRequest - Sends kill request command to remote server via RPC.
+func (rc remoteAdminClient) KillRequest(requestID string) (bool, error) {
+	if err := rc.checkCapability(rpcCapInflightRequests); err != nil {
+		return false, err
+	}
+	args := KillRequestArgs{RequestID: requestID}
+	var reply KillRequestReply
+	if err := rc.Call("Admin.KillRequest", &args, &reply); err != nil {
+		return false, err
+	}
+	return reply.Killed, nil
+}
+
+// ServerHealth - Sends server health command to remote server via RPC.
+func (rc remoteAdminClient) ServerHealth() (serverHealth, error) {
+	if err := rc.checkCapability(rpcCapServerHealth); err != nil {
+		return serverHealth{}, err
+	}
+	args := AuthRPCArgs{}
+	var reply ServerHealthReply
+	if err := rc.Call("Admin.ServerHealth", &args, &reply); err != nil {
+		return serverHealth{}, err
+	}
+	return reply.Health, nil
+}
+
+// PromoteStandby - Sends promote standby command to remote server via RPC.
+func (rc remoteAdminClient) PromoteStandby() (bool, error) {
+	if err := rc.checkCapability(rpcCapStandby); err != nil {
+		return false, err
+	}
+	args := AuthRPCArgs{}
+	var reply PromoteStandbyReply
+	if err := rc.Call("Admin.PromoteStandby", &args, &reply); err != nil {
+		return false, err
+	}
+	return reply.Promoted, nil
+}
+
 // adminPeer - represents an entity that implements Restart methods.
 type adminPeer struct {
 	addr      string
@@ -159,6 +320,87 @@ func sendServiceCmd(cps adminPeers, cmd serviceSignal) {
 	errs[0] = invokeServiceCmd(cps[0], cmd)
 }
 
+// setServerModeOnPeers - Sets the operating mode on all the remote
+// peers followed by the local peer, returns the errors keyed by the
+// address of the peers which failed.
+func setServerModeOnPeers(peers adminPeers, bucket string, mode serverMode) map[string]error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	remotePeers := peers[1:]
+	for i := range remotePeers {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			// we use idx+1 because remotePeers slice is 1 position shifted w.r.t peers
+			errs[idx+1] = remotePeers[idx].cmdRunner.SetServerMode(bucket, mode)
+		}(i)
+	}
+	wg.Wait()
+	errs[0] = peers[0].cmdRunner.SetServerMode(bucket, mode)
+
+	errMap := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			errMap[peers[i].addr] = err
+		}
+	}
+	return errMap
+}
+
+// reloadConfigOnPeers - Reloads the config on all the remote peers
+// followed by the local peer, returns the errors keyed by the address
+// of the peers which failed.
+func reloadConfigOnPeers(peers adminPeers, reloadCredentials bool) map[string]error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	remotePeers := peers[1:]
+	for i := range remotePeers {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			// we use idx+1 because remotePeers slice is 1 position shifted w.r.t peers
+			errs[idx+1] = remotePeers[idx].cmdRunner.ReloadConfig(reloadCredentials)
+		}(i)
+	}
+	wg.Wait()
+	errs[0] = peers[0].cmdRunner.ReloadConfig(reloadCredentials)
+
+	errMap := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			errMap[peers[i].addr] = err
+		}
+	}
+	return errMap
+}
+
+// reloadIAMOnPeers - Reloads the IAM users and policies on all the
+// remote peers followed by the local peer, returns the errors keyed by
+// the address of the peers which failed.
+func reloadIAMOnPeers(peers adminPeers) map[string]error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	remotePeers := peers[1:]
+	for i := range remotePeers {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			// we use idx+1 because remotePeers slice is 1 position shifted w.r.t peers
+			errs[idx+1] = remotePeers[idx].cmdRunner.ReloadIAM()
+		}(i)
+	}
+	wg.Wait()
+	errs[0] = peers[0].cmdRunner.ReloadIAM()
+
+	errMap := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			errMap[peers[i].addr] = err
+		}
+	}
+	return errMap
+}
+
 func listPeerLocksInfo(peers adminPeers, bucket, prefix string, relTime time.Duration) ([]VolumeLockInfo, error) {
 	// Used to aggregate volume lock information from all nodes.
 	allLocks := make([][]VolumeLockInfo, len(peers))
@@ -206,3 +448,154 @@ func listPeerLocksInfo(peers adminPeers, bucket, prefix string, relTime time.Dur
 	}
 	return groupedLockInfos, nil
 }
+
+// listPeerTopLocksInfo - Fetches the count oldest locks held across
+// all nodes, along with the node holding each of them.
+func listPeerTopLocksInfo(peers adminPeers, count int) ([]TopLockInfo, error) {
+	allLocks := make([][]TopLockInfo, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	localPeer := peers[0]
+	remotePeers := peers[1:]
+	for i, remotePeer := range remotePeers {
+		wg.Add(1)
+		go func(idx int, remotePeer adminPeer) {
+			defer wg.Done()
+			// `remotePeers` is right-shifted by one position relative to `peers`
+			allLocks[idx], errs[idx] = remotePeer.cmdRunner.TopLocks(count)
+		}(i+1, remotePeer)
+	}
+	wg.Wait()
+	allLocks[0], errs[0] = localPeer.cmdRunner.TopLocks(count)
+
+	// Same quorum requirements as ListLocks.
+	errCount, err := reduceErrs(errs, []error{})
+	if err != nil {
+		if errCount >= (len(peers)/2 + 1) {
+			return nil, err
+		}
+		return nil, InsufficientReadQuorum{}
+	}
+
+	topLocks := []TopLockInfo{}
+	for i, nodeLocks := range allLocks {
+		for _, lockInfo := range nodeLocks {
+			lockInfo.Node = peers[i].addr
+			topLocks = append(topLocks, lockInfo)
+		}
+	}
+	return oldestLocks(topLocks, count), nil
+}
+
+// listPeerAccessKeysLastUsed - Fetches the last use of the access keys
+// across all nodes, keeping the latest one of each access key along with
+// the node it was used on.
+func listPeerAccessKeysLastUsed(peers adminPeers) (map[string]accessKeyUsage, error) {
+	allKeys := make([]map[string]accessKeyUsage, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	localPeer := peers[0]
+	remotePeers := peers[1:]
+	for i, remotePeer := range remotePeers {
+		wg.Add(1)
+		go func(idx int, remotePeer adminPeer) {
+			defer wg.Done()
+			// `remotePeers` is right-shifted by one position relative to `peers`
+			allKeys[idx], errs[idx] = remotePeer.cmdRunner.AccessKeysLastUsed()
+		}(i+1, remotePeer)
+	}
+	wg.Wait()
+	allKeys[0], errs[0] = localPeer.cmdRunner.AccessKeysLastUsed()
+
+	// Same quorum requirements as ListLocks, peers of older releases
+	// not serving the RPC are skipped.
+	errCount, err := reduceErrs(errs, []error{errRPCUnsupported})
+	if err != nil {
+		if errCount >= (len(peers)/2 + 1) {
+			return nil, err
+		}
+		return nil, InsufficientReadQuorum{}
+	}
+
+	lastUsed := make(map[string]accessKeyUsage)
+	for i, nodeKeys := range allKeys {
+		for accessKey, usage := range nodeKeys {
+			if usage.LastUsed.Before(lastUsed[accessKey].LastUsed) {
+				continue
+			}
+			usage.Node = peers[i].addr
+			lastUsed[accessKey] = usage
+		}
+	}
+	return lastUsed, nil
+}
+
+// listPeerRequests - Fetches the requests being served by all nodes,
+// oldest first, along with the node serving each of them.
+func listPeerRequests(peers adminPeers) ([]InflightRequestInfo, error) {
+	allRequests := make([][]InflightRequestInfo, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	localPeer := peers[0]
+	remotePeers := peers[1:]
+	for i, remotePeer := range remotePeers {
+		wg.Add(1)
+		go func(idx int, remotePeer adminPeer) {
+			defer wg.Done()
+			// `remotePeers` is right-shifted by one position relative to `peers`
+			allRequests[idx], errs[idx] = remotePeer.cmdRunner.ListRequests()
+		}(i+1, remotePeer)
+	}
+	wg.Wait()
+	allRequests[0], errs[0] = localPeer.cmdRunner.ListRequests()
+
+	// Same quorum requirements as ListLocks, peers of older releases
+	// not serving the RPC are skipped.
+	errCount, err := reduceErrs(errs, []error{errRPCUnsupported})
+	if err != nil {
+		if errCount >= (len(peers)/2 + 1) {
+			return nil, err
+		}
+		return nil, InsufficientReadQuorum{}
+	}
+
+	requests := []InflightRequestInfo{}
+	for i, nodeRequests := range allRequests {
+		for _, info := range nodeRequests {
+			info.Node = peers[i].addr
+			requests = append(requests, info)
+		}
+	}
+	sort.Sort(byRequestAge(requests))
+	return requests, nil
+}
+
+// killPeerRequest - Kills the request with requestID on all the
+// peers, returns false if no peer is serving it along with the errors
+// keyed by the address of the peers which failed.
+func killPeerRequest(peers adminPeers, requestID string) (bool, map[string]error) {
+	killed := make([]bool, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	remotePeers := peers[1:]
+	for i := range remotePeers {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			// we use idx+1 because remotePeers slice is 1 position shifted w.r.t peers
+			killed[idx+1], errs[idx+1] = remotePeers[idx].cmdRunner.KillRequest(requestID)
+		}(i)
+	}
+	wg.Wait()
+	killed[0], errs[0] = peers[0].cmdRunner.KillRequest(requestID)
+
+	anyKilled := false
+	errMap := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			errMap[peers[i].addr] = err
+		}
+		anyKilled = anyKilled || killed[i]
+	}
+	return anyKilled, errMap
+}
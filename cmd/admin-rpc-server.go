@@ -45,6 +45,67 @@ type ListLocksReply struct {
 	volLocks []VolumeLockInfo
 }
 
+// TopLocksArgs - wraps TopLocks API's arguments to send over RPC.
+type TopLocksArgs struct {
+	AuthRPCArgs
+	Count int
+}
+
+// TopLocksReply - wraps TopLocks response over RPC.
+type TopLocksReply struct {
+	AuthRPCReply
+	Locks []TopLockInfo
+}
+
+// AccessKeysLastUsedReply - wraps AccessKeysLastUsed response over RPC.
+type AccessKeysLastUsedReply struct {
+	AuthRPCReply
+	Keys map[string]accessKeyUsage
+}
+
+// ListRequestsReply - wraps ListRequests response over RPC.
+type ListRequestsReply struct {
+	AuthRPCReply
+	Requests []InflightRequestInfo
+}
+
+// KillRequestArgs - wraps KillRequest API's arguments to send over RPC.
+type KillRequestArgs struct {
+	AuthRPCArgs
+	RequestID string
+}
+
+// KillRequestReply - wraps KillRequest response over RPC.
+type KillRequestReply struct {
+	AuthRPCReply
+	Killed bool
+}
+
+// ServerHealthReply - wraps ServerHealth response over RPC.
+type ServerHealthReply struct {
+	AuthRPCReply
+	Health serverHealth
+}
+
+// PromoteStandbyReply - wraps PromoteStandby response over RPC.
+type PromoteStandbyReply struct {
+	AuthRPCReply
+	Promoted bool
+}
+
+// SetServerModeArgs - wraps SetServerMode API's arguments to send over RPC.
+type SetServerModeArgs struct {
+	AuthRPCArgs
+	Bucket string
+	Mode   string
+}
+
+// ReloadConfigArgs - wraps ReloadConfig API's arguments to send over RPC.
+type ReloadConfigArgs struct {
+	AuthRPCArgs
+	ReloadCredentials bool
+}
+
 // Restart - Restart this instance of minio server.
 func (s *adminCmd) Restart(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -62,6 +123,109 @@ func (s *adminCmd) ListLocks(query *ListLocksQuery, reply *ListLocksReply) error
 	return nil
 }
 
+// TopLocks - lists the oldest locks held by requests handled by this
+// server instance.
+func (s *adminCmd) TopLocks(args *TopLocksArgs, reply *TopLocksReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Locks = listTopLocksInfo(args.Count)
+	return nil
+}
+
+// SetServerMode - sets the operating mode of this server instance, or
+// of a bucket.
+func (s *adminCmd) SetServerMode(args *SetServerModeArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	mode := serverMode(args.Mode)
+	if !mode.isValid() {
+		return errInvalidArgument
+	}
+	globalServerModes.Set(args.Bucket, mode)
+	return nil
+}
+
+// ReloadConfig - reloads the config of this server instance.
+func (s *adminCmd) ReloadConfig(args *ReloadConfigArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return reloadConfig(args.ReloadCredentials)
+}
+
+// ReloadIAM - reloads the IAM users and policies of this server
+// instance.
+func (s *adminCmd) ReloadIAM(args *AuthRPCArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	return globalIAMSys.Load(objAPI)
+}
+
+// AccessKeysLastUsed - lists the last use of the access keys
+// authenticating the requests handled by this server instance.
+func (s *adminCmd) AccessKeysLastUsed(args *AuthRPCArgs, reply *AccessKeysLastUsedReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Keys = globalAccessKeyUsage.List()
+	return nil
+}
+
+// ListRequests - lists the requests being served by this server
+// instance.
+func (s *adminCmd) ListRequests(args *AuthRPCArgs, reply *ListRequestsReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Requests = globalInflightRequests.List()
+	return nil
+}
+
+// KillRequest - kills the request served by this server instance
+// with the requested ID, if any.
+func (s *adminCmd) KillRequest(args *KillRequestArgs, reply *KillRequestReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Killed = globalInflightRequests.Kill(args.RequestID)
+	return nil
+}
+
+// ServerHealth - returns the health of this server instance.
+func (s *adminCmd) ServerHealth(args *AuthRPCArgs, reply *ServerHealthReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Health = getLocalServerHealth()
+	return nil
+}
+
+// PromoteStandby - promotes this server instance if it is a standby.
+func (s *adminCmd) PromoteStandby(args *AuthRPCArgs, reply *PromoteStandbyReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	var err error
+	reply.Promoted, err = promoteStandby()
+	return err
+}
+
 // registerAdminRPCRouter - registers RPC methods for service status,
 // stop and restart commands.
 func registerAdminRPCRouter(mux *router.Router) error {
@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Environment variable setting the anonymous access mode, one of "on",
+// "audit" and "off".
+const anonymousAccessEnv = "MINIO_ANONYMOUS"
+
+// anonymousAccessMode - whether the bucket policies grant access to
+// anonymous requests, and whether these are audited.
+type anonymousAccessMode string
+
+const (
+	// Anonymous requests are granted access by the bucket policies.
+	anonymousAccessOn anonymousAccessMode = "on"
+	// Anonymous requests are granted access by the bucket policies,
+	// every one of them being audited.
+	anonymousAccessAudit anonymousAccessMode = "audit"
+	// Anonymous requests are denied regardless of the bucket policies,
+	// every one of them being audited.
+	anonymousAccessOff anonymousAccessMode = "off"
+)
+
+// Anonymous access mode of this server.
+var globalAnonymousAccess = anonymousAccessOn
+
+// getAnonymousAccess - returns the anonymous access mode configured
+// in the environment.
+func getAnonymousAccess() (anonymousAccessMode, error) {
+	value := os.Getenv(anonymousAccessEnv)
+	switch mode := anonymousAccessMode(strings.ToLower(value)); mode {
+	case "":
+		return anonymousAccessOn, nil
+	case anonymousAccessOn, anonymousAccessAudit, anonymousAccessOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("Invalid %s ‘%s’, should be one of on, audit and off", anonymousAccessEnv, value)
+}
+
+// auditAnonymousAccess - logs the anonymous request r for action on
+// resource as a warning, unless anonymous requests are not audited.
+func auditAnonymousAccess(r *http.Request, action, resource string, allowed bool) {
+	if globalAnonymousAccess == anonymousAccessOn {
+		return
+	}
+	fields := logrus.Fields{
+		"action":     action,
+		"resource":   resource,
+		"allowed":    allowed,
+		"method":     r.Method,
+		"sourceIP":   r.RemoteAddr,
+		"userAgent":  r.UserAgent(),
+		"requestID":  getRequestInfo(r.Context()).RequestID,
+		"accessMode": globalAnonymousAccess,
+	}
+	for _, log := range log.loggers {
+		log.WithFields(fields).Warn("Anonymous access")
+	}
+}
+
+// checkAnonymousAccess - validates the anonymous request r for action
+// on bucket against the bucket policy, reqURL being the resource of
+// the action.
+func checkAnonymousAccess(r *http.Request, bucket, action string, reqURL *url.URL) (s3Error APIErrorCode) {
+	s3Error = ErrAccessDenied
+	if globalAnonymousAccess != anonymousAccessOff {
+		s3Error = enforceBucketPolicy(bucket, action, reqURL)
+	}
+	auditAnonymousAccess(r, action, getRequestResource(reqURL), s3Error == ErrNone)
+	return s3Error
+}
+
+// isAnonymousActionAllowed - returns true if the bucket policy of
+// bucket allows the anonymous request r for action on prefix.
+func isAnonymousActionAllowed(r *http.Request, action, bucket, prefix string) bool {
+	allowed := isBucketActionAllowed(action, bucket, prefix)
+	auditAnonymousAccess(r, action, bucketARNPrefix+path.Join(bucket, prefix), allowed)
+	return allowed
+}
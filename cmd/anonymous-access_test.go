@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// Tests parsing the anonymous access mode.
+func TestGetAnonymousAccess(t *testing.T) {
+	defer os.Unsetenv(anonymousAccessEnv)
+
+	testCases := []struct {
+		value      string
+		mode       anonymousAccessMode
+		shouldPass bool
+	}{
+		{"", anonymousAccessOn, true},
+		{"on", anonymousAccessOn, true},
+		{"audit", anonymousAccessAudit, true},
+		{"OFF", anonymousAccessOff, true},
+		{"deny", "", false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(anonymousAccessEnv, testCase.value)
+		mode, err := getAnonymousAccess()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if mode != testCase.mode {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.mode, mode)
+		}
+	}
+}
+
+// Wrapper for calling the anonymous access tests for both XL multiple disks and single node setup.
+func TestAnonymousAccess(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func() { globalAnonymousAccess = anonymousAccessOn }()
+	ExecObjectLayerAPITest(t, testAnonymousAccess, []string{"GetObject", "PutObject"})
+}
+
+func testAnonymousAccess(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	policyJSON, err := json.Marshal(policy.BucketAccessPolicy{
+		Version:    "2012-10-17",
+		Statements: policy.SetPolicy(nil, policy.BucketPolicyReadOnly, bucketName, ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnlyPolicy := &bucketPolicy{}
+	if err = parseBucketPolicy(bytes.NewReader(policyJSON), readOnlyPolicy); err != nil {
+		t.Fatalf("%s: Unable to parse the bucket policy: %v", instanceType, err)
+	}
+	if err = globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, readOnlyPolicy}); err != nil {
+		t.Fatal(err)
+	}
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	// Capture the audited requests.
+	var buffer bytes.Buffer
+	auditLog := logrus.New()
+	auditLog.Out = &buffer
+	auditLog.Formatter = new(logrus.JSONFormatter)
+	loggers := log.loggers
+	log.loggers = []*logrus.Logger{auditLog}
+	defer func() { log.loggers = loggers }()
+
+	testCases := []struct {
+		mode       anonymousAccessMode
+		method     string
+		urlStr     string
+		statusCode int
+		audited    bool
+	}{
+		{anonymousAccessOn, "GET", getGetObjectURL("", bucketName, "object"), http.StatusOK, false},
+		{anonymousAccessAudit, "GET", getGetObjectURL("", bucketName, "object"), http.StatusOK, true},
+		{anonymousAccessAudit, "PUT", getPutObjectURL("", bucketName, "new-object"), http.StatusForbidden, true},
+		{anonymousAccessOff, "GET", getGetObjectURL("", bucketName, "object"), http.StatusForbidden, true},
+	}
+	for i, testCase := range testCases {
+		globalAnonymousAccess = testCase.mode
+		buffer.Reset()
+
+		req, err := newTestRequest(testCase.method, testCase.urlStr, int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+
+		if !testCase.audited {
+			if buffer.Len() != 0 {
+				t.Errorf("%s: Test %d: Expected no audit, got %s", instanceType, i+1, buffer.String())
+			}
+			continue
+		}
+		var fields logrus.Fields
+		if err = json.Unmarshal(buffer.Bytes(), &fields); err != nil {
+			t.Fatalf("%s: Test %d: Unable to parse the audit: %v", instanceType, i+1, err)
+		}
+		if fields["level"] != "warning" || fields["msg"] != "Anonymous access" {
+			t.Errorf("%s: Test %d: Unexpected audit %s", instanceType, i+1, buffer.String())
+		}
+		if fields["allowed"] != (testCase.statusCode == http.StatusOK) || fields["accessMode"] != string(testCase.mode) {
+			t.Errorf("%s: Test %d: Unexpected audit %s", instanceType, i+1, buffer.String())
+		}
+	}
+}
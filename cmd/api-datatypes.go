@@ -44,3 +44,41 @@ type DeleteObjectsRequest struct {
 	// List of objects to be deleted
 	Objects []ObjectIdentifier `xml:"Object"`
 }
+
+// CopyObjectIdentifier carries the source of an object to copy and the
+// key name of its copy.
+type CopyObjectIdentifier struct {
+	// Source object, as in X-Amz-Copy-Source: sourcebucket/sourcekey.
+	Source     string
+	ObjectName string `xml:"Key"`
+}
+
+// CopyObjectsRequest - xml carrying the objects which need to be copied.
+type CopyObjectsRequest struct {
+	// Element to enable quiet mode for the request
+	Quiet bool
+	// List of objects to be copied
+	Objects []CopyObjectIdentifier `xml:"Object"`
+}
+
+// ComposeSource - a source object, or a byte range of it, to compose.
+type ComposeSource struct {
+	Bucket string
+	Key    string
+	Range  string // Optional byte range such as "bytes=0-1023".
+}
+
+// ComposeObjectRequest - xml carrying the sources of the object to compose,
+// in the order they are concatenated.
+type ComposeObjectRequest struct {
+	XMLName xml.Name        `xml:"ComposeObject" json:"-"`
+	Sources []ComposeSource `xml:"Source"`
+}
+
+// RenamePrefixRequest - xml carrying the prefix of the objects to rename
+// and their new prefix.
+type RenamePrefixRequest struct {
+	XMLName   xml.Name `xml:"RenamePrefix" json:"-"`
+	Prefix    string
+	NewPrefix string
+}
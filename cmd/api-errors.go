@@ -60,10 +60,17 @@ const (
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
 	ErrInvalidPartNumberMarker
+	ErrInvalidPartNumber
 	ErrInvalidRequestBody
 	ErrInvalidCopySource
 	ErrInvalidMetadataDirective
 	ErrInvalidCopyDest
+	ErrInvalidComposeSources
+	ErrInvalidComposeDest
+	ErrInvalidRenamePrefix
+	ErrContentMD5Required
+	ErrInvalidBandwidthLimit
+	ErrInvalidTargetBucketForLogging
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
 	ErrMalformedXML
@@ -102,6 +109,7 @@ const (
 	ErrMalformedCredentialRegion
 	ErrMalformedExpires
 	ErrNegativeExpires
+	ErrMaximumExpires
 	ErrAuthHeaderEmpty
 	ErrExpiredPresignRequest
 	ErrRequestNotReadyYet
@@ -111,6 +119,8 @@ const (
 	ErrInvalidQueryParams
 	ErrBucketAlreadyOwnedByYou
 	ErrInvalidDuration
+	ErrBucketAlreadyExists
+	ErrInvalidLockCount
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -134,15 +144,46 @@ const (
 	ErrWriteQuorum
 	ErrStorageFull
 	ErrObjectExistsAsDirectory
+	ErrPrefixExists
 	ErrPolicyNesting
 	ErrInvalidObjectName
+	ErrObjectNameUnsupported
+	ErrShareLinkRevoked
 	ErrServerNotInitialized
 	// Add new extended error codes here.
 	// Please open a https://github.com/minio/minio/issues before adding
 	// new error codes here.
 
+	ErrServerReadOnlyMode
+	ErrServerMaintenanceMode
+	ErrBucketReadOnlyMode
+	ErrBucketMaintenanceMode
+	ErrStandbyReadOnly
+
 	ErrAdminInvalidAccessKey
 	ErrAdminInvalidSecretKey
+	ErrAdminInvalidServerMode
+	ErrAdminConfigReloadFailed
+	ErrAdminInvalidExpiryDays
+	ErrMetadataIndexUnavailable
+	ErrInvalidMetadataPredicate
+	ErrInvalidTransformEndpoint
+	ErrTransformFailed
+	ErrAdminCacheDisabled
+	ErrAdminCachePrewarmInProgress
+	ErrAdminNoSuchUser
+	ErrAdminNoSuchPolicy
+	ErrAdminInvalidUserStatus
+	ErrAdminCannedPolicy
+	ErrAdminInvalidUserQuota
+	ErrAdminInvalidUserBandwidth
+	ErrUserQuotaExceeded
+	ErrRequestKilled
+	ErrAdminNoSuchRequest
+	ErrInvalidMaxBuckets
+	ErrTooManyBuckets
+	ErrBucketNameNotDNSCompatible
+	ErrAnonymousResponseHeaders
 )
 
 // error code to APIError structure, these fields carry respective
@@ -153,6 +194,36 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata, storage class, website redirect location or encryption attributes.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidComposeSources: {
+		Code:           "InvalidRequest",
+		Description:    "You must specify between 1 and 10000 compose sources.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidComposeDest: {
+		Code:           "InvalidRequest",
+		Description:    "This compose request is illegal because the destination object is also one of its sources.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidRenamePrefix: {
+		Code:           "InvalidRequest",
+		Description:    "This rename request is illegal because the prefixes are empty, do not end with a slash or are nested one in the other.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrContentMD5Required: {
+		Code:           "InvalidRequest",
+		Description:    "This bucket requires a Content-MD5 on all uploads.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidBandwidthLimit: {
+		Code:           "InvalidArgument",
+		Description:    "Bandwidth limits cannot be negative.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidCopySource: {
 		Code:           "InvalidArgument",
 		Description:    "Copy Source must mention the source bucket and key: sourcebucket/sourcekey.",
@@ -188,6 +259,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Argument partNumberMarker must be an integer.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidPartNumber: {
+		Code:           "InvalidArgument",
+		Description:    "Part number must be an integer between 1 and 10000, inclusive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidPolicyDocument: {
 		Code:           "InvalidPolicyDocument",
 		Description:    "The content of the form does not meet the conditions specified in the policy document.",
@@ -440,6 +516,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "X-Amz-Expires must be non-negative",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMaximumExpires: {
+		Code:           "AuthorizationQueryParametersError",
+		Description:    "X-Amz-Expires must be less than the maximum expiry of presigned URLs allowed by the server",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrAuthHeaderEmpty: {
 		Code:           "InvalidArgument",
 		Description:    "Authorization header is invalid -- one and only one ' ' (space) required.",
@@ -481,11 +562,21 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Your previous request to create the named bucket succeeded and you already own it.",
 		HTTPStatusCode: http.StatusConflict,
 	},
+	ErrBucketAlreadyExists: {
+		Code:           "BucketAlreadyExists",
+		Description:    "The requested bucket name is not available. The bucket namespace is shared by all users of the system. Please select a different name and try again.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrInvalidDuration: {
 		Code:           "InvalidDuration",
 		Description:    "Relative duration provided in the request is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidLockCount: {
+		Code:           "InvalidArgument",
+		Description:    "Count of locks provided in the request is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Bucket notification related errors.
 	ErrEventNotification: {
@@ -552,6 +643,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Object name already exists as a directory.",
 		HTTPStatusCode: http.StatusConflict,
 	},
+	ErrPrefixExists: {
+		Code:           "XMinioPrefixExists",
+		Description:    "Objects already exist under the new prefix.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrReadQuorum: {
 		Code:           "XMinioReadQuorum",
 		Description:    "Multiple disk failures, unable to reconstruct data.",
@@ -572,11 +668,46 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Object name contains unsupported characters. Unsupported characters are `^*|\\\"",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrObjectNameUnsupported: {
+		Code:           "XMinioInvalidObjectName",
+		Description:    "Object name cannot be stored on the server filesystem. Path segments cannot end with a dot or a space, be a reserved name like CON or NUL, or contain control characters or any of `<>:\"|?*`",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrShareLinkRevoked: {
+		Code:           "AccessDenied",
+		Description:    "The shared link has been revoked",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrServerNotInitialized: {
 		Code:           "XMinioServerNotInitialized",
 		Description:    "Server not initialized, please try again.",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrServerReadOnlyMode: {
+		Code:           "XMinioServerReadOnlyMode",
+		Description:    "Server is in read-only mode, requests modifying data are not allowed.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrServerMaintenanceMode: {
+		Code:           "XMinioServerMaintenanceMode",
+		Description:    "Server is under maintenance, please try again later.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrBucketReadOnlyMode: {
+		Code:           "XMinioBucketReadOnlyMode",
+		Description:    "Bucket is in read-only mode, requests modifying data are not allowed.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrBucketMaintenanceMode: {
+		Code:           "XMinioBucketMaintenanceMode",
+		Description:    "Bucket is under maintenance, please try again later.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrStandbyReadOnly: {
+		Code:           "XMinioStandbyReadOnly",
+		Description:    "Server is a standby, requests modifying data should be sent to the primary servers.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
 	ErrAdminInvalidAccessKey: {
 		Code:           "XMinioAdminInvalidAccessKey",
 		Description:    "The access key is invalid.",
@@ -587,6 +718,116 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The secret key is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrAdminInvalidServerMode: {
+		Code:           "XMinioAdminInvalidServerMode",
+		Description:    "The server mode is invalid, supported modes are normal, read-only and maintenance.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminConfigReloadFailed: {
+		Code:           "XMinioAdminConfigReloadFailed",
+		Description:    "Unable to reload the server config, the previous config is still in effect.",
+		HTTPStatusCode: http.StatusInternalServerError,
+	},
+	ErrAdminInvalidExpiryDays: {
+		Code:           "XMinioAdminInvalidExpiryDays",
+		Description:    "The expiry days are invalid, should be a non-negative number of days.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMetadataIndexUnavailable: {
+		Code:           "XMinioMetadataIndexUnavailable",
+		Description:    "The metadata index is not available, it is disabled or still being built.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrInvalidMetadataPredicate: {
+		Code:           "InvalidArgument",
+		Description:    "The metadata predicate is invalid, should be key=value, key!=value or key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTransformEndpoint: {
+		Code:           "InvalidArgument",
+		Description:    "The transform endpoint should be an http or https URL.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrTransformFailed: {
+		Code:           "XMinioTransformFailed",
+		Description:    "The transformer of the bucket failed to transform the object.",
+		HTTPStatusCode: http.StatusBadGateway,
+	},
+	ErrAdminCacheDisabled: {
+		Code:           "XMinioAdminCacheDisabled",
+		Description:    "The object cache is disabled on this server.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminCachePrewarmInProgress: {
+		Code:           "XMinioAdminCachePrewarmInProgress",
+		Description:    "A pre-warm of the object cache is already in progress on this server.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrAdminNoSuchUser: {
+		Code:           "XMinioAdminNoSuchUser",
+		Description:    "The specified user does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchPolicy: {
+		Code:           "XMinioAdminNoSuchPolicy",
+		Description:    "The specified policy does not exist or is not attached to the user.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidUserStatus: {
+		Code:           "XMinioAdminInvalidUserStatus",
+		Description:    "The user status should be one of enabled or disabled.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminCannedPolicy: {
+		Code:           "XMinioAdminCannedPolicy",
+		Description:    "Canned policies can not be replaced or removed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidUserQuota: {
+		Code:           "XMinioAdminInvalidUserQuota",
+		Description:    "The user quota should be a number of bytes, 0 for unlimited.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidUserBandwidth: {
+		Code:           "XMinioAdminInvalidUserBandwidth",
+		Description:    "The user bandwidth limits should be numbers of bytes per second, 0 for unlimited.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUserQuotaExceeded: {
+		Code:           "XMinioUserQuotaExceeded",
+		Description:    "The upload exceeds the storage quota of the user.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrRequestKilled: {
+		Code:           "XMinioRequestKilled",
+		Description:    "The request was killed by the administrator.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrAdminNoSuchRequest: {
+		Code:           "XMinioAdminNoSuchRequest",
+		Description:    "The specified request is not in progress.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidMaxBuckets: {
+		Code:           "InvalidArgument",
+		Description:    "Argument max-buckets must be an integer between 0 and 10000",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrTooManyBuckets: {
+		Code:           "TooManyBuckets",
+		Description:    "You have attempted to create more buckets than allowed",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketNameNotDNSCompatible: {
+		Code:           "InvalidBucketName",
+		Description:    "The specified bucket is not valid, bucket names must be DNS compatible, without periods.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAnonymousResponseHeaders: {
+		Code:           "InvalidRequest",
+		Description:    "Request specific response headers cannot be used for anonymous GET requests.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	// Add your error structure here.
 }
@@ -606,6 +847,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrSignatureDoesNotMatch
 	case errContentSHA256Mismatch:
 		apiErr = ErrContentSHA256Mismatch
+	case errRequestKilled:
+		apiErr = ErrRequestKilled
 	}
 
 	if apiErr != ErrNone {
@@ -622,6 +865,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrIncompleteBody
 	case ObjectExistsAsDirectory:
 		apiErr = ErrObjectExistsAsDirectory
+	case PrefixExists:
+		apiErr = ErrPrefixExists
 	case PrefixAccessDenied:
 		apiErr = ErrAccessDenied
 	case BucketNameInvalid:
@@ -636,6 +881,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchKey
 	case ObjectNameInvalid:
 		apiErr = ErrInvalidObjectName
+	case ObjectNameUnsupported:
+		apiErr = ErrObjectNameUnsupported
 	case InvalidUploadID:
 		apiErr = ErrNoSuchUpload
 	case InvalidPart:
@@ -33,8 +33,11 @@ func mustGetRequestID(t time.Time) string {
 
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
-	// Set unique request ID for each reply.
-	w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	// Set unique request ID for each reply, unless assigned when
+	// the request was routed.
+	if w.Header().Get(responseRequestIDKey) == "" {
+		w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	}
 	w.Header().Set("Server", globalServerUserAgent)
 	w.Header().Set("Accept-Ranges", "bytes")
 }
@@ -78,3 +81,17 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 		w.WriteHeader(http.StatusPartialContent)
 	}
 }
+
+// setHeadBucketHeaders - sets the region of a bucket, and its data
+// usage as of the last data usage crawl if any accounted it, in the
+// headers of a HEAD bucket response.
+func setHeadBucketHeaders(w http.ResponseWriter, bucket, region string) {
+	w.Header().Set("X-Amz-Bucket-Region", region)
+	usage, lastUpdate, ok := globalBucketUsage.Get(bucket)
+	if !ok {
+		return
+	}
+	w.Header().Set("X-Minio-Bucket-Objects", strconv.FormatUint(usage.Objects, 10))
+	w.Header().Set("X-Minio-Bucket-Size", strconv.FormatUint(usage.Size, 10))
+	w.Header().Set("X-Minio-Bucket-Usage-Updated", lastUpdate.UTC().Format(http.TimeFormat))
+}
@@ -51,6 +51,20 @@ func getListObjectsV2Args(values url.Values) (prefix, token, startAfter, delimit
 	return
 }
 
+// Parse url queries for ListBuckets, a Minio extension. maxBuckets is
+// 0 when the buckets are not paginated, -1 when invalid.
+func getListBucketsArgs(values url.Values) (prefix, token string, maxBuckets int) {
+	prefix = values.Get("prefix")
+	token = values.Get("continuation-token")
+	if values.Get("max-buckets") != "" {
+		var err error
+		if maxBuckets, err = strconv.Atoi(values.Get("max-buckets")); err != nil {
+			maxBuckets = -1
+		}
+	}
+	return
+}
+
 // Parse bucket url queries for ?uploads
 func getBucketMultipartResources(values url.Values) (prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int, encodingType string) {
 	prefix = values.Get("prefix")
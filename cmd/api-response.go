@@ -17,10 +17,12 @@
 package cmd
 
 import (
+	"compress/gzip"
 	"encoding/xml"
 	"net/http"
 	"net/url"
 	"path"
+	"sync"
 	"time"
 )
 
@@ -30,6 +32,7 @@ const (
 	maxObjectList     = 1000                       // Limit number of objects in a listObjectsResponse.
 	maxUploadsList    = 1000                       // Limit number of uploads in a listUploadsResponse.
 	maxPartsList      = 1000                       // Limit number of parts in a listPartsResponse.
+	maxBucketsList    = 10000                      // Limit number of buckets in a paginated listBucketsResponse.
 )
 
 // LocationResponse - format for location response.
@@ -162,6 +165,13 @@ type ListBucketsResponse struct {
 	Buckets struct {
 		Buckets []Bucket `xml:"Bucket"`
 	} // Buckets are nested
+
+	// Minio extension, set when the buckets are paginated with
+	// max-buckets and more buckets follow, to be passed back as
+	// continuation-token to list them.
+	ContinuationToken string `xml:",omitempty"`
+	// Minio extension, the prefix of the listed buckets if any.
+	Prefix string `xml:",omitempty"`
 }
 
 // Upload container for in progress multipart upload
@@ -207,6 +217,13 @@ type CopyObjectResponse struct {
 	ETag         string   // md5sum of the copied object.
 }
 
+// ComposeObjectResponse container returns ETag and LastModified of the composed object.
+type ComposeObjectResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ComposeObjectResult" json:"-"`
+	LastModified string   // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string   // md5sum of the composed object.
+}
+
 // Initiator inherit from Owner struct, fields are same
 type Initiator Owner
 
@@ -253,6 +270,31 @@ type DeleteObjectsResponse struct {
 	Errors []DeleteError `xml:"Error,omitempty"`
 }
 
+// CopiedObject - object copied by a multiple objects copy.
+type CopiedObject struct {
+	Key          string
+	ETag         string
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+}
+
+// CopyError structure.
+type CopyError struct {
+	Code    string
+	Message string
+	Key     string
+}
+
+// CopyObjectsResponse container for multiple object copies.
+type CopyObjectsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyResult" json:"-"`
+
+	// Collection of all copied objects
+	CopiedObjects []CopiedObject `xml:"Copied,omitempty"`
+
+	// Collection of errors copying certain objects.
+	Errors []CopyError `xml:"Error,omitempty"`
+}
+
 // PostResponse container for POST object request when success_action_status is set to 201
 type PostResponse struct {
 	Bucket   string
@@ -397,6 +439,14 @@ func generateCopyObjectResponse(etag string, lastModified time.Time) CopyObjectR
 	}
 }
 
+// generates ComposeObjectResponse from etag and lastModified time.
+func generateComposeObjectResponse(etag string, lastModified time.Time) ComposeObjectResponse {
+	return ComposeObjectResponse{
+		ETag:         "\"" + etag + "\"",
+		LastModified: lastModified.UTC().Format(timeFormatAMZLong),
+	}
+}
+
 // generates InitiateMultipartUploadResponse for given bucket, key and uploadID.
 func generateInitiateMultipartUploadResponse(bucket, key, uploadID string) InitiateMultipartUploadResponse {
 	return InitiateMultipartUploadResponse{
@@ -486,18 +536,68 @@ func generateMultiDeleteResponse(quiet bool, deletedObjects []ObjectIdentifier,
 	return deleteResp
 }
 
+// generate multi objects copy response.
+func generateMultiCopyResponse(quiet bool, copiedObjects []CopiedObject, errs []CopyError) CopyObjectsResponse {
+	copyResp := CopyObjectsResponse{}
+	if !quiet {
+		copyResp.CopiedObjects = copiedObjects
+	}
+	copyResp.Errors = errs
+	return copyResp
+}
+
 func writeResponse(w http.ResponseWriter, statusCode int, response []byte, mType mimeType) {
 	setCommonHeaders(w)
 	if mType != mimeNone {
 		w.Header().Set("Content-Type", string(mType))
 	}
+	// XML and JSON bodies are compressed for clients accepting it.
+	gw, compress := w.(gzipResponseWriter)
+	compress = compress && response != nil && mType != mimeNone
+	if compress {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
 	w.WriteHeader(statusCode)
 	if response != nil {
-		w.Write(response)
+		if compress {
+			gw.writeCompressed(response)
+		} else {
+			w.Write(response)
+		}
 		w.(http.Flusher).Flush()
 	}
 }
 
+// Pool of gzip writers reused across responses.
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// gzipResponseWriter - response writer of a request accepting gzip
+// content encoding, see setGzipHandler.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+}
+
+// writeCompressed - writes the response gzip compressed.
+func (w gzipResponseWriter) writeCompressed(response []byte) {
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(w.ResponseWriter)
+	gz.Write(response)
+	gz.Close()
+	gzipWriterPool.Put(gz)
+}
+
+// Flush - flushes the underlying response writer.
+func (w gzipResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // mimeType represents various MIME type used API responses.
 type mimeType string
 
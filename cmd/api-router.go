@@ -16,84 +16,252 @@
 
 package cmd
 
-import router "github.com/gorilla/mux"
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
 
 // objectAPIHandler implements and provides http handlers for S3 API.
 type objectAPIHandlers struct {
 	ObjectAPI func() ObjectLayer
 }
 
+// Levels of the S3 API requests.
+const (
+	rootLevel = iota
+	bucketLevel
+	objectLevel
+)
+
+// apiRoute - an S3 API, serving the requests having all its query
+// parameters and accepted by its match function if any.
+type apiRoute struct {
+	// Name of the API, as reported in the lock info.
+	name    string
+	queries []string
+	match   func(r *http.Request, query url.Values) bool
+	// Router extracting the path variables for the handler.
+	router *router.Router
+}
+
+// apiRequestClassifier - routes S3 API requests looking only at the
+// APIs of their level, root, bucket or object, and of their method, in
+// the order they are registered. The query of a request is parsed once
+// and only its keys are looked up, instead of matching all the
+// registered routes one after the other against the path, the query
+// and the headers of the request.
+type apiRequestClassifier struct {
+	routes [3]map[string][]*apiRoute
+}
+
+// newAPIRoute - returns an API served by handler for path, a path
+// prefix when prefix is set.
+func newAPIRoute(handler http.HandlerFunc, path string, prefix bool, queries ...string) *apiRoute {
+	// Paths are not normalized, as by the server router.
+	r := router.NewRouter().SkipClean(true)
+	if prefix {
+		r.PathPrefix(path).HandlerFunc(handler)
+	} else {
+		r.Path(path).HandlerFunc(handler)
+	}
+	return &apiRoute{name: handlerAPIName(handler), queries: queries, router: r}
+}
+
+// add - registers the route of an API of level for method.
+func (c apiRequestClassifier) add(level int, method string, route *apiRoute) *apiRoute {
+	c.routes[level][method] = append(c.routes[level][method], route)
+	return route
+}
+
+// route - returns the route of the API serving the request, nil if
+// there is none.
+func (c apiRequestClassifier) route(r *http.Request) *apiRoute {
+	level := bucketLevel
+	resource := strings.TrimPrefix(r.URL.Path, slashSeparator)
+	if resource == "" {
+		level = rootLevel
+	} else if i := strings.Index(resource, slashSeparator); i >= 0 && i+1 < len(resource) {
+		level = objectLevel
+	}
+
+	query := r.URL.Query()
+	for _, route := range c.routes[level][r.Method] {
+		matched := true
+		for _, key := range route.queries {
+			if _, ok := query[key]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched && (route.match == nil || route.match(r, query)) {
+			return route
+		}
+	}
+	return nil
+}
+
+// ServeHTTP - serves the request by the API it is routed to.
+func (c apiRequestClassifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := c.route(r)
+	if route == nil {
+		http.NotFound(w, r)
+		return
+	}
+	// The request ID is assigned before serving the request for the
+	// operations it makes, like taking locks, to be tagged with it.
+	info := requestInfo{API: route.name, RequestID: mustGetRequestID(time.Now().UTC())}
+	w.Header().Set(responseRequestIDKey, info.RequestID)
+	r, span := startRequestSpan(r.WithContext(contextWithRequestInfo(r.Context(), info)), route.name)
+	span.SetTag("request-id", info.RequestID)
+	w, r = globalBandwidthMonitor.monitor(w, r)
+	w, r, inflight := globalInflightRequests.start(w, r, info)
+	serveWithAccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveWithStatsd(route.router, w, r, route.name)
+	}), w, r, route.name)
+	globalInflightRequests.finish(inflight)
+	span.Finish(nil)
+}
+
+// matchHeader - returns a match function accepting requests with a
+// value of header matching the regular expression.
+func matchHeader(header, expr string) func(r *http.Request, query url.Values) bool {
+	re := regexp.MustCompile(expr)
+	return func(r *http.Request, query url.Values) bool {
+		for _, v := range r.Header[http.CanonicalHeaderKey(header)] {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // registerAPIRouter - registers S3 compatible APIs.
 func registerAPIRouter(mux *router.Router) {
+	// API Router
+	mux.NewRoute().PathPrefix("/").Handler(newAPIRequestClassifier())
+}
+
+// newAPIRequestClassifier - returns the classifier routing S3
+// compatible APIs.
+func newAPIRequestClassifier() apiRequestClassifier {
 	// Initialize API.
 	api := objectAPIHandlers{
 		ObjectAPI: newObjectLayerFn,
 	}
 
-	// API Router
-	apiRouter := mux.NewRoute().PathPrefix("/").Subrouter()
+	c := apiRequestClassifier{}
+	for level := range c.routes {
+		c.routes[level] = make(map[string][]*apiRoute)
+	}
 
-	// Bucket router
-	bucket := apiRouter.PathPrefix("/{bucket}").Subrouter()
+	// Object and bucket paths.
+	const object = "/{bucket}/{object:.+}"
+	const bucket = "/{bucket}"
 
 	/// Object operations
 
 	// HeadObject
-	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(api.HeadObjectHandler)
+	c.add(objectLevel, "HEAD", newAPIRoute(api.HeadObjectHandler, object, false))
 	// PutObjectPart
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
-	// ListObjectPxarts
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.ListObjectPartsHandler).Queries("uploadId", "{uploadId:.*}")
+	c.add(objectLevel, "PUT", newAPIRoute(api.PutObjectPartHandler, object, false, "partNumber", "uploadId")).match = func(r *http.Request, query url.Values) bool {
+		_, err := strconv.ParseUint(query.Get("partNumber"), 10, 64)
+		return err == nil
+	}
+	// ListObjectParts
+	c.add(objectLevel, "GET", newAPIRoute(api.ListObjectPartsHandler, object, false, "uploadId"))
 	// CompleteMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	c.add(objectLevel, "POST", newAPIRoute(api.CompleteMultipartUploadHandler, object, false, "uploadId"))
+	// ComposeObject (Minio extension)
+	c.add(objectLevel, "POST", newAPIRoute(api.ComposeObjectHandler, object, false, "compose"))
 	// NewMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
+	c.add(objectLevel, "POST", newAPIRoute(api.NewMultipartUploadHandler, object, false, "uploads"))
 	// AbortMultipartUpload
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.AbortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	c.add(objectLevel, "DELETE", newAPIRoute(api.AbortMultipartUploadHandler, object, false, "uploadId"))
 	// GetObject
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+	c.add(objectLevel, "GET", newAPIRoute(api.GetObjectHandler, object, false))
 	// CopyObject
-	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectHandler)
+	c.add(objectLevel, "PUT", newAPIRoute(api.CopyObjectHandler, object, false)).match = matchHeader("X-Amz-Copy-Source", ".*?(\\/|%2F).*?")
 	// PutObject
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
+	c.add(objectLevel, "PUT", newAPIRoute(api.PutObjectHandler, object, false))
 	// DeleteObject
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectHandler)
+	c.add(objectLevel, "DELETE", newAPIRoute(api.DeleteObjectHandler, object, false))
 
 	/// Bucket operations
 
 	// GetBucketLocation
-	bucket.Methods("GET").HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketLocationHandler, bucket, true, "location"))
 	// GetBucketPolicy
-	bucket.Methods("GET").HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketPolicyHandler, bucket, true, "policy"))
+	// GetBucketIntegrity
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketIntegrityHandler, bucket, true, "integrity"))
+	// GetBucketBandwidth
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketBandwidthHandler, bucket, true, "bandwidth"))
+	// GetBucketLogging
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketLoggingHandler, bucket, true, "logging"))
+	// GetBucketSecureDelete
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketSecureDeleteHandler, bucket, true, "secure-delete"))
+	// GetBucketTransform
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketTransformHandler, bucket, true, "transform"))
+	// MetadataQuery
+	c.add(bucketLevel, "GET", newAPIRoute(api.MetadataQueryHandler, bucket, true, "metadata-query"))
 	// GetBucketNotification
-	bucket.Methods("GET").HandlerFunc(api.GetBucketNotificationHandler).Queries("notification", "")
+	c.add(bucketLevel, "GET", newAPIRoute(api.GetBucketNotificationHandler, bucket, true, "notification"))
 	// ListenBucketNotification
-	bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+	c.add(bucketLevel, "GET", newAPIRoute(api.ListenBucketNotificationHandler, bucket, true, "events"))
 	// ListMultipartUploads
-	bucket.Methods("GET").HandlerFunc(api.ListMultipartUploadsHandler).Queries("uploads", "")
+	c.add(bucketLevel, "GET", newAPIRoute(api.ListMultipartUploadsHandler, bucket, true, "uploads"))
 	// ListObjectsV2
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
+	c.add(bucketLevel, "GET", newAPIRoute(api.ListObjectsV2Handler, bucket, true, "list-type")).match = func(r *http.Request, query url.Values) bool {
+		return query.Get("list-type") == "2"
+	}
 	// ListObjectsV1 (Legacy)
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
+	c.add(bucketLevel, "GET", newAPIRoute(api.ListObjectsV1Handler, bucket, true))
 	// PutBucketPolicy
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketPolicyHandler, bucket, true, "policy"))
+	// PutBucketIntegrity
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketIntegrityHandler, bucket, true, "integrity"))
+	// PutBucketBandwidth
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketBandwidthHandler, bucket, true, "bandwidth"))
+	// PutBucketLogging
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketLoggingHandler, bucket, true, "logging"))
+	// PutBucketSecureDelete
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketSecureDeleteHandler, bucket, true, "secure-delete"))
+	// PutBucketTransform
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketTransformHandler, bucket, true, "transform"))
 	// PutBucketNotification
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketNotificationHandler).Queries("notification", "")
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketNotificationHandler, bucket, true, "notification"))
 	// PutBucket
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
+	c.add(bucketLevel, "PUT", newAPIRoute(api.PutBucketHandler, bucket, true))
 	// HeadBucket
-	bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
-	// PostPolicy
-	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(api.PostPolicyBucketHandler)
-	// DeleteMultipleObjects
-	bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler)
+	c.add(bucketLevel, "HEAD", newAPIRoute(api.HeadBucketHandler, bucket, true))
+	// Object level POST requests not matching an object operation are
+	// served as bucket operations as well.
+	for _, level := range []int{objectLevel, bucketLevel} {
+		// PostPolicy
+		c.add(level, "POST", newAPIRoute(api.PostPolicyBucketHandler, bucket, true)).match = matchHeader("Content-Type", "multipart/form-data*")
+		// RenamePrefix (Minio extension)
+		c.add(level, "POST", newAPIRoute(api.RenamePrefixHandler, bucket, true, "rename"))
+		// CopyMultipleObjects (Minio extension)
+		c.add(level, "POST", newAPIRoute(api.CopyMultipleObjectsHandler, bucket, true, "copy"))
+		// DeleteMultipleObjects
+		c.add(level, "POST", newAPIRoute(api.DeleteMultipleObjectsHandler, bucket, true))
+	}
 	// DeleteBucketPolicy
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketPolicyHandler).Queries("policy", "")
+	c.add(bucketLevel, "DELETE", newAPIRoute(api.DeleteBucketPolicyHandler, bucket, true, "policy"))
 	// DeleteBucket
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
+	c.add(bucketLevel, "DELETE", newAPIRoute(api.DeleteBucketHandler, bucket, true))
 
 	/// Root operation
 
 	// ListBuckets
-	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
+	c.add(rootLevel, "GET", newAPIRoute(api.ListBucketsHandler, "/", true))
+
+	return c
 }
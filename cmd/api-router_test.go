@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// API requests and the handlers they are routed to.
+func getAPIRouteTestCases(api objectAPIHandlers) []struct {
+	method  string
+	url     string
+	header  http.Header
+	handler http.HandlerFunc
+	vars    map[string]string
+} {
+	return []struct {
+		method  string
+		url     string
+		header  http.Header
+		handler http.HandlerFunc
+		vars    map[string]string
+	}{
+		{"HEAD", "/bucket/dir/object", nil, api.HeadObjectHandler, map[string]string{"bucket": "bucket", "object": "dir/object"}},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=id", nil, api.PutObjectPartHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"PUT", "/bucket/object?partNumber=one&uploadId=id", nil, api.PutObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"GET", "/bucket/object?uploadId=id", nil, api.ListObjectPartsHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"POST", "/bucket/object?uploadId=id", nil, api.CompleteMultipartUploadHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"POST", "/bucket/object?compose", nil, api.ComposeObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"POST", "/bucket/object?uploads", nil, api.NewMultipartUploadHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"DELETE", "/bucket/object?uploadId=id", nil, api.AbortMultipartUploadHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"GET", "/bucket/object", nil, api.GetObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"PUT", "/bucket/object", http.Header{"X-Amz-Copy-Source": {"/src/object"}}, api.CopyObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"PUT", "/bucket/object", nil, api.PutObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		{"DELETE", "/bucket/object", nil, api.DeleteObjectHandler, map[string]string{"bucket": "bucket", "object": "object"}},
+		// Object level POST without a query falls back to the bucket routes.
+		{"POST", "/bucket/object", http.Header{"Content-Type": {"multipart/form-data; boundary=b"}}, api.PostPolicyBucketHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?location", nil, api.GetBucketLocationHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?policy", nil, api.GetBucketPolicyHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?events=s3:ObjectCreated:*", nil, api.ListenBucketNotificationHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?list-type=1", nil, api.ListObjectsV1Handler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?uploads", nil, api.ListMultipartUploadsHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket?list-type=2", nil, api.ListObjectsV2Handler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/bucket", nil, api.ListObjectsV1Handler, map[string]string{"bucket": "bucket"}},
+		{"PUT", "/bucket?notification", nil, api.PutBucketNotificationHandler, map[string]string{"bucket": "bucket"}},
+		{"PUT", "/bucket", nil, api.PutBucketHandler, map[string]string{"bucket": "bucket"}},
+		{"HEAD", "/bucket", nil, api.HeadBucketHandler, map[string]string{"bucket": "bucket"}},
+		{"POST", "/bucket", http.Header{"Content-Type": {"multipart/form-data; boundary=b"}}, api.PostPolicyBucketHandler, map[string]string{"bucket": "bucket"}},
+		{"POST", "/bucket?rename", nil, api.RenamePrefixHandler, map[string]string{"bucket": "bucket"}},
+		{"POST", "/bucket?copy", nil, api.CopyMultipleObjectsHandler, map[string]string{"bucket": "bucket"}},
+		{"POST", "/bucket?delete", nil, api.DeleteMultipleObjectsHandler, map[string]string{"bucket": "bucket"}},
+		{"DELETE", "/bucket?policy", nil, api.DeleteBucketPolicyHandler, map[string]string{"bucket": "bucket"}},
+		{"DELETE", "/bucket", nil, api.DeleteBucketHandler, map[string]string{"bucket": "bucket"}},
+		{"GET", "/", nil, api.ListBucketsHandler, map[string]string{}},
+		{"GET", "/bucket/", nil, api.ListObjectsV1Handler, map[string]string{"bucket": "bucket"}},
+	}
+}
+
+// Tests API requests are routed to their handlers.
+func TestAPIRouter(t *testing.T) {
+	api := objectAPIHandlers{ObjectAPI: newObjectLayerFn}
+	classifier := newAPIRequestClassifier()
+
+	for i, testCase := range getAPIRouteTestCases(api) {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		for k, v := range testCase.header {
+			req.Header[k] = v
+		}
+		var match router.RouteMatch
+		if route := classifier.route(req); route == nil || !route.router.Match(req, &match) {
+			t.Fatalf("Test %d: %s %s is not routed", i+1, testCase.method, testCase.url)
+		}
+		if reflect.ValueOf(match.Handler).Pointer() != reflect.ValueOf(testCase.handler).Pointer() {
+			t.Errorf("Test %d: %s %s is routed to the wrong handler", i+1, testCase.method, testCase.url)
+		}
+		if !reflect.DeepEqual(match.Vars, testCase.vars) {
+			t.Errorf("Test %d: %s %s expected vars %v, got %v", i+1, testCase.method, testCase.url, testCase.vars, match.Vars)
+		}
+	}
+}
+
+// Tests requests no API handles are not classified.
+func TestAPIRequestClassifierNotFound(t *testing.T) {
+	classifier := newAPIRequestClassifier()
+	for i, testCase := range []struct {
+		method string
+		url    string
+	}{
+		{"PUT", "/"},
+		{"PATCH", "/bucket"},
+		{"OPTIONS", "/bucket/object"},
+	} {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if classifier.route(req) != nil {
+			t.Errorf("Test %d: %s %s should not be routed", i+1, testCase.method, testCase.url)
+		}
+	}
+}
+
+// Benchmarks routing API requests.
+func BenchmarkAPIRouter(b *testing.B) {
+	api := objectAPIHandlers{ObjectAPI: newObjectLayerFn}
+	classifier := newAPIRequestClassifier()
+
+	var reqs []*http.Request
+	for _, testCase := range getAPIRouteTestCases(api) {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for k, v := range testCase.header {
+			req.Header[k] = v
+		}
+		reqs = append(reqs, req)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var match router.RouteMatch
+		req := reqs[i%len(reqs)]
+		classifier.route(req).router.Match(req, &match)
+	}
+}
@@ -104,7 +104,15 @@ func getRequestAuthType(r *http.Request) authType {
 	return authTypeUnknown
 }
 
-func checkRequestAuthType(r *http.Request, bucket, policyAction, region string) APIErrorCode {
+func checkRequestAuthType(r *http.Request, bucket, policyAction, region string) (s3Error APIErrorCode) {
+	_, span := startSpan(r.Context(), "auth")
+	defer func() {
+		if s3Error != ErrNone {
+			span.SetTag("s3-error", getAPIError(s3Error).Code)
+		}
+		span.Finish(nil)
+	}()
+
 	reqAuthType := getRequestAuthType(r)
 
 	switch reqAuthType {
@@ -113,19 +121,33 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
+			return s3Error
 		}
-		return s3Error
+		if s3Error = checkPresignedRequest(r); s3Error != ErrNone {
+			return s3Error
+		}
+		// Requests of IAM users are limited by their policies.
+		return checkIAMAccess(r, policyAction)
 	case authTypeSigned, authTypePresigned:
 		s3Error := isReqAuthenticated(r, region)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
+			return s3Error
+		}
+		if s3Error = checkIAMAccess(r, policyAction); s3Error != ErrNone {
+			return s3Error
+		}
+		if s3Error = checkPresignedRequest(r); s3Error != ErrNone {
+			return s3Error
 		}
-		return s3Error
+		// Links shared from the browser are valid until revoked.
+		return checkShareLink(r)
 	}
 
-	if reqAuthType == authTypeAnonymous && policyAction != "" {
+	// Actions of IAM policies only are never granted by bucket policies.
+	if reqAuthType == authTypeAnonymous && supportedActionMap.Contains(policyAction) {
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		return enforceBucketPolicy(bucket, policyAction, r.URL)
+		return checkAnonymousAccess(r, bucket, policyAction, r.URL)
 	}
 
 	// By default return ErrAccessDenied
@@ -223,8 +245,9 @@ func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		a.handler.ServeHTTP(w, r)
 		return
 	} else if aType == authTypeJWT {
-		// Validate Authorization header if its valid for JWT request.
-		if !isHTTPRequestValid(r) {
+		// Validate Authorization header if its valid for JWT request,
+		// the web handlers authorize the requests of IAM users.
+		if _, err := webReqestAuthenticate(r); err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
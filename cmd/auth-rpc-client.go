@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"net/rpc"
 	"sync"
 	"time"
@@ -43,6 +44,10 @@ type AuthRPCClient struct {
 	rpcClient  *RPCClient // Reconnectable RPC client to make any RPC call.
 	config     authConfig // Authentication configuration information.
 	authToken  string     // Authentication token.
+
+	// Protocol version and capabilities negotiated at login.
+	protocolVersion int
+	capabilities    map[string]bool
 }
 
 // newAuthRPCClient - returns a JWT based authenticated (go) rpc client, which does automatic reconnect.
@@ -69,6 +74,9 @@ func (authClient *AuthRPCClient) Login() (err error) {
 		Password:    authClient.config.secretKey,
 		Version:     Version,
 		RequestTime: time.Now().UTC(),
+
+		ProtocolVersion:    rpcProtocolVersion,
+		MinProtocolVersion: minRPCProtocolVersion,
 	}
 
 	reply := LoginRPCReply{}
@@ -79,12 +87,42 @@ func (authClient *AuthRPCClient) Login() (err error) {
 
 	// Logged in successfully.
 	authClient.authToken = reply.AuthToken
+	authClient.protocolVersion = reply.ProtocolVersion
+	authClient.capabilities = make(map[string]bool)
+	for _, capability := range reply.Capabilities {
+		authClient.capabilities[capability] = true
+	}
 
 	return nil
 }
 
+// ProtocolVersion - returns the protocol version negotiated with the
+// server at the last login, zero for servers of releases before
+// protocol versioning.
+func (authClient *AuthRPCClient) ProtocolVersion() int {
+	authClient.Lock()
+	defer authClient.Unlock()
+	return authClient.protocolVersion
+}
+
+// checkCapability - logs into the server and returns errRPCUnsupported
+// if the server did not announce capability, as servers of older
+// releases during a rolling upgrade.
+func (authClient *AuthRPCClient) checkCapability(capability string) error {
+	if err := authClient.Login(); err != nil {
+		return err
+	}
+
+	authClient.Lock()
+	defer authClient.Unlock()
+	if !authClient.capabilities[capability] {
+		return errRPCUnsupported
+	}
+	return nil
+}
+
 // call makes a RPC call after logs into the server.
-func (authClient *AuthRPCClient) call(serviceMethod string, args interface {
+func (authClient *AuthRPCClient) call(ctx context.Context, serviceMethod string, args interface {
 	SetAuthToken(authToken string)
 	SetRequestTime(requestTime time.Time)
 }, reply interface{}) (err error) {
@@ -95,7 +133,7 @@ func (authClient *AuthRPCClient) call(serviceMethod string, args interface {
 		args.SetRequestTime(time.Now().UTC())
 
 		// Do RPC call.
-		err = authClient.rpcClient.Call(serviceMethod, args, reply)
+		err = authClient.rpcClient.CallWithContext(ctx, serviceMethod, args, reply)
 	}
 	return err
 }
@@ -104,11 +142,20 @@ func (authClient *AuthRPCClient) call(serviceMethod string, args interface {
 func (authClient *AuthRPCClient) Call(serviceMethod string, args interface {
 	SetAuthToken(authToken string)
 	SetRequestTime(requestTime time.Time)
+}, reply interface{}) (err error) {
+	return authClient.CallWithContext(context.Background(), serviceMethod, args, reply)
+}
+
+// CallWithContext is the same as Call, except that it stops waiting
+// for the reply and retrying once ctx is done.
+func (authClient *AuthRPCClient) CallWithContext(ctx context.Context, serviceMethod string, args interface {
+	SetAuthToken(authToken string)
+	SetRequestTime(requestTime time.Time)
 }, reply interface{}) (err error) {
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 	for i := range newRetryTimer(time.Second, 30*time.Second, MaxJitter, doneCh) {
-		if err = authClient.call(serviceMethod, args, reply); err == rpc.ErrShutdown {
+		if err = authClient.call(ctx, serviceMethod, args, reply); err == rpc.ErrShutdown {
 			// As connection at server side is closed, close the rpc client.
 			authClient.Close()
 
@@ -131,6 +178,10 @@ func (authClient *AuthRPCClient) Close() error {
 	defer authClient.Unlock()
 
 	authClient.authToken = ""
+	// Negotiated again at the next login, with the server possibly
+	// restarted on another release.
+	authClient.protocolVersion = 0
+	authClient.capabilities = nil
 	return authClient.rpcClient.Close()
 }
 
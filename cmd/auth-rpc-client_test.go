@@ -53,3 +53,22 @@ func TestAuthRPCClient(t *testing.T) {
 		t.Fatalf("Unexpected node value %s, but expected %s", authRPC.ServiceEndpoint(), authCfg.serviceEndpoint)
 	}
 }
+
+// Tests RPCs of capabilities not announced by the server.
+func TestAuthRPCClientCheckCapability(t *testing.T) {
+	authRPC := newAuthRPCClient(authConfig{serverAddr: "localhost:9000", serviceName: "MyPackage"})
+	// Already logged into a server of an older release.
+	authRPC.authToken = "token"
+	authRPC.capabilities = map[string]bool{rpcCapServerHealth: true}
+
+	if err := authRPC.checkCapability(rpcCapServerHealth); err != nil {
+		t.Errorf("Expected capability %s, got %v", rpcCapServerHealth, err)
+	}
+	if err := authRPC.checkCapability(rpcCapInflightRequests); err != errRPCUnsupported {
+		t.Errorf("Expected %v, got %v", errRPCUnsupported, err)
+	}
+	remote := remoteAdminClient{authRPC}
+	if _, err := remote.ListRequests(); err != errRPCUnsupported {
+		t.Errorf("Expected %v, got %v", errRPCUnsupported, err)
+	}
+}
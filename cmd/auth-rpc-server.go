@@ -39,5 +39,14 @@ func (b AuthRPCServer) Login(args *LoginRPCArgs, reply *LoginRPCReply) error {
 	// Return the token.
 	reply.AuthToken = token
 
+	// Negotiate the protocol version with clients negotiating one.
+	if args.ProtocolVersion != 0 {
+		reply.ProtocolVersion = rpcProtocolVersion
+		if args.ProtocolVersion < rpcProtocolVersion {
+			reply.ProtocolVersion = args.ProtocolVersion
+		}
+		reply.Capabilities = rpcCapabilities
+	}
+
 	return nil
 }
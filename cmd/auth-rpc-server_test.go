@@ -54,6 +54,30 @@ func TestLogin(t *testing.T) {
 			skewTime:    0,
 			expectedErr: errServerVersionMismatch,
 		},
+		// Other release, overlapping protocol versions.
+		{
+			args: LoginRPCArgs{
+				Username:           creds.AccessKey,
+				Password:           creds.SecretKey,
+				Version:            "INVALID-" + Version,
+				ProtocolVersion:    rpcProtocolVersion + 1,
+				MinProtocolVersion: rpcProtocolVersion,
+			},
+			skewTime:    0,
+			expectedErr: nil,
+		},
+		// Other release, no protocol version served by both.
+		{
+			args: LoginRPCArgs{
+				Username:           creds.AccessKey,
+				Password:           creds.SecretKey,
+				Version:            "INVALID-" + Version,
+				ProtocolVersion:    rpcProtocolVersion + 2,
+				MinProtocolVersion: rpcProtocolVersion + 1,
+			},
+			skewTime:    0,
+			expectedErr: errServerVersionMismatch,
+		},
 		// Valid username, password and version, not request time
 		{
 			args: LoginRPCArgs{
@@ -115,3 +139,48 @@ func TestLogin(t *testing.T) {
 		}
 	}
 }
+
+// Tests the protocol version and capabilities negotiated at login.
+func TestLoginProtocolNegotiation(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Failed to create test config - %v", err)
+	}
+	defer removeAll(rootPath)
+	creds := serverConfig.GetCredential()
+	ls := AuthRPCServer{}
+
+	// Clients of a newer release negotiate the latest version of this server.
+	args := LoginRPCArgs{
+		Username:           creds.AccessKey,
+		Password:           creds.SecretKey,
+		ProtocolVersion:    rpcProtocolVersion + 1,
+		MinProtocolVersion: minRPCProtocolVersion,
+		RequestTime:        time.Now().UTC(),
+	}
+	reply := LoginRPCReply{}
+	if err = ls.Login(&args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.ProtocolVersion != rpcProtocolVersion {
+		t.Errorf("Expected protocol version %d, got %d", rpcProtocolVersion, reply.ProtocolVersion)
+	}
+	if len(reply.Capabilities) != len(rpcCapabilities) {
+		t.Errorf("Expected capabilities %v, got %v", rpcCapabilities, reply.Capabilities)
+	}
+
+	// Clients of releases before protocol versioning negotiate nothing.
+	args = LoginRPCArgs{
+		Username:    creds.AccessKey,
+		Password:    creds.SecretKey,
+		Version:     Version,
+		RequestTime: time.Now().UTC(),
+	}
+	reply = LoginRPCReply{}
+	if err = ls.Login(&args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.ProtocolVersion != 0 || len(reply.Capabilities) != 0 {
+		t.Errorf("Expected no negotiation, got version %d and capabilities %v", reply.ProtocolVersion, reply.Capabilities)
+	}
+}
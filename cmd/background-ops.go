@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Environment variables configuring background operations, such as
+// healing and multipart cleanup.
+const (
+	// Percentage of the time background operations may keep the
+	// disks busy, such as "25".
+	backgroundIOShareEnv = "MINIO_BACKGROUND_IO_SHARE"
+
+	// Latency of foreground requests pausing background operations,
+	// such as "500ms", "off" never pauses them.
+	backgroundLatencyLimitEnv = "MINIO_BACKGROUND_LATENCY_LIMIT"
+)
+
+const (
+	// Background operations keep the disks busy a quarter of the
+	// time by default.
+	defaultBackgroundIOShare = 25
+
+	// Background operations pause while foreground requests take
+	// longer than half a second to be answered by default.
+	defaultBackgroundLatencyLimit = 500 * time.Millisecond
+
+	// Interval between two checks of the foreground latency while
+	// background operations are paused.
+	backgroundPauseInterval = 100 * time.Millisecond
+
+	// Longest pause of a background operation, background operations
+	// are not starved by a busy server.
+	maxBackgroundPause = time.Minute
+
+	// Foreground latency idle for longer is not accounted, requests
+	// served then being too old to tell the current load.
+	foregroundLatencyWindow = 5 * time.Second
+)
+
+// Global scheduler of background operations.
+var globalBackgroundOps = newBackgroundOps(defaultBackgroundIOShare, defaultBackgroundLatencyLimit)
+
+// getBackgroundIOShare - returns the percentage of time available to
+// background operations configured through the environment.
+func getBackgroundIOShare() (int, error) {
+	value := os.Getenv(backgroundIOShareEnv)
+	if value == "" {
+		return defaultBackgroundIOShare, nil
+	}
+	share, err := strconv.Atoi(value)
+	if err != nil || share <= 0 || share > 100 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be a percentage between 1 and 100", backgroundIOShareEnv, value)
+	}
+	return share, nil
+}
+
+// getBackgroundLatencyLimit - returns the foreground latency pausing
+// background operations configured through the environment, 0 if
+// background operations are never paused.
+func getBackgroundLatencyLimit() (time.Duration, error) {
+	value := os.Getenv(backgroundLatencyLimitEnv)
+	switch value {
+	case "":
+		return defaultBackgroundLatencyLimit, nil
+	case "off":
+		return 0, nil
+	}
+	limit, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", backgroundLatencyLimitEnv, value, err)
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive", backgroundLatencyLimitEnv, value)
+	}
+	return limit, nil
+}
+
+// backgroundOps - schedules the background operations of all the
+// subsystems, one at a time. Operations are followed by enough idle
+// time for background work to keep the disks busy at most share
+// percent of the time, and wait while foreground requests are slow.
+type backgroundOps struct {
+	// Serializes background operations.
+	opMutex sync.Mutex
+
+	mutex        sync.Mutex
+	share        int
+	latencyLimit time.Duration
+
+	// Moving average of the foreground latency, as of latencyTime.
+	latency     time.Duration
+	latencyTime time.Time
+
+	// Sleeps between operations, time.Sleep unless overridden by tests.
+	sleep func(time.Duration)
+}
+
+// newBackgroundOps - initialize a scheduler of background operations.
+func newBackgroundOps(share int, latencyLimit time.Duration) *backgroundOps {
+	return &backgroundOps{
+		share:        share,
+		latencyLimit: latencyLimit,
+		sleep:        time.Sleep,
+	}
+}
+
+// SetConfig - updates the share of time and the latency limit.
+func (b *backgroundOps) SetConfig(share int, latencyLimit time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.share = share
+	b.latencyLimit = latencyLimit
+}
+
+// observeForeground - accounts the latency of a foreground request.
+func (b *backgroundOps) observeForeground(latency time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := time.Now()
+	if now.Sub(b.latencyTime) > foregroundLatencyWindow {
+		b.latency = latency
+	} else {
+		b.latency = (9*b.latency + latency) / 10
+	}
+	b.latencyTime = now
+}
+
+// isForegroundSlow - returns true if foreground requests are recently
+// answered slower than the latency limit.
+func (b *backgroundOps) isForegroundSlow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.latencyLimit == 0 || time.Now().Sub(b.latencyTime) > foregroundLatencyWindow {
+		return false
+	}
+	return b.latency > b.latencyLimit
+}
+
+// Do - runs a background operation once the foreground requests are
+// fast enough, then waits for the idle time following it.
+func (b *backgroundOps) Do(op func() error) error {
+	b.opMutex.Lock()
+	defer b.opMutex.Unlock()
+
+	for paused := time.Duration(0); b.isForegroundSlow() && paused < maxBackgroundPause; paused += backgroundPauseInterval {
+		b.sleep(backgroundPauseInterval)
+	}
+
+	start := time.Now()
+	err := op()
+	busy := time.Since(start)
+
+	b.mutex.Lock()
+	share := b.share
+	b.mutex.Unlock()
+	if share < 100 {
+		b.sleep(busy * time.Duration(100-share) / time.Duration(share))
+	}
+	return err
+}
+
+// foregroundLatencyHandler - accounts the latency of the API requests,
+// until the first byte of their response, to pause background
+// operations while the server is loaded.
+type foregroundLatencyHandler struct {
+	handler http.Handler
+}
+
+// setForegroundLatencyHandler to account the latency of the requests.
+func setForegroundLatencyHandler(h http.Handler) http.Handler {
+	return foregroundLatencyHandler{h}
+}
+
+// latencyResponseWriter - accounts the latency at the first write of
+// the response.
+type latencyResponseWriter struct {
+	http.ResponseWriter
+	start    time.Time
+	observed bool
+}
+
+func (w *latencyResponseWriter) observe() {
+	if !w.observed {
+		w.observed = true
+		globalBackgroundOps.observeForeground(time.Since(w.start))
+	}
+}
+
+func (w *latencyResponseWriter) WriteHeader(code int) {
+	w.observe()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *latencyResponseWriter) Write(p []byte) (int, error) {
+	w.observe()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush - flushes the underlying response writer.
+func (w *latencyResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (h foregroundLatencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	lw := &latencyResponseWriter{ResponseWriter: w, start: time.Now()}
+	h.handler.ServeHTTP(lw, r)
+	lw.observe()
+}
@@ -0,0 +1,140 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the background operations configuration.
+func TestGetBackgroundOpsConfig(t *testing.T) {
+	defer os.Unsetenv(backgroundIOShareEnv)
+	defer os.Unsetenv(backgroundLatencyLimitEnv)
+
+	shareCases := []struct {
+		value      string
+		share      int
+		shouldPass bool
+	}{
+		{"", defaultBackgroundIOShare, true},
+		{"10", 10, true},
+		{"100", 100, true},
+		{"0", 0, false},
+		{"101", 0, false},
+		{"10%", 0, false},
+	}
+	for i, testCase := range shareCases {
+		os.Setenv(backgroundIOShareEnv, testCase.value)
+		share, err := getBackgroundIOShare()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if share != testCase.share {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.share, share)
+		}
+	}
+
+	limitCases := []struct {
+		value      string
+		limit      time.Duration
+		shouldPass bool
+	}{
+		{"", defaultBackgroundLatencyLimit, true},
+		{"off", 0, true},
+		{"1s", time.Second, true},
+		{"0s", 0, false},
+		{"fast", 0, false},
+	}
+	for i, testCase := range limitCases {
+		os.Setenv(backgroundLatencyLimitEnv, testCase.value)
+		limit, err := getBackgroundLatencyLimit()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if limit != testCase.limit {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.limit, limit)
+		}
+	}
+}
+
+// Tests background operations are followed by idle time and paused
+// while foreground requests are slow.
+func TestBackgroundOps(t *testing.T) {
+	var slept []time.Duration
+	ops := newBackgroundOps(25, 500*time.Millisecond)
+	ops.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+	}
+
+	// Operations keep the disks busy a quarter of the time.
+	if err := ops.Do(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(slept) != 1 || slept[0] < 30*time.Millisecond {
+		t.Fatalf("Expected at least 30ms idle after the operation, got %v", slept)
+	}
+
+	// Slow foreground requests pause operations, up to the longest pause.
+	slept = nil
+	ops.observeForeground(time.Second)
+	ops.Do(func() error { return nil })
+	if pauses := len(slept) - 1; pauses != int(maxBackgroundPause/backgroundPauseInterval) {
+		t.Fatalf("Expected the operation to be paused, got %d pauses", pauses)
+	}
+
+	// Fast requests resume operations.
+	slept = nil
+	for i := 0; i < 100; i++ {
+		ops.observeForeground(time.Millisecond)
+	}
+	ops.Do(func() error { return nil })
+	if len(slept) != 1 {
+		t.Fatalf("Expected the operation not to be paused, got %v", slept)
+	}
+
+	// All the time may be given to background operations, never paused.
+	slept = nil
+	ops.SetConfig(100, 0)
+	ops.observeForeground(time.Hour)
+	ops.Do(func() error { return nil })
+	if len(slept) != 0 {
+		t.Fatalf("Expected no idle time nor pause, got %v", slept)
+	}
+}
+
+// Tests the latency of requests is accounted until the first byte of
+// their response.
+func TestForegroundLatencyHandler(t *testing.T) {
+	defer func(ops *backgroundOps) { globalBackgroundOps = ops }(globalBackgroundOps)
+	globalBackgroundOps = newBackgroundOps(defaultBackgroundIOShare, 10*time.Millisecond)
+
+	handler := setForegroundLatencyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("slow"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !globalBackgroundOps.isForegroundSlow() {
+		t.Fatal("Expected the foreground to be slow")
+	}
+}
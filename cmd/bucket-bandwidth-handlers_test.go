@@ -0,0 +1,133 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling bucket bandwidth HTTP handler tests for both XL multiple disks and single node setup.
+func TestBucketBandwidthHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testBucketBandwidthHandlers, []string{"PutBucketBandwidth", "GetBucketBandwidth", "PutObject", "GetObject"})
+}
+
+func testBucketBandwidthHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Bandwidth changes are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	putBandwidth := func(body []byte, accessKey string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketBandwidthURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutBucketBandwidth: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getBandwidth := func() BandwidthConfiguration {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketBandwidthURL("", bucketName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetBucketBandwidth: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		config := BandwidthConfiguration{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+		}
+		return config
+	}
+
+	limitXML := []byte("<BandwidthConfiguration><UploadLimit>1048576</UploadLimit><DownloadLimit>2097152</DownloadLimit></BandwidthConfiguration>")
+	if code := putBandwidth(limitXML, "Invalid-AccessID"); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := putBandwidth([]byte("<BandwidthConfiguration>"), credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	negativeXML := []byte("<BandwidthConfiguration><UploadLimit>-1</UploadLimit></BandwidthConfiguration>")
+	if code := putBandwidth(negativeXML, credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if !getBandwidth().isUnlimited() {
+		t.Fatalf("%s: Expected no bandwidth limits by default", instanceType)
+	}
+
+	// Limit the bandwidth of the bucket.
+	if code := putBandwidth(limitXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	expected := BandwidthConfiguration{UploadLimit: 1048576, DownloadLimit: 2097152}
+	if config := getBandwidth(); config.UploadLimit != expected.UploadLimit || config.DownloadLimit != expected.DownloadLimit {
+		t.Fatalf("%s: Expected the bandwidth config %#v, got %#v", instanceType, expected, config)
+	}
+	if config := globalBucketBandwidth.Get(bucketName); config.UploadLimit != expected.UploadLimit || config.DownloadLimit != expected.DownloadLimit {
+		t.Fatalf("%s: Expected the bandwidth limiters %#v, got %#v", instanceType, expected, config)
+	}
+
+	// Uploads and downloads go through the limiters.
+	data := []byte("hello, bandwidth")
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for PutObject: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, "object"),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for GetObject: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: Expected the object data %q, got %q", instanceType, data, rec.Body.Bytes())
+	}
+
+	// Remove the limits.
+	if code := putBandwidth([]byte("<BandwidthConfiguration></BandwidthConfiguration>"), credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if !getBandwidth().isUnlimited() || !globalBucketBandwidth.Get(bucketName).isUnlimited() {
+		t.Fatalf("%s: Expected no bandwidth limits anymore", instanceType)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Throughput is the average over this many last seconds.
+	bandwidthMonitorWindow = 10
+
+	// Interval between two throughput reports of the monitor stream.
+	bandwidthReportInterval = time.Second
+)
+
+// bucketThroughput - bytes per second sent from and received into a
+// bucket, averaged over the monitor window.
+type bucketThroughput struct {
+	SentPerSecond     float64 `json:"sentPerSecond"`
+	ReceivedPerSecond float64 `json:"receivedPerSecond"`
+}
+
+// bandwidthReport - throughput of the buckets transferring data in
+// the monitor window.
+type bandwidthReport struct {
+	Time    time.Time                   `json:"time"`
+	Buckets map[string]bucketThroughput `json:"buckets"`
+}
+
+// bandwidthCounter - bytes transferred by a bucket in every second of
+// the monitor window.
+type bandwidthCounter struct {
+	seconds  [bandwidthMonitorWindow]int64
+	sent     [bandwidthMonitorWindow]uint64
+	received [bandwidthMonitorWindow]uint64
+	last     int64
+}
+
+// add - counts the bytes transferred at second now.
+func (c *bandwidthCounter) add(now int64, sent, received uint64) {
+	i := now % bandwidthMonitorWindow
+	if c.seconds[i] != now {
+		c.seconds[i], c.sent[i], c.received[i] = now, 0, 0
+	}
+	c.sent[i] += sent
+	c.received[i] += received
+	c.last = now
+}
+
+// throughput - returns the throughput over the window ending at
+// second now.
+func (c *bandwidthCounter) throughput(now int64) bucketThroughput {
+	var sent, received uint64
+	for i := range c.seconds {
+		if now-c.seconds[i] < bandwidthMonitorWindow {
+			sent += c.sent[i]
+			received += c.received[i]
+		}
+	}
+	return bucketThroughput{
+		SentPerSecond:     float64(sent) / bandwidthMonitorWindow,
+		ReceivedPerSecond: float64(received) / bandwidthMonitorWindow,
+	}
+}
+
+// bandwidthMonitor - bytes transferred by the S3 API requests of every
+// bucket, as they are sent and received.
+type bandwidthMonitor struct {
+	mutex    sync.Mutex
+	counters map[string]*bandwidthCounter
+	now      func() time.Time
+}
+
+// newBandwidthMonitor - returns a monitor with no transfers yet.
+func newBandwidthMonitor() *bandwidthMonitor {
+	return &bandwidthMonitor{
+		counters: make(map[string]*bandwidthCounter),
+		now:      time.Now,
+	}
+}
+
+// Global bandwidth monitor of the S3 API requests of this server.
+var globalBandwidthMonitor = newBandwidthMonitor()
+
+// add - counts the bytes sent from and received into a bucket.
+func (m *bandwidthMonitor) add(bucket string, sent, received int) {
+	now := m.now().Unix()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	counter, ok := m.counters[bucket]
+	if !ok {
+		counter = &bandwidthCounter{}
+		m.counters[bucket] = counter
+	}
+	counter.add(now, uint64(sent), uint64(received))
+}
+
+// Report - returns the throughput of the buckets transferring data in
+// the window, or only of bucket when not empty.
+func (m *bandwidthMonitor) Report(bucket string) bandwidthReport {
+	now := m.now()
+	report := bandwidthReport{Time: now.UTC(), Buckets: make(map[string]bucketThroughput)}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for name, counter := range m.counters {
+		// Counters of idle buckets are dropped.
+		if now.Unix()-counter.last >= bandwidthMonitorWindow {
+			delete(m.counters, name)
+			continue
+		}
+		if bucket == "" || bucket == name {
+			report.Buckets[name] = counter.throughput(now.Unix())
+		}
+	}
+	return report
+}
+
+// monitoredResponseWriter - counts the bytes of a response as they are
+// sent.
+type monitoredResponseWriter struct {
+	http.ResponseWriter
+	monitor *bandwidthMonitor
+	bucket  string
+}
+
+func (w *monitoredResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.monitor.add(w.bucket, n, 0)
+	return n, err
+}
+
+// Flush - flushes the underlying response writer.
+func (w *monitoredResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// monitoredRequestBody - counts the bytes of a request body as they
+// are received.
+type monitoredRequestBody struct {
+	io.ReadCloser
+	monitor *bandwidthMonitor
+	bucket  string
+}
+
+func (b *monitoredRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.monitor.add(b.bucket, 0, n)
+	return n, err
+}
+
+// monitor - returns w and r counting the bytes transferred by the
+// bucket of the request, unless it has none.
+func (m *bandwidthMonitor) monitor(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request) {
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	if bucket == "" {
+		return w, r
+	}
+	if r.Body != nil {
+		r.Body = &monitoredRequestBody{ReadCloser: r.Body, monitor: m, bucket: bucket}
+	}
+	return &monitoredResponseWriter{ResponseWriter: w, monitor: m, bucket: bucket}, r
+}
+
+// writeBandwidthReports - writes a throughput report of bucket, all the
+// buckets if empty, every interval until the client goes away.
+func writeBandwidthReports(w http.ResponseWriter, r *http.Request, m *bandwidthMonitor, bucket string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reportBytes, err := json.Marshal(m.Report(bucket))
+		if err != nil {
+			errorIf(err, "Failed to marshal bandwidth report into json.")
+			return
+		}
+		// Reports are separated by CRLF, as bucket notifications.
+		if _, err = w.Write(append(reportBytes, crlf...)); err != nil {
+			return
+		}
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
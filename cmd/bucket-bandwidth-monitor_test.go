@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests averaging the throughput of the buckets over the window.
+func TestBandwidthMonitorReport(t *testing.T) {
+	now := time.Unix(1000, 0)
+	monitor := newBandwidthMonitor()
+	monitor.now = func() time.Time { return now }
+
+	monitor.add("photos", 1000, 0)
+	monitor.add("photos", 1000, 500)
+	monitor.add("videos", 0, 2000)
+	now = now.Add(5 * time.Second)
+	monitor.add("photos", 8000, 0)
+
+	report := monitor.Report("")
+	if photos := report.Buckets["photos"]; photos.SentPerSecond != 1000 || photos.ReceivedPerSecond != 50 {
+		t.Errorf("Unexpected throughput of photos %#v", photos)
+	}
+	if videos := report.Buckets["videos"]; videos.SentPerSecond != 0 || videos.ReceivedPerSecond != 200 {
+		t.Errorf("Unexpected throughput of videos %#v", videos)
+	}
+	if report = monitor.Report("videos"); len(report.Buckets) != 1 {
+		t.Errorf("Expected only the throughput of videos, got %#v", report.Buckets)
+	}
+
+	// Transfers older than the window are not counted.
+	now = now.Add(7 * time.Second)
+	report = monitor.Report("")
+	if photos := report.Buckets["photos"]; photos.SentPerSecond != 800 {
+		t.Errorf("Unexpected throughput of photos %#v", photos)
+	}
+	if _, ok := report.Buckets["videos"]; ok {
+		t.Errorf("Expected the idle videos not to be reported")
+	}
+	if _, ok := monitor.counters["videos"]; ok {
+		t.Errorf("Expected the counter of the idle videos to be dropped")
+	}
+}
+
+// Tests counting the bytes of the requests and responses of a bucket.
+func TestBandwidthMonitorRequests(t *testing.T) {
+	monitor := newBandwidthMonitor()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("hello, world"))
+	}
+
+	req := httptest.NewRequest("PUT", "/photos/a.jpg", bytes.NewReader([]byte("0123456789")))
+	w, r := monitor.monitor(httptest.NewRecorder(), req)
+	handler(w, r)
+	req = httptest.NewRequest("GET", "/", nil)
+	w, r = monitor.monitor(httptest.NewRecorder(), req)
+	handler(w, r)
+
+	report := monitor.Report("")
+	if len(report.Buckets) != 1 {
+		t.Fatalf("Expected only the throughput of photos, got %#v", report.Buckets)
+	}
+	if photos := report.Buckets["photos"]; photos.SentPerSecond != 12.0/bandwidthMonitorWindow || photos.ReceivedPerSecond != 10.0/bandwidthMonitorWindow {
+		t.Errorf("Unexpected throughput of photos %#v", photos)
+	}
+}
+
+// failingResponseWriter - fails the writes after the first ones.
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *failingResponseWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, errors.New("connection closed")
+	}
+	w.writes--
+	return w.ResponseRecorder.Write(p)
+}
+
+// Tests streaming the throughput reports until the client goes away.
+func TestWriteBandwidthReports(t *testing.T) {
+	monitor := newBandwidthMonitor()
+	monitor.add("photos", 100, 0)
+
+	w := &failingResponseWriter{ResponseRecorder: httptest.NewRecorder(), writes: 3}
+	writeBandwidthReports(w, httptest.NewRequest("GET", "/?bandwidth", nil), monitor, "", time.Millisecond)
+
+	reports := strings.Split(strings.TrimSuffix(w.Body.String(), "\r\n"), "\r\n")
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %q", w.Body.String())
+	}
+	for _, reportJSON := range reports {
+		var report bandwidthReport
+		if err := json.Unmarshal([]byte(reportJSON), &report); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := report.Buckets["photos"]; !ok {
+			t.Errorf("Expected the throughput of photos, got %s", reportJSON)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket bandwidth config file, stored only for buckets with
+	// bandwidth limits.
+	bucketBandwidthConfig = "bandwidth.json"
+)
+
+// BandwidthConfiguration - bandwidth limits of the uploads into and
+// the downloads from a bucket, in bytes per second, as set by
+// PutBucketBandwidth and persisted in bucketBandwidthConfig. Zero
+// stands for unlimited.
+type BandwidthConfiguration struct {
+	XMLName       xml.Name `xml:"BandwidthConfiguration" json:"-"`
+	UploadLimit   int64    `xml:"UploadLimit" json:"uploadLimit"`
+	DownloadLimit int64    `xml:"DownloadLimit" json:"downloadLimit"`
+}
+
+// isUnlimited - returns true if the config does not limit any bandwidth.
+func (config BandwidthConfiguration) isUnlimited() bool {
+	return config.UploadLimit == 0 && config.DownloadLimit == 0
+}
+
+// tokenBucket - limits the bytes transferred to rate bytes per second,
+// shared by all the transfers of a bucket so that they do not exceed
+// the rate altogether.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   int64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket - returns a full token bucket for rate bytes per second,
+// which is also the largest burst allowed.
+func newTokenBucket(rate int64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// wait - blocks until n bytes, at most rate, can be transferred.
+func (tb *tokenBucket) wait(n int) {
+	tb.mutex.Lock()
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * float64(tb.rate)
+	if tb.tokens > float64(tb.rate) {
+		tb.tokens = float64(tb.rate)
+	}
+	tb.last = now
+	// Tokens are reserved right away, concurrent transfers wait in turn.
+	tb.tokens -= float64(n)
+	deficit := -tb.tokens
+	tb.mutex.Unlock()
+
+	if deficit > 0 {
+		time.Sleep(time.Duration(deficit / float64(tb.rate) * float64(time.Second)))
+	}
+}
+
+// chunkSize - returns the largest transfer allowed in a single wait.
+func (tb *tokenBucket) chunkSize() int {
+	const maxChunkSize = 32 * 1024
+	if tb.rate < maxChunkSize {
+		return int(tb.rate)
+	}
+	return maxChunkSize
+}
+
+// throttledReader - reads from an io.Reader within the rate of a token bucket.
+type throttledReader struct {
+	reader io.Reader
+	bucket *tokenBucket
+}
+
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	if chunkSize := tr.bucket.chunkSize(); len(p) > chunkSize {
+		p = p[:chunkSize]
+	}
+	n, err := tr.reader.Read(p)
+	if n > 0 {
+		tr.bucket.wait(n)
+	}
+	return n, err
+}
+
+// throttledWriter - writes to an io.Writer within the rate of a token bucket.
+type throttledWriter struct {
+	writer io.Writer
+	bucket *tokenBucket
+}
+
+func (tw *throttledWriter) Write(p []byte) (n int, err error) {
+	chunkSize := tw.bucket.chunkSize()
+	for {
+		chunk := p
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		tw.bucket.wait(len(chunk))
+		m, err := tw.writer.Write(chunk)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		p = p[len(chunk):]
+		// Empty writes are passed through as well.
+		if len(p) == 0 {
+			return n, nil
+		}
+	}
+}
+
+// bandwidthLimiters - token buckets of the uploads and the downloads of
+// a bucket or of an IAM user, nil when unlimited.
+type bandwidthLimiters struct {
+	config   BandwidthConfiguration
+	upload   *tokenBucket
+	download *tokenBucket
+}
+
+// bucketBandwidth - in-memory bandwidth limiters of all the buckets
+// and of the IAM users with bandwidth limits.
+type bucketBandwidth struct {
+	rwMutex  *sync.RWMutex
+	limiters map[string]*bandwidthLimiters
+
+	// Limiters of the IAM users keyed by their access keys, created
+	// on their first transfer and replaced once their limits change.
+	users map[string]*bandwidthLimiters
+}
+
+// Global bandwidth limiters, loaded at object layer initialization
+// and kept up to date by the peers on every change. The limits apply
+// to every server separately.
+var globalBucketBandwidth = &bucketBandwidth{
+	rwMutex:  &sync.RWMutex{},
+	limiters: make(map[string]*bandwidthLimiters),
+	users:    make(map[string]*bandwidthLimiters),
+}
+
+// newBandwidthLimiters - returns the token buckets of a bandwidth config.
+func newBandwidthLimiters(config BandwidthConfiguration) *bandwidthLimiters {
+	limiters := &bandwidthLimiters{config: config}
+	if config.UploadLimit > 0 {
+		limiters.upload = newTokenBucket(config.UploadLimit)
+	}
+	if config.DownloadLimit > 0 {
+		limiters.download = newTokenBucket(config.DownloadLimit)
+	}
+	return limiters
+}
+
+// Get - returns the bandwidth config of a bucket.
+func (bb *bucketBandwidth) Get(bucket string) BandwidthConfiguration {
+	bb.rwMutex.RLock()
+	defer bb.rwMutex.RUnlock()
+	if limiters, ok := bb.limiters[bucket]; ok {
+		return limiters.config
+	}
+	return BandwidthConfiguration{}
+}
+
+// Set - sets the bandwidth config of a bucket, a nil config removes it.
+func (bb *bucketBandwidth) Set(bucket string, config *BandwidthConfiguration) {
+	bb.rwMutex.Lock()
+	defer bb.rwMutex.Unlock()
+	if config == nil || config.isUnlimited() {
+		delete(bb.limiters, bucket)
+		return
+	}
+	bb.limiters[bucket] = newBandwidthLimiters(*config)
+}
+
+// userLimiters - returns the limiters of the IAM user accessKey, nil
+// if its bandwidth is unlimited.
+func (bb *bucketBandwidth) userLimiters(accessKey string) *bandwidthLimiters {
+	config := globalIAMSys.GetUserBandwidth(accessKey)
+	if config.isUnlimited() {
+		return nil
+	}
+
+	bb.rwMutex.RLock()
+	limiters, ok := bb.users[accessKey]
+	bb.rwMutex.RUnlock()
+	if ok && limiters.config == config {
+		return limiters
+	}
+
+	bb.rwMutex.Lock()
+	defer bb.rwMutex.Unlock()
+	// Created meanwhile by a concurrent transfer.
+	if limiters, ok = bb.users[accessKey]; ok && limiters.config == config {
+		return limiters
+	}
+	limiters = newBandwidthLimiters(config)
+	bb.users[accessKey] = limiters
+	return limiters
+}
+
+// ThrottleReader - returns a reader of the data uploaded into a bucket
+// by accessKey, within the upload bandwidth limits of the bucket and
+// of the IAM user if any.
+func (bb *bucketBandwidth) ThrottleReader(bucket, accessKey string, reader io.Reader) io.Reader {
+	if limiters := bb.userLimiters(accessKey); limiters != nil && limiters.upload != nil {
+		reader = &throttledReader{reader: reader, bucket: limiters.upload}
+	}
+	bb.rwMutex.RLock()
+	defer bb.rwMutex.RUnlock()
+	if limiters, ok := bb.limiters[bucket]; ok && limiters.upload != nil {
+		return &throttledReader{reader: reader, bucket: limiters.upload}
+	}
+	return reader
+}
+
+// ThrottleWriter - returns a writer of the data downloaded from a bucket
+// by accessKey, within the download bandwidth limits of the bucket and
+// of the IAM user if any.
+func (bb *bucketBandwidth) ThrottleWriter(bucket, accessKey string, writer io.Writer) io.Writer {
+	if limiters := bb.userLimiters(accessKey); limiters != nil && limiters.download != nil {
+		writer = &throttledWriter{writer: writer, bucket: limiters.download}
+	}
+	bb.rwMutex.RLock()
+	defer bb.rwMutex.RUnlock()
+	if limiters, ok := bb.limiters[bucket]; ok && limiters.download != nil {
+		return &throttledWriter{writer: writer, bucket: limiters.download}
+	}
+	return writer
+}
+
+// Intialize bandwidth limiters of all buckets.
+func initBucketBandwidth(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	limiters := make(map[string]*bandwidthLimiters)
+	for _, bucket := range buckets {
+		config, rErr := readBucketBandwidth(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other configs if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if !config.isUnlimited() {
+			limiters[bucket.Name] = newBandwidthLimiters(config)
+		}
+	}
+
+	globalBucketBandwidth.rwMutex.Lock()
+	globalBucketBandwidth.limiters = limiters
+	globalBucketBandwidth.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// readBucketBandwidth - reads the bandwidth config of a bucket, buckets
+// without a persisted config are unlimited.
+func readBucketBandwidth(bucket string, objAPI ObjectLayer) (BandwidthConfiguration, error) {
+	bandwidthPath := pathJoin(bucketConfigPrefix, bucket, bucketBandwidthConfig)
+
+	// Acquire a read lock on bandwidth config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, bandwidthPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var config BandwidthConfiguration
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, bandwidthPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load bandwidth config for the bucket %s.", bucket)
+		return config, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse bandwidth config for the bucket %s.", bucket)
+		return config, err
+	}
+	return config, nil
+}
+
+// writeBucketBandwidth - saves the bandwidth config of a bucket.
+func writeBucketBandwidth(bucket string, config BandwidthConfiguration, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	bandwidthPath := pathJoin(bucketConfigPrefix, bucket, bucketBandwidthConfig)
+	// Acquire a write lock on bandwidth config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, bandwidthPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, bandwidthPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set bandwidth config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketBandwidth - removes the persisted bandwidth config of a
+// bucket, if any.
+func removeBucketBandwidth(bucket string, objAPI ObjectLayer) error {
+	bandwidthPath := pathJoin(bucketConfigPrefix, bucket, bucketBandwidthConfig)
+	// Acquire a write lock on bandwidth config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, bandwidthPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, bandwidthPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// Tests limiting reads and writes to the rate of a token bucket.
+func TestThrottledReaderWriter(t *testing.T) {
+	const rate = 2000
+	data := bytes.Repeat([]byte("a"), 3*rate/2)
+
+	// The first rate bytes are a burst, the rest takes half a second.
+	start := time.Now()
+	reader := &throttledReader{reader: bytes.NewReader(data), bucket: newTokenBucket(rate)}
+	readData, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(readData, data) {
+		t.Fatal("Read data is not equal to the data")
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Expected reading to take half a second, took %s", elapsed)
+	}
+
+	start = time.Now()
+	var buffer bytes.Buffer
+	writer := &throttledWriter{writer: &buffer, bucket: newTokenBucket(rate)}
+	n, err := writer.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) || !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatal("Written data is not equal to the data")
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Expected writing to take half a second, took %s", elapsed)
+	}
+}
+
+// Tests throttling only the buckets with bandwidth limits.
+func TestBucketBandwidthThrottle(t *testing.T) {
+	bandwidth := &bucketBandwidth{
+		rwMutex:  globalBucketBandwidth.rwMutex,
+		limiters: make(map[string]*bandwidthLimiters),
+		users:    make(map[string]*bandwidthLimiters),
+	}
+	bandwidth.Set("uploads", &BandwidthConfiguration{UploadLimit: 1024})
+	bandwidth.Set("unlimited", &BandwidthConfiguration{})
+
+	var buffer bytes.Buffer
+	if _, ok := bandwidth.ThrottleReader("uploads", "", &buffer).(*throttledReader); !ok {
+		t.Error("Expected the uploads into the bucket to be throttled")
+	}
+	if _, ok := bandwidth.ThrottleWriter("uploads", "", &buffer).(*throttledWriter); ok {
+		t.Error("Expected the downloads from the bucket not to be throttled")
+	}
+	if _, ok := bandwidth.ThrottleReader("unlimited", "", &buffer).(*throttledReader); ok {
+		t.Error("Expected the uploads into the unlimited bucket not to be throttled")
+	}
+	if config := bandwidth.Get("uploads"); config.UploadLimit != 1024 {
+		t.Errorf("Expected an upload limit of 1024, got %d", config.UploadLimit)
+	}
+
+	bandwidth.Set("uploads", nil)
+	if _, ok := bandwidth.ThrottleReader("uploads", "", &buffer).(*throttledReader); ok {
+		t.Error("Expected the uploads into the bucket not to be throttled once removed")
+	}
+}
+
+// Tests throttling the transfers of the IAM users with bandwidth limits
+// in any bucket.
+func TestUserBandwidthThrottle(t *testing.T) {
+	resetGlobalIAMSys()
+	defer resetGlobalIAMSys()
+	globalIAMSys.config.Users["limited"] = iamUser{
+		SecretKey: "secretkey",
+		Status:    iamUserEnabled,
+		Bandwidth: &BandwidthConfiguration{DownloadLimit: 1024},
+	}
+	globalIAMSys.config.Users["unlimited"] = iamUser{SecretKey: "secretkey", Status: iamUserEnabled}
+
+	bandwidth := &bucketBandwidth{
+		rwMutex:  globalBucketBandwidth.rwMutex,
+		limiters: make(map[string]*bandwidthLimiters),
+		users:    make(map[string]*bandwidthLimiters),
+	}
+	bandwidth.Set("uploads", &BandwidthConfiguration{UploadLimit: 1024})
+
+	var buffer bytes.Buffer
+	writer, ok := bandwidth.ThrottleWriter("bucket", "limited", &buffer).(*throttledWriter)
+	if !ok {
+		t.Fatal("Expected the downloads of the user to be throttled")
+	}
+	if writer.bucket != bandwidth.users["limited"].download {
+		t.Error("Expected the downloads of the user to share its limiter")
+	}
+	if _, ok = bandwidth.ThrottleWriter("bucket", "unlimited", &buffer).(*throttledWriter); ok {
+		t.Error("Expected the downloads of the unlimited user not to be throttled")
+	}
+
+	// Both the limits of the bucket and of the user apply.
+	reader, ok := bandwidth.ThrottleReader("uploads", "limited", &buffer).(*throttledReader)
+	if !ok || reader.bucket != bandwidth.limiters["uploads"].upload {
+		t.Fatal("Expected the uploads into the bucket to be throttled")
+	}
+	globalIAMSys.config.Users["limited"] = iamUser{
+		SecretKey: "secretkey",
+		Status:    iamUserEnabled,
+		Bandwidth: &BandwidthConfiguration{UploadLimit: 1024},
+	}
+	reader, ok = bandwidth.ThrottleReader("uploads", "limited", &buffer).(*throttledReader)
+	if !ok {
+		t.Fatal("Expected the uploads into the bucket to be throttled")
+	}
+	if inner, ok := reader.reader.(*throttledReader); !ok || inner.bucket != bandwidth.users["limited"].upload {
+		t.Error("Expected the uploads of the user to be throttled once its limits changed")
+	}
+	if bandwidth.users["limited"].download != nil {
+		t.Error("Expected the limiters of the user to be replaced once its limits changed")
+	}
+}
@@ -0,0 +1,253 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Environment variable with the domain of the bucket records,
+	// bucket DNS records are published when set.
+	domainEnv = "MINIO_DOMAIN"
+
+	// Environment variable with comma separated list of the etcd
+	// endpoints of CoreDNS.
+	dnsEtcdEndpointsEnv = "MINIO_DNS_ETCD_ENDPOINTS"
+
+	// Environment variable with comma separated list of the IPs the
+	// bucket records point at, the IPs of this server by default.
+	publicIPsEnv = "MINIO_PUBLIC_IPS"
+
+	// Prefix of the records in etcd, the default path of the
+	// CoreDNS etcd plugin.
+	coreDNSPrefix = "/skydns"
+
+	// TTL of the published records in seconds.
+	dnsRecordTTL = 30
+)
+
+// bucketDNS - publishes DNS records of the buckets, `bucket.domain`,
+// pointing at the deployment owning them.
+type bucketDNS interface {
+	// Put - publishes the records of bucket pointing at this deployment.
+	Put(bucket string) error
+	// Get - returns the records of bucket, published by any deployment.
+	Get(bucket string) ([]dnsRecord, error)
+	// Delete - removes the records of bucket.
+	Delete(bucket string) error
+	// Owns - returns true if the records point at this deployment.
+	Owns(records []dnsRecord) bool
+}
+
+// dnsRecord - SkyDNS service record, as read by the CoreDNS etcd plugin.
+type dnsRecord struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	TTL  uint32 `json:"ttl"`
+}
+
+// coreDNS - publishes bucket records in the etcd backend of CoreDNS,
+// one record for each of the public IPs of the deployment.
+type coreDNS struct {
+	etcd    *etcdClient
+	domain  string
+	records []dnsRecord
+}
+
+// newCoreDNS - returns a publisher of records for ips and port.
+func newCoreDNS(etcd *etcdClient, domain string, ips []string, port int) *coreDNS {
+	c := &coreDNS{etcd: etcd, domain: strings.Trim(domain, ".")}
+	for _, ip := range ips {
+		c.records = append(c.records, dnsRecord{Host: ip, Port: port, TTL: dnsRecordTTL})
+	}
+	return c
+}
+
+// bucketKey - returns the key prefix of the records of bucket, for
+// example "/skydns/com/example/bucket/" for bucket.example.com.
+func (c *coreDNS) bucketKey(bucket string) string {
+	labels := strings.Split(bucket+"."+c.domain, ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return coreDNSPrefix + "/" + strings.Join(labels, "/") + "/"
+}
+
+// recordKey - returns the key of the record of bucket for host.
+func (c *coreDNS) recordKey(bucket, host string) string {
+	return c.bucketKey(bucket) + strings.NewReplacer(".", "-", ":", "-").Replace(host)
+}
+
+// bucketKeys - returns the key value pairs of the records of bucket,
+// skipping the ones of the buckets named after a subdomain of it.
+func (c *coreDNS) bucketKeys(bucket string) ([]etcdKeyValue, error) {
+	prefix := c.bucketKey(bucket)
+	kvs, err := c.etcd.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var bucketKvs []etcdKeyValue
+	for _, kv := range kvs {
+		if !strings.Contains(strings.TrimPrefix(string(kv.Key), prefix), "/") {
+			bucketKvs = append(bucketKvs, kv)
+		}
+	}
+	return bucketKvs, nil
+}
+
+// Put - publishes the records of bucket.
+func (c *coreDNS) Put(bucket string) error {
+	for _, record := range c.records {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if err = c.etcd.Put(c.recordKey(bucket, record.Host), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get - returns the records of bucket.
+func (c *coreDNS) Get(bucket string) ([]dnsRecord, error) {
+	kvs, err := c.bucketKeys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	var records []dnsRecord
+	for _, kv := range kvs {
+		var record dnsRecord
+		if err = json.Unmarshal(kv.Value, &record); err != nil {
+			return nil, fmt.Errorf("Unable to parse DNS record %s. %s", kv.Key, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Delete - removes the records of bucket.
+func (c *coreDNS) Delete(bucket string) error {
+	kvs, err := c.bucketKeys(bucket)
+	if err != nil {
+		return err
+	}
+	for _, kv := range kvs {
+		if err = c.etcd.Delete(string(kv.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Owns - returns true if one of the records points at this deployment.
+func (c *coreDNS) Owns(records []dnsRecord) bool {
+	for _, record := range records {
+		for _, own := range c.records {
+			if record.Host == own.Host && record.Port == own.Port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getBucketDNS - returns the publisher of bucket records configured
+// by the environment, nil if bucket records are not published.
+func getBucketDNS() (bucketDNS, error) {
+	domain := os.Getenv(domainEnv)
+	if domain == "" {
+		return nil, nil
+	}
+	endpoints := os.Getenv(dnsEtcdEndpointsEnv)
+	if endpoints == "" {
+		return nil, fmt.Errorf("%s is required by %s", dnsEtcdEndpointsEnv, domainEnv)
+	}
+	etcd, err := newEtcdClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	if value := os.Getenv(publicIPsEnv); value != "" {
+		for _, ip := range strings.Split(value, ",") {
+			ip = strings.TrimSpace(ip)
+			if net.ParseIP(ip) == nil {
+				return nil, fmt.Errorf("Invalid %s ‘%s’, should be a comma separated list of IPs", publicIPsEnv, value)
+			}
+			ips = append(ips, ip)
+		}
+	} else {
+		interfaceIPs, err := getInterfaceIPv4s()
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range interfaceIPs {
+			if !ip.IsLoopback() {
+				ips = append(ips, ip.String())
+			}
+		}
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("No public IP found, set %s", publicIPsEnv)
+		}
+	}
+
+	port, err := strconv.Atoi(globalMinioPort)
+	if err != nil {
+		return nil, err
+	}
+	return newCoreDNS(etcd, domain, ips, port), nil
+}
+
+// checkBucketDNS - returns ErrBucketAlreadyExists if bucket is owned by
+// another deployment.
+func checkBucketDNS(bucket string) APIErrorCode {
+	if globalBucketDNS == nil {
+		return ErrNone
+	}
+	records, err := globalBucketDNS.Get(bucket)
+	if err != nil {
+		errorIf(err, "Unable to get DNS records of bucket %s.", bucket)
+		return ErrInternalError
+	}
+	if len(records) > 0 && !globalBucketDNS.Owns(records) {
+		return ErrBucketAlreadyExists
+	}
+	return ErrNone
+}
+
+// publishBucketsDNS - publishes the records of all the buckets, for
+// the existing buckets to be resolved once records are published.
+func publishBucketsDNS(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		if err = globalBucketDNS.Put(bucket.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
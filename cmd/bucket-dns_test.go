@@ -0,0 +1,114 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Tests publishing bucket records in etcd.
+func TestCoreDNS(t *testing.T) {
+	gateway := &fakeEtcdGateway{kvs: make(map[string][]byte)}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	client, err := newEtcdClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dns := newCoreDNS(client, "example.com.", []string{"10.0.0.1", "fe80::1"}, 9000)
+	other := newCoreDNS(client, "example.com", []string{"10.0.0.10"}, 9000)
+
+	if key := dns.bucketKey("my.bucket"); key != "/skydns/com/example/bucket/my/" {
+		t.Fatalf("Unexpected bucket key %s", key)
+	}
+
+	if err = dns.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	// Buckets named after a subdomain of bucket are distinct.
+	if err = other.Put("sub.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gateway.kvs["/skydns/com/example/bucket/fe80--1"]; !ok {
+		t.Fatalf("Expected a record for fe80::1, got %v", gateway.kvs)
+	}
+
+	records, err := dns.Get("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []dnsRecord{
+		{Host: "10.0.0.1", Port: 9000, TTL: dnsRecordTTL},
+		{Host: "fe80::1", Port: 9000, TTL: dnsRecordTTL},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected records %v, got %v", expected, records)
+	}
+	if !dns.Owns(records) || other.Owns(records) {
+		t.Fatal("Expected bucket to be owned by its publisher only")
+	}
+
+	if err = dns.Delete("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if records, err = dns.Get("bucket"); err != nil || len(records) != 0 {
+		t.Fatalf("Expected no records, got %v %v", records, err)
+	}
+	if records, err = other.Get("sub.bucket"); err != nil || len(records) != 1 {
+		t.Fatalf("Expected records of sub.bucket to be kept, got %v %v", records, err)
+	}
+}
+
+// Tests configuring bucket records from the environment.
+func TestGetBucketDNS(t *testing.T) {
+	defer os.Unsetenv(domainEnv)
+	defer os.Unsetenv(dnsEtcdEndpointsEnv)
+	defer os.Unsetenv(publicIPsEnv)
+	defer func(port string) { globalMinioPort = port }(globalMinioPort)
+	globalMinioPort = "9000"
+
+	testCases := []struct {
+		domain     string
+		endpoints  string
+		publicIPs  string
+		enabled    bool
+		shouldPass bool
+	}{
+		{"", "", "", false, true},
+		{"example.com", "", "", false, false},
+		{"example.com", "http://localhost:2379", "10.0.0.1, 10.0.0.2", true, true},
+		{"example.com", "http://localhost:2379", "10.0.0.1,example.com", false, false},
+		{"example.com", "localhost:2379", "10.0.0.1", false, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(domainEnv, testCase.domain)
+		os.Setenv(dnsEtcdEndpointsEnv, testCase.endpoints)
+		os.Setenv(publicIPsEnv, testCase.publicIPs)
+		dns, err := getBucketDNS()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+			continue
+		}
+		if testCase.enabled != (dns != nil) {
+			t.Errorf("Test %d: Expected bucket DNS enabled %v, got %v", i+1, testCase.enabled, dns)
+		}
+	}
+}
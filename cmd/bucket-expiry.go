@@ -0,0 +1,224 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// Bucket expiry config file, stored only for buckets expiring
+	// their objects.
+	bucketExpiryConfig = "expiry.json"
+
+	// Interval between two sweeps for expired objects.
+	bucketExpirySweepInterval = time.Hour
+)
+
+// bucketExpiry - default expiry of the objects of a bucket, as set by
+// the admin API and persisted in bucketExpiryConfig.
+type bucketExpiry struct {
+	// Objects are removed once older than Days, 0 keeps them.
+	Days int `json:"days"`
+}
+
+// readBucketExpiry - reads the expiry config of a bucket, buckets
+// without a persisted config keep their objects.
+func readBucketExpiry(bucket string, objAPI ObjectLayer) (bucketExpiry, error) {
+	expiryPath := pathJoin(bucketConfigPrefix, bucket, bucketExpiryConfig)
+
+	// Acquire a read lock on expiry config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, expiryPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var expiry bucketExpiry
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, expiryPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return expiry, nil
+		}
+		errorIf(err, "Unable to load expiry config for the bucket %s.", bucket)
+		return expiry, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &expiry); err != nil {
+		errorIf(err, "Unable to parse expiry config for the bucket %s.", bucket)
+		return expiry, err
+	}
+	return expiry, nil
+}
+
+// writeBucketExpiry - saves the expiry config of a bucket, a config
+// keeping the objects is removed.
+func writeBucketExpiry(bucket string, expiry bucketExpiry, objAPI ObjectLayer) error {
+	if expiry.Days == 0 {
+		return removeBucketExpiry(bucket, objAPI)
+	}
+
+	buf, err := json.Marshal(expiry)
+	if err != nil {
+		return err
+	}
+	expiryPath := pathJoin(bucketConfigPrefix, bucket, bucketExpiryConfig)
+	// Acquire a write lock on expiry config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, expiryPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, expiryPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set expiry config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketExpiry - removes the persisted expiry config of a
+// bucket, if any.
+func removeBucketExpiry(bucket string, objAPI ObjectLayer) error {
+	expiryPath := pathJoin(bucketConfigPrefix, bucket, bucketExpiryConfig)
+	// Acquire a write lock on expiry config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, expiryPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, expiryPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		errorIf(err, "Unable to remove expiry config for the bucket %s", bucket)
+		return err
+	}
+	return nil
+}
+
+// bucketExpirySweeper - periodically removes the objects older than
+// the expiry of their bucket. The configs are read on every sweep,
+// they are not kept in memory.
+type bucketExpirySweeper struct {
+	objAPI   ObjectLayer
+	interval time.Duration
+
+	// Notifies an event, eventNotify() unless overridden by tests.
+	notify func(eventData)
+
+	doneCh chan struct{}
+}
+
+// newBucketExpirySweeper - initializes a sweeper for objAPI, not started yet.
+func newBucketExpirySweeper(objAPI ObjectLayer, interval time.Duration) *bucketExpirySweeper {
+	return &bucketExpirySweeper{
+		objAPI:   objAPI,
+		interval: interval,
+		notify:   eventNotify,
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start - runs the sweeper in the background until Stop is called.
+func (s *bucketExpirySweeper) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(s.sweep(time.Now().UTC()), "Unable to remove expired objects.")
+			case <-s.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop - stops the sweeper, safe to be called on nil.
+func (s *bucketExpirySweeper) Stop() {
+	if s == nil {
+		return
+	}
+	close(s.doneCh)
+}
+
+// sweep - removes the objects of all the buckets with an expiry,
+// last modified earlier than the expiry of their bucket before now.
+func (s *bucketExpirySweeper) sweep(now time.Time) error {
+	buckets, err := s.objAPI.ListBuckets(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		expiry, err := readBucketExpiry(bucket.Name, s.objAPI)
+		if err != nil {
+			return err
+		}
+		if expiry.Days <= 0 {
+			continue
+		}
+		if err = s.sweepBucket(bucket.Name, now.AddDate(0, 0, -expiry.Days)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepBucket - removes the objects of bucket last modified before
+// olderThan.
+func (s *bucketExpirySweeper) sweepBucket(bucket string, olderThan time.Time) error {
+	marker := ""
+	for {
+		var result ListObjectsInfo
+		err := globalBackgroundOps.Do(func() (lErr error) {
+			result, lErr = s.objAPI.ListObjects(context.Background(), bucket, "", marker, "", maxObjectList)
+			return lErr
+		})
+		if err != nil {
+			if isErrBucketNotFound(err) {
+				// Removed since listed.
+				return nil
+			}
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			if !objInfo.ModTime.Before(olderThan) {
+				continue
+			}
+			err = globalBackgroundOps.Do(func() error {
+				return s.objAPI.DeleteObject(context.Background(), bucket, objInfo.Name)
+			})
+			if err != nil {
+				if isErrObjectNotFound(err) {
+					// Removed since listed.
+					continue
+				}
+				return err
+			}
+			s.notify(eventData{
+				Type:    ObjectRemovedDelete,
+				Bucket:  bucket,
+				ObjInfo: ObjectInfo{Name: objInfo.Name},
+			})
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
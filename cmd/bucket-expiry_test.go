@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// Tests removing the objects older than the expiry of their bucket.
+func TestBucketExpirySweep(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketExpirySweep)
+}
+
+func testBucketExpirySweep(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	for _, bucket := range []string{"scratch", "kept"} {
+		if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		for _, object := range []string{"a", "dir/b"} {
+			_, err := obj.PutObject(context.Background(), bucket, object, int64(len("data")), bytes.NewBufferString("data"), nil, "")
+			if err != nil {
+				t.Fatalf("%s: %v", instanceType, err)
+			}
+		}
+	}
+	if err := writeBucketExpiry("scratch", bucketExpiry{Days: 1}, obj); err != nil {
+		t.Fatalf("%s: Unable to save the bucket expiry: %v", instanceType, err)
+	}
+	if expiry, err := readBucketExpiry("scratch", obj); err != nil || expiry.Days != 1 {
+		t.Fatalf("%s: Expected an expiry of 1 day, got %#v, %v", instanceType, expiry, err)
+	}
+
+	sweeper := newBucketExpirySweeper(obj, time.Hour)
+	var events []eventData
+	sweeper.notify = func(event eventData) {
+		events = append(events, event)
+	}
+
+	countObjects := func(bucket string) int {
+		result, err := obj.ListObjects(context.Background(), bucket, "", "", "", maxObjectList)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return len(result.Objects)
+	}
+
+	// Nothing is old enough yet.
+	if err := sweeper.sweep(time.Now().UTC()); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(events) != 0 || countObjects("scratch") != 2 {
+		t.Fatalf("%s: Expected no object to expire, got %d events", instanceType, len(events))
+	}
+
+	// Only the objects of the bucket with an expiry are removed.
+	if err := sweeper.sweep(time.Now().UTC().Add(25 * time.Hour)); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(events) != 2 || countObjects("scratch") != 0 || countObjects("kept") != 2 {
+		t.Fatalf("%s: Expected the 2 objects of scratch to expire, got %d events", instanceType, len(events))
+	}
+	for _, event := range events {
+		if event.Type != ObjectRemovedDelete || event.Bucket != "scratch" {
+			t.Errorf("%s: Unexpected event %#v", instanceType, event)
+		}
+	}
+
+	// Removing the expiry keeps the objects.
+	if err := writeBucketExpiry("scratch", bucketExpiry{}, obj); err != nil {
+		t.Fatalf("%s: Unable to remove the bucket expiry: %v", instanceType, err)
+	}
+	if expiry, err := readBucketExpiry("scratch", obj); err != nil || expiry.Days != 0 {
+		t.Fatalf("%s: Expected no expiry, got %#v, %v", instanceType, expiry, err)
+	}
+}
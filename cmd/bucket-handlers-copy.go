@@ -0,0 +1,212 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sync"
+
+	mux "github.com/gorilla/mux"
+)
+
+// Maximum number of objects copied in parallel by a multiple objects copy.
+const maxParallelCopies = 16
+
+// copyObjectEntry - copies srcBucket/srcObject to dstBucket/dstObject
+// keeping its metadata, on behalf of a multiple objects copy by
+// accessKey.
+func copyObjectEntry(ctx context.Context, objectAPI ObjectLayer, accessKey, srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, APIErrorCode) {
+	srcPath, dstPath := path.Join(srcBucket, srcObject), path.Join(dstBucket, dstObject)
+	if srcPath == dstPath {
+		return ObjectInfo{}, ErrInvalidCopyDest
+	}
+
+	// Hold write lock on destination and read lock on source, in the
+	// order of their paths so that concurrent copies between the same
+	// objects in opposite directions do not deadlock.
+	objectDWLock := globalNSMutex.NewNSLock(ctx, dstBucket, dstObject)
+	objectSRLock := globalNSMutex.NewNSLock(ctx, srcBucket, srcObject)
+	if dstPath < srcPath {
+		objectDWLock.Lock()
+		objectSRLock.RLock()
+	} else {
+		objectSRLock.RLock()
+		objectDWLock.Lock()
+	}
+	defer objectDWLock.Unlock()
+	defer objectSRLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(ctx, srcBucket, srcObject)
+	if err != nil {
+		return ObjectInfo{}, toAPIErrorCode(err)
+	}
+
+	/// maximum Upload size for object in a single CopyObject operation.
+	if isMaxObjectSize(objInfo.Size) {
+		return ObjectInfo{}, ErrEntityTooLarge
+	}
+	if s3Error := checkUserQuota(accessKey, objInfo.Size); s3Error != ErrNone {
+		return ObjectInfo{}, s3Error
+	}
+
+	// Make sure to remove saved md5sum, object might have been uploaded
+	// as multipart which doesn't have a standard md5sum, we just let
+	// CopyObject calculate a new one.
+	metadata := objInfo.UserDefined
+	delete(metadata, "md5Sum")
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	// Copies are owned by the identity copying them.
+	setObjectOwner(metadata, accessKey)
+
+	objInfo, err = objectAPI.CopyObject(ctx, srcBucket, srcObject, dstBucket, dstObject, metadata)
+	if err != nil {
+		errorIf(err, "Unable to copy object %s to %s.", srcPath, dstPath)
+		return ObjectInfo{}, toAPIErrorCode(err)
+	}
+	return objInfo, ErrNone
+}
+
+// CopyMultipleObjectsHandler - Copy multiple objects
+// -----------
+// Minio extension which copies server side all the objects listed in
+// the request body into the bucket, reporting the result of every
+// copy like a multiple objects delete.
+func (api objectAPIHandlers) CopyMultipleObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	copyXMLBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorIf(err, "Unable to read HTTP body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	// Unmarshal list of objects to be copied.
+	copyObjects := &CopyObjectsRequest{}
+	if err = xml.Unmarshal(copyXMLBytes, copyObjects); err != nil {
+		errorIf(err, "Unable to unmarshal copy objects request XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	// At most as many objects as a multiple objects delete.
+	if len(copyObjects.Objects) > maxObjectList {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	objInfos := make([]ObjectInfo, len(copyObjects.Objects))
+	cErrs := make([]APIErrorCode, len(copyObjects.Objects))
+
+	copyObject := func(i int, obj CopyObjectIdentifier) {
+		srcBucket, srcObject := path2BucketAndObject(obj.Source)
+		if srcBucket == "" || srcObject == "" || obj.ObjectName == "" {
+			cErrs[i] = ErrInvalidCopySource
+			return
+		}
+		if cErrs[i] = checkBucketObjectPath(srcBucket, srcObject); cErrs[i] != ErrNone {
+			return
+		}
+		// Requests should also be allowed to read the source.
+		if cErrs[i] = checkCopySourceAccess(r, srcBucket, srcObject); cErrs[i] != ErrNone {
+			return
+		}
+		// The source bucket may be under maintenance.
+		if cErrs[i] = globalServerModes.checkRequest(srcBucket, false); cErrs[i] != ErrNone {
+			return
+		}
+		objInfos[i], cErrs[i] = copyObjectEntry(r.Context(), objectAPI, getRequestAccessKey(r), srcBucket, srcObject, bucket, obj.ObjectName)
+	}
+
+	// Copy all requested objects, maxParallelCopies at a time.
+	var wg = &sync.WaitGroup{}
+	indexCh := make(chan int)
+	for worker := 0; worker < maxParallelCopies; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				copyObject(i, copyObjects.Objects[i])
+			}
+		}()
+	}
+	for index := range copyObjects.Objects {
+		indexCh <- index
+	}
+	close(indexCh)
+	wg.Wait()
+
+	// Collect copied objects and errors if any.
+	var copiedObjects []CopiedObject
+	var copyErrors []CopyError
+	for index, s3Error := range cErrs {
+		object := copyObjects.Objects[index]
+		if s3Error == ErrNone {
+			copiedObjects = append(copiedObjects, CopiedObject{
+				Key:          object.ObjectName,
+				ETag:         "\"" + objInfos[index].MD5Sum + "\"",
+				LastModified: objInfos[index].ModTime.UTC().Format(timeFormatAMZLong),
+			})
+			continue
+		}
+		// Error during copy should be collected separately.
+		copyErrors = append(copyErrors, CopyError{
+			Code:    errorCodeResponse[s3Error].Code,
+			Message: errorCodeResponse[s3Error].Description,
+			Key:     object.ObjectName,
+		})
+	}
+
+	// Generate response
+	response := generateMultiCopyResponse(copyObjects.Quiet, copiedObjects, copyErrors)
+	encodedSuccessResponse := encodeResponse(response)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+
+	// Notify object created event for copied objects.
+	for index, s3Error := range cErrs {
+		if s3Error != ErrNone {
+			continue
+		}
+		eventNotify(eventData{
+			Type:    ObjectCreatedCopy,
+			Bucket:  bucket,
+			ObjInfo: objInfos[index],
+			ReqParams: map[string]string{
+				"sourceIPAddress": r.RemoteAddr,
+			},
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling CopyMultipleObjects HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPICopyMultipleObjectsHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyMultipleObjectsHandler, []string{"CopyMultipleObjects"})
+}
+
+func testAPICopyMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	var objectNames []string
+	for i := 0; i < 2*maxParallelCopies; i++ {
+		objectName := fmt.Sprintf("old/object-%d", i)
+		data := fmt.Sprintf("data-%d", i)
+		_, err := obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewBufferString(data), map[string]string{"content-type": "text/plain"}, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+		objectNames = append(objectNames, objectName)
+	}
+
+	// Copy all objects under new/, along with failing entries.
+	copyObjects := CopyObjectsRequest{}
+	for i, objectName := range objectNames {
+		copyObjects.Objects = append(copyObjects.Objects, CopyObjectIdentifier{
+			Source:     "/" + bucketName + "/" + objectName,
+			ObjectName: fmt.Sprintf("new/object-%d", i),
+		})
+	}
+	failing := []CopyObjectIdentifier{
+		{Source: bucketName + "/old/missing", ObjectName: "new/missing"},
+		{Source: bucketName, ObjectName: "new/no-source-key"},
+		{Source: bucketName + "/" + objectNames[0], ObjectName: objectNames[0]},
+	}
+	expectedCodes := []string{"NoSuchKey", "InvalidArgument", "InvalidRequest"}
+	copyObjects.Objects = append(copyObjects.Objects, failing...)
+
+	copyXML, err := xml.Marshal(copyObjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid credentials.
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("POST", getCopyMultipleObjectsURL("", bucketName),
+		int64(len(copyXML)), bytes.NewReader(copyXML), "Invalid-AccessID", credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for CopyMultipleObjects: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("POST", getCopyMultipleObjectsURL("", bucketName),
+		int64(len(copyXML)), bytes.NewReader(copyXML), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for CopyMultipleObjects: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	response := CopyObjectsResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+	}
+
+	// Results are in the order of the request.
+	if len(response.CopiedObjects) != len(objectNames) {
+		t.Fatalf("%s: Expected %d copied objects, got %d", instanceType, len(objectNames), len(response.CopiedObjects))
+	}
+	for i, copied := range response.CopiedObjects {
+		if copied.Key != copyObjects.Objects[i].ObjectName {
+			t.Fatalf("%s: Expected copied object %s, got %s", instanceType, copyObjects.Objects[i].ObjectName, copied.Key)
+		}
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, copied.Key)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if copied.ETag != fmt.Sprintf("%q", objInfo.MD5Sum) {
+			t.Errorf("%s: Expected ETag %q, got %s", instanceType, objInfo.MD5Sum, copied.ETag)
+		}
+		if objInfo.ContentType != "text/plain" {
+			t.Errorf("%s: Expected the metadata of %s to be copied, got content type %s", instanceType, copied.Key, objInfo.ContentType)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(context.Background(), bucketName, copied.Key, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if expected := fmt.Sprintf("data-%d", i); buffer.String() != expected {
+			t.Errorf("%s: Expected %s to contain %q, got %q", instanceType, copied.Key, expected, buffer.String())
+		}
+	}
+
+	if len(response.Errors) != len(failing) {
+		t.Fatalf("%s: Expected %d errors, got %d", instanceType, len(failing), len(response.Errors))
+	}
+	for i, copyErr := range response.Errors {
+		if copyErr.Key != failing[i].ObjectName || copyErr.Code != expectedCodes[i] {
+			t.Errorf("%s: Expected error %s for %s, got %s for %s", instanceType, expectedCodes[i], failing[i].ObjectName, copyErr.Code, copyErr.Key)
+		}
+	}
+
+	// Malformed request body.
+	rec = httptest.NewRecorder()
+	malformedXML := []byte("<CopyObjectsRequest><Object>")
+	req, err = newTestSignedRequestV4("POST", getCopyMultipleObjectsURL("", bucketName),
+		int64(len(malformedXML)), bytes.NewReader(malformedXML), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for CopyMultipleObjects: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+}
@@ -17,12 +17,39 @@
 package cmd
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gorilla/mux"
 )
 
+// listObjects - lists objects, on the peer owning the listing in
+// distributed mode so that deep paginated listings continue the tree
+// walk of the previous page instead of walking again from the marker.
+func listObjects(ctx context.Context, objectAPI ObjectLayer, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	if !globalIsDistXL || len(globalS3Peers) == 0 {
+		return objectAPI.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	}
+	owner := globalS3Peers.GetListingOwner(bucket, prefix, delimiter)
+	if owner.addr == globalMinioAddr {
+		return objectAPI.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	}
+	listObjectsInfo, err := owner.bmsClient.ListObjects(ctx, &ListObjectsPeerArgs{
+		Bucket:    bucket,
+		Prefix:    prefix,
+		Marker:    marker,
+		Delimiter: delimiter,
+		MaxKeys:   maxKeys,
+	})
+	if err != nil {
+		// The owner is unreachable or failed, listing locally
+		// serves the page and returns the object layer errors.
+		return objectAPI.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	}
+	return listObjectsInfo, nil
+}
+
 // Validate all the ListObjects query arguments, returns an APIErrorCode
 // if one of the args do not meet the required conditions.
 // Special conditions required by Minio server are as below
@@ -94,7 +121,7 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 	// Inititate a list objects operation based on the input params.
 	// On success would return back ListObjectsInfo object to be
 	// marshalled into S3 compatible XML header.
-	listObjectsInfo, err := objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+	listObjectsInfo, err := listObjects(r.Context(), objectAPI, bucket, prefix, marker, delimiter, maxKeys)
 	if err != nil {
 		errorIf(err, "Unable to list objects.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -140,7 +167,7 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	// Inititate a list objects operation based on the input params.
 	// On success would return back ListObjectsInfo object to be
 	// marshalled into S3 compatible XML header.
-	listObjectsInfo, err := objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+	listObjectsInfo, err := listObjects(r.Context(), objectAPI, bucket, prefix, marker, delimiter, maxKeys)
 	if err != nil {
 		errorIf(err, "Unable to list objects.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	mux "github.com/gorilla/mux"
+)
+
+// RenamePrefixHandler - POST /bucket?rename
+// -----------
+// Minio extension which renames all the objects under a prefix to a
+// new prefix at once, like a directory rename. The new prefix should
+// not hold any object.
+func (api objectAPIHandlers) RenamePrefixHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	renameXMLBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorIf(err, "Unable to read HTTP body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	renamePrefix := &RenamePrefixRequest{}
+	if err = xml.Unmarshal(renameXMLBytes, renamePrefix); err != nil {
+		errorIf(err, "Unable to unmarshal rename prefix request XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	prefix, newPrefix := renamePrefix.Prefix, renamePrefix.NewPrefix
+	if !isValidRenamePrefix(prefix) || !isValidRenamePrefix(newPrefix) ||
+		strings.HasPrefix(prefix, newPrefix) || strings.HasPrefix(newPrefix, prefix) {
+		writeErrorResponse(w, ErrInvalidRenamePrefix, r.URL)
+		return
+	}
+	if s3Error := checkRenamePrefixAccess(r, bucket, prefix, newPrefix); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Hold write locks on both prefixes, always in the same order.
+	lockPrefixes := []string{prefix, newPrefix}
+	sort.Strings(lockPrefixes)
+	for _, lockPrefix := range lockPrefixes {
+		prefixLock := globalNSMutex.NewNSLock(r.Context(), bucket, lockPrefix)
+		prefixLock.Lock()
+		defer prefixLock.Unlock()
+	}
+
+	if err = objectAPI.RenamePrefix(r.Context(), bucket, prefix, newPrefix); err != nil {
+		errorIf(err, "Unable to rename prefix %s to %s.", prefix, newPrefix)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessNoContent(w)
+}
+
+// checkRenamePrefixAccess - verifies that a request renaming prefix to
+// newPrefix is allowed to read and delete the objects under prefix and
+// to write under newPrefix, by the bucket policy for anonymous requests
+// and by the policies of the IAM user for signed requests.
+func checkRenamePrefixAccess(r *http.Request, bucket, prefix, newPrefix string) APIErrorCode {
+	for _, check := range []struct {
+		action string
+		prefix string
+	}{
+		{"s3:GetObject", prefix},
+		{"s3:DeleteObject", prefix},
+		{"s3:PutObject", newPrefix},
+	} {
+		prefixURL := &url.URL{Path: "/" + pathJoin(bucket, check.prefix) + "*"}
+		if getRequestAuthType(r) == authTypeAnonymous {
+			if s3Error := checkAnonymousAccess(r, bucket, check.action, prefixURL); s3Error != ErrNone {
+				return s3Error
+			}
+			continue
+		}
+		if !isIAMActionAllowed(getRequestAccessKey(r), check.action, getRequestResource(prefixURL), getRequestConditions(r.URL)) {
+			return ErrAccessDenied
+		}
+	}
+	return ErrNone
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling RenamePrefix HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIRenamePrefixHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIRenamePrefixHandler, []string{"RenamePrefix"})
+}
+
+func testAPIRenamePrefixHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objects := map[string]string{
+		"tmp/job-1/part-0":     "hello",
+		"tmp/job-1/dir/part-1": "world",
+		"tmp/job-2/part-0":     "other",
+		"done/job-0/part-0":    "done",
+	}
+	for objectName, data := range objects {
+		_, err := obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewBufferString(data), map[string]string{"content-type": "text/plain"}, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+	}
+
+	renameXML := func(prefix, newPrefix string) []byte {
+		data, err := xml.Marshal(RenamePrefixRequest{Prefix: prefix, NewPrefix: newPrefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	testCases := []struct {
+		body      []byte
+		accessKey string
+		secretKey string
+
+		expectedRespStatus int
+	}{
+		// Test case - 1, invalid credentials.
+		{renameXML("tmp/job-1/", "done/job-1/"), "Invalid-AccessID", credentials.SecretKey, http.StatusForbidden},
+		// Test case - 2, malformed XML.
+		{[]byte("<RenamePrefix><Prefix>"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 3, prefix not ending with a slash.
+		{renameXML("tmp/job-1", "done/job-1/"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 4, nested prefixes.
+		{renameXML("tmp/", "tmp/job-1/"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 5, no objects under prefix.
+		{renameXML("tmp/job-3/", "done/job-3/"), credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Test case - 6, objects exist under the new prefix.
+		{renameXML("tmp/job-1/", "done/"), credentials.AccessKey, credentials.SecretKey, http.StatusConflict},
+		// Test case - 7, valid rename.
+		{renameXML("tmp/job-1/", "done/job-1/"), credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getRenamePrefixURL("", bucketName),
+			int64(len(testCase.body)), bytes.NewReader(testCase.body), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for RenamePrefix: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Renamed objects keep their data and metadata.
+	for objectName, newName := range map[string]string{
+		"tmp/job-1/part-0":     "done/job-1/part-0",
+		"tmp/job-1/dir/part-1": "done/job-1/dir/part-1",
+	} {
+		if _, err := obj.GetObjectInfo(context.Background(), bucketName, objectName); !isErrObjectNotFound(err) {
+			t.Errorf("%s: Expected %s to be renamed, got %v", instanceType, objectName, err)
+		}
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, newName)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if objInfo.ContentType != "text/plain" {
+			t.Errorf("%s: Expected the metadata of %s to be renamed, got content type %s", instanceType, newName, objInfo.ContentType)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(context.Background(), bucketName, newName, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if buffer.String() != objects[objectName] {
+			t.Errorf("%s: Expected %s to contain %q, got %q", instanceType, newName, objects[objectName], buffer.String())
+		}
+	}
+
+	// Objects under other prefixes are left untouched.
+	result, err := obj.ListObjects(context.Background(), bucketName, "tmp/", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "tmp/job-2/part-0" {
+		t.Errorf("%s: Expected only tmp/job-2/part-0 under tmp/, got %v", instanceType, result.Objects)
+	}
+}
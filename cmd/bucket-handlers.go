@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 
@@ -56,17 +58,8 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 		return ErrAccessDenied
 	}
 
-	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
-	resource := bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(reqURL.Path, "/"), "/")
-
-	// Get conditions for policy verification.
-	conditionKeyMap := make(map[string]set.StringSet)
-	for queryParam := range reqURL.Query() {
-		conditionKeyMap[queryParam] = set.CreateStringSet(reqURL.Query().Get(queryParam))
-	}
-
 	// Validate action, resource and conditions with current policy statements.
-	if !bucketPolicyEvalStatements(action, resource, conditionKeyMap, policy.Statements) {
+	if !bucketPolicyEvalStatements(action, getRequestResource(reqURL), getRequestConditions(reqURL), policy.Statements) {
 		return ErrAccessDenied
 	}
 	return ErrNone
@@ -74,6 +67,10 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 
 // Check if the action is allowed on the bucket/prefix.
 func isBucketActionAllowed(action, bucket, prefix string) bool {
+	// Bucket policies are not granting access to anonymous requests.
+	if globalAnonymousAccess == anonymousAccessOff {
+		return false
+	}
 	policy := globalBucketPolicies.GetBucketPolicy(bucket)
 	if policy == nil {
 		return false
@@ -105,16 +102,22 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
 		errorIf(err, "Unable to fetch bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Get bucket region.
+	region, err := readBucketLocation(bucket, objectAPI)
+	if err != nil {
+		errorIf(err, "Unable to fetch bucket location.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	// Generate response.
 	encodedSuccessResponse := encodeResponse(LocationResponse{})
-	// Get current region.
-	region := serverConfig.GetRegion()
 	if region != globalMinioDefaultRegion {
 		encodedSuccessResponse = encodeResponse(LocationResponse{
 			Location: region,
@@ -153,6 +156,10 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		writeErrorResponse(w, ErrInvalidMaxUploads, r.URL)
 		return
 	}
+	// S3 lists at most maxUploadsList uploads per response.
+	if maxUploads > maxUploadsList {
+		maxUploads = maxUploadsList
+	}
 	if keyMarker != "" {
 		// Marker not common with prefix is not implemented.
 		if !strings.HasPrefix(keyMarker, prefix) {
@@ -161,7 +168,7 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		}
 	}
 
-	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+	listMultipartsInfo, err := objectAPI.ListMultipartUploads(r.Context(), bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
 		errorIf(err, "Unable to list multipart uploads.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -187,31 +194,80 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 	}
 
 	// ListBuckets does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
+	s3Error := checkRequestAuthType(r, "", "s3:ListAllMyBuckets", globalMinioDefaultRegion)
 	if s3Error == ErrInvalidRegion {
 		// Clients like boto3 send listBuckets() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = checkRequestAuthType(r, "", "s3:ListAllMyBuckets", serverConfig.GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
+	prefix, token, maxBuckets := getListBucketsArgs(r.URL.Query())
+	if maxBuckets < 0 || maxBuckets > maxBucketsList {
+		writeErrorResponse(w, ErrInvalidMaxBuckets, r.URL)
+		return
+	}
+
 	// Invoke the list buckets.
-	bucketsInfo, err := objectAPI.ListBuckets()
+	bucketsInfo, err := objectAPI.ListBuckets(r.Context())
 	if err != nil {
 		errorIf(err, "Unable to list buckets.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// IAM users only see the buckets they have access to.
+	if accessKey := getRequestAccessKey(r); accessKey != "" {
+		if _, ok := globalIAMSys.GetCredential(accessKey); ok {
+			bucketsInfo = filterVisibleBuckets(bucketsInfo, accessKey)
+		}
+	}
+	bucketsInfo, nextToken := paginateBuckets(bucketsInfo, prefix, token, maxBuckets)
+
 	// Generate response.
 	response := generateListBucketsResponse(bucketsInfo)
+	response.ContinuationToken = nextToken
+	response.Prefix = prefix
 	encodedSuccessResponse := encodeResponse(response)
 
 	// Write response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
 
+// filterVisibleBuckets - returns the buckets the IAM user of accessKey
+// is allowed to list, or to get or put any object in.
+func filterVisibleBuckets(buckets []BucketInfo, accessKey string) []BucketInfo {
+	var visible []BucketInfo
+	for _, bucket := range buckets {
+		resource := bucketARNPrefix + bucket.Name
+		if isIAMActionAllowed(accessKey, "s3:ListBucket", resource, nil) ||
+			isIAMActionAllowed(accessKey, "s3:GetObject", resource+"/*", nil) ||
+			isIAMActionAllowed(accessKey, "s3:PutObject", resource+"/*", nil) {
+			visible = append(visible, bucket)
+		}
+	}
+	return visible
+}
+
+// paginateBuckets - returns the buckets with prefix sorted by name
+// after the bucket token, at most maxBuckets of them unless 0, along
+// with the token of the next page if more buckets follow.
+func paginateBuckets(buckets []BucketInfo, prefix, token string, maxBuckets int) ([]BucketInfo, string) {
+	sort.Sort(byBucketName(buckets))
+	var page []BucketInfo
+	for _, bucket := range buckets {
+		if bucket.Name <= token || !strings.HasPrefix(bucket.Name, prefix) {
+			continue
+		}
+		if maxBuckets > 0 && len(page) == maxBuckets {
+			return page, page[len(page)-1].Name
+		}
+		page = append(page, bucket)
+	}
+	return page, ""
+}
+
 // DeleteMultipleObjectsHandler - deletes multiple objects.
 func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -268,7 +324,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 		wg.Add(1)
 		go func(i int, obj ObjectIdentifier) {
 			defer wg.Done()
-			dErr := objectAPI.DeleteObject(bucket, obj.ObjectName)
+			dErr := objectAPI.DeleteObject(r.Context(), bucket, obj.ObjectName)
 			if dErr != nil {
 				dErrs[i] = dErr
 			}
@@ -334,7 +390,7 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	// PutBucket does not have any bucket action.
-	if s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(r, "", "s3:CreateBucket", globalMinioDefaultRegion); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -344,29 +400,87 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 
 	// Validate if incoming location constraint is valid, reject
 	// requests which do not follow valid region requirements.
-	if s3Error := isValidLocationConstraint(r); s3Error != ErrNone {
+	location, s3Error := isValidLocationConstraint(r)
+	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	bucketLock := globalNSMutex.NewNSLock(r.Context(), bucket, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
+	// Buckets are counted and created one at a time when limited.
+	accessKey := getRequestAccessKey(r)
+	if globalBucketLimits.isCounted(accessKey) {
+		countLock := globalNSMutex.NewNSLock(r.Context(), minioMetaBucket, bucketCountLockPath)
+		countLock.Lock()
+		defer countLock.Unlock()
+	}
+	if s3Error = checkBucketCreation(r.Context(), bucket, accessKey, objectAPI); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Bucket names are unique across federated deployments.
+	if s3Error = checkBucketDNS(bucket); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Proceed to creating a bucket.
-	err := objectAPI.MakeBucket(bucket)
+	err := objectAPI.MakeBucket(r.Context(), bucket)
 	if err != nil {
 		errorIf(err, "Unable to create a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Buckets of IAM users are counted against their own limit.
+	if isIAMUser(accessKey) {
+		if err = writeBucketOwner(bucket, accessKey, objectAPI); err != nil {
+			undoPutBucket(r.Context(), bucket, false, objectAPI)
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	if globalBucketDNS != nil {
+		if err = globalBucketDNS.Put(bucket); err != nil {
+			errorIf(err, "Unable to publish DNS records of bucket %s.", bucket)
+			// Do not leave a bucket behind unreachable by its name.
+			undoPutBucket(r.Context(), bucket, false, objectAPI)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+	}
+
+	// Buckets in the server region have no persisted location.
+	if !isValidRegion(location, serverConfig.GetRegion()) {
+		if err = writeBucketLocation(bucket, location, objectAPI); err != nil {
+			// Do not leave a bucket behind in the wrong region.
+			undoPutBucket(r.Context(), bucket, globalBucketDNS != nil, objectAPI)
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set("Location", getLocation(r))
 
 	writeSuccessResponseHeadersOnly(w)
 }
 
+// undoPutBucket - removes a bucket which creation failed midway,
+// along with its owner and, if already published, its DNS records.
+func undoPutBucket(ctx context.Context, bucket string, dnsPublished bool, objAPI ObjectLayer) {
+	if dnsPublished {
+		errorIf(globalBucketDNS.Delete(bucket), "Unable to delete DNS records of bucket %s.", bucket)
+	}
+	errorIf(removeBucketOwner(bucket, objAPI), "Unable to remove owner of bucket %s.", bucket)
+	_ = objAPI.DeleteBucket(ctx, bucket)
+}
+
 // PostPolicyBucketHandler - POST policy
 // ----------
 // This implementation of the POST operation handles object creation with a specified
@@ -409,6 +523,25 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		writeErrorResponse(w, apiErr, r.URL)
 		return
 	}
+	accessKey := getPostPolicyAccessKey(formValues)
+	globalAccessKeyUsage.Record(accessKey, r.RemoteAddr)
+	setAccessLogRequester(r.Context(), accessKey)
+	if !isIAMActionAllowed(accessKey, "s3:PutObject", bucketARNPrefix+pathJoin(bucket, object), nil) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+	// The size of form uploads is only known once uploaded.
+	if s3Error := checkUserQuota(accessKey, 0); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Form uploads are never verified, reject them if the bucket
+	// requires a Content-Md5 on all uploads.
+	if s3Error := checkBucketIntegrity(bucket, nil); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	policyBytes, err := base64.StdEncoding.DecodeString(formValues["Policy"])
 	if err != nil {
@@ -448,14 +581,17 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 
 	// Extract metadata to be saved from received Form.
 	metadata := extractMetadataFromForm(formValues)
+	setObjectOwner(metadata, accessKey)
 
 	sha256sum := ""
 
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	objInfo, err := objectAPI.PutObject(bucket, object, -1, fileBody, metadata, sha256sum)
+	// Uploads are limited to the upload bandwidth of the bucket and of the user.
+	fileBody = globalBucketBandwidth.ThrottleReader(bucket, accessKey, fileBody)
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, -1, fileBody, metadata, sha256sum)
 	if err != nil {
 		errorIf(err, "Unable to create object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -528,16 +664,23 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	bucketLock := globalNSMutex.NewNSLock(r.Context(), bucket, "")
 	bucketLock.RLock()
 	defer bucketLock.RUnlock()
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
 		errorIf(err, "Unable to fetch bucket info.")
 		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
 		return
 	}
 
+	region, err := readBucketLocation(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
+		return
+	}
+	setHeadBucketHeaders(w, bucket, region)
+
 	writeSuccessResponseHeadersOnly(w)
 }
 
@@ -550,7 +693,7 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	}
 
 	// DeleteBucket does not have any bucket action.
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(r, "", "s3:DeleteBucket", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -558,12 +701,12 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	bucketLock := globalNSMutex.NewNSLock(r.Context(), bucket, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
 	// Attempt to delete bucket.
-	if err := objectAPI.DeleteBucket(bucket); err != nil {
+	if err := objectAPI.DeleteBucket(r.Context(), bucket); err != nil {
 		errorIf(err, "Unable to delete a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -578,6 +721,43 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	// Delete listener config, if present - ignore any errors.
 	_ = removeListenerConfig(bucket, objectAPI)
 
+	// Delete bucket location, if present - ignore any errors.
+	_ = removeBucketLocation(bucket, objectAPI)
+
+	// Delete bucket owner, if present - ignore any errors.
+	_ = removeBucketOwner(bucket, objectAPI)
+
+	// Delete bucket integrity config, if present - ignore any errors.
+	_ = removeBucketIntegrity(bucket, objectAPI)
+	S3PeersUpdateBucketIntegrity(bucket, nil)
+
+	// Delete bucket bandwidth config, if present - ignore any errors.
+	_ = removeBucketBandwidth(bucket, objectAPI)
+	S3PeersUpdateBucketBandwidth(bucket, nil)
+
+	// Delete bucket logging config, if present - ignore any errors.
+	_ = removeBucketLogging(bucket, objectAPI)
+	S3PeersUpdateBucketLogging(bucket, nil)
+
+	// Delete bucket secure delete config, if present - ignore any errors.
+	_ = removeBucketSecureDelete(bucket, objectAPI)
+	S3PeersUpdateBucketSecureDelete(bucket, nil)
+
+	// Delete bucket transform config, if present - ignore any errors.
+	_ = removeBucketTransform(bucket, objectAPI)
+	S3PeersUpdateBucketTransform(bucket, nil)
+
+	// Delete bucket expiry config, if present - ignore any errors.
+	_ = removeBucketExpiry(bucket, objectAPI)
+
+	// Delete bucket DNS records, if published.
+	if globalBucketDNS != nil {
+		errorIf(globalBucketDNS.Delete(bucket), "Unable to delete DNS records of bucket %s.", bucket)
+	}
+
+	// Delete share links, if present - ignore any errors.
+	_ = removeShareLinks(bucket, objectAPI)
+
 	// Write success response.
 	writeSuccessNoContent(w)
 }
@@ -18,12 +18,18 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
 )
 
 // Wrapper for calling GetBucketPolicy HTTP handler tests for both XL multiple disks and single node setup.
@@ -610,6 +616,237 @@ func testListBucketsHandler(obj ObjectLayer, instanceType, bucketName string, ap
 	ExecObjectLayerAPINilTest(t, "", "", instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling ListBuckets pagination and visibility tests for both XL multiple disks and single node setup.
+func TestListBucketsPagination(t *testing.T) {
+	defer resetGlobalIAMSys()
+	ExecObjectLayerAPITest(t, testListBucketsPagination, []string{"ListBuckets"})
+}
+
+func testListBucketsPagination(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	for _, bucket := range []string{"logs-1", "logs-2", "logs-3", "photos"} {
+		if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	listBuckets := func(query, accessKey, secretKey string, expectedStatus int) ListBucketsResponse {
+		req, err := newTestSignedRequestV4("GET", getListBucketURL("")+query, 0, nil, accessKey, secretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListBucketsHandler: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != expectedStatus {
+			t.Fatalf("%s: %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, query, expectedStatus, rec.Code)
+		}
+		var response ListBucketsResponse
+		if expectedStatus == http.StatusOK {
+			if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%s: %v", instanceType, err)
+			}
+		}
+		return response
+	}
+	names := func(response ListBucketsResponse) (names []string) {
+		for _, bucket := range response.Buckets.Buckets {
+			names = append(names, bucket.Name)
+		}
+		return names
+	}
+
+	// Pages of 2 buckets with the prefix logs-.
+	response := listBuckets("?prefix=logs-&max-buckets=2", credentials.AccessKey, credentials.SecretKey, http.StatusOK)
+	if got := names(response); !reflect.DeepEqual(got, []string{"logs-1", "logs-2"}) || response.ContinuationToken != "logs-2" {
+		t.Fatalf("%s: Unexpected first page %v, token %q", instanceType, got, response.ContinuationToken)
+	}
+	response = listBuckets("?prefix=logs-&max-buckets=2&continuation-token=logs-2", credentials.AccessKey, credentials.SecretKey, http.StatusOK)
+	if got := names(response); !reflect.DeepEqual(got, []string{"logs-3"}) || response.ContinuationToken != "" {
+		t.Fatalf("%s: Unexpected last page %v, token %q", instanceType, got, response.ContinuationToken)
+	}
+
+	// Invalid page sizes.
+	listBuckets("?max-buckets=ten", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest)
+	listBuckets("?max-buckets=10001", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest)
+
+	// IAM users only see the buckets they have access to.
+	accessKey, secretKey := "photographer", "photographersecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	policy := newCannedIAMPolicy("s3:ListAllMyBuckets")
+	policy.Statements = append(policy.Statements, policyStatement{
+		Actions:   set.CreateStringSet("s3:GetObject"),
+		Effect:    "Allow",
+		Resources: set.CreateStringSet(bucketARNPrefix + "photos/*"),
+	})
+	if err := globalIAMSys.SetPolicy(obj, "photos", policy); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "photos"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	response = listBuckets("", accessKey, secretKey, http.StatusOK)
+	if got := names(response); !reflect.DeepEqual(got, []string{"photos"}) {
+		t.Fatalf("%s: Expected the IAM user to see photos only, got %v", instanceType, got)
+	}
+}
+
+// Tests the limits on the creation of buckets.
+func TestPutBucketLimits(t *testing.T) {
+	defer resetGlobalIAMSys()
+	defer resetGlobalBucketLimits()
+	ExecObjectLayerAPITest(t, testPutBucketLimits, []string{"PutBucket", "DeleteBucket"})
+}
+
+func testPutBucketLimits(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	accessKey, secretKey := "tenant", "tenantsecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.SetPolicy(obj, "buckets", newCannedIAMPolicy("s3:CreateBucket", "s3:DeleteBucket")); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "buckets"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	sendRequest := func(method, bucket, accessKey, secretKey string, expectedStatus int) {
+		req, err := newTestSignedRequestV4(method, getMakeBucketURL("", bucket), 0, nil, accessKey, secretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s: <ERROR> %v", instanceType, method, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != expectedStatus {
+			t.Fatalf("%s: %s %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, method, bucket, expectedStatus, rec.Code)
+		}
+	}
+
+	// One bucket per IAM user, three for the deployment including bucketName.
+	globalBucketLimits = bucketLimits{maxBuckets: 3, maxBucketsPerUser: 1}
+	sendRequest("PUT", "tenant-1", accessKey, secretKey, http.StatusOK)
+	sendRequest("PUT", "tenant-2", accessKey, secretKey, http.StatusBadRequest)
+	sendRequest("PUT", "admin-1", credentials.AccessKey, credentials.SecretKey, http.StatusOK)
+	sendRequest("PUT", "admin-2", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest)
+
+	// Deleting a bucket of the user makes room for another one.
+	sendRequest("DELETE", "tenant-1", accessKey, secretKey, http.StatusNoContent)
+	sendRequest("PUT", "tenant-2", accessKey, secretKey, http.StatusOK)
+	if owner, err := readBucketOwner("tenant-2", obj); err != nil || owner != accessKey {
+		t.Fatalf("%s: Expected tenant-2 to be owned by %s, got %q, %v", instanceType, accessKey, owner, err)
+	}
+
+	// Bucket names with periods are not DNS compatible.
+	globalBucketLimits = bucketLimits{strictNames: true}
+	sendRequest("PUT", "tenant.logs", accessKey, secretKey, http.StatusBadRequest)
+	sendRequest("PUT", "tenant-logs", accessKey, secretKey, http.StatusOK)
+}
+
+// failingBucketDNS - bucketDNS failing to publish any record.
+type failingBucketDNS struct{}
+
+func (failingBucketDNS) Put(bucket string) error                { return errors.New("DNS unavailable") }
+func (failingBucketDNS) Get(bucket string) ([]dnsRecord, error) { return nil, nil }
+func (failingBucketDNS) Delete(bucket string) error             { return nil }
+func (failingBucketDNS) Owns(records []dnsRecord) bool          { return true }
+
+// Tests that a bucket which records can not be published is removed
+// along with its owner.
+func TestPutBucketDNSFailure(t *testing.T) {
+	defer resetGlobalIAMSys()
+	defer func() { globalBucketDNS = nil }()
+	ExecObjectLayerAPITest(t, testPutBucketDNSFailure, []string{"PutBucket"})
+}
+
+func testPutBucketDNSFailure(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	accessKey, secretKey := "tenant", "tenantsecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.SetPolicy(obj, "buckets", newCannedIAMPolicy("s3:CreateBucket")); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "buckets"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	globalBucketDNS = failingBucketDNS{}
+	req, err := newTestSignedRequestV4("PUT", getMakeBucketURL("", "tenant-1"), 0, nil, accessKey, secretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for PutBucket: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	globalBucketDNS = nil
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusInternalServerError, rec.Code)
+	}
+
+	if _, err = obj.GetBucketInfo(context.Background(), "tenant-1"); !isErrBucketNotFound(err) {
+		t.Errorf("%s: Expected tenant-1 to be removed, got %v", instanceType, err)
+	}
+	if owner, err := readBucketOwner("tenant-1", obj); err != nil || owner != "" {
+		t.Errorf("%s: Expected the owner of tenant-1 to be removed, got %q, %v", instanceType, owner, err)
+	}
+}
+
+// Tests the region and data usage headers of HEAD bucket responses.
+func TestHeadBucketUsageHeaders(t *testing.T) {
+	defer globalBucketUsage.Set(dataUsageInfo{})
+	ExecObjectLayerAPITest(t, testHeadBucketUsageHeaders, []string{"HeadBucket"})
+}
+
+func testHeadBucketUsageHeaders(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	headBucket := func() http.Header {
+		req, err := newTestSignedRequestV4("HEAD", getHEADBucketURL("", bucketName), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for HeadBucketHandler: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `200`, but instead found `%d`", instanceType, rec.Code)
+		}
+		return rec.Header()
+	}
+
+	// No usage before the bucket is crawled.
+	globalBucketUsage.Set(dataUsageInfo{})
+	header := headBucket()
+	if region := header.Get("X-Amz-Bucket-Region"); region != serverConfig.GetRegion() {
+		t.Errorf("%s: Expected region %s, got %s", instanceType, serverConfig.GetRegion(), region)
+	}
+	if objects := header.Get("X-Minio-Bucket-Objects"); objects != "" {
+		t.Errorf("%s: Expected no object count, got %s", instanceType, objects)
+	}
+
+	lastUpdate := time.Date(2017, 10, 14, 10, 0, 0, 0, time.UTC)
+	globalBucketUsage.Set(dataUsageInfo{
+		LastUpdate: lastUpdate,
+		Buckets: map[string]bucketUsageInfo{
+			bucketName: {dataUsageEntry: dataUsageEntry{Objects: 3, Size: 1024}},
+		},
+	})
+	header = headBucket()
+	if objects := header.Get("X-Minio-Bucket-Objects"); objects != "3" {
+		t.Errorf("%s: Expected 3 objects, got %s", instanceType, objects)
+	}
+	if size := header.Get("X-Minio-Bucket-Size"); size != "1024" {
+		t.Errorf("%s: Expected 1024 bytes, got %s", instanceType, size)
+	}
+	if updated := header.Get("X-Minio-Bucket-Usage-Updated"); updated != lastUpdate.Format(http.TimeFormat) {
+		t.Errorf("%s: Expected usage updated at %s, got %s", instanceType, lastUpdate.Format(http.TimeFormat), updated)
+	}
+}
+
 // Wrapper for calling DeleteMultipleObjects HTTP handler tests for both XL multiple disks and single node setup.
 func TestAPIDeleteMultipleObjectsHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testAPIDeleteMultipleObjectsHandler, []string{"DeleteMultipleObjects"})
@@ -632,7 +869,7 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 	for i := 0; i < 10; i++ {
 		objectName := "test-object-" + strconv.Itoa(i)
 		// uploading the object.
-		_, err = obj.PutObject(bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
+		_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
 			make(map[string]string), sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// Maximum size of an integrity configuration request body.
+const maxIntegrityConfigSize = 4 * 1024
+
+// PutBucketIntegrityHandler - PUT Bucket?integrity
+// ----------
+// Minio extension which requires, or stops requiring, a Content-MD5 on
+// all the uploads into the bucket.
+func (api objectAPIHandlers) PutBucketIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxIntegrityConfigSize))
+	if err != nil {
+		errorIf(err, "Unable to read HTTP body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	config := IntegrityConfiguration{}
+	if err = xml.Unmarshal(configBytes, &config); err != nil {
+		errorIf(err, "Unable to parse integrity configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	if config.RequireContentMD5 {
+		err = writeBucketIntegrity(bucket, config, objAPI)
+	} else {
+		err = removeBucketIntegrity(bucket, objAPI)
+	}
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Notify all peers (including self) to update in-memory state.
+	S3PeersUpdateBucketIntegrity(bucket, &config)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketIntegrityHandler - GET Bucket?integrity
+// ----------
+// Minio extension which returns the integrity configuration of the
+// bucket.
+func (api objectAPIHandlers) GetBucketIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	config, err := readBucketIntegrity(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
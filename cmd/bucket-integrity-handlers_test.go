@@ -0,0 +1,127 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling bucket integrity HTTP handler tests for both XL multiple disks and single node setup.
+func TestBucketIntegrityHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testBucketIntegrityHandlers, []string{"PutBucketIntegrity", "GetBucketIntegrity", "PutObject"})
+}
+
+func testBucketIntegrityHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Integrity changes are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	putIntegrity := func(body []byte, accessKey string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketIntegrityURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutBucketIntegrity: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getIntegrity := func() IntegrityConfiguration {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketIntegrityURL("", bucketName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetBucketIntegrity: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		config := IntegrityConfiguration{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+		}
+		return config
+	}
+	putObject := func(objectName string, withMD5 bool) int {
+		data := []byte("hello, integrity")
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, objectName),
+			int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutObject: <ERROR> %v", err)
+		}
+		// Test requests carry a Content-Md5 by default.
+		if !withMD5 {
+			req.Header.Del("Content-Md5")
+		}
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Failed to sign PutObject request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	requireXML := []byte("<IntegrityConfiguration><RequireContentMD5>true</RequireContentMD5></IntegrityConfiguration>")
+	if code := putIntegrity(requireXML, "Invalid-AccessID"); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := putIntegrity([]byte("<IntegrityConfiguration>"), credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if getIntegrity().RequireContentMD5 {
+		t.Fatalf("%s: Expected no integrity requirements by default", instanceType)
+	}
+
+	// Require a Content-Md5 on all uploads.
+	if code := putIntegrity(requireXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if !getIntegrity().RequireContentMD5 {
+		t.Fatalf("%s: Expected Content-MD5 to be required", instanceType)
+	}
+	if code := putObject("unverified", false); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if code := putObject("verified", true); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+
+	// Remove the requirement.
+	noRequireXML := []byte("<IntegrityConfiguration><RequireContentMD5>false</RequireContentMD5></IntegrityConfiguration>")
+	if code := putIntegrity(noRequireXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if getIntegrity().RequireContentMD5 {
+		t.Fatalf("%s: Expected Content-MD5 not to be required anymore", instanceType)
+	}
+	if code := putObject("unverified", false); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+}
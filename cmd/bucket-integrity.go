@@ -0,0 +1,189 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+const (
+	// Bucket integrity config file, stored only for buckets
+	// requiring verified uploads.
+	bucketIntegrityConfig = "integrity.json"
+)
+
+// errContentMD5Required - returned by the browser handlers for uploads
+// into buckets requiring a Content-MD5.
+var errContentMD5Required = errors.New("This bucket requires a Content-MD5 on all uploads")
+
+// IntegrityConfiguration - integrity requirements on the uploads into
+// a bucket, as set by PutBucketIntegrity and persisted in
+// bucketIntegrityConfig.
+type IntegrityConfiguration struct {
+	XMLName           xml.Name `xml:"IntegrityConfiguration" json:"-"`
+	RequireContentMD5 bool     `xml:"RequireContentMD5" json:"requireContentMD5"`
+}
+
+// bucketIntegrity - in-memory integrity configs of all the buckets
+// requiring verified uploads.
+type bucketIntegrity struct {
+	rwMutex *sync.RWMutex
+	configs map[string]IntegrityConfiguration
+}
+
+// Global integrity configs, loaded at object layer initialization
+// and kept up to date by the peers on every change.
+var globalBucketIntegrity = &bucketIntegrity{
+	rwMutex: &sync.RWMutex{},
+	configs: make(map[string]IntegrityConfiguration),
+}
+
+// Get - returns the integrity config of a bucket.
+func (bi *bucketIntegrity) Get(bucket string) IntegrityConfiguration {
+	bi.rwMutex.RLock()
+	defer bi.rwMutex.RUnlock()
+	return bi.configs[bucket]
+}
+
+// Set - sets the integrity config of a bucket, a nil config removes it.
+func (bi *bucketIntegrity) Set(bucket string, config *IntegrityConfiguration) {
+	bi.rwMutex.Lock()
+	defer bi.rwMutex.Unlock()
+	if config == nil || !config.RequireContentMD5 {
+		delete(bi.configs, bucket)
+		return
+	}
+	bi.configs[bucket] = *config
+}
+
+// Intialize integrity configs of all buckets.
+func initBucketIntegrity(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	configs := make(map[string]IntegrityConfiguration)
+	for _, bucket := range buckets {
+		config, rErr := readBucketIntegrity(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other configs if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if config.RequireContentMD5 {
+			configs[bucket.Name] = config
+		}
+	}
+
+	globalBucketIntegrity.rwMutex.Lock()
+	globalBucketIntegrity.configs = configs
+	globalBucketIntegrity.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// checkBucketIntegrity - returns ErrContentMD5Required for uploads into
+// a bucket requiring a Content-MD5 which the upload headers do not have,
+// nil headers stand for uploads which cannot be verified at all.
+func checkBucketIntegrity(bucket string, header http.Header) APIErrorCode {
+	if !globalBucketIntegrity.Get(bucket).RequireContentMD5 {
+		return ErrNone
+	}
+	if header.Get("Content-Md5") == "" {
+		return ErrContentMD5Required
+	}
+	return ErrNone
+}
+
+// readBucketIntegrity - reads the integrity config of a bucket, buckets
+// without a persisted config have no requirements.
+func readBucketIntegrity(bucket string, objAPI ObjectLayer) (IntegrityConfiguration, error) {
+	integrityPath := pathJoin(bucketConfigPrefix, bucket, bucketIntegrityConfig)
+
+	// Acquire a read lock on integrity config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, integrityPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var config IntegrityConfiguration
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, integrityPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load integrity config for the bucket %s.", bucket)
+		return config, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse integrity config for the bucket %s.", bucket)
+		return config, err
+	}
+	return config, nil
+}
+
+// writeBucketIntegrity - saves the integrity config of a bucket.
+func writeBucketIntegrity(bucket string, config IntegrityConfiguration, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	integrityPath := pathJoin(bucketConfigPrefix, bucket, bucketIntegrityConfig)
+	// Acquire a write lock on integrity config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, integrityPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, integrityPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set integrity config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketIntegrity - removes the persisted integrity config of a
+// bucket, if any.
+func removeBucketIntegrity(bucket string, objAPI ObjectLayer) error {
+	integrityPath := pathJoin(bucketConfigPrefix, bucket, bucketIntegrityConfig)
+	// Acquire a write lock on integrity config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, integrityPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, integrityPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
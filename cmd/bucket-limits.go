@@ -0,0 +1,218 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Environment variable with the maximum number of buckets of the
+	// deployment, unlimited if unset.
+	maxBucketsEnv = "MINIO_MAX_BUCKETS"
+
+	// Environment variable with the maximum number of buckets created
+	// by each IAM user, unlimited if unset.
+	maxBucketsPerUserEnv = "MINIO_MAX_BUCKETS_PER_USER"
+
+	// Environment variable rejecting the bucket names which are not
+	// DNS compatible when set to "on".
+	strictBucketNamesEnv = "MINIO_STRICT_BUCKET_NAMES"
+)
+
+const (
+	// Bucket owner config file, stored only for buckets created by
+	// IAM users.
+	bucketOwnerConfig = "owner.json"
+
+	// Path locked while counting the buckets before creating one, so
+	// that concurrent creations do not exceed the limits.
+	bucketCountLockPath = "buckets.lock"
+)
+
+// bucketLimits - limits on the creation of buckets.
+type bucketLimits struct {
+	// Maximum number of buckets, 0 if unlimited.
+	maxBuckets int
+	// Maximum number of buckets of each IAM user, 0 if unlimited.
+	maxBucketsPerUser int
+	// Whether the bucket names should be DNS compatible.
+	strictNames bool
+}
+
+// Limits on the creation of buckets.
+var globalBucketLimits bucketLimits
+
+// isCounted - returns true if the buckets are counted before creating
+// a bucket for accessKey.
+func (l bucketLimits) isCounted(accessKey string) bool {
+	return l.maxBuckets > 0 || (l.maxBucketsPerUser > 0 && isIAMUser(accessKey))
+}
+
+// getMaxBucketCount - returns the maximum number of buckets configured
+// through env, 0 if unlimited.
+func getMaxBucketCount(env string) (int, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be a positive number", env, value)
+	}
+	return count, nil
+}
+
+// getBucketLimits - returns the limits on the creation of buckets
+// configured through the environment.
+func getBucketLimits() (limits bucketLimits, err error) {
+	if limits.maxBuckets, err = getMaxBucketCount(maxBucketsEnv); err != nil {
+		return bucketLimits{}, err
+	}
+	if limits.maxBucketsPerUser, err = getMaxBucketCount(maxBucketsPerUserEnv); err != nil {
+		return bucketLimits{}, err
+	}
+	limits.strictNames = strings.EqualFold(os.Getenv(strictBucketNamesEnv), "on")
+	return limits, nil
+}
+
+// isIAMUser - returns true if accessKey is the access key of an IAM
+// user rather than the server credential.
+func isIAMUser(accessKey string) bool {
+	if accessKey == "" || accessKey == serverConfig.GetCredential().AccessKey {
+		return false
+	}
+	_, ok := globalIAMSys.GetCredential(accessKey)
+	return ok
+}
+
+// isDNSBucketName - returns true if bucket is a valid bucket name and
+// a single DNS label, usable in virtual-host style requests over TLS.
+func isDNSBucketName(bucket string) bool {
+	return IsValidBucketName(bucket) && !strings.Contains(bucket, ".")
+}
+
+// checkBucketCreation - checks whether accessKey may create bucket
+// under the limits, the buckets being counted with a lock on
+// bucketCountLockPath held.
+func checkBucketCreation(ctx context.Context, bucket, accessKey string, objAPI ObjectLayer) APIErrorCode {
+	if globalBucketLimits.strictNames && !isDNSBucketName(bucket) {
+		return ErrBucketNameNotDNSCompatible
+	}
+	if !globalBucketLimits.isCounted(accessKey) {
+		return ErrNone
+	}
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return toAPIErrorCode(err)
+	}
+	if globalBucketLimits.maxBuckets > 0 && len(buckets) >= globalBucketLimits.maxBuckets {
+		return ErrTooManyBuckets
+	}
+	if globalBucketLimits.maxBucketsPerUser > 0 && isIAMUser(accessKey) {
+		owned := 0
+		for _, b := range buckets {
+			owner, err := readBucketOwner(b.Name, objAPI)
+			if err != nil {
+				return toAPIErrorCode(err)
+			}
+			if owner == accessKey {
+				owned++
+			}
+		}
+		if owned >= globalBucketLimits.maxBucketsPerUser {
+			return ErrTooManyBuckets
+		}
+	}
+	return ErrNone
+}
+
+// bucketOwner - access key of the IAM user who created a bucket as
+// persisted in bucketOwnerConfig.
+type bucketOwner struct {
+	AccessKey string `json:"accessKey"`
+}
+
+// readBucketOwner - reads the access key of the IAM user who created
+// a bucket, "" for the buckets created with the server credential.
+func readBucketOwner(bucket string, objAPI ObjectLayer) (string, error) {
+	ownerPath := pathJoin(bucketConfigPrefix, bucket, bucketOwnerConfig)
+
+	// Acquire a read lock on owner config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ownerPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, ownerPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return "", nil
+		}
+		errorIf(err, "Unable to load owner for the bucket %s.", bucket)
+		return "", errorCause(err)
+	}
+
+	var owner bucketOwner
+	if err = json.Unmarshal(buffer.Bytes(), &owner); err != nil {
+		errorIf(err, "Unable to parse owner for the bucket %s.", bucket)
+		return "", err
+	}
+	return owner.AccessKey, nil
+}
+
+// writeBucketOwner - saves the access key of the IAM user who created
+// a bucket.
+func writeBucketOwner(bucket, accessKey string, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(bucketOwner{AccessKey: accessKey})
+	if err != nil {
+		return err
+	}
+	ownerPath := pathJoin(bucketConfigPrefix, bucket, bucketOwnerConfig)
+	// Acquire a write lock on owner config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ownerPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, ownerPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set owner for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketOwner - removes the persisted owner of a bucket, if any.
+func removeBucketOwner(bucket string, objAPI ObjectLayer) error {
+	ownerPath := pathJoin(bucketConfigPrefix, bucket, bucketOwnerConfig)
+	// Acquire a write lock on owner config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ownerPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, ownerPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+const (
+	// Bucket location config file, stored only for buckets
+	// created in a region other than the server region.
+	bucketLocationConfig = "location.json"
+)
+
+// bucketLocation - region of a bucket as persisted in bucketLocationConfig.
+type bucketLocation struct {
+	Region string `json:"region"`
+}
+
+// isValidBucketRegion - returns true if buckets can be created in region,
+// which is either the server region or one of the configured bucket regions.
+func isValidBucketRegion(region string) bool {
+	if isValidRegion(region, serverConfig.GetRegion()) {
+		return true
+	}
+	for _, bucketRegion := range serverConfig.GetBucketRegions() {
+		if isValidRegion(region, bucketRegion) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBucketLocation - reads the region of a bucket, buckets without
+// a persisted location are in the server region.
+func readBucketLocation(bucket string, objAPI ObjectLayer) (string, error) {
+	locationPath := pathJoin(bucketConfigPrefix, bucket, bucketLocationConfig)
+
+	// Acquire a read lock on location config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, locationPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, locationPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return serverConfig.GetRegion(), nil
+		}
+		errorIf(err, "Unable to load location for the bucket %s.", bucket)
+		return "", errorCause(err)
+	}
+
+	var location bucketLocation
+	if err = json.Unmarshal(buffer.Bytes(), &location); err != nil {
+		errorIf(err, "Unable to parse location for the bucket %s.", bucket)
+		return "", err
+	}
+	return location.Region, nil
+}
+
+// writeBucketLocation - saves the region of a bucket.
+func writeBucketLocation(bucket, region string, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(bucketLocation{Region: region})
+	if err != nil {
+		return err
+	}
+	locationPath := pathJoin(bucketConfigPrefix, bucket, bucketLocationConfig)
+	// Acquire a write lock on location config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, locationPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, locationPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set location for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketLocation - removes the persisted region of a bucket, if any.
+func removeBucketLocation(bucket string, objAPI ObjectLayer) error {
+	locationPath := pathJoin(bucketConfigPrefix, bucket, bucketLocationConfig)
+	// Acquire a write lock on location config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, locationPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, locationPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
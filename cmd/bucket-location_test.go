@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests creating buckets in the configured bucket regions.
+func TestBucketLocation(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	serverConfig.SetBucketRegions([]string{"eu-west-1"})
+	apiRouter := initTestAPIEndPoints(obj, []string{"GetBucketLocation", "PutBucket"})
+	cred := serverConfig.GetCredential()
+
+	testCases := []struct {
+		bucketName       string
+		location         string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{"bucket1", "", http.StatusOK, ""},
+		{"bucket2", "eu-west-1", http.StatusOK, "eu-west-1"},
+		{"bucket3", "eu-central-1", http.StatusBadRequest, ""},
+	}
+	for i, testCase := range testCases {
+		var body []byte
+		if testCase.location != "" {
+			body, err = xml.Marshal(createBucketLocationConfiguration{Location: testCase.location})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getMakeBucketURL("", testCase.bucketName),
+			int64(len(body)), bytes.NewReader(body), cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Fatalf("Test %d: Expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		rec = httptest.NewRecorder()
+		req, err = newTestSignedRequestV4("GET", getBucketLocationURL("", testCase.bucketName), 0, nil, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		var location LocationResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &location); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if location.Location != testCase.expectedLocation {
+			t.Errorf("Test %d: Expected location %s, got %s", i+1, testCase.expectedLocation, location.Location)
+		}
+	}
+
+	// Location is removed along with the bucket.
+	if err = removeBucketLocation("bucket2", obj); err != nil {
+		t.Fatal(err)
+	}
+	if region, err := readBucketLocation("bucket2", obj); err != nil || region != globalMinioDefaultRegion {
+		t.Fatalf("Expected region %s, got %s %v", globalMinioDefaultRegion, region, err)
+	}
+	if err = removeBucketLocation("bucket2", obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests the region signatures are verified with.
+func TestGetSigningRegion(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	serverConfig.SetBucketRegions([]string{"eu-west-1"})
+	testCases := []struct {
+		reqRegion string
+		expected  string
+	}{
+		{globalMinioDefaultRegion, globalMinioDefaultRegion},
+		{"eu-west-1", "eu-west-1"},
+		{"eu-central-1", globalMinioDefaultRegion},
+	}
+	for i, testCase := range testCases {
+		if region := getSigningRegion(testCase.reqRegion, globalMinioDefaultRegion); region != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, region)
+		}
+	}
+}
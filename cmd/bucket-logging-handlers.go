@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// Maximum size of a logging configuration request body.
+const maxLoggingConfigSize = 4 * 1024
+
+// PutBucketLoggingHandler - PUT Bucket?logging
+// ----------
+// This implementation of the PUT operation enables, or disables, the
+// access logging of the bucket into a target bucket.
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxLoggingConfigSize))
+	if err != nil {
+		errorIf(err, "Unable to read HTTP body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	config := BucketLoggingStatus{}
+	if err = xml.Unmarshal(configBytes, &config); err != nil {
+		errorIf(err, "Unable to parse logging configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	if config.LoggingEnabled == nil {
+		err = removeBucketLogging(bucket, objAPI)
+	} else {
+		// The target bucket must exist.
+		if _, err = objAPI.GetBucketInfo(r.Context(), config.LoggingEnabled.TargetBucket); err != nil {
+			writeErrorResponse(w, ErrInvalidTargetBucketForLogging, r.URL)
+			return
+		}
+		err = writeBucketLogging(bucket, config, objAPI)
+	}
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Notify all peers (including self) to update in-memory state.
+	S3PeersUpdateBucketLogging(bucket, &config)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLoggingHandler - GET Bucket?logging
+// ----------
+// This implementation of the GET operation returns the access logging
+// status of the bucket.
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	config, err := readBucketLogging(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
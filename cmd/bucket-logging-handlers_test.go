@@ -0,0 +1,169 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling bucket logging HTTP handler tests for both XL multiple disks and single node setup.
+func TestBucketLoggingHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testBucketLoggingHandlers, []string{"PutBucketLogging", "GetBucketLogging", "PutObject", "GetObject"})
+}
+
+func testBucketLoggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Logging changes are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	targetBucket := getRandomBucketName()
+	if err := obj.MakeBucket(context.Background(), targetBucket); err != nil {
+		t.Fatalf("%s: Unable to create the target bucket: <ERROR> %v", instanceType, err)
+	}
+
+	putLogging := func(body []byte, accessKey string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketLoggingURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutBucketLogging: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getLogging := func() BucketLoggingStatus {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketLoggingURL("", bucketName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetBucketLogging: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		config := BucketLoggingStatus{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+		}
+		return config
+	}
+
+	loggingXML := []byte(`<BucketLoggingStatus xmlns="http://doc.s3.amazonaws.com/2006-03-01"><LoggingEnabled><TargetBucket>` +
+		targetBucket + `</TargetBucket><TargetPrefix>logs/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`)
+	if code := putLogging(loggingXML, "Invalid-AccessID"); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := putLogging([]byte("<BucketLoggingStatus>"), credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	missingXML := []byte("<BucketLoggingStatus><LoggingEnabled><TargetBucket>missing-bucket</TargetBucket></LoggingEnabled></BucketLoggingStatus>")
+	if code := putLogging(missingXML, credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if getLogging().LoggingEnabled != nil {
+		t.Fatalf("%s: Expected access logging to be disabled by default", instanceType)
+	}
+
+	// Enable access logging.
+	if code := putLogging(loggingXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	expected := LoggingEnabled{TargetBucket: targetBucket, TargetPrefix: "logs/"}
+	if config := getLogging(); config.LoggingEnabled == nil || *config.LoggingEnabled != expected {
+		t.Fatalf("%s: Expected the logging config %#v, got %#v", instanceType, expected, config.LoggingEnabled)
+	}
+	if target, ok := globalBucketLogging.Get(bucketName); !ok || target != expected {
+		t.Fatalf("%s: Expected the logging target %#v, got %#v", instanceType, expected, target)
+	}
+
+	// Served requests are delivered to the target bucket.
+	savedLogger := globalAccessLogger
+	defer func() { globalAccessLogger = savedLogger }()
+	globalAccessLogger = newAccessLogger(10*time.Millisecond, deliverAccessLog)
+
+	data := []byte("hello, logging")
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for PutObject: <ERROR> %v", err)
+	}
+	serveWithAccessLog(apiRouter, rec, req, "PutObject")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, "missing"),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for GetObject: <ERROR> %v", err)
+	}
+	serveWithAccessLog(apiRouter, rec, req, "GetObject")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	var logs string
+	for i := 0; logs == "" && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		result, lErr := obj.ListObjects(context.Background(), targetBucket, "logs/", "", "", 1000)
+		if lErr != nil {
+			t.Fatalf("%s: %v", instanceType, lErr)
+		}
+		for _, objInfo := range result.Objects {
+			var buffer bytes.Buffer
+			if err = obj.GetObject(context.Background(), targetBucket, objInfo.Name, 0, objInfo.Size, &buffer); err != nil {
+				t.Fatalf("%s: %v", instanceType, err)
+			}
+			logs += buffer.String()
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(logs, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%s: Expected 2 access log records, got %q", instanceType, logs)
+	}
+	if !strings.Contains(lines[0], " REST.PUT.OBJECT object ") || !strings.Contains(lines[0], " 200 - ") {
+		t.Errorf("%s: Unexpected access log record of the upload %q", instanceType, lines[0])
+	}
+	if !strings.Contains(lines[1], " REST.GET.OBJECT missing ") || !strings.Contains(lines[1], " 404 NoSuchKey ") {
+		t.Errorf("%s: Unexpected access log record of the download %q", instanceType, lines[1])
+	}
+
+	// Disable access logging.
+	if code := putLogging([]byte("<BucketLoggingStatus></BucketLoggingStatus>"), credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if _, ok := globalBucketLogging.Get(bucketName); ok || getLogging().LoggingEnabled != nil {
+		t.Fatalf("%s: Expected access logging to be disabled", instanceType)
+	}
+}
@@ -0,0 +1,582 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket logging config file, stored only for buckets with
+	// access logging enabled.
+	bucketLoggingConfig = "logging.json"
+
+	// Access log records are delivered to the target bucket at least
+	// this often.
+	accessLogFlushInterval = 5 * time.Minute
+
+	// Access log records of a target are delivered right away once
+	// they reach this size.
+	maxAccessLogSize = 5 * 1024 * 1024
+
+	// Layout of the time of the access log records.
+	accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+	// Layout of the time in the names of the access log objects.
+	accessLogObjectTimeFormat = "2006-01-02-15-04-05"
+)
+
+// LoggingEnabled - target bucket and prefix of the access log objects
+// of a bucket.
+type LoggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket" json:"targetBucket"`
+	TargetPrefix string `xml:"TargetPrefix" json:"targetPrefix"`
+}
+
+// BucketLoggingStatus - access logging config of a bucket, as set by
+// PutBucketLogging and persisted in bucketLoggingConfig. Access logging
+// is disabled without LoggingEnabled.
+type BucketLoggingStatus struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus" json:"-"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty" json:"loggingEnabled,omitempty"`
+}
+
+// bucketLogging - in-memory access logging targets of all the buckets
+// with access logging enabled.
+type bucketLogging struct {
+	rwMutex *sync.RWMutex
+	targets map[string]LoggingEnabled
+}
+
+// Global access logging targets, loaded at object layer initialization
+// and kept up to date by the peers on every change.
+var globalBucketLogging = &bucketLogging{
+	rwMutex: &sync.RWMutex{},
+	targets: make(map[string]LoggingEnabled),
+}
+
+// Get - returns the access logging target of a bucket, false if access
+// logging is disabled.
+func (bl *bucketLogging) Get(bucket string) (LoggingEnabled, bool) {
+	bl.rwMutex.RLock()
+	defer bl.rwMutex.RUnlock()
+	target, ok := bl.targets[bucket]
+	return target, ok
+}
+
+// Set - sets the access logging config of a bucket, a nil config
+// disables access logging.
+func (bl *bucketLogging) Set(bucket string, config *BucketLoggingStatus) {
+	bl.rwMutex.Lock()
+	defer bl.rwMutex.Unlock()
+	if config == nil || config.LoggingEnabled == nil {
+		delete(bl.targets, bucket)
+		return
+	}
+	bl.targets[bucket] = *config.LoggingEnabled
+}
+
+// Intialize access logging targets of all buckets.
+func initBucketLogging(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	targets := make(map[string]LoggingEnabled)
+	for _, bucket := range buckets {
+		config, rErr := readBucketLogging(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other configs if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if config.LoggingEnabled != nil {
+			targets[bucket.Name] = *config.LoggingEnabled
+		}
+	}
+
+	globalBucketLogging.rwMutex.Lock()
+	globalBucketLogging.targets = targets
+	globalBucketLogging.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// readBucketLogging - reads the access logging config of a bucket,
+// access logging is disabled for buckets without a persisted config.
+func readBucketLogging(bucket string, objAPI ObjectLayer) (BucketLoggingStatus, error) {
+	loggingPath := pathJoin(bucketConfigPrefix, bucket, bucketLoggingConfig)
+
+	// Acquire a read lock on logging config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, loggingPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var config BucketLoggingStatus
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, loggingPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load logging config for the bucket %s.", bucket)
+		return config, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse logging config for the bucket %s.", bucket)
+		return config, err
+	}
+	return config, nil
+}
+
+// writeBucketLogging - saves the access logging config of a bucket.
+func writeBucketLogging(bucket string, config BucketLoggingStatus, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	loggingPath := pathJoin(bucketConfigPrefix, bucket, bucketLoggingConfig)
+	// Acquire a write lock on logging config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, loggingPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, loggingPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set logging config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketLogging - removes the persisted access logging config of
+// a bucket, if any.
+func removeBucketLogging(bucket string, objAPI ObjectLayer) error {
+	loggingPath := pathJoin(bucketConfigPrefix, bucket, bucketLoggingConfig)
+	// Acquire a write lock on logging config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, loggingPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, loggingPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// accessLogRecord - an access log record in the S3 server access log
+// format, see
+// http://docs.aws.amazon.com/AmazonS3/latest/dev/LogFormat.html
+type accessLogRecord struct {
+	BucketOwner      string
+	Bucket           string
+	Time             time.Time
+	RemoteIP         string
+	Requester        string
+	RequestID        string
+	Operation        string
+	Key              string
+	RequestURI       string
+	HTTPStatus       int
+	ErrorCode        string
+	BytesSent        int64
+	ObjectSize       int64
+	TotalTime        time.Duration
+	TurnAroundTime   time.Duration
+	Referrer         string
+	UserAgent        string
+	VersionID        string
+	HostID           string
+	SignatureVersion string
+	CipherSuite      string
+	AuthType         string
+	HostHeader       string
+	TLSVersion       string
+}
+
+// accessLogField - returns an access log field, a dash if empty.
+func accessLogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// accessLogSize - returns an access log size field, a dash if none.
+func accessLogSize(size int64) string {
+	if size <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// String - returns the record as an access log line, without the
+// trailing new line.
+func (rec accessLogRecord) String() string {
+	return strings.Join([]string{
+		accessLogField(rec.BucketOwner),
+		accessLogField(rec.Bucket),
+		"[" + rec.Time.Format(accessLogTimeFormat) + "]",
+		accessLogField(rec.RemoteIP),
+		accessLogField(rec.Requester),
+		accessLogField(rec.RequestID),
+		accessLogField(rec.Operation),
+		accessLogField(rec.Key),
+		strconv.Quote(rec.RequestURI),
+		strconv.Itoa(rec.HTTPStatus),
+		accessLogField(rec.ErrorCode),
+		accessLogSize(rec.BytesSent),
+		accessLogSize(rec.ObjectSize),
+		strconv.FormatInt(int64(rec.TotalTime/time.Millisecond), 10),
+		strconv.FormatInt(int64(rec.TurnAroundTime/time.Millisecond), 10),
+		strconv.Quote(accessLogField(rec.Referrer)),
+		strconv.Quote(accessLogField(rec.UserAgent)),
+		accessLogField(rec.VersionID),
+		accessLogField(rec.HostID),
+		accessLogField(rec.SignatureVersion),
+		accessLogField(rec.CipherSuite),
+		accessLogField(rec.AuthType),
+		accessLogField(rec.HostHeader),
+		accessLogField(rec.TLSVersion),
+	}, " ")
+}
+
+// S3 operations of the APIs, named as in the access logs, the others
+// are named after their method and API.
+var accessLogOperations = map[string]string{
+	"HeadObject":              "REST.HEAD.OBJECT",
+	"GetObject":               "REST.GET.OBJECT",
+	"PutObject":               "REST.PUT.OBJECT",
+	"CopyObject":              "REST.COPY.OBJECT",
+	"DeleteObject":            "REST.DELETE.OBJECT",
+	"PutObjectPart":           "REST.PUT.PART",
+	"ListObjectParts":         "REST.GET.UPLOAD",
+	"NewMultipartUpload":      "REST.POST.UPLOADS",
+	"CompleteMultipartUpload": "REST.POST.UPLOAD",
+	"AbortMultipartUpload":    "REST.DELETE.UPLOAD",
+	"ListMultipartUploads":    "REST.GET.UPLOADS",
+	"ListObjectsV1":           "REST.GET.BUCKET",
+	"ListObjectsV2":           "REST.GET.BUCKET",
+	"HeadBucket":              "REST.HEAD.BUCKET",
+	"PutBucket":               "REST.PUT.BUCKET",
+	"DeleteBucket":            "REST.DELETE.BUCKET",
+	"GetBucketLocation":       "REST.GET.LOCATION",
+	"GetBucketPolicy":         "REST.GET.BUCKETPOLICY",
+	"PutBucketPolicy":         "REST.PUT.BUCKETPOLICY",
+	"DeleteBucketPolicy":      "REST.DELETE.BUCKETPOLICY",
+	"GetBucketNotification":   "REST.GET.NOTIFICATION",
+	"PutBucketNotification":   "REST.PUT.NOTIFICATION",
+	"GetBucketLogging":        "REST.GET.LOGGING_STATUS",
+	"PutBucketLogging":        "REST.PUT.LOGGING_STATUS",
+	"DeleteMultipleObjects":   "REST.POST.MULTI_OBJECT_DELETE",
+	"PostPolicyBucket":        "REST.POST.OBJECT",
+}
+
+// accessLogOperation - returns the S3 operation of a request served by
+// api, as named in the access logs.
+func accessLogOperation(method, api string) string {
+	if operation, ok := accessLogOperations[api]; ok {
+		return operation
+	}
+	return "REST." + method + "." + strings.ToUpper(api)
+}
+
+// Names of the TLS versions in the access logs.
+var accessLogTLSVersions = map[uint16]string{
+	tls.VersionTLS10: "TLSv1",
+	tls.VersionTLS11: "TLSv1.1",
+	tls.VersionTLS12: "TLSv1.2",
+}
+
+// Matches the code of an S3 error response.
+var accessLogErrorCode = regexp.MustCompile("<Code>([^<]+)</Code>")
+
+// Largest part of an error response looked up for its code.
+const maxAccessLogErrorSize = 1024
+
+// accessLogResponseWriter - records the status, the size, the time to
+// first byte and the error code of the response of a request.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	start     time.Time
+	status    int
+	sent      int64
+	firstByte time.Duration
+	errorBody bytes.Buffer
+}
+
+func (w *accessLogResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		w.firstByte = time.Since(w.start)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+		w.firstByte = time.Since(w.start)
+	}
+	if w.status >= http.StatusMultipleChoices && w.errorBody.Len() < maxAccessLogErrorSize {
+		w.errorBody.Write(p)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.sent += int64(n)
+	return n, err
+}
+
+// Flush - flushes the underlying response writer.
+func (w *accessLogResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// errorCode - returns the S3 error code of the response, if any.
+func (w *accessLogResponseWriter) errorCode() string {
+	if match := accessLogErrorCode.FindSubmatch(w.errorBody.Bytes()); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// Key of the context value in which the handlers record the access key
+// of the requests which are not signed in their headers or query.
+type accessLogRequesterKey struct{}
+
+// withAccessLogRequester - returns ctx in which the access key of the
+// request can be recorded.
+func withAccessLogRequester(ctx context.Context) context.Context {
+	return context.WithValue(ctx, accessLogRequesterKey{}, new(string))
+}
+
+// setAccessLogRequester - records accessKey as the requester of the
+// request of ctx, if it is logged.
+func setAccessLogRequester(ctx context.Context, accessKey string) {
+	if requester, ok := ctx.Value(accessLogRequesterKey{}).(*string); ok {
+		*requester = accessKey
+	}
+}
+
+// getAccessLogRequester - returns the access key of r, the one recorded
+// by its handler for POST policy uploads.
+func getAccessLogRequester(r *http.Request) string {
+	if requester, ok := r.Context().Value(accessLogRequesterKey{}).(*string); ok && *requester != "" {
+		return *requester
+	}
+	return getRequestAccessKey(r)
+}
+
+// newAccessLogRecord - returns the access log record of r served by
+// api, with the response recorded by w.
+func newAccessLogRecord(r *http.Request, api string, w *accessLogResponseWriter, received int64) accessLogRecord {
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	rec := accessLogRecord{
+		BucketOwner:    serverConfig.GetCredential().AccessKey,
+		Bucket:         bucket,
+		Time:           w.start.UTC(),
+		RequestID:      w.Header().Get(responseRequestIDKey),
+		Operation:      accessLogOperation(r.Method, api),
+		RequestURI:     r.Method + " " + r.RequestURI + " " + r.Proto,
+		HTTPStatus:     w.status,
+		ErrorCode:      w.errorCode(),
+		BytesSent:      w.sent,
+		ObjectSize:     -1,
+		TotalTime:      time.Since(w.start),
+		TurnAroundTime: w.firstByte,
+		Referrer:       r.Referer(),
+		UserAgent:      r.UserAgent(),
+		HostHeader:     r.Host,
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		rec.RemoteIP = host
+	} else {
+		rec.RemoteIP = r.RemoteAddr
+	}
+	if object != "" {
+		rec.Key = getURLEncodedName(object)
+		if r.Method == httpPUT {
+			rec.ObjectSize = received
+		} else if size := w.Header().Get("Content-Length"); size != "" {
+			rec.ObjectSize, _ = strconv.ParseInt(size, 10, 64)
+		}
+	}
+
+	requester := getAccessLogRequester(r)
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned, authTypePostPolicy:
+		rec.Requester, rec.SignatureVersion, rec.AuthType = requester, "SigV4", "AuthHeader"
+	case authTypePresigned:
+		rec.Requester, rec.SignatureVersion, rec.AuthType = requester, "SigV4", "QueryString"
+	case authTypeSignedV2:
+		rec.Requester, rec.SignatureVersion, rec.AuthType = requester, "SigV2", "AuthHeader"
+	case authTypePresignedV2:
+		rec.Requester, rec.SignatureVersion, rec.AuthType = requester, "SigV2", "QueryString"
+	}
+	// Failed authentications have no requester.
+	if rec.HTTPStatus == http.StatusForbidden {
+		rec.Requester = ""
+	}
+
+	if r.TLS != nil {
+		rec.TLSVersion = accessLogTLSVersions[r.TLS.Version]
+	}
+	return rec
+}
+
+// accessLogger - buffers the access log records of every target and
+// delivers them as objects into the target buckets, when they are
+// large enough or every interval.
+type accessLogger struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	deliver  func(target LoggingEnabled, data []byte) error
+	buffers  map[LoggingEnabled]*bytes.Buffer
+	running  bool
+}
+
+// newAccessLogger - returns an access logger delivering the records
+// with deliver.
+func newAccessLogger(interval time.Duration, deliver func(target LoggingEnabled, data []byte) error) *accessLogger {
+	return &accessLogger{
+		interval: interval,
+		deliver:  deliver,
+		buffers:  make(map[LoggingEnabled]*bytes.Buffer),
+	}
+}
+
+// log - adds an access log record of target.
+func (l *accessLogger) log(target LoggingEnabled, rec accessLogRecord) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	buffer, ok := l.buffers[target]
+	if !ok {
+		buffer = &bytes.Buffer{}
+		l.buffers[target] = buffer
+	}
+	buffer.WriteString(rec.String())
+	buffer.WriteByte('\n')
+	if buffer.Len() >= maxAccessLogSize {
+		delete(l.buffers, target)
+		go l.write(target, buffer.Bytes())
+	}
+	// The worker runs only while there are records to deliver.
+	if !l.running {
+		l.running = true
+		go l.run()
+	}
+}
+
+// write - delivers the access log records of target, they are dropped
+// if the delivery fails.
+func (l *accessLogger) write(target LoggingEnabled, data []byte) {
+	if err := l.deliver(target, data); err != nil {
+		errorIf(err, "Unable to deliver the access logs to the bucket %s.", target.TargetBucket)
+	}
+}
+
+// run - delivers the buffered records every interval, until there are
+// none left.
+func (l *accessLogger) run() {
+	for {
+		time.Sleep(l.interval)
+
+		l.mutex.Lock()
+		buffers := l.buffers
+		if len(buffers) == 0 {
+			l.running = false
+			l.mutex.Unlock()
+			return
+		}
+		l.buffers = make(map[LoggingEnabled]*bytes.Buffer)
+		l.mutex.Unlock()
+
+		for target, buffer := range buffers {
+			l.write(target, buffer.Bytes())
+		}
+	}
+}
+
+// accessLogObjectName - returns the name of an access log object of
+// target delivered at t, unique among the servers.
+func accessLogObjectName(target LoggingEnabled, t time.Time) string {
+	unique := strings.ToUpper(strings.Replace(mustGetUUID(), "-", "", -1))[:16]
+	return fmt.Sprintf("%s%s-%s", target.TargetPrefix, t.UTC().Format(accessLogObjectTimeFormat), unique)
+}
+
+// deliverAccessLog - saves access log records as a new object of the
+// target bucket.
+func deliverAccessLog(target LoggingEnabled, data []byte) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	object := accessLogObjectName(target, time.Now())
+	metadata := map[string]string{"content-type": "text/plain"}
+	_, err := objAPI.PutObject(context.Background(), target.TargetBucket, object, int64(len(data)), bytes.NewReader(data), metadata, "")
+	return err
+}
+
+// Global access logger of the buckets with access logging enabled.
+var globalAccessLogger = newAccessLogger(accessLogFlushInterval, deliverAccessLog)
+
+// serveWithAccessLog - serves r with h, adding its access log record
+// as served by api if access logging is enabled for its bucket.
+func serveWithAccessLog(h http.Handler, w http.ResponseWriter, r *http.Request, api string) {
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	target, ok := globalBucketLogging.Get(bucket)
+	if !ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+	lw := &accessLogResponseWriter{ResponseWriter: w, start: time.Now()}
+	r = r.WithContext(withAccessLogRequester(r.Context()))
+	body := &metricsRequestBody{ReadCloser: r.Body}
+	if r.Body != nil {
+		r.Body = body
+	}
+	h.ServeHTTP(lw, r)
+	if lw.status == 0 {
+		lw.status = http.StatusOK
+		lw.firstByte = time.Since(lw.start)
+	}
+	globalAccessLogger.log(target, newAccessLogRecord(r, api, lw, body.received))
+}
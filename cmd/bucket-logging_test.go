@@ -0,0 +1,163 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests formatting access log records in the S3 server access log format.
+func TestAccessLogRecordString(t *testing.T) {
+	rec := accessLogRecord{
+		BucketOwner:      "minio",
+		Bucket:           "photos",
+		Time:             time.Date(2017, time.February, 6, 0, 0, 38, 0, time.UTC),
+		RemoteIP:         "192.0.2.3",
+		Requester:        "minio",
+		RequestID:        "3E57427F3EXAMPLE",
+		Operation:        "REST.GET.OBJECT",
+		Key:              "2017/photo.jpg",
+		RequestURI:       "GET /photos/2017/photo.jpg HTTP/1.1",
+		HTTPStatus:       200,
+		BytesSent:        2662992,
+		ObjectSize:       3462992,
+		TotalTime:        70 * time.Millisecond,
+		TurnAroundTime:   10 * time.Millisecond,
+		UserAgent:        "S3Console/0.4",
+		SignatureVersion: "SigV4",
+		AuthType:         "AuthHeader",
+		HostHeader:       "localhost:9000",
+	}
+	expected := `minio photos [06/Feb/2017:00:00:38 +0000] 192.0.2.3 minio 3E57427F3EXAMPLE REST.GET.OBJECT 2017/photo.jpg "GET /photos/2017/photo.jpg HTTP/1.1" 200 - 2662992 3462992 70 10 "-" "S3Console/0.4" - - SigV4 - AuthHeader localhost:9000 -`
+	if line := rec.String(); line != expected {
+		t.Errorf("Expected the access log line\n%s\ngot\n%s", expected, line)
+	}
+}
+
+// Tests logging the access key of every request as its requester.
+func TestAccessLogRecordRequester(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	req, err := newTestSignedRequestV4("GET", "http://localhost:9000/photos/photo.jpg", 0, nil, "myuser", "mysecretkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &accessLogResponseWriter{ResponseWriter: httptest.NewRecorder(), start: time.Now(), status: http.StatusOK}
+	if rec := newAccessLogRecord(req, "GetObject", w, 0); rec.Requester != "myuser" || rec.BucketOwner != serverConfig.GetCredential().AccessKey {
+		t.Errorf("Expected the requester myuser, got %#v", rec)
+	}
+
+	// Access keys of form uploads are recorded by their handler.
+	req, err = newTestRequest("POST", "http://localhost:9000/photos", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	req = req.WithContext(withAccessLogRequester(req.Context()))
+	setAccessLogRequester(req.Context(), "formuser")
+	if rec := newAccessLogRecord(req, "PostPolicyBucket", w, 0); rec.Requester != "formuser" {
+		t.Errorf("Expected the requester formuser, got %#v", rec)
+	}
+
+	// Failed authentications have no requester.
+	w.status = http.StatusForbidden
+	if rec := newAccessLogRecord(req, "PostPolicyBucket", w, 0); rec.Requester != "" {
+		t.Errorf("Expected no requester, got %#v", rec)
+	}
+}
+
+// Tests naming the S3 operations of the APIs.
+func TestAccessLogOperation(t *testing.T) {
+	testCases := []struct {
+		method    string
+		api       string
+		operation string
+	}{
+		{"GET", "GetObject", "REST.GET.OBJECT"},
+		{"PUT", "PutBucketLogging", "REST.PUT.LOGGING_STATUS"},
+		{"POST", "ComposeObject", "REST.POST.COMPOSEOBJECT"},
+	}
+	for i, testCase := range testCases {
+		if operation := accessLogOperation(testCase.method, testCase.api); operation != testCase.operation {
+			t.Errorf("Test %d: Expected operation %s, got %s", i+1, testCase.operation, operation)
+		}
+	}
+}
+
+// Tests delivering the access log records of every target separately.
+func TestAccessLogger(t *testing.T) {
+	var mutex sync.Mutex
+	delivered := make(map[LoggingEnabled]string)
+	logger := newAccessLogger(10*time.Millisecond, func(target LoggingEnabled, data []byte) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		delivered[target] += string(data)
+		return nil
+	})
+
+	photos := LoggingEnabled{TargetBucket: "logs", TargetPrefix: "photos/"}
+	videos := LoggingEnabled{TargetBucket: "logs", TargetPrefix: "videos/"}
+	logger.log(photos, accessLogRecord{Bucket: "photos", HTTPStatus: 200})
+	logger.log(photos, accessLogRecord{Bucket: "photos", HTTPStatus: 404})
+	logger.log(videos, accessLogRecord{Bucket: "videos", HTTPStatus: 200})
+
+	// The worker stops once all the records are delivered.
+	for i := 0; ; i++ {
+		logger.mutex.Lock()
+		running := logger.running
+		logger.mutex.Unlock()
+		if !running {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected the access logs to be delivered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if lines := strings.Split(strings.TrimSuffix(delivered[photos], "\n"), "\n"); len(lines) != 2 ||
+		!strings.Contains(lines[0], " 200 ") || !strings.Contains(lines[1], " 404 ") {
+		t.Errorf("Expected 2 access log records of photos, got %q", delivered[photos])
+	}
+	if lines := strings.Split(strings.TrimSuffix(delivered[videos], "\n"), "\n"); len(lines) != 1 {
+		t.Errorf("Expected 1 access log record of videos, got %q", delivered[videos])
+	}
+}
+
+// Tests naming the access log objects after their target prefix and time.
+func TestAccessLogObjectName(t *testing.T) {
+	target := LoggingEnabled{TargetBucket: "logs", TargetPrefix: "photos/"}
+	delivery := time.Date(2017, time.February, 6, 0, 0, 38, 0, time.UTC)
+	name := accessLogObjectName(target, delivery)
+	if !strings.HasPrefix(name, "photos/2017-02-06-00-00-38-") || len(name) != len("photos/2017-02-06-00-00-38-")+16 {
+		t.Errorf("Unexpected access log object name %s", name)
+	}
+	if other := accessLogObjectName(target, delivery); other == name {
+		t.Errorf("Expected unique access log object names, got %s twice", name)
+	}
+}
@@ -16,7 +16,11 @@
 
 package cmd
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
 
 // BucketMetaState - Interface to update bucket metadata in-memory
 // state.
@@ -30,8 +34,29 @@ type BucketMetaState interface {
 	// Updates bucket policy
 	UpdateBucketPolicy(args *SetBucketPolicyPeerArgs) error
 
+	// Updates bucket integrity config
+	UpdateBucketIntegrity(args *SetBucketIntegrityPeerArgs) error
+
+	// Updates bucket bandwidth config
+	UpdateBucketBandwidth(args *SetBucketBandwidthPeerArgs) error
+
+	// Updates bucket logging config
+	UpdateBucketLogging(args *SetBucketLoggingPeerArgs) error
+
+	// Updates bucket secure delete config
+	UpdateBucketSecureDelete(args *SetBucketSecureDeletePeerArgs) error
+
+	// Updates bucket transform config
+	UpdateBucketTransform(args *SetBucketTransformPeerArgs) error
+
+	// Updates bucket share links
+	UpdateBucketShareLinks(args *SetBucketShareLinksPeerArgs) error
+
 	// Sends event
 	SendEvent(args *EventArgs) error
+
+	// Lists objects, continuing the tree walks of the node
+	ListObjects(ctx context.Context, args *ListObjectsPeerArgs) (ListObjectsInfo, error)
 }
 
 // BucketUpdater - Interface implementer calls one of BucketMetaState's methods.
@@ -88,6 +113,84 @@ func (lc *localBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArgs
 	return globalBucketPolicies.SetBucketPolicy(args.Bucket, pCh)
 }
 
+// localBucketMetaState.UpdateBucketIntegrity - updates in-memory global
+// bucket integrity info.
+func (lc *localBucketMetaState) UpdateBucketIntegrity(args *SetBucketIntegrityPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketIntegrity.Set(args.Bucket, args.Config)
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketBandwidth - updates in-memory global
+// bucket bandwidth limiters.
+func (lc *localBucketMetaState) UpdateBucketBandwidth(args *SetBucketBandwidthPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketBandwidth.Set(args.Bucket, args.Config)
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketLogging - updates in-memory global
+// bucket access logging targets.
+func (lc *localBucketMetaState) UpdateBucketLogging(args *SetBucketLoggingPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketLogging.Set(args.Bucket, args.Config)
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketSecureDelete - updates in-memory
+// global bucket secure delete configs.
+func (lc *localBucketMetaState) UpdateBucketSecureDelete(args *SetBucketSecureDeletePeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketSecureDelete.Set(args.Bucket, args.Config)
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketTransform - updates in-memory
+// global bucket transform configs.
+func (lc *localBucketMetaState) UpdateBucketTransform(args *SetBucketTransformPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketTransform.Set(args.Bucket, args.Config)
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketShareLinks - updates in-memory
+// global bucket share links.
+func (lc *localBucketMetaState) UpdateBucketShareLinks(args *SetBucketShareLinksPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketShareLinks.Set(args.Bucket, args.Links)
+	return nil
+}
+
 // localBucketMetaState.SendEvent - sends event to local event notifier via
 // `globalEventNotifier`
 func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
@@ -100,6 +203,18 @@ func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
 	return globalEventNotifier.SendListenerEvent(args.Arn, args.Event)
 }
 
+// localBucketMetaState.ListObjects - lists objects from the local
+// object layer.
+func (lc *localBucketMetaState) ListObjects(ctx context.Context, args *ListObjectsPeerArgs) (ListObjectsInfo, error) {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return ListObjectsInfo{}, errServerNotInitialized
+	}
+
+	return objAPI.ListObjects(ctx, args.Bucket, args.Prefix, args.Marker, args.Delimiter, args.MaxKeys)
+}
+
 // Type that implements BucketMetaState for remote node.
 type remoteBucketMetaState struct {
 	*AuthRPCClient
@@ -126,9 +241,99 @@ func (rc *remoteBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArg
 	return rc.Call("S3.SetBucketPolicyPeer", args, &reply)
 }
 
+// remoteBucketMetaState.UpdateBucketIntegrity - sends bucket integrity
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketIntegrity(args *SetBucketIntegrityPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketIntegrityPeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketBandwidth - sends bucket bandwidth
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketBandwidth(args *SetBucketBandwidthPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketBandwidthPeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketLogging - sends bucket logging
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketLogging(args *SetBucketLoggingPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketLoggingPeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketSecureDelete - sends bucket secure
+// delete change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketSecureDelete(args *SetBucketSecureDeletePeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketSecureDeletePeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketTransform - sends bucket transform
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketTransform(args *SetBucketTransformPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketTransformPeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketShareLinks - sends bucket share
+// links change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketShareLinks(args *SetBucketShareLinksPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketShareLinksPeer", args, &reply)
+}
+
 // remoteBucketMetaState.SendEvent - sends event for bucket listener to remote
 // peer via RPC call.
 func (rc *remoteBucketMetaState) SendEvent(args *EventArgs) error {
 	reply := AuthRPCReply{}
 	return rc.Call("S3.Event", args, &reply)
 }
+
+// remoteBucketMetaState.ListObjects - lists objects on remote peer via
+// RPC call, abandoned once ctx is done.
+func (rc *remoteBucketMetaState) ListObjects(ctx context.Context, args *ListObjectsPeerArgs) (ListObjectsInfo, error) {
+	reply := ListObjectsPeerReply{}
+	err := rc.CallWithContext(ctx, "S3.ListObjectsPeer", args, &reply)
+	return reply.Info, err
+}
+
+// bucketMetadataLoaders - loaders of the bucket metadata kept in
+// memory, along with the IAM users and policies, in the order they
+// are loaded. Other bucket configs, such as the expiry or the region
+// of the buckets, are read from the backend when needed.
+var bucketMetadataLoaders = []struct {
+	desc string
+	load func(ObjectLayer) error
+}{
+	{"all bucket policies", initBucketPolicies},
+	{"IAM users and policies", initIAMSys},
+	{"all bucket integrity configs", initBucketIntegrity},
+	{"all bucket bandwidth configs", initBucketBandwidth},
+	{"all bucket logging configs", initBucketLogging},
+	{"all bucket secure delete configs", initBucketSecureDelete},
+	{"all bucket transform configs", initBucketTransform},
+	{"all bucket share links", initBucketShareLinks},
+}
+
+// initBucketMetadata - loads the bucket metadata from objAPI and
+// initializes the event notifier, on startup. Standby servers reload
+// it with reload set, in which case the event notifier keeps its
+// targets and connected listeners and only reloads the notification
+// configs of the buckets.
+func initBucketMetadata(objAPI ObjectLayer, reload bool) error {
+	for _, loader := range bucketMetadataLoaders {
+		if err := loader.load(objAPI); err != nil {
+			return fmt.Errorf("Unable to load %s. %s", loader.desc, err)
+		}
+	}
+
+	initNotifier := initEventNotifier
+	if reload {
+		initNotifier = reloadBucketNotifications
+	}
+	if err := initNotifier(objAPI); err != nil {
+		return fmt.Errorf("Unable to initialize event notification. %s", err)
+	}
+	return nil
+}
@@ -94,6 +94,10 @@ const (
 	ObjectCreatedCompleteMultipartUpload
 	// ObjectRemovedDelete is s3:ObjectRemoved:Delete
 	ObjectRemovedDelete
+	// ObjectRemovedMultipartUploadExpired is s3:ObjectRemoved:MultipartUploadExpired
+	ObjectRemovedMultipartUploadExpired
+	// ObjectCreatedCompose is s3:ObjectCreated:Compose
+	ObjectCreatedCompose
 )
 
 // Stringer interface for event name.
@@ -107,8 +111,12 @@ func (eventName EventName) String() string {
 		return "s3:ObjectCreated:Copy"
 	case ObjectCreatedCompleteMultipartUpload:
 		return "s3:ObjectCreated:CompleteMultipartUpload"
+	case ObjectCreatedCompose:
+		return "s3:ObjectCreated:Compose"
 	case ObjectRemovedDelete:
 		return "s3:ObjectRemoved:Delete"
+	case ObjectRemovedMultipartUploadExpired:
+		return "s3:ObjectRemoved:MultipartUploadExpired"
 	default:
 		return "s3:Unknown"
 	}
@@ -148,6 +156,9 @@ const (
 const (
 	// Response element origin endpoint key.
 	responseOriginEndpointKey = "x-minio-origin-endpoint"
+	// Response element event ID key, stable across deliveries of
+	// the same event.
+	responseEventIDKey = "x-minio-event-id"
 )
 
 // Notification event server specific metadata.
@@ -26,9 +26,11 @@ var suppportedEventTypes = map[string]struct{}{
 	"s3:ObjectCreated:Post":                    {},
 	"s3:ObjectCreated:Copy":                    {},
 	"s3:ObjectCreated:CompleteMultipartUpload": {},
+	"s3:ObjectCreated:Compose":                 {},
 	// Object removed event types.
-	"s3:ObjectRemoved:*":      {},
-	"s3:ObjectRemoved:Delete": {},
+	"s3:ObjectRemoved:*":                      {},
+	"s3:ObjectRemoved:Delete":                 {},
+	"s3:ObjectRemoved:MultipartUploadExpired": {},
 }
 
 // checkEvent - checks if an event is supported.
@@ -155,6 +157,15 @@ func isValidQueueID(queueARN string) bool {
 	} else if isWebhookQueue(sqsARN) {
 		webhookN := serverConfig.GetWebhookNotifyByID(sqsARN.AccountID)
 		return webhookN.Enable && webhookN.Endpoint != ""
+	} else if isNSQQueue(sqsARN) {
+		nsqN := serverConfig.GetNSQNotifyByID(sqsARN.AccountID)
+		return nsqN.Enable && nsqN.NSQDAddress != "" && nsqN.Topic != ""
+	} else if isPubSubQueue(sqsARN) {
+		pubsubN := serverConfig.GetPubSubNotifyByID(sqsARN.AccountID)
+		return pubsubN.Enable && pubsubN.Topic != "" && pubsubN.CredentialsFile != ""
+	} else if isEventHubsQueue(sqsARN) {
+		eventHubsN := serverConfig.GetEventHubsNotifyByID(sqsARN.AccountID)
+		return eventHubsN.Enable && eventHubsN.ConnectionString != ""
 	}
 	return false
 }
@@ -246,6 +257,9 @@ func validateNotificationConfig(nConfig notificationConfig) APIErrorCode {
 // - postgresql
 // - kafka
 // - webhook
+// - nsq
+// - pubsub
+// - eventhubs
 func unmarshalSqsARN(queueARN string) (mSqs arnSQS) {
 	mSqs = arnSQS{}
 	if !strings.HasPrefix(queueARN, minioSqs+serverConfig.GetRegion()+":") {
@@ -267,6 +281,12 @@ func unmarshalSqsARN(queueARN string) (mSqs arnSQS) {
 		mSqs.Type = queueTypeKafka
 	case strings.HasSuffix(sqsType, queueTypeWebhook):
 		mSqs.Type = queueTypeWebhook
+	case strings.HasSuffix(sqsType, queueTypeNSQ):
+		mSqs.Type = queueTypeNSQ
+	case strings.HasSuffix(sqsType, queueTypePubSub):
+		mSqs.Type = queueTypePubSub
+	case strings.HasSuffix(sqsType, queueTypeEventHubs):
+		mSqs.Type = queueTypeEventHubs
 	} // Add more queues here.
 	mSqs.AccountID = strings.TrimSuffix(sqsType, ":"+mSqs.Type)
 	return mSqs
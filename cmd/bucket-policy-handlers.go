@@ -32,20 +32,22 @@ import (
 const maxAccessPolicySize = 20 * humanize.KiByte
 
 // Verify if a given action is valid for the url path based on the
-// existing bucket access policy.
+// existing bucket access policy. Actions are allowed by a matching
+// Allow statement unless denied by a matching Deny statement.
 func bucketPolicyEvalStatements(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) bool {
+	allowed := false
 	for _, statement := range statements {
 		if bucketPolicyMatchStatement(action, resource, conditions, statement) {
-			if statement.Effect == "Allow" {
-				return true
+			// An explicit deny overrides any allow, whatever the
+			// order of the statements.
+			if statement.Effect == "Deny" {
+				return false
 			}
-			// Do not uncomment kept here for readability.
-			// else statement.Effect == "Deny"
-			return false
+			allowed = true
 		}
 	}
 	// None match so deny.
-	return false
+	return allowed
 }
 
 // Verify if action, resource and conditions match input policy statement.
@@ -134,7 +136,7 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 	bucket := vars["bucket"]
 
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -196,7 +198,7 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 	bucket := vars["bucket"]
 
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -239,7 +241,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 	bucket := vars["bucket"]
 
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -251,7 +252,7 @@ func testPutBucketPolicyHandler(obj ObjectLayer, instanceType, bucketName string
 	initBucketPolicies(obj)
 
 	bucketName1 := fmt.Sprintf("%s-1", bucketName)
-	if err := obj.MakeBucket(bucketName1); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -954,3 +955,43 @@ func TestBucketPolicyConditionMatch(t *testing.T) {
 		})
 	}
 }
+
+// Tests explicit deny statements overriding allow statements.
+func TestBucketPolicyEvalStatements(t *testing.T) {
+	generateStatement := func(effect, resource string) policyStatement {
+		return policyStatement{
+			Actions:   set.CreateStringSet("s3:GetObject"),
+			Effect:    effect,
+			Principal: map[string]interface{}{"AWS": "*"},
+			Resources: set.CreateStringSet(bucketARNPrefix + resource),
+		}
+	}
+	allowAll := generateStatement("Allow", "minio-bucket/*")
+	denySecret := generateStatement("Deny", "minio-bucket/secret/*")
+
+	testCases := []struct {
+		resource   string
+		statements []policyStatement
+		allowed    bool
+	}{
+		// Test case - 1.
+		// No statements deny.
+		{"minio-bucket/public/1.txt", nil, false},
+		// Test case - 2-3.
+		// Allowed unless denied.
+		{"minio-bucket/public/1.txt", []policyStatement{allowAll, denySecret}, true},
+		{"minio-bucket/secret/1.txt", []policyStatement{allowAll, denySecret}, false},
+		// Test case - 4.
+		// Deny overrides allow whatever the order of the statements.
+		{"minio-bucket/secret/1.txt", []policyStatement{denySecret, allowAll}, false},
+		// Test case - 5.
+		// Deny alone does not allow anything else.
+		{"minio-bucket/public/1.txt", []policyStatement{denySecret}, false},
+	}
+	for i, testCase := range testCases {
+		allowed := bucketPolicyEvalStatements("s3:GetObject", bucketARNPrefix+testCase.resource, nil, testCase.statements)
+		if allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected allowed to be `%v`, but instead found it to be `%v`", i+1, testCase.allowed, allowed)
+		}
+	}
+}
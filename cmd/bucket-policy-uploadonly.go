@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/minio-go/pkg/policy"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Canned policy of drop-box prefixes, where anyone can upload objects
+// but never download, list or remove them, nor list or abort
+// multipart uploads.
+const bucketPolicyUploadOnly policy.BucketPolicy = "uploadonly"
+
+// Actions of upload-only prefixes, covering simple and multipart
+// uploads.
+var uploadOnlyObjectActions = set.CreateStringSet("s3:PutObject")
+
+// isValidBucketAccessPolicy - returns true if p is one of the canned
+// bucket policies.
+func isValidBucketAccessPolicy(p policy.BucketPolicy) bool {
+	return p == bucketPolicyUploadOnly || p.IsValidBucketPolicy()
+}
+
+// newUploadOnlyStatement - returns the statement allowing anyone to
+// upload the objects of bucket under prefix.
+func newUploadOnlyStatement(bucket, prefix string) policy.Statement {
+	return policy.Statement{
+		Actions:   uploadOnlyObjectActions,
+		Effect:    "Allow",
+		Principal: policy.User{AWS: set.CreateStringSet("*")},
+		Resources: set.CreateStringSet(bucketARNPrefix + bucket + "/" + prefix + "*"),
+	}
+}
+
+// isUploadOnlyStatement - returns true if statement is the upload-only
+// statement of bucket under prefix.
+func isUploadOnlyStatement(statement policy.Statement, bucket, prefix string) bool {
+	return statement.Effect == "Allow" &&
+		statement.Principal.AWS.Contains("*") &&
+		len(statement.Conditions) == 0 &&
+		statement.Actions.Equals(uploadOnlyObjectActions) &&
+		statement.Resources.Equals(set.CreateStringSet(bucketARNPrefix+bucket+"/"+prefix+"*"))
+}
+
+// setBucketAccessPolicy - returns statements with the canned policy
+// of bucket under prefix replaced by p.
+func setBucketAccessPolicy(statements []policy.Statement, p policy.BucketPolicy, bucket, prefix string) []policy.Statement {
+	if p != bucketPolicyUploadOnly {
+		return policy.SetPolicy(statements, p, bucket, prefix)
+	}
+	statements = policy.SetPolicy(statements, policy.BucketPolicyNone, bucket, prefix)
+	return append(statements, newUploadOnlyStatement(bucket, prefix))
+}
+
+// getBucketAccessPolicy - returns the canned policy of bucket under
+// prefix in statements.
+func getBucketAccessPolicy(statements []policy.Statement, bucket, prefix string) policy.BucketPolicy {
+	p := policy.GetPolicy(statements, bucket, prefix)
+	if p != policy.BucketPolicyNone {
+		return p
+	}
+	for _, statement := range statements {
+		if isUploadOnlyStatement(statement, bucket, prefix) {
+			return bucketPolicyUploadOnly
+		}
+	}
+	return p
+}
+
+// getBucketAccessPolicies - returns the canned policies of all the
+// prefixes of bucket in statements, keyed by "bucket/prefix*".
+func getBucketAccessPolicies(statements []policy.Statement, bucket string) map[string]policy.BucketPolicy {
+	policies := policy.GetPolicies(statements, bucket)
+	for resource, p := range policies {
+		if p != policy.BucketPolicyNone || !strings.HasSuffix(resource, "*") {
+			continue
+		}
+		prefix := strings.TrimSuffix(strings.TrimPrefix(resource, bucket+"/"), "*")
+		policies[resource] = getBucketAccessPolicy(statements, bucket, prefix)
+	}
+	return policies
+}
@@ -0,0 +1,136 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// Tests setting and getting upload-only policies along with the
+// other canned policies.
+func TestUploadOnlyBucketAccessPolicy(t *testing.T) {
+	var statements []policy.Statement
+	statements = setBucketAccessPolicy(statements, bucketPolicyUploadOnly, "mybucket", "inbox/")
+	statements = setBucketAccessPolicy(statements, policy.BucketPolicyReadOnly, "mybucket", "public/")
+
+	testCases := []struct {
+		prefix string
+		policy policy.BucketPolicy
+	}{
+		{"inbox/", bucketPolicyUploadOnly},
+		{"public/", policy.BucketPolicyReadOnly},
+		{"private/", policy.BucketPolicyNone},
+	}
+	for i, testCase := range testCases {
+		if p := getBucketAccessPolicy(statements, "mybucket", testCase.prefix); p != testCase.policy {
+			t.Errorf("Test %d: Expected policy %s for %s, got %s", i+1, testCase.policy, testCase.prefix, p)
+		}
+	}
+	policies := getBucketAccessPolicies(statements, "mybucket")
+	if policies["mybucket/inbox/*"] != bucketPolicyUploadOnly || policies["mybucket/public/*"] != policy.BucketPolicyReadOnly {
+		t.Errorf("Unexpected policies %v", policies)
+	}
+
+	// Replacing the upload-only policy.
+	replaced := setBucketAccessPolicy(statements, policy.BucketPolicyWriteOnly, "mybucket", "inbox/")
+	if p := getBucketAccessPolicy(replaced, "mybucket", "inbox/"); p != policy.BucketPolicyWriteOnly {
+		t.Errorf("Expected policy %s, got %s", policy.BucketPolicyWriteOnly, p)
+	}
+	removed := setBucketAccessPolicy(statements, policy.BucketPolicyNone, "mybucket", "inbox/")
+	if p := getBucketAccessPolicy(removed, "mybucket", "inbox/"); p != policy.BucketPolicyNone {
+		t.Errorf("Expected policy %s, got %s", policy.BucketPolicyNone, p)
+	}
+	if p := getBucketAccessPolicy(removed, "mybucket", "public/"); p != policy.BucketPolicyReadOnly {
+		t.Errorf("Expected policy %s, got %s", policy.BucketPolicyReadOnly, p)
+	}
+}
+
+// Wrapper for calling the upload-only policy tests for both XL multiple disks and single node setup.
+func TestUploadOnlyBucketPolicy(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testUploadOnlyBucketPolicy, []string{"GetObject", "PutObject", "NewMultipart",
+		"PutObjectPart", "CompleteMultipart", "AbortMultipart", "ListMultipartUploads", "ListObjectParts", "DeleteObject"})
+}
+
+func testUploadOnlyBucketPolicy(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	policyJSON, err := json.Marshal(policy.BucketAccessPolicy{
+		Version:    "2012-10-17",
+		Statements: setBucketAccessPolicy(nil, bucketPolicyUploadOnly, bucketName, "inbox/"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploadOnlyPolicy := &bucketPolicy{}
+	if err = parseBucketPolicy(bytes.NewReader(policyJSON), uploadOnlyPolicy); err != nil {
+		t.Fatalf("%s: Unable to parse the upload-only policy: %v", instanceType, err)
+	}
+	if err = globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, uploadOnlyPolicy}); err != nil {
+		t.Fatal(err)
+	}
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	data := []byte("hello")
+	if _, err = obj.PutObject(context.Background(), bucketName, "inbox/existing", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, "inbox/pending", nil)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		method     string
+		urlStr     string
+		body       []byte
+		statusCode int
+	}{
+		// Uploads are allowed.
+		{"PUT", getPutObjectURL("", bucketName, "inbox/new"), data, http.StatusOK},
+		{"POST", getNewMultipartURL("", bucketName, "inbox/multipart"), nil, http.StatusOK},
+		// Only under the upload-only prefix.
+		{"PUT", getPutObjectURL("", bucketName, "outbox/new"), data, http.StatusForbidden},
+		// Objects are never downloaded, removed or listed.
+		{"GET", getGetObjectURL("", bucketName, "inbox/existing"), nil, http.StatusForbidden},
+		{"DELETE", getDeleteObjectURL("", bucketName, "inbox/existing"), nil, http.StatusForbidden},
+		// Multipart uploads are never listed or aborted.
+		{"GET", getListMultipartUploadsURLWithParams("", bucketName, "inbox/", "", "", "", ""), nil, http.StatusForbidden},
+		{"GET", getListMultipartURLWithParams("", bucketName, "inbox/pending", uploadID, "", "", ""), nil, http.StatusForbidden},
+		{"DELETE", getAbortMultipartUploadURL("", bucketName, "inbox/pending", uploadID), nil, http.StatusForbidden},
+		{"DELETE", getAbortMultipartUploadURL("", bucketName, "inbox/pending", "missing-upload"), nil, http.StatusForbidden},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest(testCase.method, testCase.urlStr, int64(len(testCase.body)), bytes.NewReader(testCase.body))
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected %s %s to respond `%d`, but instead found `%d`", instanceType, i+1,
+				testCase.method, testCase.urlStr, testCase.statusCode, rec.Code)
+		}
+	}
+}
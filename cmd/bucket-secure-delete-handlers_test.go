@@ -0,0 +1,118 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling bucket secure delete HTTP handler tests for both XL multiple disks and single node setup.
+func TestBucketSecureDeleteHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testBucketSecureDeleteHandlers, []string{"PutBucketSecureDelete", "GetBucketSecureDelete"})
+}
+
+func testBucketSecureDeleteHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Secure delete changes are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+	defer globalBucketSecureDelete.Set(bucketName, nil)
+
+	putSecureDelete := func(body []byte, accessKey string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketSecureDeleteURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutBucketSecureDelete: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getSecureDelete := func() SecureDeleteConfiguration {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketSecureDeleteURL("", bucketName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetBucketSecureDelete: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		config := SecureDeleteConfiguration{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+		}
+		return config
+	}
+
+	enableXML := []byte("<SecureDeleteConfiguration><Enabled>true</Enabled></SecureDeleteConfiguration>")
+	if code := putSecureDelete(enableXML, "Invalid-AccessID"); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := putSecureDelete([]byte("<SecureDeleteConfiguration>"), credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if getSecureDelete().Enabled {
+		t.Fatalf("%s: Expected secure delete to be disabled by default", instanceType)
+	}
+
+	// Enable secure delete.
+	if code := putSecureDelete(enableXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if !getSecureDelete().Enabled || !globalBucketSecureDelete.Enabled(bucketName) {
+		t.Fatalf("%s: Expected secure delete to be enabled", instanceType)
+	}
+
+	// Objects are overwritten and deleted as usual.
+	for _, data := range []string{"first secret", "second secret"} {
+		_, err := obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewBufferString(data), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Unable to upload the object: <ERROR> %v", instanceType, err)
+		}
+	}
+	var buffer bytes.Buffer
+	if err := obj.GetObject(context.Background(), bucketName, "object", 0, -1, &buffer); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if buffer.String() != "second secret" {
+		t.Fatalf("%s: Expected the overwritten object to contain %q, got %q", instanceType, "second secret", buffer.String())
+	}
+	if err := obj.DeleteObject(context.Background(), bucketName, "object"); err != nil {
+		t.Fatalf("%s: Unable to delete the object: <ERROR> %v", instanceType, err)
+	}
+	if _, err := obj.GetObjectInfo(context.Background(), bucketName, "object"); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected the object to be deleted, got %v", instanceType, err)
+	}
+
+	// Disable secure delete.
+	disableXML := []byte("<SecureDeleteConfiguration><Enabled>false</Enabled></SecureDeleteConfiguration>")
+	if code := putSecureDelete(disableXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if getSecureDelete().Enabled || globalBucketSecureDelete.Enabled(bucketName) {
+		t.Fatalf("%s: Expected secure delete to be disabled", instanceType)
+	}
+}
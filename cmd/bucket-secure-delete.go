@@ -0,0 +1,219 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"sync"
+)
+
+const (
+	// Bucket secure delete config file, stored only for buckets
+	// shredding their objects.
+	bucketSecureDeleteConfig = "secure-delete.json"
+
+	// Size of the zeroed writes overwriting a shredded file.
+	shredBufferSize = 1024 * 1024
+)
+
+// SecureDeleteConfiguration - secure delete of the objects of a bucket,
+// as set by PutBucketSecureDelete and persisted in
+// bucketSecureDeleteConfig. The contents of the files of the objects
+// of the bucket are overwritten before they are removed.
+type SecureDeleteConfiguration struct {
+	XMLName xml.Name `xml:"SecureDeleteConfiguration" json:"-"`
+	Enabled bool     `xml:"Enabled" json:"enabled"`
+}
+
+// bucketSecureDelete - in-memory secure delete configs of all the
+// buckets shredding their objects.
+type bucketSecureDelete struct {
+	rwMutex *sync.RWMutex
+	configs map[string]SecureDeleteConfiguration
+}
+
+// Global secure delete configs, loaded at object layer initialization
+// and kept up to date by the peers on every change. Disks of remote
+// servers shred the files according to the configs of their server.
+var globalBucketSecureDelete = &bucketSecureDelete{
+	rwMutex: &sync.RWMutex{},
+	configs: make(map[string]SecureDeleteConfiguration),
+}
+
+// Get - returns the secure delete config of a bucket.
+func (bs *bucketSecureDelete) Get(bucket string) SecureDeleteConfiguration {
+	bs.rwMutex.RLock()
+	defer bs.rwMutex.RUnlock()
+	return bs.configs[bucket]
+}
+
+// Enabled - returns true if the objects of a bucket are shredded.
+func (bs *bucketSecureDelete) Enabled(bucket string) bool {
+	return bs.Get(bucket).Enabled
+}
+
+// Set - sets the secure delete config of a bucket, a nil config removes it.
+func (bs *bucketSecureDelete) Set(bucket string, config *SecureDeleteConfiguration) {
+	bs.rwMutex.Lock()
+	defer bs.rwMutex.Unlock()
+	if config == nil || !config.Enabled {
+		delete(bs.configs, bucket)
+		return
+	}
+	bs.configs[bucket] = *config
+}
+
+// Intialize secure delete configs of all buckets.
+func initBucketSecureDelete(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	configs := make(map[string]SecureDeleteConfiguration)
+	for _, bucket := range buckets {
+		config, rErr := readBucketSecureDelete(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other configs if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if config.Enabled {
+			configs[bucket.Name] = config
+		}
+	}
+
+	globalBucketSecureDelete.rwMutex.Lock()
+	globalBucketSecureDelete.configs = configs
+	globalBucketSecureDelete.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// readBucketSecureDelete - reads the secure delete config of a bucket,
+// buckets without a persisted config do not shred their objects.
+func readBucketSecureDelete(bucket string, objAPI ObjectLayer) (SecureDeleteConfiguration, error) {
+	secureDeletePath := pathJoin(bucketConfigPrefix, bucket, bucketSecureDeleteConfig)
+
+	// Acquire a read lock on secure delete config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, secureDeletePath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var config SecureDeleteConfiguration
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, secureDeletePath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load secure delete config for the bucket %s.", bucket)
+		return config, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse secure delete config for the bucket %s.", bucket)
+		return config, err
+	}
+	return config, nil
+}
+
+// writeBucketSecureDelete - saves the secure delete config of a bucket.
+func writeBucketSecureDelete(bucket string, config SecureDeleteConfiguration, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	secureDeletePath := pathJoin(bucketConfigPrefix, bucket, bucketSecureDeleteConfig)
+	// Acquire a write lock on secure delete config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, secureDeletePath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, secureDeletePath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set secure delete config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketSecureDelete - removes the persisted secure delete config
+// of a bucket, if any.
+func removeBucketSecureDelete(bucket string, objAPI ObjectLayer) error {
+	secureDeletePath := pathJoin(bucketConfigPrefix, bucket, bucketSecureDeleteConfig)
+	// Acquire a write lock on secure delete config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, secureDeletePath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, secureDeletePath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// shredFile - overwrites the contents of a regular file with zeros and
+// syncs them to the disk, before the file is removed. Directories and
+// missing files are left to the removal.
+func shredFile(filePath string) error {
+	file, err := os.OpenFile(preparePath(filePath), os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsNotExist(err) || isSysErrIsDir(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil
+	}
+	bufSize := int64(shredBufferSize)
+	if fi.Size() < bufSize {
+		bufSize = fi.Size()
+	}
+	zeros := make([]byte, bufSize)
+	for remaining := fi.Size(); remaining > 0; {
+		n := int64(len(zeros))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err = file.Write(zeros[:n]); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+	return file.Sync()
+}
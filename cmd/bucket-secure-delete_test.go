@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests overwriting the contents of a file with zeros.
+func TestShredFile(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := bytes.Repeat([]byte("secret"), shredBufferSize/3)
+	filePath := filepath.Join(dir, "object")
+	if err = ioutil.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = shredFile(filePath); err != nil {
+		t.Fatalf("Unable to shred the file: %v", err)
+	}
+	shredded, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(shredded, make([]byte, len(data))) {
+		t.Fatal("Expected the file contents to be overwritten with zeros")
+	}
+
+	// Missing files and directories are left to the removal.
+	if err = shredFile(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("Expected no error shredding a missing file, got %v", err)
+	}
+	if err = shredFile(dir); err != nil {
+		t.Fatalf("Expected no error shredding a directory, got %v", err)
+	}
+}
+
+// Tests disks shred the files of buckets with secure delete enabled.
+func TestPosixDeleteFileSecureDelete(t *testing.T) {
+	posixStorage, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer os.RemoveAll(path)
+
+	for _, volume := range []string{"shredded", "plain"} {
+		if err = posixStorage.MakeVol(volume); err != nil {
+			t.Fatal(err)
+		}
+		if err = posixStorage.AppendFile(context.Background(), volume, "object", []byte("secret")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	globalBucketSecureDelete.Set("shredded", &SecureDeleteConfiguration{Enabled: true})
+	defer globalBucketSecureDelete.Set("shredded", nil)
+
+	testCases := []struct {
+		volume   string
+		expected []byte
+	}{
+		{"shredded", make([]byte, len("secret"))},
+		{"plain", []byte("secret")},
+	}
+	for i, testCase := range testCases {
+		// The link keeps the contents of the removed file readable.
+		linkPath := filepath.Join(path, testCase.volume+".link")
+		if err = os.Link(filepath.Join(path, testCase.volume, "object"), linkPath); err != nil {
+			t.Fatal(err)
+		}
+		if err = posixStorage.DeleteFile(testCase.volume, "object"); err != nil {
+			t.Fatalf("Test %d: Unable to delete the file: %v", i+1, err)
+		}
+		contents, err := ioutil.ReadFile(linkPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(contents, testCase.expected) {
+			t.Errorf("Test %d: Expected the removed file to contain %q, got %q", i+1, testCase.expected, contents)
+		}
+	}
+}
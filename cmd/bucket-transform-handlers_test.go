@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling bucket transform HTTP handler tests for both XL multiple disks and single node setup.
+func TestBucketTransformHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testBucketTransformHandlers, []string{"PutBucketTransform", "GetBucketTransform", "GetObject"})
+}
+
+func testBucketTransformHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Transform changes are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+	defer globalBucketTransform.Set(bucketName, nil)
+
+	// Transformer redacting the objects, failing the ones named "fail".
+	transformer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || r.Header.Get("X-Minio-Object") == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("X-Minio-Bucket") != bucketName || r.Header.Get("Content-Type") != "text/plain" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/x-redacted")
+		w.Write(bytes.Replace(body, []byte("secret"), []byte("******"), -1))
+	}))
+	defer transformer.Close()
+	defer transformHTTPClient.Transport.(*http.Transport).CloseIdleConnections()
+
+	for _, object := range []string{"object", "fail"} {
+		data := "my secret data"
+		_, err := obj.PutObject(context.Background(), bucketName, object, int64(len(data)), bytes.NewBufferString(data), map[string]string{"content-type": "text/plain"}, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object %s: <ERROR> %v", instanceType, object, err)
+		}
+	}
+
+	putTransform := func(body []byte, accessKey string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketTransformURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for PutBucketTransform: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getTransform := func() TransformConfiguration {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketTransformURL("", bucketName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetBucketTransform: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		config := TransformConfiguration{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+		}
+		return config
+	}
+	getObject := func(object string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, object),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for GetObject: <ERROR> %v", err)
+		}
+		req.Header.Set("Range", "bytes=0-1")
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	transformXML := []byte("<TransformConfiguration><Endpoint>" + transformer.URL + "</Endpoint></TransformConfiguration>")
+	if code := putTransform(transformXML, "Invalid-AccessID"); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := putTransform([]byte("<TransformConfiguration>"), credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	invalidXML := []byte("<TransformConfiguration><Endpoint>ftp://transformer</Endpoint></TransformConfiguration>")
+	if code := putTransform(invalidXML, credentials.AccessKey); code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, code)
+	}
+	if getTransform().Endpoint != "" {
+		t.Fatalf("%s: Expected no transform by default", instanceType)
+	}
+
+	// Enable the transform.
+	if code := putTransform(transformXML, credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if config := getTransform(); config.Endpoint != transformer.URL {
+		t.Fatalf("%s: Expected the transform endpoint %s, got %s", instanceType, transformer.URL, config.Endpoint)
+	}
+
+	// Transformed objects are served whole.
+	rec := getObject("object")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec.Body.String() != "my ****** data" || rec.Header().Get("Content-Type") != "text/x-redacted" {
+		t.Fatalf("%s: Unexpected transformed object %q of type %s", instanceType, rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Errorf("%s: Expected no ETag for the transformed object, got %s", instanceType, rec.Header().Get("ETag"))
+	}
+	if rec = getObject("fail"); rec.Code != http.StatusBadGateway {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadGateway, rec.Code)
+	}
+
+	// Disable the transform.
+	if code := putTransform([]byte("<TransformConfiguration></TransformConfiguration>"), credentials.AccessKey); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if rec = getObject("object"); rec.Code != http.StatusPartialContent || rec.Body.String() != "my" {
+		t.Fatalf("%s: Expected the object range, got `%d` %q", instanceType, rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Bucket transform config file, stored only for buckets transforming
+// their downloads.
+const bucketTransformConfig = "transform.json"
+
+// TransformConfiguration - transformer of the downloads of the objects
+// of a bucket, as set by PutBucketTransform and persisted in
+// bucketTransformConfig.
+type TransformConfiguration struct {
+	XMLName xml.Name `xml:"TransformConfiguration" json:"-"`
+
+	// HTTP or HTTPS URL the objects are posted to, the response
+	// being returned in place of the object.
+	Endpoint string `xml:"Endpoint" json:"endpoint"`
+}
+
+// isValidTransformEndpoint - returns true for absolute HTTP and HTTPS URLs.
+func isValidTransformEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// bucketTransform - in-memory transform configs of all the buckets
+// transforming their downloads.
+type bucketTransform struct {
+	rwMutex *sync.RWMutex
+	configs map[string]TransformConfiguration
+}
+
+// Global transform configs, loaded at object layer initialization
+// and kept up to date by the peers on every change.
+var globalBucketTransform = &bucketTransform{
+	rwMutex: &sync.RWMutex{},
+	configs: make(map[string]TransformConfiguration),
+}
+
+// Get - returns the transform config of a bucket, false if the
+// downloads of the bucket are not transformed.
+func (bt *bucketTransform) Get(bucket string) (TransformConfiguration, bool) {
+	bt.rwMutex.RLock()
+	defer bt.rwMutex.RUnlock()
+	config, ok := bt.configs[bucket]
+	return config, ok
+}
+
+// Set - sets the transform config of a bucket, a nil config removes it.
+func (bt *bucketTransform) Set(bucket string, config *TransformConfiguration) {
+	bt.rwMutex.Lock()
+	defer bt.rwMutex.Unlock()
+	if config == nil || config.Endpoint == "" {
+		delete(bt.configs, bucket)
+		return
+	}
+	bt.configs[bucket] = *config
+}
+
+// Intialize transform configs of all buckets.
+func initBucketTransform(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	configs := make(map[string]TransformConfiguration)
+	for _, bucket := range buckets {
+		config, rErr := readBucketTransform(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other configs if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if config.Endpoint != "" {
+			configs[bucket.Name] = config
+		}
+	}
+
+	globalBucketTransform.rwMutex.Lock()
+	globalBucketTransform.configs = configs
+	globalBucketTransform.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// readBucketTransform - reads the transform config of a bucket,
+// buckets without a persisted config do not transform their downloads.
+func readBucketTransform(bucket string, objAPI ObjectLayer) (TransformConfiguration, error) {
+	transformPath := pathJoin(bucketConfigPrefix, bucket, bucketTransformConfig)
+
+	// Acquire a read lock on transform config before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, transformPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var config TransformConfiguration
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, transformPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load transform config for the bucket %s.", bucket)
+		return config, errorCause(err)
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse transform config for the bucket %s.", bucket)
+		return config, err
+	}
+	return config, nil
+}
+
+// writeBucketTransform - saves the transform config of a bucket.
+func writeBucketTransform(bucket string, config TransformConfiguration, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	transformPath := pathJoin(bucketConfigPrefix, bucket, bucketTransformConfig)
+	// Acquire a write lock on transform config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, transformPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, transformPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set transform config for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// removeBucketTransform - removes the persisted transform config of a
+// bucket, if any.
+func removeBucketTransform(bucket string, objAPI ObjectLayer) error {
+	transformPath := pathJoin(bucketConfigPrefix, bucket, bucketTransformConfig)
+	// Acquire a write lock on transform config before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, transformPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, transformPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// HTTP client posting the objects to their transformers, the response
+// headers are expected once the whole object is posted.
+var transformHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	},
+}
+
+// Headers of the transformer response returned to the client.
+var transformResponseHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Encoding",
+	"Content-Disposition",
+	"Content-Language",
+	"Cache-Control",
+}
+
+// transformObject - posts the whole object to the transformer of its
+// bucket and writes back the transformer response, with the object
+// headers which still apply.
+func transformObject(ctx context.Context, w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, objInfo ObjectInfo, config TransformConfiguration) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(objAPI.GetObject(ctx, objInfo.Bucket, objInfo.Name, 0, objInfo.Size, pw))
+	}()
+
+	req, err := http.NewRequest("POST", config.Endpoint, pr)
+	if err != nil {
+		errorIf(err, "Unable to create the transform request.")
+		writeErrorResponse(w, ErrTransformFailed, r.URL)
+		return
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = objInfo.Size
+	req.Header.Set("User-Agent", globalServerUserAgent)
+	req.Header.Set("Content-Type", objInfo.ContentType)
+	req.Header.Set("X-Minio-Bucket", objInfo.Bucket)
+	req.Header.Set("X-Minio-Object", objInfo.Name)
+	req.Header.Set("X-Minio-Object-Size", strconv.FormatInt(objInfo.Size, 10))
+	if objInfo.MD5Sum != "" {
+		req.Header.Set("X-Minio-Object-Etag", "\""+objInfo.MD5Sum+"\"")
+	}
+
+	resp, err := transformHTTPClient.Do(req)
+	if err != nil {
+		errorIf(err, "Unable to transform %s/%s.", objInfo.Bucket, objInfo.Name)
+		writeErrorResponse(w, ErrTransformFailed, r.URL)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errorIf(fmt.Errorf("transformer replied %s", resp.Status), "Unable to transform %s/%s.", objInfo.Bucket, objInfo.Name)
+		writeErrorResponse(w, ErrTransformFailed, r.URL)
+		return
+	}
+
+	// The transformed object has no ETag nor user metadata of its own.
+	setCommonHeaders(w)
+	w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
+	for _, header := range transformResponseHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	// Set any additional requested response headers.
+	setGetRespHeaders(w, r.URL.Query())
+	w.WriteHeader(http.StatusOK)
+
+	// Downloads are limited to the download bandwidth of the bucket and of the user.
+	_, err = io.Copy(globalBucketBandwidth.ThrottleWriter(objInfo.Bucket, getRequestAccessKey(r), w), resp.Body)
+	errorIf(err, "Unable to write to client.")
+}
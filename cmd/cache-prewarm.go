@@ -0,0 +1,216 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/objcache"
+)
+
+// objectCacher - object layers caching whole objects in memory.
+type objectCacher interface {
+	// cacheStats - returns the space accounting of the object
+	// cache, false if the object cache is disabled.
+	cacheStats() (objcache.Stats, bool)
+	// isCached - returns true if the object last modified at
+	// modTime is cached.
+	isCached(bucket, object string, modTime time.Time) bool
+}
+
+// getObjectCacher - returns the object cache of objAPI, false if
+// objAPI does not cache objects or its cache is disabled.
+func getObjectCacher(objAPI ObjectLayer) (objectCacher, bool) {
+	for {
+		switch l := objAPI.(type) {
+		case tracedObjectLayer:
+			objAPI = l.ObjectLayer
+		case indexedObjectLayer:
+			objAPI = l.ObjectLayer
+		case objectCacher:
+			_, ok := l.cacheStats()
+			return l, ok
+		default:
+			return nil, false
+		}
+	}
+}
+
+// cachePrewarmStatus - progress of a cache pre-warm job along with
+// the space accounting of the object cache.
+type cachePrewarmStatus struct {
+	Bucket    string    `json:"bucket"`
+	Prefixes  []string  `json:"prefixes"`
+	Running   bool      `json:"running"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Error     string    `json:"error,omitempty"`
+
+	// Objects listed under the prefixes so far.
+	Objects int64 `json:"objects"`
+	// Objects cached by the job or cached already.
+	Cached      int64 `json:"cached"`
+	CachedBytes int64 `json:"cachedBytes"`
+	// Objects empty, too large or not fitting in the space left.
+	Skipped      int64 `json:"skipped"`
+	SkippedBytes int64 `json:"skippedBytes"`
+	// Objects failing to be read.
+	Failed int64 `json:"failed"`
+
+	CacheSize         uint64 `json:"cacheSize"`
+	CacheMaxSize      uint64 `json:"cacheMaxSize"`
+	CacheMaxEntrySize uint64 `json:"cacheMaxEntrySize"`
+	CacheEntries      int    `json:"cacheEntries"`
+}
+
+// cachePrewarmer - runs one cache pre-warm job at a time, keeping the
+// status of the last one.
+type cachePrewarmer struct {
+	mutex  sync.Mutex
+	status cachePrewarmStatus
+}
+
+// Pre-warm jobs of this server.
+var globalCachePrewarm = &cachePrewarmer{}
+
+// Start - starts caching the objects of bucket under prefixes in the
+// background, returns false if a job is already running.
+func (p *cachePrewarmer) Start(objAPI ObjectLayer, cacher objectCacher, bucket string, prefixes []string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.status.Running {
+		return false
+	}
+	p.status = cachePrewarmStatus{
+		Bucket:    bucket,
+		Prefixes:  prefixes,
+		Running:   true,
+		StartTime: time.Now().UTC(),
+	}
+	go p.run(objAPI, cacher, bucket, prefixes)
+	return true
+}
+
+// Status - returns the status of the last job along with the current
+// space accounting of cacher.
+func (p *cachePrewarmer) Status(cacher objectCacher) cachePrewarmStatus {
+	p.mutex.Lock()
+	status := p.status
+	p.mutex.Unlock()
+	if stats, ok := cacher.cacheStats(); ok {
+		status.CacheSize = stats.Size
+		status.CacheMaxSize = stats.MaxSize
+		status.CacheMaxEntrySize = stats.MaxEntrySize
+		status.CacheEntries = stats.Entries
+	}
+	return status
+}
+
+// update - updates the status of the running job.
+func (p *cachePrewarmer) update(fn func(status *cachePrewarmStatus)) {
+	p.mutex.Lock()
+	fn(&p.status)
+	p.mutex.Unlock()
+}
+
+func (p *cachePrewarmer) run(objAPI ObjectLayer, cacher objectCacher, bucket string, prefixes []string) {
+	var err error
+	for _, prefix := range prefixes {
+		if err = p.prewarmPrefix(objAPI, cacher, bucket, prefix); err != nil {
+			break
+		}
+	}
+	errorIf(err, "Unable to pre-warm the object cache with %s.", bucket)
+	p.update(func(status *cachePrewarmStatus) {
+		status.Running = false
+		status.EndTime = time.Now().UTC()
+		if err != nil {
+			status.Error = err.Error()
+		}
+	})
+}
+
+// prewarmPrefix - caches the objects of bucket under prefix.
+func (p *cachePrewarmer) prewarmPrefix(objAPI ObjectLayer, cacher objectCacher, bucket, prefix string) error {
+	marker := ""
+	for {
+		var result ListObjectsInfo
+		err := globalBackgroundOps.Do(func() (lErr error) {
+			result, lErr = objAPI.ListObjects(context.Background(), bucket, prefix, marker, "", maxObjectList)
+			return lErr
+		})
+		if err != nil {
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			cached, err := prewarmObject(objAPI, cacher, bucket, objInfo)
+			if err != nil {
+				errorIf(err, "Unable to cache %s/%s.", bucket, objInfo.Name)
+			}
+			p.update(func(status *cachePrewarmStatus) {
+				status.Objects++
+				switch {
+				case err != nil:
+					status.Failed++
+				case cached:
+					status.Cached++
+					status.CachedBytes += objInfo.Size
+				default:
+					status.Skipped++
+					status.SkippedBytes += objInfo.Size
+				}
+			})
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// prewarmObject - reads the whole object of bucket so that it is cached, returns
+// false if it does not fit in the cache or was removed since listed.
+func prewarmObject(objAPI ObjectLayer, cacher objectCacher, bucket string, objInfo ObjectInfo) (bool, error) {
+	if cacher.isCached(bucket, objInfo.Name, objInfo.ModTime) {
+		return true, nil
+	}
+	stats, ok := cacher.cacheStats()
+	if !ok || objInfo.Size == 0 || uint64(objInfo.Size) > stats.MaxEntrySize ||
+		stats.Size+uint64(objInfo.Size) > stats.MaxSize {
+		return false, nil
+	}
+
+	// Objects are cached only when read under the object lock.
+	objectLock := globalNSMutex.NewNSLock(context.Background(), bucket, objInfo.Name)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	err := globalBackgroundOps.Do(func() error {
+		return objAPI.GetObject(context.Background(), bucket, objInfo.Name, 0, objInfo.Size, ioutil.Discard)
+	})
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			// Removed since listed.
+			return false, nil
+		}
+		return false, err
+	}
+	return cacher.isCached(bucket, objInfo.Name, objInfo.ModTime), nil
+}
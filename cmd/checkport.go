@@ -37,7 +37,13 @@ import (
 //
 // "Keep in mind that ports in Go are strings: https://play.golang.org/p/zk2WEri_E9"
 //                 - @bradfitz
+//
+// Ports handed over by the parent process during a restart are in use
+// by this process already, hence considered available.
 func checkPortAvailability(portStr string) error {
+	if isInheritedPort(portStr) {
+		return nil
+	}
 	network := [3]string{"tcp", "tcp4", "tcp6"}
 	for _, n := range network {
 		l, err := net.Listen(n, net.JoinHostPort("", portStr))
@@ -0,0 +1,109 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Environment variable with the largest difference tolerated between
+// the clocks of the servers on internode RPCs, such as "10s".
+const clockSkewToleranceEnv = "MINIO_CLOCK_SKEW_TOLERANCE"
+
+// Internode RPCs are refused when sent more than this long before or
+// after they are received, unless configured otherwise.
+const defaultClockSkewTolerance = 3 * time.Second
+
+// Interval between two checks of the clocks of the servers.
+const clockSkewCheckInterval = 10 * time.Minute
+
+// Largest difference tolerated between the clocks of the servers.
+var globalClockSkewTolerance = defaultClockSkewTolerance
+
+// getClockSkewTolerance - returns the clock skew tolerance configured
+// through the environment.
+func getClockSkewTolerance() (time.Duration, error) {
+	value := os.Getenv(clockSkewToleranceEnv)
+	if value == "" {
+		return defaultClockSkewTolerance, nil
+	}
+	tolerance, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", clockSkewToleranceEnv, value, err)
+	}
+	if tolerance <= 0 || tolerance > globalMaxSkewTime {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive and at most %s", clockSkewToleranceEnv, value, globalMaxSkewTime)
+	}
+	return tolerance, nil
+}
+
+// absDuration - returns the absolute value of d.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// clockSkewWarning - returns why the clock of a server skew apart from
+// the clock of this server endangers the cluster, "" if it does not.
+func clockSkewWarning(skew time.Duration) string {
+	skew = absDuration(skew)
+	switch {
+	case skew > globalMaxSkewTime/2:
+		// Clients synchronized with one of the servers sign
+		// requests refused by the other one.
+		return fmt.Sprintf("Clock %s apart, S3 requests are refused by the servers more than %s apart from the signing client", skew, globalMaxSkewTime)
+	case skew > globalClockSkewTolerance/2:
+		return fmt.Sprintf("Clock %s apart, internode RPCs and distributed locks fail beyond %s", skew, globalClockSkewTolerance)
+	}
+	return ""
+}
+
+// setClockSkew - sets the clock skew of the server health health
+// fetched between sent and received, estimating the server time as of
+// the middle of the round trip.
+func setClockSkew(health *serverHealth, sent, received time.Time) {
+	health.ClockSkew = health.Time.Sub(sent.Add(received.Sub(sent) / 2))
+	health.ClockWarning = clockSkewWarning(health.ClockSkew)
+}
+
+// startClockSkewMonitor - periodically logs a warning for the servers
+// with clocks too far apart from the clock of this server.
+func startClockSkewMonitor(peers adminPeers, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, server := range getPeersHealth(peers) {
+				if server.ClockWarning == "" {
+					continue
+				}
+				for _, l := range log.loggers {
+					l.WithFields(logrus.Fields{
+						"server":    server.Addr,
+						"clockSkew": server.ClockSkew.String(),
+					}).Warn(server.ClockWarning)
+				}
+			}
+		}
+	}()
+}
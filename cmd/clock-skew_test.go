@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the clock skew tolerance from the environment.
+func TestGetClockSkewTolerance(t *testing.T) {
+	defer os.Unsetenv(clockSkewToleranceEnv)
+
+	testCases := []struct {
+		value      string
+		tolerance  time.Duration
+		shouldPass bool
+	}{
+		{"", defaultClockSkewTolerance, true},
+		{"10s", 10 * time.Second, true},
+		{"15m", 15 * time.Minute, true},
+		{"16m", 0, false},
+		{"0s", 0, false},
+		{"-1s", 0, false},
+		{"ten", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(clockSkewToleranceEnv, testCase.value)
+		tolerance, err := getClockSkewTolerance()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if tolerance != testCase.tolerance {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.tolerance, tolerance)
+		}
+	}
+}
+
+// Tests the RPC request times allowed by the clock skew tolerance.
+func TestClockSkewTolerance(t *testing.T) {
+	defer func() { globalClockSkewTolerance = defaultClockSkewTolerance }()
+
+	globalClockSkewTolerance = time.Minute
+	if !isRequestTimeAllowed(time.Now().UTC().Add(-30 * time.Second)) {
+		t.Error("Expected a request time 30s apart to be allowed")
+	}
+	if isRequestTimeAllowed(time.Now().UTC().Add(2 * time.Minute)) {
+		t.Error("Expected a request time 2m apart to be refused")
+	}
+
+	// Warnings once half of the tolerances are reached.
+	testCases := []struct {
+		skew    time.Duration
+		warning bool
+	}{
+		{0, false},
+		{-20 * time.Second, false},
+		{40 * time.Second, true},
+		{-40 * time.Second, true},
+		{10 * time.Minute, true},
+	}
+	for i, testCase := range testCases {
+		if warning := clockSkewWarning(testCase.skew); testCase.warning != (warning != "") {
+			t.Errorf("Test %d: Expected warning %t, got %q", i+1, testCase.warning, warning)
+		}
+	}
+}
+
+// Tests estimating the clock skew of a server from a round trip.
+func TestSetClockSkew(t *testing.T) {
+	sent := time.Date(2017, 10, 14, 10, 0, 0, 0, time.UTC)
+	received := sent.Add(2 * time.Second)
+
+	// Server 10s ahead, answering in the middle of the round trip.
+	health := serverHealth{Time: sent.Add(11 * time.Second)}
+	setClockSkew(&health, sent, received)
+	if health.ClockSkew != 10*time.Second {
+		t.Errorf("Expected a clock skew of 10s, got %s", health.ClockSkew)
+	}
+	if health.ClockWarning == "" {
+		t.Error("Expected a clock warning")
+	}
+}
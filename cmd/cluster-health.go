@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Health status of the cluster.
+const (
+	// All the servers and disks are online.
+	healthStatusOK = "ok"
+	// Some servers or disks are offline, data is still readable
+	// and writable.
+	healthStatusDegraded = "degraded"
+	// Not enough disks are online to write data.
+	healthStatusUnavailable = "unavailable"
+)
+
+// serverHealth - health of a server, as reported by the server.
+type serverHealth struct {
+	Addr   string `json:"addr"`
+	Online bool   `json:"online"`
+	Error  string `json:"error,omitempty"`
+	// Set for standby servers, serving reads only.
+	Standby bool `json:"standby,omitempty"`
+
+	// Time of the server, and its difference with the time of the
+	// server fetching the health.
+	Time         time.Time     `json:"time"`
+	ClockSkew    time.Duration `json:"clockSkew"`
+	ClockWarning string        `json:"clockWarning,omitempty"`
+
+	// Objects found degraded on read waiting to be healed.
+	HealOnRead int `json:"healOnRead"`
+	// Objects written with reduced redundancy waiting to be healed.
+	PartialWrites int `json:"partialWrites"`
+
+	ReadLocks    int `json:"readLocks"`
+	WriteLocks   int `json:"writeLocks"`
+	BlockedLocks int `json:"blockedLocks"`
+
+	// S3 API requests being served.
+	Requests int `json:"requests"`
+}
+
+// clusterHealth - health summary of all the servers, their disks and
+// the data they store.
+type clusterHealth struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+
+	Servers     []serverHealth `json:"servers"`
+	NodesOnline int            `json:"nodesOnline"`
+	// Servers not answering.
+	NodesOffline int `json:"nodesOffline"`
+
+	// Largest clock difference of the servers with this server.
+	MaxClockSkew time.Duration `json:"maxClockSkew"`
+
+	DisksOnline     int `json:"disksOnline"`
+	DisksOffline    int `json:"disksOffline"`
+	DisksUnwritable int `json:"disksUnwritable"`
+	ReadQuorum      int `json:"readQuorum"`
+	WriteQuorum     int `json:"writeQuorum"`
+
+	// Objects waiting to be healed on all the servers.
+	HealBacklog int `json:"healBacklog"`
+
+	ReadLocks    int `json:"readLocks"`
+	WriteLocks   int `json:"writeLocks"`
+	BlockedLocks int `json:"blockedLocks"`
+	Requests     int `json:"requests"`
+
+	// Disk space of the backend.
+	Total int64 `json:"total"`
+	Free  int64 `json:"free"`
+	// Objects and their size as of the last data usage crawl.
+	Objects         uint64    `json:"objects"`
+	Size            uint64    `json:"size"`
+	UsageLastUpdate time.Time `json:"usageLastUpdate"`
+}
+
+// getHealBacklog - returns the objects found degraded on read and the
+// objects written with reduced redundancy waiting to be healed by
+// objAPI, none for backends not healing objects.
+func getHealBacklog(objAPI ObjectLayer) (healOnRead, partialWrites int) {
+	for {
+		switch l := objAPI.(type) {
+		case tracedObjectLayer:
+			objAPI = l.ObjectLayer
+		case indexedObjectLayer:
+			objAPI = l.ObjectLayer
+		case xlObjects:
+			return l.healQueue.backlog(), l.mrf.backlog()
+		default:
+			return 0, 0
+		}
+	}
+}
+
+// getLocalServerHealth - returns the health of this server.
+func getLocalServerHealth() serverHealth {
+	health := serverHealth{
+		Online:  true,
+		Standby: globalServerModes.IsStandby(),
+		Time:    time.Now().UTC(),
+	}
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		health.HealOnRead, health.PartialWrites = getHealBacklog(objAPI)
+	}
+	if globalNSMutex != nil {
+		globalNSMutex.lockMapMutex.Lock()
+		health.ReadLocks, health.WriteLocks, health.BlockedLocks = countLocks(globalNSMutex)
+		globalNSMutex.lockMapMutex.Unlock()
+	}
+	health.Requests = globalInflightRequests.Count()
+	return health
+}
+
+// getPeersHealth - fetches the health of all the servers, servers not
+// answering are reported offline, along with a clock warning if their
+// clock is too far apart for the RPC to be served.
+func getPeersHealth(peers adminPeers) []serverHealth {
+	servers := make([]serverHealth, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			sent := time.Now().UTC()
+			health, err := peer.cmdRunner.ServerHealth()
+			switch {
+			case err == nil:
+				setClockSkew(&health, sent, time.Now().UTC())
+			case err.Error() == errServerTimeMismatch.Error():
+				health = serverHealth{
+					Error:        err.Error(),
+					ClockWarning: fmt.Sprintf("Clock more than %s apart, internode RPCs and distributed locks fail", globalClockSkewTolerance),
+				}
+			default:
+				health = serverHealth{Error: err.Error()}
+			}
+			health.Addr = peer.addr
+			servers[idx] = health
+		}(i, peer)
+	}
+	wg.Wait()
+	return servers
+}
+
+// getClusterHealth - returns the health summary of the servers and of
+// the disks and data of objAPI.
+func getClusterHealth(objAPI ObjectLayer, peers adminPeers) clusterHealth {
+	health := clusterHealth{
+		Time:    time.Now().UTC(),
+		Servers: getPeersHealth(peers),
+	}
+	for _, server := range health.Servers {
+		if !server.Online {
+			health.NodesOffline++
+			continue
+		}
+		health.NodesOnline++
+		if absDuration(server.ClockSkew) > health.MaxClockSkew {
+			health.MaxClockSkew = absDuration(server.ClockSkew)
+		}
+		health.HealBacklog += server.HealOnRead + server.PartialWrites
+		health.ReadLocks += server.ReadLocks
+		health.WriteLocks += server.WriteLocks
+		health.BlockedLocks += server.BlockedLocks
+		health.Requests += server.Requests
+	}
+
+	storageInfo := objAPI.StorageInfo()
+	health.Total, health.Free = storageInfo.Total, storageInfo.Free
+	health.DisksOnline = storageInfo.Backend.OnlineDisks
+	health.DisksOffline = storageInfo.Backend.OfflineDisks
+	health.DisksUnwritable = storageInfo.Backend.UnwritableDisks
+	health.ReadQuorum = storageInfo.Backend.ReadQuorum
+	health.WriteQuorum = storageInfo.Backend.WriteQuorum
+
+	usage, err := readDataUsage(objAPI)
+	errorIf(err, "Unable to read data usage.")
+	health.Objects, health.Size, health.UsageLastUpdate = usage.Objects, usage.Size, usage.LastUpdate
+
+	switch {
+	case storageInfo.Backend.Type != FS && health.DisksOnline-health.DisksUnwritable < health.WriteQuorum:
+		health.Status = healthStatusUnavailable
+	case health.NodesOffline > 0 || health.DisksOffline > 0 || health.DisksUnwritable > 0 || health.hasClockWarning():
+		health.Status = healthStatusDegraded
+	default:
+		health.Status = healthStatusOK
+	}
+	return health
+}
+
+// hasClockWarning - returns true if the clock of any server is too far
+// apart from the clock of this server.
+func (health clusterHealth) hasClockWarning() bool {
+	for _, server := range health.Servers {
+		if server.ClockWarning != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+// configCheck - result of checking one part of the server config.
+type configCheck struct {
+	Name string
+	Err  error
+}
+
+// checkConsoleLogger - validates the console logger config.
+func checkConsoleLogger(clogger consoleLogger) error {
+	if !clogger.Enable {
+		return nil
+	}
+	_, err := logrus.ParseLevel(clogger.Level)
+	return err
+}
+
+// checkFileLogger - validates the file logger config, the log file
+// is created if it does not exist.
+func checkFileLogger(flogger fileLogger) error {
+	if !flogger.Enable || flogger.Filename == "" {
+		return nil
+	}
+	if _, err := logrus.ParseLevel(flogger.Level); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(flogger.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// checkNotifyTarget - connects to an enabled notification target.
+func checkNotifyTarget(queueType, accountID string, newNotify func(string) (*logrus.Logger, error)) configCheck {
+	name := fmt.Sprintf("notify.%s[%q]", queueType, accountID)
+	_, err := newNotify(accountID)
+	return configCheck{Name: name, Err: err}
+}
+
+// enabledTargets - returns the sorted IDs of the enabled targets.
+func enabledTargets(enabled map[string]bool) []string {
+	var accountIDs []string
+	for accountID, enable := range enabled {
+		if enable {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	sort.Strings(accountIDs)
+	return accountIDs
+}
+
+// checkServerConfig - validates the loaded server config, connecting
+// to every enabled notification target.
+func checkServerConfig() []configCheck {
+	cred := serverConfig.GetCredential()
+	_, err := getCredential(cred.AccessKey, cred.SecretKey)
+	checks := []configCheck{
+		{Name: "credential", Err: err},
+		{Name: "logger.console", Err: checkConsoleLogger(serverConfig.GetConsoleLogger())},
+		{Name: "logger.file", Err: checkFileLogger(serverConfig.GetFileLogger())},
+	}
+
+	checkTargets := func(queueType string, enabled map[string]bool, newNotify func(string) (*logrus.Logger, error)) {
+		for _, accountID := range enabledTargets(enabled) {
+			checks = append(checks, checkNotifyTarget(queueType, accountID, newNotify))
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for accountID, target := range serverConfig.GetAMQP() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeAMQP, enabled, newAMQPNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetNATS() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeNATS, enabled, newNATSNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetElasticSearch() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeElastic, enabled, newElasticNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetRedis() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeRedis, enabled, newRedisNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetPostgreSQL() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypePostgreSQL, enabled, newPostgreSQLNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetKafka() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeKafka, enabled, newKafkaNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetWebhook() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeWebhook, enabled, newWebhookNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetNSQ() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeNSQ, enabled, newNSQNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetPubSub() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypePubSub, enabled, newPubSubNotify)
+
+	enabled = make(map[string]bool)
+	for accountID, target := range serverConfig.GetEventHubs() {
+		enabled[accountID] = target.Enable
+	}
+	checkTargets(queueTypeEventHubs, enabled, newEventHubsNotify)
+
+	return checks
+}
+
+// printConfigChecks - writes the pass/fail report of checks to w,
+// returns the number of failed checks.
+func printConfigChecks(w io.Writer, checks []configCheck) (failed int) {
+	for _, check := range checks {
+		if check.Err != nil {
+			failed++
+			fmt.Fprintf(w, "  FAIL  %s: %s\n", check.Name, check.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  PASS  %s\n", check.Name)
+	}
+	if failed > 0 {
+		fmt.Fprintf(w, "Configuration check failed, %d of %d checks failed.\n", failed, len(checks))
+	} else {
+		fmt.Fprintf(w, "Configuration check passed, %d checks.\n", len(checks))
+	}
+	return failed
+}
+
+// checkConfigMain - loads the config the server would start with,
+// prints the report of checking it and exits, without creating a
+// default config or starting the server.
+func checkConfigMain(ctx *cli.Context) {
+	setGlobalsFromContext(ctx)
+	setGlobalConfigPath(globalConfigDir)
+	globalIsSSL = isSSL()
+
+	var checks []configCheck
+	exit := func() {
+		if printConfigChecks(os.Stdout, checks) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	var err error
+	if endpoints := os.Getenv(etcdEndpointsEnv); endpoints != "" {
+		loadRootCAs()
+		globalEtcdClient, err = newEtcdClient(endpoints)
+		if err == nil {
+			_, err = globalEtcdClient.Get(etcdConfigKey)
+		}
+		console.Println("Checking minio configuration in etcd at " + endpoints)
+	} else {
+		if !isConfigFileExists() {
+			err = errConfigNotFound
+		}
+		console.Println("Checking minio configuration at " + mustGetConfigPath())
+	}
+	if err == nil {
+		_, err = initConfig()
+	}
+	checks = append(checks, configCheck{Name: "config", Err: err})
+	if err != nil {
+		exit()
+	}
+
+	serverConfigMu.Lock()
+	err = overrideConfigFromEnv(serverConfig, os.Environ())
+	serverConfigMu.Unlock()
+	checks = append(checks, configCheck{Name: "environment", Err: err})
+	if globalEnvAccessKey != "" && globalEnvSecretKey != "" {
+		serverConfig.SetCredential(credential{
+			AccessKey: globalEnvAccessKey,
+			SecretKey: globalEnvSecretKey,
+		})
+	}
+
+	checks = append(checks, checkServerConfig()...)
+	exit()
+}
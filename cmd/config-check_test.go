@@ -0,0 +1,65 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Tests checking the server config and printing the report.
+func TestCheckServerConfig(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	serverConfig.SetWebhookNotifyByID("1", webhookNotify{Enable: true, Endpoint: "http://127.0.0.1:1/"})
+	serverConfig.SetWebhookNotifyByID("2", webhookNotify{Enable: false, Endpoint: "http://127.0.0.1:1/"})
+	serverConfig.SetConsoleLogger(consoleLogger{Enable: true, Level: "verbose"})
+
+	checks := checkServerConfig()
+	expected := []struct {
+		name       string
+		shouldPass bool
+	}{
+		{"credential", true},
+		{"logger.console", false},
+		{"logger.file", true},
+		{`notify.webhook["1"]`, false},
+	}
+	if len(checks) != len(expected) {
+		t.Fatalf("Expected %d checks, got %v", len(expected), checks)
+	}
+	for i, check := range checks {
+		if check.Name != expected[i].name || (check.Err == nil) != expected[i].shouldPass {
+			t.Errorf("Check %d: Expected %s to pass %v, got %s %v", i+1, expected[i].name,
+				expected[i].shouldPass, check.Name, check.Err)
+		}
+	}
+
+	var report bytes.Buffer
+	if failed := printConfigChecks(&report, checks); failed != 2 {
+		t.Fatalf("Expected 2 failed checks, got %d", failed)
+	}
+	if !strings.Contains(report.String(), "  PASS  credential\n") ||
+		!strings.Contains(report.String(), "2 of 4 checks failed") {
+		t.Fatalf("Unexpected report %s", report.String())
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Prefix of the environment variables overriding config fields.
+const configEnvPrefix = "MINIO_"
+
+// configEnvName - converts a json field name to its environment
+// variable form, e.g. "routingKey" to "ROUTING_KEY".
+func configEnvName(name string) string {
+	var envName []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]) {
+			envName = append(envName, '_')
+		}
+		envName = append(envName, unicode.ToUpper(r))
+	}
+	return string(envName)
+}
+
+// overrideConfigFromEnv - overrides the fields of srvCfg with the
+// values of the environment variables in environ, as returned by
+// os.Environ(). The variable of a field is named after its json path,
+// such as MINIO_REGION, MINIO_LOGGER_FILE_ENABLE or
+// MINIO_NOTIFY_WEBHOOK_1_ENDPOINT for the target with ID "1".
+func overrideConfigFromEnv(srvCfg *serverConfigV17, environ []string) error {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if i := strings.Index(kv, "="); i > 0 && strings.HasPrefix(kv, configEnvPrefix) {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	return overrideValueFromEnv(reflect.ValueOf(srvCfg).Elem(), strings.TrimSuffix(configEnvPrefix, "_"), env)
+}
+
+// overrideValueFromEnv - sets v from the environment variable name,
+// or its fields and elements from the variables prefixed by name.
+func overrideValueFromEnv(v reflect.Value, name string, env map[string]string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			// Version is never overridden.
+			if tag == "" || tag == "-" || tag == "version" {
+				continue
+			}
+			if err := overrideValueFromEnv(v.Field(i), name+"_"+configEnvName(tag), env); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		// Collect the IDs of the map entries to override, IDs
+		// should not contain '_'.
+		ids := make(map[string]bool)
+		for envName := range env {
+			if !strings.HasPrefix(envName, name+"_") {
+				continue
+			}
+			id := strings.SplitN(strings.TrimPrefix(envName, name+"_"), "_", 2)[0]
+			if id != "" {
+				ids[id] = true
+			}
+		}
+		if len(ids) > 0 && v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for id := range ids {
+			key := reflect.ValueOf(id)
+			// Map elements are not addressable, override a copy.
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if existing := v.MapIndex(key); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := overrideValueFromEnv(elem, name+"_"+id, env); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		return nil
+	}
+
+	value, ok := env[name]
+	if !ok {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid boolean value ‘%s’ of %s", value, name)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid integer value ‘%s’ of %s", value, name)
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("Unsupported type of %s", name)
+		}
+		var values []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("Unsupported type of %s", name)
+	}
+	return nil
+}
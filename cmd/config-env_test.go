@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests converting json field names to environment variable names.
+func TestConfigEnvName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"region", "REGION"},
+		{"routingKey", "ROUTING_KEY"},
+		{"clusterID", "CLUSTER_ID"},
+		{"maxPubAcksInflight", "MAX_PUB_ACKS_INFLIGHT"},
+		{"amqp", "AMQP"},
+	}
+	for i, testCase := range testCases {
+		if envName := configEnvName(testCase.name); envName != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, envName)
+		}
+	}
+}
+
+// Tests overriding config fields from the environment.
+func TestOverrideConfigFromEnv(t *testing.T) {
+	srvCfg := newServerConfigV17()
+	environ := []string{
+		"PATH=/usr/bin",
+		"MINIO_REGION=us-west-1",
+		"MINIO_CREDENTIAL_ACCESS_KEY=minioadmin",
+		"MINIO_LOGGER_FILE_ENABLE=true",
+		"MINIO_LOGGER_FILE_FILE_NAME=/var/log/minio.log",
+		"MINIO_NOTIFY_AMQP_1_ROUTING_KEY=minio",
+		"MINIO_NOTIFY_NATS_1_STREAMING_MAX_PUB_ACKS_INFLIGHT=10",
+		"MINIO_NOTIFY_NATS_1_PING_INTERVAL=30",
+		"MINIO_NOTIFY_KAFKA_prod_BROKERS=kafka1:9092, kafka2:9092",
+		"MINIO_NOTIFY_KAFKA_prod_ENABLE=on",
+		"MINIO_VERSION=1",
+	}
+	if err := overrideConfigFromEnv(srvCfg, environ); err == nil {
+		t.Fatal("Expected invalid boolean to fail")
+	}
+
+	srvCfg = newServerConfigV17()
+	secretKey := srvCfg.Credential.SecretKey
+	environ[len(environ)-2] = "MINIO_NOTIFY_KAFKA_prod_ENABLE=true"
+	if err := overrideConfigFromEnv(srvCfg, environ); err != nil {
+		t.Fatal(err)
+	}
+
+	if srvCfg.Version != globalMinioConfigVersion {
+		t.Errorf("Expected version not to be overridden, got %s", srvCfg.Version)
+	}
+	if srvCfg.Region != "us-west-1" {
+		t.Errorf("Expected region us-west-1, got %s", srvCfg.Region)
+	}
+	if srvCfg.Credential.AccessKey != "minioadmin" || srvCfg.Credential.SecretKey != secretKey {
+		t.Errorf("Unexpected credential %v", srvCfg.Credential)
+	}
+	if !srvCfg.Logger.File.Enable || srvCfg.Logger.File.Filename != "/var/log/minio.log" {
+		t.Errorf("Unexpected file logger %v", srvCfg.Logger.File)
+	}
+	if srvCfg.Notify.AMQP["1"].RoutingKey != "minio" {
+		t.Errorf("Unexpected AMQP target %v", srvCfg.Notify.AMQP["1"])
+	}
+	nats := srvCfg.Notify.NATS["1"]
+	if nats.Streaming.MaxPubAcksInflight != 10 || nats.PingInterval != 30 {
+		t.Errorf("Unexpected NATS target %v", nats)
+	}
+	expected := kafkaNotify{Enable: true, Brokers: []string{"kafka1:9092", "kafka2:9092"}}
+	if !reflect.DeepEqual(srvCfg.Notify.Kafka["prod"], expected) {
+		t.Errorf("Expected Kafka target %v, got %v", expected, srvCfg.Notify.Kafka["prod"])
+	}
+	if len(srvCfg.Notify.Kafka) != 2 {
+		t.Errorf("Expected existing Kafka target to be kept, got %v", srvCfg.Notify.Kafka)
+	}
+}
@@ -0,0 +1,425 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variable with comma separated list of etcd endpoints,
+// the config is stored in etcd instead of the config dir when set.
+const etcdEndpointsEnv = "MINIO_ETCD_ENDPOINTS"
+
+// Environment variables with the TLS client certificate and key, and
+// the user and password of the etcd clusters, if they require them.
+const (
+	etcdClientCertEnv    = "MINIO_ETCD_CLIENT_CERT"
+	etcdClientCertKeyEnv = "MINIO_ETCD_CLIENT_CERT_KEY"
+	etcdUsernameEnv      = "MINIO_ETCD_USERNAME"
+	etcdPasswordEnv      = "MINIO_ETCD_PASSWORD"
+)
+
+// Keys of the server config and of the IAM config in etcd.
+const (
+	etcdConfigKey    = "minio/config/config.json"
+	etcdIAMConfigKey = "minio/config/iam.json"
+)
+
+// errEtcdKeyNotFound - the key does not exist in etcd.
+var errEtcdKeyNotFound = errors.New("etcd key not found")
+
+// etcdClient - minimal client of the etcd v3 JSON gateway, tries all
+// the endpoints in order until one of them answers.
+type etcdClient struct {
+	endpoints  []string
+	httpClient *http.Client
+
+	// Credentials of the etcd user, if authentication is enabled,
+	// and the auth token fetched with them.
+	username string
+	password string
+	tokenMu  sync.Mutex
+	token    string
+}
+
+// etcdTLSConfig - returns the TLS configuration of https:// endpoints,
+// with the client certificate of the environment if any.
+func etcdTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{RootCAs: globalRootCAs}
+	certFile, keyFile := os.Getenv(etcdClientCertEnv), os.Getenv(etcdClientCertKeyEnv)
+	if certFile == "" && keyFile == "" {
+		return config, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load etcd client certificate. %s", err)
+	}
+	config.Certificates = []tls.Certificate{cert}
+	return config, nil
+}
+
+// newEtcdClient - returns a client for the comma separated endpoints,
+// authenticating with the client certificate and the user of the
+// environment if any.
+func newEtcdClient(endpoints string) (*etcdClient, error) {
+	tlsConfig, err := etcdTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	client := &etcdClient{
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		username: os.Getenv(etcdUsernameEnv),
+		password: os.Getenv(etcdPasswordEnv),
+	}
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		u, err := url.Parse(strings.TrimSpace(endpoint))
+		if err != nil {
+			return nil, err
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("Invalid etcd endpoint ‘%s’", endpoint)
+		}
+		client.endpoints = append(client.endpoints, strings.TrimSuffix(u.String(), "/"))
+	}
+	return client, nil
+}
+
+// etcdKeyValue - key value pair as returned by the JSON gateway,
+// bytes are base64 encoded and revisions are strings.
+type etcdKeyValue struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value,omitempty"`
+	ModRevision int64  `json:"mod_revision,string,omitempty"`
+}
+
+// post - posts reqBytes to the gateway method of endpoint, returns
+// the reply along with its status code.
+func (c *etcdClient) post(endpoint, method string, reqBytes []byte, token string) ([]byte, int, error) {
+	req, err := http.NewRequest("POST", endpoint+"/v3/"+method, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	return respBytes, resp.StatusCode, err
+}
+
+// authenticate - fetches a new auth token of the etcd user from
+// endpoint.
+func (c *etcdClient) authenticate(endpoint string) (string, error) {
+	reqBytes, err := json.Marshal(map[string]string{"name": c.username, "password": c.password})
+	if err != nil {
+		return "", err
+	}
+	respBytes, status, err := c.post(endpoint, "auth/authenticate", reqBytes, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("etcd authentication of ‘%s’ failed with %d: %s", c.username, status, respBytes)
+	}
+	var response struct {
+		Token string `json:"token"`
+	}
+	if err = json.Unmarshal(respBytes, &response); err != nil {
+		return "", err
+	}
+
+	c.tokenMu.Lock()
+	c.token = response.Token
+	c.tokenMu.Unlock()
+	return response.Token, nil
+}
+
+// call - posts request to the gateway method, decoding the reply
+// into response.
+func (c *etcdClient) call(method string, request, response interface{}) error {
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range c.endpoints {
+		c.tokenMu.Lock()
+		token := c.token
+		c.tokenMu.Unlock()
+		if c.username != "" && token == "" {
+			if token, err = c.authenticate(endpoint); err != nil {
+				// Try the next endpoint.
+				continue
+			}
+		}
+
+		var respBytes []byte
+		var status int
+		respBytes, status, err = c.post(endpoint, method, reqBytes, token)
+		if err == nil && status == http.StatusUnauthorized && c.username != "" {
+			// Auth tokens expire, fetch a new one once.
+			if token, err = c.authenticate(endpoint); err == nil {
+				respBytes, status, err = c.post(endpoint, method, reqBytes, token)
+			}
+		}
+		if err != nil {
+			// Try the next endpoint.
+			continue
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("etcd %s failed with %d: %s", method, status, respBytes)
+		}
+		return json.Unmarshal(respBytes, response)
+	}
+	return err
+}
+
+// getKeyValue - returns the key value pair of key.
+func (c *etcdClient) getKeyValue(key string) (etcdKeyValue, error) {
+	var response struct {
+		Kvs []etcdKeyValue `json:"kvs"`
+	}
+	if err := c.call("kv/range", etcdKeyValue{Key: []byte(key)}, &response); err != nil {
+		return etcdKeyValue{}, err
+	}
+	if len(response.Kvs) == 0 {
+		return etcdKeyValue{}, errEtcdKeyNotFound
+	}
+	return response.Kvs[0], nil
+}
+
+// Get - returns the value of key.
+func (c *etcdClient) Get(key string) ([]byte, error) {
+	kv, err := c.getKeyValue(key)
+	return kv.Value, err
+}
+
+// etcdRange - range of keys, from key to range end excluded.
+type etcdRange struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+// prefixRange - range of all the keys with prefix.
+func prefixRange(prefix string) etcdRange {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return etcdRange{Key: []byte(prefix), RangeEnd: end[:i+1]}
+		}
+	}
+	// All the keys from prefix.
+	return etcdRange{Key: []byte(prefix), RangeEnd: []byte{0}}
+}
+
+// List - returns the key value pairs of all the keys with prefix.
+func (c *etcdClient) List(prefix string) ([]etcdKeyValue, error) {
+	var response struct {
+		Kvs []etcdKeyValue `json:"kvs"`
+	}
+	if err := c.call("kv/range", prefixRange(prefix), &response); err != nil {
+		return nil, err
+	}
+	return response.Kvs, nil
+}
+
+// DeletePrefix - deletes all the keys with prefix.
+func (c *etcdClient) DeletePrefix(prefix string) error {
+	var response struct{}
+	return c.call("kv/deleterange", prefixRange(prefix), &response)
+}
+
+// Delete - deletes key.
+func (c *etcdClient) Delete(key string) error {
+	var response struct{}
+	return c.call("kv/deleterange", etcdRange{Key: []byte(key)}, &response)
+}
+
+// Put - sets the value of key.
+func (c *etcdClient) Put(key string, value []byte) error {
+	var response struct{}
+	return c.call("kv/put", etcdKeyValue{Key: []byte(key), Value: value}, &response)
+}
+
+// etcdCompare - condition of a transaction on a revision of a key, a
+// key which does not exist has all its revisions 0.
+type etcdCompare struct {
+	Key            []byte `json:"key"`
+	Result         string `json:"result"`
+	Target         string `json:"target"`
+	CreateRevision int64  `json:"create_revision,omitempty"`
+	ModRevision    int64  `json:"mod_revision,omitempty"`
+}
+
+// txnPut - sets the value of key only if compare holds, indicates
+// whether it was set.
+func (c *etcdClient) txnPut(key string, value []byte, compare etcdCompare) (bool, error) {
+	type etcdRequestOp struct {
+		RequestPut etcdKeyValue `json:"request_put"`
+	}
+	request := struct {
+		Compare []etcdCompare   `json:"compare"`
+		Success []etcdRequestOp `json:"success"`
+	}{
+		Compare: []etcdCompare{compare},
+		Success: []etcdRequestOp{{RequestPut: etcdKeyValue{Key: []byte(key), Value: value}}},
+	}
+	var response struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	if err := c.call("kv/txn", request, &response); err != nil {
+		return false, err
+	}
+	return response.Succeeded, nil
+}
+
+// Create - sets the value of key only if it does not exist yet,
+// indicates whether it was set.
+func (c *etcdClient) Create(key string, value []byte) (bool, error) {
+	return c.txnPut(key, value, etcdCompare{Key: []byte(key), Result: "EQUAL", Target: "CREATE"})
+}
+
+// CompareAndPut - sets the value of key only if it was not modified
+// since modRevision, 0 if it did not exist. Indicates whether it was
+// set.
+func (c *etcdClient) CompareAndPut(key string, value []byte, modRevision int64) (bool, error) {
+	return c.txnPut(key, value, etcdCompare{Key: []byte(key), Result: "EQUAL", Target: "MOD", ModRevision: modRevision})
+}
+
+// String - etcd endpoints, used in messages.
+func (c *etcdClient) String() string {
+	return strings.Join(c.endpoints, ",")
+}
+
+// loadConfigEtcd - loads the server config from etcd, creating it if
+// it is not stored yet. Indicates whether the config was created.
+func loadConfigEtcd() (bool, error) {
+	configBytes, err := globalEtcdClient.Get(etcdConfigKey)
+	if err == errEtcdKeyNotFound {
+		srvCfg := newServerConfigV17()
+		if configBytes, err = json.MarshalIndent(srvCfg, "", "\t"); err != nil {
+			return false, err
+		}
+		// Servers starting at the same time race to create the
+		// config, all of them use the one created first.
+		var created bool
+		created, err = globalEtcdClient.Create(etcdConfigKey, configBytes)
+		if err != nil {
+			return false, err
+		}
+		if created {
+			serverConfigMu.Lock()
+			serverConfig = srvCfg
+			serverConfigMu.Unlock()
+			return true, nil
+		}
+		configBytes, err = globalEtcdClient.Get(etcdConfigKey)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	srvCfg := &serverConfigV17{}
+	if err = json.Unmarshal(configBytes, srvCfg); err != nil {
+		return false, fmt.Errorf("Unable to parse config in etcd. %s", err)
+	}
+	// Config in etcd is only written by releases which already
+	// migrated it.
+	if srvCfg.Version != globalMinioConfigVersion {
+		return false, fmt.Errorf("Config version ‘%s’ in etcd is not supported by this release", srvCfg.Version)
+	}
+
+	serverConfigMu.Lock()
+	serverConfig = srvCfg
+	serverConfigMu.Unlock()
+	return false, nil
+}
+
+// saveConfigEtcd - stores the server config in etcd.
+func saveConfigEtcd(srvCfg *serverConfigV17) error {
+	configBytes, err := json.MarshalIndent(srvCfg, "", "\t")
+	if err != nil {
+		return err
+	}
+	return globalEtcdClient.Put(etcdConfigKey, configBytes)
+}
+
+// readIAMConfigEtcd - reads the IAM config from etcd along with its
+// revision, a cluster without a stored config has no users.
+func readIAMConfigEtcd() (iamConfig, int64, error) {
+	kv, err := globalEtcdClient.getKeyValue(etcdIAMConfigKey)
+	if err == errEtcdKeyNotFound {
+		return newIAMConfig(), 0, nil
+	}
+	if err != nil {
+		errorIf(err, "Unable to load IAM config from etcd.")
+		return newIAMConfig(), 0, err
+	}
+	config, err := parseIAMConfig(kv.Value)
+	if err != nil {
+		errorIf(err, "Unable to parse IAM config in etcd.")
+		return config, 0, err
+	}
+	return config, kv.ModRevision, nil
+}
+
+// updateIAMConfigEtcd - applies fn to the IAM config in etcd and saves
+// the result, starting over if another server changed the config
+// meanwhile. Returns the saved config.
+func updateIAMConfigEtcd(fn func(config *iamConfig) error) (iamConfig, error) {
+	for {
+		config, revision, err := readIAMConfigEtcd()
+		if err != nil {
+			return config, err
+		}
+		if err = fn(&config); err != nil {
+			return config, err
+		}
+		buf, err := json.Marshal(config)
+		if err != nil {
+			return config, err
+		}
+		saved, err := globalEtcdClient.CompareAndPut(etcdIAMConfigKey, buf, revision)
+		if err != nil {
+			errorIf(err, "Unable to save IAM config to etcd.")
+			return config, err
+		}
+		if saved {
+			return config, nil
+		}
+	}
+}
@@ -0,0 +1,412 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// fakeEtcdGateway - in-memory implementation of the subset of the
+// etcd v3 JSON gateway used by etcdClient.
+type fakeEtcdGateway struct {
+	mu   sync.Mutex
+	kvs  map[string][]byte
+	revs map[string]int64
+	rev  int64
+	puts int
+
+	// Password of user root when authentication is enabled, and the
+	// currently valid token.
+	password string
+	token    string
+	authN    int
+}
+
+// put - sets the value of key at the next revision.
+func (g *fakeEtcdGateway) put(key string, value []byte) {
+	if g.revs == nil {
+		g.revs = make(map[string]int64)
+	}
+	g.rev++
+	g.kvs[key] = value
+	g.revs[key] = g.rev
+	g.puts++
+}
+
+func (g *fakeEtcdGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.password != "" {
+		if r.URL.Path == "/v3/auth/authenticate" {
+			var req struct {
+				Name     string `json:"name"`
+				Password string `json:"password"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name != "root" || req.Password != g.password {
+				http.Error(w, "authentication failed", http.StatusBadRequest)
+				return
+			}
+			g.authN++
+			g.token = fmt.Sprintf("token-%d", g.authN)
+			json.NewEncoder(w).Encode(map[string]string{"token": g.token})
+			return
+		}
+		if r.Header.Get("Authorization") != g.token {
+			http.Error(w, "invalid auth token", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var response interface{}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		var req etcdRange
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var kvs []etcdKeyValue
+		for _, key := range g.keys(req) {
+			kvs = append(kvs, etcdKeyValue{Key: []byte(key), Value: g.kvs[key], ModRevision: g.revs[key]})
+		}
+		response = map[string]interface{}{"kvs": kvs}
+	case "/v3/kv/deleterange":
+		var req etcdRange
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, key := range g.keys(req) {
+			delete(g.kvs, key)
+			delete(g.revs, key)
+		}
+		response = map[string]interface{}{}
+	case "/v3/kv/put":
+		var req etcdKeyValue
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g.put(string(req.Key), req.Value)
+		response = map[string]interface{}{}
+	case "/v3/kv/txn":
+		var req struct {
+			Compare []etcdCompare `json:"compare"`
+			Success []struct {
+				RequestPut etcdKeyValue `json:"request_put"`
+			} `json:"success"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		compare := req.Compare[0]
+		_, exists := g.kvs[string(compare.Key)]
+		var succeeded bool
+		switch compare.Target {
+		case "CREATE":
+			succeeded = !exists
+		case "MOD":
+			succeeded = g.revs[string(compare.Key)] == compare.ModRevision
+		}
+		if succeeded {
+			put := req.Success[0].RequestPut
+			g.put(string(put.Key), put.Value)
+		}
+		response = map[string]interface{}{"succeeded": succeeded}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// keys - returns the sorted keys in the range.
+func (g *fakeEtcdGateway) keys(req etcdRange) []string {
+	key, end := string(req.Key), string(req.RangeEnd)
+	var keys []string
+	for k := range g.kvs {
+		switch {
+		case len(req.RangeEnd) == 0:
+			if k != key {
+				continue
+			}
+		case k < key:
+			continue
+		case end != "\x00" && k >= end:
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Tests parsing etcd endpoints.
+func TestNewEtcdClient(t *testing.T) {
+	testCases := []struct {
+		endpoints   string
+		shouldPass  bool
+		numEndpoint int
+	}{
+		{"http://localhost:2379", true, 1},
+		{"http://etcd1:2379/, https://etcd2:2379", true, 2},
+		{"localhost:2379", false, 0},
+		{"ftp://localhost:2379", false, 0},
+		{"http://", false, 0},
+	}
+	for i, testCase := range testCases {
+		client, err := newEtcdClient(testCase.endpoints)
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+			continue
+		}
+		if err == nil && len(client.endpoints) != testCase.numEndpoint {
+			t.Errorf("Test %d: Expected %d endpoints, got %v", i+1, testCase.numEndpoint, client.endpoints)
+		}
+	}
+}
+
+// Tests creating, loading and saving the server config in etcd.
+func TestConfigEtcd(t *testing.T) {
+	gateway := &fakeEtcdGateway{kvs: make(map[string][]byte)}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	client, err := newEtcdClient("http://127.0.0.1:1," + server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalEtcdClient = client
+	defer func() { globalEtcdClient = nil }()
+
+	if _, err = client.Get(etcdConfigKey); err != errEtcdKeyNotFound {
+		t.Fatalf("Expected %v, got %v", errEtcdKeyNotFound, err)
+	}
+
+	created, err := initConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Fatal("Expected config to be created")
+	}
+	cred := serverConfig.GetCredential()
+
+	// Config created by another server is not overwritten.
+	if created, err = client.Create(etcdConfigKey, []byte("{}")); err != nil || created {
+		t.Fatalf("Expected existing key not to be created, got %v %v", created, err)
+	}
+
+	serverConfig.SetRegion("us-west-1")
+	if err = serverConfig.Save(); err != nil {
+		t.Fatal(err)
+	}
+	serverConfig = nil
+
+	created, err = initConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Fatal("Expected config to be loaded")
+	}
+	if serverConfig.GetRegion() != "us-west-1" {
+		t.Fatalf("Expected region us-west-1, got %s", serverConfig.GetRegion())
+	}
+	if serverConfig.GetCredential() != cred {
+		t.Fatalf("Expected credential %v, got %v", cred, serverConfig.GetCredential())
+	}
+	if gateway.puts != 2 {
+		t.Fatalf("Expected 2 writes to etcd, got %d", gateway.puts)
+	}
+
+	// Config of an unsupported version.
+	if err = client.Put(etcdConfigKey, []byte(`{"version": "1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = initConfig(); err == nil {
+		t.Fatal("Expected unsupported config version to fail")
+	}
+}
+
+// Tests authenticating with the etcd user and renewing expired tokens.
+func TestEtcdClientAuth(t *testing.T) {
+	gateway := &fakeEtcdGateway{kvs: make(map[string][]byte), password: "secret"}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	os.Setenv(etcdUsernameEnv, "root")
+	defer os.Unsetenv(etcdUsernameEnv)
+	os.Setenv(etcdPasswordEnv, "secret")
+	defer os.Unsetenv(etcdPasswordEnv)
+
+	client, err := newEtcdClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Token expired.
+	gateway.mu.Lock()
+	gateway.token = "expired"
+	gateway.mu.Unlock()
+	value, err := client.Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "value" {
+		t.Fatalf("Expected value, got %s", value)
+	}
+	if gateway.authN != 2 {
+		t.Fatalf("Expected 2 authentications, got %d", gateway.authN)
+	}
+
+	client.password = "wrong"
+	client.token = ""
+	if _, err = client.Get("key"); err == nil {
+		t.Fatal("Expected a wrong password to fail")
+	}
+}
+
+// Tests connecting to etcd requiring TLS client certificates.
+func TestEtcdClientTLS(t *testing.T) {
+	rootPath, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	certPEM, keyPEM, err := generateTLSCertKey("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(rootPath, "client.crt"), filepath.Join(rootPath, "client.key")
+	if err = ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(&fakeEtcdGateway{kvs: make(map[string][]byte)})
+	// The test certificate is only valid for servers, etcd is only
+	// checked to receive it.
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	savedRootCAs := globalRootCAs
+	defer func() { globalRootCAs = savedRootCAs }()
+	globalRootCAs = x509.NewCertPool()
+	globalRootCAs.AddCert(server.Certificate())
+
+	// Without a client certificate.
+	client, err := newEtcdClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Put("key", []byte("value")); err == nil {
+		t.Fatal("Expected etcd to require a client certificate")
+	}
+
+	os.Setenv(etcdClientCertEnv, certFile)
+	defer os.Unsetenv(etcdClientCertEnv)
+	os.Setenv(etcdClientCertKeyEnv, filepath.Join(rootPath, "missing.key"))
+	defer os.Unsetenv(etcdClientCertKeyEnv)
+	if _, err = newEtcdClient(server.URL); err == nil {
+		t.Fatal("Expected a missing client key to fail")
+	}
+
+	os.Setenv(etcdClientCertKeyEnv, keyFile)
+	if client, err = newEtcdClient(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests storing the IAM config in etcd.
+func TestIAMConfigEtcd(t *testing.T) {
+	gateway := &fakeEtcdGateway{kvs: make(map[string][]byte)}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	client, err := newEtcdClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalEtcdClient = client
+	defer func() { globalEtcdClient = nil }()
+	resetGlobalIAMSys()
+	defer resetGlobalIAMSys()
+
+	// The backend is not used with etcd.
+	if err = globalIAMSys.Load(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUser(nil, "myuser", "mysecretkey"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changed meanwhile by another deployment.
+	var conflicts int
+	err = globalIAMSys.update(nil, func(config *iamConfig) error {
+		if conflicts == 0 {
+			conflicts++
+			other, _, rerr := readIAMConfigEtcd()
+			if rerr != nil {
+				return rerr
+			}
+			other.Users["otheruser"] = iamUser{SecretKey: "othersecretkey", Status: iamUserEnabled}
+			buf, _ := json.Marshal(other)
+			if rerr = client.Put(etcdIAMConfigKey, buf); rerr != nil {
+				return rerr
+			}
+		}
+		user := config.Users["myuser"]
+		user.Quota = 1024
+		config.Users["myuser"] = user
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetGlobalIAMSys()
+	if err = globalIAMSys.Load(nil); err != nil {
+		t.Fatal(err)
+	}
+	users := globalIAMSys.ListUsers()
+	if len(users) != 2 || users["myuser"].Quota != 1024 || users["otheruser"].Status != iamUserEnabled {
+		t.Fatalf("Unexpected users %v", users)
+	}
+}
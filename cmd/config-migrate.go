@@ -74,6 +74,22 @@ func migrateConfig() error {
 	if err := migrateV12ToV13(); err != nil {
 		return err
 	}
+	// Migration version '13' to '14'.
+	if err := migrateV13ToV14(); err != nil {
+		return err
+	}
+	// Migration version '14' to '15'.
+	if err := migrateV14ToV15(); err != nil {
+		return err
+	}
+	// Migration version '15' to '16'.
+	if err := migrateV15ToV16(); err != nil {
+		return err
+	}
+	// Migration version '16' to '17'.
+	if err := migrateV16ToV17(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -934,3 +950,247 @@ func migrateV12ToV13() error {
 	)
 	return nil
 }
+
+// Version '13' to '14' migration. Adds support for per bucket regions.
+func migrateV13ToV14() error {
+	cv13, err := loadConfigV13()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘13’. %v", err)
+	}
+	if cv13.Version != "13" {
+		return nil
+	}
+
+	// Copy over fields from V13 into V14 config struct
+	srvConfig := &serverConfigV14{}
+	srvConfig.Version = "14"
+	srvConfig.Credential = cv13.Credential
+	srvConfig.Region = cv13.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Logger = cv13.Logger
+	srvConfig.Notify = cv13.Notify
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv13.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv13.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '14' to '15' migration. Adds support for NSQ notification.
+func migrateV14ToV15() error {
+	cv14, err := loadConfigV14()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘14’. %v", err)
+	}
+	if cv14.Version != "14" {
+		return nil
+	}
+
+	// Copy over fields from V14 into V15 config struct
+	srvConfig := &serverConfigV15{}
+	srvConfig.Version = "15"
+	srvConfig.Credential = cv14.Credential
+	srvConfig.Region = cv14.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.BucketRegions = cv14.BucketRegions
+	srvConfig.Logger = cv14.Logger
+	srvConfig.Notify.AMQP = cv14.Notify.AMQP
+	srvConfig.Notify.NATS = cv14.Notify.NATS
+	srvConfig.Notify.ElasticSearch = cv14.Notify.ElasticSearch
+	srvConfig.Notify.Redis = cv14.Notify.Redis
+	srvConfig.Notify.PostgreSQL = cv14.Notify.PostgreSQL
+	srvConfig.Notify.Kafka = cv14.Notify.Kafka
+	srvConfig.Notify.Webhook = cv14.Notify.Webhook
+
+	// V14 will not have an NSQ config. So we initialize one here.
+	srvConfig.Notify.NSQ = make(map[string]nsqNotify)
+	srvConfig.Notify.NSQ["1"] = nsqNotify{}
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv14.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv14.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '15' to '16' migration. Adds support for Google Cloud Pub/Sub notification.
+func migrateV15ToV16() error {
+	cv15, err := loadConfigV15()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘15’. %v", err)
+	}
+	if cv15.Version != "15" {
+		return nil
+	}
+
+	// Copy over fields from V15 into V16 config struct
+	srvConfig := &serverConfigV16{}
+	srvConfig.Version = "16"
+	srvConfig.Credential = cv15.Credential
+	srvConfig.Region = cv15.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.BucketRegions = cv15.BucketRegions
+	srvConfig.Logger = cv15.Logger
+	srvConfig.Notify.AMQP = cv15.Notify.AMQP
+	srvConfig.Notify.NATS = cv15.Notify.NATS
+	srvConfig.Notify.ElasticSearch = cv15.Notify.ElasticSearch
+	srvConfig.Notify.Redis = cv15.Notify.Redis
+	srvConfig.Notify.PostgreSQL = cv15.Notify.PostgreSQL
+	srvConfig.Notify.Kafka = cv15.Notify.Kafka
+	srvConfig.Notify.Webhook = cv15.Notify.Webhook
+	srvConfig.Notify.NSQ = cv15.Notify.NSQ
+
+	// V15 will not have a Pub/Sub config. So we initialize one here.
+	srvConfig.Notify.PubSub = make(map[string]pubsubNotify)
+	srvConfig.Notify.PubSub["1"] = pubsubNotify{}
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv15.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv15.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '16' to '17' migration. Adds support for Azure Event Hubs notification.
+func migrateV16ToV17() error {
+	cv16, err := loadConfigV16()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘16’. %v", err)
+	}
+	if cv16.Version != "16" {
+		return nil
+	}
+
+	// Copy over fields from V16 into V17 config struct
+	srvConfig := &serverConfigV17{}
+	srvConfig.Version = "17"
+	srvConfig.Credential = cv16.Credential
+	srvConfig.Region = cv16.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.BucketRegions = cv16.BucketRegions
+	srvConfig.Logger = cv16.Logger
+	srvConfig.Notify.AMQP = cv16.Notify.AMQP
+	srvConfig.Notify.NATS = cv16.Notify.NATS
+	srvConfig.Notify.ElasticSearch = cv16.Notify.ElasticSearch
+	srvConfig.Notify.Redis = cv16.Notify.Redis
+	srvConfig.Notify.PostgreSQL = cv16.Notify.PostgreSQL
+	srvConfig.Notify.Kafka = cv16.Notify.Kafka
+	srvConfig.Notify.Webhook = cv16.Notify.Webhook
+	srvConfig.Notify.NSQ = cv16.Notify.NSQ
+	srvConfig.Notify.PubSub = cv16.Notify.PubSub
+
+	// V16 will not have an Event Hubs config. So we initialize one here.
+	srvConfig.Notify.EventHubs = make(map[string]eventHubsNotify)
+	srvConfig.Notify.EventHubs["1"] = eventHubsNotify{}
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv16.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv16.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
@@ -106,6 +106,18 @@ func TestServerConfigMigrateInexistentConfig(t *testing.T) {
 	if err := migrateV12ToV13(); err != nil {
 		t.Fatal("migrate v12 to v13 should succeed when no config file is found")
 	}
+	if err := migrateV13ToV14(); err != nil {
+		t.Fatal("migrate v13 to v14 should succeed when no config file is found")
+	}
+	if err := migrateV14ToV15(); err != nil {
+		t.Fatal("migrate v14 to v15 should succeed when no config file is found")
+	}
+	if err := migrateV15ToV16(); err != nil {
+		t.Fatal("migrate v15 to v16 should succeed when no config file is found")
+	}
+	if err := migrateV16ToV17(); err != nil {
+		t.Fatal("migrate v16 to v17 should succeed when no config file is found")
+	}
 }
 
 // Test if a config migration from v2 to v12 is successfully done
@@ -218,4 +230,16 @@ func TestServerConfigMigrateFaultyConfig(t *testing.T) {
 	if err := migrateV12ToV13(); err == nil {
 		t.Fatal("migrateConfigV12ToV13() should fail with a corrupted json")
 	}
+	if err := migrateV13ToV14(); err == nil {
+		t.Fatal("migrateConfigV13ToV14() should fail with a corrupted json")
+	}
+	if err := migrateV14ToV15(); err == nil {
+		t.Fatal("migrateConfigV14ToV15() should fail with a corrupted json")
+	}
+	if err := migrateV15ToV16(); err == nil {
+		t.Fatal("migrateConfigV15ToV16() should fail with a corrupted json")
+	}
+	if err := migrateV16ToV17(); err == nil {
+		t.Fatal("migrateConfigV16ToV17() should fail with a corrupted json")
+	}
 }
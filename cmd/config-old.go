@@ -347,6 +347,45 @@ type notifierV2 struct {
 	Kafka         map[string]kafkaNotify         `json:"kafka"`
 }
 
+// Notifier represents collection of supported notification queues in version 3
+// with webhook but without NSQ.
+type notifierV3 struct {
+	AMQP          map[string]amqpNotify          `json:"amqp"`
+	NATS          map[string]natsNotify          `json:"nats"`
+	ElasticSearch map[string]elasticSearchNotify `json:"elasticsearch"`
+	Redis         map[string]redisNotify         `json:"redis"`
+	PostgreSQL    map[string]postgreSQLNotify    `json:"postgresql"`
+	Kafka         map[string]kafkaNotify         `json:"kafka"`
+	Webhook       map[string]webhookNotify       `json:"webhook"`
+}
+
+// Notifier represents collection of supported notification queues in version 4
+// with NSQ but without Google Cloud Pub/Sub.
+type notifierV4 struct {
+	AMQP          map[string]amqpNotify          `json:"amqp"`
+	NATS          map[string]natsNotify          `json:"nats"`
+	ElasticSearch map[string]elasticSearchNotify `json:"elasticsearch"`
+	Redis         map[string]redisNotify         `json:"redis"`
+	PostgreSQL    map[string]postgreSQLNotify    `json:"postgresql"`
+	Kafka         map[string]kafkaNotify         `json:"kafka"`
+	Webhook       map[string]webhookNotify       `json:"webhook"`
+	NSQ           map[string]nsqNotify           `json:"nsq"`
+}
+
+// Notifier represents collection of supported notification queues in version 5
+// with Google Cloud Pub/Sub but without Azure Event Hubs.
+type notifierV5 struct {
+	AMQP          map[string]amqpNotify          `json:"amqp"`
+	NATS          map[string]natsNotify          `json:"nats"`
+	ElasticSearch map[string]elasticSearchNotify `json:"elasticsearch"`
+	Redis         map[string]redisNotify         `json:"redis"`
+	PostgreSQL    map[string]postgreSQLNotify    `json:"postgresql"`
+	Kafka         map[string]kafkaNotify         `json:"kafka"`
+	Webhook       map[string]webhookNotify       `json:"webhook"`
+	NSQ           map[string]nsqNotify           `json:"nsq"`
+	PubSub        map[string]pubsubNotify        `json:"pubsub"`
+}
+
 // configV7 server configuration version '7'.
 type serverConfigV7 struct {
 	Version string `json:"version"`
@@ -586,3 +625,156 @@ func loadConfigV12() (*serverConfigV12, error) {
 	}
 	return srvCfg, nil
 }
+
+// serverConfigV13 server configuration version '13' which is like
+// version '12' except it adds support for webhook notification.
+type serverConfigV13 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifierV3 `json:"notify"`
+}
+
+func loadConfigV13() (*serverConfigV13, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV13{}
+	srvCfg.Version = "13"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV14 server configuration version '14' which is like
+// version '13' except it adds support for per bucket regions.
+type serverConfigV14 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Regions other than Region buckets can be created in.
+	BucketRegions []string `json:"bucketRegions"`
+
+	// Additional error logging configuration.
+	Logger logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifierV3 `json:"notify"`
+}
+
+func loadConfigV14() (*serverConfigV14, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV14{}
+	srvCfg.Version = "14"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV15 server configuration version '15' which is like
+// version '14' except it adds support for NSQ notification.
+type serverConfigV15 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Regions other than Region buckets can be created in.
+	BucketRegions []string `json:"bucketRegions"`
+
+	// Additional error logging configuration.
+	Logger logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifierV4 `json:"notify"`
+}
+
+func loadConfigV15() (*serverConfigV15, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV15{}
+	srvCfg.Version = "15"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV16 server configuration version '16' which is like
+// version '15' except it adds support for Google Cloud Pub/Sub notification.
+type serverConfigV16 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Regions other than Region buckets can be created in.
+	BucketRegions []string `json:"bucketRegions"`
+
+	// Additional error logging configuration.
+	Logger logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifierV5 `json:"notify"`
+}
+
+func loadConfigV16() (*serverConfigV16, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV16{}
+	srvCfg.Version = "16"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// errConfigNotFound - the config to reload does not exist anymore.
+var errConfigNotFound = errors.New("config file not found")
+
+// Serializes config reloads triggered by SIGHUP and the admin API.
+var configReloadMu sync.Mutex
+
+// reloadConfig - re-reads the config from the config dir or etcd and
+// applies it without restarting, re-initializing the loggers and the
+// notification targets. Credentials in effect are kept unless
+// reloadCredentials is set. On error the previous config stays in
+// effect.
+func reloadConfig(reloadCredentials bool) (err error) {
+	configReloadMu.Lock()
+	defer configReloadMu.Unlock()
+
+	if globalEtcdClient == nil && !isConfigFileExists() {
+		return errConfigNotFound
+	}
+
+	serverConfigMu.RLock()
+	prevConfig := serverConfig
+	serverConfigMu.RUnlock()
+	defer func() {
+		if err != nil {
+			serverConfigMu.Lock()
+			serverConfig = prevConfig
+			serverConfigMu.Unlock()
+		}
+	}()
+
+	// Loads the config in serverConfig.
+	if _, err = initConfig(); err != nil {
+		return err
+	}
+
+	// Environment has the same precedence as on startup.
+	serverConfigMu.Lock()
+	err = overrideConfigFromEnv(serverConfig, os.Environ())
+	serverConfigMu.Unlock()
+	if err != nil {
+		return err
+	}
+	cred := serverConfig.GetCredential()
+	if globalEnvAccessKey != "" && globalEnvSecretKey != "" {
+		cred = credential{
+			AccessKey: globalEnvAccessKey,
+			SecretKey: globalEnvSecretKey,
+		}
+	} else if !reloadCredentials {
+		cred = prevConfig.GetCredential()
+	}
+	if _, err = getCredential(cred.AccessKey, cred.SecretKey); err != nil {
+		return err
+	}
+	serverConfig.SetCredential(cred)
+
+	// Validate the loggers, enabling them fails fatally.
+	if err = checkConsoleLogger(serverConfig.GetConsoleLogger()); err != nil {
+		return err
+	}
+	if err = checkFileLogger(serverConfig.GetFileLogger()); err != nil {
+		return err
+	}
+
+	// Connect to the notification targets before replacing them.
+	var queueTargets map[string]*logrus.Logger
+	if globalEventNotifier != nil {
+		if queueTargets, err = loadAllQueueTargets(); err != nil {
+			return err
+		}
+	}
+
+	disableLoggers()
+	enableLoggers()
+	if globalEventNotifier != nil {
+		globalEventNotifier.SetExternalTargets(queueTargets)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests reloading the config from the config dir.
+func TestReloadConfig(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	savedCred := serverConfig.GetCredential()
+	serverConfig.SetRegion("us-west-1")
+	if err = serverConfig.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes in memory only.
+	activeCred := credential{AccessKey: "minioactive", SecretKey: "minioactive123"}
+	serverConfig.SetRegion(globalMinioDefaultRegion)
+	serverConfig.SetCredential(activeCred)
+
+	// Credentials in effect are kept.
+	if err = reloadConfig(false); err != nil {
+		t.Fatal(err)
+	}
+	if serverConfig.GetRegion() != "us-west-1" {
+		t.Fatalf("Expected region us-west-1, got %s", serverConfig.GetRegion())
+	}
+	if serverConfig.GetCredential() != activeCred {
+		t.Fatalf("Expected credential %v, got %v", activeCred, serverConfig.GetCredential())
+	}
+
+	// Credentials are reloaded too.
+	if err = reloadConfig(true); err != nil {
+		t.Fatal(err)
+	}
+	if serverConfig.GetCredential() != savedCred {
+		t.Fatalf("Expected credential %v, got %v", savedCred, serverConfig.GetCredential())
+	}
+
+	// Invalid config is not applied.
+	serverConfig.Logger.Console = consoleLogger{Enable: true, Level: "verbose"}
+	if err = serverConfig.Save(); err != nil {
+		t.Fatal(err)
+	}
+	serverConfig.Logger.Console = consoleLogger{}
+	serverConfig.SetRegion(globalMinioDefaultRegion)
+	if err = reloadConfig(false); err == nil {
+		t.Fatal("Expected invalid log level to fail")
+	}
+	if serverConfig.GetRegion() != globalMinioDefaultRegion {
+		t.Fatalf("Expected previous config to stay in effect, got region %s", serverConfig.GetRegion())
+	}
+
+	// Config removed.
+	removeAll(rootPath)
+	if err = reloadConfig(false); err != errConfigNotFound {
+		t.Fatalf("Expected %v, got %v", errConfigNotFound, err)
+	}
+}
@@ -26,15 +26,18 @@ import (
 // Read Write mutex for safe access to ServerConfig.
 var serverConfigMu sync.RWMutex
 
-// serverConfigV13 server configuration version '13' which is like
-// version '12' except it adds support for webhook notification.
-type serverConfigV13 struct {
+// serverConfigV17 server configuration version '17' which is like
+// version '16' except it adds support for Azure Event Hubs notification.
+type serverConfigV17 struct {
 	Version string `json:"version"`
 
 	// S3 API configuration.
 	Credential credential `json:"credential"`
 	Region     string     `json:"region"`
 
+	// Regions other than Region buckets can be created in.
+	BucketRegions []string `json:"bucketRegions"`
+
 	// Additional error logging configuration.
 	Logger logger `json:"logger"`
 
@@ -42,38 +45,50 @@ type serverConfigV13 struct {
 	Notify notifier `json:"notify"`
 }
 
+// newServerConfigV17 - returns the default server config.
+func newServerConfigV17() *serverConfigV17 {
+	srvCfg := &serverConfigV17{}
+	srvCfg.Version = globalMinioConfigVersion
+	srvCfg.Region = globalMinioDefaultRegion
+	srvCfg.Credential = newCredential()
+
+	// Enable console logger by default on a fresh run.
+	srvCfg.Logger.Console = consoleLogger{
+		Enable: true,
+		Level:  "error",
+	}
+
+	// Make sure to initialize notification configs.
+	srvCfg.Notify.AMQP = make(map[string]amqpNotify)
+	srvCfg.Notify.AMQP["1"] = amqpNotify{}
+	srvCfg.Notify.ElasticSearch = make(map[string]elasticSearchNotify)
+	srvCfg.Notify.ElasticSearch["1"] = elasticSearchNotify{}
+	srvCfg.Notify.Redis = make(map[string]redisNotify)
+	srvCfg.Notify.Redis["1"] = redisNotify{}
+	srvCfg.Notify.NATS = make(map[string]natsNotify)
+	srvCfg.Notify.NATS["1"] = natsNotify{}
+	srvCfg.Notify.PostgreSQL = make(map[string]postgreSQLNotify)
+	srvCfg.Notify.PostgreSQL["1"] = postgreSQLNotify{}
+	srvCfg.Notify.Kafka = make(map[string]kafkaNotify)
+	srvCfg.Notify.Kafka["1"] = kafkaNotify{}
+	srvCfg.Notify.Webhook = make(map[string]webhookNotify)
+	srvCfg.Notify.Webhook["1"] = webhookNotify{}
+	srvCfg.Notify.NSQ = make(map[string]nsqNotify)
+	srvCfg.Notify.NSQ["1"] = nsqNotify{}
+	srvCfg.Notify.PubSub = make(map[string]pubsubNotify)
+	srvCfg.Notify.PubSub["1"] = pubsubNotify{}
+	srvCfg.Notify.EventHubs = make(map[string]eventHubsNotify)
+	srvCfg.Notify.EventHubs["1"] = eventHubsNotify{}
+	return srvCfg
+}
+
 // initConfig - initialize server config and indicate if we are
 // creating a new file or we are just loading
 func initConfig() (bool, error) {
+	if globalEtcdClient != nil {
+		return loadConfigEtcd()
+	}
 	if !isConfigFileExists() {
-		// Initialize server config.
-		srvCfg := &serverConfigV13{}
-		srvCfg.Version = globalMinioConfigVersion
-		srvCfg.Region = globalMinioDefaultRegion
-		srvCfg.Credential = newCredential()
-
-		// Enable console logger by default on a fresh run.
-		srvCfg.Logger.Console = consoleLogger{
-			Enable: true,
-			Level:  "error",
-		}
-
-		// Make sure to initialize notification configs.
-		srvCfg.Notify.AMQP = make(map[string]amqpNotify)
-		srvCfg.Notify.AMQP["1"] = amqpNotify{}
-		srvCfg.Notify.ElasticSearch = make(map[string]elasticSearchNotify)
-		srvCfg.Notify.ElasticSearch["1"] = elasticSearchNotify{}
-		srvCfg.Notify.Redis = make(map[string]redisNotify)
-		srvCfg.Notify.Redis["1"] = redisNotify{}
-		srvCfg.Notify.NATS = make(map[string]natsNotify)
-		srvCfg.Notify.NATS["1"] = natsNotify{}
-		srvCfg.Notify.PostgreSQL = make(map[string]postgreSQLNotify)
-		srvCfg.Notify.PostgreSQL["1"] = postgreSQLNotify{}
-		srvCfg.Notify.Kafka = make(map[string]kafkaNotify)
-		srvCfg.Notify.Kafka["1"] = kafkaNotify{}
-		srvCfg.Notify.Webhook = make(map[string]webhookNotify)
-		srvCfg.Notify.Webhook["1"] = webhookNotify{}
-
 		// Create config path.
 		err := createConfigPath()
 		if err != nil {
@@ -84,7 +99,7 @@ func initConfig() (bool, error) {
 		// Save the new config globally.
 		// unlock the mutex.
 		serverConfigMu.Lock()
-		serverConfig = srvCfg
+		serverConfig = newServerConfigV17()
 		serverConfigMu.Unlock()
 
 		// Save config into file.
@@ -97,7 +112,7 @@ func initConfig() (bool, error) {
 	if _, err = os.Stat(configFile); err != nil {
 		return false, err
 	}
-	srvCfg := &serverConfigV13{}
+	srvCfg := &serverConfigV17{}
 	srvCfg.Version = globalMinioConfigVersion
 	qc, err := quick.New(srvCfg)
 	if err != nil {
@@ -119,10 +134,10 @@ func initConfig() (bool, error) {
 }
 
 // serverConfig server config.
-var serverConfig *serverConfigV13
+var serverConfig *serverConfigV17
 
 // GetVersion get current config version.
-func (s serverConfigV13) GetVersion() string {
+func (s serverConfigV17) GetVersion() string {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -131,14 +146,14 @@ func (s serverConfigV13) GetVersion() string {
 
 /// Logger related.
 
-func (s *serverConfigV13) SetAMQPNotifyByID(accountID string, amqpn amqpNotify) {
+func (s *serverConfigV17) SetAMQPNotifyByID(accountID string, amqpn amqpNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.AMQP[accountID] = amqpn
 }
 
-func (s serverConfigV13) GetAMQP() map[string]amqpNotify {
+func (s serverConfigV17) GetAMQP() map[string]amqpNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -146,7 +161,7 @@ func (s serverConfigV13) GetAMQP() map[string]amqpNotify {
 }
 
 // GetAMQPNotify get current AMQP logger.
-func (s serverConfigV13) GetAMQPNotifyByID(accountID string) amqpNotify {
+func (s serverConfigV17) GetAMQPNotifyByID(accountID string) amqpNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -154,35 +169,35 @@ func (s serverConfigV13) GetAMQPNotifyByID(accountID string) amqpNotify {
 }
 
 //
-func (s *serverConfigV13) SetNATSNotifyByID(accountID string, natsn natsNotify) {
+func (s *serverConfigV17) SetNATSNotifyByID(accountID string, natsn natsNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.NATS[accountID] = natsn
 }
 
-func (s serverConfigV13) GetNATS() map[string]natsNotify {
+func (s serverConfigV17) GetNATS() map[string]natsNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 	return s.Notify.NATS
 }
 
 // GetNATSNotify get current NATS logger.
-func (s serverConfigV13) GetNATSNotifyByID(accountID string) natsNotify {
+func (s serverConfigV17) GetNATSNotifyByID(accountID string) natsNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.NATS[accountID]
 }
 
-func (s *serverConfigV13) SetElasticSearchNotifyByID(accountID string, esNotify elasticSearchNotify) {
+func (s *serverConfigV17) SetElasticSearchNotifyByID(accountID string, esNotify elasticSearchNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.ElasticSearch[accountID] = esNotify
 }
 
-func (s serverConfigV13) GetElasticSearch() map[string]elasticSearchNotify {
+func (s serverConfigV17) GetElasticSearch() map[string]elasticSearchNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -190,28 +205,28 @@ func (s serverConfigV13) GetElasticSearch() map[string]elasticSearchNotify {
 }
 
 // GetElasticSearchNotify get current ElasicSearch logger.
-func (s serverConfigV13) GetElasticSearchNotifyByID(accountID string) elasticSearchNotify {
+func (s serverConfigV17) GetElasticSearchNotifyByID(accountID string) elasticSearchNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.ElasticSearch[accountID]
 }
 
-func (s *serverConfigV13) SetRedisNotifyByID(accountID string, rNotify redisNotify) {
+func (s *serverConfigV17) SetRedisNotifyByID(accountID string, rNotify redisNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.Redis[accountID] = rNotify
 }
 
-func (s serverConfigV13) GetRedis() map[string]redisNotify {
+func (s serverConfigV17) GetRedis() map[string]redisNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Redis
 }
 
-func (s serverConfigV13) GetWebhook() map[string]webhookNotify {
+func (s serverConfigV17) GetWebhook() map[string]webhookNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -219,43 +234,109 @@ func (s serverConfigV13) GetWebhook() map[string]webhookNotify {
 }
 
 // GetWebhookNotifyByID get current Webhook logger.
-func (s serverConfigV13) GetWebhookNotifyByID(accountID string) webhookNotify {
+func (s serverConfigV17) GetWebhookNotifyByID(accountID string) webhookNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Webhook[accountID]
 }
 
-func (s *serverConfigV13) SetWebhookNotifyByID(accountID string, pgn webhookNotify) {
+func (s *serverConfigV17) SetWebhookNotifyByID(accountID string, pgn webhookNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.Webhook[accountID] = pgn
 }
 
+func (s serverConfigV17) GetNSQ() map[string]nsqNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.NSQ
+}
+
+// GetNSQNotifyByID get current NSQ logger.
+func (s serverConfigV17) GetNSQNotifyByID(accountID string) nsqNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.NSQ[accountID]
+}
+
+func (s *serverConfigV17) SetNSQNotifyByID(accountID string, nsqn nsqNotify) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Notify.NSQ[accountID] = nsqn
+}
+
+func (s serverConfigV17) GetPubSub() map[string]pubsubNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.PubSub
+}
+
+// GetPubSubNotifyByID get current Pub/Sub logger.
+func (s serverConfigV17) GetPubSubNotifyByID(accountID string) pubsubNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.PubSub[accountID]
+}
+
+func (s *serverConfigV17) SetPubSubNotifyByID(accountID string, pubsubn pubsubNotify) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Notify.PubSub[accountID] = pubsubn
+}
+
+func (s serverConfigV17) GetEventHubs() map[string]eventHubsNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.EventHubs
+}
+
+// GetEventHubsNotifyByID get current Event Hubs logger.
+func (s serverConfigV17) GetEventHubsNotifyByID(accountID string) eventHubsNotify {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Notify.EventHubs[accountID]
+}
+
+func (s *serverConfigV17) SetEventHubsNotifyByID(accountID string, ehn eventHubsNotify) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Notify.EventHubs[accountID] = ehn
+}
+
 // GetRedisNotify get current Redis logger.
-func (s serverConfigV13) GetRedisNotifyByID(accountID string) redisNotify {
+func (s serverConfigV17) GetRedisNotifyByID(accountID string) redisNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Redis[accountID]
 }
 
-func (s *serverConfigV13) SetPostgreSQLNotifyByID(accountID string, pgn postgreSQLNotify) {
+func (s *serverConfigV17) SetPostgreSQLNotifyByID(accountID string, pgn postgreSQLNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.PostgreSQL[accountID] = pgn
 }
 
-func (s serverConfigV13) GetPostgreSQL() map[string]postgreSQLNotify {
+func (s serverConfigV17) GetPostgreSQL() map[string]postgreSQLNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.PostgreSQL
 }
 
-func (s serverConfigV13) GetPostgreSQLNotifyByID(accountID string) postgreSQLNotify {
+func (s serverConfigV17) GetPostgreSQLNotifyByID(accountID string) postgreSQLNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -263,21 +344,21 @@ func (s serverConfigV13) GetPostgreSQLNotifyByID(accountID string) postgreSQLNot
 }
 
 // Kafka related functions
-func (s *serverConfigV13) SetKafkaNotifyByID(accountID string, kn kafkaNotify) {
+func (s *serverConfigV17) SetKafkaNotifyByID(accountID string, kn kafkaNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.Kafka[accountID] = kn
 }
 
-func (s serverConfigV13) GetKafka() map[string]kafkaNotify {
+func (s serverConfigV17) GetKafka() map[string]kafkaNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Kafka
 }
 
-func (s serverConfigV13) GetKafkaNotifyByID(accountID string) kafkaNotify {
+func (s serverConfigV17) GetKafkaNotifyByID(accountID string) kafkaNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -285,7 +366,7 @@ func (s serverConfigV13) GetKafkaNotifyByID(accountID string) kafkaNotify {
 }
 
 // SetFileLogger set new file logger.
-func (s *serverConfigV13) SetFileLogger(flogger fileLogger) {
+func (s *serverConfigV17) SetFileLogger(flogger fileLogger) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -293,7 +374,7 @@ func (s *serverConfigV13) SetFileLogger(flogger fileLogger) {
 }
 
 // GetFileLogger get current file logger.
-func (s serverConfigV13) GetFileLogger() fileLogger {
+func (s serverConfigV17) GetFileLogger() fileLogger {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -301,7 +382,7 @@ func (s serverConfigV13) GetFileLogger() fileLogger {
 }
 
 // SetConsoleLogger set new console logger.
-func (s *serverConfigV13) SetConsoleLogger(clogger consoleLogger) {
+func (s *serverConfigV17) SetConsoleLogger(clogger consoleLogger) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -309,7 +390,7 @@ func (s *serverConfigV13) SetConsoleLogger(clogger consoleLogger) {
 }
 
 // GetConsoleLogger get current console logger.
-func (s serverConfigV13) GetConsoleLogger() consoleLogger {
+func (s serverConfigV17) GetConsoleLogger() consoleLogger {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -317,7 +398,7 @@ func (s serverConfigV13) GetConsoleLogger() consoleLogger {
 }
 
 // SetRegion set new region.
-func (s *serverConfigV13) SetRegion(region string) {
+func (s *serverConfigV17) SetRegion(region string) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -325,15 +406,33 @@ func (s *serverConfigV13) SetRegion(region string) {
 }
 
 // GetRegion get current region.
-func (s serverConfigV13) GetRegion() string {
+func (s serverConfigV17) GetRegion() string {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Region
 }
 
+// SetBucketRegions set regions other than the server region buckets
+// can be created in.
+func (s *serverConfigV17) SetBucketRegions(regions []string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BucketRegions = regions
+}
+
+// GetBucketRegions get regions other than the server region buckets
+// can be created in.
+func (s serverConfigV17) GetBucketRegions() []string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketRegions
+}
+
 // SetCredentials set new credentials.
-func (s *serverConfigV13) SetCredential(creds credential) {
+func (s *serverConfigV17) SetCredential(creds credential) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -341,7 +440,7 @@ func (s *serverConfigV13) SetCredential(creds credential) {
 }
 
 // GetCredentials get current credentials.
-func (s serverConfigV13) GetCredential() credential {
+func (s serverConfigV17) GetCredential() credential {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -349,10 +448,14 @@ func (s serverConfigV13) GetCredential() credential {
 }
 
 // Save config.
-func (s serverConfigV13) Save() error {
+func (s serverConfigV17) Save() error {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
+	if globalEtcdClient != nil {
+		return saveConfigEtcd(&s)
+	}
+
 	// get config file.
 	configFile, err := getConfigFile()
 	if err != nil {
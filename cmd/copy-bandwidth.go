@@ -0,0 +1,67 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Environment variable limiting the bandwidth of the server side
+// copies, in bytes per second such as "50MiB", shared by all the
+// copies of a server.
+const copyBandwidthEnv = "MINIO_COPY_BANDWIDTH"
+
+// Token bucket of the server side copies, nil when unlimited.
+var globalCopyLimiter *tokenBucket
+
+// getCopyBandwidth - returns the bandwidth of the server side copies
+// configured through the environment, in bytes per second, 0 if
+// unlimited.
+func getCopyBandwidth() (int64, error) {
+	value := os.Getenv(copyBandwidthEnv)
+	if value == "" || value == "off" {
+		return 0, nil
+	}
+	rate, err := humanize.ParseBytes(value)
+	if err != nil || rate == 0 || rate > 1<<62 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be a positive number of bytes per second such as 50MiB, or off", copyBandwidthEnv, value)
+	}
+	return int64(rate), nil
+}
+
+// setCopyBandwidth - limits the server side copies to rate bytes per
+// second, unlimited if 0.
+func setCopyBandwidth(rate int64) {
+	globalCopyLimiter = nil
+	if rate > 0 {
+		globalCopyLimiter = newTokenBucket(rate)
+	}
+}
+
+// throttleCopyWriter - returns a writer of the data read by a server
+// side copy, such as CopyObject and ComposeObject, within the copy
+// bandwidth if limited.
+func throttleCopyWriter(writer io.Writer) io.Writer {
+	if limiter := globalCopyLimiter; limiter != nil {
+		return &throttledWriter{writer: writer, bucket: limiter}
+	}
+	return writer
+}
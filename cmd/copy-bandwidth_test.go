@@ -0,0 +1,100 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the bandwidth of the server side copies from the environment.
+func TestGetCopyBandwidth(t *testing.T) {
+	defer os.Unsetenv(copyBandwidthEnv)
+
+	testCases := []struct {
+		value      string
+		rate       int64
+		shouldPass bool
+	}{
+		{"", 0, true},
+		{"off", 0, true},
+		{"1048576", 1 << 20, true},
+		{"50MiB", 50 << 20, true},
+		{"1GB", 1000 * 1000 * 1000, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"fast", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(copyBandwidthEnv, testCase.value)
+		rate, err := getCopyBandwidth()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if rate != testCase.rate {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.rate, rate)
+		}
+	}
+}
+
+// Tests the server side copies within the copy bandwidth.
+func TestCopyObjectBandwidth(t *testing.T) {
+	defer setCopyBandwidth(0)
+	ExecObjectLayerTest(t, testCopyObjectBandwidth)
+}
+
+func testCopyObjectBandwidth(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	const rate = 2000
+	data := bytes.Repeat([]byte("a"), 3*rate/2)
+	if err := obj.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err := obj.PutObject(context.Background(), "bucket", "source", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	// The first rate bytes are a burst, the rest takes half a second.
+	setCopyBandwidth(rate)
+	start := time.Now()
+	if _, err := obj.CopyObject(context.Background(), "bucket", "source", "bucket", "copy", nil); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("%s: Expected the copy to take half a second, took %s", instanceType, elapsed)
+	}
+
+	var buffer bytes.Buffer
+	if err := obj.GetObject(context.Background(), "bucket", "copy", 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("%s: Copied data is not equal to the data", instanceType)
+	}
+
+	// Downloads are not throttled.
+	start = time.Now()
+	if err := obj.GetObject(context.Background(), "bucket", "source", 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("%s: Expected the download not to be throttled, took %s", instanceType, elapsed)
+	}
+	setCopyBandwidth(0)
+}
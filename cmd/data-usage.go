@@ -0,0 +1,316 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variable setting the interval between two crawls of all
+// the objects for their data usage, such as "6h", "off" disables the
+// crawls.
+const dataUsageIntervalEnv = "MINIO_DATA_USAGE_INTERVAL"
+
+// Data usage is crawled every hour by default.
+const defaultDataUsageInterval = time.Hour
+
+// Data usage file, holding the results of the last crawl of any of
+// the servers, stored in minioMetaBucket.
+const dataUsageFile = "data-usage.json"
+
+// Object size ranges of the data usage histograms, by upper bound.
+var dataUsageSizeRanges = []struct {
+	name  string
+	upper int64
+}{
+	{"LESS_THAN_1_KiB", 1 << 10},
+	{"BETWEEN_1_KiB_AND_1_MiB", 1 << 20},
+	{"BETWEEN_1_MiB_AND_10_MiB", 10 << 20},
+	{"BETWEEN_10_MiB_AND_64_MiB", 64 << 20},
+	{"BETWEEN_64_MiB_AND_128_MiB", 128 << 20},
+	{"BETWEEN_128_MiB_AND_512_MiB", 512 << 20},
+	{"GREATER_THAN_512_MiB", -1},
+}
+
+// dataUsageEntry - number, total size and size histogram of objects.
+type dataUsageEntry struct {
+	Objects   uint64            `json:"objects"`
+	Size      uint64            `json:"size"`
+	Histogram map[string]uint64 `json:"histogram,omitempty"`
+}
+
+// add - counts an object of size.
+func (e *dataUsageEntry) add(size int64) {
+	e.Objects++
+	e.Size += uint64(size)
+	if e.Histogram == nil {
+		e.Histogram = make(map[string]uint64)
+	}
+	for _, sizeRange := range dataUsageSizeRanges {
+		if sizeRange.upper < 0 || size < sizeRange.upper {
+			e.Histogram[sizeRange.name]++
+			return
+		}
+	}
+}
+
+// bucketUsageInfo - data usage of a bucket and of its first level
+// prefixes, objects at the top of the bucket belonging to none.
+type bucketUsageInfo struct {
+	dataUsageEntry
+	Prefixes map[string]dataUsageEntry `json:"prefixes,omitempty"`
+}
+
+// dataUsageInfo - data usage of all the buckets, and of the owners of
+// their objects, as of the last crawl.
+type dataUsageInfo struct {
+	LastUpdate time.Time                  `json:"lastUpdate"`
+	Objects    uint64                     `json:"objects"`
+	Size       uint64                     `json:"size"`
+	Buckets    map[string]bucketUsageInfo `json:"buckets,omitempty"`
+	Owners     map[string]dataUsageEntry  `json:"owners,omitempty"`
+}
+
+// bucketUsageCache - data usage of the buckets as of the last data
+// usage crawl, without their prefixes.
+type bucketUsageCache struct {
+	rwMutex    sync.RWMutex
+	lastUpdate time.Time
+	buckets    map[string]dataUsageEntry
+}
+
+// Usage of the buckets, updated by the data usage crawler.
+var globalBucketUsage = &bucketUsageCache{}
+
+// Set - replaces the usage of all the buckets with the usage of info.
+func (u *bucketUsageCache) Set(info dataUsageInfo) {
+	buckets := make(map[string]dataUsageEntry, len(info.Buckets))
+	for bucket, usage := range info.Buckets {
+		buckets[bucket] = usage.dataUsageEntry
+	}
+	u.rwMutex.Lock()
+	defer u.rwMutex.Unlock()
+	u.lastUpdate = info.LastUpdate
+	u.buckets = buckets
+}
+
+// Get - returns the usage of a bucket along with the time of the crawl
+// it is as of, false if no crawl accounted the bucket yet.
+func (u *bucketUsageCache) Get(bucket string) (dataUsageEntry, time.Time, bool) {
+	u.rwMutex.RLock()
+	defer u.rwMutex.RUnlock()
+	usage, ok := u.buckets[bucket]
+	return usage, u.lastUpdate, ok
+}
+
+// getDataUsageInterval - returns the data usage crawl interval
+// configured through the environment, 0 if crawls are disabled.
+func getDataUsageInterval() (time.Duration, error) {
+	value := os.Getenv(dataUsageIntervalEnv)
+	switch value {
+	case "":
+		return defaultDataUsageInterval, nil
+	case "off":
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", dataUsageIntervalEnv, value, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive", dataUsageIntervalEnv, value)
+	}
+	return interval, nil
+}
+
+// dataUsageCrawler - periodically lists all the objects to account
+// their data usage, skipping the crawls when another server crawled
+// less than interval ago.
+type dataUsageCrawler struct {
+	objAPI   ObjectLayer
+	interval time.Duration
+
+	doneCh chan struct{}
+}
+
+// newDataUsageCrawler - initializes a crawler for objAPI, not started yet.
+func newDataUsageCrawler(objAPI ObjectLayer, interval time.Duration) *dataUsageCrawler {
+	return &dataUsageCrawler{
+		objAPI:   objAPI,
+		interval: interval,
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start - runs the crawler in the background until Stop is called.
+func (c *dataUsageCrawler) Start() {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			errorIf(c.update(time.Now().UTC()), "Unable to update the data usage.")
+			select {
+			case <-ticker.C:
+			case <-c.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop - stops the crawler, safe to be called on nil.
+func (c *dataUsageCrawler) Stop() {
+	if c == nil {
+		return
+	}
+	close(c.doneCh)
+}
+
+// update - crawls and saves the data usage, unless the saved one is
+// less than interval old.
+func (c *dataUsageCrawler) update(now time.Time) error {
+	saved, err := readDataUsage(c.objAPI)
+	if err != nil {
+		return err
+	}
+	if now.Sub(saved.LastUpdate) < c.interval {
+		// Usage crawled by another server.
+		globalOwnerUsage.Set(saved.Owners)
+		globalBucketUsage.Set(saved)
+		return nil
+	}
+	info, err := crawlDataUsage(c.objAPI)
+	if err != nil {
+		return err
+	}
+	info.LastUpdate = now
+	if err = writeDataUsage(c.objAPI, info); err != nil {
+		return err
+	}
+	globalOwnerUsage.Set(info.Owners)
+	globalBucketUsage.Set(info)
+	return nil
+}
+
+// crawlDataUsage - lists all the objects of all the buckets and
+// returns their data usage.
+func crawlDataUsage(objAPI ObjectLayer) (dataUsageInfo, error) {
+	info := dataUsageInfo{
+		Buckets: make(map[string]bucketUsageInfo),
+		Owners:  make(map[string]dataUsageEntry),
+	}
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		return info, err
+	}
+	for _, bucket := range buckets {
+		usage, err := crawlBucketUsage(objAPI, bucket.Name, info.Owners)
+		if err != nil {
+			if isErrBucketNotFound(err) {
+				// Removed since listed.
+				continue
+			}
+			return info, err
+		}
+		info.Objects += usage.Objects
+		info.Size += usage.Size
+		info.Buckets[bucket.Name] = usage
+	}
+	return info, nil
+}
+
+// crawlBucketUsage - lists all the objects of a bucket, a page at a
+// time as a background operation, and returns their data usage. The
+// objects with an owner are also accounted in owners.
+func crawlBucketUsage(objAPI ObjectLayer, bucket string, owners map[string]dataUsageEntry) (bucketUsageInfo, error) {
+	usage := bucketUsageInfo{Prefixes: make(map[string]dataUsageEntry)}
+	marker := ""
+	for {
+		var result ListObjectsInfo
+		err := globalBackgroundOps.Do(func() (err error) {
+			result, err = objAPI.ListObjects(context.Background(), bucket, "", marker, "", maxObjectList)
+			return err
+		})
+		if err != nil {
+			return usage, err
+		}
+		for _, object := range result.Objects {
+			usage.add(object.Size)
+			if i := strings.Index(object.Name, slashSeparator); i >= 0 {
+				prefix := object.Name[:i+1]
+				entry := usage.Prefixes[prefix]
+				entry.add(object.Size)
+				usage.Prefixes[prefix] = entry
+			}
+			if owner := object.UserDefined[minioMetaOwner]; owner != "" {
+				entry := owners[owner]
+				entry.add(object.Size)
+				owners[owner] = entry
+			}
+		}
+		if !result.IsTruncated || len(result.Objects) == 0 {
+			return usage, nil
+		}
+		marker = result.Objects[len(result.Objects)-1].Name
+	}
+}
+
+// readDataUsage - reads the data usage saved by the last crawl, empty
+// if none happened yet.
+func readDataUsage(objAPI ObjectLayer) (dataUsageInfo, error) {
+	// Acquire a read lock on data usage before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, dataUsageFile)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var info dataUsageInfo
+	var buffer bytes.Buffer
+	if err := objAPI.GetObject(context.Background(), minioMetaBucket, dataUsageFile, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return info, nil
+		}
+		return info, errorCause(err)
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// writeDataUsage - saves the data usage of a crawl.
+func writeDataUsage(objAPI ObjectLayer, info dataUsageInfo) error {
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	// Acquire a write lock on data usage before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, dataUsageFile)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, dataUsageFile, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		return errorCause(err)
+	}
+	return nil
+}
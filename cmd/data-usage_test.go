@@ -0,0 +1,145 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the data usage crawl interval from the environment.
+func TestGetDataUsageInterval(t *testing.T) {
+	defer os.Unsetenv(dataUsageIntervalEnv)
+
+	testCases := []struct {
+		value      string
+		interval   time.Duration
+		shouldPass bool
+	}{
+		{"", defaultDataUsageInterval, true},
+		{"off", 0, true},
+		{"6h", 6 * time.Hour, true},
+		{"0s", 0, false},
+		{"-1h", 0, false},
+		{"daily", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(dataUsageIntervalEnv, testCase.value)
+		interval, err := getDataUsageInterval()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if interval != testCase.interval {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.interval, interval)
+		}
+	}
+}
+
+// Tests counting objects in the size ranges of the histograms.
+func TestDataUsageEntryAdd(t *testing.T) {
+	var entry dataUsageEntry
+	for _, size := range []int64{0, 1023, 1024, 1 << 20, 600 << 20} {
+		entry.add(size)
+	}
+	if entry.Objects != 5 || entry.Size != 1023+1024+(1<<20)+(600<<20) {
+		t.Errorf("Unexpected data usage %#v", entry)
+	}
+	expected := map[string]uint64{
+		"LESS_THAN_1_KiB":          2,
+		"BETWEEN_1_KiB_AND_1_MiB":  1,
+		"BETWEEN_1_MiB_AND_10_MiB": 1,
+		"GREATER_THAN_512_MiB":     1,
+	}
+	if len(entry.Histogram) != len(expected) {
+		t.Fatalf("Expected the histogram %v, got %v", expected, entry.Histogram)
+	}
+	for name, count := range expected {
+		if entry.Histogram[name] != count {
+			t.Errorf("Expected %d objects %s, got %d", count, name, entry.Histogram[name])
+		}
+	}
+}
+
+// Tests crawling and saving the data usage of the buckets and prefixes.
+func TestDataUsageCrawlerUpdate(t *testing.T) {
+	// Data usage is saved under a namespace lock.
+	initNSLock(false)
+	ExecObjectLayerTest(t, testDataUsageCrawlerUpdate)
+}
+
+func testDataUsageCrawlerUpdate(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	if err := obj.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	objects := map[string]int{
+		"top":            10,
+		"photos/a.jpg":   2048,
+		"photos/2017/b":  4096,
+		"videos/c.mp4":   100,
+		"videos/d/e.mp4": 200,
+	}
+	for object, size := range objects {
+		data := bytes.Repeat([]byte("a"), size)
+		if _, err := obj.PutObject(context.Background(), "bucket", object, int64(size), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	crawler := newDataUsageCrawler(obj, time.Hour)
+	now := time.Now().UTC()
+	if err := crawler.update(now); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	info, err := readDataUsage(obj)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !info.LastUpdate.Equal(now) || info.Objects != 5 || info.Size != 10+2048+4096+100+200 {
+		t.Fatalf("%s: Unexpected data usage %#v", instanceType, info)
+	}
+	usage := info.Buckets["bucket"]
+	if usage.Objects != 5 || len(usage.Prefixes) != 2 {
+		t.Fatalf("%s: Unexpected bucket data usage %#v", instanceType, usage)
+	}
+	if photos := usage.Prefixes["photos/"]; photos.Objects != 2 || photos.Size != 2048+4096 ||
+		photos.Histogram["BETWEEN_1_KiB_AND_1_MiB"] != 2 {
+		t.Errorf("%s: Unexpected photos/ data usage %#v", instanceType, photos)
+	}
+	if videos := usage.Prefixes["videos/"]; videos.Objects != 2 || videos.Size != 300 {
+		t.Errorf("%s: Unexpected videos/ data usage %#v", instanceType, videos)
+	}
+
+	// A recent crawl, by this or another server, is not repeated.
+	if _, err = obj.PutObject(context.Background(), "bucket", "new", 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err = crawler.update(now.Add(time.Minute)); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if info, err = readDataUsage(obj); err != nil || info.Objects != 5 {
+		t.Fatalf("%s: Expected the data usage not to be crawled again, got %#v, %v", instanceType, info, err)
+	}
+	if err = crawler.update(now.Add(time.Hour)); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if info, err = readDataUsage(obj); err != nil || info.Objects != 6 {
+		t.Fatalf("%s: Expected the data usage to be crawled again, got %#v, %v", instanceType, info, err)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/klauspost/reedsolomon"
+)
+
+// Environment variable setting the maximum number of blocks erasure
+// coded at a time, "off" removes the limit.
+const erasureConcurrencyEnv = "MINIO_ERASURE_CONCURRENCY"
+
+// erasureCoders - Reed-Solomon encoders shared by all the erasure
+// coded blocks with the same number of data and parity blocks, so that
+// their matrices and the matrices inverted to reconstruct missing data
+// are computed once. Encoding and decoding a block is spread over all
+// the CPUs by the encoder, blocks are coded at most concurrency at a
+// time so that parallel requests do not oversubscribe the CPUs.
+type erasureCoders struct {
+	mutex    *sync.Mutex
+	encoders map[[2]int]reedsolomon.Encoder
+	// Coding slots, nil when unlimited.
+	slots chan struct{}
+}
+
+// newErasureCoders - returns erasure coders coding at most concurrency
+// blocks at a time, zero for unlimited.
+func newErasureCoders(concurrency int) *erasureCoders {
+	coders := &erasureCoders{
+		mutex:    &sync.Mutex{},
+		encoders: make(map[[2]int]reedsolomon.Encoder),
+	}
+	if concurrency > 0 {
+		coders.slots = make(chan struct{}, concurrency)
+	}
+	return coders
+}
+
+// Global erasure coders, coding as many blocks at a time as CPUs by default.
+var globalErasureCoders = newErasureCoders(runtime.NumCPU())
+
+// getErasureConcurrency - returns the maximum number of blocks erasure
+// coded at a time configured in the environment.
+func getErasureConcurrency() (int, error) {
+	value := os.Getenv(erasureConcurrencyEnv)
+	switch value {
+	case "":
+		return runtime.NumCPU(), nil
+	case "off":
+		return 0, nil
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be a positive number or off", erasureConcurrencyEnv, value)
+	}
+	return concurrency, nil
+}
+
+// Get - returns the encoder of dataBlocks and parityBlocks.
+func (e *erasureCoders) Get(dataBlocks, parityBlocks int) (reedsolomon.Encoder, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	key := [2]int{dataBlocks, parityBlocks}
+	if rs, ok := e.encoders[key]; ok {
+		return rs, nil
+	}
+	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	if err != nil {
+		return nil, err
+	}
+	e.encoders[key] = rs
+	return rs, nil
+}
+
+// Acquire - blocks until a block may be coded.
+func (e *erasureCoders) Acquire() {
+	if e.slots != nil {
+		e.slots <- struct{}{}
+	}
+}
+
+// Release - ends the coding of a block.
+func (e *erasureCoders) Release() {
+	if e.slots != nil {
+		<-e.slots
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Tests parsing the erasure coding concurrency from the environment.
+func TestGetErasureConcurrency(t *testing.T) {
+	defer os.Unsetenv(erasureConcurrencyEnv)
+
+	testCases := []struct {
+		value               string
+		expectedConcurrency int
+		expectErr           bool
+	}{
+		{"", runtime.NumCPU(), false},
+		{"off", 0, false},
+		{"4", 4, false},
+		{"0", 0, true},
+		{"all", 0, true},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(erasureConcurrencyEnv, testCase.value)
+		concurrency, err := getErasureConcurrency()
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+		if concurrency != testCase.expectedConcurrency {
+			t.Errorf("Test %d: Expected concurrency %d, got %d", i+1, testCase.expectedConcurrency, concurrency)
+		}
+	}
+}
+
+// Tests encoders are shared and blocks coded at most concurrency at a time.
+func TestErasureCoders(t *testing.T) {
+	coders := newErasureCoders(1)
+
+	rs1, err := coders.Get(8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs2, err := coders.Get(8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs1 != rs2 {
+		t.Error("Expected the encoder to be shared")
+	}
+	if _, err = coders.Get(0, 8); err == nil {
+		t.Error("Expected an error for zero data blocks")
+	}
+
+	coders.Acquire()
+	acquiredCh := make(chan struct{})
+	go func() {
+		coders.Acquire()
+		close(acquiredCh)
+	}()
+	select {
+	case <-acquiredCh:
+		t.Fatal("Expected the second block to wait")
+	case <-time.After(10 * time.Millisecond):
+	}
+	coders.Release()
+	<-acquiredCh
+	coders.Release()
+
+	// Unlimited coders never block.
+	coders = newErasureCoders(0)
+	for i := 0; i < 10; i++ {
+		coders.Acquire()
+	}
+}
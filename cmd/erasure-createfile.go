@@ -17,18 +17,18 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"hash"
 	"io"
 	"sync"
-
-	"github.com/klauspost/reedsolomon"
 )
 
 // erasureCreateFile - writes an entire stream by erasure coding to
 // all the disks, writes also calculate individual block's checksum
-// for future bit-rot protection.
-func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, err error) {
+// for future bit-rot protection. Writing stops with ctx.Err() once
+// ctx is done.
+func erasureCreateFile(ctx context.Context, disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, err error) {
 	// Allocated blockSized buffer for reading from incoming stream.
 	buf := make([]byte, blockSize)
 
@@ -36,6 +36,9 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 
 	// Read until io.EOF, erasure codes data and writes to all disks.
 	for {
+		if err = ctx.Err(); err != nil {
+			return 0, nil, traceError(err)
+		}
 		var blocks [][]byte
 		n, rErr := io.ReadFull(reader, buf)
 		// FIXME: this is a bug in Golang, n == 0 and err ==
@@ -49,7 +52,7 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 			// data. Will create a 0byte file instead.
 			if bytesWritten == 0 {
 				blocks = make([][]byte, len(disks))
-				rErr = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum)
+				rErr = appendFile(ctx, disks, volume, path, blocks, hashWriters, writeQuorum)
 				if rErr != nil {
 					return 0, nil, rErr
 				}
@@ -69,7 +72,7 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 			}
 
 			// Write to all disks.
-			if err = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum); err != nil {
+			if err = appendFile(ctx, disks, volume, path, blocks, hashWriters, writeQuorum); err != nil {
 				return 0, nil, err
 			}
 			bytesWritten += int64(n)
@@ -86,10 +89,13 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 // encodeData - encodes incoming data buffer into
 // dataBlocks+parityBlocks returns a 2 dimensional byte array.
 func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, error) {
-	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	rs, err := globalErasureCoders.Get(dataBlocks, parityBlocks)
 	if err != nil {
 		return nil, traceError(err)
 	}
+	globalErasureCoders.Acquire()
+	defer globalErasureCoders.Release()
+
 	// Split the input buffer into data and parity blocks.
 	var blocks [][]byte
 	blocks, err = rs.Split(dataBuffer)
@@ -108,7 +114,7 @@ func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, erro
 }
 
 // appendFile - append data buffer at path.
-func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, writeQuorum int) (err error) {
+func appendFile(ctx context.Context, disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, writeQuorum int) (err error) {
 	var wg = &sync.WaitGroup{}
 	var wErrs = make([]error, len(disks))
 	// Write encoded data to quorum disks in parallel.
@@ -120,7 +126,10 @@ func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hash
 		// Write encoded data in routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
-			wErr := disk.AppendFile(volume, path, enBlocks[index])
+			_, span := startSpan(ctx, "StorageAPI.AppendFile")
+			span.SetTag("disk", disk.String())
+			wErr := disk.AppendFile(ctx, volume, path, enBlocks[index])
+			span.Finish(wErr)
 			if wErr != nil {
 				wErrs[index] = traceError(wErr)
 				return
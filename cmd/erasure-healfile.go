@@ -16,10 +16,14 @@
 
 package cmd
 
-import "encoding/hex"
+import (
+	"context"
+	"encoding/hex"
+)
 
 // Heals the erasure coded file. reedsolomon.Reconstruct() is used to reconstruct the missing parts.
-func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volume, path, healBucket, healPath string, size int64, blockSize int64, dataBlocks int, parityBlocks int, algo string) (checkSums []string, err error) {
+// Healing stops once ctx is done.
+func erasureHealFile(ctx context.Context, latestDisks []StorageAPI, outDatedDisks []StorageAPI, volume, path, healBucket, healPath string, size int64, blockSize int64, dataBlocks int, parityBlocks int, algo string) (checkSums []string, err error) {
 	var offset int64
 	remainingSize := size
 
@@ -27,6 +31,10 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 	hashWriters := newHashWriters(len(outDatedDisks), bitRotAlgo)
 
 	for remainingSize > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, traceError(err)
+		}
+
 		curBlockSize := blockSize
 		if remainingSize < curBlockSize {
 			curBlockSize = remainingSize
@@ -45,7 +53,7 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 				continue
 			}
 			enBlocks[index] = make([]byte, curEncBlockSize)
-			_, err := disk.ReadFile(volume, path, offset, enBlocks[index])
+			_, err := disk.ReadFile(ctx, volume, path, offset, enBlocks[index])
 			if err != nil {
 				enBlocks[index] = nil
 			}
@@ -62,7 +70,7 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 			if disk == nil {
 				continue
 			}
-			err := disk.AppendFile(healBucket, healPath, enBlocks[index])
+			err := disk.AppendFile(ctx, healBucket, healPath, enBlocks[index])
 			if err != nil {
 				return nil, traceError(err)
 			}
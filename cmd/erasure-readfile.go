@@ -17,12 +17,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"io"
 	"sync"
 
-	"github.com/klauspost/reedsolomon"
 	"github.com/minio/minio/pkg/bpool"
 )
 
@@ -111,7 +111,7 @@ func getReadDisks(orderedDisks []StorageAPI, index int, dataBlocks int) (readDis
 }
 
 // parallelRead - reads chunks in parallel from the disks specified in []readDisks.
-func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []StorageAPI, enBlocks [][]byte, blockOffset int64, curChunkSize int64, bitRotVerify func(diskIndex int) bool, pool *bpool.BytePool) {
+func parallelRead(ctx context.Context, volume, path string, readDisks []StorageAPI, orderedDisks []StorageAPI, enBlocks [][]byte, blockOffset int64, curChunkSize int64, bitRotVerify func(diskIndex int) bool, pool *bpool.BytePool) {
 	// WaitGroup to synchronise the read go-routines.
 	wg := &sync.WaitGroup{}
 
@@ -140,7 +140,10 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 			}
 			buf = buf[:curChunkSize]
 
-			_, err = readDisks[index].ReadFile(volume, path, blockOffset, buf)
+			_, span := startSpan(ctx, "StorageAPI.ReadFile")
+			span.SetTag("disk", readDisks[index].String())
+			_, err = readDisks[index].ReadFile(ctx, volume, path, blockOffset, buf)
+			span.Finish(err)
 			if err != nil {
 				orderedDisks[index] = nil
 				return
@@ -157,8 +160,9 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 // Erasure coded files are read block by block as per given erasureInfo and data chunks
 // are decoded into a data block. Data block is trimmed for given offset and length,
 // then written to given writer. This function also supports bit-rot detection by
-// verifying checksum of individual block's checksum.
-func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, error) {
+// verifying checksum of individual block's checksum. Reading stops with
+// ctx.Err() once ctx is done.
+func erasureReadFile(ctx context.Context, writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, error) {
 	// Offset and length cannot be negative.
 	if offset < 0 || length < 0 {
 		return 0, traceError(errUnexpected)
@@ -185,7 +189,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 				return true
 			}
 			// Is this a valid block?
-			isValid := isValidBlock(disks[diskIndex], volume, path, checkSums[diskIndex], algo)
+			isValid := isValidBlock(ctx, disks[diskIndex], volume, path, checkSums[diskIndex], algo)
 			verified[diskIndex] = isValid
 			return isValid
 		}
@@ -194,6 +198,9 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 	// Total bytes written to writer
 	bytesWritten := int64(0)
 
+	// Disks failing reads are dropped from disks.
+	readableDisks := diskCount(disks)
+
 	startBlock := offset / blockSize
 	endBlock := (offset + length) / blockSize
 
@@ -207,6 +214,10 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 	// need to read parity disks. If one of the data disk is missing we need to read DataBlocks+1 number
 	// of disks. Once read, we Reconstruct() missing data if needed and write it to the given writer.
 	for block := startBlock; block <= endBlock; block++ {
+		if err := ctx.Err(); err != nil {
+			return bytesWritten, traceError(err)
+		}
+
 		// Mark all buffers as unused at the start of the loop so that the buffers
 		// can be reused.
 		pool.Reset()
@@ -241,7 +252,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 				return bytesWritten, err
 			}
 			// Issue a parallel read across the disks specified in readDisks.
-			parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
+			parallelRead(ctx, volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
 			if isSuccessDecodeBlocks(enBlocks, dataBlocks) {
 				// If enough blocks are available to do rs.Reconstruct()
 				break
@@ -254,6 +265,12 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 			// hence continue the for-loop till we have enough data blocks.
 		}
 
+		// Blocks read from fewer disks than available at the start
+		// are reported before being written.
+		if diskCount(disks) < readableDisks {
+			markReadHealing(ctx)
+		}
+
 		// If we have all the data blocks no need to decode, continue to write.
 		if !isSuccessDataBlocks(enBlocks, dataBlocks) {
 			// Reconstruct the missing data blocks.
@@ -301,7 +318,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 
 // isValidBlock - calculates the checksum hash for the block and
 // validates if its correct returns true for valid cases, false otherwise.
-func isValidBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string) (ok bool) {
+func isValidBlock(ctx context.Context, disk StorageAPI, volume, path, checkSum, checkSumAlgo string) (ok bool) {
 	// Disk is not available, not a valid block.
 	if disk == nil {
 		return false
@@ -312,7 +329,7 @@ func isValidBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string)
 	}
 	// Read everything for a given block and calculate hash.
 	hashWriter := newHash(checkSumAlgo)
-	hashBytes, err := hashSum(disk, volume, path, hashWriter)
+	hashBytes, err := hashSum(ctx, disk, volume, path, hashWriter)
 	if err != nil {
 		errorIf(err, "Unable to calculate checksum %s/%s", volume, path)
 		return false
@@ -323,10 +340,12 @@ func isValidBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string)
 // decodeData - decode encoded blocks.
 func decodeData(enBlocks [][]byte, dataBlocks, parityBlocks int) error {
 	// Initialized reedsolomon.
-	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	rs, err := globalErasureCoders.Get(dataBlocks, parityBlocks)
 	if err != nil {
 		return traceError(err)
 	}
+	globalErasureCoders.Acquire()
+	defer globalErasureCoders.Release()
 
 	// Reconstruct encoded blocks.
 	err = rs.Reconstruct(enBlocks)
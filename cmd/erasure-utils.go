@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"hash"
 	"io"
@@ -70,13 +71,13 @@ var hashBufferPool = sync.Pool{
 }
 
 // hashSum calculates the hash of the entire path and returns.
-func hashSum(disk StorageAPI, volume, path string, writer hash.Hash) ([]byte, error) {
+func hashSum(ctx context.Context, disk StorageAPI, volume, path string, writer hash.Hash) ([]byte, error) {
 	// Fetch staging a new staging buffer from the pool.
 	bufp := hashBufferPool.Get().(*[]byte)
 	defer hashBufferPool.Put(bufp)
 
 	// Copy entire buffer to writer.
-	if err := copyBuffer(writer, disk, volume, path, *bufp); err != nil {
+	if err := copyBuffer(ctx, writer, disk, volume, path, *bufp); err != nil {
 		return nil, err
 	}
 
@@ -173,7 +174,7 @@ func getChunkSize(blockSize int64, dataBlocks int) int64 {
 // until EOF. It does not treat an EOF from ReadFile an error to be reported.
 // Additionally copyBuffer stages through the provided buffer; otherwise if it
 // has zero length, returns error.
-func copyBuffer(writer io.Writer, disk StorageAPI, volume string, path string, buf []byte) error {
+func copyBuffer(ctx context.Context, writer io.Writer, disk StorageAPI, volume string, path string, buf []byte) error {
 	// Error condition of zero length buffer.
 	if buf != nil && len(buf) == 0 {
 		return errors.New("empty buffer in readBuffer")
@@ -184,7 +185,7 @@ func copyBuffer(writer io.Writer, disk StorageAPI, volume string, path string, b
 
 	// Read until io.EOF.
 	for {
-		n, err := disk.ReadFile(volume, path, startOffset, buf)
+		n, err := disk.ReadFile(ctx, volume, path, startOffset, buf)
 		if n > 0 {
 			m, wErr := writer.Write(buf[:n])
 			if wErr != nil {
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"testing"
@@ -91,7 +92,7 @@ func TestCopyBuffer(t *testing.T) {
 
 	testFile := "testFile"
 	testContent := []byte("hello, world")
-	err = disk.AppendFile(volume, testFile, testContent)
+	err = disk.AppendFile(context.Background(), volume, testFile, testContent)
 	if err != nil {
 		t.Fatalf("AppendFile failed: <ERROR> %s", err)
 	}
@@ -135,7 +136,7 @@ func TestCopyBuffer(t *testing.T) {
 	}
 	// iterate over the test cases and call copy Buffer with data.
 	for i, testCase := range testCases {
-		actualErr := copyBuffer(testCase.writer, testCase.disk, testCase.volume, testCase.path, testCase.buf)
+		actualErr := copyBuffer(context.Background(), testCase.writer, testCase.disk, testCase.volume, testCase.path, testCase.buf)
 
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: Expected to pass but failed instead with \"%s\"", i+1, actualErr)
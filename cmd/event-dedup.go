@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variable enabling the deduplication of events sent to
+// external targets, set to a window such as "10m".
+const notifyDedupWindowEnv = "MINIO_NOTIFY_DEDUP_WINDOW"
+
+// getNotifyDedupWindow - returns the deduplication window configured
+// through the environment, 0 if deduplication is disabled.
+func getNotifyDedupWindow() (time.Duration, error) {
+	value := os.Getenv(notifyDedupWindowEnv)
+	if value == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", notifyDedupWindowEnv, value, err)
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive", notifyDedupWindowEnv, value)
+	}
+	return window, nil
+}
+
+// eventContentID - returns a digest of the object state notified by
+// event. Events about the same state of an object, such as an event
+// notified again by a replay, share their digest. Removals carry no
+// object state and are identified by their sequencer instead.
+func eventContentID(event eventData, nEvent NotificationEvent) string {
+	fields := []string{event.Bucket, event.ObjInfo.Name, nEvent.EventName}
+	if event.Type == ObjectRemovedDelete || event.Type == ObjectRemovedMultipartUploadExpired {
+		fields = append(fields, nEvent.S3.Object.Sequencer)
+	} else {
+		fields = append(fields,
+			event.ObjInfo.MD5Sum,
+			strconv.FormatInt(event.ObjInfo.Size, 10),
+			strconv.FormatInt(event.ObjInfo.ModTime.UnixNano(), 10),
+		)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// getEventID - returns the ID of the event of contentID sent for the
+// notification rule of ruleID.
+func getEventID(ruleID, contentID string) string {
+	sum := sha256.Sum256([]byte(ruleID + "\x00" + contentID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// eventsForRule - returns copies of nEvent as sent for the rule of
+// ruleID, with the configuration ID of the rule and their event ID.
+func eventsForRule(ruleID, contentID string, nEvent []NotificationEvent) []NotificationEvent {
+	ruleEvents := make([]NotificationEvent, len(nEvent))
+	for i, event := range nEvent {
+		if ruleID != "" {
+			event.S3.ConfigurationID = ruleID
+		}
+		responseElements := make(map[string]string, len(event.ResponseElements)+1)
+		for k, v := range event.ResponseElements {
+			responseElements[k] = v
+		}
+		responseElements[responseEventIDKey] = getEventID(ruleID, contentID)
+		event.ResponseElements = responseElements
+		ruleEvents[i] = event
+	}
+	return ruleEvents
+}
+
+// eventDeduper - remembers the events sent to every target during
+// window, so that events sent again are dropped.
+type eventDeduper struct {
+	window time.Duration
+
+	mu sync.Mutex
+	// Expiry of the events sent, by target ARN and event ID.
+	sent map[string]time.Time
+	// Time of the next removal of expired entries.
+	nextPurge time.Time
+}
+
+// newEventDeduper - initializes a deduper remembering events during
+// window.
+func newEventDeduper(window time.Duration) *eventDeduper {
+	return &eventDeduper{
+		window: window,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// isDuplicate - returns true if the event of eventID was already sent
+// to targetARN during the window, records it as sent otherwise. Safe
+// to be called on nil, which never deduplicates.
+func (d *eventDeduper) isDuplicate(targetARN, eventID string) bool {
+	if d == nil {
+		return false
+	}
+	now := time.Now().UTC()
+	key := targetARN + "\x00" + eventID
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.After(d.nextPurge) {
+		for k, expiry := range d.sent {
+			if now.After(expiry) {
+				delete(d.sent, k)
+			}
+		}
+		d.nextPurge = now.Add(d.window)
+	}
+	if expiry, ok := d.sent[key]; ok && !now.After(expiry) {
+		return true
+	}
+	d.sent[key] = now.Add(d.window)
+	return false
+}
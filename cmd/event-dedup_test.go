@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEventsForRule(t *testing.T) {
+	modTime := time.Date(2017, 10, 10, 0, 0, 0, 0, time.UTC)
+	event := eventData{
+		Type:    ObjectCreatedPut,
+		Bucket:  "photos",
+		ObjInfo: ObjectInfo{Name: "january.jpg", Size: 10, MD5Sum: "etag", ModTime: modTime},
+	}
+	nEvent := []NotificationEvent{{
+		EventName:        ObjectCreatedPut.String(),
+		ResponseElements: map[string]string{responseRequestIDKey: "1"},
+		S3: eventMeta{
+			ConfigurationID: eventConfigID,
+			Object:          objectMeta{Key: "january.jpg", Sequencer: "1"},
+		},
+	}}
+	contentID := eventContentID(event, nEvent[0])
+
+	// Events notified again for the same object state share their IDs.
+	replayed := nEvent[0]
+	replayed.S3.Object.Sequencer = "2"
+	if replayedID := eventContentID(event, replayed); replayedID != contentID {
+		t.Errorf("Expected replayed event to have the content ID %s, got %s", contentID, replayedID)
+	}
+	modified := event
+	modified.ObjInfo.MD5Sum = "other-etag"
+	if eventContentID(modified, nEvent[0]) == contentID {
+		t.Error("Expected modified object to have another content ID")
+	}
+
+	ruleEvents := eventsForRule("rule1", contentID, nEvent)
+	if ruleEvents[0].S3.ConfigurationID != "rule1" {
+		t.Errorf("Expected configuration ID rule1, got %s", ruleEvents[0].S3.ConfigurationID)
+	}
+	eventID := ruleEvents[0].ResponseElements[responseEventIDKey]
+	if eventID != getEventID("rule1", contentID) || ruleEvents[0].ResponseElements[responseRequestIDKey] != "1" {
+		t.Errorf("Unexpected response elements %v", ruleEvents[0].ResponseElements)
+	}
+	if _, ok := nEvent[0].ResponseElements[responseEventIDKey]; ok || nEvent[0].S3.ConfigurationID != eventConfigID {
+		t.Error("Expected the events of the rule to be copies")
+	}
+	if getEventID("rule2", contentID) == eventID {
+		t.Error("Expected events of another rule to have another ID")
+	}
+	if ruleEvents = eventsForRule("", contentID, nEvent); ruleEvents[0].S3.ConfigurationID != eventConfigID {
+		t.Errorf("Expected default configuration ID, got %s", ruleEvents[0].S3.ConfigurationID)
+	}
+}
+
+func TestEventDeduper(t *testing.T) {
+	var nilDeduper *eventDeduper
+	if nilDeduper.isDuplicate("arn", "1") || nilDeduper.isDuplicate("arn", "1") {
+		t.Fatal("Expected disabled deduplication to never drop events")
+	}
+
+	d := newEventDeduper(time.Hour)
+	if d.isDuplicate("arn1", "1") {
+		t.Fatal("Expected first event not to be a duplicate")
+	}
+	if !d.isDuplicate("arn1", "1") {
+		t.Fatal("Expected event sent again to be a duplicate")
+	}
+	if d.isDuplicate("arn2", "1") || d.isDuplicate("arn1", "2") {
+		t.Fatal("Expected events of other targets and IDs not to be duplicates")
+	}
+
+	// Expired events are sent again.
+	d.sent["arn1\x001"] = time.Now().UTC().Add(-time.Second)
+	if d.isDuplicate("arn1", "1") {
+		t.Fatal("Expected expired event not to be a duplicate")
+	}
+}
+
+func TestGetNotifyDedupWindow(t *testing.T) {
+	defer os.Unsetenv(notifyDedupWindowEnv)
+
+	testCases := []struct {
+		value    string
+		window   time.Duration
+		hasError bool
+	}{
+		{"", 0, false},
+		{"10m", 10 * time.Minute, false},
+		{"-1m", 0, true},
+		{"forever", 0, true},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(notifyDedupWindowEnv, testCase.value)
+		window, err := getNotifyDedupWindow()
+		if (err != nil) != testCase.hasError || window != testCase.window {
+			t.Errorf("Test %d: unexpected window %s, error %v", i+1, window, err)
+		}
+	}
+}
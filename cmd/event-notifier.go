@@ -18,12 +18,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net"
 	"net/url"
 	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,6 +42,9 @@ type externalNotifier struct {
 	// from an ARN to a log object
 	targets map[string]*logrus.Logger
 
+	// Drops events sent again to a target, nil unless enabled.
+	dedup *eventDeduper
+
 	rwMutex *sync.RWMutex
 }
 
@@ -141,8 +146,8 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	// Escape the object name. For example "red flower.jpg" becomes "red+flower.jpg".
 	escapedObj := url.QueryEscape(event.ObjInfo.Name)
 
-	// For delete object event type, we do not need to set ETag and Size.
-	if event.Type == ObjectRemovedDelete {
+	// For delete object event types, we do not need to set ETag and Size.
+	if event.Type == ObjectRemovedDelete || event.Type == ObjectRemovedMultipartUploadExpired {
 		nEvent.S3.Object = objectMeta{
 			Key:       escapedObj,
 			Sequencer: uniqueID,
@@ -162,12 +167,33 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	return nEvent
 }
 
-// Fetch the external target. No locking needed here since this map is
-// never written after initial startup.
+// Fetch the external target.
 func (en eventNotifier) GetExternalTarget(queueARN string) *logrus.Logger {
+	en.external.rwMutex.RLock()
+	defer en.external.rwMutex.RUnlock()
 	return en.external.targets[queueARN]
 }
 
+// GetExternalTargetARNs - returns the sorted ARNs of all the external targets.
+func (en eventNotifier) GetExternalTargetARNs() []string {
+	en.external.rwMutex.RLock()
+	defer en.external.rwMutex.RUnlock()
+	var arns []string
+	for queueARN := range en.external.targets {
+		arns = append(arns, queueARN)
+	}
+	sort.Strings(arns)
+	return arns
+}
+
+// SetExternalTargets - replaces all the external targets, when the
+// config is reloaded.
+func (en *eventNotifier) SetExternalTargets(targets map[string]*logrus.Logger) {
+	en.external.rwMutex.Lock()
+	defer en.external.rwMutex.Unlock()
+	en.external.targets = targets
+}
+
 func (en eventNotifier) GetInternalTarget(arn string) *listenerLogger {
 	en.internal.rwMutex.RLock()
 	defer en.internal.rwMutex.RUnlock()
@@ -250,7 +276,7 @@ func (en *eventNotifier) SetBucketListenerConfig(bucket string, lcfg []listenerC
 	return nil
 }
 
-func eventNotifyForBucketNotifications(eventType, objectName, bucketName string, nEvent []NotificationEvent) {
+func eventNotifyForBucketNotifications(eventType, objectName, bucketName, contentID string, nEvent []NotificationEvent) {
 	nConfig := globalEventNotifier.GetBucketNotificationConfig(bucketName)
 	if nConfig == nil {
 		return
@@ -261,18 +287,24 @@ func eventNotifyForBucketNotifications(eventType, objectName, bucketName string,
 		ruleMatch := filterRuleMatch(objectName, qConfig.Filter.Key.FilterRules)
 		if eventMatch && ruleMatch {
 			targetLog := globalEventNotifier.GetExternalTarget(qConfig.QueueARN)
-			if targetLog != nil {
-				targetLog.WithFields(logrus.Fields{
-					"Key":       path.Join(bucketName, objectName),
-					"EventType": eventType,
-					"Records":   nEvent,
-				}).Info()
+			if targetLog == nil {
+				continue
+			}
+			eventID := getEventID(qConfig.ID, contentID)
+			if globalEventNotifier.external.dedup.isDuplicate(qConfig.QueueARN, eventID) {
+				continue
 			}
+			targetLog.WithFields(logrus.Fields{
+				"Key":       path.Join(bucketName, objectName),
+				"EventType": eventType,
+				"EventID":   eventID,
+				"Records":   eventsForRule(qConfig.ID, contentID, nEvent),
+			}).Info()
 		}
 	}
 }
 
-func eventNotifyForBucketListeners(eventType, objectName, bucketName string,
+func eventNotifyForBucketListeners(eventType, objectName, bucketName, contentID string,
 	nEvent []NotificationEvent) {
 	lCfgs := globalEventNotifier.GetBucketListenerConfig(bucketName)
 	if lCfgs == nil {
@@ -289,7 +321,7 @@ func eventNotifyForBucketListeners(eventType, objectName, bucketName string,
 				targetLog.log.WithFields(logrus.Fields{
 					"Key":       path.Join(bucketName, objectName),
 					"EventType": eventType,
-					"Records":   nEvent,
+					"Records":   eventsForRule(lcfg.TopicConfig.ID, contentID, nEvent),
 				}).Info()
 			}
 		}
@@ -306,7 +338,9 @@ func eventNotify(event eventData) {
 	//  - s3:ObjectCreated:Post
 	//  - s3:ObjectCreated:Copy
 	//  - s3:ObjectCreated:CompleteMultipartUpload
+	//  - s3:ObjectCreated:Compose
 	//  - s3:ObjectRemoved:Delete
+	//  - s3:ObjectRemoved:MultipartUploadExpired
 
 	// Event type.
 	eventType := event.Type.String()
@@ -317,11 +351,14 @@ func eventNotify(event eventData) {
 	// Save the notification event to be sent.
 	notificationEvent := []NotificationEvent{newNotificationEvent(event)}
 
+	// Events of the same object state share their IDs.
+	contentID := eventContentID(event, notificationEvent[0])
+
 	// Notify external targets.
-	eventNotifyForBucketNotifications(eventType, objectName, event.Bucket, notificationEvent)
+	eventNotifyForBucketNotifications(eventType, objectName, event.Bucket, contentID, notificationEvent)
 
 	// Notify internal targets.
-	eventNotifyForBucketListeners(eventType, objectName, event.Bucket, notificationEvent)
+	eventNotifyForBucketListeners(eventType, objectName, event.Bucket, contentID, notificationEvent)
 }
 
 // loads notification config if any for a given bucket, returns
@@ -331,12 +368,12 @@ func loadNotificationConfig(bucket string, objAPI ObjectLayer) (*notificationCon
 	ncPath := path.Join(bucketConfigPrefix, bucket, bucketNotificationConfig)
 
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, ncPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ncPath)
 	objLock.RLock()
 	defer objLock.RUnlock()
 
 	var buffer bytes.Buffer
-	err := objAPI.GetObject(minioMetaBucket, ncPath, 0, -1, &buffer) // Read everything.
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, ncPath, 0, -1, &buffer) // Read everything.
 	if err != nil {
 		// 'notification.xml' not found return
 		// 'errNoSuchNotifications'.  This is default when no
@@ -374,12 +411,12 @@ func loadListenerConfig(bucket string, objAPI ObjectLayer) ([]listenerConfig, er
 	lcPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
 
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, lcPath)
 	objLock.RLock()
 	defer objLock.RUnlock()
 
 	var buffer bytes.Buffer
-	err := objAPI.GetObject(minioMetaBucket, lcPath, 0, -1, &buffer)
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, lcPath, 0, -1, &buffer)
 	if err != nil {
 		// 'notification.xml' not found return
 		// 'errNoSuchNotifications'.  This is default when no
@@ -415,13 +452,13 @@ func persistNotificationConfig(bucket string, ncfg *notificationConfig, obj Obje
 	// build path
 	ncPath := path.Join(bucketConfigPrefix, bucket, bucketNotificationConfig)
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, ncPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ncPath)
 	objLock.Lock()
 	defer objLock.Unlock()
 
 	// write object to path
 	sha256Sum := getSHA256Hash(buf)
-	_, err = obj.PutObject(minioMetaBucket, ncPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	_, err = obj.PutObject(context.Background(), minioMetaBucket, ncPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
 	if err != nil {
 		errorIf(err, "Unable to write bucket notification configuration.")
 		return err
@@ -440,13 +477,13 @@ func persistListenerConfig(bucket string, lcfg []listenerConfig, obj ObjectLayer
 	// build path
 	lcPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, lcPath)
 	objLock.Lock()
 	defer objLock.Unlock()
 
 	// write object to path
 	sha256Sum := getSHA256Hash(buf)
-	_, err = obj.PutObject(minioMetaBucket, lcPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	_, err = obj.PutObject(context.Background(), minioMetaBucket, lcPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
 	if err != nil {
 		errorIf(err, "Unable to write bucket listener configuration to object layer.")
 	}
@@ -463,9 +500,9 @@ func removeNotificationConfig(bucket string, objAPI ObjectLayer) error {
 	ncPath := path.Join(bucketConfigPrefix, bucket, bucketNotificationConfig)
 
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, ncPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ncPath)
 	objLock.Lock()
-	err := objAPI.DeleteObject(minioMetaBucket, ncPath)
+	err := objAPI.DeleteObject(context.Background(), minioMetaBucket, ncPath)
 	objLock.Unlock()
 	return err
 }
@@ -476,9 +513,9 @@ func removeListenerConfig(bucket string, objAPI ObjectLayer) error {
 	lcPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
 
 	// Acquire a write lock on notification config before modifying.
-	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, lcPath)
 	objLock.Lock()
-	err := objAPI.DeleteObject(minioMetaBucket, lcPath)
+	err := objAPI.DeleteObject(context.Background(), minioMetaBucket, lcPath)
 	objLock.Unlock()
 	return err
 }
@@ -502,7 +539,7 @@ func loadNotificationAndListenerConfig(bucketName string, objAPI ObjectLayer) (n
 // loads all bucket notifications if present.
 func loadAllBucketNotifications(objAPI ObjectLayer) (map[string]*notificationConfig, map[string][]listenerConfig, error) {
 	// List buckets to proceed loading all notification configuration.
-	buckets, err := objAPI.ListBuckets()
+	buckets, err := objAPI.ListBuckets(context.Background())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -710,6 +747,83 @@ func loadAllQueueTargets() (map[string]*logrus.Logger, error) {
 		}
 		queueTargets[queueARN] = kafkaLog
 	}
+	// Load NSQ targets, initialize their respective loggers.
+	for accountID, nsqN := range serverConfig.GetNSQ() {
+		if !nsqN.Enable {
+			continue
+		}
+		// Construct the queue ARN for NSQ.
+		queueARN := minioSqs + serverConfig.GetRegion() + ":" + accountID + ":" + queueTypeNSQ
+		_, ok := queueTargets[queueARN]
+		if ok {
+			continue
+		}
+		// Using accountID initialize a new NSQ logrus instance.
+		nsqLog, err := newNSQNotify(accountID)
+		if err != nil {
+			// Encapsulate network error to be more informative.
+			if _, ok := err.(net.Error); ok {
+				return nil, &net.OpError{
+					Op: "Connecting to " + queueARN, Net: "tcp",
+					Err: err,
+				}
+			}
+			return nil, err
+		}
+		queueTargets[queueARN] = nsqLog
+	}
+
+	// Load Pub/Sub targets, initialize their respective loggers.
+	for accountID, pubsubN := range serverConfig.GetPubSub() {
+		if !pubsubN.Enable {
+			continue
+		}
+		// Construct the queue ARN for Pub/Sub.
+		queueARN := minioSqs + serverConfig.GetRegion() + ":" + accountID + ":" + queueTypePubSub
+		_, ok := queueTargets[queueARN]
+		if ok {
+			continue
+		}
+		// Using accountID initialize a new Pub/Sub logrus instance.
+		pubsubLog, err := newPubSubNotify(accountID)
+		if err != nil {
+			// Encapsulate network error to be more informative.
+			if _, ok := err.(net.Error); ok {
+				return nil, &net.OpError{
+					Op: "Connecting to " + queueARN, Net: "tcp",
+					Err: err,
+				}
+			}
+			return nil, err
+		}
+		queueTargets[queueARN] = pubsubLog
+	}
+
+	// Load Event Hubs targets, initialize their respective loggers.
+	for accountID, eventHubsN := range serverConfig.GetEventHubs() {
+		if !eventHubsN.Enable {
+			continue
+		}
+		// Construct the queue ARN for Event Hubs.
+		queueARN := minioSqs + serverConfig.GetRegion() + ":" + accountID + ":" + queueTypeEventHubs
+		_, ok := queueTargets[queueARN]
+		if ok {
+			continue
+		}
+		// Using accountID initialize a new Event Hubs logrus instance.
+		eventHubsLog, err := newEventHubsNotify(accountID)
+		if err != nil {
+			// Encapsulate network error to be more informative.
+			if _, ok := err.(net.Error); ok {
+				return nil, &net.OpError{
+					Op: "Connecting to " + queueARN, Net: "tcp",
+					Err: err,
+				}
+			}
+			return nil, err
+		}
+		queueTargets[queueARN] = eventHubsLog
+	}
 
 	// Successfully initialized queue targets.
 	return queueTargets, nil
@@ -737,6 +851,15 @@ func initEventNotifier(objAPI ObjectLayer) error {
 		return err
 	}
 
+	dedupWindow, err := getNotifyDedupWindow()
+	if err != nil {
+		return err
+	}
+	var dedup *eventDeduper
+	if dedupWindow > 0 {
+		dedup = newEventDeduper(dedupWindow)
+	}
+
 	// Initialize internal listener targets
 	listenTargets := make(map[string]*listenerLogger)
 	for _, listeners := range lConfigs {
@@ -759,6 +882,7 @@ func initEventNotifier(objAPI ObjectLayer) error {
 		external: externalNotifier{
 			notificationConfigs: nConfigs,
 			targets:             queueTargets,
+			dedup:               dedup,
 			rwMutex:             &sync.RWMutex{},
 		},
 		internal: internalNotifier{
@@ -771,3 +895,40 @@ func initEventNotifier(objAPI ObjectLayer) error {
 
 	return nil
 }
+
+// reloadBucketNotifications - replaces the notification and listener
+// configs of all the buckets with the ones saved in objAPI, keeping
+// the targets and the connected listeners of the event notifier.
+func reloadBucketNotifications(objAPI ObjectLayer) error {
+	if globalEventNotifier == nil {
+		return initEventNotifier(objAPI)
+	}
+
+	nConfigs, lConfigs, err := loadAllBucketNotifications(objAPI)
+	if err != nil {
+		return err
+	}
+
+	en := globalEventNotifier
+	en.external.rwMutex.Lock()
+	en.external.notificationConfigs = nConfigs
+	en.external.rwMutex.Unlock()
+
+	en.internal.rwMutex.Lock()
+	defer en.internal.rwMutex.Unlock()
+	for _, listeners := range lConfigs {
+		for _, listener := range listeners {
+			arn := listener.TopicConfig.TopicARN
+			if _, ok := en.internal.targets[arn]; ok {
+				continue
+			}
+			ln, err := newListenerLogger(arn, listener.TargetServer)
+			if err != nil {
+				return err
+			}
+			en.internal.targets[arn] = ln
+		}
+	}
+	en.internal.listenerConfigs = lConfigs
+	return nil
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"reflect"
@@ -50,7 +51,7 @@ func TestInitEventNotifierFaultyDisks(t *testing.T) {
 	}
 
 	bucketName := "bucket"
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error:", err)
 	}
 
@@ -66,7 +67,7 @@ func TestInitEventNotifierFaultyDisks(t *testing.T) {
 	notificationXML += "</NotificationConfiguration>"
 	size := int64(len([]byte(notificationXML)))
 	reader := bytes.NewReader([]byte(notificationXML))
-	if _, err := xl.PutObject(minioMetaBucket, bucketConfigPrefix+"/"+bucketName+"/"+bucketNotificationConfig, size, reader, nil, ""); err != nil {
+	if _, err := xl.PutObject(context.Background(), minioMetaBucket, bucketConfigPrefix+"/"+bucketName+"/"+bucketNotificationConfig, size, reader, nil, ""); err != nil {
 		t.Fatal("Unexpected error:", err)
 	}
 
@@ -377,7 +378,7 @@ func TestInitEventNotifier(t *testing.T) {
 	}
 
 	// create bucket
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error:", err)
 	}
 
@@ -424,6 +425,60 @@ func TestInitEventNotifier(t *testing.T) {
 	}
 }
 
+// Tests reloading the notification configs keeps the connected listeners.
+func TestReloadBucketNotifications(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal("Unable to initialize FS backend.", err)
+	}
+	defer removeAll(fsDir)
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if err = initEventNotifier(obj); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	listenerCh := make(chan []NotificationEvent, 1)
+	if err = globalEventNotifier.AddListenerChan("testlARN", listenerCh); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	ncfg := notificationConfig{
+		QueueConfigs: []queueConfig{
+			{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}, ID: "1"},
+				QueueARN:      "testqARN",
+			},
+		},
+	}
+	if err = persistNotificationConfig(bucketName, &ncfg, obj); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if err = reloadBucketNotifications(obj); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if cfg := globalEventNotifier.GetBucketNotificationConfig(bucketName); cfg == nil || len(cfg.QueueConfigs) != 1 {
+		t.Fatalf("Expected the reloaded notification config of %s, got %v", bucketName, cfg)
+	}
+	if err = globalEventNotifier.SendListenerEvent("testlARN", []NotificationEvent{{}}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	select {
+	case <-listenerCh:
+	default:
+		t.Fatal("Expected the listener to stay connected")
+	}
+}
+
 func TestListenBucketNotification(t *testing.T) {
 	currentIsDistXL := globalIsDistXL
 	defer func() {
@@ -442,7 +497,7 @@ func TestListenBucketNotification(t *testing.T) {
 	objectName := "object"
 
 	// Create the bucket to listen on
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error:", err)
 	}
 
@@ -552,7 +607,7 @@ func TestAddRemoveBucketListenerConfig(t *testing.T) {
 
 	// Make a bucket to store topicConfigs.
 	randBucket := getRandomBucketName()
-	if err := obj.MakeBucket(randBucket); err != nil {
+	if err := obj.MakeBucket(context.Background(), randBucket); err != nil {
 		t.Fatalf("Failed to make bucket %s", randBucket)
 	}
 
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -825,7 +826,7 @@ func saveFormatXL(storageDisks []StorageAPI, formats []*formatConfigV1) error {
 			disk.DeleteFile(minioMetaBucket, formatConfigFileTmp)
 
 			// Append file `format.json.tmp`.
-			if err = disk.AppendFile(minioMetaBucket, formatConfigFileTmp, formatBytes); err != nil {
+			if err = disk.AppendFile(context.Background(), minioMetaBucket, formatConfigFileTmp, formatBytes); err != nil {
 				errs[index] = err
 				return
 			}
@@ -141,6 +141,12 @@ func (fs fsObjects) appendParts(bucket, object, uploadID string, info bgAppendPa
 	appendPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, uploadID)
 	// Holds the list of parts that is already appended to the "append" file.
 	appendMeta := fsMetaV1{}
+	// Size of the "append" file.
+	var appendSize int64
+
+	// An "append" file left behind by a previous appendParts go-routine, for
+	// ex. before a restart, does not have the parts in appendMeta.
+	fsRemoveFile(appendPath)
 
 	// Allocate staging read buffer.
 	buf := make([]byte, readSizeV1)
@@ -168,14 +174,17 @@ func (fs fsObjects) appendParts(bucket, object, uploadID string, info bgAppendPa
 					break
 				}
 
-				if err := fs.appendPart(bucket, object, uploadID, part, buf); err != nil {
+				n, err := fs.appendPart(bucket, object, uploadID, part, appendSize, buf)
+				if err != nil {
 					fsRemoveFile(appendPath)
 					appendMeta.Parts = nil
+					appendSize = 0
 					input.errCh <- err
 					break
 				}
 
 				appendMeta.AddObjectPart(part.Number, part.Name, part.ETag, part.Size)
+				appendSize += n
 			}
 		case <-info.abortCh:
 			// abort-multipart-upload closed abortCh to end the appendParts go-routine.
@@ -205,9 +214,9 @@ func (fs fsObjects) appendParts(bucket, object, uploadID string, info bgAppendPa
 	}
 }
 
-// Appends the "part" to the append-file inside "tmp/" that finally gets moved to the actual location
-// upon complete-multipart-upload.
-func (fs fsObjects) appendPart(bucket, object, uploadID string, part objectPartInfo, buf []byte) error {
+// Appends the "part" to the append-file inside "tmp/" of size appendSize, that finally gets moved to
+// the actual location upon complete-multipart-upload. Returns the number of bytes appended.
+func (fs fsObjects) appendPart(bucket, object, uploadID string, part objectPartInfo, appendSize int64, buf []byte) (int64, error) {
 	partPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, bucket, object, uploadID, part.Name)
 
 	offset := int64(0)
@@ -215,9 +224,9 @@ func (fs fsObjects) appendPart(bucket, object, uploadID string, part objectPartI
 	file, size, err := fsOpenFile(partPath, offset)
 	if err != nil {
 		if err == errFileNotFound {
-			return errPartsMissing
+			return 0, errPartsMissing
 		}
-		return err
+		return 0, err
 	}
 	defer file.Close()
 
@@ -226,15 +235,14 @@ func (fs fsObjects) appendPart(bucket, object, uploadID string, part objectPartI
 	// to one one process per uploadID per minio process.
 	wfile, err := os.OpenFile(preparePath(tmpObjPath), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer wfile.Close()
 
 	// Fallocate more space as we concatenate.
-	if err = fsFAllocate(int(wfile.Fd()), 0, size); err != nil {
-		return err
+	if err = fsFAllocate(int(wfile.Fd()), appendSize, size); err != nil {
+		return 0, err
 	}
 
-	_, err = io.CopyBuffer(wfile, file, buf)
-	return err
+	return io.CopyBuffer(wfile, file, buf)
 }
@@ -20,8 +20,12 @@ import (
 	"io"
 	"os"
 	pathutil "path"
+	"sync"
 )
 
+// Number of files fsConcatFiles copies at a time.
+const fsConcatParallelism = 4
+
 // Removes only the file at given path does not remove
 // any parent directories, handles long paths for
 // windows automatically.
@@ -264,6 +268,123 @@ func fsCreateFile(tempObjPath string, reader io.Reader, buf []byte, fallocSize i
 	return bytesWritten, nil
 }
 
+// fileOffsetWriter - writes sequentially to a file from an offset.
+type fileOffsetWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func (w *fileOffsetWriter) Write(p []byte) (int, error) {
+	n, err := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// Concatenates the files at srcPaths, of the given sizes, into a new file at
+// dstPath. The destination is preallocated and the files are copied in
+// parallel, each at its offset. Returns errFileNotFound if a file is missing
+// and errCorruptedFormat if its size does not match.
+func fsConcatFiles(dstPath string, srcPaths []string, sizes []int64) error {
+	if dstPath == "" || len(srcPaths) != len(sizes) {
+		return errInvalidArgument
+	}
+
+	if err := checkPathLength(dstPath); err != nil {
+		return err
+	}
+
+	if err := mkdirAll(pathutil.Dir(dstPath), 0777); err != nil {
+		return err
+	}
+
+	writer, err := os.OpenFile(preparePath(dstPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		// File path cannot be verified since one of the parents is a file.
+		if isSysErrNotDir(err) {
+			return errFileAccessDenied
+		}
+		return err
+	}
+	defer writer.Close()
+
+	var totalSize int64
+	offsets := make([]int64, len(sizes))
+	for i, size := range sizes {
+		offsets[i] = totalSize
+		totalSize += size
+	}
+	if err = fsFAllocate(int(writer.Fd()), 0, totalSize); err != nil {
+		return err
+	}
+	if err = writer.Truncate(totalSize); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(srcPaths))
+	tokens := make(chan struct{}, fsConcatParallelism)
+	for i := range srcPaths {
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-tokens }()
+
+			reader, size, rerr := fsOpenFile(srcPaths[i], 0)
+			if rerr != nil {
+				errs[i] = rerr
+				return
+			}
+			defer reader.Close()
+			if size != sizes[i] {
+				errs[i] = errCorruptedFormat
+				return
+			}
+			buf := make([]byte, readSizeV1)
+			_, errs[i] = io.CopyBuffer(&fileOffsetWriter{writer, offsets[i]}, reader, buf)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err = range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Overwrites the contents of a file of a bucket with secure delete
+// enabled, before the file is removed or replaced.
+func fsShredFile(bucket, filePath string) error {
+	if !globalBucketSecureDelete.Enabled(bucket) {
+		return nil
+	}
+	if err := checkPathLength(filePath); err != nil {
+		return err
+	}
+	return shredFile(filePath)
+}
+
+// Overwrites the contents of all the uploaded parts at uploadID path
+// of a bucket with secure delete enabled, before they are removed.
+func fsShredUploadIDPath(bucket, uploadIDPath string) error {
+	if !globalBucketSecureDelete.Enabled(bucket) {
+		return nil
+	}
+
+	entries, err := readDir(uploadIDPath)
+	if err != nil && err != errFileNotFound {
+		return err
+	}
+	for _, entryPath := range entries {
+		if err = fsShredFile(bucket, pathJoin(uploadIDPath, entryPath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Removes uploadID at destination path.
 func fsRemoveUploadIDPath(basePath, uploadIDPath string) error {
 	if basePath == "" || uploadIDPath == "" {
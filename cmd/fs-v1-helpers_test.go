@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
 	"testing"
 )
 
@@ -215,6 +217,60 @@ func TestFSCreateAndOpen(t *testing.T) {
 	}
 }
 
+// Tests concatenating files in parallel.
+func TestFSConcatFiles(t *testing.T) {
+	// Setup test environment.
+	_, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer removeAll(path)
+
+	if err = fsConcatFiles("", nil, []int64{1}); err != errInvalidArgument {
+		t.Fatal("Unexpected error", err)
+	}
+
+	var srcPaths []string
+	var sizes []int64
+	var expected []byte
+	buf := make([]byte, 4096)
+	for i := 0; i < 2*fsConcatParallelism+1; i++ {
+		data := bytes.Repeat([]byte{byte('a' + i)}, 1000*(i+1))
+		srcPath := pathJoin(path, "parts", fmt.Sprintf("part.%d", i+1))
+		if _, err = fsCreateFile(srcPath, bytes.NewReader(data), buf, int64(len(data))); err != nil {
+			t.Fatalf("Unable to create file, %s", err)
+		}
+		srcPaths = append(srcPaths, srcPath)
+		sizes = append(sizes, int64(len(data)))
+		expected = append(expected, data...)
+	}
+
+	dstPath := pathJoin(path, "success-vol", "success-file")
+	if err = fsConcatFiles(dstPath, srcPaths, sizes); err != nil {
+		t.Fatalf("Unable to concatenate files, %s", err)
+	}
+	data, err := ioutil.ReadFile(dstPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Expected %d bytes of concatenated files, got %d", len(expected), len(data))
+	}
+
+	// Size which does not match the file.
+	sizes[1]++
+	if err = fsConcatFiles(dstPath, srcPaths, sizes); err != errCorruptedFormat {
+		t.Fatalf("Expected %v, got %v", errCorruptedFormat, err)
+	}
+
+	// Missing file.
+	sizes[1]--
+	srcPaths[2] = pathJoin(path, "parts", "missing")
+	if err = fsConcatFiles(dstPath, srcPaths, sizes); err != errFileNotFound {
+		t.Fatalf("Expected %v, got %v", errFileNotFound, err)
+	}
+}
+
 func TestFSDeletes(t *testing.T) {
 	// create posix test setup
 	_, path, err := newPosixTestSetup()
@@ -69,7 +69,7 @@ func (m fsMetaV1) ToObjectInfo(bucket, object string, fi os.FileInfo) ObjectInfo
 	// We set file into only if its valid.
 	objInfo.ModTime = timeSentinel
 	if fi != nil {
-		objInfo.ModTime = fi.ModTime()
+		objInfo.ModTime = sourceModTime(m.Meta, fi.ModTime())
 		objInfo.Size = fi.Size()
 		objInfo.IsDir = fi.IsDir()
 	}
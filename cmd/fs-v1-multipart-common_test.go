@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 )
@@ -32,15 +33,15 @@ func TestFSWriteUploadJSON(t *testing.T) {
 	bucketName := "bucket"
 	objectName := "object"
 
-	obj.MakeBucket(bucketName)
-	_, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	obj.MakeBucket(context.Background(), bucketName)
+	_, err := obj.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
 	if err != nil {
 		t.Fatal("Unexpected err: ", err)
 	}
 
 	// newMultipartUpload will fail.
 	removeAll(disk) // Remove disk.
-	_, err = obj.NewMultipartUpload(bucketName, objectName, nil)
+	_, err = obj.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
 	if err != nil {
 		if _, ok := errorCause(err).(BucketNotFound); !ok {
 			t.Fatal("Unexpected err: ", err)
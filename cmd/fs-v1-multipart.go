@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -31,12 +32,12 @@ import (
 )
 
 // listMultipartUploadIDs - list all the upload ids from a marker up to 'count'.
-func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int) ([]uploadMetadata, bool, error) {
+func (fs fsObjects) listMultipartUploadIDs(ctx context.Context, bucketName, objectName, uploadIDMarker string, count int) ([]uploadMetadata, bool, error) {
 	var uploads []uploadMetadata
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucketName, objectName))
+	objectMPartPathLock := globalNSMutex.NewNSLock(context.Background(), minioMetaMultipartBucket, pathJoin(bucketName, objectName))
 	objectMPartPathLock.RLock()
 	defer objectMPartPathLock.RUnlock()
 
@@ -58,13 +59,16 @@ func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarke
 
 	index := 0
 	if uploadIDMarker != "" {
-		for ; index < len(uploadIDs.Uploads); index++ {
-			if uploadIDs.Uploads[index].UploadID == uploadIDMarker {
+		for i, upload := range uploadIDs.Uploads {
+			if upload.UploadID == uploadIDMarker {
 				// Skip the uploadID as it would already be listed in previous listing.
-				index++
+				index = i + 1
 				break
 			}
 		}
+		// An upload ID marker not found was completed or aborted
+		// since listed, all the uploads left are listed again so
+		// that none are skipped.
 	}
 
 	for index < len(uploadIDs.Uploads) {
@@ -85,7 +89,7 @@ func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarke
 }
 
 // listMultipartUploads - lists all multipart uploads.
-func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+func (fs fsObjects) listMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
 	result := ListMultipartsInfo{}
 	recursive := true
 	if delimiter == slashSeparator {
@@ -95,6 +99,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 	result.IsTruncated = true
 	result.MaxUploads = maxUploads
 	result.KeyMarker = keyMarker
+	result.UploadIDMarker = uploadIDMarker
 	result.Prefix = prefix
 	result.Delimiter = delimiter
 
@@ -115,7 +120,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 	var eof bool
 
 	if uploadIDMarker != "" {
-		uploads, _, err = fs.listMultipartUploadIDs(bucket, keyMarker, uploadIDMarker, maxUploads)
+		uploads, _, err = fs.listMultipartUploadIDs(ctx, bucket, keyMarker, uploadIDMarker, maxUploads)
 		if err != nil {
 			return ListMultipartsInfo{}, err
 		}
@@ -180,7 +185,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 			var end bool
 			uploadIDMarker = ""
 
-			tmpUploads, end, err = fs.listMultipartUploadIDs(bucket, entry, uploadIDMarker, maxUploads)
+			tmpUploads, end, err = fs.listMultipartUploadIDs(ctx, bucket, entry, uploadIDMarker, maxUploads)
 			if err != nil {
 				return ListMultipartsInfo{}, err
 			}
@@ -212,10 +217,11 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		result.NextUploadIDMarker = uploadID
 	}
 
-	if !eof {
+	if !eof && walkResultCh != nil {
 		// Save the go-routine state in the pool so that it can continue from where it left off on
-		// the next request.
-		fs.listPool.Set(listParams{bucket, recursive, result.NextKeyMarker, prefix, heal}, walkResultCh, endWalkCh)
+		// the next request, which lists from the next key marker.
+		nextMarkerPath := pathJoin(bucket, result.NextKeyMarker)
+		fs.listPool.Set(listParams{minioMetaMultipartBucket, recursive, nextMarkerPath, multipartPrefixPath, heal}, walkResultCh, endWalkCh)
 	}
 
 	result.IsTruncated = !eof
@@ -237,7 +243,8 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // Implements S3 compatible ListMultipartUploads API. The resulting
 // ListMultipartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (fs fsObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+func (fs fsObjects) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	prefix, keyMarker = fs.normalizeName(prefix), fs.normalizeName(keyMarker)
 	if err := checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter, fs); err != nil {
 		return ListMultipartsInfo{}, err
 	}
@@ -246,7 +253,7 @@ func (fs fsObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		return ListMultipartsInfo{}, toObjectErr(err, bucket)
 	}
 
-	return fs.listMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+	return fs.listMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 }
 
 // newMultipartUpload - wrapper for initializing a new multipart
@@ -301,7 +308,8 @@ func (fs fsObjects) newMultipartUpload(bucket string, object string, meta map[st
 // subsequent request each UUID is unique.
 //
 // Implements S3 compatible initiate multipart API.
-func (fs fsObjects) NewMultipartUpload(bucket, object string, meta map[string]string) (string, error) {
+func (fs fsObjects) NewMultipartUpload(ctx context.Context, bucket, object string, meta map[string]string) (string, error) {
+	object = fs.normalizeName(object)
 	if err := checkNewMultipartArgs(bucket, object, fs); err != nil {
 		return "", err
 	}
@@ -312,7 +320,7 @@ func (fs fsObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
 
@@ -339,7 +347,8 @@ func partToAppend(fsMeta fsMetaV1, fsAppendMeta fsMetaV1) (part objectPartInfo,
 // an ongoing multipart transaction. Internally incoming data is
 // written to '.minio.sys/tmp' location and safely renamed to
 // '.minio.sys/multipart' for reach parts.
-func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+func (fs fsObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+	object = fs.normalizeName(object)
 	if err := checkPutObjectPartArgs(bucket, object, fs); err != nil {
 		return "", err
 	}
@@ -350,7 +359,7 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
 
@@ -450,7 +459,7 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	partPath := pathJoin(bucket, object, uploadID, partSuffix)
 	// Lock the part so that another part upload with same part-number gets blocked
 	// while the part is getting appended in the background.
-	partLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, partPath)
+	partLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, partPath)
 	partLock.Lock()
 
 	fsNSPartPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, partPath)
@@ -460,7 +469,7 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	}
 
 	// Save the object part info in `fs.json`.
-	fsMeta.AddObjectPart(partID, partSuffix, newMD5Hex, size)
+	fsMeta.AddObjectPart(partID, partSuffix, newMD5Hex, bytesWritten)
 	if _, err = fsMeta.WriteTo(rwlk); err != nil {
 		partLock.Unlock()
 		return "", toObjectErr(err, minioMetaMultipartBucket, uploadIDPath)
@@ -504,13 +513,25 @@ func (fs fsObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 		return ListPartsInfo{}, toObjectErr(err, minioMetaBucket, fsMetaPath)
 	}
 
-	// Only parts with higher part numbers will be listed.
-	partIdx := fsMeta.ObjectPartIndex(partNumberMarker)
-	parts := fsMeta.Parts
-	if partIdx != -1 {
-		parts = fsMeta.Parts[partIdx+1:]
+	result.Bucket = bucket
+	result.Object = object
+	result.UploadID = uploadID
+	result.PartNumberMarker = partNumberMarker
+	result.MaxParts = maxParts
+
+	// For empty number of parts or maxParts as zero, return right here.
+	if len(fsMeta.Parts) == 0 || maxParts == 0 {
+		return result, nil
 	}
 
+	// Limit output to maxPartsList.
+	if maxParts > maxPartsList {
+		maxParts = maxPartsList
+	}
+
+	// Only parts with higher part numbers will be listed.
+	parts := partsAfterMarker(fsMeta.Parts, partNumberMarker)
+
 	count := maxParts
 	for _, part := range parts {
 		var fi os.FileInfo
@@ -539,10 +560,6 @@ func (fs fsObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 		nextPartNumberMarker := result.Parts[len(result.Parts)-1].PartNumber
 		result.NextPartNumberMarker = nextPartNumberMarker
 	}
-	result.Bucket = bucket
-	result.Object = object
-	result.UploadID = uploadID
-	result.MaxParts = maxParts
 
 	// Success.
 	return result, nil
@@ -555,7 +572,8 @@ func (fs fsObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 // Implements S3 compatible ListObjectParts API. The resulting
 // ListPartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+func (fs fsObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+	object = fs.normalizeName(object)
 	if err := checkListPartsArgs(bucket, object, fs); err != nil {
 		return ListPartsInfo{}, err
 	}
@@ -566,7 +584,7 @@ func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, pathJoin(bucket, object))
 	objectMPartPathLock.RLock()
 	defer objectMPartPathLock.RUnlock()
 
@@ -585,7 +603,8 @@ func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (fs fsObjects) CompleteMultipartUpload(ctx context.Context, bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+	object = fs.normalizeName(object)
 	if err := checkCompleteMultipartArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -604,7 +623,7 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
 
@@ -657,6 +676,11 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		if err == nil {
 			appendFallback = false
 			fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, uploadID)
+			// Shred the contents of an object being overwritten.
+			if err = fsShredFile(bucket, fsNSObjPath); err != nil {
+				fs.rwPool.Close(fsMetaPathMultipart)
+				return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+			}
 			if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
 				fs.rwPool.Close(fsMetaPathMultipart)
 				return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, uploadID)
@@ -674,20 +698,22 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		// nothing to delete.
 		defer fsRemoveFile(fsTmpObjPath)
 
-		// Allocate staging buffer.
-		var buf = make([]byte, readSizeV1)
-
-		// Validate all parts and then commit to disk.
+		// Validate all parts against their stored ETags and sizes
+		// before assembling them.
+		partIndexes := objectPartIndexes(fsMeta.Parts)
+		partPaths := make([]string, len(parts))
+		partSizes := make([]int64, len(parts))
 		for i, part := range parts {
-			partIdx := fsMeta.ObjectPartIndex(part.PartNumber)
-			if partIdx == -1 {
+			partIdx, ok := partIndexes[part.PartNumber]
+			if !ok {
 				fs.rwPool.Close(fsMetaPathMultipart)
 				return ObjectInfo{}, traceError(InvalidPart{})
 			}
 
+			// All parts should have same ETag as previously generated.
 			if fsMeta.Parts[partIdx].ETag != part.ETag {
 				fs.rwPool.Close(fsMetaPathMultipart)
-				return ObjectInfo{}, traceError(BadDigest{})
+				return ObjectInfo{}, traceError(InvalidPart{})
 			}
 
 			// All parts except the last part has to be atleast 5MB.
@@ -702,41 +728,25 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 
 			// Construct part suffix.
 			partSuffix := fmt.Sprintf("object%d", part.PartNumber)
-			multipartPartFile := pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, partSuffix)
-
-			var reader io.ReadCloser
-			offset := int64(0)
-			reader, _, err = fsOpenFile(multipartPartFile, offset)
-			if err != nil {
-				fs.rwPool.Close(fsMetaPathMultipart)
-				if err == errFileNotFound {
-					return ObjectInfo{}, traceError(InvalidPart{})
-				}
-				return ObjectInfo{}, toObjectErr(traceError(err), minioMetaMultipartBucket, partSuffix)
-			}
-
-			// No need to hold a lock, this is a unique file and will be only written
-			// to one one process per uploadID per minio process.
-			var wfile *os.File
-			wfile, err = os.OpenFile(preparePath(fsTmpObjPath), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-			if err != nil {
-				reader.Close()
-				fs.rwPool.Close(fsMetaPathMultipart)
-				return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
-			}
+			partPaths[i] = pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, partSuffix)
+			partSizes[i] = fsMeta.Parts[partIdx].Size
+		}
 
-			_, err = io.CopyBuffer(wfile, reader, buf)
-			if err != nil {
-				wfile.Close()
-				reader.Close()
-				fs.rwPool.Close(fsMetaPathMultipart)
-				return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+		// No need to hold a lock, this is a unique file and will be only written
+		// to one one process per uploadID per minio process.
+		if err = fsConcatFiles(fsTmpObjPath, partPaths, partSizes); err != nil {
+			fs.rwPool.Close(fsMetaPathMultipart)
+			if err == errFileNotFound {
+				return ObjectInfo{}, traceError(InvalidPart{})
 			}
-
-			wfile.Close()
-			reader.Close()
+			return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 		}
 
+		// Shred the contents of an object being overwritten.
+		if err = fsShredFile(bucket, fsNSObjPath); err != nil {
+			fs.rwPool.Close(fsMetaPathMultipart)
+			return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+		}
 		if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
 			fs.rwPool.Close(fsMetaPathMultipart)
 			return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, uploadID)
@@ -766,6 +776,9 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 	// Cleanup all the parts if everything else has been safely committed.
 	multipartObjectDir := pathJoin(fs.fsPath, minioMetaMultipartBucket, bucket, object)
 	multipartUploadIDDir := pathJoin(multipartObjectDir, uploadID)
+	if err = fsShredUploadIDPath(bucket, multipartUploadIDDir); err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
 	if err = fsRemoveUploadIDPath(multipartObjectDir, multipartUploadIDDir); err != nil {
 		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
@@ -779,6 +792,7 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
+	fs.watcher.markChanged(bucket, object)
 
 	// Return object info.
 	return fsMeta.ToObjectInfo(bucket, object, fi), nil
@@ -796,7 +810,8 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 // that this is an atomic idempotent operation. Subsequent calls have
 // no affect and further requests to the same uploadID would not be
 // honored.
-func (fs fsObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+func (fs fsObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	object = fs.normalizeName(object)
 	if err := checkAbortMultipartArgs(bucket, object, fs); err != nil {
 		return err
 	}
@@ -809,7 +824,7 @@ func (fs fsObjects) AbortMultipartUpload(bucket, object, uploadID string) error
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
@@ -844,6 +859,9 @@ func (fs fsObjects) AbortMultipartUpload(bucket, object, uploadID string) error
 	// Cleanup all uploaded parts and abort the upload.
 	multipartObjectDir := pathJoin(fs.fsPath, minioMetaMultipartBucket, bucket, object)
 	multipartUploadIDDir := pathJoin(multipartObjectDir, uploadID)
+	if err = fsShredUploadIDPath(bucket, multipartUploadIDDir); err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
 	if err = fsRemoveUploadIDPath(multipartObjectDir, multipartUploadIDDir); err != nil {
 		return toObjectErr(traceError(err), bucket, object)
 	}
@@ -18,8 +18,11 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"path/filepath"
 	"testing"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // TestNewMultipartUploadFaultyDisk - test NewMultipartUpload with faulty disks
@@ -33,13 +36,13 @@ func TestNewMultipartUploadFaultyDisk(t *testing.T) {
 	bucketName := "bucket"
 	objectName := "object"
 
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Cannot create bucket, err: ", err)
 	}
 
 	// Test with disk removed.
 	removeAll(disk) // remove disk.
-	if _, err := fs.NewMultipartUpload(bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"}); err != nil {
+	if _, err := fs.NewMultipartUpload(context.Background(), bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"}); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error ", err)
 		}
@@ -64,11 +67,11 @@ func TestPutObjectPartFaultyDisk(t *testing.T) {
 	data := []byte("12345")
 	dataLen := int64(len(data))
 
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Cannot create bucket, err: ", err)
 	}
 
-	uploadID, err := fs.NewMultipartUpload(bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
+	uploadID, err := fs.NewMultipartUpload(context.Background(), bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
 	if err != nil {
 		t.Fatal("Unexpected error ", err)
 	}
@@ -77,7 +80,7 @@ func TestPutObjectPartFaultyDisk(t *testing.T) {
 	sha256sum := ""
 
 	removeAll(disk) // Disk not found.
-	_, err = fs.PutObjectPart(bucketName, objectName, uploadID, 1, dataLen, bytes.NewReader(data), md5Hex, sha256sum)
+	_, err = fs.PutObjectPart(context.Background(), bucketName, objectName, uploadID, 1, dataLen, bytes.NewReader(data), md5Hex, sha256sum)
 	if !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("Unexpected error ", err)
 	}
@@ -95,11 +98,11 @@ func TestCompleteMultipartUploadFaultyDisk(t *testing.T) {
 	objectName := "object"
 	data := []byte("12345")
 
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Cannot create bucket, err: ", err)
 	}
 
-	uploadID, err := fs.NewMultipartUpload(bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
+	uploadID, err := fs.NewMultipartUpload(context.Background(), bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
 	if err != nil {
 		t.Fatal("Unexpected error ", err)
 	}
@@ -107,20 +110,70 @@ func TestCompleteMultipartUploadFaultyDisk(t *testing.T) {
 	md5Hex := getMD5Hash(data)
 	sha256sum := ""
 
-	if _, err := fs.PutObjectPart(bucketName, objectName, uploadID, 1, 5, bytes.NewReader(data), md5Hex, sha256sum); err != nil {
+	if _, err := fs.PutObjectPart(context.Background(), bucketName, objectName, uploadID, 1, 5, bytes.NewReader(data), md5Hex, sha256sum); err != nil {
 		t.Fatal("Unexpected error ", err)
 	}
 
 	parts := []completePart{{PartNumber: 1, ETag: md5Hex}}
 
 	removeAll(disk) // Disk not found.
-	if _, err := fs.CompleteMultipartUpload(bucketName, objectName, uploadID, parts); err != nil {
+	if _, err := fs.CompleteMultipartUpload(context.Background(), bucketName, objectName, uploadID, parts); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error ", err)
 		}
 	}
 }
 
+// TestCompleteMultipartUploadBackgroundAppend - test parts uploaded out of order
+// are appended in the background, ignoring any stale append file.
+func TestCompleteMultipartUploadBackgroundAppend(t *testing.T) {
+	// Prepare for tests
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+
+	fs := obj.(*fsObjects)
+	bucketName := "bucket"
+	objectName := "object"
+
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatal("Cannot create bucket, err: ", err)
+	}
+
+	uploadID, err := fs.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// Append file left behind, for ex. before a restart.
+	appendPath := pathJoin(disk, minioMetaTmpBucket, fs.fsUUID, uploadID)
+	if _, err = fsCreateFile(appendPath, bytes.NewReader([]byte("stale")), make([]byte, 5), 5); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	partsData := [][]byte{bytes.Repeat([]byte("a"), 5*humanize.MiByte), []byte("12345")}
+	var parts []completePart
+	for i := len(partsData) - 1; i >= 0; i-- {
+		md5Hex := getMD5Hash(partsData[i])
+		if _, err = fs.PutObjectPart(context.Background(), bucketName, objectName, uploadID, i+1, int64(len(partsData[i])), bytes.NewReader(partsData[i]), md5Hex, ""); err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+		parts = append([]completePart{{PartNumber: i + 1, ETag: md5Hex}}, parts...)
+	}
+
+	if _, err = fs.CompleteMultipartUpload(context.Background(), bucketName, objectName, uploadID, parts); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucketName, objectName, 0, -1, &buffer); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), bytes.Join(partsData, nil)) {
+		t.Fatalf("Unexpected object of %d bytes", buffer.Len())
+	}
+}
+
 // TestListMultipartUploadsFaultyDisk - test ListMultipartUploads with faulty disks
 func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 	// Prepare for tests
@@ -134,11 +187,11 @@ func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 	objectName := "object"
 	data := []byte("12345")
 
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Cannot create bucket, err: ", err)
 	}
 
-	uploadID, err := fs.NewMultipartUpload(bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
+	uploadID, err := fs.NewMultipartUpload(context.Background(), bucketName, objectName, map[string]string{"X-Amz-Meta-xid": "3f"})
 	if err != nil {
 		t.Fatal("Unexpected error ", err)
 	}
@@ -146,12 +199,12 @@ func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 	md5Hex := getMD5Hash(data)
 	sha256sum := ""
 
-	if _, err := fs.PutObjectPart(bucketName, objectName, uploadID, 1, 5, bytes.NewReader(data), md5Hex, sha256sum); err != nil {
+	if _, err := fs.PutObjectPart(context.Background(), bucketName, objectName, uploadID, 1, 5, bytes.NewReader(data), md5Hex, sha256sum); err != nil {
 		t.Fatal("Unexpected error ", err)
 	}
 
 	removeAll(disk) // Disk not found.
-	if _, err := fs.ListMultipartUploads(bucketName, objectName, "", "", "", 1000); err != nil {
+	if _, err := fs.ListMultipartUploads(context.Background(), bucketName, objectName, "", "", "", 1000); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error ", err)
 		}
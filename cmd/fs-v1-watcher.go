@@ -0,0 +1,240 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Environment variable enabling the periodic reconciler of FS
+// backend, set to an interval such as "5m".
+const fsWatchIntervalEnv = "MINIO_FS_WATCH_INTERVAL"
+
+// Smallest allowed reconcile interval, each pass walks the entire
+// namespace.
+const fsWatchMinInterval = 10 * time.Second
+
+// fsWatchKey - identifies an object seen by the reconciler.
+type fsWatchKey struct {
+	bucket, object string
+}
+
+// fsWatchEntry - state of a file seen by the reconciler.
+type fsWatchEntry struct {
+	size    int64
+	modTime time.Time
+}
+
+// fsWatcher - periodically reconciles the namespace of FS backend
+// with the last seen state, sending bucket notification events for
+// objects created, modified or removed outside of Minio.
+type fsWatcher struct {
+	fs       fsObjects
+	interval time.Duration
+
+	// Notifies an event, eventNotify() unless overridden by tests.
+	notify func(eventData)
+
+	mu sync.Mutex
+	// Last seen state, nil until the first pass.
+	entries map[fsWatchKey]fsWatchEntry
+	// Objects modified through the object API, mapped to the pass
+	// during which they were modified.
+	changed map[fsWatchKey]int
+	pass    int
+
+	doneCh chan struct{}
+}
+
+// getFSWatchInterval - returns the reconcile interval configured
+// through the environment, 0 if the reconciler is disabled.
+func getFSWatchInterval() (time.Duration, error) {
+	value := os.Getenv(fsWatchIntervalEnv)
+	if value == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", fsWatchIntervalEnv, value, err)
+	}
+	if interval < fsWatchMinInterval {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be at least %s", fsWatchIntervalEnv, value, fsWatchMinInterval)
+	}
+	return interval, nil
+}
+
+// newFSWatcher - initializes a reconciler for fs, not started yet.
+func newFSWatcher(fs fsObjects, interval time.Duration) *fsWatcher {
+	return &fsWatcher{
+		fs:       fs,
+		interval: interval,
+		notify:   eventNotify,
+		changed:  make(map[fsWatchKey]int),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start - runs the reconciler in the background until Stop is called.
+func (w *fsWatcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		// The first pass only records the current state.
+		errorIf(w.reconcile(), "Unable to scan %s for external changes.", w.fs.fsPath)
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(w.reconcile(), "Unable to scan %s for external changes.", w.fs.fsPath)
+			case <-w.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop - stops the reconciler, safe to be called on nil.
+func (w *fsWatcher) Stop() {
+	if w == nil {
+		return
+	}
+	close(w.doneCh)
+}
+
+// markChanged - records that the object was modified through the
+// object API, which already notifies about it. Safe to be called on nil.
+func (w *fsWatcher) markChanged(bucket, object string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.changed[fsWatchKey{bucket, object}] = w.pass
+	w.mu.Unlock()
+}
+
+// scan - returns the state of all the objects in all the buckets.
+func (w *fsWatcher) scan() (map[fsWatchKey]fsWatchEntry, error) {
+	entries := make(map[fsWatchKey]fsWatchEntry)
+	buckets, err := ioutil.ReadDir(preparePath(w.fs.fsPath))
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		if !bucket.IsDir() || isMinioMetaBucketName(bucket.Name()) || !IsValidBucketName(bucket.Name()) {
+			continue
+		}
+		bucketDir := pathJoin(w.fs.fsPath, bucket.Name())
+		err = filepath.Walk(bucketDir, func(filePath string, fi os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				// Entries removed while walking.
+				if fi != nil && fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+			relPath, err := filepath.Rel(bucketDir, filePath)
+			if err != nil {
+				return err
+			}
+			object := filepath.ToSlash(relPath)
+			if !IsValidObjectName(object) {
+				return nil
+			}
+			entries[fsWatchKey{bucket.Name(), object}] = fsWatchEntry{fi.Size(), fi.ModTime()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// reconcile - scans the namespace and notifies about the differences
+// with the previous pass not caused by the object API.
+func (w *fsWatcher) reconcile() error {
+	w.mu.Lock()
+	w.pass++
+	pass := w.pass
+	w.mu.Unlock()
+
+	entries, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	prevEntries := w.entries
+	w.entries = entries
+	changed := make(map[fsWatchKey]bool, len(w.changed))
+	for key, changedPass := range w.changed {
+		changed[key] = true
+		// Changes during this pass may not be reflected in the
+		// scan yet, keep them for the next pass.
+		if changedPass < pass {
+			delete(w.changed, key)
+		}
+	}
+	w.mu.Unlock()
+
+	if prevEntries == nil {
+		return nil
+	}
+	for key, entry := range entries {
+		if changed[key] {
+			continue
+		}
+		prevEntry, ok := prevEntries[key]
+		if ok && prevEntry.size == entry.size && prevEntry.modTime.Equal(entry.modTime) {
+			continue
+		}
+		objInfo, err := w.fs.getObjectInfo(key.bucket, key.object)
+		if err != nil {
+			// Removed since the scan, notified on the next pass.
+			continue
+		}
+		w.notify(eventData{
+			Type:      ObjectCreatedPut,
+			Bucket:    key.bucket,
+			ObjInfo:   objInfo,
+			ReqParams: map[string]string{},
+		})
+	}
+	for key := range prevEntries {
+		if _, ok := entries[key]; ok || changed[key] {
+			continue
+		}
+		w.notify(eventData{
+			Type:   ObjectRemovedDelete,
+			Bucket: key.bucket,
+			ObjInfo: ObjectInfo{
+				Bucket: key.bucket,
+				Name:   key.object,
+			},
+			ReqParams: map[string]string{},
+		})
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Tests parsing the reconcile interval from the environment.
+func TestGetFSWatchInterval(t *testing.T) {
+	defer os.Unsetenv(fsWatchIntervalEnv)
+
+	testCases := []struct {
+		value       string
+		expected    time.Duration
+		shouldError bool
+	}{
+		{"", 0, false},
+		{"5m", 5 * time.Minute, false},
+		{"1s", 0, true},
+		{"often", 0, true},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(fsWatchIntervalEnv, testCase.value)
+		interval, err := getFSWatchInterval()
+		if testCase.shouldError != (err != nil) {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.shouldError, err)
+		}
+		if interval != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, interval)
+		}
+	}
+}
+
+// Tests detecting changes made outside of the object API.
+func TestFSWatcherReconcile(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+	fs := obj.(*fsObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	for _, object := range []string{"modified", "removed", "unchanged"} {
+		if _, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var events []string
+	fs.watcher = newFSWatcher(*fs, time.Minute)
+	fs.watcher.notify = func(event eventData) {
+		events = append(events, event.Type.String()+" "+event.Bucket+"/"+event.ObjInfo.Name)
+	}
+
+	// First pass only records the state.
+	if err = fs.watcher.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Expected no events, got %v", events)
+	}
+
+	// Changes through the object API are not reported again.
+	if _, err = obj.PutObject(context.Background(), bucket, "api", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.DeleteObject(context.Background(), bucket, "unchanged"); err != nil {
+		t.Fatal(err)
+	}
+
+	// External changes.
+	if err = os.MkdirAll(filepath.Join(fsDir, bucket, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(fsDir, bucket, "dir", "added"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(fsDir, bucket, "modified"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(filepath.Join(fsDir, bucket, "removed")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = fs.watcher.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(events)
+	expected := []string{
+		"s3:ObjectCreated:Put bucket/dir/added",
+		"s3:ObjectCreated:Put bucket/modified",
+		"s3:ObjectRemoved:Delete bucket/removed",
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, events)
+		}
+	}
+
+	// Nothing changed since.
+	events = nil
+	if err = fs.watcher.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Expected no events, got %v", events)
+	}
+}
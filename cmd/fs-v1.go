@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -28,13 +29,19 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/minio/minio/pkg/disk"
 	"github.com/minio/minio/pkg/lock"
 	"github.com/minio/sha256-simd"
+	"golang.org/x/text/unicode/norm"
 )
 
+// Environment variable enabling the normalization of object names
+// to Unicode normalization form C, set to "on".
+const fsNormalizeNamesEnv = "MINIO_FS_NORMALIZE_NAMES"
+
 // fsObjects - Implements fs object layer.
 type fsObjects struct {
 	// Path to be exported over S3 API.
@@ -55,6 +62,21 @@ type fsObjects struct {
 
 	// To manage the appendRoutine go0routines
 	bgAppend *backgroundAppend
+
+	// Reconciler of external changes, nil if disabled.
+	watcher *fsWatcher
+
+	// Object names are stored and looked up in NFC if set.
+	normalizeNames bool
+}
+
+// normalizeName - returns name in Unicode normalization form C if
+// names normalization is enabled, name as is otherwise.
+func (fs fsObjects) normalizeName(name string) string {
+	if !fs.normalizeNames {
+		return name
+	}
+	return norm.NFC.String(name)
 }
 
 // Initializes meta volume on all the fs path.
@@ -139,6 +161,11 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to recognize backend format, Disk is not in FS format. %s", format.Format)
 	}
 
+	watchInterval, err := getFSWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize fs objects.
 	fs := &fsObjects{
 		fsPath:        fsPath,
@@ -152,6 +179,7 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		bgAppend: &backgroundAppend{
 			infoMap: make(map[string]bgAppendPartsInfo),
 		},
+		normalizeNames: strings.EqualFold(os.Getenv(fsNormalizeNamesEnv), "on"),
 	}
 
 	// Validate if disk has enough free space to use.
@@ -159,16 +187,16 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, err
 	}
 
-	// Initialize and load bucket policies.
-	err = initBucketPolicies(fs)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to load all bucket policies. %s", err)
+	// Initialize and load the bucket metadata, IAM users and policies
+	// and the event notifier.
+	if err = initBucketMetadata(fs, false); err != nil {
+		return nil, err
 	}
 
-	// Initialize a new event notifier.
-	err = initEventNotifier(fs)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to initialize event notification. %s", err)
+	// Start detecting changes made outside of Minio, if enabled.
+	if watchInterval > 0 {
+		fs.watcher = newFSWatcher(*fs, watchInterval)
+		fs.watcher.Start()
 	}
 
 	// Return successfully initialized object layer.
@@ -212,6 +240,8 @@ func (fs fsObjects) checkDiskFree() (err error) {
 
 // Should be called when process shuts down.
 func (fs fsObjects) Shutdown() error {
+	fs.watcher.Stop()
+
 	// Cleanup and delete tmp uuid.
 	return fsRemoveAll(pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID))
 }
@@ -257,7 +287,7 @@ func (fs fsObjects) statBucketDir(bucket string) (os.FileInfo, error) {
 
 // MakeBucket - create a new bucket, returns if it
 // already exists.
-func (fs fsObjects) MakeBucket(bucket string) error {
+func (fs fsObjects) MakeBucket(ctx context.Context, bucket string) error {
 	bucketDir, err := fs.getBucketDir(bucket)
 	if err != nil {
 		return toObjectErr(err, bucket)
@@ -271,7 +301,7 @@ func (fs fsObjects) MakeBucket(bucket string) error {
 }
 
 // GetBucketInfo - fetch bucket metadata info.
-func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
+func (fs fsObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
 	st, err := fs.statBucketDir(bucket)
 	if err != nil {
 		return BucketInfo{}, toObjectErr(err, bucket)
@@ -286,7 +316,7 @@ func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
 }
 
 // ListBuckets - list all s3 compatible buckets (directories) at fsPath.
-func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
+func (fs fsObjects) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
 	if err := checkPathLength(fs.fsPath); err != nil {
 		return nil, err
 	}
@@ -342,7 +372,7 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 
 // DeleteBucket - delete a bucket and all the metadata associated
 // with the bucket including pending multipart, object metadata.
-func (fs fsObjects) DeleteBucket(bucket string) error {
+func (fs fsObjects) DeleteBucket(ctx context.Context, bucket string) error {
 	bucketDir, err := fs.getBucketDir(bucket)
 	if err != nil {
 		return toObjectErr(err, bucket)
@@ -368,12 +398,54 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 	return nil
 }
 
+// RenameBucket - renames srcBucket to dstBucket, which should not
+// exist yet, by renaming the bucket directory along with its
+// incomplete multiparts and metadata directories.
+func (fs fsObjects) RenameBucket(ctx context.Context, srcBucket, dstBucket string) error {
+	if _, err := fs.statBucketDir(srcBucket); err != nil {
+		return toObjectErr(err, srcBucket)
+	}
+	dstBucketDir, err := fs.getBucketDir(dstBucket)
+	if err != nil {
+		return toObjectErr(err, dstBucket)
+	}
+	if _, err = fsStatDir(dstBucketDir); err == nil {
+		return toObjectErr(traceError(errVolumeExists), dstBucket)
+	} else if err != errVolumeNotFound {
+		return toObjectErr(traceError(err), dstBucket)
+	}
+
+	if err = fsRenameFile(pathJoin(fs.fsPath, srcBucket), dstBucketDir); err != nil {
+		return toObjectErr(err, srcBucket)
+	}
+
+	// Move all the previously incomplete multiparts and the bucket
+	// metadata, left over entries of a previously deleted dstBucket
+	// are removed first.
+	for _, metaDir := range []string{minioMetaMultipartBucket, pathJoin(minioMetaBucket, bucketMetaPrefix)} {
+		srcDir := pathJoin(fs.fsPath, metaDir, srcBucket)
+		dstDir := pathJoin(fs.fsPath, metaDir, dstBucket)
+		if err = fsRemoveAll(dstDir); err != nil {
+			return toObjectErr(err, dstBucket)
+		}
+		if _, err = fsStatDir(srcDir); err == errVolumeNotFound {
+			continue
+		}
+		if err = fsRenameFile(srcDir, dstDir); err != nil {
+			return toObjectErr(err, srcBucket)
+		}
+	}
+
+	return nil
+}
+
 /// Object Operations
 
 // CopyObject - copy object source object to destination object.
 // if source object and destination object are same we only
 // update metadata.
-func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+func (fs fsObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+	srcObject, dstObject = fs.normalizeName(srcObject), fs.normalizeName(dstObject)
 	if _, err := fs.statBucketDir(srcBucket); err != nil {
 		return ObjectInfo{}, toObjectErr(err, srcBucket)
 	}
@@ -415,7 +487,7 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 
 	go func() {
 		startOffset := int64(0) // Read the whole file.
-		if gerr := fs.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+		if gerr := fs.GetObject(ctx, srcBucket, srcObject, startOffset, length, throttleCopyWriter(pipeWriter)); gerr != nil {
 			errorIf(gerr, "Unable to read %s/%s.", srcBucket, srcObject)
 			pipeWriter.CloseWithError(gerr)
 			return
@@ -423,7 +495,7 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
 	}()
 
-	objInfo, err := fs.PutObject(dstBucket, dstObject, length, pipeReader, metadata, "")
+	objInfo, err := fs.PutObject(ctx, dstBucket, dstObject, length, pipeReader, metadata, "")
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -440,7 +512,8 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 //
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
-func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64, writer io.Writer) (err error) {
+func (fs fsObjects) GetObject(ctx context.Context, bucket, object string, offset int64, length int64, writer io.Writer) (err error) {
+	object = fs.normalizeName(object)
 	if err = checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -530,7 +603,8 @@ func (fs fsObjects) getObjectInfo(bucket, object string) (ObjectInfo, error) {
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
-func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+func (fs fsObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	object = fs.normalizeName(object)
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -546,7 +620,8 @@ func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 // until EOF, writes data directly to configured filesystem path.
 // Additionally writes `fs.json` which carries the necessary metadata
 // for future object operations.
-func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+func (fs fsObjects) PutObject(ctx context.Context, bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+	object = fs.normalizeName(object)
 	if err = checkPutObjectArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -652,6 +727,10 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
+	// Shred the contents of an object being overwritten.
+	if err = fsShredFile(bucket, fsNSObjPath); err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
 	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
@@ -668,6 +747,7 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
+	fs.watcher.markChanged(bucket, object)
 
 	// Success.
 	return fsMeta.ToObjectInfo(bucket, object, fi), nil
@@ -675,7 +755,8 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 
 // DeleteObject - deletes an object from a bucket, this operation is destructive
 // and there are no rollbacks supported.
-func (fs fsObjects) DeleteObject(bucket, object string) error {
+func (fs fsObjects) DeleteObject(ctx context.Context, bucket, object string) error {
+	object = fs.normalizeName(object)
 	if err := checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -697,10 +778,16 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 		}
 	}
 
+	// Shred the object contents, if required by the bucket.
+	if err := fsShredFile(bucket, pathJoin(fs.fsPath, bucket, object)); err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+
 	// Delete the object.
 	if err := fsDeleteFile(pathJoin(fs.fsPath, bucket), pathJoin(fs.fsPath, bucket, object)); err != nil {
 		return toObjectErr(traceError(err), bucket, object)
 	}
+	fs.watcher.markChanged(bucket, object)
 
 	if bucket != minioMetaBucket {
 		// Delete the metadata object.
@@ -712,6 +799,33 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 	return nil
 }
 
+// RenamePrefix - renames all the objects under prefix to newPrefix
+// by renaming the prefix directory along with its metadata directory.
+func (fs fsObjects) RenamePrefix(ctx context.Context, bucket, prefix, newPrefix string) error {
+	prefix, newPrefix = fs.normalizeName(prefix), fs.normalizeName(newPrefix)
+	if err := checkRenamePrefixArgs(ctx, bucket, prefix, newPrefix, fs); err != nil {
+		return err
+	}
+
+	for _, baseDir := range []string{
+		pathJoin(fs.fsPath, bucket),
+		pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket),
+	} {
+		srcDir := pathJoin(baseDir, strings.TrimSuffix(prefix, slashSeparator))
+		if _, err := fsStatDir(srcDir); err == errVolumeNotFound {
+			// Objects without metadata have no metadata directory.
+			continue
+		}
+		dstDir := pathJoin(baseDir, strings.TrimSuffix(newPrefix, slashSeparator))
+		if err := fsRenameFile(srcDir, dstDir); err != nil {
+			return toObjectErr(err, bucket, prefix)
+		}
+		// Remove the parent directories left empty, if any.
+		_ = fsDeleteFile(baseDir, filepath.Dir(srcDir))
+	}
+	return nil
+}
+
 // list of all errors that can be ignored in tree walk operation in FS
 var fsTreeWalkIgnoredErrs = append(baseIgnoredErrs, []error{
 	errFileNotFound,
@@ -724,14 +838,12 @@ var fsTreeWalkIgnoredErrs = append(baseIgnoredErrs, []error{
 func (fs fsObjects) listDirFactory(isLeaf isLeafFunc) listDirFunc {
 	// listDir - lists all the entries at a given prefix and given entry in the prefix.
 	listDir := func(bucket, prefixDir, prefixEntry string) (entries []string, delayIsLeaf bool, err error) {
-		entries, err = readDir(pathJoin(fs.fsPath, bucket, prefixDir))
+		// Only entries with the prefix prefixEntry are read.
+		entries, err = readDirWithPrefix(pathJoin(fs.fsPath, bucket, prefixDir), prefixEntry)
 		if err == nil {
 			// Listing needs to be sorted.
 			sort.Strings(entries)
 
-			// Filter entries that have the prefix prefixEntry.
-			entries = filterMatchingPrefix(entries, prefixEntry)
-
 			// Can isLeaf() check be delayed till when it has to be sent down the
 			// treeWalkResult channel?
 			delayIsLeaf = delayIsLeafCheck(entries)
@@ -762,9 +874,55 @@ func (fs fsObjects) listDirFactory(isLeaf isLeafFunc) listDirFunc {
 	return listDir
 }
 
+// Maximum number of listed objects whose metadata is read in parallel.
+const fsListMetaWorkers = 16
+
+// listObjectInfos - returns the ObjectInfo of the entries listed in
+// bucket. Reading `fs.json` and stating an object cost an open, a read
+// and a parse per listed object, hence the objects of a page are read
+// in parallel, fsListMetaWorkers at a time.
+func (fs fsObjects) listObjectInfos(bucket string, entries []string) ([]ObjectInfo, error) {
+	objInfos := make([]ObjectInfo, len(entries))
+	errs := make([]error, len(entries))
+
+	var wg = &sync.WaitGroup{}
+	indexCh := make(chan int)
+	workers := fsListMetaWorkers
+	if len(entries) < workers {
+		workers = len(entries)
+	}
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				objInfos[i], errs[i] = fs.getObjectInfo(bucket, entries[i])
+			}
+		}()
+	}
+	for i, entry := range entries {
+		if strings.HasSuffix(entry, slashSeparator) {
+			// Object name needs to be full path.
+			objInfos[i] = ObjectInfo{Name: entry, IsDir: true}
+			continue
+		}
+		indexCh <- i
+	}
+	close(indexCh)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return objInfos, nil
+}
+
 // ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/'. Maintains the list pool
 // state for future re-entrant list requests.
-func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (fs fsObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	prefix, marker = fs.normalizeName(prefix), fs.normalizeName(marker)
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, fs); err != nil {
 		return ListObjectsInfo{}, err
 	}
@@ -798,24 +956,6 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 		recursive = false
 	}
 
-	// Convert entry to ObjectInfo
-	entryToObjectInfo := func(entry string) (objInfo ObjectInfo, err error) {
-		if strings.HasSuffix(entry, slashSeparator) {
-			// Object name needs to be full path.
-			objInfo.Name = entry
-			objInfo.IsDir = true
-			return
-		}
-		// Stat the file to get file size.
-		var fi os.FileInfo
-		fi, err = fsStatFile(pathJoin(fs.fsPath, bucket, entry))
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(traceError(err), bucket, entry)
-		}
-		fsMeta := fsMetaV1{}
-		return fsMeta.ToObjectInfo(bucket, entry, fi), nil
-	}
-
 	heal := false // true only for xl.ListObjectsHeal()
 	walkResultCh, endWalkCh := fs.listPool.Release(listParams{bucket, recursive, marker, prefix, heal})
 	if walkResultCh == nil {
@@ -830,13 +970,21 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
 	}
 
-	var objInfos []ObjectInfo
+	var entries []string
 	var eof bool
 	var nextMarker string
 
 	// List until maxKeys requested.
 	for i := 0; i < maxKeys; {
-		walkResult, ok := <-walkResultCh
+		var walkResult treeWalkResult
+		var ok bool
+		select {
+		case walkResult, ok = <-walkResultCh:
+		case <-ctx.Done():
+			// Nobody waits for the listing anymore, end the walk.
+			close(endWalkCh)
+			return ListObjectsInfo{}, traceError(ctx.Err())
+		}
 		if !ok {
 			// Closed channel.
 			eof = true
@@ -850,12 +998,8 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 			}
 			return ListObjectsInfo{}, toObjectErr(walkResult.err, bucket, prefix)
 		}
-		objInfo, err := entryToObjectInfo(walkResult.entry)
-		if err != nil {
-			return ListObjectsInfo{}, nil
-		}
-		nextMarker = objInfo.Name
-		objInfos = append(objInfos, objInfo)
+		nextMarker = walkResult.entry
+		entries = append(entries, walkResult.entry)
 		if walkResult.end {
 			eof = true
 			break
@@ -863,6 +1007,13 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 		i++
 	}
 
+	objInfos, err := fs.listObjectInfos(bucket, entries)
+	if err != nil {
+		// Ends the walk, the listed objects have changed.
+		close(endWalkCh)
+		return ListObjectsInfo{}, nil
+	}
+
 	// Save list routine for the next marker if we haven't reached EOF.
 	params := listParams{bucket, recursive, nextMarker, prefix, heal}
 	if !eof {
@@ -884,16 +1035,16 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 }
 
 // HealObject - no-op for fs. Valid only for XL.
-func (fs fsObjects) HealObject(bucket, object string) error {
+func (fs fsObjects) HealObject(ctx context.Context, bucket, object string) error {
 	return traceError(NotImplemented{})
 }
 
 // HealBucket - no-op for fs, Valid only for XL.
-func (fs fsObjects) HealBucket(bucket string) error {
+func (fs fsObjects) HealBucket(ctx context.Context, bucket string) error {
 	return traceError(NotImplemented{})
 }
 
 // ListObjectsHeal - list all objects to be healed. Valid only for XL
-func (fs fsObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (fs fsObjects) ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	return ListObjectsInfo{}, traceError(NotImplemented{})
 }
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -63,9 +64,9 @@ func TestFSShutdown(t *testing.T) {
 		obj := initFSObjects(disk, t)
 		fs := obj.(*fsObjects)
 		objectContent := "12345"
-		obj.MakeBucket(bucketName)
+		obj.MakeBucket(context.Background(), bucketName)
 		sha256sum := ""
-		obj.PutObject(bucketName, objectName, int64(len(objectContent)), bytes.NewReader([]byte(objectContent)), nil, sha256sum)
+		obj.PutObject(context.Background(), bucketName, objectName, int64(len(objectContent)), bytes.NewReader([]byte(objectContent)), nil, sha256sum)
 		return fs, disk
 	}
 
@@ -79,7 +80,7 @@ func TestFSShutdown(t *testing.T) {
 	// Test Shutdown with faulty disk
 	for i := 1; i <= 5; i++ {
 		fs, disk := prepareTest()
-		fs.DeleteObject(bucketName, objectName)
+		fs.DeleteObject(context.Background(), bucketName, objectName)
 		removeAll(disk)
 		if err := fs.Shutdown(); err != nil {
 			t.Fatal(i, ", Got unexpected fs shutdown error: ", err)
@@ -138,10 +139,10 @@ func TestFSGetBucketInfo(t *testing.T) {
 	fs := obj.(*fsObjects)
 	bucketName := "bucket"
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 
 	// Test with valid parameters
-	info, err := fs.GetBucketInfo(bucketName)
+	info, err := fs.GetBucketInfo(context.Background(), bucketName)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -150,20 +151,65 @@ func TestFSGetBucketInfo(t *testing.T) {
 	}
 
 	// Test with inexistant bucket
-	_, err = fs.GetBucketInfo("a")
+	_, err = fs.GetBucketInfo(context.Background(), "a")
 	if !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("BucketNameInvalid error not returned")
 	}
 
 	// Check for buckets and should get disk not found.
 	removeAll(disk)
-	_, err = fs.GetBucketInfo(bucketName)
+	_, err = fs.GetBucketInfo(context.Background(), bucketName)
 	if !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("BucketNotFound error not returned")
 	}
 }
 
-// TestFSDeleteObject - test fs.DeleteObject() with healthy and corrupted disks
+// TestFSListObjectsMetadata - tests listed objects carry the metadata of fs.json.
+func TestFSListObjectsMetadata(t *testing.T) {
+	// Prepare for testing
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// More objects than metadata workers, with a prefix.
+	objInfos := make(map[string]ObjectInfo)
+	for i := 0; i < 2*fsListMetaWorkers; i++ {
+		objectName := fmt.Sprintf("object-%02d", i)
+		if i%2 == 0 {
+			objectName = "dir/" + objectName
+		}
+		data := fmt.Sprintf("data-%d", i)
+		objInfo, err := obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewBufferString(data), map[string]string{"content-type": "application/x-test"}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		objInfos[objectName] = objInfo
+	}
+
+	result, err := obj.ListObjects(context.Background(), bucketName, "", "", "/", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Prefixes) != 1 || result.Prefixes[0] != "dir/" {
+		t.Fatalf("Expected prefix dir/, got %v", result.Prefixes)
+	}
+	if len(result.Objects) != fsListMetaWorkers {
+		t.Fatalf("Expected %d objects, got %d", fsListMetaWorkers, len(result.Objects))
+	}
+	for _, objInfo := range result.Objects {
+		expected := objInfos[objInfo.Name]
+		if objInfo.MD5Sum != expected.MD5Sum || objInfo.Size != expected.Size || objInfo.ContentType != "application/x-test" {
+			t.Errorf("Expected listed object %#v, got %#v", expected, objInfo)
+		}
+	}
+}
+
+// TestFSDeleteObject - test fs.DeleteObject(context.Background()) with healthy and corrupted disks
 func TestFSDeleteObject(t *testing.T) {
 	// Prepare for tests
 	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
@@ -174,34 +220,34 @@ func TestFSDeleteObject(t *testing.T) {
 	bucketName := "bucket"
 	objectName := "object"
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 	sha256sum := ""
-	obj.PutObject(bucketName, objectName, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, sha256sum)
+	obj.PutObject(context.Background(), bucketName, objectName, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, sha256sum)
 
 	// Test with invalid bucket name
-	if err := fs.DeleteObject("fo", objectName); !isSameType(errorCause(err), BucketNameInvalid{}) {
+	if err := fs.DeleteObject(context.Background(), "fo", objectName); !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with bucket does not exist
-	if err := fs.DeleteObject("foobucket", "fooobject"); !isSameType(errorCause(err), BucketNotFound{}) {
+	if err := fs.DeleteObject(context.Background(), "foobucket", "fooobject"); !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with invalid object name
-	if err := fs.DeleteObject(bucketName, "\\"); !isSameType(errorCause(err), ObjectNameInvalid{}) {
+	if err := fs.DeleteObject(context.Background(), bucketName, "\\"); !isSameType(errorCause(err), ObjectNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with object does not exist.
-	if err := fs.DeleteObject(bucketName, "foooobject"); !isSameType(errorCause(err), ObjectNotFound{}) {
+	if err := fs.DeleteObject(context.Background(), bucketName, "foooobject"); !isSameType(errorCause(err), ObjectNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with valid condition
-	if err := fs.DeleteObject(bucketName, objectName); err != nil {
+	if err := fs.DeleteObject(context.Background(), bucketName, objectName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
 	// Delete object should err disk not found.
 	removeAll(disk)
-	if err := fs.DeleteObject(bucketName, objectName); err != nil {
+	if err := fs.DeleteObject(context.Background(), bucketName, objectName); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -219,29 +265,29 @@ func TestFSDeleteBucket(t *testing.T) {
 	fs := obj.(*fsObjects)
 	bucketName := "bucket"
 
-	err := obj.MakeBucket(bucketName)
+	err := obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
 	// Test with an invalid bucket name
-	if err = fs.DeleteBucket("fo"); !isSameType(errorCause(err), BucketNameInvalid{}) {
+	if err = fs.DeleteBucket(context.Background(), "fo"); !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with an inexistant bucket
-	if err = fs.DeleteBucket("foobucket"); !isSameType(errorCause(err), BucketNotFound{}) {
+	if err = fs.DeleteBucket(context.Background(), "foobucket"); !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with a valid case
-	if err = fs.DeleteBucket(bucketName); err != nil {
+	if err = fs.DeleteBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 
 	// Delete bucker should get error disk not found.
 	removeAll(disk)
-	if err = fs.DeleteBucket(bucketName); err != nil {
+	if err = fs.DeleteBucket(context.Background(), bucketName); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -258,7 +304,7 @@ func TestFSListBuckets(t *testing.T) {
 	fs := obj.(*fsObjects)
 
 	bucketName := "bucket"
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -273,7 +319,7 @@ func TestFSListBuckets(t *testing.T) {
 	f.Close()
 
 	// Test list buckets to have only one entry.
-	buckets, err := fs.ListBuckets()
+	buckets, err := fs.ListBuckets(context.Background())
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -284,7 +330,7 @@ func TestFSListBuckets(t *testing.T) {
 	// Test ListBuckets with disk not found.
 	removeAll(disk)
 
-	if _, err := fs.ListBuckets(); err != nil {
+	if _, err := fs.ListBuckets(context.Background()); err != nil {
 		if errorCause(err) != errDiskNotFound {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -292,7 +338,7 @@ func TestFSListBuckets(t *testing.T) {
 
 	longPath := fmt.Sprintf("%0256d", 1)
 	fs.fsPath = longPath
-	if _, err := fs.ListBuckets(); err != nil {
+	if _, err := fs.ListBuckets(context.Background()); err != nil {
 		if errorCause(err) != errFileNameTooLong {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -305,7 +351,7 @@ func TestFSHealObject(t *testing.T) {
 	defer removeAll(disk)
 
 	obj := initFSObjects(disk, t)
-	err := obj.HealObject("bucket", "object")
+	err := obj.HealObject(context.Background(), "bucket", "object")
 	if err == nil || !isSameType(errorCause(err), NotImplemented{}) {
 		t.Fatalf("Heal Object should return NotImplemented error ")
 	}
@@ -317,8 +363,52 @@ func TestFSListObjectsHeal(t *testing.T) {
 	defer removeAll(disk)
 
 	obj := initFSObjects(disk, t)
-	_, err := obj.ListObjectsHeal("bucket", "prefix", "marker", "delimiter", 1000)
+	_, err := obj.ListObjectsHeal(context.Background(), "bucket", "prefix", "marker", "delimiter", 1000)
 	if err == nil || !isSameType(errorCause(err), NotImplemented{}) {
 		t.Fatalf("Heal Object should return NotImplemented error ")
 	}
 }
+
+// TestFSNormalizeNames - tests object names are stored and looked up
+// in NFC when names normalization is enabled.
+func TestFSNormalizeNames(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	os.Setenv(fsNormalizeNamesEnv, "on")
+	defer os.Unsetenv(fsNormalizeNamesEnv)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// "é" decomposed, as sent by macOS clients, and precomposed.
+	nfdName, nfcName := "docs/re\u0301sume\u0301.txt", "docs/r\u00e9sum\u00e9.txt"
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(context.Background(), bucketName, nfdName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, nfcName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Name != nfcName {
+		t.Fatalf("Expected object name %q, got %q", nfcName, objInfo.Name)
+	}
+	result, err := obj.ListObjects(context.Background(), bucketName, "docs/re\u0301", "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != nfcName {
+		t.Fatalf("Expected to list %q, got %v", nfcName, result.Objects)
+	}
+	if err = obj.DeleteObject(context.Background(), bucketName, nfdName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, nfcName); !isSameType(errorCause(err), ObjectNotFound{}) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+}
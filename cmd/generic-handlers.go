@@ -19,6 +19,7 @@ package cmd
 import (
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -172,6 +173,11 @@ func (h minioPrivateBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		writeErrorResponse(w, ErrAllAccessDisabled, r.URL)
 		return
 	}
+	// Reject access to the server metadata and out of the buckets.
+	if s3Error := checkRequestPaths(r); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 	h.handler.ServeHTTP(w, r)
 }
 
@@ -275,7 +281,8 @@ func setCorsHandler(h http.Handler) http.Handler {
 		AllowedOrigins: []string{"*"},
 		AllowedMethods: defaultAllowableHTTPMethods,
 		AllowedHeaders: []string{"*"},
-		ExposedHeaders: []string{"ETag"},
+		ExposedHeaders: []string{"ETag", "X-Amz-Bucket-Region", "X-Minio-Bucket-Objects",
+			"X-Minio-Bucket-Size", "X-Minio-Bucket-Usage-Updated"},
 	})
 	return c.Handler(h)
 }
@@ -356,3 +363,42 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Serve HTTP.
 	h.handler.ServeHTTP(w, r)
 }
+
+// gzipHandler - compresses the XML and JSON response bodies, such as
+// listings and errors, of requests accepting gzip content encoding.
+type gzipHandler struct {
+	handler http.Handler
+}
+
+func setGzipHandler(h http.Handler) http.Handler {
+	return gzipHandler{h}
+}
+
+// Returns true if the request accepts gzip content encoding.
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header["Accept-Encoding"] {
+		for _, coding := range strings.Split(value, ",") {
+			coding, params := strings.TrimSpace(coding), ""
+			if i := strings.Index(coding, ";"); i >= 0 {
+				coding, params = strings.TrimSpace(coding[:i]), strings.TrimSpace(coding[i+1:])
+			}
+			if !strings.EqualFold(coding, "gzip") {
+				continue
+			}
+			// A quality value of zero rejects the encoding.
+			if strings.HasPrefix(params, "q=") {
+				q, err := strconv.ParseFloat(params[len("q="):], 64)
+				return err == nil && q > 0
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (h gzipHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if acceptsGzip(r) {
+		w = gzipResponseWriter{w}
+	}
+	h.handler.ServeHTTP(w, r)
+}
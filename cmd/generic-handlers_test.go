@@ -17,7 +17,11 @@
 package cmd
 
 import (
+	"compress/gzip"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -88,3 +92,79 @@ func TestGuessIsBrowser(t *testing.T) {
 		t.Fatal("Test shouldn't report as browser for a non browser request.")
 	}
 }
+
+// Tests parsing of the Accept-Encoding header.
+func TestAcceptsGzip(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding []string
+		expected       bool
+	}{
+		{nil, false},
+		{[]string{"identity"}, false},
+		{[]string{"gzip"}, true},
+		{[]string{"GZIP"}, true},
+		{[]string{"deflate, gzip;q=0.5"}, true},
+		{[]string{"deflate", "gzip"}, true},
+		{[]string{"gzip;q=0"}, false},
+		{[]string{"gzip; q=0.0, deflate"}, false},
+		{[]string{"x-gzip"}, false},
+	}
+	for i, testCase := range testCases {
+		r := &http.Request{Header: http.Header{"Accept-Encoding": testCase.acceptEncoding}}
+		if acceptsGzip(r) != testCase.expected {
+			t.Errorf("Test %d: Expected %v for %v", i+1, testCase.expected, testCase.acceptEncoding)
+		}
+	}
+}
+
+// Tests compression of the XML responses by the gzip handler.
+func TestGzipHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	reqURL := &url.URL{Path: "/bucket"}
+	handler := setGzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+	}))
+	expected := encodeResponse(getAPIErrorResponse(getAPIError(ErrNoSuchBucket), reqURL.Path))
+
+	for _, acceptEncoding := range []string{"", "gzip"} {
+		rec := httptest.NewRecorder()
+		r := &http.Request{Method: httpGET, URL: reqURL, Header: http.Header{}}
+		if acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		handler.ServeHTTP(rec, r)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+
+		body := rec.Body.Bytes()
+		if acceptEncoding == "" {
+			if rec.Header().Get("Content-Encoding") != "" {
+				t.Fatalf("Unexpected content encoding %s", rec.Header().Get("Content-Encoding"))
+			}
+		} else {
+			if rec.Header().Get("Content-Encoding") != "gzip" {
+				t.Fatalf("Expected gzip content encoding, got %s", rec.Header().Get("Content-Encoding"))
+			}
+			gr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, err = ioutil.ReadAll(gr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if rec.Header().Get("Content-Type") != string(mimeXML) {
+			t.Errorf("Expected content type %s, got %s", mimeXML, rec.Header().Get("Content-Type"))
+		}
+		// Request IDs are not compared.
+		if len(body) != len(expected) {
+			t.Errorf("Expected response %s, got %s", expected, body)
+		}
+	}
+}
@@ -37,7 +37,7 @@ const (
 
 // minio configuration related constants.
 const (
-	globalMinioConfigVersion      = "13"
+	globalMinioConfigVersion      = "17"
 	globalMinioConfigDir          = ".minio"
 	globalMinioCertsDir           = "certs"
 	globalMinioCertsCADir         = "CAs"
@@ -74,6 +74,11 @@ var (
 	// when MINIO_BROWSER env is set to 'off'.
 	globalIsBrowserEnabled = !strings.EqualFold(os.Getenv("MINIO_BROWSER"), "off")
 
+	// This flag is set to 'true' when MINIO_STRICT_NAMES env is set
+	// to 'on', rejecting object names unsupported by Windows and NAS
+	// filesystems.
+	globalIsStrictNames = strings.EqualFold(os.Getenv("MINIO_STRICT_NAMES"), "on")
+
 	// Maximum cache size. Defaults to disabled.
 	// Caching is enabled only for RAM size > 8GiB.
 	globalMaxCacheSize = uint64(0)
@@ -112,6 +117,14 @@ var (
 	// Secret key passed from the environment
 	globalEnvSecretKey = os.Getenv("MINIO_SECRET_KEY")
 
+	// Client of the etcd cluster storing the config, nil if the
+	// config is stored in the config dir.
+	globalEtcdClient *etcdClient
+
+	// Publisher of the bucket DNS records, nil if bucket records
+	// are not published.
+	globalBucketDNS bucketDNS
+
 	// Add new variable global values here.
 )
 
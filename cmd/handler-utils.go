@@ -26,9 +26,10 @@ import (
 
 // Validates location constraint in PutBucket request body.
 // The location value in the request body should match the
-// region configured at serverConfig, otherwise error is returned.
-func isValidLocationConstraint(r *http.Request) (s3Error APIErrorCode) {
-	serverRegion := serverConfig.GetRegion()
+// region configured at serverConfig or one of the configured
+// bucket regions, otherwise error is returned. Returns the
+// region the bucket should be created in.
+func isValidLocationConstraint(r *http.Request) (location string, s3Error APIErrorCode) {
 	// If the request has no body with content-length set to 0,
 	// we do not have to validate location constraint. Bucket will
 	// be created at default region.
@@ -44,16 +45,15 @@ func isValidLocationConstraint(r *http.Request) (s3Error APIErrorCode) {
 			incomingRegion = globalMinioDefaultRegion
 		}
 		// Return errInvalidRegion if location constraint does not match
-		// with configured region.
-		s3Error = ErrNone
-		if serverRegion != incomingRegion {
-			s3Error = ErrInvalidRegion
+		// with any of the configured regions.
+		if !isValidBucketRegion(incomingRegion) {
+			return "", ErrInvalidRegion
 		}
-		return s3Error
+		return incomingRegion, ErrNone
 	}
 	errorIf(err, "Unable to xml decode location constraint")
 	// Treat all other failures as XML parsing errors.
-	return ErrMalformedXML
+	return "", ErrMalformedXML
 }
 
 // Supported headers that needs to be extracted.
@@ -62,6 +62,8 @@ var supportedHeaders = []string{
 	"cache-control",
 	"content-encoding",
 	"content-disposition",
+	"content-language",
+	"expires",
 	// Add more supported headers here.
 }
 
@@ -38,7 +38,7 @@ func TestIsValidLocationContraint(t *testing.T) {
 		Body:          ioutil.NopCloser(bytes.NewBuffer([]byte("<>"))),
 		ContentLength: int64(len("<>")),
 	}
-	if err := isValidLocationConstraint(malformedReq); err != ErrMalformedXML {
+	if _, err := isValidLocationConstraint(malformedReq); err != ErrMalformedXML {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -60,26 +60,36 @@ func TestIsValidLocationContraint(t *testing.T) {
 	testCases := []struct {
 		locationForInputRequest string
 		serverConfigRegion      string
+		expectedLocation        string
 		expectedCode            APIErrorCode
 	}{
 		// Test case - 1.
-		{globalMinioDefaultRegion, globalMinioDefaultRegion, ErrNone},
+		{globalMinioDefaultRegion, globalMinioDefaultRegion, globalMinioDefaultRegion, ErrNone},
 		// Test case - 2.
 		// In case of empty request body ErrNone is returned.
-		{"", globalMinioDefaultRegion, ErrNone},
+		{"", globalMinioDefaultRegion, globalMinioDefaultRegion, ErrNone},
 		// Test case - 3.
-		{"eu-central-1", globalMinioDefaultRegion, ErrInvalidRegion},
+		{"eu-central-1", globalMinioDefaultRegion, "", ErrInvalidRegion},
+		// Test case - 4.
+		// Location in one of the configured bucket regions.
+		{"eu-west-1", globalMinioDefaultRegion, "eu-west-1", ErrNone},
+		// Test case - 5.
+		{"", "eu-west-1", "", ErrInvalidRegion},
 	}
+	serverConfig.SetBucketRegions([]string{"eu-west-1"})
 	for i, testCase := range testCases {
 		inputRequest, e := createExpectedRequest(&http.Request{}, testCase.locationForInputRequest)
 		if e != nil {
 			t.Fatalf("Test %d: Failed to Marshal bucket configuration", i+1)
 		}
 		serverConfig.SetRegion(testCase.serverConfigRegion)
-		actualCode := isValidLocationConstraint(inputRequest)
+		actualLocation, actualCode := isValidLocationConstraint(inputRequest)
 		if testCase.expectedCode != actualCode {
 			t.Errorf("Test %d: Expected the APIErrCode to be %d, but instead found %d", i+1, testCase.expectedCode, actualCode)
 		}
+		if testCase.expectedLocation != actualLocation {
+			t.Errorf("Test %d: Expected the location to be %s, but instead found %s", i+1, testCase.expectedLocation, actualLocation)
+		}
 	}
 }
 
@@ -98,6 +108,19 @@ func TestExtractMetadataHeaders(t *testing.T) {
 				"content-type": "image/png",
 			},
 		},
+		// Validate if the cache directives are extracted.
+		{
+			header: http.Header{
+				"Cache-Control":    []string{"public, max-age=3600"},
+				"Expires":          []string{"Thu, 01 Dec 1994 16:00:00 GMT"},
+				"Content-Language": []string{"en-US"},
+			},
+			metadata: map[string]string{
+				"cache-control":    "public, max-age=3600",
+				"expires":          "Thu, 01 Dec 1994 16:00:00 GMT",
+				"content-language": "en-US",
+			},
+		},
 		// Validate if there are no keys to extract.
 		{
 			header: http.Header{
@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Metadata holding the access key of the owner of an object, which
+// is the identity that uploaded it.
+const minioMetaOwner = "X-Minio-Meta-Owner"
+
+// setObjectOwner - sets accessKey as the owner in the metadata of an
+// uploaded object, dropping any owner set by the client. Anonymous
+// uploads have no owner.
+func setObjectOwner(metadata map[string]string, accessKey string) {
+	delete(metadata, minioMetaOwner)
+	if accessKey != "" {
+		metadata[minioMetaOwner] = accessKey
+	}
+}
+
+// ownerUsage - data usage of the owners of the objects as of the last
+// data usage crawl.
+type ownerUsage struct {
+	rwMutex sync.RWMutex
+	owners  map[string]dataUsageEntry
+}
+
+// Usage of the owners, updated by the data usage crawler.
+var globalOwnerUsage = &ownerUsage{}
+
+// Set - replaces the usage of all the owners.
+func (u *ownerUsage) Set(owners map[string]dataUsageEntry) {
+	u.rwMutex.Lock()
+	defer u.rwMutex.Unlock()
+	u.owners = owners
+}
+
+// Get - returns the usage of an owner, empty if it owns no objects.
+func (u *ownerUsage) Get(accessKey string) dataUsageEntry {
+	u.rwMutex.RLock()
+	defer u.rwMutex.RUnlock()
+	return u.owners[accessKey]
+}
+
+// errUserQuotaExceeded - returned by the browser handlers for uploads
+// exceeding the quota of the IAM user logged in.
+var errUserQuotaExceeded = errors.New("The upload exceeds the storage quota of the user")
+
+// checkUserQuota - verifies that uploading size bytes keeps the IAM
+// user accessKey within its quota. The usage of the user is the one
+// of the last data usage crawl, so that a quota may be exceeded by
+// the uploads since.
+func checkUserQuota(accessKey string, size int64) APIErrorCode {
+	quota := globalIAMSys.GetUserQuota(accessKey)
+	if quota <= 0 {
+		return ErrNone
+	}
+	if size < 0 {
+		size = 0
+	}
+	if int64(globalOwnerUsage.Get(accessKey).Size)+size > quota {
+		return ErrUserQuotaExceeded
+	}
+	return ErrNone
+}
+
+// checkRequestQuota - verifies that the authenticated request r
+// uploading size bytes keeps its IAM user within its quota.
+func checkRequestQuota(r *http.Request, size int64) APIErrorCode {
+	return checkUserQuota(getRequestAccessKey(r), size)
+}
@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling the user quota tests for both XL multiple disks and single node setup.
+func TestUserQuota(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer resetGlobalIAMSys()
+	defer globalOwnerUsage.Set(nil)
+	ExecObjectLayerAPITest(t, testUserQuota, []string{"CopyObject", "PutObject"})
+}
+
+func testUserQuota(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	accessKey, secretKey := "uploader", "uploadersecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "readwrite"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.SetUserQuota(obj, accessKey, 10); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	globalOwnerUsage.Set(nil)
+
+	put := func(object string, data []byte, accessKey, secretKey string, header http.Header) int {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, object),
+			int64(len(data)), bytes.NewReader(data), accessKey, secretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Objects are owned by their uploader, regardless of the owner
+	// set by the client.
+	data := []byte("12345678")
+	header := http.Header{minioMetaOwner: {"someone-else"}}
+	if code := put("object", data, accessKey, secretKey, header); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if code := put("root-object", data, credentials.AccessKey, credentials.SecretKey, header); code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, "object")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if owner := objInfo.UserDefined[minioMetaOwner]; owner != accessKey {
+		t.Errorf("%s: Expected owner %s, got %s", instanceType, accessKey, owner)
+	}
+
+	// Usage is accounted by the data usage crawls.
+	if err = newDataUsageCrawler(obj, time.Hour).update(time.Now().UTC()); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	usage := globalIAMSys.ListUsers()[accessKey].Usage
+	if usage.Objects != 1 || usage.Size != uint64(len(data)) {
+		t.Errorf("%s: Unexpected usage %#v", instanceType, usage)
+	}
+	info, err := readDataUsage(obj)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if info.Owners[credentials.AccessKey].Objects != 1 || info.Owners["someone-else"].Objects != 0 {
+		t.Errorf("%s: Unexpected owners %#v", instanceType, info.Owners)
+	}
+
+	// Uploads within the quota are allowed, the others rejected.
+	if code := put("small-object", []byte("12"), accessKey, secretKey, nil); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	if code := put("large-object", []byte("123"), accessKey, secretKey, nil); code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	// As are copies.
+	req, err := newTestSignedRequestV4("PUT", getCopyObjectURL("", bucketName, "copied-object"), 0, nil, accessKey, secretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Copy-Source", "/"+bucketName+"/object")
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+	// Other identities are not limited.
+	if code := put("large-object", []byte("123"), credentials.AccessKey, credentials.SecretKey, nil); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+
+	// Unlimited again.
+	if err = globalIAMSys.SetUserQuota(obj, accessKey, 0); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if code := put("large-object", []byte("123"), accessKey, secretKey, nil); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+}
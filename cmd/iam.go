@@ -0,0 +1,728 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// IAM config path in minioMetaBucket, holding the users and policies
+// of all the servers.
+const iamConfigFile = "config/iam.json"
+
+// Current version of the IAM config.
+const iamConfigVersion = "1"
+
+// Status of IAM users.
+const (
+	iamUserEnabled  = "enabled"
+	iamUserDisabled = "disabled"
+)
+
+var (
+	errNoSuchUser   = errors.New("No such user")
+	errNoSuchPolicy = errors.New("No such policy")
+	errCannedPolicy = errors.New("Canned policies can not be modified")
+
+	errInvalidIAMAccessKey = errors.New("Access keys of users can not contain wild cards")
+)
+
+// Wild cards of the resources of policies. Access keys replace the
+// policy variables of the policies of their user, they should not
+// contain any.
+const iamWildcards = "*?"
+
+// isIAMAccessKeyValid - returns true if accessKey is valid for a user.
+func isIAMAccessKeyValid(accessKey string) bool {
+	return isAccessKeyValid(accessKey) && !strings.ContainsAny(accessKey, iamWildcards)
+}
+
+// iamUser - credentials, status, policies and storage quota of an IAM
+// user, a quota of 0 being unlimited.
+type iamUser struct {
+	SecretKey string   `json:"secretKey"`
+	Status    string   `json:"status"`
+	Policies  []string `json:"policies,omitempty"`
+	Quota     int64    `json:"quota,omitempty"`
+	// Bandwidth limits of the transfers of the user, unlimited if nil.
+	Bandwidth *BandwidthConfiguration `json:"bandwidth,omitempty"`
+}
+
+// iamConfig - users and policies of the IAM subsystem, keyed by the
+// access keys of the users and the names of the policies.
+type iamConfig struct {
+	Version  string                  `json:"version"`
+	Users    map[string]iamUser      `json:"users"`
+	Policies map[string]bucketPolicy `json:"policies"`
+}
+
+func newIAMConfig() iamConfig {
+	return iamConfig{
+		Version:  iamConfigVersion,
+		Users:    make(map[string]iamUser),
+		Policies: make(map[string]bucketPolicy),
+	}
+}
+
+// clone - returns a copy of config safe to be modified.
+func (config iamConfig) clone() iamConfig {
+	cloned := newIAMConfig()
+	for accessKey, user := range config.Users {
+		user.Policies = append([]string(nil), user.Policies...)
+		cloned.Users[accessKey] = user
+	}
+	for name, policy := range config.Policies {
+		cloned.Policies[name] = policy
+	}
+	return cloned
+}
+
+// newCannedIAMPolicy - returns a policy allowing actions on all the
+// buckets and objects.
+func newCannedIAMPolicy(actions ...string) bucketPolicy {
+	return bucketPolicy{
+		Version: "2012-10-17",
+		Statements: []policyStatement{{
+			Actions:   set.CreateStringSet(actions...),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(bucketARNPrefix + "*"),
+		}},
+	}
+}
+
+// Canned policies, available to all the users and not modifiable.
+var cannedIAMPolicies = map[string]bucketPolicy{
+	"readonly": newCannedIAMPolicy("s3:GetBucketLocation", "s3:ListAllMyBuckets",
+		"s3:ListBucket", "s3:GetObject"),
+	"writeonly": newCannedIAMPolicy("s3:GetBucketLocation", "s3:ListAllMyBuckets",
+		"s3:PutObject", "s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads",
+		"s3:ListMultipartUploadParts"),
+	"readwrite": newCannedIAMPolicy("s3:*"),
+	// Drop-box access, uploading objects without listing, reading
+	// or removing them.
+	"uploadonly": newCannedIAMPolicy("s3:PutObject"),
+}
+
+// Actions of IAM policies in addition to the actions of bucket
+// policies, for requests not bound to an existing bucket.
+var iamOnlyActions = set.CreateStringSet("s3:ListAllMyBuckets", "s3:CreateBucket", "s3:DeleteBucket")
+
+// parseIAMPolicy - parses and validates an IAM policy, which is a
+// bucket policy without principals and with resources in any bucket.
+func parseIAMPolicy(reader io.Reader) (bucketPolicy, error) {
+	var policy bucketPolicy
+	if err := json.NewDecoder(reader).Decode(&policy); err != nil {
+		return policy, err
+	}
+	if policy.Version == "" {
+		return policy, errors.New("Policy version cannot be empty")
+	}
+	if len(policy.Statements) == 0 {
+		return policy, errors.New("Policy statement cannot be empty")
+	}
+	for _, statement := range policy.Statements {
+		if len(statement.Actions) == 0 {
+			return policy, errors.New("Action list cannot be empty")
+		}
+		unsupportedActions := statement.Actions.Difference(supportedActionMap).Difference(iamOnlyActions)
+		if !unsupportedActions.IsEmpty() {
+			return policy, fmt.Errorf("Unsupported actions found: ‘%#v’, please validate your policy document", unsupportedActions)
+		}
+		if err := isValidEffect(statement.Effect); err != nil {
+			return policy, err
+		}
+		if err := isValidResources(statement.Resources); err != nil {
+			return policy, err
+		}
+		if err := isValidConditions(statement.Conditions); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+// iamSys - users and policies of the IAM subsystem, loaded from etcd
+// or the backend and updated by the admin API.
+type iamSys struct {
+	rwMutex sync.RWMutex
+	config  iamConfig
+}
+
+// Users and policies of this server.
+var globalIAMSys = &iamSys{config: newIAMConfig()}
+
+// parseIAMConfig - parses a persisted IAM config.
+func parseIAMConfig(data []byte) (iamConfig, error) {
+	config := newIAMConfig()
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+	if config.Users == nil {
+		config.Users = make(map[string]iamUser)
+	}
+	if config.Policies == nil {
+		config.Policies = make(map[string]bucketPolicy)
+	}
+	return config, nil
+}
+
+// readIAMConfig - reads the IAM config from etcd if configured, from
+// the backend otherwise. A server without a persisted config has no
+// users.
+func readIAMConfig(objAPI ObjectLayer) (iamConfig, error) {
+	if globalEtcdClient != nil {
+		config, _, err := readIAMConfigEtcd()
+		return config, err
+	}
+
+	config := newIAMConfig()
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, iamConfigFile, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return config, nil
+		}
+		errorIf(err, "Unable to load IAM config.")
+		return config, errorCause(err)
+	}
+	if config, err = parseIAMConfig(buffer.Bytes()); err != nil {
+		errorIf(err, "Unable to parse IAM config.")
+		return config, err
+	}
+	return config, nil
+}
+
+// writeIAMConfig - saves the IAM config.
+func writeIAMConfig(config iamConfig, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, iamConfigFile, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to save IAM config.")
+		return errorCause(err)
+	}
+	return nil
+}
+
+// initIAMSys - loads the users and policies of the IAM subsystem.
+func initIAMSys(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+	return globalIAMSys.Load(objAPI)
+}
+
+// Load - replaces the users and policies with the ones persisted in
+// etcd or the backend.
+func (sys *iamSys) Load(objAPI ObjectLayer) error {
+	iamLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, iamConfigFile)
+	iamLock.RLock()
+	config, err := readIAMConfig(objAPI)
+	iamLock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	sys.rwMutex.Lock()
+	sys.config = config
+	sys.rwMutex.Unlock()
+	return nil
+}
+
+// update - applies fn to the persisted IAM config under the IAM
+// lock, then saves and uses the result.
+func (sys *iamSys) update(objAPI ObjectLayer, fn func(config *iamConfig) error) error {
+	iamLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, iamConfigFile)
+	iamLock.Lock()
+	defer iamLock.Unlock()
+
+	var config iamConfig
+	var err error
+	if globalEtcdClient != nil {
+		// Deployments sharing etcd do not share the lock, updates
+		// are made atomic by etcd instead.
+		config, err = updateIAMConfigEtcd(fn)
+	} else {
+		// Users and policies may have been changed by other servers.
+		config, err = readIAMConfig(objAPI)
+		if err == nil {
+			err = fn(&config)
+		}
+		if err == nil {
+			err = writeIAMConfig(config, objAPI)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	sys.rwMutex.Lock()
+	sys.config = config
+	sys.rwMutex.Unlock()
+	return nil
+}
+
+// SetUser - adds a user, or changes the secret key of an existing
+// user, keeping its status and policies.
+func (sys *iamSys) SetUser(objAPI ObjectLayer, accessKey, secretKey string) error {
+	if !isIAMAccessKeyValid(accessKey) {
+		return errInvalidIAMAccessKey
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			user.Status = iamUserEnabled
+		}
+		user.SecretKey = secretKey
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// RemoveUser - removes a user.
+func (sys *iamSys) RemoveUser(objAPI ObjectLayer, accessKey string) error {
+	return sys.update(objAPI, func(config *iamConfig) error {
+		if _, ok := config.Users[accessKey]; !ok {
+			return errNoSuchUser
+		}
+		delete(config.Users, accessKey)
+		return nil
+	})
+}
+
+// SetUserStatus - enables or disables a user, disabled users can not
+// authenticate.
+func (sys *iamSys) SetUserStatus(objAPI ObjectLayer, accessKey, status string) error {
+	if status != iamUserEnabled && status != iamUserDisabled {
+		return errInvalidArgument
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		user.Status = status
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// SetUserQuota - sets the storage quota of a user in bytes, 0 for
+// unlimited.
+func (sys *iamSys) SetUserQuota(objAPI ObjectLayer, accessKey string, quota int64) error {
+	if quota < 0 {
+		return errInvalidArgument
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		user.Quota = quota
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// GetUserQuota - returns the storage quota of a user, 0 if unlimited
+// or not a user.
+func (sys *iamSys) GetUserQuota(accessKey string) int64 {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	return sys.config.Users[accessKey].Quota
+}
+
+// SetUserBandwidth - sets the bandwidth limits of the transfers of a
+// user, in bytes per second, 0 for unlimited.
+func (sys *iamSys) SetUserBandwidth(objAPI ObjectLayer, accessKey string, bandwidth BandwidthConfiguration) error {
+	if bandwidth.UploadLimit < 0 || bandwidth.DownloadLimit < 0 {
+		return errInvalidArgument
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		user.Bandwidth = nil
+		if !bandwidth.isUnlimited() {
+			user.Bandwidth = &bandwidth
+		}
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// GetUserBandwidth - returns the bandwidth limits of a user, unlimited
+// if not a user.
+func (sys *iamSys) GetUserBandwidth(accessKey string) BandwidthConfiguration {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	if bandwidth := sys.config.Users[accessKey].Bandwidth; bandwidth != nil {
+		return *bandwidth
+	}
+	return BandwidthConfiguration{}
+}
+
+// SetPolicy - adds or replaces a policy.
+func (sys *iamSys) SetPolicy(objAPI ObjectLayer, name string, policy bucketPolicy) error {
+	if _, ok := cannedIAMPolicies[name]; ok {
+		return errCannedPolicy
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		config.Policies[name] = policy
+		return nil
+	})
+}
+
+// RemovePolicy - removes a policy, detaching it from all the users.
+func (sys *iamSys) RemovePolicy(objAPI ObjectLayer, name string) error {
+	if _, ok := cannedIAMPolicies[name]; ok {
+		return errCannedPolicy
+	}
+	return sys.update(objAPI, func(config *iamConfig) error {
+		if _, ok := config.Policies[name]; !ok {
+			return errNoSuchPolicy
+		}
+		delete(config.Policies, name)
+		for accessKey, user := range config.Users {
+			user.Policies = removePolicyName(user.Policies, name)
+			config.Users[accessKey] = user
+		}
+		return nil
+	})
+}
+
+// AttachPolicy - attaches a canned or added policy to a user.
+func (sys *iamSys) AttachPolicy(objAPI ObjectLayer, accessKey, name string) error {
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		if _, ok = cannedIAMPolicies[name]; !ok {
+			if _, ok = config.Policies[name]; !ok {
+				return errNoSuchPolicy
+			}
+		}
+		user.Policies = append(removePolicyName(user.Policies, name), name)
+		sort.Strings(user.Policies)
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// DetachPolicy - detaches a policy from a user.
+func (sys *iamSys) DetachPolicy(objAPI ObjectLayer, accessKey, name string) error {
+	return sys.update(objAPI, func(config *iamConfig) error {
+		user, ok := config.Users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		policies := removePolicyName(user.Policies, name)
+		if len(policies) == len(user.Policies) {
+			return errNoSuchPolicy
+		}
+		user.Policies = policies
+		config.Users[accessKey] = user
+		return nil
+	})
+}
+
+// removePolicyName - returns policies without name.
+func removePolicyName(policies []string, name string) []string {
+	var remaining []string
+	for _, policy := range policies {
+		if policy != name {
+			remaining = append(remaining, policy)
+		}
+	}
+	return remaining
+}
+
+// iamUserInfo - user as listed by the admin API, without its secret
+// key, along with the data usage of the objects it owns as of the
+// last data usage crawl.
+type iamUserInfo struct {
+	Status    string                  `json:"status"`
+	Policies  []string                `json:"policies,omitempty"`
+	Quota     int64                   `json:"quota,omitempty"`
+	Bandwidth *BandwidthConfiguration `json:"bandwidth,omitempty"`
+	Usage     dataUsageEntry          `json:"usage"`
+}
+
+// ListUsers - returns the users keyed by their access keys.
+func (sys *iamSys) ListUsers() map[string]iamUserInfo {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	users := make(map[string]iamUserInfo, len(sys.config.Users))
+	for accessKey, user := range sys.config.Users {
+		users[accessKey] = iamUserInfo{
+			Status:    user.Status,
+			Policies:  append([]string(nil), user.Policies...),
+			Quota:     user.Quota,
+			Bandwidth: user.Bandwidth,
+			Usage:     globalOwnerUsage.Get(accessKey),
+		}
+	}
+	return users
+}
+
+// ListPolicies - returns the canned and added policies keyed by their
+// names.
+func (sys *iamSys) ListPolicies() map[string]bucketPolicy {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	policies := make(map[string]bucketPolicy, len(cannedIAMPolicies)+len(sys.config.Policies))
+	for name, policy := range cannedIAMPolicies {
+		policies[name] = policy
+	}
+	for name, policy := range sys.config.Policies {
+		policies[name] = policy
+	}
+	return policies
+}
+
+// GetCredential - returns the credential of an enabled user.
+func (sys *iamSys) GetCredential(accessKey string) (credential, bool) {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	user, ok := sys.config.Users[accessKey]
+	if !ok || user.Status != iamUserEnabled {
+		return credential{}, false
+	}
+	return credential{AccessKey: accessKey, SecretKey: user.SecretKey}, true
+}
+
+// IsAllowed - returns true if action on resource is allowed for the
+// user by its policies or by the policy of the bucket, and denied by
+// none of them.
+func (sys *iamSys) IsAllowed(accessKey, action, resource string, conditions map[string]set.StringSet) bool {
+	statements, ok := sys.GetStatements(accessKey)
+	if !ok {
+		return false
+	}
+	statements = append(statements, getResourcePolicyStatements(action, resource)...)
+	return bucketPolicyEvalStatements(action, resource, conditions, statements)
+}
+
+// GetStatements - returns the statements of the policies of an
+// enabled user, with their policy variables substituted.
+func (sys *iamSys) GetStatements(accessKey string) ([]policyStatement, bool) {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+	user, ok := sys.config.Users[accessKey]
+	if !ok || user.Status != iamUserEnabled {
+		return nil, false
+	}
+	// Substituted wild cards would match the resources of other users.
+	if strings.ContainsAny(accessKey, iamWildcards) {
+		return nil, false
+	}
+	var statements []policyStatement
+	for _, name := range user.Policies {
+		policy, ok := cannedIAMPolicies[name]
+		if !ok {
+			policy = sys.config.Policies[name]
+		}
+		for _, statement := range policy.Statements {
+			statements = append(statements, substitutePolicyVariables(statement, accessKey))
+		}
+	}
+	return statements, true
+}
+
+// Policy variables replaced in the resources and condition values of
+// IAM policies by the access key of the user the policy is evaluated
+// for, e.g "arn:aws:s3:::mybucket/${aws:username}/*".
+var iamPolicyVariables = []string{"${aws:username}", "${aws:userid}"}
+
+// substitutePolicyVariables - returns statement with its policy
+// variables replaced by accessKey.
+func substitutePolicyVariables(statement policyStatement, accessKey string) policyStatement {
+	var oldnew []string
+	for _, variable := range iamPolicyVariables {
+		oldnew = append(oldnew, variable, accessKey)
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	hasVariables := func(values set.StringSet) bool {
+		for value := range values {
+			if replacer.Replace(value) != value {
+				return true
+			}
+		}
+		return false
+	}
+	substitute := func(values set.StringSet) set.StringSet {
+		substituted := set.NewStringSet()
+		for value := range values {
+			substituted.Add(replacer.Replace(value))
+		}
+		return substituted
+	}
+
+	if hasVariables(statement.Resources) {
+		statement.Resources = substitute(statement.Resources)
+	}
+	if len(statement.Conditions) > 0 {
+		conditions := make(map[string]map[string]set.StringSet, len(statement.Conditions))
+		for condition, conditionKeyVal := range statement.Conditions {
+			conditions[condition] = make(map[string]set.StringSet, len(conditionKeyVal))
+			for key, values := range conditionKeyVal {
+				if hasVariables(values) {
+					values = substitute(values)
+				}
+				conditions[condition][key] = values
+			}
+		}
+		statement.Conditions = conditions
+	}
+	return statement
+}
+
+// lookupCredential - returns the credential of accessKey, which is the
+// server credential or the credential of an enabled IAM user.
+func lookupCredential(accessKey string) (credential, APIErrorCode) {
+	cred := serverConfig.GetCredential()
+	if accessKey == cred.AccessKey {
+		return cred, ErrNone
+	}
+	if userCred, ok := globalIAMSys.GetCredential(accessKey); ok {
+		return userCred, ErrNone
+	}
+	return credential{}, ErrInvalidAccessKeyID
+}
+
+// getRequestAccessKey - returns the access key a signed request is
+// signed with.
+func getRequestAccessKey(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		if signV4Values, err := parseSignV4(r.Header.Get("Authorization")); err == ErrNone {
+			return signV4Values.Credential.accessKey
+		}
+	case authTypePresigned:
+		credHeader, err := parseCredentialHeader("Credential=" + r.URL.Query().Get("X-Amz-Credential"))
+		if err == ErrNone {
+			return credHeader.accessKey
+		}
+	case authTypeSignedV2:
+		authFields := strings.Split(r.Header.Get("Authorization"), " ")
+		if len(authFields) == 2 {
+			return strings.Split(strings.TrimSpace(authFields[1]), ":")[0]
+		}
+	case authTypePresignedV2:
+		return r.URL.Query().Get("AWSAccessKeyId")
+	}
+	return ""
+}
+
+// getPostPolicyAccessKey - returns the access key a form upload is
+// signed with.
+func getPostPolicyAccessKey(formValues map[string]string) string {
+	if _, ok := formValues["X-Amz-Credential"]; !ok {
+		return formValues["Awsaccesskeyid"]
+	}
+	credHeader, _ := parseCredentialHeader("Credential=" + formValues["X-Amz-Credential"])
+	return credHeader.accessKey
+}
+
+// isIAMActionAllowed - returns true if action on resource is allowed
+// for accessKey, always true for the server credential. Actions not
+// bound to a policy action are reserved to the server credential.
+//
+// As for AWS, the requests of IAM users are allowed by their policies
+// or by the policy of the bucket, and denied when explicitly denied by
+// any of them.
+func isIAMActionAllowed(accessKey, action, resource string, conditions map[string]set.StringSet) bool {
+	if accessKey == serverConfig.GetCredential().AccessKey {
+		return true
+	}
+	if action == "" {
+		return false
+	}
+	return globalIAMSys.IsAllowed(accessKey, action, resource, conditions)
+}
+
+// getResourcePolicyStatements - returns the statements of the policy
+// of the bucket of resource, none for actions of IAM policies only.
+func getResourcePolicyStatements(action, resource string) []policyStatement {
+	if globalBucketPolicies == nil || iamOnlyActions.Contains(action) {
+		return nil
+	}
+	bucket := strings.SplitN(strings.TrimPrefix(resource, bucketARNPrefix), "/", 2)[0]
+	policy := globalBucketPolicies.GetBucketPolicy(bucket)
+	if policy == nil {
+		return nil
+	}
+	return policy.Statements
+}
+
+// checkIAMAccess - verifies that the policies of the IAM user an
+// authenticated request is signed by allow policyAction, recording the
+// use of its access key.
+func checkIAMAccess(r *http.Request, policyAction string) APIErrorCode {
+	accessKey := getRequestAccessKey(r)
+	globalAccessKeyUsage.Record(accessKey, r.RemoteAddr)
+	if !isIAMActionAllowed(accessKey, policyAction, getRequestResource(r.URL), getRequestConditions(r.URL)) {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// checkCopySourceAccess - verifies that a request copying from
+// srcBucket/srcObject is allowed to read the source, by the bucket
+// policy of the source for anonymous requests and by the policies of
+// the IAM user for signed requests. Copies are otherwise only checked
+// against their destination.
+func checkCopySourceAccess(r *http.Request, srcBucket, srcObject string) APIErrorCode {
+	srcURL := &url.URL{Path: "/" + pathJoin(srcBucket, srcObject)}
+	if getRequestAuthType(r) == authTypeAnonymous {
+		return checkAnonymousAccess(r, srcBucket, "s3:GetObject", srcURL)
+	}
+	if !isIAMActionAllowed(getRequestAccessKey(r), "s3:GetObject", getRequestResource(srcURL), getRequestConditions(r.URL)) {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// getRequestResource - returns the resource of the request in
+// 'arn:aws:s3:::examplebucket/object' format.
+func getRequestResource(reqURL *url.URL) string {
+	return bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(reqURL.Path, "/"), "/")
+}
+
+// getRequestConditions - returns the condition keys of the request.
+func getRequestConditions(reqURL *url.URL) map[string]set.StringSet {
+	conditionKeyMap := make(map[string]set.StringSet)
+	for queryParam := range reqURL.Query() {
+		conditionKeyMap[queryParam] = set.CreateStringSet(reqURL.Query().Get(queryParam))
+	}
+	return conditionKeyMap
+}
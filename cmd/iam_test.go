@@ -0,0 +1,484 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Tests validating IAM policies.
+func TestParseIAMPolicy(t *testing.T) {
+	testCases := []struct {
+		policy     string
+		shouldPass bool
+	}{
+		// Test 1 - actions on objects of a bucket.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`, true},
+		// Test 2 - actions of IAM policies only.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:ListAllMyBuckets","s3:CreateBucket"],"Resource":["arn:aws:s3:::*"]}]}`, true},
+		// Test 3 - unsupported action.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:PutBucketPolicy"],"Resource":["arn:aws:s3:::mybucket"]}]}`, false},
+		// Test 4 - invalid effect.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Maybe","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`, false},
+		// Test 5 - invalid resource.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["mybucket/*"]}]}`, false},
+		// Test 6 - no statements.
+		{`{"Version":"2012-10-17","Statement":[]}`, false},
+		// Test 7 - no version.
+		{`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`, false},
+		// Test 8 - malformed JSON.
+		{`{"Version":`, false},
+	}
+	for i, testCase := range testCases {
+		_, err := parseIAMPolicy(strings.NewReader(testCase.policy))
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+}
+
+// Tests evaluating the policies of IAM users.
+func TestIAMSysIsAllowed(t *testing.T) {
+	policy, err := parseIAMPolicy(strings.NewReader(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::uploads/*"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys := &iamSys{config: newIAMConfig()}
+	sys.config.Policies["uploads"] = policy
+	sys.config.Users["reader"] = iamUser{SecretKey: "readersecret", Status: iamUserEnabled, Policies: []string{"readonly"}}
+	sys.config.Users["uploader"] = iamUser{SecretKey: "uploadersecret", Status: iamUserEnabled, Policies: []string{"uploads"}}
+	sys.config.Users["disabled"] = iamUser{SecretKey: "disabledsecret", Status: iamUserDisabled, Policies: []string{"readwrite"}}
+
+	testCases := []struct {
+		accessKey, action, resource string
+		allowed                     bool
+	}{
+		{"reader", "s3:GetObject", "arn:aws:s3:::mybucket/object", true},
+		{"reader", "s3:ListAllMyBuckets", "arn:aws:s3:::", true},
+		{"reader", "s3:PutObject", "arn:aws:s3:::mybucket/object", false},
+		{"uploader", "s3:PutObject", "arn:aws:s3:::uploads/object", true},
+		{"uploader", "s3:PutObject", "arn:aws:s3:::mybucket/object", false},
+		{"uploader", "s3:GetObject", "arn:aws:s3:::uploads/object", false},
+		{"disabled", "s3:GetObject", "arn:aws:s3:::mybucket/object", false},
+		{"missing", "s3:GetObject", "arn:aws:s3:::mybucket/object", false},
+	}
+	for i, testCase := range testCases {
+		allowed := sys.IsAllowed(testCase.accessKey, testCase.action, testCase.resource, map[string]set.StringSet{})
+		if allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %s of %s on %s to be allowed %v, got %v", i+1,
+				testCase.action, testCase.accessKey, testCase.resource, testCase.allowed, allowed)
+		}
+	}
+
+	if _, ok := sys.GetCredential("disabled"); ok {
+		t.Errorf("Expected disabled users not to authenticate")
+	}
+	if cred, ok := sys.GetCredential("reader"); !ok || cred.SecretKey != "readersecret" {
+		t.Errorf("Expected the credential of reader, got %v", cred)
+	}
+}
+
+// Tests substituting policy variables in the resources and conditions
+// of IAM policies.
+func TestIAMPolicyVariables(t *testing.T) {
+	policy, err := parseIAMPolicy(strings.NewReader(`{"Version":"2012-10-17","Statement":[
+{"Effect":"Allow","Action":["s3:ListBucket"],"Resource":["arn:aws:s3:::home"],"Condition":{"StringEquals":{"s3:prefix":["${aws:username}/"]}}},
+{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":["arn:aws:s3:::home/${aws:username}/*"]},
+{"Effect":"Deny","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::home/${aws:username}/readonly/*"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys := &iamSys{config: newIAMConfig()}
+	sys.config.Policies["home"] = policy
+	for _, accessKey := range []string{"alice", "bob"} {
+		sys.config.Users[accessKey] = iamUser{SecretKey: "secretkey", Status: iamUserEnabled, Policies: []string{"home"}}
+	}
+	// Stored before access keys with wild cards were rejected.
+	sys.config.Users["wild*"] = iamUser{SecretKey: "secretkey", Status: iamUserEnabled, Policies: []string{"readwrite", "home"}}
+
+	testCases := []struct {
+		accessKey, action, resource string
+		prefix                      string
+		allowed                     bool
+	}{
+		{"alice", "s3:GetObject", "arn:aws:s3:::home/alice/notes.txt", "", true},
+		{"alice", "s3:PutObject", "arn:aws:s3:::home/alice/docs/report.pdf", "", true},
+		{"alice", "s3:GetObject", "arn:aws:s3:::home/bob/notes.txt", "", false},
+		{"bob", "s3:GetObject", "arn:aws:s3:::home/bob/notes.txt", "", true},
+		{"alice", "s3:ListBucket", "arn:aws:s3:::home", "alice/", true},
+		{"alice", "s3:ListBucket", "arn:aws:s3:::home", "bob/", false},
+		{"alice", "s3:PutObject", "arn:aws:s3:::home/alice/readonly/notes.txt", "", false},
+		// Users with wild cards in their access keys are denied
+		// everything, denied statements included.
+		{"wild*", "s3:GetObject", "arn:aws:s3:::home/wild*/notes.txt", "", false},
+		{"wild*", "s3:GetObject", "arn:aws:s3:::home/wildcat/notes.txt", "", false},
+		{"wild*", "s3:PutObject", "arn:aws:s3:::home/wildcat/readonly/notes.txt", "", false},
+		{"wild*", "s3:GetObject", "arn:aws:s3:::photos/cat.png", "", false},
+	}
+	for i, testCase := range testCases {
+		conditions := map[string]set.StringSet{}
+		if testCase.prefix != "" {
+			conditions["prefix"] = set.CreateStringSet(testCase.prefix)
+		}
+		allowed := sys.IsAllowed(testCase.accessKey, testCase.action, testCase.resource, conditions)
+		if allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %s of %s on %s to be allowed %v, got %v", i+1,
+				testCase.action, testCase.accessKey, testCase.resource, testCase.allowed, allowed)
+		}
+	}
+
+	// The policy itself is left untouched.
+	if !sys.config.Policies["home"].Statements[1].Resources.Contains("arn:aws:s3:::home/${aws:username}/*") {
+		t.Errorf("Expected the policy variables to be kept in the policy, got %v", sys.config.Policies["home"].Statements[1].Resources)
+	}
+
+	// Access keys with wild cards are rejected.
+	for _, accessKey := range []string{"wild*", "wild?"} {
+		if err := sys.SetUser(nil, accessKey, "secretkey"); err != errInvalidIAMAccessKey {
+			t.Errorf("Expected %s to be rejected with %v, got %v", accessKey, errInvalidIAMAccessKey, err)
+		}
+	}
+}
+
+// Tests the requests of IAM users being allowed by their policies or
+// the policy of the bucket, unless explicitly denied by any of them.
+func TestIAMBucketPolicyPrecedence(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+	defer resetGlobalIAMSys()
+
+	savedPolicies := globalBucketPolicies
+	defer func() { globalBucketPolicies = savedPolicies }()
+	sharedPolicy, err := parseIAMPolicy(strings.NewReader(`{"Version":"2012-10-17","Statement":[
+{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::shared/public/*"]},
+{"Effect":"Deny","Principal":{"AWS":["*"]},"Action":["s3:GetObject","s3:PutObject"],"Resource":["arn:aws:s3:::shared/locked/*"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalBucketPolicies = &bucketPolicies{
+		rwMutex:             &sync.RWMutex{},
+		bucketPolicyConfigs: map[string]*bucketPolicy{"shared": &sharedPolicy},
+	}
+
+	denyPolicy, err := parseIAMPolicy(strings.NewReader(`{"Version":"2012-10-17","Statement":[
+{"Effect":"Deny","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::*/readonly/*"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalIAMSys = &iamSys{config: newIAMConfig()}
+	globalIAMSys.config.Policies["deny-readonly"] = denyPolicy
+	globalIAMSys.config.Users["writer"] = iamUser{SecretKey: "secretkey", Status: iamUserEnabled, Policies: []string{"readwrite", "deny-readonly"}}
+	globalIAMSys.config.Users["nobody"] = iamUser{SecretKey: "secretkey", Status: iamUserEnabled}
+
+	testCases := []struct {
+		accessKey, action, resource string
+		allowed                     bool
+	}{
+		// Allowed by the policies of the user.
+		{"writer", "s3:PutObject", "arn:aws:s3:::shared/data/1.txt", true},
+		// Denied by the policies of the user, whatever the statement order.
+		{"writer", "s3:PutObject", "arn:aws:s3:::shared/readonly/1.txt", false},
+		// Denied by the bucket policy.
+		{"writer", "s3:GetObject", "arn:aws:s3:::shared/locked/1.txt", false},
+		{"writer", "s3:PutObject", "arn:aws:s3:::shared/locked/1.txt", false},
+		// Allowed by the bucket policy only.
+		{"nobody", "s3:GetObject", "arn:aws:s3:::shared/public/1.txt", true},
+		{"nobody", "s3:GetObject", "arn:aws:s3:::shared/data/1.txt", false},
+		// The server credentials are never denied.
+		{serverConfig.GetCredential().AccessKey, "s3:GetObject", "arn:aws:s3:::shared/locked/1.txt", true},
+	}
+	for i, testCase := range testCases {
+		allowed := isIAMActionAllowed(testCase.accessKey, testCase.action, testCase.resource, map[string]set.StringSet{})
+		if allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %s of %s on %s to be allowed %v, got %v", i+1,
+				testCase.action, testCase.accessKey, testCase.resource, testCase.allowed, allowed)
+		}
+	}
+}
+
+// Wrapper for calling the requests of IAM users tests for both XL multiple disks and single node setup.
+func TestIAMUserRequests(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer resetGlobalIAMSys()
+	ExecObjectLayerAPITest(t, testIAMUserRequests, []string{"GetObject", "PutObject", "PutBucketPolicy"})
+}
+
+func testIAMUserRequests(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	accessKey, secretKey := "reader", "readersecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	policyJSON := []byte(`{"Version":"2012-10-17","Statement":[]}`)
+	testCases := []struct {
+		method     string
+		urlStr     string
+		body       []byte
+		statusCode int
+	}{
+		{"GET", getGetObjectURL("", bucketName, "object"), nil, http.StatusOK},
+		{"GET", getListBucketURL(""), nil, http.StatusOK},
+		{"PUT", getPutObjectURL("", bucketName, "new-object"), data, http.StatusForbidden},
+		{"PUT", getPutPolicyURL("", bucketName), policyJSON, http.StatusForbidden},
+	}
+
+	check := func(policies string, expected []int) {
+		for i, testCase := range testCases {
+			req, err := newTestSignedRequestV4(testCase.method, testCase.urlStr, int64(len(testCase.body)),
+				bytes.NewReader(testCase.body), accessKey, secretKey)
+			if err != nil {
+				t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+			}
+			rec := httptest.NewRecorder()
+			apiRouter.ServeHTTP(rec, req)
+			if rec.Code != expected[i] {
+				t.Errorf("%s: Test %d with %s: Expected the response status to be `%d`, but instead found `%d`",
+					instanceType, i+1, policies, expected[i], rec.Code)
+			}
+		}
+	}
+
+	// Users have no access until policies are attached to them.
+	check("no policies", []int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden})
+
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "readonly"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	expected := make([]int, len(testCases))
+	for i, testCase := range testCases {
+		expected[i] = testCase.statusCode
+	}
+	check("readonly", expected)
+
+	// Bucket configuration is reserved to the server credentials.
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "readwrite"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	check("readwrite", []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusForbidden})
+
+	// Disabled users can not authenticate.
+	if err := globalIAMSys.SetUserStatus(obj, accessKey, iamUserDisabled); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	check("disabled user", []int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden})
+}
+
+// Wrapper for calling the copies of IAM users tests for both XL multiple disks and single node setup.
+func TestIAMUserCopySources(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer resetGlobalIAMSys()
+	ExecObjectLayerAPITest(t, testIAMUserCopySources, []string{"CopyObject", "ComposeObject", "CopyMultipleObjects"})
+}
+
+func testIAMUserCopySources(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	privateBucket := "private-sources"
+	if err := obj.MakeBucket(context.Background(), privateBucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	data := []byte("hello")
+	for _, bucket := range []string{bucketName, privateBucket} {
+		if _, err := obj.PutObject(context.Background(), bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	// The user can read and write bucketName only.
+	accessKey, secretKey := "copier", "copiersecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	policy := bucketPolicy{
+		Version: "2012-10-17",
+		Statements: []policyStatement{{
+			Actions:   set.CreateStringSet("s3:GetObject", "s3:PutObject"),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(bucketARNPrefix+bucketName, bucketARNPrefix+bucketName+"/*"),
+		}},
+	}
+	if err := globalIAMSys.SetPolicy(obj, "copier", policy); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "copier"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	sendRequest := func(method, urlStr string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		req, err := newTestRequest(method, urlStr, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if err = signRequestV4(req, accessKey, secretKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, testCase := range []struct {
+		srcBucket  string
+		statusCode int
+	}{
+		{bucketName, http.StatusOK},
+		// Sources should be readable by the user.
+		{privateBucket, http.StatusForbidden},
+	} {
+		// Copy.
+		header := http.Header{"X-Amz-Copy-Source": []string{url.QueryEscape("/" + testCase.srcBucket + "/object")}}
+		rec := sendRequest("PUT", getCopyObjectURL("", bucketName, "copied"), nil, header)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the copy response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+
+		// Compose.
+		composeXML, err := xml.Marshal(ComposeObjectRequest{Sources: []ComposeSource{{Bucket: testCase.srcBucket, Key: "object"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec = sendRequest("POST", getComposeObjectURL("", bucketName, "composed"), composeXML, nil)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the compose response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+
+		// Bulk copy, failing copies are reported per object.
+		copyXML, err := xml.Marshal(CopyObjectsRequest{Objects: []CopyObjectIdentifier{{Source: "/" + testCase.srcBucket + "/object", ObjectName: "bulk-copied"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec = sendRequest("POST", getCopyMultipleObjectsURL("", bucketName), copyXML, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Test %d: Expected the bulk copy response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, http.StatusOK, rec.Code)
+		}
+		copyResponse := CopyObjectsResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &copyResponse); err != nil {
+			t.Fatal(err)
+		}
+		denied := len(copyResponse.Errors) == 1 && copyResponse.Errors[0].Code == "AccessDenied"
+		if denied != (testCase.statusCode == http.StatusForbidden) {
+			t.Errorf("%s: Test %d: Unexpected bulk copy response %+v", instanceType, i+1, copyResponse)
+		}
+	}
+}
+
+// Wrapper for calling the prefix renames of IAM users tests for both XL multiple disks and single node setup.
+func TestIAMUserRenamePrefix(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer resetGlobalIAMSys()
+	ExecObjectLayerAPITest(t, testIAMUserRenamePrefix, []string{"RenamePrefix"})
+}
+
+func testIAMUserRenamePrefix(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, "victim/doc", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	accessKey, secretKey := "renamer", "renamersecret"
+	if err := globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err := globalIAMSys.AttachPolicy(obj, accessKey, "uploadonly"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	renameXML, err := xml.Marshal(RenamePrefixRequest{Prefix: "victim/", NewPrefix: "moved/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rename := func() int {
+		req, err := newTestSignedRequestV4("POST", getRenamePrefixURL("", bucketName),
+			int64(len(renameXML)), bytes.NewReader(renameXML), accessKey, secretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Writing objects does not allow to move the objects of others.
+	if code := rename(); code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the upload only user to be denied, got `%d`", instanceType, code)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, "victim/doc"); err != nil {
+		t.Fatalf("%s: Expected victim/doc to be left in place, got %v", instanceType, err)
+	}
+
+	// The user should read and delete the source prefix, and write the new one.
+	policy := bucketPolicy{
+		Version: "2012-10-17",
+		Statements: []policyStatement{{
+			Actions:   set.CreateStringSet("s3:GetObject", "s3:DeleteObject"),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(bucketARNPrefix + bucketName + "/victim/*"),
+		}, {
+			Actions:   set.CreateStringSet("s3:PutObject"),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(bucketARNPrefix + bucketName + "/moved/*"),
+		}},
+	}
+	if err = globalIAMSys.SetPolicy(obj, "renamer", policy); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err = globalIAMSys.AttachPolicy(obj, accessKey, "renamer"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if code := rename(); code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the rename to succeed, got `%d`", instanceType, code)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, "moved/doc"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+}
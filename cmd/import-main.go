@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/lock"
+)
+
+var importFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "skip-md5",
+		Usage: "Do not read the files to generate ETags, imported objects are served without ETag.",
+	},
+}
+
+var importCmd = cli.Command{
+	Name:   "import",
+	Usage:  "Import an existing directory tree into a bucket of FS backend in-place.",
+	Flags:  append(importFlags, globalFlags...),
+	Action: mainImport,
+	CustomHelpTemplate: `NAME:
+  minio {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio {{.Name}} [FLAGS] PATH BUCKET [DIRECTORY]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Adopts the files under PATH/BUCKET as objects of BUCKET, generating their metadata. If
+  DIRECTORY is provided it is first moved to PATH/BUCKET, it must be on the same filesystem
+  and no data is copied. Last-Modified of the objects is the modification time of the files,
+  which is preserved. Files whose names are not valid object names, symbolic links and other
+  special files are skipped and reported.
+
+EXAMPLES:
+  1. Import "/mnt/nas/photos" as bucket "photos" of FS backend exported from "/mnt/nas".
+      $ minio {{.Name}} /mnt/nas photos /mnt/nas/photos-archive
+
+  2. Adopt files already present under "/home/shared/logs" without reading them.
+      $ minio {{.Name}} --skip-md5 /home/shared logs
+
+`,
+}
+
+// fsImportResult - outcome of importing a directory tree.
+type fsImportResult struct {
+	imported int      // Number of files with newly generated metadata.
+	existing int      // Number of files already having metadata.
+	skipped  []string // Files which can not be served as objects.
+}
+
+// fsImportTree - generates `fs.json` for all the files in the bucket
+// directory of the FS backend at fsPath. Data files are never modified.
+func fsImportTree(fsPath, bucket string, computeMD5 bool) (result fsImportResult, err error) {
+	bucketDir := pathJoin(fsPath, bucket)
+	err = filepath.Walk(bucketDir, func(filePath string, fi os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			result.skipped = append(result.skipped, fmt.Sprintf("%s: %s", filePath, walkErr))
+			if fi != nil && fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(bucketDir, filePath)
+		if err != nil {
+			return err
+		}
+		object := filepath.ToSlash(relPath)
+		if !fi.Mode().IsRegular() {
+			result.skipped = append(result.skipped, fmt.Sprintf("%s: not a regular file", filePath))
+			return nil
+		}
+		if !IsValidObjectName(object) {
+			result.skipped = append(result.skipped, fmt.Sprintf("%s: invalid object name", filePath))
+			return nil
+		}
+
+		fsMetaPath := pathJoin(fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+		if _, err = os.Stat(preparePath(fsMetaPath)); err == nil {
+			result.existing++
+			return nil
+		}
+		if !computeMD5 {
+			// Files without `fs.json` are served as they are.
+			result.imported++
+			return nil
+		}
+
+		md5Hex, err := fsFileMD5(filePath)
+		if err != nil {
+			result.skipped = append(result.skipped, fmt.Sprintf("%s: %s", filePath, err))
+			return nil
+		}
+		if err = fsWriteMeta(fsMetaPath, map[string]string{"md5Sum": md5Hex}); err != nil {
+			return err
+		}
+		result.imported++
+		return nil
+	})
+	return result, err
+}
+
+// fsFileMD5 - returns hex encoded md5sum of the file content.
+func fsFileMD5(filePath string) (string, error) {
+	f, err := os.Open(preparePath(filePath))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fsWriteMeta - writes a new `fs.json` with metadata at fsMetaPath.
+func fsWriteMeta(fsMetaPath string, metadata map[string]string) error {
+	if err := mkdirAll(filepath.Dir(fsMetaPath), 0777); err != nil {
+		return err
+	}
+	wlk, err := lock.LockedOpenFile(preparePath(fsMetaPath), os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	fsMeta.Meta = metadata
+	_, err = fsMeta.WriteTo(wlk)
+	return err
+}
+
+// fsImportDir - moves srcDir to the bucket directory of the FS backend
+// at fsPath, the bucket should not exist yet.
+func fsImportDir(fsPath, bucket, srcDir string) error {
+	bucketDir := pathJoin(fsPath, bucket)
+	if _, err := os.Stat(preparePath(bucketDir)); err == nil {
+		return fmt.Errorf("Bucket %s already exists", bucket)
+	}
+	fi, err := os.Stat(preparePath(srcDir))
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", srcDir)
+	}
+	// Rename fails with EXDEV across filesystems, data is never
+	// copied over.
+	return os.Rename(preparePath(srcDir), preparePath(bucketDir))
+}
+
+// mainImport handler called for 'minio import' command.
+func mainImport(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 || len(args) > 3 {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1)
+	}
+
+	// Initialization routine, such as config loading, enable logging, ..
+	minioInit(ctx)
+
+	fsPath, err := filepath.Abs(args[0])
+	fatalIf(err, "Unable to get absolute path of %s.", args[0])
+	bucket := args[1]
+	if !IsValidBucketName(bucket) || isMinioMetaBucketName(bucket) {
+		fatalIf(BucketNameInvalid{Bucket: bucket}, "Unable to import into bucket %s.", bucket)
+	}
+
+	format, err := loadFormatFS(fsPath)
+	if err != nil && err != errUnformattedDisk {
+		fatalIf(err, "Unable to load backend format of %s.", fsPath)
+	}
+	if err == nil && format.Format != "fs" {
+		fatalIf(errInvalidArgument, "Only FS backend supports import, %s is in %s format.", fsPath, format.Format)
+	}
+
+	if len(args) == 3 {
+		err = fsImportDir(fsPath, bucket, args[2])
+		fatalIf(err, "Unable to move %s to %s.", args[2], pathJoin(fsPath, bucket))
+	}
+
+	result, err := fsImportTree(fsPath, bucket, !ctx.Bool("skip-md5"))
+	fatalIf(err, "Unable to import %s.", pathJoin(fsPath, bucket))
+
+	for _, skipped := range result.skipped {
+		console.Println("Skipped " + skipped)
+	}
+	if !globalQuiet {
+		console.Printf("Imported %d object(s) into bucket %s, %d already present, %d skipped.\n",
+			result.imported, bucket, result.existing, len(result.skipped))
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Tests importing a directory tree into a bucket of FS backend.
+func TestFSImportTree(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	baseDir, err := ioutil.TempDir(globalTestTmpDir, "minio-import-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(baseDir)
+
+	fsDir := filepath.Join(baseDir, "export")
+	srcDir := filepath.Join(baseDir, "tree")
+	if err = os.MkdirAll(filepath.Join(srcDir, "dir", "subdir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(fsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello, world")
+	modTime := time.Date(2015, time.March, 1, 10, 0, 0, 0, time.UTC)
+	objects := []string{"file", "dir/file", "dir/subdir/file"}
+	for _, object := range objects {
+		filePath := filepath.Join(srcDir, filepath.FromSlash(object))
+		if err = ioutil.WriteFile(filePath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.Chtimes(filePath, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Invalid object name.
+	if err = ioutil.WriteFile(filepath.Join(srcDir, "dir", "file\\name"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectedSkipped := 1
+	if runtime.GOOS != globalWindowsOSName {
+		if err = os.Symlink(filepath.Join(srcDir, "file"), filepath.Join(srcDir, "link")); err != nil {
+			t.Fatal(err)
+		}
+		expectedSkipped++
+	}
+
+	bucket := "bucket"
+	if err = fsImportDir(fsDir, "bucket", filepath.Join(baseDir, "missing")); err == nil {
+		t.Fatal("Expected importing a missing directory to fail")
+	}
+	if err = fsImportDir(fsDir, bucket, srcDir); err != nil {
+		t.Fatal(err)
+	}
+	if err = fsImportDir(fsDir, bucket, srcDir); err == nil {
+		t.Fatal("Expected importing into an existing bucket to fail")
+	}
+
+	result, err := fsImportTree(fsDir, bucket, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.imported != len(objects) || result.existing != 0 || len(result.skipped) != expectedSkipped {
+		t.Fatalf("Unexpected import result %#v", result)
+	}
+
+	// Importing again leaves existing metadata in place.
+	result, err = fsImportTree(fsDir, bucket, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.imported != 0 || result.existing != len(objects) {
+		t.Fatalf("Unexpected import result %#v", result)
+	}
+
+	obj, err := newFSObjectLayer(fsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum(data)
+	expectedMD5 := hex.EncodeToString(sum[:])
+	for _, object := range objects {
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucket, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.MD5Sum != expectedMD5 {
+			t.Errorf("%s: Expected md5sum %s, got %s", object, expectedMD5, objInfo.MD5Sum)
+		}
+		if !objInfo.ModTime.Equal(modTime) {
+			t.Errorf("%s: Expected modification time %s, got %s", object, modTime, objInfo.ModTime)
+		}
+		if objInfo.Size != int64(len(data)) {
+			t.Errorf("%s: Expected size %d, got %d", object, len(data), objInfo.Size)
+		}
+	}
+}
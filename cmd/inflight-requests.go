@@ -0,0 +1,229 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errRequestKilled - the request was killed through the admin API.
+var errRequestKilled = errors.New("Request killed by the administrator")
+
+// InflightRequestInfo - an S3 API request being served.
+type InflightRequestInfo struct {
+	RequestID     string        `json:"requestId"`
+	API           string        `json:"api"`    // S3 API serving the request (GetObject, PutObject...)
+	Bucket        string        `json:"bucket"` // Bucket of the request, if any.
+	Object        string        `json:"object"` // Object of the request, if any.
+	AccessKey     string        `json:"accessKey,omitempty"`
+	SourceIP      string        `json:"sourceIP"`
+	Node          string        `json:"node"`          // Server serving the request.
+	Since         time.Time     `json:"since"`         // Time when the request was received.
+	Duration      time.Duration `json:"duration"`      // Duration since the request was received.
+	BytesSent     int64         `json:"bytesSent"`     // Bytes of the response sent so far.
+	BytesReceived int64         `json:"bytesReceived"` // Bytes of the request body received so far.
+}
+
+// inflightRequest - accounting of a request being served, which can be
+// killed.
+type inflightRequest struct {
+	info          InflightRequestInfo
+	bytesSent     int64 // Accessed atomically.
+	bytesReceived int64 // Accessed atomically.
+	cancel        context.CancelFunc
+
+	mutex sync.Mutex
+	// Set once the response has started.
+	responding bool
+	killed     bool
+	// Set when killed after the response started, the rest of the
+	// response is then not sent.
+	cutResponse bool
+}
+
+// isKilled - returns true if the request was killed, and the response
+// should be cut.
+func (req *inflightRequest) isKilled() (killed, cutResponse bool) {
+	req.mutex.Lock()
+	defer req.mutex.Unlock()
+	return req.killed, req.cutResponse
+}
+
+// startResponse - marks the response of the request as started.
+func (req *inflightRequest) startResponse() {
+	req.mutex.Lock()
+	req.responding = true
+	req.mutex.Unlock()
+}
+
+// kill - fails the rest of the reads of the request body and of the
+// writes of a started response, and cancels the context of the request.
+func (req *inflightRequest) kill() {
+	req.mutex.Lock()
+	req.killed = true
+	req.cutResponse = req.responding
+	req.mutex.Unlock()
+	req.cancel()
+}
+
+// inflightResponseWriter - counts the bytes of a response as they are
+// sent, failing once the request is killed.
+type inflightResponseWriter struct {
+	http.ResponseWriter
+	req *inflightRequest
+}
+
+func (w *inflightResponseWriter) WriteHeader(code int) {
+	w.req.startResponse()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *inflightResponseWriter) Write(p []byte) (int, error) {
+	if _, cutResponse := w.req.isKilled(); cutResponse {
+		return 0, errRequestKilled
+	}
+	w.req.startResponse()
+	n, err := w.ResponseWriter.Write(p)
+	atomic.AddInt64(&w.req.bytesSent, int64(n))
+	return n, err
+}
+
+// Flush - flushes the underlying response writer.
+func (w *inflightResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// inflightRequestBody - counts the bytes of a request body as they are
+// received, failing once the request is killed.
+type inflightRequestBody struct {
+	io.ReadCloser
+	req *inflightRequest
+}
+
+func (b *inflightRequestBody) Read(p []byte) (int, error) {
+	if killed, _ := b.req.isKilled(); killed {
+		return 0, errRequestKilled
+	}
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(&b.req.bytesReceived, int64(n))
+	return n, err
+}
+
+// inflightRequests - the S3 API requests being served by this server.
+type inflightRequests struct {
+	mutex    sync.Mutex
+	requests map[*inflightRequest]struct{}
+}
+
+// Requests being served by this server.
+var globalInflightRequests = &inflightRequests{requests: make(map[*inflightRequest]struct{})}
+
+// start - accounts the request until finished, returns w and r counting
+// the bytes transferred and failing once the request is killed.
+func (m *inflightRequests) start(w http.ResponseWriter, r *http.Request, info requestInfo) (http.ResponseWriter, *http.Request, *inflightRequest) {
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	req := &inflightRequest{
+		info: InflightRequestInfo{
+			RequestID: info.RequestID,
+			API:       info.API,
+			Bucket:    bucket,
+			Object:    object,
+			AccessKey: getRequestAccessKey(r),
+			SourceIP:  sourceIP,
+			Since:     time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+
+	m.mutex.Lock()
+	m.requests[req] = struct{}{}
+	m.mutex.Unlock()
+
+	r = r.WithContext(ctx)
+	if r.Body != nil {
+		r.Body = &inflightRequestBody{ReadCloser: r.Body, req: req}
+	}
+	return &inflightResponseWriter{ResponseWriter: w, req: req}, r, req
+}
+
+// finish - stops accounting the request once served.
+func (m *inflightRequests) finish(req *inflightRequest) {
+	m.mutex.Lock()
+	delete(m.requests, req)
+	m.mutex.Unlock()
+	req.cancel()
+}
+
+// byRequestAge - sorts requests by age, oldest first.
+type byRequestAge []InflightRequestInfo
+
+func (r byRequestAge) Len() int           { return len(r) }
+func (r byRequestAge) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byRequestAge) Less(i, j int) bool { return r[i].Since.Before(r[j].Since) }
+
+// List - returns the requests being served, oldest first.
+func (m *inflightRequests) List() []InflightRequestInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	requests := []InflightRequestInfo{}
+	for req := range m.requests {
+		info := req.info
+		info.Duration = time.Since(info.Since)
+		info.BytesSent = atomic.LoadInt64(&req.bytesSent)
+		info.BytesReceived = atomic.LoadInt64(&req.bytesReceived)
+		requests = append(requests, info)
+	}
+	sort.Sort(byRequestAge(requests))
+	return requests
+}
+
+// Count - returns the count of requests being served.
+func (m *inflightRequests) Count() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.requests)
+}
+
+// Kill - kills the requests of requestID being served, returns false
+// if there are none.
+func (m *inflightRequests) Kill(requestID string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	killed := false
+	for req := range m.requests {
+		if req.info.RequestID == requestID {
+			req.kill()
+			killed = true
+		}
+	}
+	return killed
+}
@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests that requests are accounted while served and fail once killed.
+func TestInflightRequests(t *testing.T) {
+	requests := &inflightRequests{requests: make(map[*inflightRequest]struct{})}
+
+	req, err := http.NewRequest("PUT", "http://localhost:9000/mybucket/myobject", bytes.NewReader(make([]byte, 1024)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "10.0.0.7:49152"
+	w, r, inflight := requests.start(httptest.NewRecorder(), req, requestInfo{API: "PutObject", RequestID: "REQ1"})
+
+	buf := make([]byte, 512)
+	if _, err = r.Body.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("started")); err != nil {
+		t.Fatal(err)
+	}
+
+	list := requests.List()
+	if len(list) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(list))
+	}
+	info := list[0]
+	if info.RequestID != "REQ1" || info.API != "PutObject" || info.Bucket != "mybucket" || info.Object != "myobject" ||
+		info.SourceIP != "10.0.0.7" || info.BytesReceived != 512 || info.BytesSent != 7 {
+		t.Fatalf("Unexpected request %#v", info)
+	}
+
+	if requests.Kill("REQ2") {
+		t.Fatal("Expected unknown request not to be killed")
+	}
+	if !requests.Kill("REQ1") {
+		t.Fatal("Expected request to be killed")
+	}
+	if _, err = r.Body.Read(buf); err != errRequestKilled {
+		t.Fatalf("Expected %v reading the body of a killed request, got %v", errRequestKilled, err)
+	}
+	if _, err = w.Write([]byte("rest")); err != errRequestKilled {
+		t.Fatalf("Expected %v writing the response of a killed request, got %v", errRequestKilled, err)
+	}
+	select {
+	case <-r.Context().Done():
+	default:
+		t.Fatal("Expected the context of a killed request to be canceled")
+	}
+
+	requests.finish(inflight)
+	if list = requests.List(); len(list) != 0 {
+		t.Fatalf("Expected no request once finished, got %v", list)
+	}
+}
+
+// Tests that a request killed before responding still gets its error
+// response.
+func TestInflightRequestKilledBeforeResponse(t *testing.T) {
+	requests := &inflightRequests{requests: make(map[*inflightRequest]struct{})}
+
+	req, err := http.NewRequest("PUT", "http://localhost:9000/mybucket/myobject", bytes.NewReader(make([]byte, 1024)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	w, r, inflight := requests.start(rec, req, requestInfo{API: "PutObject", RequestID: "REQ1"})
+	defer requests.finish(inflight)
+
+	requests.Kill("REQ1")
+	_, err = ioutil.ReadAll(r.Body)
+	if toAPIErrorCode(traceError(err)) != ErrRequestKilled {
+		t.Fatalf("Expected reading the body of a killed request to fail with %v, got %v", errRequestKilled, err)
+	}
+	writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+	if rec.Code != http.StatusForbidden || !bytes.Contains(rec.Body.Bytes(), []byte("XMinioRequestKilled")) {
+		t.Fatalf("Unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -45,7 +45,9 @@ var errInvalidAccessKeyID = errors.New("The access key ID you provided does not
 var errAuthentication = errors.New("Authentication failed, check your access credentials")
 var errNoAuthToken = errors.New("JWT token missing")
 
-func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string, error) {
+// authenticateJWT - returns a token for accessKey, once its credential
+// returned by lookup is verified to have secretKey.
+func authenticateJWT(accessKey, secretKey string, expiry time.Duration, lookup func(string) (credential, bool)) (string, error) {
 	// Trim spaces.
 	accessKey = strings.TrimSpace(accessKey)
 
@@ -56,20 +58,22 @@ func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string,
 		return "", errInvalidSecretKeyLength
 	}
 
-	serverCred := serverConfig.GetCredential()
-
 	// Validate access key.
-	if accessKey != serverCred.AccessKey {
+	cred, ok := lookup(accessKey)
+	if !ok {
 		return "", errInvalidAccessKeyID
 	}
 
 	// Validate secret key.
 	// Using bcrypt to avoid timing attacks.
-	hashedSecretKey, _ := bcrypt.GenerateFromPassword([]byte(serverCred.SecretKey), bcrypt.DefaultCost)
+	hashedSecretKey, _ := bcrypt.GenerateFromPassword([]byte(cred.SecretKey), bcrypt.DefaultCost)
 	if bcrypt.CompareHashAndPassword(hashedSecretKey, []byte(secretKey)) != nil {
 		return "", errAuthentication
 	}
 
+	// Tokens are all signed by the server credential.
+	serverCred := serverConfig.GetCredential()
+
 	utcNow := time.Now().UTC()
 	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
 		"exp": utcNow.Add(expiry).Unix(),
@@ -80,12 +84,33 @@ func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string,
 	return token.SignedString([]byte(serverCred.SecretKey))
 }
 
+// lookupServerCredential - returns the server credential if accessKey
+// is its access key.
+func lookupServerCredential(accessKey string) (credential, bool) {
+	cred := serverConfig.GetCredential()
+	return cred, accessKey == cred.AccessKey
+}
+
+// lookupWebCredential - returns the credential of accessKey, the server
+// credential or the credential of an enabled IAM user.
+func lookupWebCredential(accessKey string) (credential, bool) {
+	cred, apiErr := lookupCredential(accessKey)
+	return cred, apiErr == ErrNone
+}
+
 func authenticateNode(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultInterNodeJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, defaultInterNodeJWTExpiry, lookupServerCredential)
 }
 
 func authenticateWeb(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, defaultJWTExpiry, lookupServerCredential)
+}
+
+// authenticateWebUser - returns a browser token for the server
+// credential or for an IAM user, whose browser requests are limited
+// by its policies.
+func authenticateWebUser(accessKey, secretKey string) (string, error) {
+	return authenticateJWT(accessKey, secretKey, defaultJWTExpiry, lookupWebCredential)
 }
 
 func keyFuncCallback(jwtToken *jwtgo.Token) (interface{}, error) {
@@ -96,34 +121,71 @@ func keyFuncCallback(jwtToken *jwtgo.Token) (interface{}, error) {
 	return []byte(serverConfig.GetCredential().SecretKey), nil
 }
 
+// getTokenAccessKey - returns the access key jwtToken was issued for,
+// errAuthentication if the token is invalid or if its access key is
+// no longer the server credential nor an enabled IAM user.
+func getTokenAccessKey(jwtToken *jwtgo.Token) (string, error) {
+	if !jwtToken.Valid {
+		return "", errAuthentication
+	}
+	claims, ok := jwtToken.Claims.(jwtgo.MapClaims)
+	if !ok {
+		return "", errAuthentication
+	}
+	accessKey, _ := claims["sub"].(string)
+	if _, ok = lookupWebCredential(accessKey); !ok {
+		return "", errAuthentication
+	}
+	return accessKey, nil
+}
+
+// isAuthTokenValid - returns true if tokenString is a valid token of
+// the server credential, the only one authenticating the servers.
 func isAuthTokenValid(tokenString string) bool {
+	accessKey, err := getWebTokenAccessKey(tokenString)
+	return err == nil && accessKey == serverConfig.GetCredential().AccessKey
+}
+
+// getWebTokenAccessKey - returns the access key of the valid token
+// tokenString.
+func getWebTokenAccessKey(tokenString string) (string, error) {
 	jwtToken, err := jwtgo.Parse(tokenString, keyFuncCallback)
 	if err != nil {
 		errorIf(err, "Unable to parse JWT token string")
-		return false
+		return "", errAuthentication
 	}
-
-	return jwtToken.Valid
+	return getTokenAccessKey(jwtToken)
 }
 
+// isHTTPRequestValid - returns true if the browser request is
+// authenticated by the server credential. Browser requests of IAM
+// users are authorized by their policies, see isWebActionAllowed.
 func isHTTPRequestValid(req *http.Request) bool {
-	return webReqestAuthenticate(req) == nil
+	accessKey, err := webReqestAuthenticate(req)
+	return err == nil && accessKey == serverConfig.GetCredential().AccessKey
 }
 
 // Check if the request is authenticated.
-// Returns nil if the request is authenticated. errNoAuthToken if token missing.
+// Returns the access key of the request if it is authenticated. errNoAuthToken if token missing.
 // Returns errAuthentication for all other errors.
-func webReqestAuthenticate(req *http.Request) error {
+func webReqestAuthenticate(req *http.Request) (string, error) {
 	jwtToken, err := jwtreq.ParseFromRequest(req, jwtreq.AuthorizationHeaderExtractor, keyFuncCallback)
 	if err != nil {
 		if err == jwtreq.ErrNoTokenInRequest {
-			return errNoAuthToken
+			return "", errNoAuthToken
 		}
-		return errAuthentication
+		return "", errAuthentication
 	}
+	return getTokenAccessKey(jwtToken)
+}
 
-	if !jwtToken.Valid {
-		return errAuthentication
+// isWebActionAllowed - returns the access key of the authenticated
+// browser request req, true if allowed action on bucket/object by the
+// policies of its user, always allowed for the server credential.
+func isWebActionAllowed(req *http.Request, action, bucket, object string) (string, bool) {
+	accessKey, err := webReqestAuthenticate(req)
+	if err != nil {
+		return "", false
 	}
-	return nil
+	return accessKey, isIAMActionAllowed(accessKey, action, bucketARNPrefix+pathJoin(bucket, object), nil)
 }
@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variable used to hand over listening sockets to a
+// restarted process. It holds a comma separated list of listener
+// keys, the n'th key describes the inherited file descriptor 3+n.
+const minioInheritedListenersEnv = "_MINIO_INHERITED_LISTENERS"
+
+// First file descriptor passed via exec.Cmd.ExtraFiles.
+const inheritedListenersStartFD = 3
+
+var (
+	inheritedListenersMu   sync.Mutex
+	inheritedListenersOnce sync.Once
+	// Listening sockets handed over by the parent process, keyed
+	// by listenerKey(), consumed by listen().
+	inheritedListeners = make(map[string]net.Listener)
+)
+
+// listenerKey - uniquely identifies a listener by its network and the
+// address as requested on the command line, which stays the same
+// across restarts.
+func listenerKey(network, addr string) string {
+	return network + "://" + addr
+}
+
+// loadInheritedListeners - converts the file descriptors inherited from
+// the parent process into listeners.
+func loadInheritedListeners() {
+	keys := os.Getenv(minioInheritedListenersEnv)
+	if keys == "" {
+		return
+	}
+	// Do not leak the environment to any process started later.
+	os.Unsetenv(minioInheritedListenersEnv)
+
+	for i, key := range strings.Split(keys, ",") {
+		f := os.NewFile(uintptr(inheritedListenersStartFD+i), key)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			errorIf(err, "Unable to use inherited listener %s.", key)
+			continue
+		}
+		inheritedListeners[key] = l
+	}
+}
+
+// isInheritedPort - returns true if a TCP listener on the given port was
+// handed over by the parent process.
+func isInheritedPort(port string) bool {
+	inheritedListenersMu.Lock()
+	defer inheritedListenersMu.Unlock()
+	inheritedListenersOnce.Do(loadInheritedListeners)
+
+	for key := range inheritedListeners {
+		if !strings.HasPrefix(key, "tcp://") {
+			continue
+		}
+		_, p, err := net.SplitHostPort(strings.TrimPrefix(key, "tcp://"))
+		if err == nil && p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// listen - returns the listener handed over by the parent process for
+// network and addr if any, otherwise announces on the local address.
+func listen(network, addr string) (net.Listener, error) {
+	inheritedListenersMu.Lock()
+	inheritedListenersOnce.Do(loadInheritedListeners)
+	key := listenerKey(network, addr)
+	l, ok := inheritedListeners[key]
+	delete(inheritedListeners, key)
+	inheritedListenersMu.Unlock()
+	if ok {
+		return l, nil
+	}
+
+	if network == "unix" {
+		// Remove any stale socket left behind by a previous run,
+		// regular files are never removed.
+		if st, err := os.Lstat(addr); err == nil && st.Mode()&os.ModeSocket != 0 {
+			if err = os.Remove(addr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return net.Listen(network, addr)
+}
+
+// listenerFiler is implemented by listeners which can be duplicated as
+// a file, i.e *net.TCPListener and *net.UnixListener.
+type listenerFiler interface {
+	File() (*os.File, error)
+}
+
+// listenerFiles - duplicates all the active listening sockets for
+// handing them over to a new process, along with the value for
+// minioInheritedListenersEnv describing them.
+func (m *ServerMux) listenerFiles() (files []*os.File, env string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for _, l := range m.listeners {
+		filer, ok := l.Listener.(listenerFiler)
+		if !ok || l.key == "" {
+			continue
+		}
+		var f *os.File
+		if f, err = filer.File(); err != nil {
+			for _, f = range files {
+				f.Close()
+			}
+			return nil, "", err
+		}
+		// The socket file is now shared with the new process,
+		// it should outlive this listener.
+		if ul, ok := l.Listener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+		files = append(files, f)
+		keys = append(keys, l.key)
+	}
+	return files, minioInheritedListenersEnv + "=" + strings.Join(keys, ","), nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"net"
+	"runtime"
+	"testing"
+)
+
+// Tests duplicating listeners to be handed over on restart.
+func TestListenerFiles(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip("listening sockets can not be handed over on windows")
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	listeners, err := initListeners(addr, &tls.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewServerMux(addr, nil)
+	m.listeners = listeners
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	files, env, err := m.listenerFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(files))
+	}
+	if expected := minioInheritedListenersEnv + "=" + listenerKey("tcp", addr); env != expected {
+		t.Fatalf("Expected %s, got %s", expected, env)
+	}
+
+	// The duplicated file should be usable as a listener.
+	l, err := net.FileListener(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+}
+
+// Tests that inherited listeners are used instead of announcing again.
+func TestListenInherited(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	inheritedListenersOnce.Do(loadInheritedListeners)
+	inheritedListenersMu.Lock()
+	inheritedListeners[listenerKey("tcp", l.Addr().String())] = l
+	inheritedListenersMu.Unlock()
+
+	if !isInheritedPort(port) {
+		t.Fatalf("Expected port %s to be inherited", port)
+	}
+	// Port is in use by this process, yet available for the server.
+	if err = checkPortAvailability(port); err != nil {
+		t.Fatal(err)
+	}
+
+	nl, err := listen("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nl != l {
+		t.Fatal("Expected inherited listener to be returned")
+	}
+	// Inherited listeners are consumed only once.
+	if isInheritedPort(port) {
+		t.Fatalf("Expected port %s to be consumed", port)
+	}
+}
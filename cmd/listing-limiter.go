@@ -0,0 +1,155 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Environment variable setting the maximum number of concurrent
+// directory listings of the tree walks, "off" removes the limit.
+const listConcurrencyEnv = "MINIO_LIST_CONCURRENCY"
+
+// At most 32 directory listings run at a time by default.
+const defaultListConcurrency = 32
+
+// listingLimiter - bounds the number of directory listings run at a
+// time by all the tree walks, so that expensive listings do not starve
+// the other requests. A bucket gets at most half of the listings, so
+// that the listings of other buckets still make progress.
+type listingLimiter struct {
+	mutex *sync.Mutex
+	cond  *sync.Cond
+
+	maxListings          int
+	maxListingsPerBucket int
+
+	active        int
+	bucketsActive map[string]int
+	queued        int
+	bucketsQueued map[string]int
+}
+
+// newListingLimiter - returns a limiter of maxListings listings at a
+// time, zero for unlimited.
+func newListingLimiter(maxListings int) *listingLimiter {
+	mutex := &sync.Mutex{}
+	return &listingLimiter{
+		mutex:                mutex,
+		cond:                 sync.NewCond(mutex),
+		maxListings:          maxListings,
+		maxListingsPerBucket: (maxListings + 1) / 2,
+		bucketsActive:        make(map[string]int),
+		bucketsQueued:        make(map[string]int),
+	}
+}
+
+// Global listing limiter.
+var globalListingLimiter = newListingLimiter(defaultListConcurrency)
+
+// getListConcurrency - returns the maximum number of concurrent
+// directory listings configured in the environment.
+func getListConcurrency() (int, error) {
+	value := os.Getenv(listConcurrencyEnv)
+	switch value {
+	case "":
+		return defaultListConcurrency, nil
+	case "off":
+		return 0, nil
+	}
+	maxListings, err := strconv.Atoi(value)
+	if err != nil || maxListings <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be a positive number or off", listConcurrencyEnv, value)
+	}
+	return maxListings, nil
+}
+
+// Acquire - blocks until a directory listing of bucket may run.
+func (l *listingLimiter) Acquire(bucket string) {
+	if l.maxListings == 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.active >= l.maxListings || l.bucketsActive[bucket] >= l.maxListingsPerBucket {
+		l.queued++
+		l.bucketsQueued[bucket]++
+		for l.active >= l.maxListings || l.bucketsActive[bucket] >= l.maxListingsPerBucket {
+			l.cond.Wait()
+		}
+		l.queued--
+		if l.bucketsQueued[bucket]--; l.bucketsQueued[bucket] == 0 {
+			delete(l.bucketsQueued, bucket)
+		}
+	}
+	l.active++
+	l.bucketsActive[bucket]++
+}
+
+// Release - ends a directory listing of bucket.
+func (l *listingLimiter) Release(bucket string) {
+	if l.maxListings == 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.active--
+	if l.bucketsActive[bucket]--; l.bucketsActive[bucket] == 0 {
+		delete(l.bucketsActive, bucket)
+	}
+	// Waiters are listing different buckets, wake them all.
+	l.cond.Broadcast()
+}
+
+// bucketListingInfo - directory listings of a bucket.
+type bucketListingInfo struct {
+	Active int `json:"active"`
+	Queued int `json:"queued"`
+}
+
+// listingInfo - limits and current directory listings of a server.
+type listingInfo struct {
+	MaxListings          int                          `json:"maxListings"`
+	MaxListingsPerBucket int                          `json:"maxListingsPerBucket"`
+	Active               int                          `json:"active"`
+	Queued               int                          `json:"queued"`
+	Buckets              map[string]bucketListingInfo `json:"buckets,omitempty"`
+}
+
+// Info - returns the limits and the directory listings running and
+// waiting to run, per bucket.
+func (l *listingLimiter) Info() listingInfo {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	info := listingInfo{
+		MaxListings:          l.maxListings,
+		MaxListingsPerBucket: l.maxListingsPerBucket,
+		Active:               l.active,
+		Queued:               l.queued,
+		Buckets:              make(map[string]bucketListingInfo),
+	}
+	for bucket, active := range l.bucketsActive {
+		info.Buckets[bucket] = bucketListingInfo{Active: active, Queued: l.bucketsQueued[bucket]}
+	}
+	for bucket, queued := range l.bucketsQueued {
+		info.Buckets[bucket] = bucketListingInfo{Active: l.bucketsActive[bucket], Queued: queued}
+	}
+	return info
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the listing concurrency from the environment.
+func TestGetListConcurrency(t *testing.T) {
+	defer os.Unsetenv(listConcurrencyEnv)
+
+	testCases := []struct {
+		value               string
+		expectedMaxListings int
+		expectErr           bool
+	}{
+		{"", defaultListConcurrency, false},
+		{"off", 0, false},
+		{"8", 8, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"many", 0, true},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(listConcurrencyEnv, testCase.value)
+		maxListings, err := getListConcurrency()
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+		if maxListings != testCase.expectedMaxListings {
+			t.Errorf("Test %d: Expected %d listings, got %d", i+1, testCase.expectedMaxListings, maxListings)
+		}
+	}
+}
+
+// Tests the global and per bucket bounds of the listing limiter.
+func TestListingLimiter(t *testing.T) {
+	limiter := newListingLimiter(4)
+	if limiter.maxListingsPerBucket != 2 {
+		t.Fatalf("Expected 2 listings per bucket, got %d", limiter.maxListingsPerBucket)
+	}
+
+	limiter.Acquire("bucket-a")
+	limiter.Acquire("bucket-a")
+
+	// A third listing of bucket-a waits for one of bucket-a to end.
+	acquiredCh := make(chan struct{})
+	go func() {
+		limiter.Acquire("bucket-a")
+		close(acquiredCh)
+	}()
+	for limiter.Info().Queued != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Other buckets still get listings.
+	limiter.Acquire("bucket-b")
+	limiter.Acquire("bucket-b")
+	info := limiter.Info()
+	if info.Active != 4 || info.Buckets["bucket-a"].Queued != 1 || info.Buckets["bucket-b"].Active != 2 {
+		t.Fatalf("Unexpected listing info %#v", info)
+	}
+
+	// Ending a listing of bucket-b frees a global slot only.
+	limiter.Release("bucket-b")
+	select {
+	case <-acquiredCh:
+		t.Fatal("Listing of bucket-a should still wait")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	limiter.Release("bucket-a")
+	<-acquiredCh
+
+	limiter.Release("bucket-a")
+	limiter.Release("bucket-a")
+	limiter.Release("bucket-b")
+	info = limiter.Info()
+	if info.Active != 0 || info.Queued != 0 || len(info.Buckets) != 0 {
+		t.Fatalf("Expected no listings, got %#v", info)
+	}
+
+	// An unlimited limiter never blocks.
+	limiter = newListingLimiter(0)
+	for i := 0; i < 10; i++ {
+		limiter.Acquire("bucket-a")
+	}
+	if info = limiter.Info(); info.Active != 0 {
+		t.Fatalf("Expected unlimited listings not to be counted, got %#v", info)
+	}
+}
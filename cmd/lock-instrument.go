@@ -47,6 +47,8 @@ type debugLockInfo struct {
 	status statusType
 	// Time of last status update.
 	since time.Time
+	// S3 API and ID of the request holding the lock, if any.
+	origin requestInfo
 }
 
 // debugLockInfoPerVolumePath - lock state information on all locks held on (volume, path).
@@ -136,7 +138,10 @@ func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID stri
 		return traceError(LockInfoStateNotBlocked{param.volume, param.path, opsID})
 	}
 	// Change lock status to running and update the time.
-	n.debugLockMap[param].lockInfo[opsID] = newDebugLockInfo(lockSource, runningStatus, readLock)
+	runningInfo := newDebugLockInfo(lockSource, runningStatus, readLock)
+	runningInfo.origin = lockInfo.origin
+	n.debugLockMap[param].lockInfo[opsID] = runningInfo
+	n.metrics.lockAcquired(readLock, time.Since(lockInfo.since))
 
 	// Update global lock stats.
 	n.counters.lockGranted()
@@ -178,6 +183,16 @@ func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID string,
 	return nil
 }
 
+// setLockOrigin - tags the lock of opsID with the request taking it.
+func (n *nsLockMap) setLockOrigin(param nsParam, opsID string, origin requestInfo) {
+	if debugLock, ok := n.debugLockMap[param]; ok {
+		if lockInfo, ok := debugLock.lockInfo[opsID]; ok {
+			lockInfo.origin = origin
+			debugLock.lockInfo[opsID] = lockInfo
+		}
+	}
+}
+
 // deleteLockInfoEntry - Deletes the lock information for given (volume, path).
 // Called when nsLk.ref count is 0.
 func (n *nsLockMap) deleteLockInfoEntryForVolumePath(param nsParam) error {
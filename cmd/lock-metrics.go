@@ -0,0 +1,138 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Upper bounds in seconds of the lock acquisition latency buckets.
+var lockAcquireBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60}
+
+// latencyHistogram - cumulative histogram of latencies, as exported
+// to Prometheus.
+type latencyHistogram struct {
+	counts []uint64 // Count of latencies lower or equal to each bucket.
+	count  uint64
+	sum    float64
+}
+
+// observe - adds latency to the histogram.
+func (h *latencyHistogram) observe(latency time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(lockAcquireBuckets))
+	}
+	seconds := latency.Seconds()
+	for i, le := range lockAcquireBuckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// lockMetrics - lock instrumentation not kept by the lock info of the
+// locks currently held.
+type lockMetrics struct {
+	readAcquire  latencyHistogram
+	writeAcquire latencyHistogram
+	forceUnlocks uint64
+}
+
+// lockAcquired - updates lock metrics when a lock is granted after
+// waiting for it for latency.
+func (m *lockMetrics) lockAcquired(readLock bool, latency time.Duration) {
+	if readLock {
+		m.readAcquire.observe(latency)
+	} else {
+		m.writeAcquire.observe(latency)
+	}
+}
+
+// countLocks - returns the read and write locks held and the locks
+// waited for in n, n.lockMapMutex being held.
+func countLocks(n *nsLockMap) (readLocks, writeLocks, blockedLocks int) {
+	for _, debugLock := range n.debugLockMap {
+		for _, lockInfo := range debugLock.lockInfo {
+			switch {
+			case lockInfo.status == blockedStatus:
+				blockedLocks++
+			case lockInfo.lType == debugRLockStr:
+				readLocks++
+			default:
+				writeLocks++
+			}
+		}
+	}
+	return readLocks, writeLocks, blockedLocks
+}
+
+// writeLockMetrics - writes the lock instrumentation of n in the
+// Prometheus text exposition format.
+func writeLockMetrics(w io.Writer, n *nsLockMap) error {
+	n.lockMapMutex.Lock()
+	readLocks, writeLocks, blockedLocks := countLocks(n)
+	metrics := n.metrics
+	metrics.readAcquire.counts = append([]uint64(nil), n.metrics.readAcquire.counts...)
+	metrics.writeAcquire.counts = append([]uint64(nil), n.metrics.writeAcquire.counts...)
+	n.lockMapMutex.Unlock()
+
+	_, err := fmt.Fprintf(w, `# HELP minio_locks_held Number of namespace locks held.
+# TYPE minio_locks_held gauge
+minio_locks_held{type="read"} %d
+minio_locks_held{type="write"} %d
+# HELP minio_locks_blocked Number of operations blocked waiting for a namespace lock.
+# TYPE minio_locks_blocked gauge
+minio_locks_blocked %d
+# HELP minio_lock_force_unlocks_total Number of namespace locks forcefully unlocked.
+# TYPE minio_lock_force_unlocks_total counter
+minio_lock_force_unlocks_total %d
+# HELP minio_lock_acquire_seconds Time waited to acquire namespace locks.
+# TYPE minio_lock_acquire_seconds histogram
+`, readLocks, writeLocks, blockedLocks, metrics.forceUnlocks)
+	if err != nil {
+		return err
+	}
+	if err = writeHistogram(w, "minio_lock_acquire_seconds", `type="read"`, metrics.readAcquire); err != nil {
+		return err
+	}
+	return writeHistogram(w, "minio_lock_acquire_seconds", `type="write"`, metrics.writeAcquire)
+}
+
+// writeHistogram - writes the samples of histogram h named name with
+// labels, in the Prometheus text exposition format.
+func writeHistogram(w io.Writer, name, labels string, h latencyHistogram) error {
+	for i, le := range lockAcquireBuckets {
+		var count uint64
+		if h.counts != nil {
+			count = h.counts[i]
+		}
+		le := strconv.FormatFloat(le, 'g', -1, 64)
+		if _, err := fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, le, count); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n%s_sum{%s} %s\n%s_count{%s} %d\n",
+		name, labels, h.count,
+		name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64),
+		name, labels, h.count)
+	return err
+}
@@ -0,0 +1,76 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests lock acquisition latency histograms.
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	h.observe(2 * time.Millisecond)
+	h.observe(2 * time.Minute)
+	if h.count != 2 || h.counts[0] != 0 || h.counts[1] != 1 || h.counts[len(lockAcquireBuckets)-1] != 1 {
+		t.Fatalf("Unexpected histogram %#v", h)
+	}
+}
+
+// Tests exporting lock instrumentation in Prometheus format.
+func TestMetricsHandler(t *testing.T) {
+	initNSLock(false)
+	defer initNSLock(false)
+
+	readLock := globalNSMutex.NewNSLock(context.Background(), "bucket", "read")
+	readLock.RLock()
+	defer readLock.RUnlock()
+	writeLock := globalNSMutex.NewNSLock(context.Background(), "bucket", "write")
+	writeLock.Lock()
+	globalNSMutex.ForceUnlock("bucket", "write")
+
+	mux := router.NewRouter()
+	registerMetricsRouter(mux)
+	req, err := newTestRequest("GET", reservedBucket+metricsPath, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	for _, line := range []string{
+		`minio_locks_held{type="read"} 1`,
+		`minio_locks_held{type="write"} 0`,
+		`minio_locks_blocked 0`,
+		`minio_lock_force_unlocks_total 1`,
+		`minio_lock_acquire_seconds_bucket{type="read",le="+Inf"} 1`,
+		`minio_lock_acquire_seconds_count{type="write"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got\n%s", line, rec.Body.String())
+		}
+	}
+}
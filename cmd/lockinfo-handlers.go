@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"sort"
 	"strings"
 	"time"
 )
@@ -60,12 +61,14 @@ type VolumeLockInfo struct {
 // OpsLockState - structure to fill in state information of the lock.
 // structure to fill in status information for each operation with given operation ID.
 type OpsLockState struct {
-	OperationID string        `json:"id"`       // String containing operation ID.
-	LockSource  string        `json:"source"`   // Operation type (GetObject, PutObject...)
-	LockType    lockType      `json:"type"`     // Lock type (RLock, WLock)
-	Status      statusType    `json:"status"`   // Status can be Running/Ready/Blocked.
-	Since       time.Time     `json:"since"`    // Time when the lock was initially held.
-	Duration    time.Duration `json:"duration"` // Duration since the lock was held.
+	OperationID string        `json:"id"`        // String containing operation ID.
+	LockSource  string        `json:"source"`    // Source line taking the lock.
+	API         string        `json:"api"`       // S3 API taking the lock (GetObject, PutObject...)
+	RequestID   string        `json:"requestId"` // ID of the request taking the lock.
+	LockType    lockType      `json:"type"`      // Lock type (RLock, WLock)
+	Status      statusType    `json:"status"`    // Status can be Running/Ready/Blocked.
+	Since       time.Time     `json:"since"`     // Time when the lock was initially held.
+	Duration    time.Duration `json:"duration"`  // Duration since the lock was held.
 }
 
 // listLocksInfo - Fetches locks held on bucket, matching prefix older than relTime.
@@ -104,6 +107,8 @@ func listLocksInfo(bucket, prefix string, relTime time.Duration) []VolumeLockInf
 				OpsLockState{
 					OperationID: opsID,
 					LockSource:  lockInfo.lockSource,
+					API:         lockInfo.origin.API,
+					RequestID:   lockInfo.origin.RequestID,
 					LockType:    lockInfo.lType,
 					Status:      lockInfo.status,
 					Since:       lockInfo.since,
@@ -114,3 +119,63 @@ func listLocksInfo(bucket, prefix string, relTime time.Duration) []VolumeLockInf
 	}
 	return volumeLocks
 }
+
+// TopLockInfo - state of a lock held on a <volume,path> pair, along
+// with the server holding it.
+type TopLockInfo struct {
+	Bucket      string        `json:"bucket"`
+	Object      string        `json:"object"`
+	LockType    lockType      `json:"type"`      // Lock type (RLock, WLock)
+	Node        string        `json:"node"`      // Server holding the lock.
+	OperationID string        `json:"id"`        // String containing operation ID.
+	LockSource  string        `json:"source"`    // Source line taking the lock.
+	API         string        `json:"api"`       // S3 API taking the lock (GetObject, PutObject...)
+	RequestID   string        `json:"requestId"` // ID of the request taking the lock.
+	Since       time.Time     `json:"since"`     // Time when the lock was held.
+	Duration    time.Duration `json:"duration"`  // Duration since the lock was held.
+}
+
+// byLockAge - sorts locks by age, oldest first.
+type byLockAge []TopLockInfo
+
+func (l byLockAge) Len() int           { return len(l) }
+func (l byLockAge) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byLockAge) Less(i, j int) bool { return l[i].Since.Before(l[j].Since) }
+
+// oldestLocks - returns the count oldest locks of locks.
+func oldestLocks(locks []TopLockInfo, count int) []TopLockInfo {
+	sort.Sort(byLockAge(locks))
+	if len(locks) > count {
+		locks = locks[:count]
+	}
+	return locks
+}
+
+// listTopLocksInfo - Fetches the count oldest locks held on all buckets,
+// locks operations are blocked on are not held yet.
+func listTopLocksInfo(count int) []TopLockInfo {
+	globalNSMutex.lockMapMutex.Lock()
+	defer globalNSMutex.lockMapMutex.Unlock()
+
+	timeNow := time.Now().UTC()
+	topLocks := []TopLockInfo{}
+	for param, debugLock := range globalNSMutex.debugLockMap {
+		for opsID, lockInfo := range debugLock.lockInfo {
+			if lockInfo.status != runningStatus {
+				continue
+			}
+			topLocks = append(topLocks, TopLockInfo{
+				Bucket:      param.volume,
+				Object:      param.path,
+				LockType:    lockInfo.lType,
+				OperationID: opsID,
+				LockSource:  lockInfo.lockSource,
+				API:         lockInfo.origin.API,
+				RequestID:   lockInfo.origin.RequestID,
+				Since:       lockInfo.since,
+				Duration:    timeNow.Sub(lockInfo.since),
+			})
+		}
+	}
+	return oldestLocks(topLocks, count)
+}
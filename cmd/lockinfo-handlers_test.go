@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -32,13 +33,13 @@ func TestListLocksInfo(t *testing.T) {
 	// Acquire a few locks to populate lock instrumentation.
 	// Take 10 read locks on bucket1/prefix1/obj1
 	for i := 0; i < 10; i++ {
-		readLk := globalNSMutex.NewNSLock("bucket1", "prefix1/obj1")
+		readLk := globalNSMutex.NewNSLock(context.Background(), "bucket1", "prefix1/obj1")
 		readLk.RLock()
 	}
 
 	// Take write locks on bucket1/prefix/obj{11..19}
 	for i := 0; i < 10; i++ {
-		wrLk := globalNSMutex.NewNSLock("bucket1", fmt.Sprintf("prefix1/obj%d", 10+i))
+		wrLk := globalNSMutex.NewNSLock(context.Background(), "bucket1", fmt.Sprintf("prefix1/obj%d", 10+i))
 		wrLk.Lock()
 	}
 
@@ -79,3 +80,26 @@ func TestListLocksInfo(t *testing.T) {
 		}
 	}
 }
+
+// Tests tagging locks with the request taking them.
+func TestListLocksInfoOrigin(t *testing.T) {
+	initNSLock(false)
+	defer initNSLock(false)
+
+	origin := requestInfo{API: "PutObject", RequestID: "14B01B8E0F0C4E1A"}
+	objectLock := globalNSMutex.NewNSLock(contextWithRequestInfo(context.Background(), origin), "bucket", "object")
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	volLocks := listLocksInfo("bucket", "object", 0)
+	if len(volLocks) != 1 || len(volLocks[0].LockDetailsOnObject) != 1 {
+		t.Fatalf("Expected a single lock, got %#v", volLocks)
+	}
+	lockState := volLocks[0].LockDetailsOnObject[0]
+	if lockState.API != origin.API || lockState.RequestID != origin.RequestID || lockState.Status != runningStatus {
+		t.Errorf("Expected lock taken by %#v, got %#v", origin, lockState)
+	}
+	if topLocks := listTopLocksInfo(1); len(topLocks) != 1 || topLocks[0].API != origin.API {
+		t.Errorf("Expected top lock taken by %#v, got %#v", origin, topLocks)
+	}
+}
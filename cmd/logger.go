@@ -44,6 +44,25 @@ type logger struct {
 	// Add new loggers here.
 }
 
+// disableLoggers - unregisters all the loggers, closing their files.
+func disableLoggers() {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	for _, logger := range log.loggers {
+		closed := make(map[*localFile]bool)
+		for _, hooks := range logger.Hooks {
+			for _, hook := range hooks {
+				// The same hook is registered for multiple levels.
+				if file, ok := hook.(*localFile); ok && !closed[file] {
+					closed[file] = true
+					file.Close()
+				}
+			}
+		}
+	}
+	log.loggers = nil
+}
+
 // Get file, line, function name of the caller.
 func callerSource() string {
 	pc, file, line, success := runtime.Caller(2)
@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"sort"
 	"time"
 
@@ -72,6 +73,11 @@ func init() {
 }
 
 func migrate() {
+	// Config in etcd is always saved in the latest version.
+	if globalEtcdClient != nil {
+		return
+	}
+
 	// Migrate config file
 	err := migrateConfig()
 	fatalIf(err, "Config migration failed.")
@@ -112,6 +118,12 @@ func registerApp() *cli.App {
 	registerCommand(serverCmd)
 	registerCommand(versionCmd)
 	registerCommand(updateCmd)
+	registerCommand(verifyCmd)
+	registerCommand(migrateCmd)
+	registerCommand(importCmd)
+	if runtime.GOOS == globalWindowsOSName {
+		registerCommand(serviceCmd)
+	}
 
 	// Set up app.
 	app := cli.NewApp()
@@ -174,6 +186,17 @@ func minioInit(ctx *cli.Context) {
 	// Is TLS configured?.
 	globalIsSSL = isSSL()
 
+	// Is the config stored in etcd?.
+	if endpoints := os.Getenv(etcdEndpointsEnv); endpoints != "" {
+		// Root CAs verify etcd servers over TLS.
+		loadRootCAs()
+		var err error
+		globalEtcdClient, err = newEtcdClient(endpoints)
+		if err != nil {
+			console.Fatalf("Unable to initialize etcd client. Err: %s.\n", err)
+		}
+	}
+
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
@@ -182,10 +205,20 @@ func minioInit(ctx *cli.Context) {
 	if err != nil {
 		console.Fatalf("Unable to initialize minio config. Err: %s.\n", err)
 	}
-	if configCreated {
+	if configCreated && globalEtcdClient != nil {
+		console.Println("Created minio configuration in etcd at " + globalEtcdClient.String())
+	} else if configCreated {
 		console.Println("Created minio configuration file at " + mustGetConfigPath())
 	}
 
+	// Override config fields set in the environment.
+	serverConfigMu.Lock()
+	err = overrideConfigFromEnv(serverConfig, os.Environ())
+	serverConfigMu.Unlock()
+	if err != nil {
+		console.Fatalf("Unable to override minio config from environment. Err: %s.\n", err)
+	}
+
 	// Enable all loggers by now so we can use errorIf() and fatalIf()
 	enableLoggers()
 
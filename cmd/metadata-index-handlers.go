@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// MetadataQueryHandler - GET Bucket?metadata-query&where=key=value
+// ----------
+// Minio extension which lists the objects whose metadata satisfies
+// all the "where" predicates, "key=value", "key!=value" or "key", from
+// the metadata index instead of listing the whole bucket.
+func (api objectAPIHandlers) MetadataQueryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	index := globalMetadataIndex
+	if index == nil || !index.Ready() {
+		writeErrorResponse(w, ErrMetadataIndexUnavailable, r.URL)
+		return
+	}
+
+	values := r.URL.Query()
+	prefix, marker, _, maxKeys, _ := getListObjectsV1Args(values)
+	if s3Error := validateListObjectsArgs(prefix, marker, "", maxKeys); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	var preds []metadataPredicate
+	for _, where := range values["where"] {
+		pred, err := parseMetadataPredicate(where)
+		if err != nil {
+			writeErrorResponse(w, ErrInvalidMetadataPredicate, r.URL)
+			return
+		}
+		preds = append(preds, pred)
+	}
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	result := index.query(bucket, prefix, marker, maxKeys, preds)
+	response := generateListObjectsV1Response(bucket, prefix, marker, "", maxKeys, result)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Wrapper for calling metadata query HTTP handler tests for both XL multiple disks and single node setup.
+func TestMetadataQueryHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testMetadataQueryHandler, []string{"MetadataQuery"})
+}
+
+func testMetadataQueryHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	for object, customer := range map[string]string{"a": "42", "b": "7", "c": "42"} {
+		metadata := map[string]string{"X-Amz-Meta-Customer": customer}
+		_, err := obj.PutObject(context.Background(), bucketName, object, int64(len("data")), bytes.NewBufferString("data"), metadata, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object %s: <ERROR> %v", instanceType, object, err)
+		}
+	}
+
+	query := func(accessKey string, where ...string) *httptest.ResponseRecorder {
+		queryVal := url.Values{"where": where}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getMetadataQueryURL("", bucketName, queryVal),
+			0, nil, accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request for MetadataQuery: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The index is disabled.
+	if rec := query(credentials.AccessKey, "x-amz-meta-customer=42"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusServiceUnavailable, rec.Code)
+	}
+
+	globalMetadataIndex = newMetadataIndex()
+	defer func() { globalMetadataIndex = nil }()
+	if err := globalMetadataIndex.build(obj); err != nil {
+		t.Fatalf("%s: Unable to build the metadata index: <ERROR> %v", instanceType, err)
+	}
+
+	if rec := query("Invalid-AccessID", "x-amz-meta-customer=42"); rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+	if rec := query(credentials.AccessKey, "=42"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	rec := query(credentials.AccessKey, "x-amz-meta-customer=42")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	response := ListObjectsResponse{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s: Unable to parse the response: <ERROR> %v", instanceType, err)
+	}
+	if len(response.Contents) != 2 || response.Contents[0].Key != "a" || response.Contents[1].Key != "c" {
+		t.Fatalf("%s: Expected the objects a and c, got %#v", instanceType, response.Contents)
+	}
+	if response.Contents[0].Size != int64(len("data")) || response.Contents[0].ETag == "" {
+		t.Errorf("%s: Unexpected listing info %#v", instanceType, response.Contents[0])
+	}
+}
@@ -0,0 +1,581 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variable enabling the metadata index when set to "on".
+const metadataIndexEnv = "MINIO_METADATA_INDEX"
+
+// Journal of the metadata index, in the config directory.
+const metadataIndexJournal = "metadata-index.json"
+
+// The journal is compacted once holding that many records, and twice
+// as many as there are objects indexed.
+const metadataIndexCompactRecords = 100000
+
+// Operations journaled by the metadata index.
+const (
+	indexOpSet          = "set"
+	indexOpRemove       = "remove"
+	indexOpRemoveBucket = "remove-bucket"
+	indexOpRenameBucket = "rename-bucket"
+	indexOpRenamePrefix = "rename-prefix"
+	// The index was built, all the records before it are complete.
+	indexOpBuilt = "built"
+)
+
+// indexRecord - update of the metadata index, as journaled on disk.
+type indexRecord struct {
+	Op     string `json:"op"`
+	Bucket string `json:"bucket,omitempty"`
+	// Object, or prefix when renamed.
+	Object string `json:"object,omitempty"`
+	// New name of the bucket or of the prefix when renamed.
+	NewName string         `json:"newName,omitempty"`
+	Info    *indexedObject `json:"info,omitempty"`
+}
+
+// indexedObject - listing info and metadata of an indexed object.
+type indexedObject struct {
+	ModTime time.Time
+	Size    int64
+	MD5Sum  string
+
+	// Metadata with canonical header keys, such as
+	// "X-Amz-Meta-Customer".
+	Metadata map[string]string
+}
+
+// metadataIndex - index of the objects of all the buckets and of
+// their metadata, updated on every write served by this server. The
+// index is queried in memory and its updates are journaled on disk,
+// it is only built from the backend when no complete journal exists.
+type metadataIndex struct {
+	mutex   *sync.RWMutex
+	buckets map[string]map[string]indexedObject
+
+	// Objects removed while the index is being built, not to be
+	// added back by the build.
+	building bool
+	removed  map[string]struct{}
+
+	// Journal of the updates, nil when the index is kept in memory
+	// only, along with the number of records it holds.
+	journalPath string
+	journal     *os.File
+	records     int
+}
+
+// Global metadata index, nil when disabled.
+var globalMetadataIndex *metadataIndex
+
+// newMetadataIndex - initializes an empty index, to be built.
+func newMetadataIndex() *metadataIndex {
+	return &metadataIndex{
+		mutex:    &sync.RWMutex{},
+		buckets:  make(map[string]map[string]indexedObject),
+		building: true,
+		removed:  make(map[string]struct{}),
+	}
+}
+
+// newIndexedObject - returns the indexed info of an object.
+func newIndexedObject(objInfo ObjectInfo) indexedObject {
+	metadata := make(map[string]string, len(objInfo.UserDefined))
+	for key, value := range objInfo.UserDefined {
+		metadata[http.CanonicalHeaderKey(key)] = value
+	}
+	return indexedObject{
+		ModTime:  objInfo.ModTime,
+		Size:     objInfo.Size,
+		MD5Sum:   objInfo.MD5Sum,
+		Metadata: metadata,
+	}
+}
+
+// Ready - returns true once the index is built.
+func (idx *metadataIndex) Ready() bool {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return !idx.building
+}
+
+// set - indexes a written object.
+func (idx *metadataIndex) set(bucket string, objInfo ObjectInfo) {
+	info := newIndexedObject(objInfo)
+	idx.update(indexRecord{Op: indexOpSet, Bucket: bucket, Object: objInfo.Name, Info: &info})
+}
+
+// add - indexes an object found by the build, unless written or
+// removed since.
+func (idx *metadataIndex) add(bucket string, objInfo ObjectInfo) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	if _, ok := idx.removed[pathJoin(bucket, objInfo.Name)]; ok {
+		return
+	}
+	if _, ok := idx.buckets[bucket][objInfo.Name]; ok {
+		return
+	}
+	info := newIndexedObject(objInfo)
+	idx.updateLocked(indexRecord{Op: indexOpSet, Bucket: bucket, Object: objInfo.Name, Info: &info})
+}
+
+// remove - removes a deleted object from the index.
+func (idx *metadataIndex) remove(bucket, object string) {
+	idx.update(indexRecord{Op: indexOpRemove, Bucket: bucket, Object: object})
+}
+
+// removeBucket - removes all the objects of a deleted bucket.
+func (idx *metadataIndex) removeBucket(bucket string) {
+	idx.update(indexRecord{Op: indexOpRemoveBucket, Bucket: bucket})
+}
+
+// renameBucket - moves all the objects of srcBucket to dstBucket.
+func (idx *metadataIndex) renameBucket(srcBucket, dstBucket string) {
+	idx.update(indexRecord{Op: indexOpRenameBucket, Bucket: srcBucket, NewName: dstBucket})
+}
+
+// renamePrefix - moves the objects of bucket under prefix to newPrefix.
+func (idx *metadataIndex) renamePrefix(bucket, prefix, newPrefix string) {
+	idx.update(indexRecord{Op: indexOpRenamePrefix, Bucket: bucket, Object: prefix, NewName: newPrefix})
+}
+
+// update - applies rec to the index and journals it.
+func (idx *metadataIndex) update(rec indexRecord) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.updateLocked(rec)
+}
+
+func (idx *metadataIndex) updateLocked(rec indexRecord) {
+	idx.applyLocked(rec)
+	idx.journalLocked(rec)
+}
+
+// applyLocked - applies rec to the index in memory.
+func (idx *metadataIndex) applyLocked(rec indexRecord) {
+	switch rec.Op {
+	case indexOpSet:
+		objects, ok := idx.buckets[rec.Bucket]
+		if !ok {
+			objects = make(map[string]indexedObject)
+			idx.buckets[rec.Bucket] = objects
+		}
+		objects[rec.Object] = *rec.Info
+		delete(idx.removed, pathJoin(rec.Bucket, rec.Object))
+	case indexOpRemove:
+		delete(idx.buckets[rec.Bucket], rec.Object)
+		if idx.building {
+			idx.removed[pathJoin(rec.Bucket, rec.Object)] = struct{}{}
+		}
+	case indexOpRemoveBucket:
+		delete(idx.buckets, rec.Bucket)
+	case indexOpRenameBucket:
+		if objects, ok := idx.buckets[rec.Bucket]; ok {
+			idx.buckets[rec.NewName] = objects
+			delete(idx.buckets, rec.Bucket)
+		}
+	case indexOpRenamePrefix:
+		objects := idx.buckets[rec.Bucket]
+		renamed := make(map[string]indexedObject)
+		for object, obj := range objects {
+			if strings.HasPrefix(object, rec.Object) {
+				delete(objects, object)
+				renamed[rec.NewName+strings.TrimPrefix(object, rec.Object)] = obj
+			}
+		}
+		for object, obj := range renamed {
+			objects[object] = obj
+		}
+	case indexOpBuilt:
+		idx.building = false
+		idx.removed = nil
+	}
+}
+
+// journalLocked - appends rec to the journal, compacting it when
+// needed. The journal is dropped on errors, for the index to be
+// built again on the next startup.
+func (idx *metadataIndex) journalLocked(rec indexRecord) {
+	if idx.journal == nil {
+		return
+	}
+	buf, err := json.Marshal(rec)
+	if err == nil {
+		_, err = idx.journal.Write(append(buf, '\n'))
+	}
+	if err == nil {
+		idx.records++
+		if idx.records < metadataIndexCompactRecords || idx.records < 2*idx.countLocked() {
+			return
+		}
+		err = idx.compactLocked()
+	}
+	if err != nil {
+		errorIf(err, "Unable to journal the metadata index, it will be built again on restart.")
+		if idx.journal != nil {
+			idx.journal.Close()
+			idx.journal = nil
+		}
+		os.Remove(idx.journalPath)
+	}
+}
+
+// countLocked - returns the number of objects indexed.
+func (idx *metadataIndex) countLocked() int {
+	count := 0
+	for _, objects := range idx.buckets {
+		count += len(objects)
+	}
+	return count
+}
+
+// compactLocked - replaces the journal with the records of the
+// objects indexed, and opens it for the next updates.
+func (idx *metadataIndex) compactLocked() error {
+	if idx.journal != nil {
+		idx.journal.Close()
+		idx.journal = nil
+	}
+
+	tmpPath := idx.journalPath + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmpFile)
+	enc := json.NewEncoder(w)
+	records := 0
+	for bucket, objects := range idx.buckets {
+		for object, obj := range objects {
+			info := obj
+			if err = enc.Encode(indexRecord{Op: indexOpSet, Bucket: bucket, Object: object, Info: &info}); err != nil {
+				tmpFile.Close()
+				return err
+			}
+			records++
+		}
+	}
+	if !idx.building {
+		if err = enc.Encode(indexRecord{Op: indexOpBuilt}); err != nil {
+			tmpFile.Close()
+			return err
+		}
+		records++
+	}
+	if err = w.Flush(); err == nil {
+		err = tmpFile.Sync()
+	}
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, idx.journalPath); err != nil {
+		return err
+	}
+
+	idx.journal, err = os.OpenFile(idx.journalPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	idx.records = records
+	return nil
+}
+
+// load - replays the journal, the index is left empty if it is
+// missing, corrupted or of an index never completely built.
+func (idx *metadataIndex) load() error {
+	f, err := os.Open(idx.journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A last record partially written is dropped.
+			if idx.building {
+				idx.resetLocked()
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var rec indexRecord
+		if err = json.Unmarshal(line, &rec); err != nil || (rec.Op == indexOpSet && rec.Info == nil) {
+			errorIf(errCorruptedFormat, "Unable to replay the metadata index journal %s.", idx.journalPath)
+			idx.resetLocked()
+			return nil
+		}
+		idx.applyLocked(rec)
+	}
+}
+
+// resetLocked - empties the index, to be built.
+func (idx *metadataIndex) resetLocked() {
+	idx.buckets = make(map[string]map[string]indexedObject)
+	idx.building = true
+	idx.removed = make(map[string]struct{})
+}
+
+// openMetadataIndex - opens the index journaled in dir, ready unless
+// it is to be built as no complete journal exists.
+func openMetadataIndex(dir string) (*metadataIndex, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	idx := newMetadataIndex()
+	idx.journalPath = filepath.Join(dir, metadataIndexJournal)
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+	if err := idx.compactLocked(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// build - indexes the objects of all the buckets, then marks the
+// index ready.
+func (idx *metadataIndex) build(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		if err = idx.buildBucket(objAPI, bucket.Name); err != nil {
+			return err
+		}
+	}
+
+	idx.update(indexRecord{Op: indexOpBuilt})
+	return nil
+}
+
+// buildBucket - indexes the objects of bucket with their metadata.
+func (idx *metadataIndex) buildBucket(objAPI ObjectLayer, bucket string) error {
+	marker := ""
+	for {
+		var result ListObjectsInfo
+		err := globalBackgroundOps.Do(func() (lErr error) {
+			result, lErr = objAPI.ListObjects(context.Background(), bucket, "", marker, "", maxObjectList)
+			return lErr
+		})
+		if err != nil {
+			if isErrBucketNotFound(err) {
+				// Removed since listed.
+				return nil
+			}
+			return err
+		}
+		for _, listed := range result.Objects {
+			var objInfo ObjectInfo
+			err = globalBackgroundOps.Do(func() (gErr error) {
+				objInfo, gErr = objAPI.GetObjectInfo(context.Background(), bucket, listed.Name)
+				return gErr
+			})
+			if err != nil {
+				if isErrObjectNotFound(err) {
+					// Removed since listed.
+					continue
+				}
+				return err
+			}
+			idx.add(bucket, objInfo)
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// metadataPredicate - condition on a metadata entry of the objects,
+// parsed from "key=value", "key!=value" or "key" for any value.
+type metadataPredicate struct {
+	key      string
+	value    string
+	negate   bool
+	anyValue bool
+}
+
+// parseMetadataPredicate - parses a metadata predicate, keys are case
+// insensitive and values case sensitive.
+func parseMetadataPredicate(s string) (metadataPredicate, error) {
+	var pred metadataPredicate
+	if i := strings.Index(s, "!="); i >= 0 {
+		pred.key, pred.value, pred.negate = s[:i], s[i+2:], true
+	} else if i = strings.Index(s, "="); i >= 0 {
+		pred.key, pred.value = s[:i], s[i+1:]
+	} else {
+		pred.key, pred.anyValue = s, true
+	}
+	if pred.key == "" {
+		return pred, errInvalidArgument
+	}
+	pred.key = http.CanonicalHeaderKey(pred.key)
+	return pred, nil
+}
+
+// matches - returns true if the metadata satisfies the predicate, an
+// entry missing is different from any value.
+func (pred metadataPredicate) matches(metadata map[string]string) bool {
+	value, ok := metadata[pred.key]
+	if pred.anyValue {
+		return ok
+	}
+	return (ok && value == pred.value) != pred.negate
+}
+
+// query - lists in lexical order up to maxKeys objects of bucket under
+// prefix and after marker satisfying all the predicates.
+func (idx *metadataIndex) query(bucket, prefix, marker string, maxKeys int, preds []metadataPredicate) ListObjectsInfo {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	objects := idx.buckets[bucket]
+	var names []string
+	for object, obj := range objects {
+		if !strings.HasPrefix(object, prefix) || object <= marker {
+			continue
+		}
+		matched := true
+		for _, pred := range preds {
+			if !pred.matches(obj.Metadata) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			names = append(names, object)
+		}
+	}
+	sort.Strings(names)
+
+	var result ListObjectsInfo
+	if len(names) > maxKeys {
+		names = names[:maxKeys]
+		result.IsTruncated = true
+	}
+	for _, object := range names {
+		obj := objects[object]
+		result.Objects = append(result.Objects, ObjectInfo{
+			Bucket:      bucket,
+			Name:        object,
+			ModTime:     obj.ModTime,
+			Size:        obj.Size,
+			MD5Sum:      obj.MD5Sum,
+			UserDefined: obj.Metadata,
+		})
+	}
+	if result.IsTruncated && len(names) > 0 {
+		result.NextMarker = names[len(names)-1]
+	}
+	return result
+}
+
+// indexedObjectLayer - updates the metadata index on the writes of the
+// wrapped ObjectLayer.
+type indexedObjectLayer struct {
+	ObjectLayer
+	index *metadataIndex
+}
+
+// indexObjectLayer - returns objAPI updating the global metadata
+// index, objAPI itself when the index is disabled.
+func indexObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	if globalMetadataIndex == nil {
+		return objAPI
+	}
+	return indexedObjectLayer{objAPI, globalMetadataIndex}
+}
+
+func (l indexedObjectLayer) DeleteBucket(ctx context.Context, bucket string) error {
+	err := l.ObjectLayer.DeleteBucket(ctx, bucket)
+	if err == nil {
+		l.index.removeBucket(bucket)
+	}
+	return err
+}
+
+func (l indexedObjectLayer) RenameBucket(ctx context.Context, srcBucket, dstBucket string) error {
+	err := l.ObjectLayer.RenameBucket(ctx, srcBucket, dstBucket)
+	if err == nil {
+		l.index.renameBucket(srcBucket, dstBucket)
+	}
+	return err
+}
+
+func (l indexedObjectLayer) PutObject(ctx context.Context, bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (ObjectInfo, error) {
+	objInfo, err := l.ObjectLayer.PutObject(ctx, bucket, object, size, data, metadata, sha256sum)
+	if err == nil && bucket != minioMetaBucket {
+		l.index.set(bucket, objInfo)
+	}
+	return objInfo, err
+}
+
+func (l indexedObjectLayer) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (ObjectInfo, error) {
+	objInfo, err := l.ObjectLayer.CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, metadata)
+	if err == nil {
+		l.index.set(destBucket, objInfo)
+	}
+	return objInfo, err
+}
+
+func (l indexedObjectLayer) DeleteObject(ctx context.Context, bucket, object string) error {
+	err := l.ObjectLayer.DeleteObject(ctx, bucket, object)
+	if err == nil && bucket != minioMetaBucket {
+		l.index.remove(bucket, object)
+	}
+	return err
+}
+
+func (l indexedObjectLayer) RenamePrefix(ctx context.Context, bucket, prefix, newPrefix string) error {
+	err := l.ObjectLayer.RenamePrefix(ctx, bucket, prefix, newPrefix)
+	if err == nil {
+		l.index.renamePrefix(bucket, prefix, newPrefix)
+	}
+	return err
+}
+
+func (l indexedObjectLayer) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []completePart) (ObjectInfo, error) {
+	objInfo, err := l.ObjectLayer.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	if err == nil {
+		l.index.set(bucket, objInfo)
+	}
+	return objInfo, err
+}
@@ -0,0 +1,228 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests parsing and matching the metadata predicates.
+func TestMetadataPredicate(t *testing.T) {
+	metadata := map[string]string{
+		"X-Amz-Meta-Customer": "42",
+		"Content-Type":        "text/plain",
+	}
+	testCases := []struct {
+		predicate  string
+		matches    bool
+		shouldPass bool
+	}{
+		{"x-amz-meta-customer=42", true, true},
+		{"X-AMZ-META-CUSTOMER=42", true, true},
+		{"x-amz-meta-customer=43", false, true},
+		{"x-amz-meta-customer!=43", true, true},
+		{"x-amz-meta-customer!=42", false, true},
+		{"x-amz-meta-tier!=cold", true, true},
+		{"x-amz-meta-customer", true, true},
+		{"x-amz-meta-tier", false, true},
+		{"content-type=text/plain", true, true},
+		{"x-amz-meta-customer=", false, true},
+		{"=42", false, false},
+		{"", false, false},
+	}
+	for i, testCase := range testCases {
+		pred, err := parseMetadataPredicate(testCase.predicate)
+		if testCase.shouldPass != (err == nil) {
+			t.Fatalf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if err != nil {
+			continue
+		}
+		if matches := pred.matches(metadata); matches != testCase.matches {
+			t.Errorf("Test %d: Expected %s to match %v, got %v", i+1, testCase.predicate, testCase.matches, matches)
+		}
+	}
+}
+
+// Tests building the metadata index and keeping it up to date.
+func TestMetadataIndex(t *testing.T) {
+	ExecObjectLayerTest(t, testMetadataIndex)
+}
+
+func testMetadataIndex(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	putObject := func(objAPI ObjectLayer, bucket, object, customer string) {
+		metadata := map[string]string{"X-Amz-Meta-Customer": customer}
+		_, err := objAPI.PutObject(context.Background(), bucket, object, int64(len("data")), bytes.NewBufferString("data"), metadata, "")
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	queryNames := func(idx *metadataIndex, bucket, prefix, marker string, maxKeys int, where ...string) []string {
+		var preds []metadataPredicate
+		for _, w := range where {
+			pred, err := parseMetadataPredicate(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			preds = append(preds, pred)
+		}
+		var names []string
+		for _, objInfo := range idx.query(bucket, prefix, marker, maxKeys, preds).Objects {
+			names = append(names, objInfo.Name)
+		}
+		return names
+	}
+
+	if err := obj.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	putObject(obj, "bucket", "a/1", "42")
+	putObject(obj, "bucket", "a/2", "7")
+	putObject(obj, "bucket", "b/1", "42")
+
+	idx := newMetadataIndex()
+	if idx.Ready() {
+		t.Fatalf("%s: Expected the index not to be ready before it is built", instanceType)
+	}
+	if err := idx.build(obj); err != nil {
+		t.Fatalf("%s: Unable to build the index: %v", instanceType, err)
+	}
+	if !idx.Ready() {
+		t.Fatalf("%s: Expected the index to be ready once built", instanceType)
+	}
+
+	if names := queryNames(idx, "bucket", "", "", 1000, "x-amz-meta-customer=42"); !reflect.DeepEqual(names, []string{"a/1", "b/1"}) {
+		t.Fatalf("%s: Unexpected objects %v", instanceType, names)
+	}
+	if names := queryNames(idx, "bucket", "a/", "", 1000, "x-amz-meta-customer=42"); !reflect.DeepEqual(names, []string{"a/1"}) {
+		t.Fatalf("%s: Unexpected objects under a/ %v", instanceType, names)
+	}
+	result := idx.query("bucket", "", "", 1, nil)
+	if len(result.Objects) != 1 || !result.IsTruncated || result.NextMarker != "a/1" {
+		t.Fatalf("%s: Unexpected first page %#v", instanceType, result)
+	}
+	if names := queryNames(idx, "bucket", "", "a/1", 1000); !reflect.DeepEqual(names, []string{"a/2", "b/1"}) {
+		t.Fatalf("%s: Unexpected objects after a/1 %v", instanceType, names)
+	}
+
+	// Writes through the indexed object layer update the index.
+	indexed := indexedObjectLayer{obj, idx}
+	putObject(indexed, "bucket", "a/2", "42")
+	if err := indexed.DeleteObject(context.Background(), "bucket", "b/1"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err := indexed.CopyObject(context.Background(), "bucket", "a/1", "bucket", "c/1", map[string]string{"X-Amz-Meta-Customer": "42"}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if names := queryNames(idx, "bucket", "", "", 1000, "x-amz-meta-customer=42"); !reflect.DeepEqual(names, []string{"a/1", "a/2", "c/1"}) {
+		t.Fatalf("%s: Unexpected objects after the writes %v", instanceType, names)
+	}
+	if err := indexed.RenamePrefix(context.Background(), "bucket", "a/", "d/"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if names := queryNames(idx, "bucket", "", "", 1000, "x-amz-meta-customer!=7"); !reflect.DeepEqual(names, []string{"c/1", "d/1", "d/2"}) {
+		t.Fatalf("%s: Unexpected objects after the rename %v", instanceType, names)
+	}
+}
+
+// Tests the metadata index is reloaded from its journal.
+func TestMetadataIndexJournal(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	objInfo := func(object, customer string) ObjectInfo {
+		return ObjectInfo{Name: object, Size: 4, UserDefined: map[string]string{"X-Amz-Meta-Customer": customer}}
+	}
+	names := func(idx *metadataIndex, bucket string) []string {
+		var names []string
+		for _, obj := range idx.query(bucket, "", "", 1000, nil).Objects {
+			names = append(names, obj.Name)
+		}
+		return names
+	}
+
+	idx, err := openMetadataIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Indexes not completely built are built again once reopened.
+	idx.add("bucket", objInfo("a/1", "42"))
+	idx.journal.Close()
+	if idx, err = openMetadataIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Ready() || len(names(idx, "bucket")) != 0 {
+		t.Fatal("Expected an index not completely built to be built again")
+	}
+
+	idx.add("bucket", objInfo("a/1", "42"))
+	idx.update(indexRecord{Op: indexOpBuilt})
+	idx.set("bucket", objInfo("a/2", "7"))
+	idx.set("other", objInfo("b/1", "42"))
+	idx.remove("bucket", "a/1")
+	idx.renamePrefix("bucket", "a/", "c/")
+	idx.renameBucket("other", "renamed")
+	idx.journal.Close()
+
+	// A last record partially written is dropped.
+	f, err := os.OpenFile(filepath.Join(dir, metadataIndexJournal), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteString(`{"op":"remove","bucket":"buck`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if idx, err = openMetadataIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !idx.Ready() {
+		t.Fatal("Expected the index to be ready once reloaded")
+	}
+	if got := names(idx, "bucket"); !reflect.DeepEqual(got, []string{"c/2"}) {
+		t.Fatalf("Unexpected objects %v", got)
+	}
+	if got := names(idx, "renamed"); !reflect.DeepEqual(got, []string{"b/1"}) {
+		t.Fatalf("Unexpected objects of the renamed bucket %v", got)
+	}
+	if got := idx.query("renamed", "", "", 1000, nil).Objects[0].UserDefined["X-Amz-Meta-Customer"]; got != "42" {
+		t.Fatalf("Unexpected metadata %q", got)
+	}
+	idx.journal.Close()
+
+	// Corrupted journals are built again.
+	if err = ioutil.WriteFile(filepath.Join(dir, metadataIndexJournal), []byte("corrupted\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if idx, err = openMetadataIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Ready() || len(names(idx, "bucket")) != 0 {
+		t.Fatal("Expected a corrupted index to be built again")
+	}
+	idx.journal.Close()
+}
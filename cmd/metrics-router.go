@@ -0,0 +1,51 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	metricsPath = "/prometheus/metrics"
+)
+
+// registerMetricsRouter - registers the Prometheus metrics endpoint.
+func registerMetricsRouter(mux *router.Router) {
+	metricsRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+	metricsRouter.Methods("GET").Path(metricsPath).HandlerFunc(metricsHandler)
+}
+
+// metricsHandler - GET /minio/prometheus/metrics
+// ----------
+// Returns the metrics of this server in the Prometheus text exposition
+// format. Metrics are aggregate counts only, they are available to
+// anonymous requests for Prometheus to scrape them.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	var buffer bytes.Buffer
+	if err := writeLockMetrics(&buffer, globalNSMutex); err != nil {
+		errorIf(err, "Unable to write lock metrics.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,202 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+// Latest version of the backend format, for both FS and XL.
+const backendFormatVersion = "1"
+
+var migrateFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Only report the migrations which would be performed, without modifying anything.",
+	},
+}
+
+var migrateCmd = cli.Command{
+	Name:   "migrate",
+	Usage:  "Migrate config and backend format to the latest version.",
+	Flags:  append(migrateFlags, globalFlags...),
+	Action: mainMigrate,
+	CustomHelpTemplate: `NAME:
+  minio {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio {{.Name}} [FLAGS] [PATH...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Migrates the config in the config directory and the backend format of the local paths, if
+  any, to the latest version supported by this release. The same migrations are otherwise
+  performed implicitly when the server starts. Run with --dry-run first to preview them, the
+  server should be stopped while migrating.
+
+EXAMPLES:
+  1. Preview the migrations of the config and the FS backend exported from "/home/shared".
+      $ minio {{.Name}} --dry-run /home/shared
+
+  2. Migrate the config in a custom config directory.
+      $ minio {{.Name}} --config-dir /etc/minio
+
+`,
+}
+
+// getConfigVersion - returns the version of the config found in the
+// config directory, empty if there is no config yet.
+func getConfigVersion() (string, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	buf, err := ioutil.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		// Version '1' config lived in a different file.
+		if _, err = loadConfigV1(); err == nil {
+			return "1", nil
+		} else if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var config struct {
+		Version string `json:"version"`
+	}
+	if err = json.Unmarshal(buf, &config); err != nil {
+		return "", fmt.Errorf("Unable to parse config file %s. %v", configFile, err)
+	}
+	return config.Version, nil
+}
+
+// getConfigMigrationPlan - returns the config versions the config
+// goes through until it reaches globalMinioConfigVersion, the first
+// element being the current version. Empty if no migration is needed.
+func getConfigMigrationPlan() ([]string, error) {
+	version, err := getConfigVersion()
+	if err != nil || version == "" || version == globalMinioConfigVersion {
+		return nil, err
+	}
+
+	from, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("Unrecognized config version ‘%s’", version)
+	}
+	to, _ := strconv.Atoi(globalMinioConfigVersion)
+	if from < 1 || from > to {
+		return nil, fmt.Errorf("Config version ‘%s’ is not supported by this release", version)
+	}
+
+	var plan []string
+	for v := from; v <= to; v++ {
+		plan = append(plan, strconv.Itoa(v))
+	}
+	return plan, nil
+}
+
+// checkBackendFormat - returns a description of the backend format of
+// the local diskPath, error if it can not be used by this release.
+func checkBackendFormat(diskPath string) (string, error) {
+	if _, err := os.Stat(diskPath); err != nil {
+		return "", err
+	}
+	disk, err := newPosix(diskPath)
+	if err != nil {
+		return "", err
+	}
+
+	format, err := loadFormat(disk)
+	if err == errUnformattedDisk {
+		return "not formatted yet", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var backendVersion string
+	switch {
+	case format.Format == "fs" && format.FS != nil:
+		backendVersion = format.FS.Version
+	case format.Format == "xl" && format.XL != nil:
+		backendVersion = format.XL.Version
+	default:
+		return "", fmt.Errorf("Unrecognized backend format ‘%s’", format.Format)
+	}
+	if format.Version != backendFormatVersion || backendVersion != backendFormatVersion {
+		return "", fmt.Errorf("Backend format %s version ‘%s’ is not supported by this release", format.Format, backendVersion)
+	}
+	return fmt.Sprintf("%s format version ‘%s’ is the latest", format.Format, backendVersion), nil
+}
+
+// mainMigrate handler called for 'minio migrate' command.
+func mainMigrate(ctx *cli.Context) {
+	if ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, "migrate", 1)
+	}
+
+	// Unlike minioInit() the config is neither migrated
+	// nor created implicitly here.
+	setGlobalsFromContext(ctx)
+	setGlobalConfigPath(globalConfigDir)
+	dryRun := ctx.Bool("dry-run")
+
+	plan, err := getConfigMigrationPlan()
+	if err != nil {
+		console.Fatalf("Unable to plan config migration. %s.\n", err)
+	}
+	switch {
+	case len(plan) > 0 && plan[0] == "1":
+		console.Println("Config: unsupported version ‘1’ to be removed, a new config is created on server start.")
+	case len(plan) > 0:
+		console.Printf("Config: migrate from version ‘%s’.\n", strings.Join(plan, "’ → ‘"))
+	case isConfigFileExists():
+		console.Printf("Config: version ‘%s’ is the latest.\n", globalMinioConfigVersion)
+	default:
+		console.Println("Config: not found, created on server start.")
+	}
+
+	for _, diskPath := range ctx.Args() {
+		desc, err := checkBackendFormat(diskPath)
+		if err != nil {
+			console.Fatalf("Unable to check backend format of %s. %s.\n", diskPath, err)
+		}
+		console.Printf("%s: %s.\n", diskPath, desc)
+	}
+
+	if dryRun || len(plan) == 0 {
+		return
+	}
+	if err = migrateConfig(); err != nil {
+		console.Fatalf("Config migration failed. %s.\n", err)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests planning config migrations.
+func TestGetConfigMigrationPlan(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	setGlobalConfigPath(rootPath)
+	configPath := filepath.Join(rootPath, globalMinioConfigFile)
+
+	// Test 1: latest config needs no migration.
+	plan, err := getConfigMigrationPlan()
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("Test 1: Expected no migration, got %v, %v", plan, err)
+	}
+
+	// Test 2: older config.
+	if err = ioutil.WriteFile(configPath, []byte(`{"version":"11"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = getConfigMigrationPlan()
+	if err != nil {
+		t.Fatalf("Test 2: Unexpected error %s", err)
+	}
+	if expected := []string{"11", "12", "13", "14", "15", "16", "17"}; !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("Test 2: Expected %v, got %v", expected, plan)
+	}
+
+	// Test 3: config from a newer release.
+	if err = ioutil.WriteFile(configPath, []byte(`{"version":"99"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getConfigMigrationPlan(); err == nil {
+		t.Fatal("Test 3: Expected error for unsupported config version")
+	}
+
+	// Test 4: no config at all.
+	if err = os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = getConfigMigrationPlan()
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("Test 4: Expected no migration, got %v, %v", plan, err)
+	}
+}
+
+// Tests checking the backend format.
+func TestCheckBackendFormat(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	_, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if _, err = checkBackendFormat(fsDir); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	formatPath := filepath.Join(fsDir, minioMetaBucket, fsFormatJSONFile)
+	if err = ioutil.WriteFile(formatPath, []byte(`{"version":"1","format":"fs","fs":{"version":"2"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = checkBackendFormat(fsDir); err == nil {
+		t.Fatal("Expected error for unsupported backend format version")
+	}
+
+	if _, err = checkBackendFormat(filepath.Join(fsDir, "non-existent")); err == nil {
+		t.Fatal("Expected error for non existent path")
+	}
+}
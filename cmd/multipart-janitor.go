@@ -0,0 +1,159 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Environment variable setting the age after which multipart uploads
+// are aborted, such as "72h", "off" disables the expiry.
+const multipartExpiryEnv = "MINIO_MULTIPART_EXPIRY"
+
+// Multipart uploads are aborted after a week by default.
+const defaultMultipartExpiry = 7 * 24 * time.Hour
+
+// Largest interval between two scans for expired multipart uploads.
+const multipartExpiryMaxInterval = time.Hour
+
+// multipartJanitor - periodically aborts the multipart uploads
+// older than expiry, which clients never completed nor aborted.
+type multipartJanitor struct {
+	objAPI   ObjectLayer
+	expiry   time.Duration
+	interval time.Duration
+
+	// Notifies an event, eventNotify() unless overridden by tests.
+	notify func(eventData)
+
+	doneCh chan struct{}
+}
+
+// getMultipartExpiry - returns the multipart upload expiry configured
+// through the environment, 0 if the expiry is disabled.
+func getMultipartExpiry() (time.Duration, error) {
+	value := os.Getenv(multipartExpiryEnv)
+	switch value {
+	case "":
+		return defaultMultipartExpiry, nil
+	case "off":
+		return 0, nil
+	}
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", multipartExpiryEnv, value, err)
+	}
+	if expiry <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive", multipartExpiryEnv, value)
+	}
+	return expiry, nil
+}
+
+// newMultipartJanitor - initializes a janitor for objAPI, not started yet.
+func newMultipartJanitor(objAPI ObjectLayer, expiry time.Duration) *multipartJanitor {
+	interval := expiry
+	if interval > multipartExpiryMaxInterval {
+		interval = multipartExpiryMaxInterval
+	}
+	return &multipartJanitor{
+		objAPI:   objAPI,
+		expiry:   expiry,
+		interval: interval,
+		notify:   eventNotify,
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start - runs the janitor in the background until Stop is called.
+func (j *multipartJanitor) Start() {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(j.expireUploads(time.Now().UTC()), "Unable to abort expired multipart uploads.")
+			case <-j.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop - stops the janitor, safe to be called on nil.
+func (j *multipartJanitor) Stop() {
+	if j == nil {
+		return
+	}
+	close(j.doneCh)
+}
+
+// expireUploads - aborts the multipart uploads of all the buckets
+// initiated earlier than expiry before now.
+func (j *multipartJanitor) expireUploads(now time.Time) error {
+	buckets, err := j.objAPI.ListBuckets(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		var keyMarker, uploadIDMarker string
+		for {
+			result, err := j.objAPI.ListMultipartUploads(context.Background(), bucket.Name, "", keyMarker, uploadIDMarker, "", maxUploadsList)
+			if err != nil {
+				if isErrBucketNotFound(err) {
+					// Removed since listed.
+					break
+				}
+				return err
+			}
+			for _, upload := range result.Uploads {
+				if now.Sub(upload.Initiated) <= j.expiry {
+					continue
+				}
+				err = globalBackgroundOps.Do(func() error {
+					return j.objAPI.AbortMultipartUpload(context.Background(), bucket.Name, upload.Object, upload.UploadID)
+				})
+				if err != nil {
+					if isErrInvalidUploadID(err) {
+						// Completed or aborted since listed, for ex. by another server.
+						continue
+					}
+					return err
+				}
+				j.notify(eventData{
+					Type:   ObjectRemovedMultipartUploadExpired,
+					Bucket: bucket.Name,
+					ObjInfo: ObjectInfo{
+						Name: upload.Object,
+					},
+					ReqParams: map[string]string{
+						"uploadId": upload.UploadID,
+					},
+				})
+			}
+			if !result.IsTruncated {
+				break
+			}
+			keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the multipart upload expiry from the environment.
+func TestGetMultipartExpiry(t *testing.T) {
+	defer os.Unsetenv(multipartExpiryEnv)
+
+	testCases := []struct {
+		value      string
+		expiry     time.Duration
+		shouldPass bool
+	}{
+		{"", defaultMultipartExpiry, true},
+		{"off", 0, true},
+		{"72h", 72 * time.Hour, true},
+		{"0s", 0, false},
+		{"-1h", 0, false},
+		{"week", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(multipartExpiryEnv, testCase.value)
+		expiry, err := getMultipartExpiry()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if expiry != testCase.expiry {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expiry, expiry)
+		}
+	}
+}
+
+// Tests aborting the expired multipart uploads.
+func TestMultipartJanitorExpireUploads(t *testing.T) {
+	ExecObjectLayerTest(t, testMultipartJanitorExpireUploads)
+}
+
+func testMultipartJanitorExpireUploads(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	for _, bucket := range []string{"bucket1", "bucket2"} {
+		if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	uploadIDs := make(map[string]string)
+	for _, object := range []string{"a", "b", "c"} {
+		uploadID, err := obj.NewMultipartUpload(context.Background(), "bucket1", object, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		uploadIDs[object] = uploadID
+	}
+
+	janitor := newMultipartJanitor(obj, time.Hour)
+	var events []eventData
+	janitor.notify = func(event eventData) {
+		events = append(events, event)
+	}
+
+	// Nothing is old enough yet.
+	if err := janitor.expireUploads(time.Now().UTC()); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("%s: Expected no expired uploads, got %v", instanceType, events)
+	}
+
+	if err := janitor.expireUploads(time.Now().UTC().Add(2 * time.Hour)); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(events) != len(uploadIDs) {
+		t.Fatalf("%s: Expected %d expired uploads, got %v", instanceType, len(uploadIDs), events)
+	}
+	for _, event := range events {
+		if event.Type != ObjectRemovedMultipartUploadExpired || event.Bucket != "bucket1" ||
+			event.ReqParams["uploadId"] != uploadIDs[event.ObjInfo.Name] {
+			t.Errorf("%s: Unexpected event %v", instanceType, event)
+		}
+	}
+	result, err := obj.ListMultipartUploads(context.Background(), "bucket1", "", "", "", "", maxUploadsList)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Uploads) != 0 {
+		t.Fatalf("%s: Expected uploads to be aborted, got %v", instanceType, result.Uploads)
+	}
+}
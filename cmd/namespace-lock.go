@@ -17,10 +17,15 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/url"
+	"os"
 	pathutil "path"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/minio/dsync"
 )
@@ -28,6 +33,68 @@ import (
 // Global name space lock.
 var globalNSMutex *nsLockMap
 
+const (
+	// Environment variable with the time to wait for the lock
+	// responses of the servers on each attempt to acquire a lock.
+	lockAcquireTimeoutEnv = "MINIO_LOCK_ACQUIRE_TIMEOUT"
+
+	// Environment variable with the initial back-off between attempts
+	// to acquire a lock, doubled after every failed attempt.
+	lockRetryIntervalEnv = "MINIO_LOCK_RETRY_INTERVAL"
+
+	// Environment variable with the longest back-off between attempts
+	// to acquire a lock.
+	lockMaxRetryIntervalEnv = "MINIO_LOCK_MAX_RETRY_INTERVAL"
+
+	// Environment variables with the number of servers granting a
+	// write and a read lock.
+	lockWriteQuorumEnv = "MINIO_LOCK_WRITE_QUORUM"
+	lockReadQuorumEnv  = "MINIO_LOCK_READ_QUORUM"
+)
+
+// getDsyncConfig - returns the distributed lock acquisition tunables
+// configured in the environment, unset ones are left to the defaults.
+func getDsyncConfig() (config dsync.Config, err error) {
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{lockAcquireTimeoutEnv, &config.AcquireTimeout},
+		{lockRetryIntervalEnv, &config.RetryInterval},
+		{lockMaxRetryIntervalEnv, &config.MaxRetryInterval},
+	}
+	for _, d := range durations {
+		value := os.Getenv(d.env)
+		if value == "" {
+			continue
+		}
+		if *d.value, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("Invalid %s ‘%s’, %s", d.env, value, err)
+		}
+		if *d.value <= 0 {
+			return config, fmt.Errorf("Invalid %s ‘%s’, should be positive", d.env, value)
+		}
+	}
+
+	quorums := []struct {
+		env   string
+		value *int
+	}{
+		{lockWriteQuorumEnv, &config.WriteQuorum},
+		{lockReadQuorumEnv, &config.ReadQuorum},
+	}
+	for _, q := range quorums {
+		value := os.Getenv(q.env)
+		if value == "" {
+			continue
+		}
+		if *q.value, err = strconv.Atoi(value); err != nil || *q.value <= 0 {
+			return config, fmt.Errorf("Invalid %s ‘%s’, should be a positive number", q.env, value)
+		}
+	}
+	return config, nil
+}
+
 // RWLocker - locker interface extends sync.Locker
 // to introduce RLock, RUnlock.
 type RWLocker interface {
@@ -38,7 +105,7 @@ type RWLocker interface {
 
 // Initialize distributed locking only in case of distributed setup.
 // Returns if the setup is distributed or not on success.
-func initDsyncNodes(eps []*url.URL) error {
+func initDsyncNodes(eps []*url.URL, config dsync.Config) error {
 	cred := serverConfig.GetCredential()
 	// Initialize rpc lock client information only if this instance is a distributed setup.
 	clnts := make([]dsync.NetLocker, len(eps))
@@ -60,7 +127,7 @@ func initDsyncNodes(eps []*url.URL) error {
 		}
 	}
 
-	return dsync.Init(clnts, myNode)
+	return dsync.InitWithConfig(clnts, myNode, config)
 }
 
 // initNSLock - initialize name space lock map.
@@ -94,6 +161,7 @@ type nsLockMap struct {
 	// Lock counter used for lock debugging.
 	counters     *lockStat
 	debugLockMap map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
+	metrics      lockMetrics                             // Lock latencies and force unlocks.
 
 	// Indicates if namespace is part of a distributed setup.
 	isDistXL     bool
@@ -102,7 +170,7 @@ type nsLockMap struct {
 }
 
 // Lock the namespace resource.
-func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool) {
+func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, origin requestInfo, readLock bool) {
 	var nsLk *nsLock
 	n.lockMapMutex.Lock()
 
@@ -129,6 +197,7 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	if err := n.statusNoneToBlocked(param, lockSource, opsID, readLock); err != nil {
 		errorIf(err, "Failed to set lock state to blocked")
 	}
+	n.setLockOrigin(param, opsID, origin)
 
 	// Unlock map before Locking NS which might block.
 	n.lockMapMutex.Unlock()
@@ -195,7 +264,7 @@ func (n *nsLockMap) Lock(volume, path, opsID string) {
 	readLock := false // This is a write lock.
 
 	lockSource := callerSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, requestInfo{}, readLock)
 }
 
 // Unlock - unlocks any previously acquired write locks.
@@ -209,7 +278,7 @@ func (n *nsLockMap) RLock(volume, path, opsID string) {
 	readLock := true
 
 	lockSource := callerSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, requestInfo{}, readLock)
 }
 
 // RUnlock - unlocks any previously acquired read locks.
@@ -236,6 +305,8 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 	//   that participated in granting the lock. Any pending dsync locks that
 	//   are blocking can now proceed as normal and any new locks will also
 	//   participate normally.
+	n.metrics.forceUnlocks++
+
 	if n.isDistXL { // For distributed mode, broadcast ForceUnlock message.
 		dsync.NewDRWMutex(pathJoin(volume, path)).ForceUnlock()
 	}
@@ -258,20 +329,25 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 type lockInstance struct {
 	ns                  *nsLockMap
 	volume, path, opsID string
+	origin              requestInfo
+	ctx                 context.Context
 }
 
 // NewNSLock - returns a lock instance for a given volume and
 // path. The returned lockInstance object encapsulates the nsLockMap,
-// volume, path and operation ID.
-func (n *nsLockMap) NewNSLock(volume, path string) RWLocker {
-	return &lockInstance{n, volume, path, getOpsID()}
+// volume, path and operation ID, along with the API and request ID
+// of the request ctx is of.
+func (n *nsLockMap) NewNSLock(ctx context.Context, volume, path string) RWLocker {
+	return &lockInstance{n, volume, path, getOpsID(), getRequestInfo(ctx), ctx}
 }
 
 // Lock - block until write lock is taken.
 func (li *lockInstance) Lock() {
 	lockSource := callerSource()
 	readLock := false
-	li.ns.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	span := li.startSpan(readLock)
+	li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.origin, readLock)
+	span.Finish(nil)
 }
 
 // Unlock - block until write lock is released.
@@ -284,7 +360,22 @@ func (li *lockInstance) Unlock() {
 func (li *lockInstance) RLock() {
 	lockSource := callerSource()
 	readLock := true
-	li.ns.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	span := li.startSpan(readLock)
+	li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.origin, readLock)
+	span.Finish(nil)
+}
+
+// startSpan - starts the span timing the acquisition of the lock.
+func (li *lockInstance) startSpan(readLock bool) *traceSpan {
+	_, span := startSpan(li.ctx, "lock")
+	span.SetTag("volume", li.volume)
+	span.SetTag("path", li.path)
+	if readLock {
+		span.SetTag("type", "read")
+	} else {
+		span.SetTag("type", "write")
+	}
+	return span
 }
 
 // RUnlock - block until read lock is released.
@@ -17,10 +17,14 @@
 package cmd
 
 import (
+	"context"
+	"os"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/minio/dsync"
 )
 
 // Tests functionality provided by namespace lock.
@@ -386,7 +390,7 @@ func TestLockStats(t *testing.T) {
 func TestNamespaceForceUnlockTest(t *testing.T) {
 
 	// Create lock.
-	lock := globalNSMutex.NewNSLock("bucket", "object")
+	lock := globalNSMutex.NewNSLock(context.Background(), "bucket", "object")
 	lock.Lock()
 	// Forcefully unlock lock.
 	globalNSMutex.ForceUnlock("bucket", "object")
@@ -395,7 +399,7 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 
 	go func() {
 		// Try to claim lock again.
-		anotherLock := globalNSMutex.NewNSLock("bucket", "object")
+		anotherLock := globalNSMutex.NewNSLock(context.Background(), "bucket", "object")
 		anotherLock.Lock()
 		// And signal succes.
 		ch <- struct{}{}
@@ -414,3 +418,45 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 	// Clean up lock.
 	globalNSMutex.ForceUnlock("bucket", "object")
 }
+
+// Tests parsing the distributed locking configuration.
+func TestGetDsyncConfig(t *testing.T) {
+	envs := []string{lockAcquireTimeoutEnv, lockRetryIntervalEnv, lockMaxRetryIntervalEnv, lockWriteQuorumEnv, lockReadQuorumEnv}
+	defer func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}()
+
+	testCases := []struct {
+		values     []string
+		expected   dsync.Config
+		shouldPass bool
+	}{
+		{[]string{"", "", "", "", ""}, dsync.Config{}, true},
+		{[]string{"100ms", "10ms", "5s", "5", "4"}, dsync.Config{
+			AcquireTimeout:   100 * time.Millisecond,
+			RetryInterval:    10 * time.Millisecond,
+			MaxRetryInterval: 5 * time.Second,
+			WriteQuorum:      5,
+			ReadQuorum:       4,
+		}, true},
+		{[]string{"100", "", "", "", ""}, dsync.Config{}, false},
+		{[]string{"", "-1s", "", "", ""}, dsync.Config{}, false},
+		{[]string{"", "", "", "0", ""}, dsync.Config{}, false},
+		{[]string{"", "", "", "", "half"}, dsync.Config{}, false},
+	}
+	for i, testCase := range testCases {
+		for j, env := range envs {
+			os.Setenv(env, testCase.values[j])
+		}
+		config, err := getDsyncConfig()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+			continue
+		}
+		if err == nil && config != testCase.expected {
+			t.Errorf("Test %d: Expected %#v, got %#v", i+1, testCase.expected, config)
+		}
+	}
+}
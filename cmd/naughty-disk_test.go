@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"sync"
 
 	"github.com/minio/minio/pkg/disk"
@@ -115,11 +116,11 @@ func (d *naughtyDisk) ListDir(volume, path string) (entries []string, err error)
 	return d.disk.ListDir(volume, path)
 }
 
-func (d *naughtyDisk) ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error) {
+func (d *naughtyDisk) ReadFile(ctx context.Context, volume string, path string, offset int64, buf []byte) (n int64, err error) {
 	if err := d.calcError(); err != nil {
 		return 0, err
 	}
-	return d.disk.ReadFile(volume, path, offset, buf)
+	return d.disk.ReadFile(ctx, volume, path, offset, buf)
 }
 
 func (d *naughtyDisk) PrepareFile(volume, path string, length int64) error {
@@ -129,11 +130,11 @@ func (d *naughtyDisk) PrepareFile(volume, path string, length int64) error {
 	return d.disk.PrepareFile(volume, path, length)
 }
 
-func (d *naughtyDisk) AppendFile(volume, path string, buf []byte) error {
+func (d *naughtyDisk) AppendFile(ctx context.Context, volume, path string, buf []byte) error {
 	if err := d.calcError(); err != nil {
 		return err
 	}
-	return d.disk.AppendFile(volume, path, buf)
+	return d.disk.AppendFile(ctx, volume, path, buf)
 }
 
 func (d *naughtyDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
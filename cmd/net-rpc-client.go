@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -145,6 +146,24 @@ func (rpcClient *RPCClient) Call(serviceMethod string, args interface{}, reply i
 	return netRPCClient.Call(serviceMethod, args, reply)
 }
 
+// CallWithContext is the same as Call, except that it returns ctx.Err()
+// without waiting for the reply once ctx is done.
+func (rpcClient *RPCClient) CallWithContext(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	// Get a new or existing rpc.Client.
+	netRPCClient, err := rpcClient.dial()
+	if err != nil {
+		return err
+	}
+
+	call := netRPCClient.Go(serviceMethod, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Close closes underlying rpc.Client.
 func (rpcClient *RPCClient) Close() error {
 	rpcClient.Lock()
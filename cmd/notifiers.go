@@ -42,6 +42,12 @@ const (
 	queueTypeKafka = "kafka"
 	// Static string for Webhooks
 	queueTypeWebhook = "webhook"
+	// Static string indicating queue type 'nsq'.
+	queueTypeNSQ = "nsq"
+	// Static string indicating queue type 'pubsub'.
+	queueTypePubSub = "pubsub"
+	// Static string indicating queue type 'eventhubs'.
+	queueTypeEventHubs = "eventhubs"
 )
 
 // Topic type.
@@ -56,6 +62,12 @@ const (
 var errNotifyNotEnabled = errors.New("requested notifier not enabled")
 
 // Notifier represents collection of supported notification queues.
+//
+// New queues need a new config version. Fields added to the config of
+// an existing queue, such as the batching of webhook and Kafka targets
+// or the TLS and publisher confirms of AMQP targets, should behave as
+// before when absent instead, configs of previous releases are then
+// loaded unchanged by the current version.
 type notifier struct {
 	AMQP          map[string]amqpNotify          `json:"amqp"`
 	NATS          map[string]natsNotify          `json:"nats"`
@@ -64,6 +76,9 @@ type notifier struct {
 	PostgreSQL    map[string]postgreSQLNotify    `json:"postgresql"`
 	Kafka         map[string]kafkaNotify         `json:"kafka"`
 	Webhook       map[string]webhookNotify       `json:"webhook"`
+	NSQ           map[string]nsqNotify           `json:"nsq"`
+	PubSub        map[string]pubsubNotify        `json:"pubsub"`
+	EventHubs     map[string]eventHubsNotify     `json:"eventhubs"`
 	// Add new notification queues.
 }
 
@@ -213,3 +228,54 @@ func filterRuleMatch(object string, frs []filterRule) bool {
 	}
 	return prefixMatch && suffixMatch
 }
+
+// Returns true if queueArn is for an NSQ queue.
+func isNSQQueue(sqsArn arnSQS) bool {
+	if sqsArn.Type != queueTypeNSQ {
+		return false
+	}
+	nsqL := serverConfig.GetNSQNotifyByID(sqsArn.AccountID)
+	if !nsqL.Enable {
+		return false
+	}
+	// Ping nsqd to validate.
+	if _, err := dialNSQ(nsqL); err != nil {
+		errorIf(err, "Unable to connect to nsqd. %#v", nsqL)
+		return false
+	}
+	return true
+}
+
+// Returns true if queueArn is for a Pub/Sub queue.
+func isPubSubQueue(sqsArn arnSQS) bool {
+	if sqsArn.Type != queueTypePubSub {
+		return false
+	}
+	pubsubL := serverConfig.GetPubSubNotifyByID(sqsArn.AccountID)
+	if !pubsubL.Enable {
+		return false
+	}
+	// Connect to Pub/Sub to validate.
+	if _, err := dialPubSub(pubsubL); err != nil {
+		errorIf(err, "Unable to connect to Pub/Sub. %#v", pubsubL)
+		return false
+	}
+	return true
+}
+
+// Returns true if queueArn is for an Event Hubs queue.
+func isEventHubsQueue(sqsArn arnSQS) bool {
+	if sqsArn.Type != queueTypeEventHubs {
+		return false
+	}
+	eventHubsL := serverConfig.GetEventHubsNotifyByID(sqsArn.AccountID)
+	if !eventHubsL.Enable {
+		return false
+	}
+	// Connect to Event Hubs to validate.
+	if _, err := dialEventHubs(eventHubsL); err != nil {
+		errorIf(err, "Unable to connect to Event Hubs. %#v", eventHubsL)
+		return false
+	}
+	return true
+}
@@ -17,7 +17,14 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/streadway/amqp"
@@ -26,10 +33,11 @@ import (
 // amqpNotify - represents logrus compatible AMQP hook.
 // All fields represent AMQP configuration details.
 type amqpNotify struct {
-	Enable       bool   `json:"enable"`
-	URL          string `json:"url"`
-	Exchange     string `json:"exchange"`
-	RoutingKey   string `json:"routingKey"`
+	Enable     bool   `json:"enable"`
+	URL        string `json:"url"`
+	Exchange   string `json:"exchange"`
+	RoutingKey string `json:"routingKey"`
+	// One of direct, fanout, topic or headers, topic by default.
 	ExchangeType string `json:"exchangeType"`
 	Mandatory    bool   `json:"mandatory"`
 	Immediate    bool   `json:"immediate"`
@@ -37,25 +45,114 @@ type amqpNotify struct {
 	Internal     bool   `json:"internal"`
 	NoWait       bool   `json:"noWait"`
 	AutoDeleted  bool   `json:"autoDeleted"`
+	// TLS of amqps:// URLs.
+	TLS amqpNotifyTLS `json:"tls"`
+	// Wait for the broker to confirm every event, publishing
+	// it again up to MaxRetries times if not confirmed.
+	PublisherConfirms bool `json:"publisherConfirms"`
+	MaxRetries        int  `json:"maxRetries"`
 }
 
+// amqpNotifyTLS - client certificate and server verification of
+// AMQP connections over TLS.
+type amqpNotifyTLS struct {
+	ClientCert string `json:"clientCert"`
+	ClientKey  string `json:"clientKey"`
+	SkipVerify bool   `json:"skipVerify"`
+}
+
+// Exchange types of AMQP brokers.
+const (
+	amqpExchangeDirect  = "direct"
+	amqpExchangeFanout  = "fanout"
+	amqpExchangeTopic   = "topic"
+	amqpExchangeHeaders = "headers"
+)
+
+// Placeholders of routing key templates.
+const (
+	amqpRoutingKeyBucket    = "{bucket}"
+	amqpRoutingKeyObject    = "{object}"
+	amqpRoutingKeyEventName = "{eventName}"
+)
+
+// Time to wait for the confirmation of a published event.
+const amqpConfirmTimeout = 5 * time.Second
+
+var errAMQPNotConfirmed = errors.New("Event not confirmed by the AMQP broker")
+
 type amqpConn struct {
-	params amqpNotify
+	params    amqpNotify
+	tlsConfig *tls.Config
+
+	// Connection redialed once closed by the broker.
+	mutex sync.Mutex
 	*amqp.Connection
 }
 
+// validAMQPExchangeType - returns true if exchangeType is one of the
+// exchange types of the broker.
+func validAMQPExchangeType(exchangeType string) bool {
+	switch exchangeType {
+	case amqpExchangeDirect, amqpExchangeFanout, amqpExchangeTopic, amqpExchangeHeaders:
+		return true
+	}
+	return false
+}
+
+// amqpTLSConfig - returns the TLS configuration of amqps:// URLs,
+// nil for plain connections.
+func amqpTLSConfig(amqpL amqpNotify) (*tls.Config, error) {
+	u, err := url.Parse(amqpL.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "amqps" {
+		return nil, nil
+	}
+	config := &tls.Config{
+		RootCAs:            globalRootCAs,
+		InsecureSkipVerify: amqpL.TLS.SkipVerify,
+	}
+	if amqpL.TLS.ClientCert != "" || amqpL.TLS.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(amqpL.TLS.ClientCert, amqpL.TLS.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
 // dialAMQP - dials and returns an amqpConnection instance,
 // for sending notifications. Returns error if amqp logger
 // is not enabled.
-func dialAMQP(amqpL amqpNotify) (amqpConn, error) {
+func dialAMQP(amqpL amqpNotify) (*amqpConn, error) {
 	if !amqpL.Enable {
-		return amqpConn{}, errNotifyNotEnabled
+		return nil, errNotifyNotEnabled
+	}
+	if amqpL.ExchangeType == "" {
+		amqpL.ExchangeType = amqpExchangeTopic
 	}
-	conn, err := amqp.Dial(amqpL.URL)
+	if !validAMQPExchangeType(amqpL.ExchangeType) || amqpL.MaxRetries < 0 {
+		return nil, errInvalidArgument
+	}
+	tlsConfig, err := amqpTLSConfig(amqpL)
 	if err != nil {
-		return amqpConn{}, err
+		return nil, err
+	}
+	q := &amqpConn{params: amqpL, tlsConfig: tlsConfig}
+	if q.Connection, err = q.dial(); err != nil {
+		return nil, err
 	}
-	return amqpConn{Connection: conn, params: amqpL}, nil
+	return q, nil
+}
+
+func (q *amqpConn) dial() (*amqp.Connection, error) {
+	if q.tlsConfig != nil {
+		return amqp.DialTLS(q.params.URL, q.tlsConfig)
+	}
+	return amqp.Dial(q.params.URL)
 }
 
 func newAMQPNotify(accountID string) (*logrus.Logger, error) {
@@ -82,24 +179,58 @@ func newAMQPNotify(accountID string) (*logrus.Logger, error) {
 	return amqpLog, nil
 }
 
-// Fire is called when an event should be sent to the message broker.
-func (q amqpConn) Fire(entry *logrus.Entry) error {
+// amqpEventFields - returns the bucket, object and event name of
+// the event of entry.
+func amqpEventFields(entry *logrus.Entry) (bucket, object, eventName string) {
+	key, _ := entry.Data["Key"].(string)
+	if i := strings.Index(key, "/"); i >= 0 {
+		bucket, object = key[:i], key[i+1:]
+	} else {
+		bucket = key
+	}
+	if eventType, ok := entry.Data["EventType"].(EventName); ok {
+		eventName = eventType.String()
+	}
+	return bucket, object, eventName
+}
+
+// expandAMQPRoutingKey - replaces the placeholders of routingKey with
+// the bucket, object and event name of the event.
+func expandAMQPRoutingKey(routingKey, bucket, object, eventName string) string {
+	return strings.NewReplacer(
+		amqpRoutingKeyBucket, bucket,
+		amqpRoutingKeyObject, object,
+		amqpRoutingKeyEventName, eventName,
+	).Replace(routingKey)
+}
+
+// channel - opens a channel, dialing the broker again if the
+// connection was closed.
+func (q *amqpConn) channel() (*amqp.Channel, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 	ch, err := q.Connection.Channel()
+	if err == nil {
+		return ch, nil
+	}
+	// Any other error other than connection closed, return.
+	if err != amqp.ErrClosed {
+		return nil, err
+	}
+	// Attempt to connect again.
+	conn, err := q.dial()
 	if err != nil {
-		// Any other error other than connection closed, return.
-		if err != amqp.ErrClosed {
-			return err
-		}
-		// Attempt to connect again.
-		var conn *amqp.Connection
-		conn, err = amqp.Dial(q.params.URL)
-		if err != nil {
-			return err
-		}
-		ch, err = conn.Channel()
-		if err != nil {
-			return err
-		}
+		return nil, err
+	}
+	q.Connection = conn
+	return conn.Channel()
+}
+
+// Fire is called when an event should be sent to the message broker.
+func (q *amqpConn) Fire(entry *logrus.Entry) error {
+	ch, err := q.channel()
+	if err != nil {
+		return err
 	}
 	defer ch.Close()
 
@@ -121,24 +252,58 @@ func (q amqpConn) Fire(entry *logrus.Entry) error {
 		return err
 	}
 
-	err = ch.Publish(
-		q.params.Exchange,
-		q.params.RoutingKey,
-		q.params.Mandatory,
-		q.params.Immediate,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        []byte(body),
-		})
-	if err != nil {
-		return err
+	bucket, object, eventName := amqpEventFields(entry)
+	publishing := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        []byte(body),
+	}
+	publishing.MessageId, _ = entry.Data["EventID"].(string)
+	if q.params.ExchangeType == amqpExchangeHeaders {
+		// Headers exchanges route on headers rather than routing keys.
+		publishing.Headers = amqp.Table{
+			"bucket":    bucket,
+			"object":    object,
+			"eventName": eventName,
+		}
+	}
+	routingKey := expandAMQPRoutingKey(q.params.RoutingKey, bucket, object, eventName)
+
+	if !q.params.PublisherConfirms {
+		return ch.Publish(q.params.Exchange, routingKey, q.params.Mandatory, q.params.Immediate, publishing)
 	}
 
-	return nil
+	if err = ch.Confirm(false); err != nil {
+		return err
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	for i := 0; ; i++ {
+		err = ch.Publish(q.params.Exchange, routingKey, q.params.Mandatory, q.params.Immediate, publishing)
+		if err != nil {
+			return err
+		}
+		select {
+		case confirm, ok := <-confirms:
+			if !ok {
+				// Channel closed by the broker.
+				return amqp.ErrClosed
+			}
+			if confirm.Ack {
+				return nil
+			}
+			err = errAMQPNotConfirmed
+		case <-time.After(amqpConfirmTimeout):
+			// Confirmations arrive in order, so a late one could be
+			// mistaken for the next publishing, give up.
+			return fmt.Errorf("Event not confirmed by the AMQP broker within %s", amqpConfirmTimeout)
+		}
+		if i >= q.params.MaxRetries {
+			return err
+		}
+	}
 }
 
 // Levels is available logging levels.
-func (q amqpConn) Levels() []logrus.Level {
+func (q *amqpConn) Levels() []logrus.Level {
 	return []logrus.Level{
 		logrus.InfoLevel,
 	}
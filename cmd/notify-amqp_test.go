@@ -0,0 +1,72 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestExpandAMQPRoutingKey(t *testing.T) {
+	entry := logrus.NewEntry(logrus.New()).WithFields(logrus.Fields{
+		"Key":       "photos/2017/january.jpg",
+		"EventType": ObjectCreatedPut,
+	})
+	bucket, object, eventName := amqpEventFields(entry)
+	if bucket != "photos" || object != "2017/january.jpg" || eventName != "s3:ObjectCreated:Put" {
+		t.Fatalf("Unexpected event fields %s, %s, %s", bucket, object, eventName)
+	}
+
+	testCases := []struct {
+		routingKey string
+		expected   string
+	}{
+		{"minio", "minio"},
+		{"minio.{bucket}", "minio.photos"},
+		{"{bucket}/{object}", "photos/2017/january.jpg"},
+		{"{eventName}.{bucket}.{bucket}", "s3:ObjectCreated:Put.photos.photos"},
+	}
+	for i, testCase := range testCases {
+		if key := expandAMQPRoutingKey(testCase.routingKey, bucket, object, eventName); key != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, key)
+		}
+	}
+}
+
+func TestDialAMQPInvalidArguments(t *testing.T) {
+	testCases := []amqpNotify{
+		{Enable: true, URL: "amqp://localhost:5672", ExchangeType: "broadcast"},
+		{Enable: true, URL: "amqp://localhost:5672", MaxRetries: -1},
+	}
+	for i, amqpL := range testCases {
+		if _, err := dialAMQP(amqpL); err != errInvalidArgument {
+			t.Errorf("Test %d: expected %v, got %v", i+1, errInvalidArgument, err)
+		}
+	}
+	if _, err := dialAMQP(amqpNotify{}); err != errNotifyNotEnabled {
+		t.Errorf("Expected %v, got %v", errNotifyNotEnabled, err)
+	}
+	amqpL := amqpNotify{
+		Enable: true,
+		URL:    "amqps://localhost:5671",
+		TLS:    amqpNotifyTLS{ClientCert: "missing.crt", ClientKey: "missing.key"},
+	}
+	if _, err := dialAMQP(amqpL); err == nil {
+		t.Error("Expected missing client certificate to fail")
+	}
+}
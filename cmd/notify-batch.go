@@ -0,0 +1,140 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// notifyBatch - options of targets sending several events per message.
+type notifyBatch struct {
+	// Events sent per message, events are sent one per message when
+	// below 2.
+	MaxEvents int `json:"maxEvents"`
+	// Longest time a partial batch waits for more events, such as
+	// "500ms", 1s when empty.
+	FlushInterval string `json:"flushInterval"`
+}
+
+// Default time a partial batch waits for more events.
+const defaultNotifyFlushInterval = time.Second
+
+// eventBatcher - logrus hook accumulating the events of a target,
+// sent as a JSON array once MaxEvents are accumulated or FlushInterval
+// after the first event of the batch.
+type eventBatcher struct {
+	send      func(payload []byte) error
+	maxEvents int
+	interval  time.Duration
+
+	// Held while sending, so that batches are sent in order.
+	mutex  sync.Mutex
+	events [][]byte
+	timer  *time.Timer
+}
+
+// newEventBatcher - returns a hook sending the events in batches with
+// send, nil if batch does not batch events.
+func newEventBatcher(batch notifyBatch, send func(payload []byte) error) (*eventBatcher, error) {
+	if batch.MaxEvents < 2 {
+		return nil, nil
+	}
+	interval := defaultNotifyFlushInterval
+	if batch.FlushInterval != "" {
+		var err error
+		if interval, err = time.ParseDuration(batch.FlushInterval); err != nil {
+			return nil, err
+		}
+		if interval <= 0 {
+			return nil, errInvalidArgument
+		}
+	}
+	return &eventBatcher{
+		send:      send,
+		maxEvents: batch.MaxEvents,
+		interval:  interval,
+	}, nil
+}
+
+// Fire - adds the event to the batch, sending it once full.
+func (b *eventBatcher) Fire(entry *logrus.Entry) error {
+	body, err := entry.Reader()
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.events = append(b.events, bytes.TrimSpace(body.Bytes()))
+	if len(b.events) < b.maxEvents {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.interval, b.flush)
+		}
+		return nil
+	}
+	return b.sendEvents()
+}
+
+// flush - sends the partial batch once FlushInterval elapsed.
+func (b *eventBatcher) flush() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	errorIf(b.sendEvents(), "Unable to send a batch of events.")
+}
+
+// sendEvents - sends the events of the batch as a JSON array, called
+// with mutex held.
+func (b *eventBatcher) sendEvents() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.events) == 0 {
+		return nil
+	}
+	payload := make([]byte, 0, 2+len(b.events)*len(b.events[0]))
+	payload = append(payload, '[')
+	payload = append(payload, bytes.Join(b.events, []byte(","))...)
+	payload = append(payload, ']')
+	b.events = nil
+	return b.send(payload)
+}
+
+// Levels - to implement logrus.Hook interface
+func (b *eventBatcher) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.InfoLevel,
+	}
+}
+
+// gzipPayload - returns payload compressed with gzip.
+func gzipPayload(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// batchHandler - records the events of the batches posted.
+type batchHandler struct {
+	mutex   sync.Mutex
+	batches [][]map[string]interface{}
+}
+
+func (b *batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		http.Error(w, "Expected a gzip payload", http.StatusBadRequest)
+		return
+	}
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var batch []map[string]interface{}
+	if err = json.NewDecoder(zr).Decode(&batch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.mutex.Lock()
+	b.batches = append(b.batches, batch)
+	b.mutex.Unlock()
+}
+
+func (b *batchHandler) sizes() (sizes []int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, batch := range b.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+// Tests sending compressed batches of events to a webhook.
+func TestEventBatcher(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if batcher, err := newEventBatcher(notifyBatch{MaxEvents: 1}, nil); batcher != nil || err != nil {
+		t.Fatalf("Expected no batching of single events, got %v, %v", batcher, err)
+	}
+	if _, err = newEventBatcher(notifyBatch{MaxEvents: 2, FlushInterval: "soon"}, nil); err == nil {
+		t.Fatal("Expected an invalid flush interval to fail")
+	}
+
+	handler := &batchHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	serverConfig.SetWebhookNotifyByID("batch", webhookNotify{
+		Enable:   true,
+		Endpoint: server.URL,
+		Batch:    notifyBatch{MaxEvents: 3, FlushInterval: "50ms"},
+		Compress: true,
+	})
+	webhook, err := newWebhookNotify("batch")
+	if err != nil {
+		t.Fatal("Unexpected shouldn't fail", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		webhook.WithFields(logrus.Fields{
+			"Key":       path.Join("bucket", "object"),
+			"EventType": "s3:ObjectCreated:Put",
+		}).Info()
+	}
+	if sizes := handler.sizes(); len(sizes) != 1 || sizes[0] != 3 {
+		t.Fatalf("Expected a full batch of 3 events, got %v", sizes)
+	}
+
+	// The partial batch is sent once the flush interval elapsed.
+	for deadline := time.Now().Add(5 * time.Second); len(handler.sizes()) < 2 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sizes := handler.sizes(); len(sizes) != 2 || sizes[1] != 1 {
+		t.Fatalf("Expected a partial batch of 1 event, got %v", sizes)
+	}
+	if key := handler.batches[0][0]["Key"]; key != "bucket/object" {
+		t.Fatalf("Unexpected event %v", handler.batches[0][0])
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// eventHubsNotify - represents logrus compatible Azure Event Hubs hook.
+// Events are sent through the HTTPS API of the namespace, authenticated
+// with shared access signatures of a policy with Send rights.
+type eventHubsNotify struct {
+	Enable bool `json:"enable"`
+	// Connection string of the shared access policy, as shown by the
+	// Azure portal, Endpoint=sb://<namespace>.servicebus.windows.net/;
+	// SharedAccessKeyName=<policy>;SharedAccessKey=<key>[;EntityPath=<hub>]
+	ConnectionString string `json:"connectionString"`
+	// Event hub, EntityPath of the connection string by default.
+	EventHub string `json:"eventHub"`
+}
+
+// Validity of the shared access signatures.
+const eventHubsSASExpiry = time.Hour
+
+// eventHubsConn - sends the events to an event hub.
+type eventHubsConn struct {
+	*http.Client
+	// https://<namespace>.servicebus.windows.net/<hub>
+	resourceURI string
+	keyName     string
+	key         string
+}
+
+// parseEventHubsConnectionString - returns the endpoint, shared access
+// policy, key and event hub of an Event Hubs connection string.
+func parseEventHubsConnectionString(connStr string) (endpoint *url.URL, keyName, key, eventHub string, err error) {
+	for _, field := range strings.Split(connStr, ";") {
+		i := strings.Index(field, "=")
+		if i < 0 {
+			continue
+		}
+		value := field[i+1:]
+		switch strings.ToLower(field[:i]) {
+		case "endpoint":
+			if endpoint, err = url.Parse(value); err != nil {
+				return nil, "", "", "", err
+			}
+		case "sharedaccesskeyname":
+			keyName = value
+		case "sharedaccesskey":
+			key = value
+		case "entitypath":
+			eventHub = value
+		}
+	}
+	if endpoint == nil || endpoint.Host == "" || keyName == "" || key == "" {
+		return nil, "", "", "", errInvalidArgument
+	}
+	return endpoint, keyName, key, eventHub, nil
+}
+
+// dialEventHubs - validates the connection string and dials the
+// namespace, returns an eventHubsConn for sending notifications.
+// Returns error if the Event Hubs target is not enabled.
+func dialEventHubs(eventHubsL eventHubsNotify) (eventHubsConn, error) {
+	if !eventHubsL.Enable {
+		return eventHubsConn{}, errNotifyNotEnabled
+	}
+	endpoint, keyName, key, eventHub, err := parseEventHubsConnectionString(eventHubsL.ConnectionString)
+	if err != nil {
+		return eventHubsConn{}, err
+	}
+	if eventHubsL.EventHub != "" {
+		eventHub = eventHubsL.EventHub
+	}
+	if eventHub == "" {
+		return eventHubsConn{}, errInvalidArgument
+	}
+
+	// Namespaces are reached over HTTPS whatever the scheme, sb://
+	// usually, of the connection string.
+	u := &url.URL{Scheme: "https", Host: endpoint.Host, Path: "/" + eventHub}
+	if err = lookupEndpoint(u); err != nil {
+		return eventHubsConn{}, err
+	}
+
+	return eventHubsConn{
+		// Configure aggressive timeouts for client posts.
+		Client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSClientConfig:       &tls.Config{RootCAs: globalRootCAs},
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		resourceURI: u.String(),
+		keyName:     keyName,
+		key:         key,
+	}, nil
+}
+
+// sharedAccessSignature - returns a shared access signature of the
+// event hub valid until expiry.
+func (n eventHubsConn) sharedAccessSignature(expiry time.Time) string {
+	resource := url.QueryEscape(n.resourceURI)
+	se := strconv.FormatInt(expiry.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(n.key))
+	mac.Write([]byte(resource + "\n" + se))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("SharedAccessSignature sr=%s&sig=%s&se=%s&skn=%s",
+		resource, url.QueryEscape(sig), se, url.QueryEscape(n.keyName))
+}
+
+// Initializes new Event Hubs logrus notifier.
+func newEventHubsNotify(accountID string) (*logrus.Logger, error) {
+	eventHubsL := serverConfig.GetEventHubsNotifyByID(accountID)
+	conn, err := dialEventHubs(eventHubsL)
+	if err != nil {
+		return nil, err
+	}
+
+	eventHubsLog := logrus.New()
+	eventHubsLog.Out = ioutil.Discard
+
+	// Set default JSON formatter.
+	eventHubsLog.Formatter = new(logrus.JSONFormatter)
+
+	eventHubsLog.Hooks.Add(conn)
+
+	// Success
+	return eventHubsLog, nil
+}
+
+// Fire is called when an event should be sent to the message broker.
+func (n eventHubsConn) Fire(entry *logrus.Entry) error {
+	body, err := entry.Reader()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.resourceURI+"/messages?api-version=2014-01", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", n.sharedAccessSignature(time.Now().UTC().Add(eventHubsSASExpiry)))
+	req.Header.Set("Content-Type", "application/atom+xml;type=entry;charset=utf-8")
+
+	// Set proper server user-agent.
+	req.Header.Set("User-Agent", globalServerUserAgent)
+
+	resp, err := n.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unable to send event %s", resp.Status)
+	}
+	return nil
+}
+
+// Levels are Required for logrus hook implementation
+func (eventHubsConn) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.InfoLevel,
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// eventHubsHandler - fakes the send API of an Event Hubs namespace,
+// verifying the shared access signatures.
+type eventHubsHandler struct {
+	key      string
+	mutex    sync.Mutex
+	messages map[string][]string
+}
+
+func (e *eventHubsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || !strings.HasSuffix(r.URL.Path, "/messages") {
+		http.NotFound(w, r)
+		return
+	}
+	sas, err := url.ParseQuery(strings.TrimPrefix(r.Header.Get("Authorization"), "SharedAccessSignature "))
+	if err != nil || sas.Get("skn") != "send" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(e.key))
+	mac.Write([]byte(url.QueryEscape(sas.Get("sr")) + "\n" + sas.Get("se")))
+	if sas.Get("sig") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.mutex.Lock()
+	eventHub := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/messages")
+	e.messages[eventHub] = append(e.messages[eventHub], string(body))
+	e.mutex.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Tests parsing of Event Hubs connection strings.
+func TestParseEventHubsConnectionString(t *testing.T) {
+	testCases := []struct {
+		connStr  string
+		host     string
+		keyName  string
+		key      string
+		eventHub string
+		err      error
+	}{
+		{"Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=a2V5=", "ns.servicebus.windows.net", "send", "a2V5=", "", nil},
+		{"Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=key;EntityPath=events", "ns.servicebus.windows.net", "send", "key", "events", nil},
+		{"Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=send", "", "", "", "", errInvalidArgument},
+		{"SharedAccessKeyName=send;SharedAccessKey=key", "", "", "", "", errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		endpoint, keyName, key, eventHub, err := parseEventHubsConnectionString(testCase.connStr)
+		if err != testCase.err {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if endpoint.Host != testCase.host || keyName != testCase.keyName || key != testCase.key || eventHub != testCase.eventHub {
+			t.Errorf("Test %d: Unexpected %s, %s, %s, %s", i+1, endpoint.Host, keyName, key, eventHub)
+		}
+	}
+}
+
+// Tests Event Hubs initialization and sending.
+func TestNewEventHubsNotify(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if _, err = newEventHubsNotify("1"); err != errNotifyNotEnabled {
+		t.Fatalf("Expected %s, got %v", errNotifyNotEnabled, err)
+	}
+
+	handler := &eventHubsHandler{key: "c2VjcmV0", messages: make(map[string][]string)}
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	defer func(rootCAs *x509.CertPool) { globalRootCAs = rootCAs }(globalRootCAs)
+	globalRootCAs = x509.NewCertPool()
+	globalRootCAs.AddCert(server.Certificate())
+
+	connStr := "Endpoint=sb://" + strings.TrimPrefix(server.URL, "https://") + "/;SharedAccessKeyName=send;SharedAccessKey=" + handler.key
+
+	serverConfig.SetEventHubsNotifyByID("10", eventHubsNotify{Enable: true, ConnectionString: connStr})
+	if _, err = newEventHubsNotify("10"); err != errInvalidArgument {
+		t.Fatalf("Expected %s without an event hub, got %v", errInvalidArgument, err)
+	}
+
+	serverConfig.SetEventHubsNotifyByID("20", eventHubsNotify{Enable: true, ConnectionString: connStr + ";EntityPath=other", EventHub: "events"})
+	eventHubs, err := newEventHubsNotify("20")
+	if err != nil {
+		t.Fatal("Unexpected shouldn't fail", err)
+	}
+
+	eventHubs.WithFields(logrus.Fields{
+		"Key":       path.Join("bucket", "object"),
+		"EventType": "s3:ObjectCreated:Put",
+	}).Info()
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	if len(handler.messages["events"]) != 1 || !strings.Contains(handler.messages["events"][0], "s3:ObjectCreated:Put") {
+		t.Fatalf("Expected the event to be sent to the event hub, got %v", handler.messages)
+	}
+}
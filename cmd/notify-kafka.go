@@ -37,6 +37,12 @@ type kafkaNotify struct {
 
 	// Topic to which event notifications should be sent.
 	Topic string `json:"topic"`
+
+	// Several events per message, sent as a JSON array.
+	Batch notifyBatch `json:"batch"`
+
+	// Compress the messages with gzip.
+	Compress bool `json:"compress"`
 }
 
 // kafkaConn contains the active connection to the Kafka cluster and
@@ -62,6 +68,10 @@ func dialKafka(kn kafkaNotify) (kafkaConn, error) {
 	// Retry up to 10 times to produce the message
 	config.Producer.Retry.Max = 10
 	config.Producer.Return.Successes = true
+	if kn.Compress {
+		// Consumers decompress the messages transparently.
+		config.Producer.Compression = sarama.CompressionGZIP
+	}
 
 	p, err := sarama.NewSyncProducer(kn.Brokers, config)
 	if err != nil {
@@ -83,11 +93,23 @@ func newKafkaNotify(accountID string) (*logrus.Logger, error) {
 		return nil, err
 	}
 
+	batcher, err := newEventBatcher(kafkaNotifyCfg.Batch, func(payload []byte) error {
+		return kc.send(payload, "")
+	})
+	if err != nil {
+		kc.Close()
+		return nil, err
+	}
+
 	// Configure kafkaConn object as a Hook in logrus.
 	kafkaLog := logrus.New()
 	kafkaLog.Out = ioutil.Discard
 	kafkaLog.Formatter = new(logrus.JSONFormatter)
-	kafkaLog.Hooks.Add(kc)
+	if batcher != nil {
+		kafkaLog.Hooks.Add(batcher)
+	} else {
+		kafkaLog.Hooks.Add(kc)
+	}
 
 	return kafkaLog, nil
 }
@@ -102,15 +124,24 @@ func (kC kafkaConn) Fire(entry *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
+	eventID, _ := entry.Data["EventID"].(string)
+	return kC.send(body.Bytes(), eventID)
+}
 
+// send - sends the payload of one or more events to Kafka, keyed by
+// the ID of a single event.
+func (kC kafkaConn) send(payload []byte, eventID string) error {
 	// Construct message to send to Kafka
 	msg := sarama.ProducerMessage{
 		Topic: kC.topic,
-		Value: sarama.ByteEncoder(body.Bytes()),
+		Value: sarama.ByteEncoder(payload),
+	}
+	if eventID != "" {
+		msg.Key = sarama.StringEncoder(eventID)
 	}
 
 	// Attempt sending the message to Kafka
-	_, _, err = kC.producer.SendMessage(&msg)
+	_, _, err := kC.producer.SendMessage(&msg)
 	if err != nil {
 		return fmt.Errorf("Error sending event to Kafka - %v", err)
 	}
@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// nsqNotifyTLS - TLS options of the connection to nsqd.
+type nsqNotifyTLS struct {
+	Enable     bool `json:"enable"`
+	SkipVerify bool `json:"skipVerify"`
+}
+
+// nsqNotify - represents logrus compatible NSQ hook. Events are
+// published to the topic through the HTTP API of nsqd, NSQDAddress
+// is the host:port of its HTTP or, with TLS, HTTPS listener.
+type nsqNotify struct {
+	Enable      bool         `json:"enable"`
+	NSQDAddress string       `json:"nsqdAddress"`
+	Topic       string       `json:"topic"`
+	TLS         nsqNotifyTLS `json:"tls"`
+}
+
+// Valid NSQ topic names, as enforced by nsqd.
+var validNSQTopic = regexp.MustCompile(`^[\.a-zA-Z0-9_-]{1,64}$`)
+
+// nsqConn - publishes the events to a topic of nsqd.
+type nsqConn struct {
+	*http.Client
+	params  nsqNotify
+	baseURL string
+}
+
+// dialNSQ - validates the NSQ target and pings nsqd, returns an
+// nsqConn for sending notifications. Returns error if the NSQ target
+// is not enabled.
+func dialNSQ(nsqL nsqNotify) (nsqConn, error) {
+	if !nsqL.Enable {
+		return nsqConn{}, errNotifyNotEnabled
+	}
+	if nsqL.NSQDAddress == "" || !validNSQTopic.MatchString(nsqL.Topic) {
+		return nsqConn{}, errInvalidArgument
+	}
+
+	scheme := "http"
+	if nsqL.TLS.Enable {
+		scheme = "https"
+	}
+	conn := nsqConn{
+		// Configure aggressive timeouts for client posts.
+		Client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSClientConfig: &tls.Config{
+					RootCAs:            globalRootCAs,
+					InsecureSkipVerify: nsqL.TLS.SkipVerify,
+				},
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		params:  nsqL,
+		baseURL: scheme + "://" + nsqL.NSQDAddress,
+	}
+
+	resp, err := conn.Get(conn.baseURL + "/ping")
+	if err != nil {
+		return nsqConn{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nsqConn{}, fmt.Errorf("Unable to ping nsqd %s", resp.Status)
+	}
+	return conn, nil
+}
+
+// Initializes new NSQ logrus notifier.
+func newNSQNotify(accountID string) (*logrus.Logger, error) {
+	nsqL := serverConfig.GetNSQNotifyByID(accountID)
+	conn, err := dialNSQ(nsqL)
+	if err != nil {
+		return nil, err
+	}
+
+	nsqLog := logrus.New()
+	nsqLog.Out = ioutil.Discard
+
+	// Set default JSON formatter.
+	nsqLog.Formatter = new(logrus.JSONFormatter)
+
+	nsqLog.Hooks.Add(conn)
+
+	// Success
+	return nsqLog, nil
+}
+
+// Fire is called when an event should be sent to the message broker.
+func (n nsqConn) Fire(entry *logrus.Entry) error {
+	body, err := entry.Reader()
+	if err != nil {
+		return err
+	}
+
+	pubURL := n.baseURL + "/pub?" + url.Values{"topic": []string{n.params.Topic}}.Encode()
+	req, err := http.NewRequest("POST", pubURL, body)
+	if err != nil {
+		return err
+	}
+
+	// Set proper server user-agent.
+	req.Header.Set("User-Agent", globalServerUserAgent)
+
+	resp, err := n.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unable to publish event %s", resp.Status)
+	}
+	return nil
+}
+
+// Levels are Required for logrus hook implementation
+func (nsqConn) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.InfoLevel,
+	}
+}
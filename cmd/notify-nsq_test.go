@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// nsqdHandler - fakes the ping and publish HTTP API of nsqd.
+type nsqdHandler struct {
+	mutex    sync.Mutex
+	messages map[string][]string
+}
+
+func (n *nsqdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/ping":
+	case r.Method == "POST" && r.URL.Path == "/pub":
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n.mutex.Lock()
+		topic := r.URL.Query().Get("topic")
+		n.messages[topic] = append(n.messages[topic], string(body))
+		n.mutex.Unlock()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Write([]byte("OK"))
+}
+
+// Tests NSQ initialization and publishing.
+func TestNewNSQNotify(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if _, err = newNSQNotify("1"); err != errNotifyNotEnabled {
+		t.Fatalf("Expected %s, got %v", errNotifyNotEnabled, err)
+	}
+
+	handler := &nsqdHandler{messages: make(map[string][]string)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	serverConfig.SetNSQNotifyByID("10", nsqNotify{Enable: true, NSQDAddress: address, Topic: "bad topic"})
+	if _, err = newNSQNotify("10"); err != errInvalidArgument {
+		t.Fatalf("Expected %s for an invalid topic, got %v", errInvalidArgument, err)
+	}
+
+	serverConfig.SetNSQNotifyByID("15", nsqNotify{Enable: true, NSQDAddress: address, Topic: "events", TLS: nsqNotifyTLS{Enable: true}})
+	if _, err = newNSQNotify("15"); err == nil {
+		t.Fatal("Unexpected should fail with TLS to a plain HTTP nsqd")
+	}
+
+	serverConfig.SetNSQNotifyByID("20", nsqNotify{Enable: true, NSQDAddress: address, Topic: "events"})
+	nsq, err := newNSQNotify("20")
+	if err != nil {
+		t.Fatal("Unexpected shouldn't fail", err)
+	}
+	if !isNSQQueue(arnSQS{Type: queueTypeNSQ, AccountID: "20"}) {
+		t.Fatal("Expected the NSQ queue to be valid")
+	}
+
+	nsq.WithFields(logrus.Fields{
+		"Key":       path.Join("bucket", "object"),
+		"EventType": "s3:ObjectCreated:Put",
+	}).Info()
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	if len(handler.messages["events"]) != 1 || !strings.Contains(handler.messages["events"][0], "s3:ObjectCreated:Put") {
+		t.Fatalf("Expected the event to be published to the topic, got %v", handler.messages)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// Pub/Sub API endpoint.
+	pubsubDefaultEndpoint = "https://pubsub.googleapis.com"
+	// OAuth2 token endpoint of Google service accounts.
+	pubsubDefaultTokenURI = "https://oauth2.googleapis.com/token"
+	// OAuth2 scope of the access tokens.
+	pubsubScope = "https://www.googleapis.com/auth/pubsub"
+)
+
+// pubsubNotify - represents logrus compatible Google Cloud Pub/Sub
+// hook. Events are published to Topic of Project, Project of the
+// service account by default, authenticated with the JSON key of the
+// service account in CredentialsFile.
+type pubsubNotify struct {
+	Enable          bool   `json:"enable"`
+	Project         string `json:"project"`
+	Topic           string `json:"topic"`
+	CredentialsFile string `json:"credentialsFile"`
+	// Pub/Sub API endpoint, https://pubsub.googleapis.com by default.
+	Endpoint string `json:"endpoint"`
+}
+
+// pubsubCredentials - the fields of a service account JSON key used to
+// authenticate.
+type pubsubCredentials struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// pubsubConn - publishes the events to a Pub/Sub topic, refreshing its
+// access token before it expires.
+type pubsubConn struct {
+	*http.Client
+	publishURL string
+	creds      pubsubCredentials
+	key        *rsa.PrivateKey
+
+	mutex       sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// dialPubSub - loads the service account key and fetches an access
+// token, returns a pubsubConn for sending notifications. Returns error
+// if the Pub/Sub target is not enabled.
+func dialPubSub(pubsubL pubsubNotify) (*pubsubConn, error) {
+	if !pubsubL.Enable {
+		return nil, errNotifyNotEnabled
+	}
+	if pubsubL.Topic == "" || pubsubL.CredentialsFile == "" {
+		return nil, errInvalidArgument
+	}
+
+	credsBytes, err := ioutil.ReadFile(pubsubL.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	var creds pubsubCredentials
+	if err = json.Unmarshal(credsBytes, &creds); err != nil {
+		return nil, fmt.Errorf("Unable to parse the service account key. %s", err)
+	}
+	if creds.ClientEmail == "" {
+		return nil, errInvalidArgument
+	}
+	key, err := jwtgo.ParseRSAPrivateKeyFromPEM([]byte(creds.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the private key of the service account. %s", err)
+	}
+	if creds.TokenURI == "" {
+		creds.TokenURI = pubsubDefaultTokenURI
+	}
+
+	project := pubsubL.Project
+	if project == "" {
+		project = creds.ProjectID
+	}
+	if project == "" {
+		return nil, errInvalidArgument
+	}
+	endpoint := pubsubL.Endpoint
+	if endpoint == "" {
+		endpoint = pubsubDefaultEndpoint
+	}
+
+	conn := &pubsubConn{
+		// Configure aggressive timeouts for client posts.
+		Client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		publishURL: endpoint + "/v1/projects/" + url.PathEscape(project) + "/topics/" + url.PathEscape(pubsubL.Topic) + ":publish",
+		creds:      creds,
+		key:        key,
+	}
+
+	// Validate the credentials.
+	if _, err = conn.token(); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// token - returns the access token, exchanging a JWT signed by the
+// service account for a new one when it expires in less than a minute.
+func (n *pubsubConn) token() (string, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	now := time.Now().UTC()
+	if n.accessToken != "" && now.Add(time.Minute).Before(n.expiry) {
+		return n.accessToken, nil
+	}
+
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, jwtgo.MapClaims{
+		"iss":   n.creds.ClientEmail,
+		"scope": pubsubScope,
+		"aud":   n.creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	jwt.Header["kid"] = n.creds.PrivateKeyID
+	assertion, err := jwt.SignedString(n.key)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := n.PostForm(n.creds.TokenURI, url.Values{
+		"grant_type": []string{"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  []string{assertion},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Unable to fetch a Pub/Sub access token %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("Unable to fetch a Pub/Sub access token, none returned")
+	}
+	n.accessToken = token.AccessToken
+	n.expiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return n.accessToken, nil
+}
+
+// Initializes new Pub/Sub logrus notifier.
+func newPubSubNotify(accountID string) (*logrus.Logger, error) {
+	pubsubL := serverConfig.GetPubSubNotifyByID(accountID)
+	conn, err := dialPubSub(pubsubL)
+	if err != nil {
+		return nil, err
+	}
+
+	pubsubLog := logrus.New()
+	pubsubLog.Out = ioutil.Discard
+
+	// Set default JSON formatter.
+	pubsubLog.Formatter = new(logrus.JSONFormatter)
+
+	pubsubLog.Hooks.Add(conn)
+
+	// Success
+	return pubsubLog, nil
+}
+
+// Fire is called when an event should be sent to the message broker.
+func (n *pubsubConn) Fire(entry *logrus.Entry) error {
+	entryBytes, err := entry.Reader()
+	if err != nil {
+		return err
+	}
+
+	type pubsubMessage struct {
+		Data string `json:"data"`
+	}
+	body, err := json.Marshal(struct {
+		Messages []pubsubMessage `json:"messages"`
+	}{
+		Messages: []pubsubMessage{{Data: base64.StdEncoding.EncodeToString(entryBytes.Bytes())}},
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := n.token()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.publishURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	// Set proper server user-agent.
+	req.Header.Set("User-Agent", globalServerUserAgent)
+
+	resp, err := n.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unable to publish event %s", resp.Status)
+	}
+	return nil
+}
+
+// Levels are Required for logrus hook implementation
+func (*pubsubConn) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.InfoLevel,
+	}
+}
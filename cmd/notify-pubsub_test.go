@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// pubsubHandler - fakes the token endpoint of service accounts and the
+// publish API of Pub/Sub.
+type pubsubHandler struct {
+	key      *rsa.PublicKey
+	mutex    sync.Mutex
+	tokens   int
+	messages []string
+}
+
+func (p *pubsubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/token":
+		_, err := jwtgo.Parse(r.FormValue("assertion"), func(token *jwtgo.Token) (interface{}, error) {
+			return p.key, nil
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		p.mutex.Lock()
+		p.tokens++
+		p.mutex.Unlock()
+		w.Write([]byte(`{"access_token":"secret-token","expires_in":3600,"token_type":"Bearer"}`))
+	case "/v1/projects/myproject/topics/events:publish":
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Messages []struct {
+				Data string `json:"data"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		for _, message := range body.Messages {
+			data, err := base64.StdEncoding.DecodeString(message.Data)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			p.messages = append(p.messages, string(data))
+		}
+		w.Write([]byte(`{"messageIds":["1"]}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Tests Pub/Sub initialization and publishing.
+func TestNewPubSubNotify(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if _, err = newPubSubNotify("1"); err != errNotifyNotEnabled {
+		t.Fatalf("Expected %s, got %v", errNotifyNotEnabled, err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &pubsubHandler{key: &key.PublicKey}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	credsBytes, err := json.Marshal(pubsubCredentials{
+		ProjectID:    "myproject",
+		PrivateKeyID: "1",
+		PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		ClientEmail:  "minio@myproject.iam.gserviceaccount.com",
+		TokenURI:     server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credsFile := filepath.Join(root, "pubsub.json")
+	if err = ioutil.WriteFile(credsFile, credsBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	serverConfig.SetPubSubNotifyByID("10", pubsubNotify{Enable: true, Topic: "events", CredentialsFile: filepath.Join(root, "missing.json")})
+	if _, err = newPubSubNotify("10"); err == nil {
+		t.Fatal("Unexpected should fail with a missing credentials file")
+	}
+
+	serverConfig.SetPubSubNotifyByID("15", pubsubNotify{Enable: true, CredentialsFile: credsFile})
+	if _, err = newPubSubNotify("15"); err != errInvalidArgument {
+		t.Fatalf("Expected %s without a topic, got %v", errInvalidArgument, err)
+	}
+
+	serverConfig.SetPubSubNotifyByID("20", pubsubNotify{Enable: true, Topic: "events", CredentialsFile: credsFile, Endpoint: server.URL})
+	pubsub, err := newPubSubNotify("20")
+	if err != nil {
+		t.Fatal("Unexpected shouldn't fail", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		pubsub.WithFields(logrus.Fields{
+			"Key":       path.Join("bucket", "object"),
+			"EventType": "s3:ObjectCreated:Put",
+		}).Info()
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	if len(handler.messages) != 2 || !strings.Contains(handler.messages[0], "s3:ObjectCreated:Put") {
+		t.Fatalf("Expected the events to be published to the topic, got %v", handler.messages)
+	}
+	if handler.tokens != 1 {
+		t.Fatalf("Expected the access token to be fetched once, fetched %d times", handler.tokens)
+	}
+}
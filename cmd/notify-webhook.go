@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -30,11 +31,16 @@ import (
 type webhookNotify struct {
 	Enable   bool   `json:"enable"`
 	Endpoint string `json:"endpoint"`
+	// Several events per request, sent as a JSON array.
+	Batch notifyBatch `json:"batch"`
+	// Compress the requests with gzip.
+	Compress bool `json:"compress"`
 }
 
 type httpConn struct {
 	*http.Client
 	Endpoint string
+	Compress bool
 }
 
 // Lookup endpoint address by successfully dialing.
@@ -81,6 +87,13 @@ func newWebhookNotify(accountID string) (*logrus.Logger, error) {
 			},
 		},
 		Endpoint: rNotify.Endpoint,
+		Compress: rNotify.Compress,
+	}
+	batcher, err := newEventBatcher(rNotify.Batch, func(payload []byte) error {
+		return conn.post(payload, "")
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	notifyLog := logrus.New()
@@ -89,7 +102,11 @@ func newWebhookNotify(accountID string) (*logrus.Logger, error) {
 	// Set default JSON formatter.
 	notifyLog.Formatter = new(logrus.JSONFormatter)
 
-	notifyLog.Hooks.Add(conn)
+	if batcher != nil {
+		notifyLog.Hooks.Add(batcher)
+	} else {
+		notifyLog.Hooks.Add(conn)
+	}
 
 	// Success
 	return notifyLog, nil
@@ -101,14 +118,32 @@ func (n httpConn) Fire(entry *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
+	eventID, _ := entry.Data["EventID"].(string)
+	return n.post(body.Bytes(), eventID)
+}
 
-	req, err := http.NewRequest("POST", n.Endpoint, body)
+// post - posts the payload of one or more events to the endpoint,
+// along with the ID of a single event.
+func (n httpConn) post(payload []byte, eventID string) (err error) {
+	if n.Compress {
+		if payload, err = gzipPayload(payload); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", n.Endpoint, bytes.NewReader(payload))
 	if err != nil {
 		return err
 	}
 
 	// Set content-type.
 	req.Header.Set("Content-Type", "application/json")
+	if n.Compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if eventID != "" {
+		req.Header.Set("X-Minio-Event-Id", eventID)
+	}
 
 	// Set proper server user-agent.
 	req.Header.Set("User-Agent", globalServerUserAgent)
@@ -118,6 +153,7 @@ func (n httpConn) Fire(entry *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
+	resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusAccepted &&
@@ -127,7 +127,7 @@ func isLocalStorage(ep *url.URL) bool {
 		return false
 	}
 	for _, addr := range addrs {
-		if ip := net.ParseIP(addr); ip.IsLoopback() {
+		if ip := hostIP(addr); ip != nil && ip.IsLoopback() {
 			return true
 		}
 	}
@@ -137,13 +137,14 @@ func isLocalStorage(ep *url.URL) bool {
 		return false
 	}
 	for _, addr := range addrs {
+		addrIP := hostIP(addr)
 		for _, iaddr := range iaddrs {
 			ip, _, err := net.ParseCIDR(iaddr.String())
 			if err != nil {
 				errorIf(err, "Unable to parse CIDR")
 				return false
 			}
-			if ip.String() == addr {
+			if ip.Equal(addrIP) {
 				return true
 			}
 
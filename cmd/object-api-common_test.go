@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"runtime"
 	"sync"
 	"testing"
@@ -70,7 +71,7 @@ func TestHouseKeeping(t *testing.T) {
 			if errs[index] != nil {
 				return
 			}
-			errs[index] = store.AppendFile(minioMetaTmpBucket, "hello.txt", []byte("hello"))
+			errs[index] = store.AppendFile(context.Background(), minioMetaTmpBucket, "hello.txt", []byte("hello"))
 		}(i, store)
 	}
 	wg.Wait()
@@ -47,6 +47,9 @@ type StorageInfo struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+
+		// Disks failing write probes, counted as offline.
+		UnwritableDisks int
 	}
 }
 
@@ -166,6 +166,13 @@ func (e ObjectExistsAsDirectory) Error() string {
 	return "Object exists on : " + e.Bucket + " as directory " + e.Object
 }
 
+// PrefixExists objects already exist under a prefix.
+type PrefixExists GenericError
+
+func (e PrefixExists) Error() string {
+	return "Prefix exists: " + e.Bucket + "/" + e.Object
+}
+
 //PrefixAccessDenied object access is denied.
 type PrefixAccessDenied GenericError
 
@@ -244,6 +251,15 @@ func (e ObjectNameInvalid) Error() string {
 	return "Object name invalid: " + e.Bucket + "#" + e.Object
 }
 
+// ObjectNameUnsupported - object name cannot be represented on the
+// backend filesystem, returned in strict names mode only.
+type ObjectNameUnsupported GenericError
+
+// Return string an error formatted as the given text.
+func (e ObjectNameUnsupported) Error() string {
+	return "Object name unsupported by the backend filesystem: " + e.Bucket + "#" + e.Object
+}
+
 // IncompleteBody You did not provide the number of bytes specified by the Content-Length HTTP header.
 type IncompleteBody GenericError
 
@@ -361,3 +377,23 @@ func isErrObjectNotFound(err error) bool {
 	}
 	return false
 }
+
+// Check if error type is BucketNotFound.
+func isErrBucketNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketNotFound:
+		return true
+	}
+	return false
+}
+
+// Check if error type is InvalidUploadID.
+func isErrInvalidUploadID(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case InvalidUploadID:
+		return true
+	}
+	return false
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -39,7 +40,7 @@ func testGetObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	bucketName := getRandomBucketName()
 	objectName := "test-object"
 	// create bucket.
-	err := obj.MakeBucket(bucketName)
+	err := obj.MakeBucket(context.Background(), bucketName)
 	// Stop the test if creation of the bucket fails.
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -69,7 +70,7 @@ func testGetObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	// iterate through the above set of inputs and upkoad the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err = obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err = obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -153,7 +154,7 @@ func testGetObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	}
 
 	for i, testCase := range testCases {
-		err = obj.GetObject(testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
+		err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
 		if err != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s:  Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, err.Error())
 		}
@@ -192,7 +193,7 @@ func testGetObjectPermissionDenied(obj ObjectLayer, instanceType string, disks [
 	// Setup for the tests.
 	bucketName := getRandomBucketName()
 	// create bucket.
-	err := obj.MakeBucket(bucketName)
+	err := obj.MakeBucket(context.Background(), bucketName)
 	// Stop the test if creation of the bucket fails.
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -219,7 +220,7 @@ func testGetObjectPermissionDenied(obj ObjectLayer, instanceType string, disks [
 	// iterate through the above set of inputs and upkoad the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err = obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err = obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -264,7 +265,7 @@ func testGetObjectPermissionDenied(obj ObjectLayer, instanceType string, disks [
 			}
 		}
 
-		err = obj.GetObject(testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
+		err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
 		if err != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s:  Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, err.Error())
 		}
@@ -303,7 +304,7 @@ func testGetObjectDiskNotFound(obj ObjectLayer, instanceType string, disks []str
 	bucketName := getRandomBucketName()
 	objectName := "test-object"
 	// create bucket.
-	err := obj.MakeBucket(bucketName)
+	err := obj.MakeBucket(context.Background(), bucketName)
 	// Stop the test if creation of the bucket fails.
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -333,7 +334,7 @@ func testGetObjectDiskNotFound(obj ObjectLayer, instanceType string, disks []str
 	// iterate through the above set of inputs and upkoad the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err = obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err = obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -426,7 +427,7 @@ func testGetObjectDiskNotFound(obj ObjectLayer, instanceType string, disks []str
 	}
 
 	for i, testCase := range testCases {
-		err = obj.GetObject(testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
+		err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, testCase.startOffset, testCase.length, testCase.writer)
 		if err != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s:  Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, err.Error())
 		}
@@ -452,170 +453,170 @@ func testGetObjectDiskNotFound(obj ObjectLayer, instanceType string, disks []str
 	}
 }
 
-// Benchmarks for ObjectLayer.GetObject().
+// Benchmarks for ObjectLayer.GetObject(context.Background()).
 // The intent is to benchmark GetObject for various sizes ranging from few bytes to 100MB.
 // Also each of these Benchmarks are run both XL and FS backends.
 
-// BenchmarkGetObjectVerySmallFS - Benchmark FS.GetObject() for object size of 10 bytes.
+// BenchmarkGetObjectVerySmallFS - Benchmark FS.GetObject(context.Background()) for object size of 10 bytes.
 func BenchmarkGetObjectVerySmallFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 10)
 }
 
-// BenchmarkGetObjectVerySmallXL - Benchmark XL.GetObject() for object size of 10 bytes.
+// BenchmarkGetObjectVerySmallXL - Benchmark XL.GetObject(context.Background()) for object size of 10 bytes.
 func BenchmarkGetObjectVerySmallXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 10)
 }
 
-// BenchmarkGetObject10KbFS - Benchmark FS.GetObject() for object size of 10KB.
+// BenchmarkGetObject10KbFS - Benchmark FS.GetObject(context.Background()) for object size of 10KB.
 func BenchmarkGetObject10KbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 10*humanize.KiByte)
 }
 
-// BenchmarkGetObject10KbXL - Benchmark XL.GetObject() for object size of 10KB.
+// BenchmarkGetObject10KbXL - Benchmark XL.GetObject(context.Background()) for object size of 10KB.
 func BenchmarkGetObject10KbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 10*humanize.KiByte)
 }
 
-// BenchmarkGetObject100KbFS - Benchmark FS.GetObject() for object size of 100KB.
+// BenchmarkGetObject100KbFS - Benchmark FS.GetObject(context.Background()) for object size of 100KB.
 func BenchmarkGetObject100KbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 100*humanize.KiByte)
 }
 
-// BenchmarkGetObject100KbXL - Benchmark XL.GetObject() for object size of 100KB.
+// BenchmarkGetObject100KbXL - Benchmark XL.GetObject(context.Background()) for object size of 100KB.
 func BenchmarkGetObject100KbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 100*humanize.KiByte)
 }
 
-// BenchmarkGetObject1MbFS - Benchmark FS.GetObject() for object size of 1MB.
+// BenchmarkGetObject1MbFS - Benchmark FS.GetObject(context.Background()) for object size of 1MB.
 func BenchmarkGetObject1MbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 1*humanize.MiByte)
 }
 
-// BenchmarkGetObject1MbXL - Benchmark XL.GetObject() for object size of 1MB.
+// BenchmarkGetObject1MbXL - Benchmark XL.GetObject(context.Background()) for object size of 1MB.
 func BenchmarkGetObject1MbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 1*humanize.MiByte)
 }
 
-// BenchmarkGetObject5MbFS - Benchmark FS.GetObject() for object size of 5MB.
+// BenchmarkGetObject5MbFS - Benchmark FS.GetObject(context.Background()) for object size of 5MB.
 func BenchmarkGetObject5MbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 5*humanize.MiByte)
 }
 
-// BenchmarkGetObject5MbXL - Benchmark XL.GetObject() for object size of 5MB.
+// BenchmarkGetObject5MbXL - Benchmark XL.GetObject(context.Background()) for object size of 5MB.
 func BenchmarkGetObject5MbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 5*humanize.MiByte)
 }
 
-// BenchmarkGetObject10MbFS - Benchmark FS.GetObject() for object size of 10MB.
+// BenchmarkGetObject10MbFS - Benchmark FS.GetObject(context.Background()) for object size of 10MB.
 func BenchmarkGetObject10MbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 10*humanize.MiByte)
 }
 
-// BenchmarkGetObject10MbXL - Benchmark XL.GetObject() for object size of 10MB.
+// BenchmarkGetObject10MbXL - Benchmark XL.GetObject(context.Background()) for object size of 10MB.
 func BenchmarkGetObject10MbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 10*humanize.MiByte)
 }
 
-// BenchmarkGetObject25MbFS - Benchmark FS.GetObject() for object size of 25MB.
+// BenchmarkGetObject25MbFS - Benchmark FS.GetObject(context.Background()) for object size of 25MB.
 func BenchmarkGetObject25MbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 25*humanize.MiByte)
 
 }
 
-// BenchmarkGetObject25MbXL - Benchmark XL.GetObject() for object size of 25MB.
+// BenchmarkGetObject25MbXL - Benchmark XL.GetObject(context.Background()) for object size of 25MB.
 func BenchmarkGetObject25MbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 25*humanize.MiByte)
 }
 
-// BenchmarkGetObject50MbFS - Benchmark FS.GetObject() for object size of 50MB.
+// BenchmarkGetObject50MbFS - Benchmark FS.GetObject(context.Background()) for object size of 50MB.
 func BenchmarkGetObject50MbFS(b *testing.B) {
 	benchmarkGetObject(b, "FS", 50*humanize.MiByte)
 }
 
-// BenchmarkGetObject50MbXL - Benchmark XL.GetObject() for object size of 50MB.
+// BenchmarkGetObject50MbXL - Benchmark XL.GetObject(context.Background()) for object size of 50MB.
 func BenchmarkGetObject50MbXL(b *testing.B) {
 	benchmarkGetObject(b, "XL", 50*humanize.MiByte)
 }
 
-// parallel benchmarks for ObjectLayer.GetObject() .
+// parallel benchmarks for ObjectLayer.GetObject(context.Background()) .
 
-// BenchmarkGetObjectParallelVerySmallFS - Benchmark FS.GetObject() for object size of 10 bytes.
+// BenchmarkGetObjectParallelVerySmallFS - Benchmark FS.GetObject(context.Background()) for object size of 10 bytes.
 func BenchmarkGetObjectParallelVerySmallFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 10)
 }
 
-// BenchmarkGetObjectParallelVerySmallXL - Benchmark XL.GetObject() for object size of 10 bytes.
+// BenchmarkGetObjectParallelVerySmallXL - Benchmark XL.GetObject(context.Background()) for object size of 10 bytes.
 func BenchmarkGetObjectParallelVerySmallXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 10)
 }
 
-// BenchmarkGetObjectParallel10KbFS - Benchmark FS.GetObject() for object size of 10KB.
+// BenchmarkGetObjectParallel10KbFS - Benchmark FS.GetObject(context.Background()) for object size of 10KB.
 func BenchmarkGetObjectParallel10KbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 10*humanize.KiByte)
 }
 
-// BenchmarkGetObjectParallel10KbXL - Benchmark XL.GetObject() for object size of 10KB.
+// BenchmarkGetObjectParallel10KbXL - Benchmark XL.GetObject(context.Background()) for object size of 10KB.
 func BenchmarkGetObjectParallel10KbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 10*humanize.KiByte)
 }
 
-// BenchmarkGetObjectParallel100KbFS - Benchmark FS.GetObject() for object size of 100KB.
+// BenchmarkGetObjectParallel100KbFS - Benchmark FS.GetObject(context.Background()) for object size of 100KB.
 func BenchmarkGetObjectParallel100KbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 100*humanize.KiByte)
 }
 
-// BenchmarkGetObjectParallel100KbXL - Benchmark XL.GetObject() for object size of 100KB.
+// BenchmarkGetObjectParallel100KbXL - Benchmark XL.GetObject(context.Background()) for object size of 100KB.
 func BenchmarkGetObjectParallel100KbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 100*humanize.KiByte)
 }
 
-// BenchmarkGetObjectParallel1MbFS - Benchmark FS.GetObject() for object size of 1MB.
+// BenchmarkGetObjectParallel1MbFS - Benchmark FS.GetObject(context.Background()) for object size of 1MB.
 func BenchmarkGetObjectParallel1MbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 1*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel1MbXL - Benchmark XL.GetObject() for object size of 1MB.
+// BenchmarkGetObjectParallel1MbXL - Benchmark XL.GetObject(context.Background()) for object size of 1MB.
 func BenchmarkGetObjectParallel1MbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 1*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel5MbFS - Benchmark FS.GetObject() for object size of 5MB.
+// BenchmarkGetObjectParallel5MbFS - Benchmark FS.GetObject(context.Background()) for object size of 5MB.
 func BenchmarkGetObjectParallel5MbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 5*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel5MbXL - Benchmark XL.GetObject() for object size of 5MB.
+// BenchmarkGetObjectParallel5MbXL - Benchmark XL.GetObject(context.Background()) for object size of 5MB.
 func BenchmarkGetObjectParallel5MbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 5*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel10MbFS - Benchmark FS.GetObject() for object size of 10MB.
+// BenchmarkGetObjectParallel10MbFS - Benchmark FS.GetObject(context.Background()) for object size of 10MB.
 func BenchmarkGetObjectParallel10MbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 10*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel10MbXL - Benchmark XL.GetObject() for object size of 10MB.
+// BenchmarkGetObjectParallel10MbXL - Benchmark XL.GetObject(context.Background()) for object size of 10MB.
 func BenchmarkGetObjectParallel10MbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 10*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel25MbFS - Benchmark FS.GetObject() for object size of 25MB.
+// BenchmarkGetObjectParallel25MbFS - Benchmark FS.GetObject(context.Background()) for object size of 25MB.
 func BenchmarkGetObjectParallel25MbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 25*humanize.MiByte)
 
 }
 
-// BenchmarkGetObjectParallel25MbXL - Benchmark XL.GetObject() for object size of 25MB.
+// BenchmarkGetObjectParallel25MbXL - Benchmark XL.GetObject(context.Background()) for object size of 25MB.
 func BenchmarkGetObjectParallel25MbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 25*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel50MbFS - Benchmark FS.GetObject() for object size of 50MB.
+// BenchmarkGetObjectParallel50MbFS - Benchmark FS.GetObject(context.Background()) for object size of 50MB.
 func BenchmarkGetObjectParallel50MbFS(b *testing.B) {
 	benchmarkGetObjectParallel(b, "FS", 50*humanize.MiByte)
 }
 
-// BenchmarkGetObjectParallel50MbXL - Benchmark XL.GetObject() for object size of 50MB.
+// BenchmarkGetObjectParallel50MbXL - Benchmark XL.GetObject(context.Background()) for object size of 50MB.
 func BenchmarkGetObjectParallel50MbXL(b *testing.B) {
 	benchmarkGetObjectParallel(b, "XL", 50*humanize.MiByte)
 }
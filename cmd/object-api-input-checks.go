@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"strings"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
@@ -145,6 +146,13 @@ func checkPutObjectArgs(bucket, object string, obj ObjectLayer) error {
 			Object: object,
 		})
 	}
+	// Reject names unsupported by the backend filesystem upfront.
+	if globalIsStrictNames && !isStrictObjectName(object) {
+		return traceError(ObjectNameUnsupported{
+			Bucket: bucket,
+			Object: object,
+		})
+	}
 	return nil
 }
 
@@ -153,9 +161,52 @@ func checkBucketExist(bucket string, obj ObjectLayer) error {
 	if !IsValidBucketName(bucket) {
 		return BucketNameInvalid{Bucket: bucket}
 	}
-	_, err := obj.GetBucketInfo(bucket)
+	_, err := obj.GetBucketInfo(context.Background(), bucket)
 	if err != nil {
 		return BucketNotFound{Bucket: bucket}
 	}
 	return nil
 }
+
+// isValidRenamePrefix - returns true if prefix can be renamed, the
+// prefix of a rename should be a non empty directory like prefix.
+func isValidRenamePrefix(prefix string) bool {
+	return IsValidObjectPrefix(prefix) && prefix != slashSeparator &&
+		strings.HasSuffix(prefix, slashSeparator) && !strings.HasPrefix(prefix, slashSeparator)
+}
+
+// Checks for RenamePrefix arguments validity, objects should exist
+// under prefix and none under newPrefix.
+func checkRenamePrefixArgs(ctx context.Context, bucket, prefix, newPrefix string, obj ObjectLayer) error {
+	if err := checkBucketExist(bucket, obj); err != nil {
+		return traceError(err)
+	}
+	for _, p := range []string{prefix, newPrefix} {
+		if !isValidRenamePrefix(p) {
+			return traceError(ObjectNameInvalid{Bucket: bucket, Object: p})
+		}
+	}
+	// Prefixes nested one in the other can not be renamed.
+	if strings.HasPrefix(prefix, newPrefix) || strings.HasPrefix(newPrefix, prefix) {
+		return traceError(ObjectNameInvalid{Bucket: bucket, Object: newPrefix})
+	}
+	if globalIsStrictNames && !isStrictObjectName(newPrefix) {
+		return traceError(ObjectNameUnsupported{Bucket: bucket, Object: newPrefix})
+	}
+
+	result, err := obj.ListObjects(ctx, bucket, prefix, "", "", 1)
+	if err != nil {
+		return err
+	}
+	if len(result.Objects) == 0 {
+		return traceError(ObjectNotFound{Bucket: bucket, Object: prefix})
+	}
+	result, err = obj.ListObjects(ctx, bucket, newPrefix, "", "", 1)
+	if err != nil {
+		return err
+	}
+	if len(result.Objects) != 0 {
+		return traceError(PrefixExists{Bucket: bucket, Object: newPrefix})
+	}
+	return nil
+}
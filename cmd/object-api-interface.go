@@ -16,38 +16,45 @@
 
 package cmd
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
-// ObjectLayer implements primitives for object API layer.
+// ObjectLayer implements primitives for object API layer. Operations
+// given a request context stop reading and writing data, and listing,
+// once the context is done.
 type ObjectLayer interface {
 	// Storage operations.
 	Shutdown() error
 	StorageInfo() StorageInfo
 
 	// Bucket operations.
-	MakeBucket(bucket string) error
-	GetBucketInfo(bucket string) (bucketInfo BucketInfo, err error)
-	ListBuckets() (buckets []BucketInfo, err error)
-	DeleteBucket(bucket string) error
-	ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error)
+	MakeBucket(ctx context.Context, bucket string) error
+	GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error)
+	ListBuckets(ctx context.Context) (buckets []BucketInfo, err error)
+	DeleteBucket(ctx context.Context, bucket string) error
+	RenameBucket(ctx context.Context, srcBucket, dstBucket string) error
+	ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error)
 
 	// Object operations.
-	GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) (err error)
-	GetObjectInfo(bucket, object string) (objInfo ObjectInfo, err error)
-	PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error)
-	CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (objInfo ObjectInfo, err error)
-	DeleteObject(bucket, object string) error
+	GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer) (err error)
+	GetObjectInfo(ctx context.Context, bucket, object string) (objInfo ObjectInfo, err error)
+	PutObject(ctx context.Context, bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error)
+	CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (objInfo ObjectInfo, err error)
+	DeleteObject(ctx context.Context, bucket, object string) error
+	RenamePrefix(ctx context.Context, bucket, prefix, newPrefix string) error
 
 	// Multipart operations.
-	ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error)
-	NewMultipartUpload(bucket, object string, metadata map[string]string) (uploadID string, err error)
-	PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (md5 string, err error)
-	ListObjectParts(bucket, object, uploadID string, partNumberMarker int, maxParts int) (result ListPartsInfo, err error)
-	AbortMultipartUpload(bucket, object, uploadID string) error
-	CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []completePart) (objInfo ObjectInfo, err error)
+	ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error)
+	NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error)
+	PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (md5 string, err error)
+	ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (result ListPartsInfo, err error)
+	AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error
+	CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []completePart) (objInfo ObjectInfo, err error)
 
 	// Healing operations.
-	HealBucket(bucket string) error
-	HealObject(bucket, object string) error
-	ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error)
+	HealBucket(ctx context.Context, bucket string) error
+	HealObject(ctx context.Context, bucket, object string) error
+	ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error)
 }
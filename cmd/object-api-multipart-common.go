@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -143,7 +144,7 @@ func writeUploadJSON(u *uploadsV1, uploadsPath, tmpPath string, disk StorageAPI)
 	}
 
 	// Write `uploads.json` to disk. First to tmp location and then rename.
-	if wErr = disk.AppendFile(minioMetaTmpBucket, tmpPath, uplBytes); wErr != nil {
+	if wErr = disk.AppendFile(context.Background(), minioMetaTmpBucket, tmpPath, uplBytes); wErr != nil {
 		return traceError(wErr)
 	}
 	wErr = disk.RenameFile(minioMetaTmpBucket, tmpPath, minioMetaMultipartBucket, uploadsPath)
@@ -206,13 +207,16 @@ func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count
 	}
 	index := 0
 	if uploadIDMarker != "" {
-		for ; index < len(uploadsJSON.Uploads); index++ {
-			if uploadsJSON.Uploads[index].UploadID == uploadIDMarker {
+		for i, upload := range uploadsJSON.Uploads {
+			if upload.UploadID == uploadIDMarker {
 				// Skip the uploadID as it would already be listed in previous listing.
-				index++
+				index = i + 1
 				break
 			}
 		}
+		// An upload ID marker not found was completed or aborted
+		// since listed, all the uploads left are listed again so
+		// that none are skipped.
 	}
 	for index < len(uploadsJSON.Uploads) {
 		uploads = append(uploads, uploadMetadata{
@@ -229,3 +233,13 @@ func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count
 	end := (index == len(uploadsJSON.Uploads))
 	return uploads, end, nil
 }
+
+// partsAfterMarker - returns the parts numbered higher than
+// partNumberMarker, which need not be an uploaded part, from parts
+// sorted by part number.
+func partsAfterMarker(parts []objectPartInfo, partNumberMarker int) []objectPartInfo {
+	i := sort.Search(len(parts), func(i int) bool {
+		return parts[i].Number > partNumberMarker
+	})
+	return parts[i:]
+}
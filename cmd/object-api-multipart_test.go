@@ -18,7 +18,9 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -36,14 +38,14 @@ func testObjectNewMultipartUpload(obj ObjectLayer, instanceType string, t TestEr
 	bucket := "minio-bucket"
 	object := "minio-object"
 
-	_, err := obj.NewMultipartUpload("--", object, nil)
+	_, err := obj.NewMultipartUpload(context.Background(), "--", object, nil)
 	if err == nil {
 		t.Fatalf("%s: Expected to fail since bucket name is invalid.", instanceType)
 	}
 
 	errMsg := "Bucket not found: minio-bucket"
 	// opearation expected to fail since the bucket on which NewMultipartUpload is being initiated doesn't exist.
-	_, err = obj.NewMultipartUpload(bucket, object, nil)
+	_, err = obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err == nil {
 		t.Fatalf("%s: Expected to fail since the NewMultipartUpload is intialized on a non-existent bucket.", instanceType)
 	}
@@ -52,23 +54,23 @@ func testObjectNewMultipartUpload(obj ObjectLayer, instanceType string, t TestEr
 	}
 
 	// Create bucket before intiating NewMultipartUpload.
-	err = obj.MakeBucket(bucket)
+	err = obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
-	_, err = obj.NewMultipartUpload(bucket, "\\", nil)
+	_, err = obj.NewMultipartUpload(context.Background(), bucket, "\\", nil)
 	if err == nil {
 		t.Fatalf("%s: Expected to fail since object name is invalid.", instanceType)
 	}
 
-	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
-	err = obj.AbortMultipartUpload(bucket, object, uploadID)
+	err = obj.AbortMultipartUpload(context.Background(), bucket, object, uploadID)
 	if err != nil {
 		switch err.(type) {
 		case InvalidUploadID:
@@ -91,13 +93,13 @@ func testObjectAbortMultipartUpload(obj ObjectLayer, instanceType string, t Test
 	object := "minio-object"
 
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
-	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
@@ -116,7 +118,7 @@ func testObjectAbortMultipartUpload(obj ObjectLayer, instanceType string, t Test
 	}
 	// Iterating over creatPartCases to generate multipart chunks.
 	for i, testCase := range abortTestCases {
-		err = obj.AbortMultipartUpload(testCase.bucketName, testCase.objName, testCase.uploadID)
+		err = obj.AbortMultipartUpload(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID)
 		if testCase.expectedErrType == nil && err != nil {
 			t.Errorf("Test %d, unexpected err is received: %v, expected:%v\n", i+1, err, testCase.expectedErrType)
 		}
@@ -137,18 +139,18 @@ func testObjectAPIIsUploadIDExists(obj ObjectLayer, instanceType string, t TestE
 	object := "minio-object"
 
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
-	_, err = obj.NewMultipartUpload(bucket, object, nil)
+	_, err = obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
-	err = obj.AbortMultipartUpload(bucket, object, "abc")
+	err = obj.AbortMultipartUpload(context.Background(), bucket, object, "abc")
 	err = errorCause(err)
 	switch err.(type) {
 	case InvalidUploadID:
@@ -173,14 +175,14 @@ func testPutObjectPartDiskNotFound(obj ObjectLayer, instanceType string, disks [
 	// objectNames[0].
 	// uploadIds [0].
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucketNames[0])
+	err := obj.MakeBucket(context.Background(), bucketNames[0])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucketNames[0], objectNames[0], nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketNames[0], objectNames[0], nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -217,7 +219,7 @@ func testPutObjectPartDiskNotFound(obj ObjectLayer, instanceType string, disks [
 	sha256sum := ""
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, testCase := range createPartCases {
-		_, err = obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
+		_, err = obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err.Error())
 		}
@@ -231,7 +233,7 @@ func testPutObjectPartDiskNotFound(obj ObjectLayer, instanceType string, disks [
 
 	// Object part upload should fail with quorum not available.
 	testCase := createPartCases[len(createPartCases)-1]
-	_, err = obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
+	_, err = obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
 	if err == nil {
 		t.Fatalf("Test %s: expected to fail but passed instead", instanceType)
 	}
@@ -253,19 +255,19 @@ func testObjectAPIPutObjectPart(obj ObjectLayer, instanceType string, t TestErrH
 	object := "minio-object"
 
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 	// Creating a dummy bucket for tests.
-	err = obj.MakeBucket("unused-bucket")
+	err = obj.MakeBucket(context.Background(), "unused-bucket")
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -346,7 +348,7 @@ func testObjectAPIPutObjectPart(obj ObjectLayer, instanceType string, t TestErrH
 
 	// Validate all the test cases.
 	for i, testCase := range testCases {
-		actualMd5Hex, actualErr := obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, testCase.inputSHA256)
+		actualMd5Hex, actualErr := obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, testCase.inputSHA256)
 		// All are test cases above are expected to fail.
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s.", i+1, instanceType, actualErr.Error())
@@ -386,13 +388,13 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	// objectNames[0].
 	// uploadIds [0].
 	// Create bucket before initiating NewMultipartUpload.
-	err := obj.MakeBucket(bucketNames[0])
+	err := obj.MakeBucket(context.Background(), bucketNames[0])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucketNames[0], objectNames[0], nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketNames[0], objectNames[0], nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -404,7 +406,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	// objectNames[0].
 	// uploadIds [1-3].
 	// Bucket to test for mutiple upload Id's for a given object.
-	err = obj.MakeBucket(bucketNames[1])
+	err = obj.MakeBucket(context.Background(), bucketNames[1])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -412,7 +414,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	for i := 0; i < 3; i++ {
 		// Initiate Multipart Upload on bucketNames[1] for the same object 3 times.
 		//  Used to test the listing for the case of multiple uploadID's for a given object.
-		uploadID, err = obj.NewMultipartUpload(bucketNames[1], objectNames[0], nil)
+		uploadID, err = obj.NewMultipartUpload(context.Background(), bucketNames[1], objectNames[0], nil)
 		if err != nil {
 			// Failed to create NewMultipartUpload, abort.
 			t.Fatalf("%s : %s", instanceType, err.Error())
@@ -425,7 +427,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	// bucketnames[2].
 	// objectNames[0-2].
 	// uploadIds [4-9].
-	err = obj.MakeBucket(bucketNames[2])
+	err = obj.MakeBucket(context.Background(), bucketNames[2])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -434,7 +436,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	//  Used to test the listing for the case of multiple objects for a given bucket.
 	for i := 0; i < 6; i++ {
 		var uploadID string
-		uploadID, err = obj.NewMultipartUpload(bucketNames[2], objectNames[i], nil)
+		uploadID, err = obj.NewMultipartUpload(context.Background(), bucketNames[2], objectNames[i], nil)
 		if err != nil {
 			// Failed to create NewMultipartUpload, abort.
 			t.Fatalf("%s : %s", instanceType, err.Error())
@@ -480,7 +482,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	sha256sum := ""
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, testCase := range createPartCases {
-		_, err := obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
+		_, err := obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err.Error())
 		}
@@ -1208,7 +1210,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 
 	for i, testCase := range testCases {
 		// fmt.Println(i+1, testCase) // uncomment to peek into the test cases.
-		actualResult, actualErr := obj.ListMultipartUploads(testCase.bucket, testCase.prefix, testCase.keyMarker, testCase.uploadIDMarker, testCase.delimiter, testCase.maxUploads)
+		actualResult, actualErr := obj.ListMultipartUploads(context.Background(), testCase.bucket, testCase.prefix, testCase.keyMarker, testCase.uploadIDMarker, testCase.delimiter, testCase.maxUploads)
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, actualErr.Error())
 		}
@@ -1272,6 +1274,155 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	}
 }
 
+// Wrapper for calling TestListMultipartUploadsPagination tests for both XL multiple disks and single node setup.
+func TestListMultipartUploadsPagination(t *testing.T) {
+	ExecObjectLayerTest(t, testListMultipartUploadsPagination)
+}
+
+// testListMultipartUploadsPagination - lists the uploads page by page
+// following the next markers, as s3cmd does to clean up uploads.
+func testListMultipartUploadsPagination(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// Uploads in listing order, 3 on each object.
+	objectNames := []string{"a.txt", "dir/b.txt", "dir/c.txt", "e.txt"}
+	var expected []string
+	for _, objectName := range objectNames {
+		for i := 0; i < 3; i++ {
+			uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, objectName, nil)
+			if err != nil {
+				t.Fatalf("%s : %s", instanceType, err)
+			}
+			expected = append(expected, objectName+"/"+uploadID)
+		}
+	}
+
+	// listAll - lists all the uploads with maxUploads per page, aborting
+	// every listed upload first if abort is set.
+	listAll := func(delimiter string, maxUploads int, abort bool) (listed, prefixes []string) {
+		var keyMarker, uploadIDMarker string
+		for page := 0; ; page++ {
+			if page > len(expected) {
+				t.Fatalf("%s: listing with max uploads %d does not end", instanceType, maxUploads)
+			}
+			result, err := obj.ListMultipartUploads(context.Background(), bucket, "", keyMarker, uploadIDMarker, delimiter, maxUploads)
+			if err != nil {
+				t.Fatalf("%s : %s", instanceType, err)
+			}
+			if result.UploadIDMarker != uploadIDMarker {
+				t.Fatalf("%s: expected upload id marker %q, got %q", instanceType, uploadIDMarker, result.UploadIDMarker)
+			}
+			for _, upload := range result.Uploads {
+				listed = append(listed, upload.Object+"/"+upload.UploadID)
+				if abort {
+					if err = obj.AbortMultipartUpload(context.Background(), bucket, upload.Object, upload.UploadID); err != nil {
+						t.Fatalf("%s : %s", instanceType, err)
+					}
+				}
+			}
+			prefixes = append(prefixes, result.CommonPrefixes...)
+			if !result.IsTruncated {
+				if result.NextKeyMarker != "" || result.NextUploadIDMarker != "" {
+					t.Fatalf("%s: expected no next markers on the last page", instanceType)
+				}
+				return listed, prefixes
+			}
+			keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+		}
+	}
+
+	for _, maxUploads := range []int{1, 2, 3, 4, 5, len(expected), len(expected) + 1} {
+		listed, _ := listAll("", maxUploads, false)
+		if !reflect.DeepEqual(listed, expected) {
+			t.Errorf("%s: max uploads %d: expected %v, got %v", instanceType, maxUploads, expected, listed)
+		}
+	}
+
+	// Directories are common prefixes with a delimiter, a page may
+	// end right after one.
+	for _, maxUploads := range []int{1, 3, 4} {
+		listed, prefixes := listAll(slashSeparator, maxUploads, false)
+		expectedListed := append(append([]string{}, expected[:3]...), expected[9:]...)
+		if !reflect.DeepEqual(listed, expectedListed) {
+			t.Errorf("%s: max uploads %d: expected %v, got %v", instanceType, maxUploads, expectedListed, listed)
+		}
+		if !reflect.DeepEqual(prefixes, []string{"dir/"}) {
+			t.Errorf("%s: max uploads %d: expected common prefixes [dir/], got %v", instanceType, maxUploads, prefixes)
+		}
+	}
+
+	// Aborting the listed uploads removes the upload id markers,
+	// none of the uploads should be skipped.
+	listed, _ := listAll("", 2, true)
+	if !reflect.DeepEqual(listed, expected) {
+		t.Errorf("%s: expected %v, got %v", instanceType, expected, listed)
+	}
+	if listed, _ = listAll("", 2, false); len(listed) != 0 {
+		t.Errorf("%s: expected all the uploads to be aborted, got %v", instanceType, listed)
+	}
+}
+
+// Wrapper for calling TestListObjectPartsMarker tests for both XL multiple disks and single node setup.
+func TestListObjectPartsMarker(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectPartsMarker)
+}
+
+// testListObjectPartsMarker - lists parts after part number markers
+// which are not uploaded parts.
+func testListObjectPartsMarker(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket, object := "minio-bucket", "minio-object"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	for _, partID := range []int{2, 4, 6} {
+		if _, err = obj.PutObjectPart(context.Background(), bucket, object, uploadID, partID, 4, bytes.NewBufferString("abcd"), "", ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+	}
+
+	testCases := []struct {
+		partNumberMarker int
+		maxParts         int
+		expectedParts    []int
+		isTruncated      bool
+	}{
+		{0, 10, []int{2, 4, 6}, false},
+		{1, 10, []int{2, 4, 6}, false},
+		{3, 10, []int{4, 6}, false},
+		{3, 1, []int{4}, true},
+		{5, 1, []int{6}, false},
+		{6, 10, nil, false},
+		{7, 10, nil, false},
+		{0, 0, nil, false},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjectParts(context.Background(), bucket, object, uploadID, testCase.partNumberMarker, testCase.maxParts)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		var parts []int
+		for _, part := range result.Parts {
+			parts = append(parts, part.PartNumber)
+		}
+		if !reflect.DeepEqual(parts, testCase.expectedParts) {
+			t.Errorf("Test %d: %s: expected parts %v, got %v", i+1, instanceType, testCase.expectedParts, parts)
+		}
+		if result.IsTruncated != testCase.isTruncated {
+			t.Errorf("Test %d: %s: expected truncated %v, got %v", i+1, instanceType, testCase.isTruncated, result.IsTruncated)
+		}
+		if result.PartNumberMarker != testCase.partNumberMarker {
+			t.Errorf("Test %d: %s: expected part number marker %d, got %d", i+1, instanceType, testCase.partNumberMarker, result.PartNumberMarker)
+		}
+	}
+}
+
 // Wrapper for calling TestListObjectPartsDiskNotFound tests for both XL multiple disks and single node setup.
 func TestListObjectPartsDiskNotFound(t *testing.T) {
 	ExecObjectLayerDiskAlteredTest(t, testListObjectPartsDiskNotFound)
@@ -1288,13 +1439,13 @@ func testListObjectPartsDiskNotFound(obj ObjectLayer, instanceType string, disks
 	// objectNames[0].
 	// uploadIds [0].
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucketNames[0])
+	err := obj.MakeBucket(context.Background(), bucketNames[0])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucketNames[0], objectNames[0], nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketNames[0], objectNames[0], nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -1328,7 +1479,7 @@ func testListObjectPartsDiskNotFound(obj ObjectLayer, instanceType string, disks
 	sha256sum := ""
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, testCase := range createPartCases {
-		_, err := obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
+		_, err := obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err.Error())
 		}
@@ -1395,11 +1546,12 @@ func testListObjectPartsDiskNotFound(obj ObjectLayer, instanceType string, disks
 		},
 		// partinfos - 2.
 		{
-			Bucket:      bucketNames[0],
-			Object:      objectNames[0],
-			MaxParts:    2,
-			IsTruncated: false,
-			UploadID:    uploadIDs[0],
+			Bucket:           bucketNames[0],
+			Object:           objectNames[0],
+			MaxParts:         2,
+			IsTruncated:      false,
+			UploadID:         uploadIDs[0],
+			PartNumberMarker: 3,
 			Parts: []partInfo{
 				{
 					PartNumber: 4,
@@ -1447,7 +1599,7 @@ func testListObjectPartsDiskNotFound(obj ObjectLayer, instanceType string, disks
 	}
 
 	for i, testCase := range testCases {
-		actualResult, actualErr := obj.ListObjectParts(testCase.bucket, testCase.object, testCase.uploadID, testCase.partNumberMarker, testCase.maxParts)
+		actualResult, actualErr := obj.ListObjectParts(context.Background(), testCase.bucket, testCase.object, testCase.uploadID, testCase.partNumberMarker, testCase.maxParts)
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, actualErr.Error())
 		}
@@ -1531,13 +1683,13 @@ func testListObjectParts(obj ObjectLayer, instanceType string, t TestErrHandler)
 	// objectNames[0].
 	// uploadIds [0].
 	// Create bucket before intiating NewMultipartUpload.
-	err := obj.MakeBucket(bucketNames[0])
+	err := obj.MakeBucket(context.Background(), bucketNames[0])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucketNames[0], objectNames[0], nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketNames[0], objectNames[0], nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -1568,7 +1720,7 @@ func testListObjectParts(obj ObjectLayer, instanceType string, t TestErrHandler)
 	sha256sum := ""
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, testCase := range createPartCases {
-		_, err := obj.PutObjectPart(testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
+		_, err := obj.PutObjectPart(context.Background(), testCase.bucketName, testCase.objName, testCase.uploadID, testCase.PartID, testCase.intputDataSize, bytes.NewBufferString(testCase.inputReaderData), testCase.inputMd5, sha256sum)
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err.Error())
 		}
@@ -1632,11 +1784,12 @@ func testListObjectParts(obj ObjectLayer, instanceType string, t TestErrHandler)
 		},
 		// partinfos - 2.
 		{
-			Bucket:      bucketNames[0],
-			Object:      objectNames[0],
-			MaxParts:    2,
-			IsTruncated: false,
-			UploadID:    uploadIDs[0],
+			Bucket:           bucketNames[0],
+			Object:           objectNames[0],
+			MaxParts:         2,
+			IsTruncated:      false,
+			UploadID:         uploadIDs[0],
+			PartNumberMarker: 3,
 			Parts: []partInfo{
 				{
 					PartNumber: 4,
@@ -1684,7 +1837,7 @@ func testListObjectParts(obj ObjectLayer, instanceType string, t TestErrHandler)
 	}
 
 	for i, testCase := range testCases {
-		actualResult, actualErr := obj.ListObjectParts(testCase.bucket, testCase.object, testCase.uploadID, testCase.partNumberMarker, testCase.maxParts)
+		actualResult, actualErr := obj.ListObjectParts(context.Background(), testCase.bucket, testCase.object, testCase.uploadID, testCase.partNumberMarker, testCase.maxParts)
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, actualErr.Error())
 		}
@@ -1769,13 +1922,13 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 	// objectNames[0].
 	// uploadIds [0].
 	// Create bucket before intiating NewMultipartUpload.
-	err = obj.MakeBucket(bucketNames[0])
+	err = obj.MakeBucket(context.Background(), bucketNames[0])
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
 	}
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err = obj.NewMultipartUpload(bucketNames[0], objectNames[0], map[string]string{"X-Amz-Meta-Id": "id"})
+	uploadID, err = obj.NewMultipartUpload(context.Background(), bucketNames[0], objectNames[0], map[string]string{"X-Amz-Meta-Id": "id"})
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -1810,7 +1963,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 	sha256sum := ""
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, part := range parts {
-		_, err = obj.PutObjectPart(part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize, bytes.NewBufferString(part.inputReaderData), part.inputMd5, sha256sum)
+		_, err = obj.PutObjectPart(context.Background(), part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize, bytes.NewBufferString(part.inputReaderData), part.inputMd5, sha256sum)
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err)
 		}
@@ -1895,7 +2048,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		// Part number 0 doesn't exist, expecting InvalidPart error (Test number 12).
 		{bucketNames[0], objectNames[0], uploadIDs[0], []completePart{{ETag: "abcd", PartNumber: 0}}, "", InvalidPart{}, false},
 		// // Upload and PartNumber exists, But a deliberate ETag mismatch is introduced (Test number 13).
-		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[0].parts, "", BadDigest{}, false},
+		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[0].parts, "", InvalidPart{}, false},
 		// Test case with non existent object name (Test number 14).
 		{bucketNames[0], "my-object", uploadIDs[0], []completePart{{ETag: "abcd", PartNumber: 1}}, "", InvalidUploadID{UploadID: uploadIDs[0]}, false},
 		// Testing for Part being too small (Test number 15).
@@ -1913,7 +2066,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 	}
 
 	for i, testCase := range testCases {
-		actualResult, actualErr := obj.CompleteMultipartUpload(testCase.bucket, testCase.object, testCase.uploadID, testCase.parts)
+		actualResult, actualErr := obj.CompleteMultipartUpload(context.Background(), testCase.bucket, testCase.object, testCase.uploadID, testCase.parts)
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, actualErr)
 		}
@@ -1937,47 +2090,47 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 	}
 }
 
-// Benchmarks for ObjectLayer.PutObjectPart().
+// Benchmarks for ObjectLayer.PutObjectPart(context.Background()).
 // The intent is to benchmark PutObjectPart for various sizes ranging from few bytes to 100MB.
 // Also each of these Benchmarks are run both XL and FS backends.
 
-// BenchmarkPutObjectPart5MbFS - Benchmark FS.PutObjectPart() for object size of 5MB.
+// BenchmarkPutObjectPart5MbFS - Benchmark FS.PutObjectPart(context.Background()) for object size of 5MB.
 func BenchmarkPutObjectPart5MbFS(b *testing.B) {
 	benchmarkPutObjectPart(b, "FS", 5*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart5MbXL - Benchmark XL.PutObjectPart() for object size of 5MB.
+// BenchmarkPutObjectPart5MbXL - Benchmark XL.PutObjectPart(context.Background()) for object size of 5MB.
 func BenchmarkPutObjectPart5MbXL(b *testing.B) {
 	benchmarkPutObjectPart(b, "XL", 5*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart10MbFS - Benchmark FS.PutObjectPart() for object size of 10MB.
+// BenchmarkPutObjectPart10MbFS - Benchmark FS.PutObjectPart(context.Background()) for object size of 10MB.
 func BenchmarkPutObjectPart10MbFS(b *testing.B) {
 	benchmarkPutObjectPart(b, "FS", 10*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart10MbXL - Benchmark XL.PutObjectPart() for object size of 10MB.
+// BenchmarkPutObjectPart10MbXL - Benchmark XL.PutObjectPart(context.Background()) for object size of 10MB.
 func BenchmarkPutObjectPart10MbXL(b *testing.B) {
 	benchmarkPutObjectPart(b, "XL", 10*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart25MbFS - Benchmark FS.PutObjectPart() for object size of 25MB.
+// BenchmarkPutObjectPart25MbFS - Benchmark FS.PutObjectPart(context.Background()) for object size of 25MB.
 func BenchmarkPutObjectPart25MbFS(b *testing.B) {
 	benchmarkPutObjectPart(b, "FS", 25*humanize.MiByte)
 
 }
 
-// BenchmarkPutObjectPart25MbXL - Benchmark XL.PutObjectPart() for object size of 25MB.
+// BenchmarkPutObjectPart25MbXL - Benchmark XL.PutObjectPart(context.Background()) for object size of 25MB.
 func BenchmarkPutObjectPart25MbXL(b *testing.B) {
 	benchmarkPutObjectPart(b, "XL", 25*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart50MbFS - Benchmark FS.PutObjectPart() for object size of 50MB.
+// BenchmarkPutObjectPart50MbFS - Benchmark FS.PutObjectPart(context.Background()) for object size of 50MB.
 func BenchmarkPutObjectPart50MbFS(b *testing.B) {
 	benchmarkPutObjectPart(b, "FS", 50*humanize.MiByte)
 }
 
-// BenchmarkPutObjectPart50MbXL - Benchmark XL.PutObjectPart() for object size of 50MB.
+// BenchmarkPutObjectPart50MbXL - Benchmark XL.PutObjectPart(context.Background()) for object size of 50MB.
 func BenchmarkPutObjectPart50MbXL(b *testing.B) {
 	benchmarkPutObjectPart(b, "XL", 50*humanize.MiByte)
 }
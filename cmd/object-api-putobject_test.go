@@ -18,12 +18,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"io/ioutil"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -44,14 +46,14 @@ func testObjectAPIPutObject(obj ObjectLayer, instanceType string, t TestErrHandl
 	object := "minio-object"
 
 	// Create bucket.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
 	// Creating a dummy bucket for tests.
-	err = obj.MakeBucket("unused-bucket")
+	err = obj.MakeBucket(context.Background(), "unused-bucket")
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -154,7 +156,7 @@ func testObjectAPIPutObject(obj ObjectLayer, instanceType string, t TestErrHandl
 	}
 
 	for i, testCase := range testCases {
-		objInfo, actualErr := obj.PutObject(testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, testCase.inputSHA256)
+		objInfo, actualErr := obj.PutObject(context.Background(), testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, testCase.inputSHA256)
 		actualErr = errorCause(actualErr)
 		if actualErr != nil && testCase.expectedError == nil {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: error %s.", i+1, instanceType, actualErr.Error())
@@ -189,14 +191,14 @@ func testObjectAPIPutObjectDiskNotFOund(obj ObjectLayer, instanceType string, di
 	object := "minio-object"
 
 	// Create bucket.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
 	// Creating a dummy bucket for tests.
-	err = obj.MakeBucket("unused-bucket")
+	err = obj.MakeBucket(context.Background(), "unused-bucket")
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -228,7 +230,7 @@ func testObjectAPIPutObjectDiskNotFOund(obj ObjectLayer, instanceType string, di
 
 	sha256sum := ""
 	for i, testCase := range testCases {
-		objInfo, actualErr := obj.PutObject(testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, sha256sum)
+		objInfo, actualErr := obj.PutObject(context.Background(), testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, sha256sum)
 		actualErr = errorCause(err)
 		if actualErr != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s.", i+1, instanceType, actualErr.Error())
@@ -278,7 +280,7 @@ func testObjectAPIPutObjectDiskNotFOund(obj ObjectLayer, instanceType string, di
 		InsufficientWriteQuorum{},
 	}
 
-	_, actualErr := obj.PutObject(testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, sha256sum)
+	_, actualErr := obj.PutObject(context.Background(), testCase.bucketName, testCase.objName, testCase.intputDataSize, bytes.NewReader(testCase.inputData), testCase.inputMeta, sha256sum)
 	actualErr = errorCause(actualErr)
 	if actualErr != nil && testCase.shouldPass {
 		t.Errorf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s.", len(testCases)+1, instanceType, actualErr.Error())
@@ -291,6 +293,88 @@ func testObjectAPIPutObjectDiskNotFOund(obj ObjectLayer, instanceType string, di
 	}
 }
 
+// Wrapper for calling PutObject strict names tests for both XL multiple disks and single node setup.
+func TestObjectAPIPutObjectStrictNames(t *testing.T) {
+	globalIsStrictNames = true
+	defer func() { globalIsStrictNames = false }()
+	ExecObjectLayerTest(t, testObjectAPIPutObjectStrictNames)
+}
+
+// Tests validate that PutObject and NewMultipartUpload reject names
+// unsupported by the backend filesystem in strict names mode.
+func testObjectAPIPutObjectStrictNames(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	data := []byte("hello, world")
+	for i, object := range []string{"dir./object", "aux", "dir/report ", "what?"} {
+		_, err := obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, "")
+		if _, ok := errorCause(err).(ObjectNameUnsupported); !ok {
+			t.Errorf("Test %d: %s: Expected ObjectNameUnsupported for %q, got %v", i+1, instanceType, object, err)
+		}
+		_, err = obj.NewMultipartUpload(context.Background(), bucket, object, nil)
+		if _, ok := errorCause(err).(ObjectNameUnsupported); !ok {
+			t.Errorf("Test %d: %s: Expected ObjectNameUnsupported for %q, got %v", i+1, instanceType, object, err)
+		}
+	}
+
+	if _, err := obj.PutObject(context.Background(), bucket, "dir/report.txt", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+}
+
+// Wrapper for calling PutObject source modification time tests for both XL multiple disks and single node setup.
+func TestObjectAPIPutObjectSourceModTime(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectAPIPutObjectSourceModTime)
+}
+
+// Tests validate that a modification time set in the metadata is the
+// last modified time of the object, kept by server side copies.
+func testObjectAPIPutObjectSourceModTime(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	data := []byte("hello, world")
+	modTime := time.Unix(1262304000, 250000000).UTC()
+	metadata := map[string]string{amzMetaMtime: "1262304000.25"}
+	objInfo, err := obj.PutObject(context.Background(), bucket, "backup", int64(len(data)), bytes.NewReader(data), metadata, "")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if !objInfo.ModTime.Equal(modTime) {
+		t.Errorf("%s: Expected modification time %s, got %s", instanceType, modTime, objInfo.ModTime)
+	}
+
+	objInfo, err = obj.GetObjectInfo(context.Background(), bucket, "backup")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if !objInfo.ModTime.Equal(modTime) {
+		t.Errorf("%s: Expected modification time %s, got %s", instanceType, modTime, objInfo.ModTime)
+	}
+
+	objInfo, err = obj.CopyObject(context.Background(), bucket, "backup", bucket, "backup-copy", objInfo.UserDefined)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if !objInfo.ModTime.Equal(modTime) {
+		t.Errorf("%s: Expected copy modification time %s, got %s", instanceType, modTime, objInfo.ModTime)
+	}
+
+	// Invalid values are kept as metadata only.
+	metadata = map[string]string{amzMetaMtime: "yesterday"}
+	if objInfo, err = obj.PutObject(context.Background(), bucket, "other", int64(len(data)), bytes.NewReader(data), metadata, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if time.Since(objInfo.ModTime) > time.Minute {
+		t.Errorf("%s: Expected a current modification time, got %s", instanceType, objInfo.ModTime)
+	}
+}
+
 // Wrapper for calling PutObject tests for both XL multiple disks and single node setup.
 func TestObjectAPIPutObjectStaleFiles(t *testing.T) {
 	ExecObjectLayerStaleFilesTest(t, testObjectAPIPutObjectStaleFiles)
@@ -303,7 +387,7 @@ func testObjectAPIPutObjectStaleFiles(obj ObjectLayer, instanceType string, disk
 	object := "minio-object"
 
 	// Create bucket.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -312,7 +396,7 @@ func testObjectAPIPutObjectStaleFiles(obj ObjectLayer, instanceType string, disk
 	data := []byte("hello, world")
 	sha256sum := ""
 	// Create object.
-	_, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, sha256sum)
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, sha256sum)
 	if err != nil {
 		// Failed to create object, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -338,14 +422,14 @@ func testObjectAPIMultipartPutObjectStaleFiles(obj ObjectLayer, instanceType str
 	object := "minio-object"
 
 	// Create bucket.
-	err := obj.MakeBucket(bucket)
+	err := obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		// Failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
 	}
 
 	// Initiate Multipart Upload on the above created bucket.
-	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -357,7 +441,7 @@ func testObjectAPIMultipartPutObjectStaleFiles(obj ObjectLayer, instanceType str
 	md5Writer.Write(fiveMBBytes)
 	etag1 := hex.EncodeToString(md5Writer.Sum(nil))
 	sha256sum := ""
-	_, err = obj.PutObjectPart(bucket, object, uploadID, 1, int64(len(fiveMBBytes)), bytes.NewReader(fiveMBBytes), etag1, sha256sum)
+	_, err = obj.PutObjectPart(context.Background(), bucket, object, uploadID, 1, int64(len(fiveMBBytes)), bytes.NewReader(fiveMBBytes), etag1, sha256sum)
 	if err != nil {
 		// Failed to upload object part, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -368,7 +452,7 @@ func testObjectAPIMultipartPutObjectStaleFiles(obj ObjectLayer, instanceType str
 	md5Writer = md5.New()
 	md5Writer.Write(data)
 	etag2 := hex.EncodeToString(md5Writer.Sum(nil))
-	_, err = obj.PutObjectPart(bucket, object, uploadID, 2, int64(len(data)), bytes.NewReader(data), etag2, sha256sum)
+	_, err = obj.PutObjectPart(context.Background(), bucket, object, uploadID, 2, int64(len(data)), bytes.NewReader(data), etag2, sha256sum)
 	if err != nil {
 		// Failed to upload object part, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -379,7 +463,7 @@ func testObjectAPIMultipartPutObjectStaleFiles(obj ObjectLayer, instanceType str
 		{ETag: etag1, PartNumber: 1},
 		{ETag: etag2, PartNumber: 2},
 	}
-	_, err = obj.CompleteMultipartUpload(bucket, object, uploadID, parts)
+	_, err = obj.CompleteMultipartUpload(context.Background(), bucket, object, uploadID, parts)
 	if err != nil {
 		// Failed to complete multipart upload, abort.
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -404,160 +488,160 @@ func testObjectAPIMultipartPutObjectStaleFiles(obj ObjectLayer, instanceType str
 	}
 }
 
-// Benchmarks for ObjectLayer.PutObject().
+// Benchmarks for ObjectLayer.PutObject(context.Background()).
 // The intent is to benchmark PutObject for various sizes ranging from few bytes to 100MB.
 // Also each of these Benchmarks are run both XL and FS backends.
 
-// BenchmarkPutObjectVerySmallFS - Benchmark FS.PutObject() for object size of 10 bytes.
+// BenchmarkPutObjectVerySmallFS - Benchmark FS.PutObject(context.Background()) for object size of 10 bytes.
 func BenchmarkPutObjectVerySmallFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 10)
 }
 
-// BenchmarkPutObjectVerySmallXL - Benchmark XL.PutObject() for object size of 10 bytes.
+// BenchmarkPutObjectVerySmallXL - Benchmark XL.PutObject(context.Background()) for object size of 10 bytes.
 func BenchmarkPutObjectVerySmallXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 10)
 }
 
-// BenchmarkPutObject10KbFS - Benchmark FS.PutObject() for object size of 10KB.
+// BenchmarkPutObject10KbFS - Benchmark FS.PutObject(context.Background()) for object size of 10KB.
 func BenchmarkPutObject10KbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 10*humanize.KiByte)
 }
 
-// BenchmarkPutObject10KbXL - Benchmark XL.PutObject() for object size of 10KB.
+// BenchmarkPutObject10KbXL - Benchmark XL.PutObject(context.Background()) for object size of 10KB.
 func BenchmarkPutObject10KbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 10*humanize.KiByte)
 }
 
-// BenchmarkPutObject100KbFS - Benchmark FS.PutObject() for object size of 100KB.
+// BenchmarkPutObject100KbFS - Benchmark FS.PutObject(context.Background()) for object size of 100KB.
 func BenchmarkPutObject100KbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 100*humanize.KiByte)
 }
 
-// BenchmarkPutObject100KbXL - Benchmark XL.PutObject() for object size of 100KB.
+// BenchmarkPutObject100KbXL - Benchmark XL.PutObject(context.Background()) for object size of 100KB.
 func BenchmarkPutObject100KbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 100*humanize.KiByte)
 }
 
-// BenchmarkPutObject1MbFS - Benchmark FS.PutObject() for object size of 1MB.
+// BenchmarkPutObject1MbFS - Benchmark FS.PutObject(context.Background()) for object size of 1MB.
 func BenchmarkPutObject1MbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 1*humanize.MiByte)
 }
 
-// BenchmarkPutObject1MbXL - Benchmark XL.PutObject() for object size of 1MB.
+// BenchmarkPutObject1MbXL - Benchmark XL.PutObject(context.Background()) for object size of 1MB.
 func BenchmarkPutObject1MbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 1*humanize.MiByte)
 }
 
-// BenchmarkPutObject5MbFS - Benchmark FS.PutObject() for object size of 5MB.
+// BenchmarkPutObject5MbFS - Benchmark FS.PutObject(context.Background()) for object size of 5MB.
 func BenchmarkPutObject5MbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 5*humanize.MiByte)
 }
 
-// BenchmarkPutObject5MbXL - Benchmark XL.PutObject() for object size of 5MB.
+// BenchmarkPutObject5MbXL - Benchmark XL.PutObject(context.Background()) for object size of 5MB.
 func BenchmarkPutObject5MbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 5*humanize.MiByte)
 }
 
-// BenchmarkPutObject10MbFS - Benchmark FS.PutObject() for object size of 10MB.
+// BenchmarkPutObject10MbFS - Benchmark FS.PutObject(context.Background()) for object size of 10MB.
 func BenchmarkPutObject10MbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 10*humanize.MiByte)
 }
 
-// BenchmarkPutObject10MbXL - Benchmark XL.PutObject() for object size of 10MB.
+// BenchmarkPutObject10MbXL - Benchmark XL.PutObject(context.Background()) for object size of 10MB.
 func BenchmarkPutObject10MbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 10*humanize.MiByte)
 }
 
-// BenchmarkPutObject25MbFS - Benchmark FS.PutObject() for object size of 25MB.
+// BenchmarkPutObject25MbFS - Benchmark FS.PutObject(context.Background()) for object size of 25MB.
 func BenchmarkPutObject25MbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 25*humanize.MiByte)
 
 }
 
-// BenchmarkPutObject25MbXL - Benchmark XL.PutObject() for object size of 25MB.
+// BenchmarkPutObject25MbXL - Benchmark XL.PutObject(context.Background()) for object size of 25MB.
 func BenchmarkPutObject25MbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 25*humanize.MiByte)
 }
 
-// BenchmarkPutObject50MbFS - Benchmark FS.PutObject() for object size of 50MB.
+// BenchmarkPutObject50MbFS - Benchmark FS.PutObject(context.Background()) for object size of 50MB.
 func BenchmarkPutObject50MbFS(b *testing.B) {
 	benchmarkPutObject(b, "FS", 50*humanize.MiByte)
 }
 
-// BenchmarkPutObject50MbXL - Benchmark XL.PutObject() for object size of 50MB.
+// BenchmarkPutObject50MbXL - Benchmark XL.PutObject(context.Background()) for object size of 50MB.
 func BenchmarkPutObject50MbXL(b *testing.B) {
 	benchmarkPutObject(b, "XL", 50*humanize.MiByte)
 }
 
-// parallel benchmarks for ObjectLayer.PutObject() .
+// parallel benchmarks for ObjectLayer.PutObject(context.Background()) .
 
-// BenchmarkParallelPutObjectVerySmallFS - BenchmarkParallel FS.PutObject() for object size of 10 bytes.
+// BenchmarkParallelPutObjectVerySmallFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 10 bytes.
 func BenchmarkParallelPutObjectVerySmallFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 10)
 }
 
-// BenchmarkParallelPutObjectVerySmallXL - BenchmarkParallel XL.PutObject() for object size of 10 bytes.
+// BenchmarkParallelPutObjectVerySmallXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 10 bytes.
 func BenchmarkParallelPutObjectVerySmallXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 10)
 }
 
-// BenchmarkParallelPutObject10KbFS - BenchmarkParallel FS.PutObject() for object size of 10KB.
+// BenchmarkParallelPutObject10KbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 10KB.
 func BenchmarkParallelPutObject10KbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 10*humanize.KiByte)
 }
 
-// BenchmarkParallelPutObject10KbXL - BenchmarkParallel XL.PutObject() for object size of 10KB.
+// BenchmarkParallelPutObject10KbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 10KB.
 func BenchmarkParallelPutObject10KbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 10*humanize.KiByte)
 }
 
-// BenchmarkParallelPutObject100KbFS - BenchmarkParallel FS.PutObject() for object size of 100KB.
+// BenchmarkParallelPutObject100KbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 100KB.
 func BenchmarkParallelPutObject100KbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 100*humanize.KiByte)
 }
 
-// BenchmarkParallelPutObject100KbXL - BenchmarkParallel XL.PutObject() for object size of 100KB.
+// BenchmarkParallelPutObject100KbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 100KB.
 func BenchmarkParallelPutObject100KbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 100*humanize.KiByte)
 }
 
-// BenchmarkParallelPutObject1MbFS - BenchmarkParallel FS.PutObject() for object size of 1MB.
+// BenchmarkParallelPutObject1MbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 1MB.
 func BenchmarkParallelPutObject1MbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 1*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject1MbXL - BenchmarkParallel XL.PutObject() for object size of 1MB.
+// BenchmarkParallelPutObject1MbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 1MB.
 func BenchmarkParallelPutObject1MbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 1*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject5MbFS - BenchmarkParallel FS.PutObject() for object size of 5MB.
+// BenchmarkParallelPutObject5MbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 5MB.
 func BenchmarkParallelPutObject5MbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 5*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject5MbXL - BenchmarkParallel XL.PutObject() for object size of 5MB.
+// BenchmarkParallelPutObject5MbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 5MB.
 func BenchmarkParallelPutObject5MbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 5*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject10MbFS - BenchmarkParallel FS.PutObject() for object size of 10MB.
+// BenchmarkParallelPutObject10MbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 10MB.
 func BenchmarkParallelPutObject10MbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 10*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject10MbXL - BenchmarkParallel XL.PutObject() for object size of 10MB.
+// BenchmarkParallelPutObject10MbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 10MB.
 func BenchmarkParallelPutObject10MbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 10*humanize.MiByte)
 }
 
-// BenchmarkParallelPutObject25MbFS - BenchmarkParallel FS.PutObject() for object size of 25MB.
+// BenchmarkParallelPutObject25MbFS - BenchmarkParallel FS.PutObject(context.Background()) for object size of 25MB.
 func BenchmarkParallelPutObject25MbFS(b *testing.B) {
 	benchmarkPutObjectParallel(b, "FS", 25*humanize.MiByte)
 
 }
 
-// BenchmarkParallelPutObject25MbXL - BenchmarkParallel XL.PutObject() for object size of 25MB.
+// BenchmarkParallelPutObject25MbXL - BenchmarkParallel XL.PutObject(context.Background()) for object size of 25MB.
 func BenchmarkParallelPutObject25MbXL(b *testing.B) {
 	benchmarkPutObjectParallel(b, "XL", 25*humanize.MiByte)
 }
@@ -0,0 +1,191 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"strconv"
+)
+
+// tracedObjectLayer - ObjectLayer timing each call in a span of the
+// request context.
+type tracedObjectLayer struct {
+	ObjectLayer
+}
+
+// traceObjectLayer - returns objAPI tracing its calls if tracing is
+// enabled, objAPI otherwise.
+func traceObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	if globalTracer == nil {
+		return objAPI
+	}
+	return tracedObjectLayer{objAPI}
+}
+
+// startObjectLayerSpan - starts the span of an ObjectLayer call on
+// bucket, and object if not empty.
+func startObjectLayerSpan(ctx context.Context, name, bucket, object string) (context.Context, *traceSpan) {
+	ctx, span := startSpan(ctx, "ObjectLayer."+name)
+	span.SetTag("bucket", bucket)
+	if object != "" {
+		span.SetTag("object", object)
+	}
+	return ctx, span
+}
+
+func (t tracedObjectLayer) MakeBucket(ctx context.Context, bucket string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "MakeBucket", bucket, "")
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.MakeBucket(ctx, bucket)
+}
+
+func (t tracedObjectLayer) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "GetBucketInfo", bucket, "")
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.GetBucketInfo(ctx, bucket)
+}
+
+func (t tracedObjectLayer) ListBuckets(ctx context.Context) (buckets []BucketInfo, err error) {
+	ctx, span := startSpan(ctx, "ObjectLayer.ListBuckets")
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.ListBuckets(ctx)
+}
+
+func (t tracedObjectLayer) DeleteBucket(ctx context.Context, bucket string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "DeleteBucket", bucket, "")
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.DeleteBucket(ctx, bucket)
+}
+
+func (t tracedObjectLayer) RenameBucket(ctx context.Context, srcBucket, dstBucket string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "RenameBucket", srcBucket, "")
+	span.SetTag("new-bucket", dstBucket)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.RenameBucket(ctx, srcBucket, dstBucket)
+}
+
+func (t tracedObjectLayer) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "ListObjects", bucket, "")
+	span.SetTag("prefix", prefix)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+}
+
+func (t tracedObjectLayer) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "GetObject", bucket, object)
+	span.SetTag("offset", strconv.FormatInt(startOffset, 10))
+	span.SetTag("length", strconv.FormatInt(length, 10))
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, writer)
+}
+
+func (t tracedObjectLayer) GetObjectInfo(ctx context.Context, bucket, object string) (objInfo ObjectInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "GetObjectInfo", bucket, object)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.GetObjectInfo(ctx, bucket, object)
+}
+
+func (t tracedObjectLayer) PutObject(ctx context.Context, bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "PutObject", bucket, object)
+	span.SetTag("size", strconv.FormatInt(size, 10))
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.PutObject(ctx, bucket, object, size, data, metadata, sha256sum)
+}
+
+func (t tracedObjectLayer) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (objInfo ObjectInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "CopyObject", destBucket, destObject)
+	span.SetTag("source", pathJoin(srcBucket, srcObject))
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, metadata)
+}
+
+func (t tracedObjectLayer) DeleteObject(ctx context.Context, bucket, object string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "DeleteObject", bucket, object)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.DeleteObject(ctx, bucket, object)
+}
+
+func (t tracedObjectLayer) RenamePrefix(ctx context.Context, bucket, prefix, newPrefix string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "RenamePrefix", bucket, "")
+	span.SetTag("prefix", prefix)
+	span.SetTag("new-prefix", newPrefix)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.RenamePrefix(ctx, bucket, prefix, newPrefix)
+}
+
+func (t tracedObjectLayer) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "ListMultipartUploads", bucket, "")
+	span.SetTag("prefix", prefix)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+}
+
+func (t tracedObjectLayer) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "NewMultipartUpload", bucket, object)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.NewMultipartUpload(ctx, bucket, object, metadata)
+}
+
+func (t tracedObjectLayer) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (md5 string, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "PutObjectPart", bucket, object)
+	span.SetTag("uploadId", uploadID)
+	span.SetTag("partNumber", strconv.Itoa(partID))
+	span.SetTag("size", strconv.FormatInt(size, 10))
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.PutObjectPart(ctx, bucket, object, uploadID, partID, size, data, md5Hex, sha256sum)
+}
+
+func (t tracedObjectLayer) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (result ListPartsInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "ListObjectParts", bucket, object)
+	span.SetTag("uploadId", uploadID)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts)
+}
+
+func (t tracedObjectLayer) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "AbortMultipartUpload", bucket, object)
+	span.SetTag("uploadId", uploadID)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.AbortMultipartUpload(ctx, bucket, object, uploadID)
+}
+
+func (t tracedObjectLayer) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []completePart) (objInfo ObjectInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "CompleteMultipartUpload", bucket, object)
+	span.SetTag("uploadId", uploadID)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+}
+
+func (t tracedObjectLayer) HealBucket(ctx context.Context, bucket string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "HealBucket", bucket, "")
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.HealBucket(ctx, bucket)
+}
+
+func (t tracedObjectLayer) HealObject(ctx context.Context, bucket, object string) (err error) {
+	ctx, span := startObjectLayerSpan(ctx, "HealObject", bucket, object)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.HealObject(ctx, bucket, object)
+}
+
+func (t tracedObjectLayer) ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	ctx, span := startObjectLayerSpan(ctx, "ListObjectsHeal", bucket, "")
+	span.SetTag("prefix", prefix)
+	defer func() { span.Finish(err) }()
+	return t.ObjectLayer.ListObjectsHeal(ctx, bucket, prefix, marker, delimiter, maxKeys)
+}
@@ -22,7 +22,9 @@ import (
 	"io"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
@@ -113,9 +115,98 @@ func IsValidObjectPrefix(object string) bool {
 	if strings.ContainsAny(object, "\\") {
 		return false
 	}
+	// Reject names resolving out of their bucket on the backend.
+	return !hasBadPathComponent(object)
+}
+
+// Device names reserved on Windows, with or without an extension.
+var reservedFileNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// isStrictObjectName - verifies that every path segment of the object
+// name can be a file name on Windows and most NAS filesystems: it must
+// not end with a dot or a space, be a reserved device name, or contain
+// a control character or any of `<>:"|?*`.
+func isStrictObjectName(object string) bool {
+	for _, segment := range strings.Split(object, slashSeparator) {
+		if segment == "" {
+			continue
+		}
+		if strings.HasSuffix(segment, ".") || strings.HasSuffix(segment, " ") {
+			return false
+		}
+		if strings.ContainsAny(segment, "<>:\"|?*") {
+			return false
+		}
+		for _, r := range segment {
+			if r < 0x20 {
+				return false
+			}
+		}
+		name := segment
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[:i]
+		}
+		if _, ok := reservedFileNames[strings.ToUpper(strings.TrimRight(name, " "))]; ok {
+			return false
+		}
+	}
 	return true
 }
 
+// Metadata holding the modification time of the source of an object,
+// in seconds since the epoch with an optional fraction, as set by rclone.
+const amzMetaMtime = "X-Amz-Meta-Mtime"
+
+// parseSourceModTime - parses a modification time in seconds since the
+// epoch, with an optional fraction of up to nine digits.
+func parseSourceModTime(value string) (time.Time, error) {
+	secStr, fracStr := value, ""
+	if i := strings.Index(value, "."); i >= 0 {
+		secStr, fracStr = value[:i], value[i+1:]
+	}
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if sec < 0 {
+		return time.Time{}, fmt.Errorf("Invalid modification time ‘%s’, should not be before the epoch", value)
+	}
+	var nsec int64
+	if fracStr != "" {
+		if len(fracStr) > 9 {
+			fracStr = fracStr[:9]
+		}
+		if strings.Trim(fracStr, "0123456789") != "" {
+			return time.Time{}, fmt.Errorf("Invalid modification time ‘%s’", value)
+		}
+		if nsec, err = strconv.ParseInt(fracStr, 10, 64); err != nil {
+			return time.Time{}, err
+		}
+		for i := len(fracStr); i < 9; i++ {
+			nsec *= 10
+		}
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+// sourceModTime - returns the modification time of the source of an
+// object set in its metadata, modTime if none or invalid.
+func sourceModTime(metadata map[string]string, modTime time.Time) time.Time {
+	value, ok := metadata[amzMetaMtime]
+	if !ok {
+		return modTime
+	}
+	mtime, err := parseSourceModTime(value)
+	if err != nil {
+		return modTime
+	}
+	return mtime
+}
+
 // Slash separator.
 const slashSeparator = "/"
 
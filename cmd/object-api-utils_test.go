@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Tests validate bucket name.
@@ -96,6 +97,7 @@ func TestIsValidObjectName(t *testing.T) {
 		{"contains-\"-quote", true},
 		{"contains-`-tick", true},
 		{"There are far too many object names, and far too few bucket names!", true},
+		{"a/..b/c..", true},
 		// cases for which test should fail.
 		// passing invalid object names.
 		{"", false},
@@ -103,6 +105,11 @@ func TestIsValidObjectName(t *testing.T) {
 		{"/a/b/c", false},
 		{"contains-\\-backslash", false},
 		{string([]byte{0xff, 0xfe, 0xfd}), false},
+		// path segments resolving out of the bucket.
+		{"../.minio.sys/config/config.json", false},
+		{"a/../../b", false},
+		{"a/./b", false},
+		{"..", false},
 	}
 
 	for i, testCase := range testCases {
@@ -116,6 +123,84 @@ func TestIsValidObjectName(t *testing.T) {
 	}
 }
 
+// Tests validate object names in strict names mode.
+func TestIsStrictObjectName(t *testing.T) {
+	testCases := []struct {
+		objectName string
+		shouldPass bool
+	}{
+		// cases which should pass the test.
+		{"object", true},
+		{"dir/sub dir/file.tar.gz", true},
+		{"prefix/", true},
+		{".hidden/CONFIG.txt", true},
+		{"console.log", true},
+		{"SHØRT", true},
+		// cases for which test should fail.
+		{"file.", false},
+		{"dir /file", false},
+		{"dir/..", false},
+		{"CON", false},
+		{"dir/nul.txt", false},
+		{"Com1 .tar", false},
+		{"lpt9", false},
+		{"f*le", false},
+		{"The Shining Script <v1>.pdf", false},
+		{"c:drive", false},
+		{"contains-|-pipe", false},
+		{"contains-\"-quote", false},
+		{"what?", false},
+		{"tab\tseparated", false},
+	}
+
+	for i, testCase := range testCases {
+		isStrictObjectName := isStrictObjectName(testCase.objectName)
+		if testCase.shouldPass && !isStrictObjectName {
+			t.Errorf("Test case %d: Expected \"%s\" to be a strict object name", i+1, testCase.objectName)
+		}
+		if !testCase.shouldPass && isStrictObjectName {
+			t.Errorf("Test case %d: Expected object name \"%s\" not to be strict", i+1, testCase.objectName)
+		}
+	}
+}
+
+// Tests parsing modification times set in object metadata.
+func TestParseSourceModTime(t *testing.T) {
+	testCases := []struct {
+		value     string
+		modTime   time.Time
+		shouldErr bool
+	}{
+		{"1500000000", time.Unix(1500000000, 0), false},
+		{"1500000000.5", time.Unix(1500000000, 500000000), false},
+		{"1500000000.123456789", time.Unix(1500000000, 123456789), false},
+		{"1500000000.1234567891234", time.Unix(1500000000, 123456789), false},
+		{"0", time.Unix(0, 0), false},
+		{"", time.Time{}, true},
+		{"-1", time.Time{}, true},
+		{"1500000000.-5", time.Time{}, true},
+		{"1500000000.+5", time.Time{}, true},
+		{"2017-07-14T02:40:00Z", time.Time{}, true},
+	}
+
+	for i, testCase := range testCases {
+		modTime, err := parseSourceModTime(testCase.value)
+		if testCase.shouldErr {
+			if err == nil {
+				t.Errorf("Test case %d: Expected %q to be invalid", i+1, testCase.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test case %d: Unexpected error for %q: %v", i+1, testCase.value, err)
+			continue
+		}
+		if !modTime.Equal(testCase.modTime) {
+			t.Errorf("Test case %d: Expected %s, got %s", i+1, testCase.modTime, modTime)
+		}
+	}
+}
+
 // Tests rangeReader.
 func TestRangeReader(t *testing.T) {
 	testCases := []struct {
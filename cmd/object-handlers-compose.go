@@ -0,0 +1,213 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+
+	mux "github.com/gorilla/mux"
+)
+
+// Maximum number of sources of a compose request, same as the
+// maximum number of parts of a multipart upload.
+const maxComposeSources = 10000
+
+// composePart - byte range of a source object to compose.
+type composePart struct {
+	bucket, object string
+	offset, length int64
+}
+
+// ComposeObjectHandler - POST Object?compose
+// ----------
+// Minio extension which builds an object from a list of source
+// objects, or byte ranges of them, concatenated server side.
+func (api objectAPIHandlers) ComposeObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	composeBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorIf(err, "Unable to read HTTP body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	composeRequest := &ComposeObjectRequest{}
+	if err = xml.Unmarshal(composeBytes, composeRequest); err != nil {
+		errorIf(err, "Unable to parse compose object request XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	sources := composeRequest.Sources
+	if len(sources) == 0 || len(sources) > maxComposeSources {
+		writeErrorResponse(w, ErrInvalidComposeSources, r.URL)
+		return
+	}
+
+	dstPath := path.Join(bucket, object)
+	srcPaths := make(map[string]struct{})
+	for _, src := range sources {
+		if src.Bucket == "" || src.Key == "" {
+			writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+			return
+		}
+		if s3Error := checkBucketObjectPath(src.Bucket, src.Key); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		srcPath := path.Join(src.Bucket, src.Key)
+		if srcPath == dstPath {
+			// Reading the destination while it is written is not supported.
+			writeErrorResponse(w, ErrInvalidComposeDest, r.URL)
+			return
+		}
+		if _, ok := srcPaths[srcPath]; ok {
+			continue
+		}
+		srcPaths[srcPath] = struct{}{}
+
+		// Requests should also be allowed to read every source.
+		if s3Error := checkCopySourceAccess(r, src.Bucket, src.Key); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		// The source buckets may be under maintenance.
+		if s3Error := globalServerModes.checkRequest(src.Bucket, false); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Hold write lock on destination, it is the sole mutating state.
+	objectDWLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
+	objectDWLock.Lock()
+	defer objectDWLock.Unlock()
+
+	// Hold read locks on the sources, always in the same order.
+	var lockPaths []string
+	for srcPath := range srcPaths {
+		lockPaths = append(lockPaths, srcPath)
+	}
+	sort.Strings(lockPaths)
+	for _, srcPath := range lockPaths {
+		srcBucket, srcObject := path2BucketAndObject(srcPath)
+		objectSRLock := globalNSMutex.NewNSLock(r.Context(), srcBucket, srcObject)
+		objectSRLock.RLock()
+		defer objectSRLock.RUnlock()
+	}
+
+	srcInfos := make(map[string]ObjectInfo)
+	parts := make([]composePart, len(sources))
+	var size int64
+	for i, src := range sources {
+		srcPath := path.Join(src.Bucket, src.Key)
+		srcInfo, ok := srcInfos[srcPath]
+		if !ok {
+			srcInfo, err = objectAPI.GetObjectInfo(r.Context(), src.Bucket, src.Key)
+			if err != nil {
+				errorIf(err, "Unable to fetch object info.")
+				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+				return
+			}
+			srcInfos[srcPath] = srcInfo
+		}
+
+		parts[i] = composePart{src.Bucket, src.Key, 0, srcInfo.Size}
+		if src.Range != "" {
+			hrange, rerr := parseRequestRange(src.Range, srcInfo.Size)
+			if rerr != nil {
+				writeErrorResponse(w, ErrInvalidRange, r.URL)
+				return
+			}
+			parts[i].offset, parts[i].length = hrange.offsetBegin, hrange.getLength()
+		}
+		size += parts[i].length
+	}
+
+	/// maximum Upload size for object in a single ComposeObject operation.
+	if isMaxObjectSize(size) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+	if s3Error := checkRequestQuota(r, size); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	metadata := extractMetadataFromHeader(r.Header)
+	setObjectOwner(metadata, getRequestAccessKey(r))
+
+	// Initialize pipe.
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		// Sources are read within the bandwidth of the server side copies.
+		writer := throttleCopyWriter(pipeWriter)
+		for _, part := range parts {
+			if part.length == 0 {
+				continue
+			}
+			if gerr := objectAPI.GetObject(r.Context(), part.bucket, part.object, part.offset, part.length, writer); gerr != nil {
+				errorIf(gerr, "Unable to read %s/%s.", part.bucket, part.object)
+				pipeWriter.CloseWithError(gerr)
+				return
+			}
+		}
+		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
+	}()
+
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, size, pipeReader, metadata, "")
+	// Explicitly close the reader, ending the reads on error.
+	pipeReader.Close()
+	if err != nil {
+		errorIf(err, "Unable to compose object %s.", dstPath)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := generateComposeObjectResponse(objInfo.MD5Sum, objInfo.ModTime)
+	encodedSuccessResponse := encodeResponse(response)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+
+	// Notify object created event.
+	eventNotify(eventData{
+		Type:    ObjectCreatedCompose,
+		Bucket:  bucket,
+		ObjInfo: objInfo,
+		ReqParams: map[string]string{
+			"sourceIPAddress": r.RemoteAddr,
+		},
+	})
+}
@@ -0,0 +1,193 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling ComposeObject HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIComposeObjectHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIComposeObjectHandler, []string{"ComposeObject"})
+}
+
+func testAPIComposeObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	objects := map[string]string{
+		"log-1": "0123456789",
+		"log-2": "abcdefghij",
+		"empty": "",
+	}
+	for objectName, data := range objects {
+		_, err := obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewBufferString(data), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+	}
+
+	sourcesXML := func(sources ...ComposeSource) []byte {
+		data, err := xml.Marshal(ComposeObjectRequest{Sources: sources})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	testCases := []struct {
+		objectName string
+		body       []byte
+		accessKey  string
+		secretKey  string
+
+		expectedRespStatus int
+		expectedData       string
+	}{
+		// Test case - 1, whole objects.
+		{
+			objectName: "composed-1",
+			body: sourcesXML(
+				ComposeSource{Bucket: bucketName, Key: "log-1"},
+				ComposeSource{Bucket: bucketName, Key: "empty"},
+				ComposeSource{Bucket: bucketName, Key: "log-2"},
+			),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusOK,
+			expectedData:       "0123456789abcdefghij",
+		},
+		// Test case - 2, byte ranges, repeating a source.
+		{
+			objectName: "composed-2",
+			body: sourcesXML(
+				ComposeSource{Bucket: bucketName, Key: "log-2", Range: "bytes=7-"},
+				ComposeSource{Bucket: bucketName, Key: "log-1", Range: "bytes=0-2"},
+				ComposeSource{Bucket: bucketName, Key: "log-2", Range: "bytes=-2"},
+			),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusOK,
+			expectedData:       "hij012ij",
+		},
+		// Test case - 3, destination is one of the sources.
+		{
+			objectName: "log-1",
+			body: sourcesXML(
+				ComposeSource{Bucket: bucketName, Key: "log-1"},
+				ComposeSource{Bucket: bucketName, Key: "log-2", Range: "bytes=0-0"},
+			),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 4, no sources.
+		{
+			objectName:         "composed-4",
+			body:               sourcesXML(),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 5, source without a key.
+		{
+			objectName:         "composed-5",
+			body:               sourcesXML(ComposeSource{Bucket: bucketName}),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 6, source not found.
+		{
+			objectName:         "composed-6",
+			body:               sourcesXML(ComposeSource{Bucket: bucketName, Key: "log-3"}),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusNotFound,
+		},
+		// Test case - 7, range beyond the source size.
+		{
+			objectName:         "composed-7",
+			body:               sourcesXML(ComposeSource{Bucket: bucketName, Key: "log-1", Range: "bytes=20-30"}),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		// Test case - 8, malformed XML.
+		{
+			objectName:         "composed-8",
+			body:               []byte("<ComposeObject><Source>"),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 9, invalid credentials.
+		{
+			objectName:         "composed-9",
+			body:               sourcesXML(ComposeSource{Bucket: bucketName, Key: "log-2"}),
+			accessKey:          "Invalid-AccessID",
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusForbidden,
+		},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getComposeObjectURL("", bucketName, testCase.objectName),
+			int64(len(testCase.body)), bytes.NewReader(testCase.body), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for ComposeObject: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedRespStatus != http.StatusOK {
+			continue
+		}
+
+		response := ComposeObjectResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse the response: <ERROR> %v", i+1, instanceType, err)
+		}
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, testCase.objectName)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %v", i+1, instanceType, err)
+		}
+		if response.ETag != fmt.Sprintf("%q", objInfo.MD5Sum) {
+			t.Errorf("Test %d: %s: Expected ETag %q, got %s", i+1, instanceType, objInfo.MD5Sum, response.ETag)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(context.Background(), bucketName, testCase.objectName, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s: %v", i+1, instanceType, err)
+		}
+		if buffer.String() != testCase.expectedData {
+			t.Errorf("Test %d: %s: Expected the composed data %q, got %q", i+1, instanceType, testCase.expectedData, buffer.String())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Largest read of an object served without the namespace read lock,
+// the data being buffered until validated.
+const lockFreeReadMaxSize = 1 * humanize.MiByte
+
+// Key of the context value marking lock-free reads.
+type lockFreeReadKey struct{}
+
+// withLockFreeRead - returns ctx marking reads from it as made without
+// the namespace lock, object versions may be replaced meanwhile.
+func withLockFreeRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lockFreeReadKey{}, true)
+}
+
+// isLockFreeRead - returns true if reads from ctx are made without the
+// namespace lock. Data read from ctx may then be of multiple versions,
+// it should not be cached before being validated.
+func isLockFreeRead(ctx context.Context) bool {
+	lockFree, _ := ctx.Value(lockFreeReadKey{}).(bool)
+	return lockFree
+}
+
+// getObjectLockFree - serves a GET of a small object without the
+// namespace read lock, saving the lock round trips in distributed
+// mode. Completed objects replace the previous version by rename, the
+// object is read between two reads of its metadata and served only if
+// the version stayed the same, never coming back once replaced.
+// Returns false if the request was not served, the read then has to
+// be made under the lock.
+func getObjectLockFree(w http.ResponseWriter, r *http.Request, objectAPI ObjectLayer, bucket, object string) bool {
+	ctx, healStatus := withReadHealStatus(withLockFreeRead(r.Context()))
+	objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return false
+	}
+
+	var hrange *httpRange
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		// Invalid ranges are reported under the lock.
+		if hrange, err = parseRequestRange(rangeHeader, objInfo.Size); err != nil {
+			return false
+		}
+	}
+	startOffset, length := int64(0), objInfo.Size
+	if hrange != nil {
+		startOffset, length = hrange.offsetBegin, hrange.getLength()
+	}
+	if length > lockFreeReadMaxSize {
+		return false
+	}
+
+	// Pre-conditions apply to the version read, as current when
+	// the request was served.
+	if checkPreconditions(w, r, objInfo) {
+		return true
+	}
+	// Presigned URLs may limit the size of the downloads.
+	if isPresignMaxSizeExceeded(r, length) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return true
+	}
+
+	var buffer bytes.Buffer
+	if err = objectAPI.GetObject(ctx, bucket, object, startOffset, length, &buffer); err != nil {
+		return false
+	}
+	current, err := objectAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil || !current.ModTime.Equal(objInfo.ModTime) || current.MD5Sum != objInfo.MD5Sum {
+		// Replaced while being read.
+		return false
+	}
+
+	setObjectHeaders(w, objInfo, hrange)
+	setGetRespHeaders(w, r.URL.Query())
+	setHealingHeader(w, healStatus)
+	if _, err = globalBucketBandwidth.ThrottleWriter(bucket, getRequestAccessKey(r), w).Write(buffer.Bytes()); err != nil {
+		errorIf(err, "Unable to write to client.")
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// replacingObjectLayer - object layer replacing objects while they are read.
+type replacingObjectLayer struct {
+	ObjectLayer
+}
+
+func (l replacingObjectLayer) GetObject(ctx context.Context, bucket, object string, startOffset, length int64, writer io.Writer) error {
+	data := "replaced"
+	if _, err := l.ObjectLayer.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewBufferString(data), nil, ""); err != nil {
+		return err
+	}
+	return l.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, writer)
+}
+
+// Tests serving small objects without the namespace lock.
+func TestGetObjectLockFree(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	obj, disks, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+
+	if err = obj.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), lockFreeReadMaxSize+1)
+	objects := map[string][]byte{"small": data[:10], "large": data}
+	for object, content := range objects {
+		if _, err = obj.PutObject(context.Background(), "bucket", object, int64(len(content)), bytes.NewReader(content), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		objAPI         ObjectLayer
+		object         string
+		rangeHeader    string
+		served         bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{obj, "small", "", true, http.StatusOK, "aaaaaaaaaa"},
+		{obj, "small", "bytes=2-4", true, http.StatusPartialContent, "aaa"},
+		{obj, "large", "", false, 0, ""},
+		{obj, "large", "bytes=0-9", true, http.StatusPartialContent, "aaaaaaaaaa"},
+		{obj, "missing", "", false, 0, ""},
+		{obj, "small", "bytes=100-200", false, 0, ""},
+		// Objects replaced while being read are read under the lock.
+		{replacingObjectLayer{obj}, "small", "", false, 0, ""},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("GET", "/bucket/"+testCase.object, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.rangeHeader != "" {
+			req.Header.Set("Range", testCase.rangeHeader)
+		}
+		rec := httptest.NewRecorder()
+		served := getObjectLockFree(rec, req, testCase.objAPI, "bucket", testCase.object)
+		if served != testCase.served {
+			t.Errorf("Test %d: Expected served %v, got %v", i+1, testCase.served, served)
+			continue
+		}
+		if !served {
+			continue
+		}
+		if rec.Code != testCase.expectedStatus || rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: Expected %d %q, got %d %q", i+1, testCase.expectedStatus, testCase.expectedBody, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// Tests marking lock-free reads in the context.
+func TestIsLockFreeRead(t *testing.T) {
+	ctx := context.Background()
+	if isLockFreeRead(ctx) || !isLockFreeRead(withLockFreeRead(ctx)) {
+		t.Fatal("Unexpected lock-free read marking")
+	}
+}
@@ -49,6 +49,22 @@ func setGetRespHeaders(w http.ResponseWriter, reqParams url.Values) {
 	}
 }
 
+// checkGetRespHeaders - returns ErrAnonymousResponseHeaders if an
+// anonymous request overrides the response headers, the overrides are
+// only honored when covered by a signature.
+func checkGetRespHeaders(r *http.Request) APIErrorCode {
+	if getRequestAuthType(r) != authTypeAnonymous {
+		return ErrNone
+	}
+	reqParams := r.URL.Query()
+	for k := range supportedGetReqParams {
+		if _, ok := reqParams[k]; ok {
+			return ErrAnonymousResponseHeaders
+		}
+	}
+	return ErrNone
+}
+
 // errAllowableNotFound - For an anon user, return 404 if have ListBucket, 403 otherwise
 // this is in keeping with the permissions sections of the docs of both:
 //   HEAD Object: http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectHEAD.html
@@ -59,7 +75,7 @@ func errAllowableObjectNotFound(bucket string, r *http.Request) APIErrorCode {
 		url := *r.URL
 		url.Path = "/" + bucket
 
-		if s3Error := enforceBucketPolicy(bucket, "s3:ListBucket", &url); s3Error != ErrNone {
+		if s3Error := checkAnonymousAccess(r, bucket, "s3:ListBucket", &url); s3Error != ErrNone {
 			return ErrAccessDenied
 		}
 	}
@@ -90,17 +106,31 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if s3Error := checkGetRespHeaders(r); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
+	// Downloads of the bucket may be transformed by an external service.
+	transform, transformed := globalBucketTransform.Get(bucket)
+
+	// In distributed mode small objects are read without the lock
+	// round trips when not replaced meanwhile.
+	if globalIsDistXL && !transformed && getObjectLockFree(w, r, objectAPI, bucket, object) {
+		return
+	}
+
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), bucket, object)
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
@@ -133,6 +163,12 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Transformed objects are served whole, ranges do not apply.
+	if transformed {
+		transformObject(r.Context(), w, r, objectAPI, objInfo, transform)
+		return
+	}
+
 	// Get the object.
 	startOffset := int64(0)
 	length := objInfo.Size
@@ -140,6 +176,14 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		startOffset = hrange.offsetBegin
 		length = hrange.getLength()
 	}
+	// Presigned URLs may limit the size of the downloads.
+	if isPresignMaxSizeExceeded(r, length) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+	// Reports if the object was found degraded while being read.
+	ctx, healStatus := withReadHealStatus(r.Context())
+
 	// Indicates if any data was written to the http.ResponseWriter
 	dataWritten := false
 	// io.Writer type which keeps track if any data was written.
@@ -152,13 +196,17 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			// Set any additional requested response headers.
 			setGetRespHeaders(w, r.URL.Query())
 
+			// Report objects queued for healing.
+			setHealingHeader(w, healStatus)
+
 			dataWritten = true
 		}
 		return w.Write(p)
 	})
 
 	// Reads the object at startOffset and writes to mw.
-	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
+	// Downloads are limited to the download bandwidth of the bucket and of the user.
+	if err := objectAPI.GetObject(ctx, bucket, object, startOffset, length, globalBucketBandwidth.ThrottleWriter(bucket, getRequestAccessKey(r), writer)); err != nil {
 		errorIf(err, "Unable to write to client.")
 		if !dataWritten {
 			// Error response only if no data has been written to client yet. i.e if
@@ -192,17 +240,22 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if s3Error := checkGetRespHeaders(r); s3Error != ErrNone {
+		writeErrorResponseHeadersOnly(w, s3Error)
+		return
+	}
+
 	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponseHeadersOnly(w, s3Error)
 		return
 	}
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), bucket, object)
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
@@ -221,6 +274,9 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	// Set standard object headers.
 	setObjectHeaders(w, objInfo, nil)
 
+	// Set any additional requested response headers.
+	setGetRespHeaders(w, r.URL.Query())
+
 	// Successful response.
 	w.WriteHeader(http.StatusOK)
 }
@@ -281,6 +337,17 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Requests should also be allowed to read the source.
+	if s3Error := checkCopySourceAccess(r, srcBucket, srcObject); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// The source bucket may be under maintenance.
+	if s3Error := globalServerModes.checkRequest(srcBucket, false); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Check if metadata directive is valid.
 	if !isMetadataDirectiveValid(r.Header) {
 		writeErrorResponse(w, ErrInvalidMetadataDirective, r.URL)
@@ -292,7 +359,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	// - if source and destination are same
 	// - if source and destination are different
 	// it is the sole mutating state.
-	objectDWLock := globalNSMutex.NewNSLock(dstBucket, dstObject)
+	objectDWLock := globalNSMutex.NewNSLock(r.Context(), dstBucket, dstObject)
 	objectDWLock.Lock()
 	defer objectDWLock.Unlock()
 
@@ -302,13 +369,13 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	if !cpSrcDstSame {
 		// Hold read locks on source object only if we are
 		// going to read data from source object.
-		objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
+		objectSRLock := globalNSMutex.NewNSLock(r.Context(), srcBucket, srcObject)
 		objectSRLock.RLock()
 		defer objectSRLock.RUnlock()
 
 	}
 
-	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), srcBucket, srcObject)
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -325,6 +392,10 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
 		return
 	}
+	if s3Error := checkRequestQuota(r, objInfo.Size); !cpSrcDstSame && s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	defaultMeta := objInfo.UserDefined
 
@@ -334,6 +405,8 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	delete(defaultMeta, "md5Sum")
 
 	newMetadata := getCpObjMetadataFromHeader(r.Header, defaultMeta)
+	// Copies are owned by the identity copying them.
+	setObjectOwner(newMetadata, getRequestAccessKey(r))
 	// Check if x-amz-metadata-directive was not set to REPLACE and source,
 	// desination are same objects.
 	if !isMetadataReplace(r.Header) && cpSrcDstSame {
@@ -342,10 +415,14 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponse(w, ErrInvalidCopyDest, r.URL)
 		return
 	}
+	if cpSrcDstSame {
+		// Data is unchanged when only metadata is updated, keep its md5sum.
+		newMetadata["md5Sum"] = objInfo.MD5Sum
+	}
 
 	// Copy source object to destination, if source and destination
 	// object is same then only metadata is updated.
-	objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	objInfo, err = objectAPI.CopyObject(r.Context(), srcBucket, srcObject, dstBucket, dstObject, newMetadata)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -396,6 +473,13 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		writeErrorResponse(w, ErrInvalidDigest, r.URL)
 		return
 	}
+	// Bucket might require a Content-Md5 on all uploads.
+	if s3Error := checkBucketIntegrity(bucket, r.Header); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// Uploads are limited to the upload bandwidth of the bucket and of the user.
+	r.Body = ioutil.NopCloser(globalBucketBandwidth.ThrottleReader(bucket, getRequestAccessKey(r), r.Body))
 
 	/// if Content-Length is unknown/missing, deny the request
 	size := r.ContentLength
@@ -422,13 +506,14 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 
 	// Extract metadata to be saved from incoming HTTP header.
 	metadata := extractMetadataFromHeader(r.Header)
+	setObjectOwner(metadata, getRequestAccessKey(r))
 	// Make sure we hex encode md5sum here.
 	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
 
 	sha256sum := ""
 
 	// Lock the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
@@ -440,12 +525,12 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := checkAnonymousAccess(r, bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = objectAPI.PutObject(r.Context(), bucket, object, size, r.Body, metadata, sha256sum)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
@@ -454,7 +539,15 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
+		if s3Error = checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		objInfo, err = objectAPI.PutObject(r.Context(), bucket, object, size, reader, metadata, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
@@ -462,18 +555,42 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		if s3Error = checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkPresignedRequest(r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		objInfo, err = objectAPI.PutObject(r.Context(), bucket, object, size, r.Body, metadata, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
+		if s3Error := checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkPresignedRequest(r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
 		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = objectAPI.PutObject(r.Context(), bucket, object, size, r.Body, metadata, sha256sum)
 	}
 	if err != nil {
 		errorIf(err, "Unable to create an object.")
@@ -516,8 +633,9 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
+	setObjectOwner(metadata, getRequestAccessKey(r))
 
-	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
+	uploadID, err := objectAPI.NewMultipartUpload(r.Context(), bucket, object, metadata)
 	if err != nil {
 		errorIf(err, "Unable to initiate new multipart upload id.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -549,6 +667,13 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		writeErrorResponse(w, ErrInvalidDigest, r.URL)
 		return
 	}
+	// Bucket might require a Content-Md5 on all uploads.
+	if s3Error := checkBucketIntegrity(bucket, r.Header); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// Uploads are limited to the upload bandwidth of the bucket and of the user.
+	r.Body = ioutil.NopCloser(globalBucketBandwidth.ThrottleReader(bucket, getRequestAccessKey(r), r.Body))
 
 	/// if Content-Length is unknown/missing, throw away
 	size := r.ContentLength
@@ -580,13 +705,13 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 
 	partID, err := strconv.Atoi(partIDString)
 	if err != nil {
-		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		writeErrorResponse(w, ErrInvalidPartNumber, r.URL)
 		return
 	}
 
-	// check partID with maximum part ID for multipart objects
-	if isMaxPartID(partID) {
-		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+	// check partID with minimum and maximum part ID for multipart objects
+	if partID < 1 || isMaxPartID(partID) {
+		writeErrorResponse(w, ErrInvalidPartNumber, r.URL)
 		return
 	}
 
@@ -600,12 +725,12 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := checkAnonymousAccess(r, bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		// No need to verify signature, anonymous request access is already allowed.
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		partMD5, err = objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
@@ -614,7 +739,15 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
+		if s3Error = checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		partMD5, err = objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
@@ -622,18 +755,42 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		if s3Error = checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkPresignedRequest(r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		partMD5, err = objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
+		if s3Error := checkIAMAccess(r, "s3:PutObject"); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkRequestQuota(r, size); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkPresignedRequest(r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
 
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		partMD5, err = objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	}
 	if err != nil {
 		errorIf(err, "Unable to create object part.")
@@ -666,7 +823,7 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 	}
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
-	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
+	if err := objectAPI.AbortMultipartUpload(r.Context(), bucket, object, uploadID); err != nil {
 		errorIf(err, "Unable to abort multipart upload.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -700,7 +857,11 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
 		return
 	}
-	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+	// S3 lists at most maxPartsList parts per response.
+	if maxParts > maxPartsList {
+		maxParts = maxPartsList
+	}
+	listPartsInfo, err := objectAPI.ListObjectParts(r.Context(), bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
 		errorIf(err, "Unable to list uploaded parts.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -763,11 +924,11 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	}
 
 	// Hold write lock on the object.
-	destLock := globalNSMutex.NewNSLock(bucket, object)
+	destLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	destLock.Lock()
 	defer destLock.Unlock()
 
-	objInfo, err := objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
+	objInfo, err := objectAPI.CompleteMultipartUpload(r.Context(), bucket, object, uploadID, completeParts)
 	if err != nil {
 		errorIf(err, "Unable to complete multipart upload.")
 		err = errorCause(err)
@@ -829,14 +990,14 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
 	/// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	/// Ignore delete object errors, since we are suppposed to reply
 	/// only 204.
-	if err := objectAPI.DeleteObject(bucket, object); err != nil {
+	if err := objectAPI.DeleteObject(r.Context(), bucket, object); err != nil {
 		writeSuccessNoContent(w)
 		return
 	}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// Browser paths followed by the bucket and the object they access.
+var browserObjectPaths = []string{
+	reservedBucket + "/upload/",
+	reservedBucket + "/upload-part/",
+	reservedBucket + "/download/",
+}
+
+// Query parameters of the S3 API holding object names or prefixes.
+var objectNameQueries = []string{
+	"prefix",
+	"marker",
+	"start-after",
+	"key-marker",
+}
+
+// hasBadPathComponent - returns true if a segment of p, separated by
+// slashes or backslashes, is "." or "..", which resolve out of their
+// parent directory once joined. Windows also drops the trailing dots
+// and spaces of file names, so that "..." and ". " are as bad there.
+func hasBadPathComponent(p string) bool {
+	segments := strings.FieldsFunc(p, func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+	for _, segment := range segments {
+		if segment == "." || segment == ".." {
+			return true
+		}
+		if runtime.GOOS == "windows" && strings.Trim(segment, ". ") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinioReservedBucket - returns true if bucket is the bucket of the
+// server metadata, which S3 requests have no access to whatever their
+// credentials or the policies.
+func isMinioReservedBucket(bucket string) bool {
+	return strings.EqualFold(bucket, minioMetaBucket) ||
+		strings.HasPrefix(strings.ToLower(bucket), minioMetaBucket+slashSeparator)
+}
+
+// checkBucketObjectPath - returns the error of accessing object of
+// bucket from an S3 or browser request, ErrNone if it stays within a
+// bucket of the S3 API.
+func checkBucketObjectPath(bucket, object string) APIErrorCode {
+	if isMinioReservedBucket(bucket) {
+		return ErrAllAccessDisabled
+	}
+	if hasBadPathComponent(bucket) {
+		return ErrInvalidBucketName
+	}
+	if hasBadPathComponent(object) {
+		return ErrInvalidObjectName
+	}
+	return ErrNone
+}
+
+// checkRequestPaths - checks the bucket and object of the path of r,
+// and of its copy source and object name query parameters, so that
+// no request reaches the server metadata or a path out of a bucket.
+func checkRequestPaths(r *http.Request) APIErrorCode {
+	urlPath := r.URL.Path
+	if strings.HasPrefix(urlPath, reservedBucket+slashSeparator) || urlPath == reservedBucket {
+		bucketPath := ""
+		for _, prefix := range browserObjectPaths {
+			if strings.HasPrefix(urlPath, prefix) {
+				bucketPath = strings.TrimPrefix(urlPath, prefix)
+				break
+			}
+		}
+		if bucketPath == "" {
+			// Not an object path, as for RPCs and assets.
+			if hasBadPathComponent(urlPath) {
+				return ErrAllAccessDisabled
+			}
+			return ErrNone
+		}
+		urlPath = bucketPath
+	}
+	if s3Error := checkBucketObjectPath(path2BucketAndObject(urlPath)); s3Error != ErrNone {
+		return s3Error
+	}
+
+	if copySource := r.Header.Get("X-Amz-Copy-Source"); copySource != "" {
+		if unescaped, err := url.QueryUnescape(copySource); err == nil {
+			copySource = unescaped
+		}
+		if s3Error := checkBucketObjectPath(path2BucketAndObject(copySource)); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+
+	values := r.URL.Query()
+	for _, query := range objectNameQueries {
+		if hasBadPathComponent(values.Get(query)) {
+			return ErrInvalidObjectName
+		}
+	}
+	return ErrNone
+}
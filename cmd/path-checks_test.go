@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests detecting the path segments resolving out of their parent.
+func TestHasBadPathComponent(t *testing.T) {
+	testCases := []struct {
+		path string
+		bad  bool
+	}{
+		{"", false},
+		{"object", false},
+		{"a/b/c/", false},
+		{"a..b/..c/d..", false},
+		{".minio.sys", false},
+		{"..", true},
+		{".", true},
+		{"a/../b", true},
+		{"a/./b", true},
+		{"../", true},
+		{"a\\..\\b", true},
+		{"/a//../b", true},
+	}
+	for i, testCase := range testCases {
+		if bad := hasBadPathComponent(testCase.path); bad != testCase.bad {
+			t.Errorf("Test %d: Expected %q to be bad %t, got %t", i+1, testCase.path, testCase.bad, bad)
+		}
+	}
+}
+
+// Tests the paths of the requests reaching the server metadata or out
+// of a bucket.
+func TestCheckRequestPaths(t *testing.T) {
+	testCases := []struct {
+		method     string
+		target     string
+		copySource string
+		s3Error    APIErrorCode
+	}{
+		// S3 requests within a bucket.
+		{"GET", "/", "", ErrNone},
+		{"GET", "/bucket", "", ErrNone},
+		{"GET", "/bucket/a/b..c", "", ErrNone},
+		{"GET", "/bucket?prefix=a/&marker=a/b", "", ErrNone},
+		{"PUT", "/bucket/object", "bucket/source", ErrNone},
+		// S3 requests on the server metadata.
+		{"GET", "/.minio.sys/config/config.json", "", ErrAllAccessDisabled},
+		{"GET", "/.minio.sys", "", ErrAllAccessDisabled},
+		{"GET", "/.MINIO.SYS/config/config.json", "", ErrAllAccessDisabled},
+		{"GET", "/%2Eminio.sys/config/config.json", "", ErrAllAccessDisabled},
+		{"PUT", "/bucket/object", ".minio.sys/config/config.json", ErrAllAccessDisabled},
+		{"PUT", "/bucket/object", "%2Eminio.sys%2Fconfig%2Fconfig.json", ErrAllAccessDisabled},
+		// S3 requests out of a bucket, encoded or not.
+		{"GET", "/bucket/../.minio.sys/config/config.json", "", ErrInvalidObjectName},
+		{"GET", "/bucket/%2e%2e/%2eminio.sys/config/config.json", "", ErrInvalidObjectName},
+		{"GET", "/bucket/a%2F..%2F..%2Fb", "", ErrInvalidObjectName},
+		{"GET", "/../bucket/object", "", ErrInvalidBucketName},
+		{"GET", "/bucket?prefix=../", "", ErrInvalidObjectName},
+		{"GET", "/bucket?prefix=a/&marker=a/../../b", "", ErrInvalidObjectName},
+		{"PUT", "/bucket/object", "bucket/../.minio.sys/config/config.json", ErrInvalidObjectName},
+		// Browser requests.
+		{"GET", "/minio/login", "", ErrNone},
+		{"PUT", "/minio/upload/bucket/object", "", ErrNone},
+		{"PUT", "/minio/upload/.minio.sys/config/config.json", "", ErrAllAccessDisabled},
+		{"GET", "/minio/download/bucket/../../.minio.sys/config/config.json?token=x", "", ErrInvalidObjectName},
+		{"GET", "/minio/../.minio.sys/config/config.json", "", ErrAllAccessDisabled},
+	}
+	for i, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.target, nil)
+		if testCase.copySource != "" {
+			req.Header.Set("X-Amz-Copy-Source", testCase.copySource)
+		}
+		if s3Error := checkRequestPaths(req); s3Error != testCase.s3Error {
+			t.Errorf("Test %d: %s %s: Expected %s, got %s", i+1, testCase.method, testCase.target,
+				getAPIError(testCase.s3Error).Code, getAPIError(s3Error).Code)
+		}
+	}
+}
+
+// Tests rejecting the requests for the server metadata before they are
+// routed, whatever their credentials.
+func TestPrivateBucketHandlerMetadata(t *testing.T) {
+	handler := setPrivateBucketHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	testCases := []struct {
+		target string
+		status int
+	}{
+		{"/bucket/object", http.StatusOK},
+		{"/.minio.sys/config/iam/users.json", http.StatusForbidden},
+		{"/bucket/..%2F.minio.sys%2Fconfig%2Fconfig.json", http.StatusBadRequest},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", testCase.target, nil))
+		if rec.Code != testCase.status {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.target, testCase.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// Scheme of the storage endpoints discovered from DNS SRV records,
+// as in dns+srv://_minio._tcp.minio.default.svc.cluster.local/export
+// for the disk /export of every server of the records.
+const dnsSRVScheme = "dns+srv"
+
+const (
+	// Interval between two lookups of the SRV records, while they
+	// do not list the expected number of servers yet.
+	discoveryRetryInterval = 5 * time.Second
+
+	// Time to wait for the SRV records to list the expected number
+	// of servers, as when the servers are starting at the same time.
+	maxDiscoveryWait = 10 * time.Minute
+)
+
+// lookupSRV - resolves the SRV records of a name, replaced in tests.
+var lookupSRV = net.LookupSRV
+
+// srvDiscovery - storage endpoints of the servers listed by the SRV
+// records of name.
+type srvDiscovery struct {
+	name string
+	path string
+	// Expected number of servers, 0 if any number is expected.
+	servers int
+}
+
+// parseSRVDiscovery - parses a dns+srv storage endpoint, the optional
+// query parameter servers is the number of servers to wait for.
+func parseSRVDiscovery(arg string) (srvDiscovery, error) {
+	u, err := url.Parse(arg)
+	if err != nil {
+		return srvDiscovery{}, err
+	}
+	if u.Host == "" || u.Path == "" || u.Path == "/" {
+		return srvDiscovery{}, fmt.Errorf("Invalid endpoint ‘%s’, should be %s://name/path", arg, dnsSRVScheme)
+	}
+	d := srvDiscovery{name: u.Host, path: u.Path}
+	if value := u.Query().Get("servers"); value != "" {
+		if d.servers, err = strconv.Atoi(value); err != nil || d.servers <= 0 {
+			return srvDiscovery{}, fmt.Errorf("Invalid number of servers ‘%s’ in ‘%s’", value, arg)
+		}
+	}
+	return d, nil
+}
+
+// endpoints - returns the storage endpoints of the servers listed by
+// the SRV records addrs, sorted by host.
+func (d srvDiscovery) endpoints(addrs []*net.SRV) ([]string, error) {
+	var hosts []string
+	for _, addr := range addrs {
+		host := strings.TrimSuffix(addr.Target, ".")
+		port := strconv.Itoa(int(addr.Port))
+		if globalMinioHost == "" {
+			// Ports are only configurable with --address, the same
+			// on all the servers.
+			if port != globalMinioPort {
+				return nil, fmt.Errorf("Port %s of %s in the SRV records of %s should be the port %s of --address", port, host, d.name, globalMinioPort)
+			}
+		} else {
+			host = net.JoinHostPort(host, port)
+		}
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var endpoints []string
+	for _, host := range hosts {
+		endpoints = append(endpoints, "http://"+host+d.path)
+	}
+	return endpoints, nil
+}
+
+// discover - looks up the SRV records until they list the expected
+// number of servers, or any server if none is expected.
+func (d srvDiscovery) discover(retryInterval, maxWait time.Duration) ([]string, error) {
+	start := time.Now()
+	for {
+		_, addrs, err := lookupSRV("", "", d.name)
+		if err == nil && len(addrs) > 0 && (d.servers == 0 || len(addrs) == d.servers) {
+			return d.endpoints(addrs)
+		}
+		if time.Since(start) >= maxWait {
+			if err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("SRV records of %s list %d servers instead of %d", d.name, len(addrs), d.servers)
+		}
+		if err != nil {
+			console.Printf("Unable to look up the SRV records of %s, retrying. %s\n", d.name, err)
+		} else {
+			console.Printf("Waiting for the SRV records of %s to list %d servers, %d listed. (elapsed %s)\n", d.name, d.servers, len(addrs), time.Since(start))
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// discoverStorageEndpoints - returns the storage endpoints of args,
+// replacing the dns+srv endpoints by the endpoints of the servers
+// listed by their SRV records.
+func discoverStorageEndpoints(args []string) ([]string, error) {
+	var endpoints []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, dnsSRVScheme+"://") {
+			endpoints = append(endpoints, arg)
+			continue
+		}
+		d, err := parseSRVDiscovery(arg)
+		if err != nil {
+			return nil, err
+		}
+		discovered, err := d.discover(discoveryRetryInterval, maxDiscoveryWait)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, discovered...)
+	}
+	return endpoints, nil
+}
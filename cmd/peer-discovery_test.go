@@ -0,0 +1,112 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Tests the storage endpoints discovered from SRV records.
+func TestDiscoverStorageEndpoints(t *testing.T) {
+	savedLookupSRV, savedHost, savedPort := lookupSRV, globalMinioHost, globalMinioPort
+	defer func() {
+		lookupSRV, globalMinioHost, globalMinioPort = savedLookupSRV, savedHost, savedPort
+	}()
+
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		if name != "_minio._tcp.example.com" {
+			return "", nil, errors.New("no such host")
+		}
+		return name, []*net.SRV{
+			{Target: "node2.example.com.", Port: 9000},
+			{Target: "node1.example.com.", Port: 9000},
+		}, nil
+	}
+
+	testCases := []struct {
+		host      string
+		args      []string
+		endpoints []string
+		shouldErr bool
+	}{
+		// Endpoints without dns+srv are unchanged.
+		{"", []string{"/mnt/export1", "http://node3/mnt/export"}, []string{"/mnt/export1", "http://node3/mnt/export"}, false},
+		// Without an --address host, the port of the records is the port of --address.
+		{"", []string{"dns+srv://_minio._tcp.example.com/mnt/export"}, []string{"http://node1.example.com/mnt/export", "http://node2.example.com/mnt/export"}, false},
+		{"", []string{"dns+srv://_minio._tcp.example.com/mnt/export?servers=2"}, []string{"http://node1.example.com/mnt/export", "http://node2.example.com/mnt/export"}, false},
+		{"localhost", []string{"dns+srv://_minio._tcp.example.com/mnt/export"}, []string{"http://node1.example.com:9000/mnt/export", "http://node2.example.com:9000/mnt/export"}, false},
+		// Missing path.
+		{"", []string{"dns+srv://_minio._tcp.example.com"}, nil, true},
+		// Invalid number of servers.
+		{"", []string{"dns+srv://_minio._tcp.example.com/mnt/export?servers=x"}, nil, true},
+	}
+	for i, testCase := range testCases {
+		globalMinioHost, globalMinioPort = testCase.host, "9000"
+		endpoints, err := discoverStorageEndpoints(testCase.args)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %t, got %v", i+1, testCase.shouldErr, err)
+		}
+		if !reflect.DeepEqual(endpoints, testCase.endpoints) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.endpoints, endpoints)
+		}
+	}
+
+	// Records of another port than the port of --address.
+	globalMinioHost, globalMinioPort = "", "9001"
+	if _, err := discoverStorageEndpoints([]string{"dns+srv://_minio._tcp.example.com/mnt/export"}); err == nil {
+		t.Error("Expected an error for records of another port")
+	}
+}
+
+// Tests waiting for the SRV records to list the expected servers.
+func TestSRVDiscoveryWait(t *testing.T) {
+	savedLookupSRV, savedHost, savedPort := lookupSRV, globalMinioHost, globalMinioPort
+	defer func() {
+		lookupSRV, globalMinioHost, globalMinioPort = savedLookupSRV, savedHost, savedPort
+	}()
+	globalMinioHost, globalMinioPort = "", "9000"
+
+	lookups := 0
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		lookups++
+		var addrs []*net.SRV
+		for i := 0; i < lookups && i < 3; i++ {
+			addrs = append(addrs, &net.SRV{Target: "node" + strconv.Itoa(i+1), Port: 9000})
+		}
+		return name, addrs, nil
+	}
+
+	d := srvDiscovery{name: "_minio._tcp.example.com", path: "/export", servers: 3}
+	endpoints, err := d.discover(time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 3 || lookups != 3 {
+		t.Fatalf("Expected 3 endpoints after 3 lookups, got %v after %d lookups", endpoints, lookups)
+	}
+
+	// Records never listing the expected servers.
+	d.servers = 4
+	if _, err = d.discover(time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Fatal("Expected an error waiting for 4 servers")
+	}
+}
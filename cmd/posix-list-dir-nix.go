@@ -48,7 +48,9 @@ func clen(n []byte) int {
 
 // parseDirents - inspired from
 // https://golang.org/src/syscall/syscall_<os>.go
-func parseDirents(dirPath string, buf []byte) (entries []string, err error) {
+// Entries not starting with prefix are skipped before their d_type is
+// looked at, so that they never cost a Stat().
+func parseDirents(dirPath string, buf []byte, prefix string) (entries []string, err error) {
 	bufidx := 0
 	for bufidx < len(buf) {
 		dirent := (*syscall.Dirent)(unsafe.Pointer(&buf[bufidx]))
@@ -72,6 +74,10 @@ func parseDirents(dirPath string, buf []byte) (entries []string, err error) {
 		if hasPosixReservedPrefix(name) {
 			continue
 		}
+		// Skip entries not matching the prefix.
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
 
 		switch dirent.Type {
 		case syscall.DT_DIR:
@@ -116,6 +122,14 @@ var readDirBufPool = sync.Pool{
 
 // Return all the entries at the directory dirPath.
 func readDir(dirPath string) (entries []string, err error) {
+	return readDirWithPrefix(dirPath, "")
+}
+
+// Return the entries starting with prefix at the directory dirPath.
+// Directory entries are read readDirentBufSize bytes at a time, their
+// d_type telling files from directories without a Stat() except on
+// filesystems not filling it in, and for symbolic links.
+func readDirWithPrefix(dirPath, prefix string) (entries []string, err error) {
 	bufp := readDirBufPool.Get().(*[]byte)
 	buf := *bufp
 	defer readDirBufPool.Put(bufp)
@@ -148,7 +162,7 @@ func readDir(dirPath string) (entries []string, err error) {
 			break
 		}
 		var tmpEntries []string
-		if tmpEntries, err = parseDirents(dirPath, buf[:nbuf]); err != nil {
+		if tmpEntries, err = parseDirents(dirPath, buf[:nbuf], prefix); err != nil {
 			return nil, err
 		}
 		entries = append(entries, tmpEntries...)
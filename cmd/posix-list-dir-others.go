@@ -25,8 +25,16 @@ import (
 	"strings"
 )
 
+// Number of directory entries read at a time.
+const readDirCount = 4096
+
 // Return all the entries at the directory dirPath.
 func readDir(dirPath string) (entries []string, err error) {
+	return readDirWithPrefix(dirPath, "")
+}
+
+// Return the entries starting with prefix at the directory dirPath.
+func readDirWithPrefix(dirPath, prefix string) (entries []string, err error) {
 	d, err := os.Open(preparePath(dirPath))
 	if err != nil {
 		// File is really not found.
@@ -43,8 +51,8 @@ func readDir(dirPath string) (entries []string, err error) {
 	defer d.Close()
 
 	for {
-		// Read 1000 entries.
-		fis, err := d.Readdir(1000)
+		// Read readDirCount entries.
+		fis, err := d.Readdir(readDirCount)
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -56,6 +64,10 @@ func readDir(dirPath string) (entries []string, err error) {
 			if hasPosixReservedPrefix(fi.Name()) {
 				continue
 			}
+			// Skip entries not matching the prefix.
+			if !strings.HasPrefix(fi.Name(), prefix) {
+				continue
+			}
 			// Stat symbolic link and follow to get the final value.
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 				var st os.FileInfo
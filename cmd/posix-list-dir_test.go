@@ -223,3 +223,40 @@ func TestReadDir(t *testing.T) {
 		}
 	}
 }
+
+// TestReadDirWithPrefix - tests only the entries with the prefix are read.
+func TestReadDirWithPrefix(t *testing.T) {
+	dir := mustSetupDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"photo-1", "photo-2", "video-1"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"photos", "videos"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		prefix  string
+		entries []string
+	}{
+		{"", []string{"photo-1", "photo-2", "photos/", "video-1", "videos/"}},
+		{"photo", []string{"photo-1", "photo-2", "photos/"}},
+		{"video-", []string{"video-1"}},
+		{"audio", nil},
+	}
+	for i, testCase := range testCases {
+		entries, err := readDirWithPrefix(dir, testCase.prefix)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		sort.Strings(entries)
+		if !checkResult(testCase.entries, entries) {
+			t.Errorf("Test %d: expected = %s, got: %s", i+1, testCase.entries, entries)
+		}
+	}
+}
@@ -19,6 +19,7 @@
 package cmd
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path"
@@ -106,7 +107,7 @@ func TestIsValidUmaskFile(t *testing.T) {
 
 	// Attempt to create a file to verify the permissions later.
 	// AppendFile creates file with 0666 perms.
-	if err = disk.AppendFile(testCase.volName, "hello-world.txt", []byte("Hello World")); err != nil {
+	if err = disk.AppendFile(context.Background(), testCase.volName, "hello-world.txt", []byte("Hello World")); err != nil {
 		t.Fatalf("Create a file `test` failed with %s expected to pass.", err)
 	}
 
@@ -20,6 +20,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"strings"
 	"testing"
@@ -60,7 +61,7 @@ func TestUNCPaths(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		err = fs.AppendFile("voldir", test.objName, []byte("hello"))
+		err = fs.AppendFile(context.Background(), "voldir", test.objName, []byte("hello"))
 		if err != nil && test.pass {
 			t.Error(err)
 		} else if err == nil && !test.pass {
@@ -93,14 +94,14 @@ func TestUNCPathENOTDIR(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = fs.AppendFile("voldir", "/file", []byte("hello"))
+	err = fs.AppendFile(context.Background(), "voldir", "/file", []byte("hello"))
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Try to create a file that includes a file in its path components.
 	// In *nix, this returns syscall.ENOTDIR while in windows we receive the following error.
-	err = fs.AppendFile("voldir", "/file/obj1", []byte("hello"))
+	err = fs.AppendFile(context.Background(), "voldir", "/file/obj1", []byte("hello"))
 	if err != errFileAccessDenied {
 		t.Errorf("expected: %s, got: %s", errFileAccessDenied, err)
 	}
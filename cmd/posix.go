@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
@@ -71,6 +72,16 @@ func checkPathLength(pathName string) error {
 	return nil
 }
 
+// checkFilePath - returns errFileAccessDenied if filePath, joined from
+// the volume directory volumeDir and a path of the volume, resolves out
+// of the volume, else returns the error of checkPathLength.
+func checkFilePath(volumeDir, filePath string) error {
+	if filePath != volumeDir && !strings.HasPrefix(filePath, retainSlash(volumeDir)) {
+		return errFileAccessDenied
+	}
+	return checkPathLength(preparePath(filePath))
+}
+
 // isDirEmpty - returns whether given directory is empty or not.
 func isDirEmpty(dirname string) bool {
 	f, err := os.Open(preparePath(dirname))
@@ -214,7 +225,7 @@ func (s *posix) DiskInfo() (info disk.Info, err error) {
 // compatible way for all operating systems. If volume is not found
 // an error is generated.
 func (s *posix) getVolDir(volume string) (string, error) {
-	if !isValidVolname(volume) {
+	if !isValidVolname(volume) || hasBadPathComponent(volume) {
 		return "", errInvalidArgument
 	}
 	volumeDir := pathJoin(s.diskPath, volume)
@@ -435,7 +446,11 @@ func (s *posix) ListDir(volume, dirPath string) (entries []string, err error) {
 		}
 		return nil, err
 	}
-	return readDir(pathJoin(volumeDir, dirPath))
+	dirPath = pathJoin(volumeDir, dirPath)
+	if err = checkFilePath(volumeDir, dirPath); err != nil {
+		return nil, err
+	}
+	return readDir(dirPath)
 }
 
 // ReadAll reads from r until an error or EOF and returns the data it read.
@@ -474,7 +489,7 @@ func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
 
 	// Validate file path length, before reading.
 	filePath := pathJoin(volumeDir, path)
-	if err = checkPathLength(preparePath(filePath)); err != nil {
+	if err = checkFilePath(volumeDir, filePath); err != nil {
 		return nil, err
 	}
 
@@ -510,7 +525,7 @@ func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
 // ReadFull returns ErrUnexpectedEOF.
 // Additionally ReadFile also starts reading from an offset.
 // ReadFile symantics are same as io.ReadFull
-func (s *posix) ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error) {
+func (s *posix) ReadFile(ctx context.Context, volume string, path string, offset int64, buf []byte) (n int64, err error) {
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
@@ -521,6 +536,11 @@ func (s *posix) ReadFile(volume string, path string, offset int64, buf []byte) (
 		return 0, errFaultyDisk
 	}
 
+	// Nobody waits for the data anymore.
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	if err = s.checkDiskFound(); err != nil {
 		return 0, err
 	}
@@ -540,7 +560,7 @@ func (s *posix) ReadFile(volume string, path string, offset int64, buf []byte) (
 
 	// Validate effective path length before reading.
 	filePath := pathJoin(volumeDir, path)
-	if err = checkPathLength(preparePath(filePath)); err != nil {
+	if err = checkFilePath(volumeDir, filePath); err != nil {
 		return 0, err
 	}
 
@@ -612,7 +632,7 @@ func (s *posix) createFile(volume, path string) (f *os.File, err error) {
 	}
 
 	filePath := pathJoin(volumeDir, path)
-	if err = checkPathLength(preparePath(filePath)); err != nil {
+	if err = checkFilePath(volumeDir, filePath); err != nil {
 		return nil, err
 	}
 
@@ -702,8 +722,9 @@ func (s *posix) PrepareFile(volume, path string, fileSize int64) (err error) {
 }
 
 // AppendFile - append a byte array at path, if file doesn't exist at
-// path this call explicitly creates it.
-func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
+// path this call explicitly creates it. Nothing is appended once ctx
+// is done.
+func (s *posix) AppendFile(ctx context.Context, volume, path string, buf []byte) (err error) {
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
@@ -714,6 +735,10 @@ func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
 		return errFaultyDisk
 	}
 
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
 	// Create file if not found
 	w, err := s.createFile(volume, path)
 	if err != nil {
@@ -763,7 +788,7 @@ func (s *posix) StatFile(volume, path string) (file FileInfo, err error) {
 	}
 
 	filePath := slashpath.Join(volumeDir, path)
-	if err = checkPathLength(preparePath(filePath)); err != nil {
+	if err = checkFilePath(volumeDir, filePath); err != nil {
 		return FileInfo{}, err
 	}
 	st, err := os.Stat(preparePath(filePath))
@@ -861,10 +886,17 @@ func (s *posix) DeleteFile(volume, path string) (err error) {
 	// Following code is needed so that we retain "/" suffix if any in
 	// path argument.
 	filePath := pathJoin(volumeDir, path)
-	if err = checkPathLength(preparePath(filePath)); err != nil {
+	if err = checkFilePath(volumeDir, filePath); err != nil {
 		return err
 	}
 
+	// Shred the file contents of buckets with secure delete enabled.
+	if globalBucketSecureDelete.Enabled(volume) {
+		if err = shredFile(filePath); err != nil {
+			return err
+		}
+	}
+
 	// Delete file and delete parent directory as well if its empty.
 	return deleteFile(volumeDir, filePath)
 }
@@ -915,11 +947,11 @@ func (s *posix) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err e
 		return errFileAccessDenied
 	}
 	srcFilePath := slashpath.Join(srcVolumeDir, srcPath)
-	if err = checkPathLength(preparePath(srcFilePath)); err != nil {
+	if err = checkFilePath(srcVolumeDir, srcFilePath); err != nil {
 		return err
 	}
 	dstFilePath := slashpath.Join(dstVolumeDir, dstPath)
-	if err = checkPathLength(preparePath(dstFilePath)); err != nil {
+	if err = checkFilePath(dstVolumeDir, dstFilePath); err != nil {
 		return err
 	}
 	if srcIsDir {
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
@@ -81,13 +82,13 @@ func TestPosixReadAll(t *testing.T) {
 	if err = posixStorage.MakeVol("exists"); err != nil {
 		t.Fatalf("Unable to create a volume \"exists\", %s", err)
 	}
-	if err = posixStorage.AppendFile("exists", "as-directory/as-file", []byte("Hello, World")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "exists", "as-directory/as-file", []byte("Hello, World")); err != nil {
 		t.Fatalf("Unable to create a file \"as-directory/as-file\", %s", err)
 	}
-	if err = posixStorage.AppendFile("exists", "as-file", []byte("Hello, World")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "exists", "as-file", []byte("Hello, World")); err != nil {
 		t.Fatalf("Unable to create a file \"as-file\", %s", err)
 	}
-	if err = posixStorage.AppendFile("exists", "as-file-parent", []byte("Hello, World")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "exists", "as-file-parent", []byte("Hello, World")); err != nil {
 		t.Fatalf("Unable to create a file \"as-file-parent\", %s", err)
 	}
 
@@ -558,10 +559,10 @@ func TestPosixPosixListDir(t *testing.T) {
 	if err = posixStorage.MakeVol("success-vol"); err != nil {
 		t.Fatalf("Unable to create volume, %s", err)
 	}
-	if err = posixStorage.AppendFile("success-vol", "abc/def/ghi/success-file", []byte("Hello, world")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "success-vol", "abc/def/ghi/success-file", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
-	if err = posixStorage.AppendFile("success-vol", "abc/xyz/ghi/success-file", []byte("Hello, world")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "success-vol", "abc/xyz/ghi/success-file", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
@@ -696,7 +697,7 @@ func TestPosixDeleteFile(t *testing.T) {
 	if err = posixStorage.MakeVol("success-vol"); err != nil {
 		t.Fatalf("Unable to create volume, %s", err)
 	}
-	if err = posixStorage.AppendFile("success-vol", "success-file", []byte("Hello, world")); err != nil {
+	if err = posixStorage.AppendFile(context.Background(), "success-vol", "success-file", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
@@ -922,7 +923,7 @@ func TestPosixReadFile(t *testing.T) {
 
 	// Create test files for further reading.
 	for i, appendFile := range appendFiles {
-		err = posixStorage.AppendFile(volume, appendFile.fileName, []byte("hello, world"))
+		err = posixStorage.AppendFile(context.Background(), volume, appendFile.fileName, []byte("hello, world"))
 		if err != appendFile.expectedErr {
 			t.Fatalf("Creating file failed: %d %#v, expected: %s, got: %s", i+1, appendFile, appendFile.expectedErr, err)
 		}
@@ -933,7 +934,7 @@ func TestPosixReadFile(t *testing.T) {
 		var n int64
 		// Common read buffer.
 		var buf = make([]byte, testCase.bufSize)
-		n, err = posixStorage.ReadFile(testCase.volume, testCase.fileName, testCase.offset, buf)
+		n, err = posixStorage.ReadFile(context.Background(), testCase.volume, testCase.fileName, testCase.offset, buf)
 		if err != nil && testCase.expectedErr != nil {
 			// Validate if the type string of the errors are an exact match.
 			if err.Error() != testCase.expectedErr.Error() {
@@ -994,7 +995,7 @@ func TestPosixReadFile(t *testing.T) {
 		if err == nil {
 			// Common read buffer.
 			var buf = make([]byte, 10)
-			if _, err = posixStorage.ReadFile("proc", "1/fd", 0, buf); err != errFileAccessDenied {
+			if _, err = posixStorage.ReadFile(context.Background(), "proc", "1/fd", 0, buf); err != errFileAccessDenied {
 				t.Errorf("expected: %s, got: %s", errFileAccessDenied, err)
 			}
 		}
@@ -1008,7 +1009,7 @@ func TestPosixReadFile(t *testing.T) {
 		posixType.ioErrCount = int32(6)
 		// Common read buffer.
 		var buf = make([]byte, 10)
-		_, err = posixType.ReadFile("abc", "yes", 0, buf)
+		_, err = posixType.ReadFile(context.Background(), "abc", "yes", 0, buf)
 		if err != errFaultyDisk {
 			t.Fatalf("Expected \"Faulty Disk\", got: \"%s\"", err)
 		}
@@ -1069,7 +1070,7 @@ func TestPosixAppendFile(t *testing.T) {
 	}{"level0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001/level0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002/level0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003/object000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001", err})
 
 	for _, testCase := range testCases {
-		if err = posixStorage.AppendFile("success-vol", testCase.fileName, []byte("hello, world")); err != testCase.expectedErr {
+		if err = posixStorage.AppendFile(context.Background(), "success-vol", testCase.fileName, []byte("hello, world")); err != testCase.expectedErr {
 			t.Errorf("Case: %s, expected: %s, got: %s", testCase, testCase.expectedErr, err)
 		}
 	}
@@ -1082,13 +1083,13 @@ func TestPosixAppendFile(t *testing.T) {
 			t.Fatalf("Unable to initialize posix, %s", err)
 		}
 
-		if err = posixStorage.AppendFile("bin", "yes", []byte("hello, world")); !os.IsPermission(err) {
+		if err = posixStorage.AppendFile(context.Background(), "bin", "yes", []byte("hello, world")); !os.IsPermission(err) {
 			t.Errorf("expected: Permission error, got: %s", err)
 		}
 	}
 	// TestPosix case with invalid volume name.
 	// A valid volume name should be atleast of size 3.
-	err = posixStorage.AppendFile("bn", "yes", []byte("hello, world"))
+	err = posixStorage.AppendFile(context.Background(), "bn", "yes", []byte("hello, world"))
 	if err != errInvalidArgument {
 		t.Fatalf("expected: \"Invalid argument error\", got: \"%s\"", err)
 	}
@@ -1100,7 +1101,7 @@ func TestPosixAppendFile(t *testing.T) {
 	if posixType, ok := posixStorage.(*posix); ok {
 		// setting the io error count from as specified in the test case.
 		posixType.ioErrCount = int32(6)
-		err = posixType.AppendFile("abc", "yes", []byte("hello, world"))
+		err = posixType.AppendFile(context.Background(), "abc", "yes", []byte("hello, world"))
 		if err != errFaultyDisk {
 			t.Fatalf("Expected \"Faulty Disk\", got: \"%s\"", err)
 		}
@@ -1109,6 +1110,36 @@ func TestPosixAppendFile(t *testing.T) {
 	}
 }
 
+// TestPosix posix.ReadFile() and posix.AppendFile() once the context
+// is canceled.
+func TestPosixCanceledContext(t *testing.T) {
+	posixStorage, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer removeAll(path)
+
+	if err = posixStorage.MakeVol("success-vol"); err != nil {
+		t.Fatalf("Unable to create volume, %s", err)
+	}
+	if err = posixStorage.AppendFile(context.Background(), "success-vol", "myobject", []byte("hello, world")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf := make([]byte, 5)
+	if _, err = posixStorage.ReadFile(ctx, "success-vol", "myobject", 0, buf); err != context.Canceled {
+		t.Errorf("Expected ReadFile to fail with %v, got %v", context.Canceled, err)
+	}
+	if err = posixStorage.AppendFile(ctx, "success-vol", "myobject", []byte("ignored")); err != context.Canceled {
+		t.Errorf("Expected AppendFile to fail with %v, got %v", context.Canceled, err)
+	}
+	if fi, err := posixStorage.StatFile("success-vol", "myobject"); err != nil || fi.Size != int64(len("hello, world")) {
+		t.Errorf("Expected nothing to be appended, got %v %v", fi, err)
+	}
+}
+
 // TestPosix posix.PrepareFile()
 func TestPosixPrepareFile(t *testing.T) {
 	// create posix test setup
@@ -1221,24 +1252,24 @@ func TestPosixRenameFile(t *testing.T) {
 		t.Fatalf("Unable to create volume, %s", err)
 	}
 
-	if err := posixStorage.AppendFile("src-vol", "file1", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "file1", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
-	if err := posixStorage.AppendFile("src-vol", "file2", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "file2", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
-	if err := posixStorage.AppendFile("src-vol", "file3", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "file3", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
-	if err := posixStorage.AppendFile("src-vol", "file4", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "file4", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
-	if err := posixStorage.AppendFile("src-vol", "file5", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "file5", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
-	if err := posixStorage.AppendFile("src-vol", "path/to/file1", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "src-vol", "path/to/file1", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
@@ -1471,11 +1502,11 @@ func TestPosixStatFile(t *testing.T) {
 		t.Fatalf("Unable to create volume, %s", err)
 	}
 
-	if err := posixStorage.AppendFile("success-vol", "success-file", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "success-vol", "success-file", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
-	if err := posixStorage.AppendFile("success-vol", "path/to/success-file", []byte("Hello, world")); err != nil {
+	if err := posixStorage.AppendFile(context.Background(), "success-vol", "path/to/success-file", []byte("Hello, world")); err != nil {
 		t.Fatalf("Unable to create file, %s", err)
 	}
 
@@ -1556,3 +1587,53 @@ func TestPosixStatFile(t *testing.T) {
 		}
 	}
 }
+
+// Test posix refusing the paths resolving out of their volume.
+func TestPosixPathEscape(t *testing.T) {
+	// create posix test setup
+	posixStorage, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer removeAll(path)
+
+	for _, volume := range []string{"exists", "secret"} {
+		if err = posixStorage.MakeVol(volume); err != nil {
+			t.Fatalf("Unable to create a volume %q, %s", volume, err)
+		}
+	}
+	if err = posixStorage.AppendFile(context.Background(), "secret", "config.json", []byte("secret")); err != nil {
+		t.Fatalf("Unable to create a file \"config.json\", %s", err)
+	}
+
+	escapePath := "../secret/config.json"
+	if _, err = posixStorage.ReadAll("exists", escapePath); err != errFileAccessDenied {
+		t.Errorf("ReadAll: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if _, err = posixStorage.ReadFile(context.Background(), "exists", escapePath, 0, make([]byte, 6)); err != errFileAccessDenied {
+		t.Errorf("ReadFile: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if _, err = posixStorage.StatFile("exists", escapePath); err != errFileAccessDenied {
+		t.Errorf("StatFile: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if _, err = posixStorage.ListDir("exists", "../secret/"); err != errFileAccessDenied {
+		t.Errorf("ListDir: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if err = posixStorage.AppendFile(context.Background(), "exists", escapePath, []byte("overwritten")); err != errFileAccessDenied {
+		t.Errorf("AppendFile: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if err = posixStorage.RenameFile("exists", "../secret/config.json", "exists", "config.json"); err != errFileAccessDenied {
+		t.Errorf("RenameFile: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if err = posixStorage.DeleteFile("exists", escapePath); err != errFileAccessDenied {
+		t.Errorf("DeleteFile: Expected %s, got %v", errFileAccessDenied, err)
+	}
+	if _, err = posixStorage.ListDir("../"+slashpath.Base(path), ""); err != errInvalidArgument {
+		t.Errorf("ListDir: Expected %s for a volume out of the disk, got %v", errInvalidArgument, err)
+	}
+
+	// Paths within the volume are not refused.
+	if buf, err := posixStorage.ReadAll("secret", "dir/../config.json"); err != nil || string(buf) != "secret" {
+		t.Errorf("ReadAll: Expected the file within the volume, got %q, %v", buf, err)
+	}
+}
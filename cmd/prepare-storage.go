@@ -238,6 +238,7 @@ func retryFormattingXLDisks(firstDisk bool, endpoints []*url.URL, storageDisks [
 			case FormatDisks:
 				console.Eraseline()
 				printFormatMsg(endpoints, storageDisks, printOnceFn())
+				sdNotifyStatus("Formatting %d disks.", len(storageDisks))
 				return initFormatXL(storageDisks)
 			case InitObjectLayer:
 				console.Eraseline()
@@ -252,6 +253,7 @@ func retryFormattingXLDisks(firstDisk bool, endpoints []*url.URL, storageDisks [
 				err := genericFormatCheckXL(formatConfigs, sErrs)
 				if err == nil {
 					printHealMsg(endpoints, storageDisks, printOnceFn())
+					sdNotifyStatus("Some disks need healing, use 'mc admin heal' to heal them.")
 				}
 				return err
 			case WaitForQuorum:
@@ -259,13 +261,17 @@ func retryFormattingXLDisks(firstDisk bool, endpoints []*url.URL, storageDisks [
 					"Initializing data volume. Waiting for minimum %d servers to come online. (elapsed %s)\n",
 					len(storageDisks)/2+1, getElapsedTime(),
 				)
+				sdNotifyStatus("Waiting for minimum %d servers to come online. (elapsed %s)", len(storageDisks)/2+1, getElapsedTime())
 			case WaitForConfig:
 				// Print configuration errors.
 				printConfigErrMsg(storageDisks, sErrs, printOnceFn())
+				sdNotifyStatus("Waiting for all servers to have the same configuration.")
 			case WaitForAll:
 				console.Printf("Initializing data volume for first time. Waiting for other servers to come online (elapsed %s)\n", getElapsedTime())
+				sdNotifyStatus("Waiting for other servers to come online. (elapsed %s)", getElapsedTime())
 			case WaitForFormatting:
 				console.Printf("Initializing data volume for first time. Waiting for first server to come online (elapsed %s)\n", getElapsedTime())
+				sdNotifyStatus("Waiting for first server to format disks. (elapsed %s)", getElapsedTime())
 			}
 		case <-globalServiceDoneCh:
 			return errors.New("Initializing data volumes gracefully stopped")
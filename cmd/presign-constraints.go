@@ -0,0 +1,173 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query parameters of presigned URLs constraining their use, signed
+// along with the URL by signature V4.
+const (
+	// Comma separated IP addresses or CIDR ranges the requests
+	// should come from, e.g "10.0.0.0/8,192.168.1.7".
+	presignSourceIPQuery = "X-Minio-Source-Ip"
+	// Largest size in bytes of the object uploaded or downloaded.
+	presignMaxSizeQuery = "X-Minio-Max-Content-Length"
+)
+
+// Environment variable setting the longest expiry of presigned URLs,
+// such as "24h".
+const presignMaxExpiryEnv = "MINIO_PRESIGN_MAX_EXPIRY"
+
+// Presigned URLs expire after at most a week by default, the longest
+// expiry allowed by signature V4.
+const defaultPresignMaxExpiry = 7 * 24 * time.Hour
+
+// Longest expiry of presigned URLs.
+var globalPresignMaxExpiry = defaultPresignMaxExpiry
+
+// getPresignMaxExpiry - returns the longest expiry of presigned URLs
+// configured in the environment.
+func getPresignMaxExpiry() (time.Duration, error) {
+	value := os.Getenv(presignMaxExpiryEnv)
+	if value == "" {
+		return defaultPresignMaxExpiry, nil
+	}
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", presignMaxExpiryEnv, value, err)
+	}
+	if expiry < time.Second || expiry > defaultPresignMaxExpiry {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be between 1s and %s", presignMaxExpiryEnv, value, defaultPresignMaxExpiry)
+	}
+	return expiry, nil
+}
+
+// hasPresignConstraints - returns true if the presigned URL of r is
+// constrained.
+func hasPresignConstraints(r *http.Request) bool {
+	query := r.URL.Query()
+	_, sourceIP := query[presignSourceIPQuery]
+	_, maxSize := query[presignMaxSizeQuery]
+	return sourceIP || maxSize
+}
+
+// checkPresignedRequest - verifies the constraints of the presigned
+// URL of r if any. Constraints are not signed by signature V2, so
+// presigned V2 URLs can not be constrained.
+func checkPresignedRequest(r *http.Request) APIErrorCode {
+	switch getRequestAuthType(r) {
+	case authTypePresigned:
+		return checkPresignConstraints(r)
+	case authTypePresignedV2:
+		if hasPresignConstraints(r) {
+			return ErrInvalidQueryParams
+		}
+	}
+	return ErrNone
+}
+
+// checkPresignConstraints - verifies that a presigned request is sent
+// from an allowed source, with a body no larger than allowed.
+func checkPresignConstraints(r *http.Request) APIErrorCode {
+	if sources := r.URL.Query().Get(presignSourceIPQuery); sources != "" {
+		allowed, err := isSourceIPAllowed(r.RemoteAddr, sources)
+		if err != nil {
+			return ErrInvalidQueryParams
+		}
+		if !allowed {
+			return ErrAccessDenied
+		}
+	}
+
+	maxSize, ok, err := getPresignMaxSize(r)
+	if err != nil {
+		return ErrInvalidQueryParams
+	}
+	if ok && (r.Method == httpPUT || r.Method == httpPOST) {
+		if r.ContentLength < 0 {
+			return ErrMissingContentLength
+		}
+		if r.ContentLength > maxSize {
+			return ErrEntityTooLarge
+		}
+	}
+	return ErrNone
+}
+
+// getPresignMaxSize - returns the largest object size allowed by the
+// presigned URL of r, false if any size is allowed.
+func getPresignMaxSize(r *http.Request) (int64, bool, error) {
+	value := r.URL.Query().Get(presignMaxSizeQuery)
+	if value == "" {
+		return 0, false, nil
+	}
+	maxSize, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	if maxSize < 0 {
+		return 0, false, errInvalidArgument
+	}
+	return maxSize, true, nil
+}
+
+// isPresignMaxSizeExceeded - returns true if r is a presigned request
+// limiting the size of the downloads below length.
+func isPresignMaxSizeExceeded(r *http.Request, length int64) bool {
+	if getRequestAuthType(r) != authTypePresigned {
+		return false
+	}
+	maxSize, ok, _ := getPresignMaxSize(r)
+	return ok && length > maxSize
+}
+
+// isSourceIPAllowed - returns true if the host of remoteAddr is one of
+// the comma separated IP addresses or CIDR ranges of sources.
+func isSourceIPAllowed(remoteAddr, sources string) (bool, error) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+
+	allowed := false
+	for _, source := range strings.Split(sources, ",") {
+		source = strings.TrimSpace(source)
+		if !strings.Contains(source, "/") {
+			sourceIP := net.ParseIP(source)
+			if sourceIP == nil {
+				return false, errInvalidArgument
+			}
+			allowed = allowed || (ip != nil && sourceIP.Equal(ip))
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(source)
+		if err != nil {
+			return false, err
+		}
+		allowed = allowed || (ip != nil && ipNet.Contains(ip))
+	}
+	return allowed, nil
+}
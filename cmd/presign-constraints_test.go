@@ -0,0 +1,246 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the longest expiry of presigned URLs.
+func TestGetPresignMaxExpiry(t *testing.T) {
+	defer os.Unsetenv(presignMaxExpiryEnv)
+
+	testCases := []struct {
+		value      string
+		expiry     time.Duration
+		shouldPass bool
+	}{
+		{"", defaultPresignMaxExpiry, true},
+		{"1h", time.Hour, true},
+		{"168h", defaultPresignMaxExpiry, true},
+		{"169h", 0, false},
+		{"0s", 0, false},
+		{"hour", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(presignMaxExpiryEnv, testCase.value)
+		expiry, err := getPresignMaxExpiry()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if expiry != testCase.expiry {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expiry, expiry)
+		}
+	}
+}
+
+// Wrapper for calling the presigned URLs expiry tests for both XL multiple disks and single node setup.
+func TestPresignMaxExpiry(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func() { globalPresignMaxExpiry = defaultPresignMaxExpiry }()
+	ExecObjectLayerAPITest(t, testPresignMaxExpiry, []string{"GetObject"})
+}
+
+func testPresignMaxExpiry(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		maxExpiry  time.Duration
+		expires    int64
+		presignV2  bool
+		statusCode int
+	}{
+		// Test 1-2 - the longest expiry by default is a week.
+		{defaultPresignMaxExpiry, 7 * 24 * 3600, false, http.StatusOK},
+		{defaultPresignMaxExpiry, 7*24*3600 + 1, false, http.StatusBadRequest},
+		// Test 3-4 - shorter longest expiry.
+		{time.Hour, 3600, false, http.StatusOK},
+		{time.Hour, 3601, false, http.StatusBadRequest},
+		// Test 5-6 - signature V2.
+		{time.Hour, 3000, true, http.StatusOK},
+		{time.Hour, 2 * 3600, true, http.StatusBadRequest},
+	}
+	for i, testCase := range testCases {
+		globalPresignMaxExpiry = testCase.maxExpiry
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, "object"), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		if testCase.presignV2 {
+			err = preSignV2(req, credentials.AccessKey, credentials.SecretKey, testCase.expires)
+		} else {
+			err = preSignV4(req, credentials.AccessKey, credentials.SecretKey, testCase.expires)
+		}
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to presign HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`: %s",
+				instanceType, i+1, testCase.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// Tests matching the source of requests with IP addresses and CIDR ranges.
+func TestIsSourceIPAllowed(t *testing.T) {
+	testCases := []struct {
+		remoteAddr string
+		sources    string
+		allowed    bool
+		shouldPass bool
+	}{
+		{"10.1.2.3:4567", "10.0.0.0/8", true, true},
+		{"10.1.2.3:4567", "192.168.0.0/16, 10.1.2.3", true, true},
+		{"10.1.2.3:4567", "192.168.0.0/16,10.1.2.4", false, true},
+		{"[::1]:4567", "::1", true, true},
+		{"[::1]:4567", "127.0.0.1", false, true},
+		{"10.1.2.3", "10.1.2.3/32", true, true},
+		{"unknown", "10.0.0.0/8", false, true},
+		{"10.1.2.3:4567", "10.0.0.0/33", false, false},
+		{"10.1.2.3:4567", "localhost", false, false},
+	}
+	for i, testCase := range testCases {
+		allowed, err := isSourceIPAllowed(testCase.remoteAddr, testCase.sources)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected allowed to be %v, got %v", i+1, testCase.allowed, allowed)
+		}
+	}
+}
+
+// Wrapper for calling the constrained presigned requests tests for both XL multiple disks and single node setup.
+func TestPresignConstraints(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testPresignConstraints, []string{"GetObject", "PutObject"})
+}
+
+func testPresignConstraints(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		method      string
+		object      string
+		constraints url.Values
+		remoteAddr  string
+		presignV2   bool
+		statusCode  int
+	}{
+		// Test 1-2 - sources.
+		{"GET", "object", url.Values{presignSourceIPQuery: {"10.0.0.0/8"}}, "10.1.2.3:4567", false, http.StatusOK},
+		{"GET", "object", url.Values{presignSourceIPQuery: {"10.0.0.0/8"}}, "192.168.1.7:4567", false, http.StatusForbidden},
+		// Test 3-4 - size of downloads.
+		{"GET", "object", url.Values{presignMaxSizeQuery: {"12"}}, "10.1.2.3:4567", false, http.StatusOK},
+		{"GET", "object", url.Values{presignMaxSizeQuery: {"11"}}, "10.1.2.3:4567", false, http.StatusBadRequest},
+		// Test 5-6 - size of uploads.
+		{"PUT", "new-object", url.Values{presignMaxSizeQuery: {"12"}}, "10.1.2.3:4567", false, http.StatusOK},
+		{"PUT", "new-object", url.Values{presignMaxSizeQuery: {"11"}}, "10.1.2.3:4567", false, http.StatusBadRequest},
+		// Test 7-8 - invalid constraints.
+		{"GET", "object", url.Values{presignMaxSizeQuery: {"-1"}}, "10.1.2.3:4567", false, http.StatusBadRequest},
+		{"GET", "object", url.Values{presignSourceIPQuery: {"10.0.0.0/64"}}, "10.1.2.3:4567", false, http.StatusBadRequest},
+		// Test 9 - constraints are not signed by signature V2.
+		{"GET", "object", url.Values{presignSourceIPQuery: {"10.0.0.0/8"}}, "10.1.2.3:4567", true, http.StatusBadRequest},
+	}
+	for i, testCase := range testCases {
+		var body []byte
+		if testCase.method == "PUT" {
+			body = data
+		}
+		urlStr := makeTestTargetURL("", bucketName, testCase.object, testCase.constraints)
+		req, err := newTestRequest(testCase.method, urlStr, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		if testCase.presignV2 {
+			err = preSignV2(req, credentials.AccessKey, credentials.SecretKey, 600)
+		} else {
+			err = preSignV4(req, credentials.AccessKey, credentials.SecretKey, 600)
+		}
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to presign HTTP request: <ERROR> %v", instanceType, i+1, err)
+		}
+		req.RemoteAddr = testCase.remoteAddr
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`: %s",
+				instanceType, i+1, testCase.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Small objects are read without the lock in distributed mode, the
+	// size of their downloads is limited all the same.
+	if instanceType == XLTestStr {
+		globalIsDistXL = true
+		defer func() { globalIsDistXL = false }()
+		urlStr := makeTestTargetURL("", bucketName, "object", url.Values{presignMaxSizeQuery: {"11"}})
+		req, err := newTestRequest("GET", urlStr, 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		if err = preSignV4(req, credentials.AccessKey, credentials.SecretKey, 600); err != nil {
+			t.Fatalf("%s: Failed to presign HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: Expected the lock-free download to be rejected with `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+		}
+	}
+
+	// Constraints are signed.
+	urlStr := makeTestTargetURL("", bucketName, "object", url.Values{presignSourceIPQuery: {"10.0.0.0/8"}})
+	req, err := newTestRequest("GET", urlStr, 0, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	if err = preSignV4(req, credentials.AccessKey, credentials.SecretKey, 600); err != nil {
+		t.Fatalf("%s: Failed to presign HTTP request: <ERROR> %v", instanceType, err)
+	}
+	query := req.URL.Query()
+	query.Set(presignSourceIPQuery, "0.0.0.0/0")
+	req.URL.RawQuery = query.Encode()
+	req.RemoteAddr = "192.168.1.7:4567"
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("%s: Expected tampered constraints to be rejected with `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+}
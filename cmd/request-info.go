@@ -0,0 +1,56 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// requestInfo - the S3 API and ID of a request, as served.
+type requestInfo struct {
+	API       string
+	RequestID string
+}
+
+// Key of the context value holding the requestInfo.
+type requestInfoKey struct{}
+
+// contextWithRequestInfo - returns ctx carrying info of the request
+// it is derived from.
+func contextWithRequestInfo(ctx context.Context, info requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// getRequestInfo - returns the info of the request carried by ctx,
+// empty if ctx is not of an S3 API request.
+func getRequestInfo(ctx context.Context) requestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return info
+}
+
+// handlerAPIName - returns the name of the S3 API served by handler,
+// "GetObject" for objectAPIHandlers.GetObjectHandler.
+func handlerAPIName(handler interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	// Method values are suffixed by -fm.
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.TrimSuffix(name, "Handler")
+}
@@ -0,0 +1,73 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests naming S3 APIs after their handlers.
+func TestHandlerAPIName(t *testing.T) {
+	api := objectAPIHandlers{}
+	testCases := []struct {
+		handler  interface{}
+		expected string
+	}{
+		{api.GetObjectHandler, "GetObject"},
+		{api.PutObjectPartHandler, "PutObjectPart"},
+		{objectAPIHandlers.ListBucketsHandler, "ListBuckets"},
+		{metricsHandler, "metrics"},
+	}
+	for i, testCase := range testCases {
+		if name := handlerAPIName(testCase.handler); name != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, name)
+		}
+	}
+}
+
+// Tests requests served by the API classifier carry their info.
+func TestAPIRequestInfo(t *testing.T) {
+	var info requestInfo
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		info = getRequestInfo(r.Context())
+	}
+	c := apiRequestClassifier{}
+	for level := range c.routes {
+		c.routes[level] = make(map[string][]*apiRoute)
+	}
+	route := c.add(objectLevel, "GET", newAPIRoute(handler, "/{bucket}/{object:.+}", false))
+	route.name = "GetObject"
+
+	req, err := newTestRequest("GET", "/bucket/object", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	if info.API != "GetObject" || info.RequestID == "" {
+		t.Fatalf("Unexpected request info %#v", info)
+	}
+	if id := rec.Header().Get(responseRequestIDKey); id != info.RequestID {
+		t.Errorf("Expected request ID %s in the response, got %s", info.RequestID, id)
+	}
+	if info = getRequestInfo(context.Background()); info != (requestInfo{}) {
+		t.Errorf("Expected no request info, got %#v", info)
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"time"
 
 	"github.com/minio/minio/pkg/disk"
@@ -131,12 +132,12 @@ func (f retryStorage) PrepareFile(volume, path string, length int64) (err error)
 }
 
 // AppendFile - a retryable implementation of append to a file.
-func (f retryStorage) AppendFile(volume, path string, buffer []byte) (err error) {
-	err = f.remoteStorage.AppendFile(volume, path, buffer)
-	if err == errDiskNotFound {
+func (f retryStorage) AppendFile(ctx context.Context, volume, path string, buffer []byte) (err error) {
+	err = f.remoteStorage.AppendFile(ctx, volume, path, buffer)
+	if err == errDiskNotFound && ctx.Err() == nil {
 		err = f.reInit()
 		if err == nil {
-			return f.remoteStorage.AppendFile(volume, path, buffer)
+			return f.remoteStorage.AppendFile(ctx, volume, path, buffer)
 		}
 	}
 	return err
@@ -167,12 +168,12 @@ func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
 }
 
 // ReadFile - a retryable implementation of reading at offset from a file.
-func (f retryStorage) ReadFile(volume, path string, offset int64, buffer []byte) (m int64, err error) {
-	m, err = f.remoteStorage.ReadFile(volume, path, offset, buffer)
-	if err == errDiskNotFound {
+func (f retryStorage) ReadFile(ctx context.Context, volume, path string, offset int64, buffer []byte) (m int64, err error) {
+	m, err = f.remoteStorage.ReadFile(ctx, volume, path, offset, buffer)
+	if err == errDiskNotFound && ctx.Err() == nil {
 		err = f.reInit()
 		if err == nil {
-			return f.remoteStorage.ReadFile(volume, path, offset, buffer)
+			return f.remoteStorage.ReadFile(ctx, volume, path, offset, buffer)
 		}
 	}
 	return m, err
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -237,7 +238,7 @@ func TestRetryStorage(t *testing.T) {
 	}
 
 	for _, disk := range storageDisks {
-		if err = disk.AppendFile("existent", "path", []byte("Hello, World")); err != nil {
+		if err = disk.AppendFile(context.Background(), "existent", "path", []byte("Hello, World")); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -287,7 +288,7 @@ func TestRetryStorage(t *testing.T) {
 	for _, disk := range storageDisks {
 		var buf2 = make([]byte, 5)
 		var n int64
-		if n, err = disk.ReadFile("existent", "path", 7, buf2); err != nil {
+		if n, err = disk.ReadFile(context.Background(), "existent", "path", 7, buf2); err != nil {
 			t.Fatal(err)
 		}
 		if n != 5 {
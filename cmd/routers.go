@@ -79,6 +79,9 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 	// Add Admin router.
 	registerAdminRouter(mux)
 
+	// Add Prometheus metrics router.
+	registerMetricsRouter(mux)
+
 	// Add API router.
 	registerAPIRouter(mux)
 
@@ -105,6 +108,15 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		// routes them accordingly. Client receives a HTTP error for
 		// invalid/unsupported signatures.
 		setAuthHandler,
+		// Rejects requests not allowed in the current read-only or
+		// maintenance mode of the server or bucket.
+		setServerModeHandler,
+		// Compresses XML and JSON response bodies for clients
+		// accepting gzip content encoding.
+		setGzipHandler,
+		// Accounts the latency of requests, pausing background
+		// operations while the server is loaded.
+		setForegroundLatencyHandler,
 		// Add new handlers here.
 	}
 
@@ -22,15 +22,44 @@ import (
 	"github.com/minio/dsync"
 )
 
-// Allow any RPC call request time should be no more/less than 3 seconds.
-// 3 seconds is chosen arbitrarily.
-const rpcSkewTimeAllowed = 3 * time.Second
-
 func isRequestTimeAllowed(requestTime time.Time) bool {
 	// Check whether request time is within acceptable skew time.
 	utcNow := time.Now().UTC()
-	return !(requestTime.Sub(utcNow) > rpcSkewTimeAllowed ||
-		utcNow.Sub(requestTime) > rpcSkewTimeAllowed)
+	return !(requestTime.Sub(utcNow) > globalClockSkewTolerance ||
+		utcNow.Sub(requestTime) > globalClockSkewTolerance)
+}
+
+// Versions of the internode RPC protocol, negotiated at login. The
+// protocol version is increased when the format of an RPC message
+// changes, servers of different releases serving overlapping protocol
+// versions can run in the same cluster, as during a rolling upgrade.
+const (
+	// Latest protocol version served by this server.
+	rpcProtocolVersion = 1
+	// Oldest protocol version still served by this server.
+	minRPCProtocolVersion = 1
+)
+
+// Capabilities of the internode RPC servers, RPCs added after protocol
+// versioning are only sent to the servers announcing their capability
+// at login.
+const (
+	// Admin.AccessKeysLastUsed RPC.
+	rpcCapAccessKeysLastUsed = "admin.access-keys-last-used"
+	// Admin.ListRequests and Admin.KillRequest RPCs.
+	rpcCapInflightRequests = "admin.inflight-requests"
+	// Admin.ServerHealth RPC.
+	rpcCapServerHealth = "admin.server-health"
+	// Admin.PromoteStandby RPC.
+	rpcCapStandby = "admin.standby"
+)
+
+// Capabilities announced by this server at login.
+var rpcCapabilities = []string{
+	rpcCapAccessKeysLastUsed,
+	rpcCapInflightRequests,
+	rpcCapServerHealth,
+	rpcCapStandby,
 }
 
 // AuthRPCArgs represents minimum required arguments to make any authenticated RPC call.
@@ -78,12 +107,23 @@ type LoginRPCArgs struct {
 	Password    string
 	Version     string
 	RequestTime time.Time
+
+	// Range of protocol versions served by the client, zero for
+	// clients of releases before protocol versioning.
+	ProtocolVersion    int
+	MinProtocolVersion int
 }
 
 // IsValid - validates whether this LoginRPCArgs are valid for authentication.
 func (args LoginRPCArgs) IsValid() error {
-	// Check if version matches.
-	if args.Version != Version {
+	if args.ProtocolVersion == 0 {
+		// Clients not negotiating a protocol version are only
+		// served by servers of the same release.
+		if args.Version != Version {
+			return errServerVersionMismatch
+		}
+	} else if args.MinProtocolVersion > rpcProtocolVersion || args.ProtocolVersion < minRPCProtocolVersion {
+		// No protocol version served by both.
 		return errServerVersionMismatch
 	}
 
@@ -98,6 +138,12 @@ func (args LoginRPCArgs) IsValid() error {
 // with subsequent requests.
 type LoginRPCReply struct {
 	AuthToken string
+
+	// Negotiated protocol version, the latest served by both the
+	// client and the server, and the capabilities of the server.
+	// Unset by servers of releases before protocol versioning.
+	ProtocolVersion int
+	Capabilities    []string
 }
 
 // LockArgs represents arguments for any authenticated lock RPC call.
@@ -19,8 +19,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
 	"net/url"
 	"path"
+	"sort"
 	"sync"
 )
 
@@ -151,6 +153,25 @@ func (s3p s3Peers) SendUpdate(peerIndex []int, args BucketUpdater) []error {
 	return errs
 }
 
+// GetListingOwner - returns the peer owning the tree walks listing
+// prefix of bucket with delimiter. All the peers pick the same owner,
+// so that the consecutive pages of a listing are served from the tree
+// walk cached by the owner whichever peer receives them.
+func (s3p s3Peers) GetListingOwner(bucket, prefix, delimiter string) s3Peer {
+	addrs := make([]string, len(s3p))
+	for i, p := range s3p {
+		addrs[i] = p.addr
+	}
+	sort.Strings(addrs)
+	owner := addrs[crc32.ChecksumIEEE([]byte(bucket+"/"+prefix+delimiter))%uint32(len(addrs))]
+	for _, p := range s3p {
+		if p.addr == owner {
+			return p
+		}
+	}
+	return s3p[0]
+}
+
 // S3PeersUpdateBucketNotification - Sends Update Bucket notification
 // request to all peers. Currently we log an error and continue.
 func S3PeersUpdateBucketNotification(bucket string, ncfg *notificationConfig) {
@@ -197,3 +218,87 @@ func S3PeersUpdateBucketPolicy(bucket string, pCh policyChange) {
 		)
 	}
 }
+
+// S3PeersUpdateBucketIntegrity - Sends update bucket integrity request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketIntegrity(bucket string, config *IntegrityConfiguration) {
+	setBIPArgs := &SetBucketIntegrityPeerArgs{Bucket: bucket, Config: config}
+	errs := globalS3Peers.SendUpdate(nil, setBIPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket integrity to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// S3PeersUpdateBucketBandwidth - Sends update bucket bandwidth request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketBandwidth(bucket string, config *BandwidthConfiguration) {
+	setBBPArgs := &SetBucketBandwidthPeerArgs{Bucket: bucket, Config: config}
+	errs := globalS3Peers.SendUpdate(nil, setBBPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket bandwidth to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// S3PeersUpdateBucketLogging - Sends update bucket logging request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketLogging(bucket string, config *BucketLoggingStatus) {
+	setBLPArgs := &SetBucketLoggingPeerArgs{Bucket: bucket, Config: config}
+	errs := globalS3Peers.SendUpdate(nil, setBLPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket logging to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// S3PeersUpdateBucketSecureDelete - Sends update bucket secure delete request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketSecureDelete(bucket string, config *SecureDeleteConfiguration) {
+	setBSDPArgs := &SetBucketSecureDeletePeerArgs{Bucket: bucket, Config: config}
+	errs := globalS3Peers.SendUpdate(nil, setBSDPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket secure delete to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// S3PeersUpdateBucketTransform - Sends update bucket transform request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketTransform(bucket string, config *TransformConfiguration) {
+	setBTPArgs := &SetBucketTransformPeerArgs{Bucket: bucket, Config: config}
+	errs := globalS3Peers.SendUpdate(nil, setBTPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket transform to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// S3PeersUpdateBucketShareLinks - Sends update bucket share links request to
+// all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketShareLinks(bucket string, links []shareLink) {
+	setBSLPArgs := &SetBucketShareLinksPeerArgs{Bucket: bucket, Links: links}
+	errs := globalS3Peers.SendUpdate(nil, setBSLPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket share links to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
@@ -61,3 +61,31 @@ func TestMakeS3Peers(t *testing.T) {
 		}
 	}
 }
+
+// Validates that all the peers pick the same owner for a listing.
+func TestS3PeersGetListingOwner(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer removeAll(root)
+
+	eps := []*url.URL{{Host: "m1:9000"}, {Host: "m2:9000"}, {Host: "m3:9000"}}
+	owners := make(map[string]bool)
+	for _, prefix := range []string{"", "photos/", "photos/2017/", "videos/", "logs/"} {
+		var owner string
+		for i, ep := range eps {
+			// Every peer lists itself first.
+			globalMinioAddr = ep.Host
+			peerOwner := makeS3Peers(eps).GetListingOwner("bucket", prefix, "/").addr
+			if i > 0 && peerOwner != owner {
+				t.Fatalf("Peer %s picked owner %s for prefix %q, expected %s", ep.Host, peerOwner, prefix, owner)
+			}
+			owner = peerOwner
+		}
+		owners[owner] = true
+	}
+	if len(owners) < 2 {
+		t.Errorf("Expected listings to be spread over peers, got owners %v", owners)
+	}
+}
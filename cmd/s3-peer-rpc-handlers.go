@@ -16,6 +16,8 @@
 
 package cmd
 
+import "context"
+
 // SetBucketNotificationPeerArgs - Arguments collection to SetBucketNotificationPeer RPC
 // call
 type SetBucketNotificationPeerArgs struct {
@@ -116,3 +118,195 @@ func (s3 *s3PeerAPIHandlers) SetBucketPolicyPeer(args *SetBucketPolicyPeerArgs,
 
 	return s3.bms.UpdateBucketPolicy(args)
 }
+
+// SetBucketIntegrityPeerArgs - Arguments collection for SetBucketIntegrityPeer RPC call
+type SetBucketIntegrityPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Integrity config, nil when removed.
+	Config *IntegrityConfiguration
+}
+
+// BucketUpdate - implements bucket integrity updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset integrity configs.
+func (s *SetBucketIntegrityPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketIntegrity(s)
+}
+
+// tell receiving server to update a bucket integrity config
+func (s3 *s3PeerAPIHandlers) SetBucketIntegrityPeer(args *SetBucketIntegrityPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketIntegrity(args)
+}
+
+// SetBucketBandwidthPeerArgs - Arguments collection for SetBucketBandwidthPeer RPC call
+type SetBucketBandwidthPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Bandwidth config, nil when removed.
+	Config *BandwidthConfiguration
+}
+
+// BucketUpdate - implements bucket bandwidth updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset bandwidth configs.
+func (s *SetBucketBandwidthPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketBandwidth(s)
+}
+
+// tell receiving server to update a bucket bandwidth config
+func (s3 *s3PeerAPIHandlers) SetBucketBandwidthPeer(args *SetBucketBandwidthPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketBandwidth(args)
+}
+
+// SetBucketLoggingPeerArgs - Arguments collection for SetBucketLoggingPeer RPC call
+type SetBucketLoggingPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Logging config, nil when removed.
+	Config *BucketLoggingStatus
+}
+
+// BucketUpdate - implements bucket logging updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset logging configs.
+func (s *SetBucketLoggingPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketLogging(s)
+}
+
+// tell receiving server to update a bucket logging config
+func (s3 *s3PeerAPIHandlers) SetBucketLoggingPeer(args *SetBucketLoggingPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketLogging(args)
+}
+
+// SetBucketSecureDeletePeerArgs - Arguments collection for SetBucketSecureDeletePeer RPC call
+type SetBucketSecureDeletePeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Secure delete config, nil when removed.
+	Config *SecureDeleteConfiguration
+}
+
+// BucketUpdate - implements bucket secure delete updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset secure delete configs.
+func (s *SetBucketSecureDeletePeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketSecureDelete(s)
+}
+
+// tell receiving server to update a bucket secure delete config
+func (s3 *s3PeerAPIHandlers) SetBucketSecureDeletePeer(args *SetBucketSecureDeletePeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketSecureDelete(args)
+}
+
+// SetBucketTransformPeerArgs - Arguments collection for SetBucketTransformPeer RPC call
+type SetBucketTransformPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Transform config, nil when removed.
+	Config *TransformConfiguration
+}
+
+// BucketUpdate - implements bucket transform updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset transform configs.
+func (s *SetBucketTransformPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketTransform(s)
+}
+
+// tell receiving server to update a bucket transform config
+func (s3 *s3PeerAPIHandlers) SetBucketTransformPeer(args *SetBucketTransformPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketTransform(args)
+}
+
+// SetBucketShareLinksPeerArgs - Arguments collection for SetBucketShareLinksPeer RPC call
+type SetBucketShareLinksPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Share links, nil when removed.
+	Links []shareLink
+}
+
+// BucketUpdate - implements bucket share links updates,
+// the underlying operation is a network call updates all
+// the peers participating for new set/unset share links.
+func (s *SetBucketShareLinksPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketShareLinks(s)
+}
+
+// tell receiving server to update the share links of a bucket
+func (s3 *s3PeerAPIHandlers) SetBucketShareLinksPeer(args *SetBucketShareLinksPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketShareLinks(args)
+}
+
+// ListObjectsPeerArgs - Arguments collection for ListObjectsPeer RPC call
+type ListObjectsPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket    string
+	Prefix    string
+	Marker    string
+	Delimiter string
+	MaxKeys   int
+}
+
+// ListObjectsPeerReply - reply of ListObjectsPeer RPC call
+type ListObjectsPeerReply struct {
+	AuthRPCReply
+
+	Info ListObjectsInfo
+}
+
+// list objects on the receiving server, which owns the tree walks of
+// the listing
+func (s3 *s3PeerAPIHandlers) ListObjectsPeer(args *ListObjectsPeerArgs, reply *ListObjectsPeerReply) (err error) {
+	if err = args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Info, err = s3.bms.ListObjects(context.Background(), args)
+	return err
+}
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"path"
 	"testing"
@@ -100,6 +102,31 @@ func (s *TestRPCS3PeerSuite) testS3PeerRPC(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Check list objects call is served by the receiving server.
+	if err = s.testServer.Obj.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"a/1", "a/2", "b"} {
+		if _, err = s.testServer.Obj.PutObject(context.Background(), "bucket", object, 0, bytes.NewReader(nil), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	LOPArgs := ListObjectsPeerArgs{Bucket: "bucket", Prefix: "a/", MaxKeys: 1}
+	LOPReply := ListObjectsPeerReply{}
+	if err = client.Call("S3.ListObjectsPeer", &LOPArgs, &LOPReply); err != nil {
+		t.Fatal(err)
+	}
+	if !LOPReply.Info.IsTruncated || len(LOPReply.Info.Objects) != 1 || LOPReply.Info.Objects[0].Name != "a/1" {
+		t.Fatalf("Unexpected list objects reply %#v", LOPReply.Info)
+	}
+	LOPArgs.Marker = "a/1"
+	if err = client.Call("S3.ListObjectsPeer", &LOPArgs, &LOPReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(LOPReply.Info.Objects) != 1 || LOPReply.Info.Objects[0].Name != "a/2" {
+		t.Fatalf("Unexpected list objects reply %#v", LOPReply.Info)
+	}
+
 	// Check event send event call works.
 	evArgs := EventArgs{Event: nil, Arn: "localhost:9000"}
 	err = client.Call("S3.Event", &evArgs, &AuthRPCReply{})
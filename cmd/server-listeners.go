@@ -0,0 +1,243 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Scheme used to denote a unix domain socket listener.
+const unixScheme = "unix"
+
+// Returned when a https listener is requested without certificates.
+var errListenerNoCerts = errors.New("Certificates not provided for https listener")
+
+// Valid names of the listeners, such as "accelerate".
+var validListenerName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// serverListener - describes an additional address the server accepts
+// connections on, independently of the main `--address` listener.
+//
+// Supported forms are
+//
+//	http://[host]:port                      - plain text only.
+//	https://[host]:port[?cert=..&key=..]    - TLS, with its own certificates optionally.
+//	unix:///path/to/minio.sock              - plain text unix domain socket.
+//
+// TCP listeners accept in addition a name, such as "accelerate", and
+// their own network tuning, so that WAN uploads can be routed to a
+// dedicated listener with larger socket buffers and another TCP
+// congestion control than the LAN traffic
+//
+//	https://:9443?name=accelerate&rcvbuf=4MiB&sndbuf=4MiB&congestion=bbr
+type serverListener struct {
+	scheme   string // One of httpScheme, httpsScheme or unixScheme.
+	addr     string // host:port for TCP, file path for unix sockets.
+	certFile string // Certificate used for https listeners.
+	keyFile  string // Private key used for https listeners.
+
+	name       string // Name of the listener printed at startup, optional.
+	rcvBuf     int    // Receive buffer of the connections, system default if 0.
+	sndBuf     int    // Send buffer of the connections, system default if 0.
+	congestion string // TCP congestion control of the connections, system default if empty.
+}
+
+// network - returns the network type as understood by net.Listen.
+func (l serverListener) network() string {
+	if l.scheme == unixScheme {
+		return "unix"
+	}
+	return "tcp"
+}
+
+// String - returns the listener as an URL like string, used in
+// startup messages.
+func (l serverListener) String() string {
+	return l.scheme + "://" + l.addr
+}
+
+// parseServerListener - parses a listener specification passed via
+// `--listen`.
+func parseServerListener(spec string) (l serverListener, err error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return l, err
+	}
+	switch u.Scheme {
+	case unixScheme:
+		if u.Host != "" || u.Path == "" {
+			return l, fmt.Errorf("Invalid unix socket listener %s, expected unix:///path/to/socket", spec)
+		}
+		if u.RawQuery != "" {
+			return l, fmt.Errorf("Invalid unix socket listener %s, only TCP listeners accept a name and tuning", spec)
+		}
+		return serverListener{scheme: unixScheme, addr: u.Path}, nil
+	case httpScheme, httpsScheme:
+		if _, _, err = getHostPort(u.Host); err != nil {
+			return l, fmt.Errorf("Invalid listener %s: %s", spec, err)
+		}
+		l = serverListener{scheme: u.Scheme, addr: u.Host}
+		if u.Scheme == httpsScheme {
+			l.certFile = u.Query().Get("cert")
+			l.keyFile = u.Query().Get("key")
+			if (l.certFile == "") != (l.keyFile == "") {
+				return l, fmt.Errorf("Invalid listener %s, both cert and key should be provided", spec)
+			}
+		}
+		if err = l.parseTuning(u.Query()); err != nil {
+			return l, fmt.Errorf("Invalid listener %s: %s", spec, err)
+		}
+		return l, nil
+	}
+	return l, fmt.Errorf("Invalid listener %s, supported schemes are http, https and unix", spec)
+}
+
+// parseTuning - parses the name and the network tuning of a TCP
+// listener from the query of its specification.
+func (l *serverListener) parseTuning(query url.Values) (err error) {
+	if l.name = query.Get("name"); l.name != "" && !validListenerName.MatchString(l.name) {
+		return fmt.Errorf("name ‘%s’ should be made of lowercase letters, digits and hyphens", l.name)
+	}
+	if l.rcvBuf, err = parseSocketBuffer(query, "rcvbuf"); err != nil {
+		return err
+	}
+	if l.sndBuf, err = parseSocketBuffer(query, "sndbuf"); err != nil {
+		return err
+	}
+	if l.congestion = query.Get("congestion"); l.congestion != "" {
+		if !tcpCongestionSupported {
+			return errors.New("congestion is not supported on this platform")
+		}
+		if strings.ContainsAny(l.congestion, " \x00") {
+			return fmt.Errorf("congestion ‘%s’ is not a valid TCP congestion control", l.congestion)
+		}
+	}
+	return nil
+}
+
+// parseSocketBuffer - parses the size of a socket buffer from the
+// query parameter key, such as "4MiB", 0 if unset.
+func parseSocketBuffer(query url.Values, key string) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(value)
+	if err != nil || size == 0 || size > 1<<30 {
+		return 0, fmt.Errorf("%s ‘%s’ should be a positive size up to 1GiB such as 4MiB", key, value)
+	}
+	return int(size), nil
+}
+
+// parseServerListeners - parses all the listener specifications,
+// fills in the default certificates for https listeners which do
+// not carry their own.
+func parseServerListeners(specs []string) ([]serverListener, error) {
+	var listeners []serverListener
+	for _, spec := range specs {
+		l, err := parseServerListener(spec)
+		if err != nil {
+			return nil, err
+		}
+		if l.scheme == httpsScheme && l.certFile == "" {
+			if !globalIsSSL {
+				return nil, errListenerNoCerts
+			}
+			l.certFile, l.keyFile = mustGetCertFile(), mustGetKeyFile()
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listen - opens the listener described by l, TLS connections are
+// detected automatically and served on https listeners.
+func (l serverListener) listen() (*ListenerMux, error) {
+	var tlsConfig *tls.Config
+	if l.scheme == httpsScheme {
+		cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{
+			NextProtos:   []string{"http/1.1", "h2"},
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+
+	listener, err := listen(l.network(), l.addr)
+	if err != nil {
+		return nil, err
+	}
+	if listener, err = l.tune(listener); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	lm := newListenerMux(listener, tlsConfig)
+	lm.key = listenerKey(l.network(), l.addr)
+	return lm, nil
+}
+
+// tune - applies the network tuning of l to listener. The congestion
+// control is set on the listening socket, inherited by the accepted
+// connections, so that an unavailable one fails at startup.
+func (l serverListener) tune(listener net.Listener) (net.Listener, error) {
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		return listener, nil
+	}
+	if l.congestion != "" {
+		if err := setTCPCongestion(tcpListener, l.congestion); err != nil {
+			return listener, fmt.Errorf("Unable to set TCP congestion control %s on %s: %s", l.congestion, l, err)
+		}
+	}
+	if l.rcvBuf == 0 && l.sndBuf == 0 {
+		return listener, nil
+	}
+	return &tunedListener{TCPListener: tcpListener, rcvBuf: l.rcvBuf, sndBuf: l.sndBuf}, nil
+}
+
+// tunedListener - sets the socket buffers of the accepted connections,
+// the embedded *net.TCPListener keeps it a listenerFiler.
+type tunedListener struct {
+	*net.TCPListener
+	rcvBuf, sndBuf int
+}
+
+// Accept - accepts a connection and sets its socket buffers, failing
+// to set them is not fatal to the connection.
+func (l *tunedListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	if l.rcvBuf > 0 {
+		errorIf(conn.SetReadBuffer(l.rcvBuf), "Unable to set the receive buffer of a connection on %s.", l.Addr())
+	}
+	if l.sndBuf > 0 {
+		errorIf(conn.SetWriteBuffer(l.sndBuf), "Unable to set the send buffer of a connection on %s.", l.Addr())
+	}
+	return conn, nil
+}
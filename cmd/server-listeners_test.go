@@ -0,0 +1,176 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Tests parsing of listener specifications.
+func TestParseServerListener(t *testing.T) {
+	port := getFreePort()
+	testCases := []struct {
+		spec       string
+		expected   serverListener
+		shouldPass bool
+	}{
+		// Test 1: plain text listener.
+		{"http://127.0.0.1:" + port, serverListener{scheme: httpScheme, addr: "127.0.0.1:" + port}, true},
+		// Test 2: TLS listener with its own certificates.
+		{"https://:" + port + "?cert=/tmp/public.crt&key=/tmp/private.key", serverListener{
+			scheme:   httpsScheme,
+			addr:     ":" + port,
+			certFile: "/tmp/public.crt",
+			keyFile:  "/tmp/private.key",
+		}, true},
+		// Test 3: unix domain socket.
+		{"unix:///var/run/minio.sock", serverListener{scheme: unixScheme, addr: "/var/run/minio.sock"}, true},
+		// Test 4: unix domain socket without path.
+		{"unix://", serverListener{}, false},
+		// Test 5: missing port.
+		{"http://127.0.0.1", serverListener{}, false},
+		// Test 6: unsupported scheme.
+		{"ftp://127.0.0.1:" + port, serverListener{}, false},
+		// Test 7: cert without key.
+		{"https://:" + port + "?cert=/tmp/public.crt", serverListener{}, false},
+		// Test 8: named listener with its own socket buffers.
+		{"http://:" + port + "?name=accelerate&rcvbuf=4MiB&sndbuf=1048576", serverListener{
+			scheme: httpScheme,
+			addr:   ":" + port,
+			name:   "accelerate",
+			rcvBuf: 4 << 20,
+			sndBuf: 1 << 20,
+		}, true},
+		// Test 9: congestion control, supported on linux only.
+		{"http://:" + port + "?congestion=bbr", serverListener{
+			scheme:     httpScheme,
+			addr:       ":" + port,
+			congestion: "bbr",
+		}, tcpCongestionSupported},
+		// Test 10: invalid name.
+		{"http://:" + port + "?name=Accelerate!", serverListener{}, false},
+		// Test 11: invalid socket buffer.
+		{"http://:" + port + "?rcvbuf=large", serverListener{}, false},
+		// Test 12: unix domain socket with tuning.
+		{"unix:///var/run/minio.sock?rcvbuf=4MiB", serverListener{}, false},
+	}
+	for i, testCase := range testCases {
+		l, err := parseServerListener(testCase.spec)
+		if testCase.shouldPass && err != nil {
+			t.Fatalf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Fatalf("Test %d: Should fail but is successful", i+1)
+		}
+		if testCase.shouldPass && l != testCase.expected {
+			t.Fatalf("Test %d: Expected %#v, got %#v", i+1, testCase.expected, l)
+		}
+	}
+}
+
+// Tests that https listeners without certificates are rejected
+// when the server is not configured with TLS.
+func TestParseServerListenersNoCerts(t *testing.T) {
+	isSSL := globalIsSSL
+	defer func() { globalIsSSL = isSSL }()
+	globalIsSSL = false
+
+	if _, err := parseServerListeners([]string{"https://:" + getFreePort()}); err != errListenerNoCerts {
+		t.Fatalf("Expected %s, got %s", errListenerNoCerts, err)
+	}
+}
+
+// Tests serving requests on additional plain text and unix domain
+// socket listeners.
+func TestListenAndServeExtraListeners(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip("unix domain sockets are not supported on windows")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "minio-listeners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	httpAddr := net.JoinHostPort("127.0.0.1", getFreePort())
+	sockPath := filepath.Join(tmpDir, "minio.sock")
+
+	m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	}))
+	// The plain text listener is tuned, as an accelerate listener.
+	m.AddListener(serverListener{scheme: httpScheme, addr: httpAddr, name: "accelerate", rcvBuf: 1 << 20, sndBuf: 1 << 20})
+	m.AddListener(serverListener{scheme: unixScheme, addr: sockPath})
+
+	errc := make(chan error, 1)
+	go func() { errc <- m.ListenAndServe("", "") }()
+	defer m.Close()
+
+	unixClient := http.Client{
+		Timeout: 100 * time.Millisecond,
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return net.Dial("unix", sockPath)
+			},
+		},
+	}
+	tcpClient := http.Client{Timeout: 100 * time.Millisecond}
+
+	for _, get := range []func() (*http.Response, error){
+		func() (*http.Response, error) { return tcpClient.Get("http://" + httpAddr) },
+		func() (*http.Response, error) { return unixClient.Get("http://minio/") },
+	} {
+		var res *http.Response
+		for i := 0; i < 100; i++ {
+			select {
+			case err = <-errc:
+				t.Fatalf("Unable to serve %s", err)
+			default:
+			}
+			if res, err = get(); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != "hello" {
+			t.Fatalf("Expected hello, got %s", string(body))
+		}
+	}
+}
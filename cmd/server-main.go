@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"runtime"
 
@@ -38,6 +39,20 @@ var serverFlags = []cli.Flag{
 		Value: ":9000",
 		Usage: `Bind to a specific IP:PORT. Defaults to ":9000".`,
 	},
+	cli.StringSliceFlag{
+		Name:  "listen",
+		Value: &cli.StringSlice{},
+		Usage: `Additional address to listen on, can be repeated. Accepts http://IP:PORT, https://IP:PORT and unix:///PATH. TCP listeners accept ?name=NAME&rcvbuf=SIZE&sndbuf=SIZE&congestion=ALGORITHM.`,
+	},
+	cli.DurationFlag{
+		Name:  "shutdown-timeout",
+		Value: 30 * time.Second,
+		Usage: `Time to wait for in-flight requests to finish upon stop or restart. Defaults to "30s".`,
+	},
+	cli.BoolFlag{
+		Name:  "check-config",
+		Usage: "Check the config and the connections to notification targets, then exit without starting the server.",
+	},
 }
 
 var serverCmd = cli.Command{
@@ -58,9 +73,68 @@ ENVIRONMENT VARIABLES:
   ACCESS:
      MINIO_ACCESS_KEY: Custom username or access key of 5 to 20 characters in length.
      MINIO_SECRET_KEY: Custom password or secret key of 8 to 40 characters in length.
+     MINIO_ANONYMOUS: To deny anonymous requests regardless of the bucket policies, set this value to "off". To log every anonymous request as a warning, set this value to "audit" or "off". Defaults to "on".
 
   BROWSER:
      MINIO_BROWSER: To disable web browser access, set this value to "off".
+     MINIO_BROWSER_SHARE_MAX_EXPIRY: Longest expiry of the links shared from the web browser, up to "168h". Defaults to "168h".
+
+  PRESIGNED URLS:
+     MINIO_PRESIGN_MAX_EXPIRY: Longest expiry of presigned URLs, up to "168h", longer ones being rejected. Also caps MINIO_BROWSER_SHARE_MAX_EXPIRY. Defaults to "168h".
+
+  MULTIPART:
+     MINIO_MULTIPART_EXPIRY: Age after which incomplete multipart uploads are aborted, "off" to keep them. Defaults to "168h".
+
+  LISTING:
+     MINIO_LIST_CONCURRENCY: Maximum number of directory listings run at a time by object listings, a bucket getting at most half of them, "off" for unlimited. Defaults to "32".
+
+  ERASURE:
+     MINIO_ERASURE_CONCURRENCY: Maximum number of blocks erasure coded or decoded at a time, each of them spread over all the CPUs, "off" for unlimited. Defaults to the number of CPUs.
+     MINIO_DISK_PROBE_INTERVAL: Interval between two write probes of all the disks, disks failing writes being marked offline, "off" to probe only at startup. Defaults to "1m".
+
+  BACKGROUND:
+     MINIO_BACKGROUND_IO_SHARE: Percentage of the time background operations, such as healing and multipart cleanup, may keep the disks busy. Defaults to "25".
+     MINIO_BACKGROUND_LATENCY_LIMIT: Latency of foreground requests pausing background operations, "off" to never pause them. Defaults to "500ms".
+     MINIO_DATA_USAGE_INTERVAL: Interval between two crawls of all the objects for their data usage, a crawl by any server counting for all of them, "off" to never crawl. Defaults to "1h".
+
+  COPY:
+     MINIO_COPY_BANDWIDTH: Bandwidth shared by all the server side copies of this server, such as CopyObject and compose, in bytes per second such as "50MiB", "off" for unlimited. Unlimited by default.
+
+  LOCKING:
+     MINIO_LOCK_ACQUIRE_TIMEOUT: Time to wait for the lock responses of the servers on each attempt to acquire a lock, in distributed mode. Defaults to "25ms".
+     MINIO_LOCK_RETRY_INTERVAL: Initial back-off between attempts to acquire a lock, doubled after every failed attempt. Defaults to "1ms".
+     MINIO_LOCK_MAX_RETRY_INTERVAL: Longest back-off between attempts to acquire a lock. Defaults to "1s".
+     MINIO_LOCK_WRITE_QUORUM: Number of servers granting a write lock, more than half of the servers. Defaults to half of the servers plus one.
+     MINIO_LOCK_READ_QUORUM: Number of servers granting a read lock, overlapping with the write quorum. Defaults to half of the servers.
+     MINIO_CLOCK_SKEW_TOLERANCE: Largest difference between the clocks of the servers tolerated on internode RPCs, at most "15m". Defaults to "3s".
+
+  METRICS:
+     MINIO_STATSD_ENDPOINT: StatsD server or Datadog agent the metrics of the requests are sent to, such as "localhost:8125". Disabled by default.
+     MINIO_STATSD_PREFIX: Prefix of the metric names. Defaults to "minio".
+     MINIO_STATSD_FORMAT: To tag the metrics with the API name instead of naming a metric per API, set this value to "datadog". Defaults to "statsd".
+
+  TRACING:
+     MINIO_TRACING_ENDPOINT: Zipkin compatible collector, such as Jaeger, the spans of the requests are reported to, such as "http://jaeger:9411/api/v2/spans". Tracing is disabled by default.
+
+  INDEX:
+     MINIO_METADATA_INDEX: To index the metadata of all the objects and query it with the "metadata-query" API, set this value to "on". The index is journaled in the config directory. Not supported in distributed mode.
+
+  NAMES:
+     MINIO_STRICT_NAMES: To reject object names unsupported by Windows and NAS filesystems, set this value to "on".
+     MINIO_FS_NORMALIZE_NAMES: To store and look up object names in Unicode NFC on FS backend, set this value to "on".
+
+  BUCKETS:
+     MINIO_MAX_BUCKETS: Maximum number of buckets of the deployment. Unlimited by default.
+     MINIO_MAX_BUCKETS_PER_USER: Maximum number of buckets created by each IAM user. Unlimited by default.
+     MINIO_STRICT_BUCKET_NAMES: To reject bucket names which are not DNS compatible, such as names with periods, set this value to "on".
+
+  STANDBY:
+     MINIO_STANDBY: To designate this server as a standby of a distributed setup, only serving the requests not modifying data until promoted through the admin API, even while the other servers are in maintenance mode, set this value to "on".
+
+  DNS:
+     MINIO_DOMAIN: To publish "bucket.domain" DNS records of the buckets, set this value to the domain.
+     MINIO_DNS_ETCD_ENDPOINTS: Comma separated list of the etcd endpoints of CoreDNS.
+     MINIO_PUBLIC_IPS: Comma separated list of the IPs published in the bucket records, defaults to the IPs of this server.
 
 EXAMPLES:
   1. Start minio server on "/home/shared" directory.
@@ -69,17 +143,34 @@ EXAMPLES:
   2. Start minio server bound to a specific IP:PORT.
       $ minio {{.Name}} --address 192.168.1.101:9000 /home/shared
 
-  3. Start erasure coded minio server on a 12 disks server.
+  3. Start minio server on "/home/shared" directory, additionally listening on a plain text
+     internal port and a unix domain socket.
+      $ minio {{.Name}} --listen http://127.0.0.1:9001 --listen unix:///var/run/minio.sock /home/shared
+
+     Additionally listening on an "accelerate" port for the WAN uploads, with larger socket
+     buffers and the bbr TCP congestion control.
+      $ minio {{.Name}} --listen "https://:9443?name=accelerate&rcvbuf=4MiB&sndbuf=4MiB&congestion=bbr" /home/shared
+
+  4. Start erasure coded minio server on a 12 disks server.
       $ minio {{.Name}} /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/ \
           /mnt/export5/ /mnt/export6/ /mnt/export7/ /mnt/export8/ /mnt/export9/ \
           /mnt/export10/ /mnt/export11/ /mnt/export12/
 
-  4. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
+  5. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
       $ export MINIO_ACCESS_KEY=minio
       $ export MINIO_SECRET_KEY=miniostorage
       $ minio {{.Name}} http://192.168.1.11/mnt/export/ http://192.168.1.12/mnt/export/ \
           http://192.168.1.13/mnt/export/ http://192.168.1.14/mnt/export/
 
+  6. Start erasure coded distributed minio server on the 4 nodes listed by the DNS SRV records
+     of _minio._tcp.minio.example.com, with 1 drive each. Run following commands on all the 4 nodes.
+      $ export MINIO_ACCESS_KEY=minio
+      $ export MINIO_SECRET_KEY=miniostorage
+      $ minio {{.Name}} "dns+srv://_minio._tcp.minio.example.com/mnt/export/?servers=4"
+
+  7. Check the configuration of minio server, including the environment, and exit.
+      $ minio {{.Name}} --check-config
+
 `,
 }
 
@@ -100,11 +191,14 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 			return nil, err
 		}
 		if u.Host != "" {
-			_, port, err := net.SplitHostPort(u.Host)
-			// Ignore the missing port error as the default port can be globalMinioPort.
-			if err != nil && !strings.Contains(err.Error(), "missing port in address") {
+			// The port may be missing, as the default port can be globalMinioPort.
+			host, port, err := splitHostPort(u.Host)
+			if err != nil {
 				return nil, err
 			}
+			// IP literals are compared with the server address and
+			// the other endpoints in their canonical form.
+			host = canonicalHost(host)
 
 			if globalMinioHost == "" {
 				// For ex.: minio server host1:port1 host2:port2...
@@ -113,7 +207,7 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 				if port != "" {
 					return nil, fmt.Errorf("Invalid Argument %s, port configurable using --address :<port>", u.Host)
 				}
-				u.Host = net.JoinHostPort(u.Host, globalMinioPort)
+				u.Host = net.JoinHostPort(host, globalMinioPort)
 			} else {
 				// For ex.: minio server --address host:port host1:port1 host2:port2...
 				// i.e if "--address host:port" is specified
@@ -121,6 +215,7 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 				if port == "" {
 					return nil, fmt.Errorf("Invalid Argument %s, port mandatory when --address <host>:<port> is used", u.Host)
 				}
+				u.Host = net.JoinHostPort(host, port)
 			}
 		}
 		endpoints = append(endpoints, u)
@@ -248,15 +343,12 @@ func checkEndpointsSyntax(eps []*url.URL, disks []string) error {
 }
 
 // Make sure all the command line parameters are OK and exit in case of invalid parameters.
-func checkServerSyntax(c *cli.Context) {
+func checkServerSyntax(c *cli.Context, disks []string) {
 	serverAddr := c.String("address")
 
 	host, portStr, err := net.SplitHostPort(serverAddr)
 	fatalIf(err, "Unable to parse %s.", serverAddr)
 
-	// Verify syntax for all the XL disks.
-	disks := c.Args()
-
 	// Parse disks check if they comply with expected URI style.
 	endpoints, err := parseStorageEndpoints(disks)
 	fatalIf(err, "Unable to parse storage endpoints %s", strings.Join(disks, " "))
@@ -293,8 +385,9 @@ func checkServerSyntax(c *cli.Context) {
 			fatalIf(errInvalidArgument, "Port missing, Host:Port should be specified for --address")
 		}
 		foundCnt := 0
+		serverHostPort := net.JoinHostPort(canonicalHost(host), portStr)
 		for _, ep := range endpoints {
-			if ep.Host == serverAddr {
+			if ep.Host == serverHostPort {
 				foundCnt++
 			}
 		}
@@ -358,11 +451,16 @@ func getHostPort(address string) (host, port string, err error) {
 	}
 
 	// Success.
-	return host, port, nil
+	return canonicalHost(host), port, nil
 }
 
 // serverMain handler called for 'minio server' command.
 func serverMain(c *cli.Context) {
+	// Check the config and exit, no storage is needed.
+	if c.Bool("check-config") {
+		checkConfigMain(c)
+	}
+
 	if !c.Args().Present() || c.Args().First() == "help" {
 		cli.ShowCommandHelpAndExit(c, "server", 1)
 	}
@@ -370,6 +468,12 @@ func serverMain(c *cli.Context) {
 	// Initialization routine, such as config loading, enable logging, ..
 	minioInit(c)
 
+	// Keep systemd watchdog happy, if enabled, for the lifetime of the process.
+	startSdWatchdog(nil)
+
+	// Handle service control requests when running as a Windows service.
+	startServiceControlHandler()
+
 	// Check for new updates from dl.minio.io.
 	checkUpdate()
 
@@ -380,17 +484,97 @@ func serverMain(c *cli.Context) {
 	globalMinioHost, globalMinioPort, err = getHostPort(serverAddr)
 	fatalIf(err, "Unable to extract host and port %s", serverAddr)
 
+	// Disks of the command line, with the disks of the servers
+	// listed by DNS SRV records in place of the dns+srv endpoints.
+	disks, err := discoverStorageEndpoints(c.Args())
+	fatalIf(err, "Unable to discover storage endpoints %s", strings.Join(c.Args(), " "))
+
 	// Check server syntax and exit in case of errors.
 	// Done after globalMinioHost and globalMinioPort is set
 	// as parseStorageEndpoints() depends on it.
-	checkServerSyntax(c)
+	checkServerSyntax(c, disks)
 
 	// Initialize server config.
 	initServerConfig(c)
 
+	// Age after which multipart uploads are aborted.
+	multipartExpiry, err := getMultipartExpiry()
+	fatalIf(err, "Unable to parse %s", multipartExpiryEnv)
+
+	dataUsageInterval, err := getDataUsageInterval()
+	fatalIf(err, "Unable to parse %s", dataUsageIntervalEnv)
+
+	diskProbeInterval, err := getDiskProbeInterval()
+	fatalIf(err, "Unable to parse %s", diskProbeIntervalEnv)
+
+	globalClockSkewTolerance, err = getClockSkewTolerance()
+	fatalIf(err, "Unable to parse %s", clockSkewToleranceEnv)
+
+	globalBucketLimits, err = getBucketLimits()
+	fatalIf(err, "Unable to parse the bucket limits")
+
+	copyBandwidth, err := getCopyBandwidth()
+	fatalIf(err, "Unable to parse %s", copyBandwidthEnv)
+	setCopyBandwidth(copyBandwidth)
+
+	backgroundIOShare, err := getBackgroundIOShare()
+	fatalIf(err, "Unable to parse %s", backgroundIOShareEnv)
+	backgroundLatencyLimit, err := getBackgroundLatencyLimit()
+	fatalIf(err, "Unable to parse %s", backgroundLatencyLimitEnv)
+	globalBackgroundOps.SetConfig(backgroundIOShare, backgroundLatencyLimit)
+
+	tracingEndpoint, err := getTracingEndpoint()
+	fatalIf(err, "Unable to parse %s", tracingEndpointEnv)
+	if tracingEndpoint != "" {
+		globalTracer = newTracer(tracingEndpoint)
+	}
+
+	statsdConfig, err := getStatsdConfig()
+	fatalIf(err, "Unable to parse the StatsD configuration")
+	if statsdConfig.endpoint != "" {
+		globalStatsd, err = newStatsdSink(statsdConfig)
+		fatalIf(err, "Unable to initialize the StatsD sink to %s", statsdConfig.endpoint)
+	}
+
+	// Access granted to anonymous requests by the bucket policies.
+	globalAnonymousAccess, err = getAnonymousAccess()
+	fatalIf(err, "Unable to parse %s", anonymousAccessEnv)
+
+	// Longest expiry of presigned URLs.
+	globalPresignMaxExpiry, err = getPresignMaxExpiry()
+	fatalIf(err, "Unable to parse %s", presignMaxExpiryEnv)
+
+	// Longest expiry of the links shared from the browser, which are
+	// presigned URLs too.
+	globalShareMaxExpiry, err = getShareMaxExpiry()
+	fatalIf(err, "Unable to parse %s", shareMaxExpiryEnv)
+	if globalShareMaxExpiry > globalPresignMaxExpiry {
+		globalShareMaxExpiry = globalPresignMaxExpiry
+	}
+
+	// Maximum number of concurrent directory listings.
+	maxListings, err := getListConcurrency()
+	fatalIf(err, "Unable to parse %s", listConcurrencyEnv)
+	globalListingLimiter = newListingLimiter(maxListings)
+
+	// Maximum number of blocks erasure coded at a time.
+	erasureConcurrency, err := getErasureConcurrency()
+	fatalIf(err, "Unable to parse %s", erasureConcurrencyEnv)
+	globalErasureCoders = newErasureCoders(erasureConcurrency)
+
+	// Index of the object metadata queried without listing the buckets.
+	if strings.EqualFold(os.Getenv(metadataIndexEnv), "on") {
+		globalMetadataIndex, err = openMetadataIndex(mustGetConfigPath())
+		fatalIf(err, "Unable to open the metadata index.")
+	}
+
+	// Publisher of the bucket DNS records of federated deployments.
+	globalBucketDNS, err = getBucketDNS()
+	fatalIf(err, "Unable to initialize bucket DNS")
+
 	// Disks to be used in server init.
-	endpoints, err := parseStorageEndpoints(c.Args())
-	fatalIf(err, "Unable to parse storage endpoints %s", c.Args())
+	endpoints, err := parseStorageEndpoints(disks)
+	fatalIf(err, "Unable to parse storage endpoints %s", disks)
 
 	// Should exit gracefully if none of the endpoints passed
 	// as command line args are local to this server.
@@ -415,7 +599,23 @@ func serverMain(c *cli.Context) {
 
 	// Set nodes for dsync for distributed setup.
 	if globalIsDistXL {
-		fatalIf(initDsyncNodes(endpoints), "Unable to initialize distributed locking clients")
+		dsyncConfig, err := getDsyncConfig()
+		fatalIf(err, "Unable to parse distributed locking configuration")
+		fatalIf(initDsyncNodes(endpoints, dsyncConfig), "Unable to initialize distributed locking clients")
+
+		// Writes served by the other servers would be missing in the index.
+		if globalMetadataIndex != nil {
+			fatalIf(errInvalidArgument, "%s is not supported in distributed mode", metadataIndexEnv)
+		}
+	}
+
+	// Standby servers only serve reads until promoted, set before
+	// serving any request.
+	if strings.EqualFold(os.Getenv(standbyEnv), "on") {
+		if !globalIsDistXL {
+			fatalIf(errInvalidArgument, "%s is only supported in distributed mode", standbyEnv)
+		}
+		globalServerModes.SetStandby(true)
 	}
 
 	// Initialize name space lock.
@@ -431,8 +631,21 @@ func serverMain(c *cli.Context) {
 	// Initialize Admin Peers inter-node communication only in distributed setup.
 	initGlobalAdminPeers(endpoints)
 
+	// Warn of the servers with clocks too far apart.
+	if globalIsDistXL {
+		startClockSkewMonitor(globalAdminPeers, clockSkewCheckInterval)
+	}
+
 	// Initialize a new HTTP server.
 	apiServer := NewServerMux(serverAddr, handler)
+	apiServer.GracefulTimeout = c.Duration("shutdown-timeout")
+
+	// Additional listeners, each with its own TLS settings.
+	listeners, err := parseServerListeners(c.StringSlice("listen"))
+	fatalIf(err, "Unable to parse listen addresses %s", strings.Join(c.StringSlice("listen"), " "))
+	for _, listener := range listeners {
+		apiServer.AddListener(listener)
+	}
 
 	// Set the global minio addr for this server.
 	globalMinioAddr = getLocalAddress(srvConfig)
@@ -440,6 +653,9 @@ func serverMain(c *cli.Context) {
 	// Determine API endpoints where we are going to serve the S3 API from.
 	apiEndPoints, err := finalizeAPIEndpoints(apiServer.Server)
 	fatalIf(err, "Unable to finalize API endpoints for %s", apiServer.Server.Addr)
+	for _, listener := range listeners {
+		apiEndPoints = append(apiEndPoints, listener.String())
+	}
 
 	// Set the global API endpoints value.
 	globalAPIEndpoints = apiEndPoints
@@ -453,15 +669,59 @@ func serverMain(c *cli.Context) {
 		fatalIf(apiServer.ListenAndServe(cert, key), "Failed to start minio server.")
 	}()
 
+	sdNotifyStatus("Initializing object layer.")
 	newObject, err := newObjectLayer(srvConfig)
 	fatalIf(err, "Initializing object layer failed")
 
+	// Writes of all the services update the metadata index.
+	objAPI := indexObjectLayer(newObject)
+
 	globalObjLayerMutex.Lock()
-	globalObjectAPI = newObject
+	globalObjectAPI = traceObjectLayer(objAPI)
 	globalObjLayerMutex.Unlock()
 
+	// Publish the records of the buckets created before bucket
+	// DNS records were enabled.
+	if globalBucketDNS != nil {
+		errorIf(publishBucketsDNS(newObject), "Unable to publish bucket DNS records.")
+	}
+
+	// Abort multipart uploads never completed nor aborted by clients.
+	if multipartExpiry > 0 {
+		newMultipartJanitor(objAPI, multipartExpiry).Start()
+	}
+
+	// Account the data usage of the buckets and their prefixes.
+	if dataUsageInterval > 0 {
+		newDataUsageCrawler(newObject, dataUsageInterval).Start()
+	}
+
+	// Remove the objects older than the expiry of their bucket.
+	newBucketExpirySweeper(objAPI, bucketExpirySweepInterval).Start()
+
+	// Index the objects written before the index was enabled.
+	if globalMetadataIndex != nil && !globalMetadataIndex.Ready() {
+		go func() {
+			errorIf(globalMetadataIndex.build(newObject), "Unable to build the metadata index.")
+		}()
+	}
+
+	// Keep the bucket metadata of standby servers up to date.
+	if globalServerModes.IsStandby() {
+		startStandbySync(newObject, standbySyncInterval)
+	}
+
+	// Mark disks failing writes offline until writable again.
+	if diskProbeInterval > 0 {
+		startDiskProbe(newObject, diskProbeInterval)
+	}
+
+	// Object layer is initialized, let systemd route traffic to this server.
+	errorIf(sdNotify(sdNotifyReady+"\nSTATUS=Serving requests on "+strings.Join(apiEndPoints, " ")), "Unable to notify systemd.")
+
 	// Prints the formatted startup message once object layer is initialized.
 	printStartupMessage(apiEndPoints)
+	printListenerNamesMsg(listeners)
 
 	// Waits on the server.
 	<-globalServiceDoneCh
@@ -507,6 +767,7 @@ func newObjectLayer(srvCmdCfg serverCmdConfig) (newObject ObjectLayer, err error
 	}
 
 	// Cleanup objects that weren't successfully written into the namespace.
+	sdNotifyStatus("Cleaning up temporary objects.")
 	if err = houseKeeping(storageDisks); err != nil {
 		return nil, err
 	}
@@ -128,6 +128,15 @@ func TestFinalizeAPIEndpoints(t *testing.T) {
 				i+1, test.addr)
 		}
 	}
+
+	// IPv6 hosts are bracketed.
+	endPoints, err := finalizeAPIEndpoints(&http.Server{Addr: "[::1]:80"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endPoints) != 1 || endPoints[0] != httpScheme+"://[::1]:80" {
+		t.Errorf("Unexpected API end points %v", endPoints)
+	}
 }
 
 // Tests all the expected input disks for function checkSufficientDisks.
@@ -298,12 +307,19 @@ func TestParseStorageEndpoints(t *testing.T) {
 			errors.New("Invalid Argument localhost:9000, port configurable using --address :<port>"),
 		},
 		{"testhost", "http://localhost:9000/export", nil},
+		{"", "http://[::1]/export", nil},
+		{
+			"",
+			"http://[::1]:9000/export",
+			errors.New("Invalid Argument [::1]:9000, port configurable using --address :<port>"),
+		},
+		{"::1", "http://[::1]:9000/export", nil},
 	}
 	for i, test := range testCases {
 		globalMinioHost = test.globalMinioHost
 		_, err := parseStorageEndpoints([]string{test.host})
 		if err != nil {
-			if err.Error() != test.expectedErr.Error() {
+			if test.expectedErr == nil || err.Error() != test.expectedErr.Error() {
 				t.Errorf("Test %d : got %v, expected %v", i+1, err, test.expectedErr)
 			}
 		}
@@ -312,6 +328,37 @@ func TestParseStorageEndpoints(t *testing.T) {
 	globalMinioHost = ""
 }
 
+// Tests that IPv6 endpoints are parsed into their canonical form.
+func TestParseStorageEndpointsIPv6(t *testing.T) {
+	defer func() {
+		globalMinioHost = ""
+	}()
+
+	testCases := []struct {
+		globalMinioHost string
+		endpoint        string
+		host            string
+	}{
+		{"", "http://[::1]/export", "[::1]:" + globalMinioPort},
+		{"", "http://[0:0::1]/export", "[::1]:" + globalMinioPort},
+		{"", "https://[fe80::1%25eth0]/export", "[fe80::1%eth0]:" + globalMinioPort},
+		{"::1", "http://[0:0::1]:9001/export", "[::1]:9001"},
+	}
+	for i, testCase := range testCases {
+		globalMinioHost = testCase.globalMinioHost
+		endpoints, err := parseStorageEndpoints([]string{testCase.endpoint})
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if endpoints[0].Host != testCase.host {
+			t.Errorf("Test %d: Expected host %s, got %s", i+1, testCase.host, endpoints[0].Host)
+		}
+		if endpoints[0].Path != "/export" {
+			t.Errorf("Test %d: Expected path /export, got %s", i+1, endpoints[0].Path)
+		}
+	}
+}
+
 // Test check endpoints syntax function for syntax verification
 // across various scenarios of inputs.
 func TestCheckEndpointsSyntax(t *testing.T) {
@@ -392,7 +439,7 @@ func TestCheckServerSyntax(t *testing.T) {
 			t.Errorf("Test %d failed to parse arguments %s", i+1, disks)
 		}
 		defer removeRoots(disks)
-		checkServerSyntax(ctx)
+		checkServerSyntax(ctx, ctx.Args())
 	}
 }
 
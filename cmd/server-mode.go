@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"sync"
+)
+
+// serverMode - operating mode of the whole server or of a single bucket.
+type serverMode string
+
+// List of supported operating modes.
+const (
+	// All requests are served.
+	serverModeNormal serverMode = "normal"
+	// Only requests which do not modify any data are served.
+	serverModeReadOnly serverMode = "read-only"
+	// No object storage requests are served at all.
+	serverModeMaintenance serverMode = "maintenance"
+)
+
+// isValid - returns true if mode is one of the supported modes.
+func (mode serverMode) isValid() bool {
+	switch mode {
+	case serverModeNormal, serverModeReadOnly, serverModeMaintenance:
+		return true
+	}
+	return false
+}
+
+// ServerModeInfo - operating modes of the server and of the buckets
+// having a mode other than normal, returned by the admin API.
+type ServerModeInfo struct {
+	Server  serverMode            `json:"server"`
+	Buckets map[string]serverMode `json:"buckets,omitempty"`
+	// Set on standby servers, see standby.go.
+	Standby bool `json:"standby,omitempty"`
+}
+
+// serverModes - operating modes currently in effect, the modes are
+// kept in memory only and reset to normal when the server restarts.
+type serverModes struct {
+	mu      sync.RWMutex
+	server  serverMode
+	buckets map[string]serverMode
+	standby bool
+}
+
+// newServerModes - initializes all modes to normal.
+func newServerModes() *serverModes {
+	return &serverModes{
+		server:  serverModeNormal,
+		buckets: make(map[string]serverMode),
+	}
+}
+
+// Set - sets mode for bucket, or for the whole server if bucket is empty.
+func (s *serverModes) Set(bucket string, mode serverMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case bucket == "":
+		s.server = mode
+	case mode == serverModeNormal:
+		delete(s.buckets, bucket)
+	default:
+		s.buckets[bucket] = mode
+	}
+}
+
+// SetStandby - designates this server as a standby, or promotes it
+// when standby is false.
+func (s *serverModes) SetStandby(standby bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.standby = standby
+}
+
+// IsStandby - returns true if this server is a standby.
+func (s *serverModes) IsStandby() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.standby
+}
+
+// Info - returns a copy of the modes currently in effect.
+func (s *serverModes) Info() ServerModeInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := ServerModeInfo{Server: s.server, Standby: s.standby}
+	if len(s.buckets) > 0 {
+		info.Buckets = make(map[string]serverMode, len(s.buckets))
+		for bucket, mode := range s.buckets {
+			info.Buckets[bucket] = mode
+		}
+	}
+	return info
+}
+
+// checkRequest - returns ErrNone if a request on bucket is allowed
+// in the current modes, isWrite is true for requests modifying data.
+// Server wide modes take precedence over the bucket modes, standby
+// servers only serve reads, even when under maintenance.
+func (s *serverModes) checkRequest(bucket string, isWrite bool) APIErrorCode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bucketMode := s.buckets[bucket]
+	switch {
+	case s.standby && isWrite:
+		return ErrStandbyReadOnly
+	case s.server == serverModeMaintenance && !s.standby:
+		return ErrServerMaintenanceMode
+	case bucketMode == serverModeMaintenance:
+		return ErrBucketMaintenanceMode
+	case !isWrite:
+		return ErrNone
+	case s.server == serverModeReadOnly:
+		return ErrServerReadOnlyMode
+	case bucketMode == serverModeReadOnly:
+		return ErrBucketReadOnlyMode
+	}
+	return ErrNone
+}
+
+// Operating modes in effect for this server.
+var globalServerModes = newServerModes()
+
+// serverModeErr - returned by the browser handlers for requests which
+// are refused in the current operating mode.
+type serverModeErr APIErrorCode
+
+func (e serverModeErr) Error() string {
+	return getAPIError(APIErrorCode(e)).Description
+}
+
+// checkServerMode - same as checkRequest on the global modes, used by
+// browser handlers, returns nil if the request is allowed.
+func checkServerMode(bucket string, isWrite bool) error {
+	if apiErr := globalServerModes.checkRequest(bucket, isWrite); apiErr != ErrNone {
+		return serverModeErr(apiErr)
+	}
+	return nil
+}
+
+// isWriteRequest - returns true for all methods which may modify data.
+func isWriteRequest(r *http.Request) bool {
+	switch r.Method {
+	case httpGET, httpHEAD, httpOPTIONS:
+		return false
+	}
+	return true
+}
+
+// Rejects object storage requests not allowed in the current
+// operating mode of the server or of the requested bucket.
+type serverModeHandler struct {
+	handler http.Handler
+}
+
+func setServerModeHandler(h http.Handler) http.Handler {
+	return serverModeHandler{handler: h}
+}
+
+// isAdminRequest - returns true if r is an admin API request signed
+// by the server credential.
+func isAdminRequest(r *http.Request) bool {
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	if bucket != "" || r.Header.Get(minioAdminOpHeader) == "" {
+		return false
+	}
+	return checkRequestAuthType(r, "", "", "") == ErrNone
+}
+
+func (h serverModeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	// Internode RPC is always available, browser handlers check
+	// the modes on their own.
+	if "/"+bucket != reservedBucket {
+		// Admin API is always available, it is needed to switch
+		// the modes back. Admin handlers modifying buckets check
+		// the modes on their own.
+		apiErr := globalServerModes.checkRequest(bucket, isWriteRequest(r))
+		if apiErr != ErrNone && !isAdminRequest(r) {
+			writeErrorResponse(w, apiErr, r.URL)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,256 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// Tests which requests are allowed in the various modes.
+func TestServerModesCheckRequest(t *testing.T) {
+	testCases := []struct {
+		serverMode serverMode
+		bucketMode serverMode
+		bucket     string
+		isWrite    bool
+		expected   APIErrorCode
+	}{
+		// Test 1 - 3: normal mode.
+		{serverModeNormal, serverModeNormal, "mybucket", false, ErrNone},
+		{serverModeNormal, serverModeNormal, "mybucket", true, ErrNone},
+		{serverModeNormal, serverModeReadOnly, "otherbucket", true, ErrNone},
+		// Test 4 - 5: read-only server.
+		{serverModeReadOnly, serverModeNormal, "mybucket", false, ErrNone},
+		{serverModeReadOnly, serverModeNormal, "mybucket", true, ErrServerReadOnlyMode},
+		// Test 6 - 7: read-only bucket.
+		{serverModeNormal, serverModeReadOnly, "mybucket", false, ErrNone},
+		{serverModeNormal, serverModeReadOnly, "mybucket", true, ErrBucketReadOnlyMode},
+		// Test 8 - 9: server under maintenance.
+		{serverModeMaintenance, serverModeNormal, "mybucket", false, ErrServerMaintenanceMode},
+		{serverModeMaintenance, serverModeReadOnly, "mybucket", true, ErrServerMaintenanceMode},
+		// Test 10 - 11: bucket under maintenance.
+		{serverModeNormal, serverModeMaintenance, "mybucket", false, ErrBucketMaintenanceMode},
+		{serverModeReadOnly, serverModeMaintenance, "mybucket", true, ErrBucketMaintenanceMode},
+	}
+	for i, testCase := range testCases {
+		modes := newServerModes()
+		modes.Set("", testCase.serverMode)
+		modes.Set("mybucket", testCase.bucketMode)
+		if apiErr := modes.checkRequest(testCase.bucket, testCase.isWrite); apiErr != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, apiErr)
+		}
+	}
+}
+
+// Tests standby servers only serving reads, even under maintenance.
+func TestServerModesStandby(t *testing.T) {
+	modes := newServerModes()
+	modes.SetStandby(true)
+	modes.Set("", serverModeMaintenance)
+	if apiErr := modes.checkRequest("mybucket", false); apiErr != ErrNone {
+		t.Errorf("Expected reads to be served, got %v", apiErr)
+	}
+	if apiErr := modes.checkRequest("mybucket", true); apiErr != ErrStandbyReadOnly {
+		t.Errorf("Expected %v, got %v", ErrStandbyReadOnly, apiErr)
+	}
+
+	// Buckets under maintenance are not served.
+	modes.Set("mybucket", serverModeMaintenance)
+	if apiErr := modes.checkRequest("mybucket", false); apiErr != ErrBucketMaintenanceMode {
+		t.Errorf("Expected %v, got %v", ErrBucketMaintenanceMode, apiErr)
+	}
+	if !modes.Info().Standby {
+		t.Error("Expected the standby to be reported")
+	}
+}
+
+// Tests that modes are reported back and normal buckets dropped.
+func TestServerModesInfo(t *testing.T) {
+	modes := newServerModes()
+	modes.Set("", serverModeReadOnly)
+	modes.Set("mybucket", serverModeMaintenance)
+	modes.Set("otherbucket", serverModeReadOnly)
+	modes.Set("otherbucket", serverModeNormal)
+
+	expected := ServerModeInfo{
+		Server:  serverModeReadOnly,
+		Buckets: map[string]serverMode{"mybucket": serverModeMaintenance},
+	}
+	if info := modes.Info(); !reflect.DeepEqual(info, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, info)
+	}
+}
+
+// Tests the generic handler rejecting requests in read-only mode.
+func TestServerModeHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+	credentials := serverConfig.GetCredential()
+
+	defer resetGlobalServerModes()
+	globalServerModes = newServerModes()
+	globalServerModes.Set("", serverModeReadOnly)
+
+	handler := setServerModeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		method         string
+		path           string
+		adminOp        string
+		signed         bool
+		expectedStatus int
+	}{
+		// Test 1: reads are allowed.
+		{httpGET, "/mybucket/myobject", "", false, http.StatusOK},
+		// Test 2: writes are rejected.
+		{httpPUT, "/mybucket/myobject", "", false, http.StatusServiceUnavailable},
+		// Test 3: deletes are rejected.
+		{httpDELETE, "/mybucket", "", false, http.StatusServiceUnavailable},
+		// Test 4: admin requests are allowed.
+		{httpPOST, "/?mode", "set", true, http.StatusOK},
+		// Test 5: admin header does not bypass the mode for buckets.
+		{httpPUT, "/mybucket/myobject", "set", true, http.StatusServiceUnavailable},
+		// Test 6: internode and browser requests are allowed.
+		{httpPOST, reservedBucket + "/webrpc", "", false, http.StatusOK},
+		// Test 7: admin header does not bypass the mode unsigned.
+		{httpPOST, "/?mode", "set", false, http.StatusServiceUnavailable},
+	}
+	for i, testCase := range testCases {
+		var req *http.Request
+		if testCase.signed {
+			req, err = newTestSignedRequestV4(testCase.method, "http://localhost:9000"+testCase.path, 0, nil,
+				credentials.AccessKey, credentials.SecretKey)
+		} else {
+			req, err = http.NewRequest(testCase.method, "http://localhost:9000"+testCase.path, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, testCase.adminOp)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+	}
+}
+
+// Wrapper for calling the copies from buckets under maintenance tests for both XL multiple disks and single node setup.
+func TestServerModeCopySources(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer resetGlobalServerModes()
+	ExecObjectLayerAPITest(t, testServerModeCopySources, []string{"CopyObject", "ComposeObject", "CopyMultipleObjects"})
+}
+
+func testServerModeCopySources(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("Initializing event notifiers failed")
+	}
+
+	maintainedBucket := "maintained-sources"
+	if err := obj.MakeBucket(context.Background(), maintainedBucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	data := []byte("hello")
+	for _, bucket := range []string{bucketName, maintainedBucket} {
+		if _, err := obj.PutObject(context.Background(), bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	globalServerModes = newServerModes()
+	globalServerModes.Set(maintainedBucket, serverModeMaintenance)
+
+	sendRequest := func(method, urlStr string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		req, err := newTestRequest(method, urlStr, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, testCase := range []struct {
+		srcBucket  string
+		statusCode int
+	}{
+		{bucketName, http.StatusOK},
+		// Sources should not be under maintenance.
+		{maintainedBucket, http.StatusServiceUnavailable},
+	} {
+		// Copy.
+		header := http.Header{"X-Amz-Copy-Source": []string{url.QueryEscape("/" + testCase.srcBucket + "/object")}}
+		rec := sendRequest("PUT", getCopyObjectURL("", bucketName, "copied"), nil, header)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the copy response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+
+		// Compose.
+		composeXML, err := xml.Marshal(ComposeObjectRequest{Sources: []ComposeSource{{Bucket: testCase.srcBucket, Key: "object"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec = sendRequest("POST", getComposeObjectURL("", bucketName, "composed"), composeXML, nil)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("%s: Test %d: Expected the compose response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+
+		// Bulk copy, failing copies are reported per object.
+		copyXML, err := xml.Marshal(CopyObjectsRequest{Objects: []CopyObjectIdentifier{{Source: "/" + testCase.srcBucket + "/object", ObjectName: "bulk-copied"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec = sendRequest("POST", getCopyMultipleObjectsURL("", bucketName), copyXML, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Test %d: Expected the bulk copy response status to be `%d`, but instead found `%d`",
+				instanceType, i+1, http.StatusOK, rec.Code)
+		}
+		copyResponse := CopyObjectsResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &copyResponse); err != nil {
+			t.Fatal(err)
+		}
+		maintained := len(copyResponse.Errors) == 1 && copyResponse.Errors[0].Code == getAPIError(ErrBucketMaintenanceMode).Code
+		if maintained != (testCase.statusCode == http.StatusServiceUnavailable) {
+			t.Errorf("%s: Test %d: Unexpected bulk copy response %+v", instanceType, i+1, copyResponse)
+		}
+	}
+}
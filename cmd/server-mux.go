@@ -143,6 +143,8 @@ func (c *ConnMux) Close() (err error) {
 type ListenerMux struct {
 	net.Listener
 	config *tls.Config
+	// key identifies the listener when handed over to a restarted process.
+	key string
 	// acceptResCh is a channel for transporting wrapped net.Conn (regular or tls)
 	// after peeking the content of the latter
 	acceptResCh chan ListenerMuxAcceptRes
@@ -166,7 +168,8 @@ type ListenerMuxAcceptRes struct {
 // Effective value of total keep alive comes upto 9 x 3 * time.Minute = 27 Minutes.
 var defaultKeepAliveTimeout = 3 * time.Minute // 3 minutes.
 
-// newListenerMux listens and wraps accepted connections with tls after protocol peeking
+// newListenerMux listens and wraps accepted connections with tls after protocol peeking,
+// a nil config disables protocol peeking and serves only plain text connections.
 func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
 	l := ListenerMux{
 		Listener:    listener,
@@ -178,23 +181,25 @@ func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
 	go func() {
 		// Loop for accepting new connections
 		for {
-			// Extract tcp listener.
-			tcpListener, ok := l.Listener.(*net.TCPListener)
-			if !ok {
-				l.acceptResCh <- ListenerMuxAcceptRes{err: errInvalidArgument}
-				return
-			}
-
-			// Use accept TCP method to receive the connection.
-			conn, err := tcpListener.AcceptTCP()
+			conn, err := l.Listener.Accept()
 			if err != nil {
 				l.acceptResCh <- ListenerMuxAcceptRes{err: err}
 				return
 			}
 
-			// Enable keep alive for each connection.
-			conn.SetKeepAlive(true)
-			conn.SetKeepAlivePeriod(defaultKeepAliveTimeout)
+			// Enable keep alive for each TCP connection, unix
+			// domain sockets do not support keep alives.
+			if tcpConn, ok := conn.(*net.TCPConn); ok {
+				tcpConn.SetKeepAlive(true)
+				tcpConn.SetKeepAlivePeriod(defaultKeepAliveTimeout)
+			}
+
+			// Listeners without TLS config serve only plain
+			// text connections, no need to peek.
+			if l.config == nil {
+				l.acceptResCh <- ListenerMuxAcceptRes{conn: conn}
+				continue
+			}
 
 			// Wrap the connection with ConnMux to be able to peek the data in the incoming connection
 			// and decide if we need to wrap the connection itself with a TLS or not
@@ -271,6 +276,8 @@ func (l *ListenerMux) Accept() (net.Conn, error) {
 type ServerMux struct {
 	*http.Server
 	listeners       []*ListenerMux
+	extraListeners  []serverListener // Additional listeners configured via `--listen`.
+	servers         []*http.Server   // Serving connections accepted on each listener.
 	WaitGroup       *sync.WaitGroup
 	GracefulTimeout time.Duration
 	mu              sync.Mutex // guards closed, conns, and listener
@@ -311,15 +318,19 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 	var listeners []*ListenerMux
 	if host == "" {
 		var listener net.Listener
-		listener, err = net.Listen("tcp", serverAddr)
+		listener, err = listen("tcp", serverAddr)
 		if err != nil {
 			return nil, err
 		}
-		listeners = append(listeners, newListenerMux(listener, tls))
+		lm := newListenerMux(listener, tls)
+		lm.key = listenerKey("tcp", serverAddr)
+		listeners = append(listeners, lm)
 		return listeners, nil
 	}
+	// Host names are listened on all their addresses, both IPv4
+	// and IPv6 ones.
 	var addrs []string
-	if net.ParseIP(host) != nil {
+	if hostIP(host) != nil {
 		addrs = append(addrs, host)
 	} else {
 		addrs, err = net.LookupHost(host)
@@ -332,15 +343,26 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 	}
 	for _, addr := range addrs {
 		var listener net.Listener
-		listener, err = net.Listen("tcp", net.JoinHostPort(addr, port))
+		listenAddr := net.JoinHostPort(addr, port)
+		listener, err = listen("tcp", listenAddr)
 		if err != nil {
 			return nil, err
 		}
-		listeners = append(listeners, newListenerMux(listener, tls))
+		lm := newListenerMux(listener, tls)
+		lm.key = listenerKey("tcp", listenAddr)
+		listeners = append(listeners, lm)
 	}
 	return listeners, nil
 }
 
+// AddListener - registers an additional listener, which is opened by
+// ListenAndServe along with the listeners on the server address.
+func (m *ServerMux) AddListener(l serverListener) {
+	m.mu.Lock()
+	m.extraListeners = append(m.extraListeners, l)
+	m.mu.Unlock()
+}
+
 // ListenAndServe - serve HTTP requests with protocol multiplexing support
 // TLS is actived when certFile and keyFile parameters are not empty.
 func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
@@ -369,11 +391,53 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 	}
 
 	m.mu.Lock()
+	for _, l := range m.extraListeners {
+		var listener *ListenerMux
+		if listener, err = l.listen(); err != nil {
+			m.mu.Unlock()
+			for _, listener = range listeners {
+				listener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
 	m.listeners = listeners
 	m.mu.Unlock()
 
-	// All http requests start to be processed by httpHandler
-	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var wg = &sync.WaitGroup{}
+	for _, listener := range listeners {
+		// Plain text requests are redirected to https only on
+		// listeners configured with certificates.
+		listenerTLS := listener.config != nil && len(listener.config.Certificates) > 0
+		srv := &http.Server{
+			Handler:        m.httpHandler(listenerTLS),
+			MaxHeaderBytes: m.Server.MaxHeaderBytes,
+			// Track connections of all listeners for graceful shutdown.
+			ConnState: m.Server.ConnState,
+		}
+		m.mu.Lock()
+		m.servers = append(m.servers, srv)
+		m.mu.Unlock()
+		wg.Add(1)
+		go func(listener *ListenerMux) {
+			defer wg.Done()
+			serr := srv.Serve(listener)
+			// Do not print the error if the listener is closed.
+			if !listener.IsClosed() {
+				errorIf(serr, "Unable to serve incoming requests.")
+			}
+		}(listener)
+	}
+	// Wait for all http.Serve's to return.
+	wg.Wait()
+	return nil
+}
+
+// httpHandler - returns the handler all http requests start to be
+// processed by, redirects plain text requests to https when tlsEnabled.
+func (m *ServerMux) httpHandler(tlsEnabled bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if tlsEnabled && r.TLS == nil {
 			// TLS is enabled but Request is not TLS configured
 			u := url.URL{
@@ -391,22 +455,6 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 			m.Server.Handler.ServeHTTP(w, r)
 		}
 	})
-
-	var wg = &sync.WaitGroup{}
-	for _, listener := range listeners {
-		wg.Add(1)
-		go func(listener *ListenerMux) {
-			defer wg.Done()
-			serr := http.Serve(listener, httpHandler)
-			// Do not print the error if the listener is closed.
-			if !listener.IsClosed() {
-				errorIf(serr, "Unable to serve incoming requests.")
-			}
-		}(listener)
-	}
-	// Wait for all http.Serve's to return.
-	wg.Wait()
-	return nil
 }
 
 // Close initiates the graceful shutdown
@@ -427,7 +475,12 @@ func (m *ServerMux) Close() error {
 		}
 	}
 
+	// Responses to in-flight requests carry `Connection: close`,
+	// clients have to reconnect for any further requests.
 	m.SetKeepAlivesEnabled(false)
+	for _, srv := range m.servers {
+		srv.SetKeepAlivesEnabled(false)
+	}
 	// Force close any idle and new connections. Waiting for other connections
 	// to close on their own (within the timeout period)
 	for c, st := range m.conns {
@@ -438,6 +491,8 @@ func (m *ServerMux) Close() error {
 
 	// If the GracefulTimeout happens then forcefully close all connections
 	t := time.AfterFunc(m.GracefulTimeout, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
 		for c := range m.conns {
 			c.Close()
 		}
@@ -472,3 +472,71 @@ func generateTestCert(host string) error {
 	keyOut.Close()
 	return nil
 }
+
+// Tests that Close waits for in-flight requests, and their responses
+// ask clients to close the connection.
+func TestServerCloseDrainsRequests(t *testing.T) {
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+		fmt.Fprint(w, "hello")
+	}))
+	m.GracefulTimeout = 10 * time.Second
+	go m.ListenAndServe("", "")
+
+	type result struct {
+		res *http.Response
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		client := http.Client{}
+		for {
+			res, err := client.Get("http://" + addr)
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			resCh <- result{res, err}
+			return
+		}
+	}()
+	<-inFlight
+
+	closed := make(chan error, 1)
+	go func() { closed <- m.Close() }()
+
+	// Close should be blocked by the in-flight request.
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	r := <-resCh
+	if r.res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", r.res.StatusCode)
+	}
+	if !r.res.Close {
+		t.Fatal("Expected response to carry `Connection: close`")
+	}
+	r.res.Body.Close()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after draining requests")
+	}
+}
@@ -74,6 +74,19 @@ func printStartupMessage(apiEndPoints []string) {
 	}
 }
 
+// Prints the endpoints of the named listeners, such as the accelerate
+// listener dedicated to WAN uploads.
+func printListenerNamesMsg(listeners []serverListener) {
+	if globalQuiet {
+		return
+	}
+	for _, l := range listeners {
+		if l.name != "" {
+			console.Println(colorBlue("\nEndpoint (%s): ", l.name) + colorBold(l.String()))
+		}
+	}
+}
+
 // Prints common server startup message. Prints credential, region and browser access.
 func printServerCommonMsg(apiEndpoints []string) {
 	// Get saved credentials.
@@ -143,6 +156,9 @@ func getStorageInfoMsg(storageInfo StorageInfo) string {
 		humanize.IBytes(uint64(storageInfo.Total)))
 	if storageInfo.Backend.Type == XL {
 		diskInfo := fmt.Sprintf(" %d Online, %d Offline. ", storageInfo.Backend.OnlineDisks, storageInfo.Backend.OfflineDisks)
+		if storageInfo.Backend.UnwritableDisks > 0 {
+			diskInfo += fmt.Sprintf("%d Offline drive(s) not writable. ", storageInfo.Backend.UnwritableDisks)
+		}
 		if maxDiskFailures := storageInfo.Backend.ReadQuorum - storageInfo.Backend.OfflineDisks; maxDiskFailures >= 0 {
 			diskInfo += fmt.Sprintf("We can withstand [%d] more drive failure(s).", maxDiskFailures)
 		}
@@ -38,12 +38,19 @@ func TestStorageInfoMsg(t *testing.T) {
 			OfflineDisks int
 			ReadQuorum   int
 			WriteQuorum  int
-		}{XL, 7, 1, 4, 5},
+
+			UnwritableDisks int
+		}{XL, 7, 1, 4, 5, 0},
 	}
 
 	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "2.0 GiB Free, 10 GiB Total") || !strings.Contains(msg, "7 Online, 1 Offline") {
 		t.Fatal("Unexpected storage info message, found:", msg)
 	}
+
+	infoStorage.Backend.UnwritableDisks = 1
+	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "1 Offline drive(s) not writable") {
+		t.Fatal("Unexpected storage info message, found:", msg)
+	}
 }
 
 // Tests if certificate expiry warning will be printed
@@ -64,7 +64,7 @@ func finalizeAPIEndpoints(apiServer *http.Server) (endPoints []string, err error
 
 	// Construct proper endpoints.
 	for _, host := range hosts {
-		endPoints = append(endPoints, fmt.Sprintf("%s://%s:%s", scheme, host, port))
+		endPoints = append(endPoints, fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port)))
 	}
 
 	// Success.
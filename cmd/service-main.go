@@ -0,0 +1,76 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+// Name under which minio server is registered as a native Windows
+// service, also used as the event log source.
+const minioServiceName = "minio"
+
+var serviceCmd = cli.Command{
+	Name:            "service",
+	Usage:           "Manage minio server as a Windows service.",
+	Action:          mainService,
+	Flags:           globalFlags,
+	SkipFlagParsing: true,
+	CustomHelpTemplate: `NAME:
+   minio {{.Name}} - {{.Usage}}
+
+USAGE:
+   minio {{.Name}} COMMAND [SERVER FLAGS] [PATH...]
+
+COMMANDS:
+   install   Install minio server as a service, started automatically upon boot.
+   remove    Remove the installed service.
+   start     Start the installed service.
+   stop      Stop the running service.
+
+EXAMPLES:
+   1. Install minio server as a service serving "C:\data", with configuration under "C:\minio".
+      $ minio {{.Name}} install --config-dir C:\minio C:\data
+
+   2. Start the installed service, server logs are available in the Windows event log.
+      $ minio {{.Name}} start
+
+`,
+}
+
+// mainService handler called for 'minio service' command.
+func mainService(ctx *cli.Context) {
+	args := ctx.Args()
+	if !args.Present() || args.First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, "service", 1)
+	}
+
+	var err error
+	switch args.First() {
+	case "install":
+		// Remaining arguments are passed to `minio server`
+		// every time the service is started.
+		err = installService(args.Tail())
+	case "remove":
+		err = removeService()
+	case "start":
+		err = startService()
+	case "stop":
+		err = stopService()
+	default:
+		cli.ShowCommandHelpAndExit(ctx, "service", 1)
+	}
+	fatalIf(err, "Unable to %s service %s.", args.First(), minioServiceName)
+}
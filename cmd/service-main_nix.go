@@ -0,0 +1,45 @@
+// +build !windows
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "errors"
+
+// Native services are supported only on Windows, see docs/systemd
+// for running minio server as a service on Linux.
+var errServiceNotSupported = errors.New("Windows services are not supported on this platform")
+
+func installService(serverArgs []string) error {
+	return errServiceNotSupported
+}
+
+func removeService() error {
+	return errServiceNotSupported
+}
+
+func startService() error {
+	return errServiceNotSupported
+}
+
+func stopService() error {
+	return errServiceNotSupported
+}
+
+// startServiceControlHandler - nothing to do, systemd and other service
+// managers control minio server via signals.
+func startServiceControlHandler() {}
@@ -0,0 +1,35 @@
+// +build !windows
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests that service management is refused outside of Windows.
+func TestServiceNotSupported(t *testing.T) {
+	for i, fn := range []func() error{
+		func() error { return installService([]string{"/tmp/export"}) },
+		removeService,
+		startService,
+		stopService,
+	} {
+		if err := fn(); err != errServiceNotSupported {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, errServiceNotSupported, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// +build windows
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// Returns the absolute path of the running minio binary, which is
+// registered with the service control manager.
+func getExecutablePath() (string, error) {
+	exePath, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		return "", err
+	}
+	if filepath.Ext(exePath) == "" {
+		exePath += ".exe"
+	}
+	if _, err = os.Stat(exePath); err != nil {
+		return "", err
+	}
+	return exePath, nil
+}
+
+// installService - registers minio server as a service started upon
+// boot with serverArgs, along with an event log source.
+func installService(serverArgs []string) error {
+	exePath, err := getExecutablePath()
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(minioServiceName); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", minioServiceName)
+	}
+
+	s, err := m.CreateService(minioServiceName, exePath, mgr.Config{
+		DisplayName: "Minio",
+		Description: "Minio Cloud Storage Server.",
+		StartType:   mgr.StartAutomatic,
+	}, append([]string{"server"}, serverArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	err = eventlog.InstallAsEventCreate(minioServiceName, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil {
+		s.Delete()
+		return err
+	}
+	return nil
+}
+
+// removeService - removes the service and its event log source.
+func removeService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(minioServiceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err = s.Delete(); err != nil {
+		return err
+	}
+	return eventlog.Remove(minioServiceName)
+}
+
+// startService - asks the service control manager to start the service.
+func startService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(minioServiceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	return s.Start()
+}
+
+// stopService - asks the service control manager to stop the service.
+func stopService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(minioServiceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	_, err = s.Control(svc.Stop)
+	return err
+}
+
+// minioService - implements svc.Handler, translates requests of the
+// service control manager into service signals.
+type minioService struct{}
+
+// Execute - reports the service as running and waits for stop or
+// shutdown requests.
+func (minioService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for c := range r {
+		switch c.Cmd {
+		case svc.Interrogate:
+			changes <- c.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			globalServiceSignalCh <- serviceStop
+			return false, 0
+		}
+	}
+	return false, 0
+}
+
+// eventLogHook - logrus hook writing log entries to the Windows event log.
+type eventLogHook struct {
+	elog *eventlog.Log
+}
+
+// Fire - writes the log entry into the event log.
+func (h eventLogHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return fmt.Errorf("Unable to read entry, %v", err)
+	}
+	switch entry.Level {
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		return h.elog.Error(1, line)
+	case logrus.WarnLevel:
+		return h.elog.Warning(1, line)
+	}
+	return h.elog.Info(1, line)
+}
+
+// Levels - indicate log levels supported.
+func (h eventLogHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.PanicLevel,
+		logrus.FatalLevel,
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+	}
+}
+
+// startServiceControlHandler - when started by the service control
+// manager, logs into the event log and handles service control
+// requests in the background.
+func startServiceControlHandler() {
+	interactive, err := svc.IsAnInteractiveSession()
+	fatalIf(err, "Unable to determine if running as a service.")
+	if interactive {
+		return
+	}
+
+	elog, err := eventlog.Open(minioServiceName)
+	fatalIf(err, "Unable to open event log.")
+
+	eventLogger := logrus.New()
+	eventLogger.Hooks.Add(eventLogHook{elog})
+	eventLogger.Out = ioutil.Discard
+	eventLogger.Formatter = new(logrus.JSONFormatter)
+	eventLogger.Level = logrus.WarnLevel
+
+	log.mu.Lock()
+	log.loggers = append(log.loggers, eventLogger)
+	log.mu.Unlock()
+
+	go func() {
+		errorIf(svc.Run(minioServiceName, minioService{}), "Unable to run as service %s.", minioServiceName)
+	}()
+}
@@ -19,6 +19,9 @@ package cmd
 import (
 	"os"
 	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 )
 
@@ -26,9 +29,10 @@ import (
 type serviceSignal int
 
 const (
-	serviceStatus  = iota // Gets status about the service.
-	serviceRestart        // Restarts the service.
-	serviceStop           // Stops the server.
+	serviceStatus       = iota // Gets status about the service.
+	serviceRestart             // Restarts the service.
+	serviceStop                // Stops the server.
+	serviceReloadConfig        // Reloads the config.
 	// Add new service requests here.
 )
 
@@ -47,9 +51,9 @@ func init() {
 // restartProcess starts a new process passing it the active fd's. It
 // doesn't fork, but starts a new process using the same environment and
 // arguments as when it was originally started. This allows for a newly
-// deployed binary to be started. It returns the pid of the newly started
-// process when successful.
-func restartProcess() error {
+// deployed binary to be started. Listening sockets in files are handed
+// over to the new process, listenersEnv describes them.
+func restartProcess(files []*os.File, listenersEnv string) error {
 	// Use the original binary location. This works with symlinks such that if
 	// the file it points to has been changed we will use the updated symlink.
 	argv0, err := exec.LookPath(os.Args[0])
@@ -57,11 +61,54 @@ func restartProcess() error {
 		return err
 	}
 
-	// Pass on the environment and replace the old count key with the new one.
+	// Pass on the environment and replace the old listeners key with the new one.
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, minioInheritedListenersEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+	if len(files) > 0 {
+		env = append(env, listenersEnv)
+	}
+
 	cmd := exec.Command(argv0, os.Args[1:]...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Start()
+	cmd.Env = env
+	cmd.ExtraFiles = files
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	// New process is going to be the main process, systemd should
+	// follow it instead of this process.
+	errorIf(sdNotify("MAINPID="+strconv.Itoa(cmd.Process.Pid)), "Unable to notify systemd.")
+	return nil
+}
+
+// restart - starts a new process accepting connections on the same
+// listening sockets, then drains in-flight requests of this process.
+// In case the listening sockets can not be handed over, for example
+// on Windows, the listeners are closed before starting the new process.
+func (m *ServerMux) restart() error {
+	files, listenersEnv, err := m.listenerFiles()
+	if err != nil {
+		errorIf(err, "Unable to hand over listeners, restarting without them.")
+		if err = m.Close(); err != nil {
+			errorIf(err, "Unable to close server gracefully")
+		}
+		return restartProcess(nil, "")
+	}
+	err = restartProcess(files, listenersEnv)
+	// New process holds its own copy of the sockets by now.
+	for _, f := range files {
+		f.Close()
+	}
+	if err != nil {
+		return err
+	}
+	return m.Close()
 }
 
 // Handles all serviceSignal and execute service functions.
@@ -87,6 +134,15 @@ func (m *ServerMux) handleServiceSignals() error {
 		globalServiceSignalCh <- serviceStop
 	}(trapCh)
 
+	// Reload config on every SIGHUP.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			globalServiceSignalCh <- serviceReloadConfig
+		}
+	}()
+
 	// Start listening on service signal. Monitor signals.
 	for {
 		signal := <-globalServiceSignalCh
@@ -94,14 +150,17 @@ func (m *ServerMux) handleServiceSignals() error {
 		case serviceStatus:
 			/// We don't do anything for this.
 		case serviceRestart:
-			if err := m.Close(); err != nil {
-				errorIf(err, "Unable to close server gracefully")
-			}
-			if err := restartProcess(); err != nil {
+			errorIf(sdNotify(sdNotifyReloading), "Unable to notify systemd.")
+			if err := m.restart(); err != nil {
 				errorIf(err, "Unable to restart the server.")
 			}
 			runExitFn(nil)
+		case serviceReloadConfig:
+			errorIf(sdNotify(sdNotifyReloading), "Unable to notify systemd.")
+			errorIf(reloadConfig(false), "Unable to reload config.")
+			errorIf(sdNotify(sdNotifyReady), "Unable to notify systemd.")
 		case serviceStop:
+			errorIf(sdNotify(sdNotifyStopping), "Unable to notify systemd.")
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
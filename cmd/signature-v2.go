@@ -65,10 +65,10 @@ var resourceList = []string{
 }
 
 func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
-	cred := serverConfig.GetCredential()
 	accessKey := formValues["Awsaccesskeyid"]
-	if accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	cred, apiErr := lookupCredential(accessKey)
+	if apiErr != ErrNone {
+		return apiErr
 	}
 	signature := formValues["Signature"]
 	policy := formValues["Policy"]
@@ -82,9 +82,6 @@ func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html#RESTAuthenticationQueryStringAuth
 // returns ErrNone if matches. S3 errors otherwise.
 func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// url.RawPath will be valid if path has any encoded characters, if not it will
 	// be empty - in which case we need to consider url.Path (bug in net/http?)
 	encodedResource := r.URL.RawPath
@@ -111,12 +108,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		case "Expires":
 			expires, err = url.QueryUnescape(keyval[1])
 		default:
-			unescapedQuery, qerr := url.QueryUnescape(query)
-			if qerr == nil {
-				filteredQueries = append(filteredQueries, unescapedQuery)
-			} else {
-				err = qerr
-			}
+			filteredQueries = append(filteredQueries, query)
 		}
 		// Check if the query unescaped properly.
 		if err != nil {
@@ -131,8 +123,9 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 	}
 
 	// Validate if access key id same.
-	if accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	cred, apiErr := lookupCredential(accessKey)
+	if apiErr != ErrNone {
+		return apiErr
 	}
 
 	// Make sure the request has not expired.
@@ -145,8 +138,20 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 	if expiresInt < time.Now().UTC().Unix() {
 		return ErrExpiredPresignRequest
 	}
+	// Presigned V2 URLs carry no signing date, they should expire
+	// before the longest expiry allowed from now on.
+	if expiresInt-time.Now().UTC().Unix() > int64(globalPresignMaxExpiry/time.Second) {
+		return ErrMaximumExpires
+	}
 
-	expectedSignature := preSignatureV2(r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
+	// Unescape the other query strings, as the values of the sub-resources.
+	unescapedQuery, err := unescapeQueryV2(strings.Join(filteredQueries, "&"))
+	if err != nil {
+		errorIf(err, "Unable to unescape query values %s", r.URL.RawQuery)
+		return ErrInvalidQueryParams
+	}
+
+	expectedSignature := preSignatureV2(cred, r.Method, encodedResource, unescapedQuery, r.Header, expires)
 	if gotSignature != expectedSignature {
 		return ErrSignatureDoesNotMatch
 	}
@@ -197,9 +202,8 @@ func validateV2AuthHeader(v2Auth string) APIErrorCode {
 	}
 
 	// Access credentials.
-	cred := serverConfig.GetCredential()
-	if keySignFields[0] != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	if _, apiErr := lookupCredential(keySignFields[0]); apiErr != ErrNone {
+		return apiErr
 	}
 
 	return ErrNone
@@ -223,10 +227,17 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 		}
 	}
 
-	// Encode query strings
-	encodedQuery := r.URL.Query().Encode()
+	// Unescape query strings, as the values of the sub-resources.
+	unescapedQuery, err := unescapeQueryV2(r.URL.RawQuery)
+	if err != nil {
+		errorIf(err, "Unable to unescape query values %s", r.URL.RawQuery)
+		return ErrInvalidQueryParams
+	}
+
+	// Access credentials, validated along with the header.
+	cred, _ := lookupCredential(getRequestAccessKey(r))
 
-	expectedAuth := signatureV2(r.Method, encodedResource, encodedQuery, r.Header)
+	expectedAuth := signatureV2(cred, r.Method, encodedResource, unescapedQuery, r.Header)
 	if v2Auth != expectedAuth {
 		return ErrSignatureDoesNotMatch
 	}
@@ -241,16 +252,14 @@ func calculateSignatureV2(stringToSign string, secret string) string {
 }
 
 // Return signature-v2 for the presigned request.
-func preSignatureV2(method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
-	cred := serverConfig.GetCredential()
-	stringToSign := presignV2STS(method, encodedResource, encodedQuery, headers, expires)
+func preSignatureV2(cred credential, method string, encodedResource string, query map[string]string, headers http.Header, expires string) string {
+	stringToSign := presignV2STS(method, encodedResource, query, headers, expires)
 	return calculateSignatureV2(stringToSign, cred.SecretKey)
 }
 
 // Return signature-v2 authrization header.
-func signatureV2(method string, encodedResource string, encodedQuery string, headers http.Header) string {
-	cred := serverConfig.GetCredential()
-	stringToSign := signV2STS(method, encodedResource, encodedQuery, headers)
+func signatureV2(cred credential, method string, encodedResource string, query map[string]string, headers http.Header) string {
+	stringToSign := signV2STS(method, encodedResource, query, headers)
 	signature := calculateSignatureV2(stringToSign, cred.SecretKey)
 	return fmt.Sprintf("%s %s:%s", signV2Algorithm, cred.AccessKey, signature)
 }
@@ -275,23 +284,39 @@ func canonicalizedAmzHeadersV2(headers http.Header) string {
 	return strings.Join(canonicalHeaders, "\n")
 }
 
-// Return canonical resource string.
-func canonicalizedResourceV2(encodedPath string, encodedQuery string) string {
-	queries := strings.Split(encodedQuery, "&")
-	keyval := make(map[string]string)
-	for _, query := range queries {
-		key := query
+// unescapeQueryV2 - returns the unescaped values of the parameters of
+// rawQuery by their unescaped names, the values of the sub-resources
+// such as response-content-disposition are signed unescaped by
+// signature V2. Parameters are split before being unescaped, values
+// may contain escaped '&' and '='.
+func unescapeQueryV2(rawQuery string) (map[string]string, error) {
+	query := make(map[string]string)
+	for _, param := range strings.Split(rawQuery, "&") {
+		key := param
 		val := ""
-		index := strings.Index(query, "=")
+		index := strings.Index(param, "=")
 		if index != -1 {
-			key = query[:index]
-			val = query[index+1:]
+			key = param[:index]
+			val = param[index+1:]
+		}
+		unescapedKey, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
 		}
-		keyval[key] = val
+		unescapedVal, err := url.QueryUnescape(val)
+		if err != nil {
+			return nil, err
+		}
+		query[unescapedKey] = unescapedVal
 	}
+	return query, nil
+}
+
+// Return canonical resource string.
+func canonicalizedResourceV2(encodedPath string, query map[string]string) string {
 	var canonicalQueries []string
 	for _, key := range resourceList {
-		val, ok := keyval[key]
+		val, ok := query[key]
 		if !ok {
 			continue
 		}
@@ -309,7 +334,7 @@ func canonicalizedResourceV2(encodedPath string, encodedQuery string) string {
 }
 
 // Return string to sign for authz header calculation.
-func signV2STS(method string, encodedResource string, encodedQuery string, headers http.Header) string {
+func signV2STS(method string, encodedResource string, query map[string]string, headers http.Header) string {
 	canonicalHeaders := canonicalizedAmzHeadersV2(headers)
 	if len(canonicalHeaders) > 0 {
 		canonicalHeaders += "\n"
@@ -329,13 +354,13 @@ func signV2STS(method string, encodedResource string, encodedQuery string, heade
 		headers.Get("Content-Type"),
 		headers.Get("Date"),
 		canonicalHeaders,
-	}, "\n") + canonicalizedResourceV2(encodedResource, encodedQuery)
+	}, "\n") + canonicalizedResourceV2(encodedResource, query)
 
 	return stringToSign
 }
 
 // Return string to sign for pre-sign signature calculation.
-func presignV2STS(method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
+func presignV2STS(method string, encodedResource string, query map[string]string, headers http.Header, expires string) string {
 	canonicalHeaders := canonicalizedAmzHeadersV2(headers)
 	if len(canonicalHeaders) > 0 {
 		canonicalHeaders += "\n"
@@ -355,6 +380,6 @@ func presignV2STS(method string, encodedResource string, encodedQuery string, he
 		headers.Get("Content-Type"),
 		expires,
 		canonicalHeaders,
-	}, "\n") + canonicalizedResourceV2(encodedResource, encodedQuery)
+	}, "\n") + canonicalizedResourceV2(encodedResource, query)
 	return stringToSign
 }
@@ -22,6 +22,35 @@ func TestResourceListSorting(t *testing.T) {
 	}
 }
 
+// Tests the canonical resource of the unescaped query strings.
+func TestCanonicalizedResourceV2(t *testing.T) {
+	testCases := []struct {
+		rawQuery string
+		resource string
+	}{
+		{"", "/bucket/object"},
+		{"acl", "/bucket/object?acl"},
+		{"uploadId=id&partNumber=1&prefix=a", "/bucket/object?partNumber=1&uploadId=id"},
+		{"response-content-type=text%2Fplain", "/bucket/object?response-content-type=text/plain"},
+		// Escaped '&' and '=' are part of the values.
+		{"response-content-disposition=attachment%3B%20filename%3D%22a%26b.txt%22&response-cache-control=no-cache",
+			`/bucket/object?response-cache-control=no-cache&response-content-disposition=attachment; filename="a&b.txt"`},
+	}
+	for i, testCase := range testCases {
+		query, err := unescapeQueryV2(testCase.rawQuery)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if resource := canonicalizedResourceV2("/bucket/object", query); resource != testCase.resource {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.resource, resource)
+		}
+	}
+
+	if _, err := unescapeQueryV2("response-content-type=%zz"); err == nil {
+		t.Error("Expected invalid escapes to fail")
+	}
+}
+
 // Tests presigned v2 signature.
 func TestDoesPresignedV2SignatureMatch(t *testing.T) {
 	root, err := newTestConfig(globalMinioDefaultRegion)
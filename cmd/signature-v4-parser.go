@@ -179,6 +179,9 @@ func parsePreSignV4(query url.Values) (preSignValues, APIErrorCode) {
 	if preSignV4Values.Expires < 0 {
 		return preSignValues{}, ErrNegativeExpires
 	}
+	if preSignV4Values.Expires > globalPresignMaxExpiry {
+		return preSignValues{}, ErrMaximumExpires
+	}
 	// Save signed headers.
 	preSignV4Values.SignedHeaders, err = parseSignedHeader("SignedHeaders=" + query.Get("X-Amz-SignedHeaders"))
 	if err != ErrNone {
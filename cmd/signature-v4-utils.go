@@ -46,6 +46,18 @@ func skipContentSha256Cksum(r *http.Request) bool {
 	return isRequestUnsignedPayload(r) || isRequestPresignedUnsignedPayload(r)
 }
 
+// getSigningRegion - returns the region to verify a signature scoped to
+// reqRegion with, requests can also be signed for any of the configured
+// bucket regions.
+func getSigningRegion(reqRegion string, confRegion string) string {
+	for _, bucketRegion := range serverConfig.GetBucketRegions() {
+		if isValidRegion(reqRegion, bucketRegion) {
+			return reqRegion
+		}
+	}
+	return confRegion
+}
+
 // isValidRegion - verify if incoming region value is valid with configured Region.
 func isValidRegion(reqRegion string, confRegion string) bool {
 	if confRegion == "" || confRegion == "US" {
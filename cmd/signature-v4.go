@@ -32,6 +32,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/sha256-simd"
@@ -45,18 +46,33 @@ const (
 	presignedHostHeader = "host"
 )
 
-// getCanonicalHeaders generate a list of request headers with their values
-func getCanonicalHeaders(signedHeaders http.Header, host string) string {
-	var headers []string
-	vals := make(http.Header)
+// sortedSignedHeaders - returns the alphabetically sorted lowercase
+// names of the signed headers along with the host header, and their
+// values by lowercase name.
+func sortedSignedHeaders(signedHeaders http.Header) (headers []string, vals http.Header) {
+	headers = make([]string, 0, len(signedHeaders)+1)
+	vals = make(http.Header, len(signedHeaders))
 	for k, vv := range signedHeaders {
-		headers = append(headers, strings.ToLower(k))
-		vals[strings.ToLower(k)] = vv
+		k = strings.ToLower(k)
+		headers = append(headers, k)
+		vals[k] = vv
 	}
 	headers = append(headers, presignedHostHeader)
 	sort.Strings(headers)
+	return headers, vals
+}
 
+// getCanonicalHeaders generate a list of request headers with their values
+func getCanonicalHeaders(signedHeaders http.Header, host string) string {
 	var buf bytes.Buffer
+	headers, vals := sortedSignedHeaders(signedHeaders)
+	writeCanonicalHeaders(&buf, headers, vals, host)
+	return buf.String()
+}
+
+// writeCanonicalHeaders - writes the canonical headers of the sorted
+// headers with their values to buf.
+func writeCanonicalHeaders(buf *bytes.Buffer, headers []string, vals http.Header, host string) {
 	for _, k := range headers {
 		buf.WriteString(k)
 		buf.WriteByte(':')
@@ -74,17 +90,11 @@ func getCanonicalHeaders(signedHeaders http.Header, host string) string {
 			buf.WriteByte('\n')
 		}
 	}
-	return buf.String()
 }
 
 // getSignedHeaders generate a string i.e alphabetically sorted, semicolon-separated list of lowercase request header names
 func getSignedHeaders(signedHeaders http.Header) string {
-	var headers []string
-	for k := range signedHeaders {
-		headers = append(headers, strings.ToLower(k))
-	}
-	headers = append(headers, presignedHostHeader)
-	sort.Strings(headers)
+	headers, _ := sortedSignedHeaders(signedHeaders)
 	return strings.Join(headers, ";")
 }
 
@@ -99,45 +109,90 @@ func getSignedHeaders(signedHeaders http.Header) string {
 //  <HashedPayload>
 //
 func getCanonicalRequest(extractedSignedHeaders http.Header, payload, queryStr, urlPath, method, host string) string {
-	rawQuery := strings.Replace(queryStr, "+", "%20", -1)
-	encodedPath := getURLEncodedName(urlPath)
-	canonicalRequest := strings.Join([]string{
-		method,
-		encodedPath,
-		rawQuery,
-		getCanonicalHeaders(extractedSignedHeaders, host),
-		getSignedHeaders(extractedSignedHeaders),
-		payload,
-	}, "\n")
-	return canonicalRequest
+	headers, vals := sortedSignedHeaders(extractedSignedHeaders)
+
+	// Build the canonical request in a single buffer.
+	var buf bytes.Buffer
+	buf.Grow(len(method) + len(urlPath) + len(queryStr) + len(payload) + 64*len(headers))
+	buf.WriteString(method)
+	buf.WriteByte('\n')
+	buf.WriteString(getURLEncodedName(urlPath))
+	buf.WriteByte('\n')
+	buf.WriteString(strings.Replace(queryStr, "+", "%20", -1))
+	buf.WriteByte('\n')
+	writeCanonicalHeaders(&buf, headers, vals, host)
+	buf.WriteByte('\n')
+	for i, k := range headers {
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		buf.WriteString(k)
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(payload)
+	return buf.String()
 }
 
 // getScope generate a string of a specific date, an AWS region, and a service.
 func getScope(t time.Time, region string) string {
-	scope := strings.Join([]string{
-		t.Format(yyyymmdd),
-		region,
-		"s3",
-		"aws4_request",
-	}, "/")
-	return scope
+	return t.Format(yyyymmdd) + "/" + region + "/s3/aws4_request"
 }
 
 // getStringToSign a string based on selected query values.
 func getStringToSign(canonicalRequest string, t time.Time, region string) string {
-	stringToSign := signV4Algorithm + "\n" + t.Format(iso8601Format) + "\n"
-	stringToSign = stringToSign + getScope(t, region) + "\n"
 	canonicalRequestBytes := sha256.Sum256([]byte(canonicalRequest))
-	stringToSign = stringToSign + hex.EncodeToString(canonicalRequestBytes[:])
-	return stringToSign
+	return signV4Algorithm + "\n" + t.Format(iso8601Format) + "\n" +
+		getScope(t, region) + "\n" + hex.EncodeToString(canonicalRequestBytes[:])
+}
+
+// Maximum number of signing keys cached, beyond that the cache is
+// emptied. A key is cached per secret key, date and region.
+const maxCachedSigningKeys = 128
+
+// signingKeyParams - what a signing key is derived from.
+type signingKeyParams struct {
+	secretKey string
+	date      string
+	region    string
+}
+
+// signingKeyCache - signing keys derived for the recent days, so that
+// requests signed the same day do not derive them again by chaining
+// four HMACs.
+type signingKeyCache struct {
+	mutex *sync.RWMutex
+	keys  map[signingKeyParams][]byte
+}
+
+// Global signing key cache.
+var globalSigningKeyCache = &signingKeyCache{
+	mutex: &sync.RWMutex{},
+	keys:  make(map[signingKeyParams][]byte),
 }
 
 // getSigningKey hmac seed to calculate final signature.
 func getSigningKey(secretKey string, t time.Time, region string) []byte {
-	date := sumHMAC([]byte("AWS4"+secretKey), []byte(t.Format(yyyymmdd)))
+	params := signingKeyParams{secretKey, t.Format(yyyymmdd), region}
+	cache := globalSigningKeyCache
+
+	cache.mutex.RLock()
+	signingKey, ok := cache.keys[params]
+	cache.mutex.RUnlock()
+	if ok {
+		return signingKey
+	}
+
+	date := sumHMAC([]byte("AWS4"+secretKey), []byte(params.date))
 	regionBytes := sumHMAC(date, []byte(region))
 	service := sumHMAC(regionBytes, []byte("s3"))
-	signingKey := sumHMAC(service, []byte("aws4_request"))
+	signingKey = sumHMAC(service, []byte("aws4_request"))
+
+	cache.mutex.Lock()
+	if len(cache.keys) >= maxCachedSigningKeys {
+		cache.keys = make(map[signingKeyParams][]byte)
+	}
+	cache.keys[params] = signingKey
+	cache.mutex.Unlock()
 	return signingKey
 }
 
@@ -159,9 +214,6 @@ func doesPolicySignatureMatch(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
 // returns ErrNone if the signature matches.
 func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Server region.
 	region := serverConfig.GetRegion()
 
@@ -172,12 +224,14 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 	}
 
 	// Verify if the access key id matches.
-	if credHeader.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	cred, errCode := lookupCredential(credHeader.accessKey)
+	if errCode != ErrNone {
+		return errCode
 	}
 
 	// Verify if the region is valid.
 	sRegion := credHeader.scope.region
+	region = getSigningRegion(sRegion, region)
 	if !isValidRegion(sRegion, region) {
 		return ErrInvalidRegion
 	}
@@ -205,9 +259,6 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html
 // returns ErrNone if the signature matches.
 func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request
 	req := *r
 
@@ -218,8 +269,9 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	}
 
 	// Verify if the access key id matches.
-	if pSignValues.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	cred, err := lookupCredential(pSignValues.Credential.accessKey)
+	if err != ErrNone {
+		return err
 	}
 
 	// Hashed payload mismatch, return content sha256 mismatch.
@@ -234,6 +286,7 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	if region == "" {
 		region = sRegion
 	}
+	region = getSigningRegion(sRegion, region)
 	if !isValidRegion(sRegion, region) {
 		return ErrInvalidRegion
 	}
@@ -330,9 +383,6 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
 // returns ErrNone if signature matches.
 func doesSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request.
 	req := *r
 
@@ -370,8 +420,9 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	}
 
 	// Verify if the access key id matches.
-	if signV4Values.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	cred, errCode := lookupCredential(signV4Values.Credential.accessKey)
+	if errCode != ErrNone {
+		return errCode
 	}
 
 	// Verify if region is valid.
@@ -383,6 +434,7 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	if region == "" {
 		region = sRegion
 	}
+	region = getSigningRegion(sRegion, region)
 	// Should validate region, only if region is set.
 	if !isValidRegion(sRegion, region) {
 		return ErrInvalidRegion
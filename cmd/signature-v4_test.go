@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -332,3 +333,72 @@ func TestDoesPresignedSignatureMatch(t *testing.T) {
 		}
 	}
 }
+
+// Tests signing keys are cached per secret key, date and region.
+func TestGetSigningKeyCache(t *testing.T) {
+	deriveSigningKey := func(secretKey string, t time.Time, region string) []byte {
+		date := sumHMAC([]byte("AWS4"+secretKey), []byte(t.Format(yyyymmdd)))
+		regionBytes := sumHMAC(date, []byte(region))
+		service := sumHMAC(regionBytes, []byte("s3"))
+		return sumHMAC(service, []byte("aws4_request"))
+	}
+
+	now := time.Now().UTC()
+	testCases := []struct {
+		secretKey string
+		t         time.Time
+		region    string
+	}{
+		{"secret-1", now, "us-east-1"},
+		{"secret-1", now, "us-west-1"},
+		{"secret-2", now, "us-east-1"},
+		{"secret-1", now.Add(-24 * time.Hour), "us-east-1"},
+		// Same day as the first case, served from the cache.
+		{"secret-1", now.Add(time.Second), "us-east-1"},
+	}
+	for i, testCase := range testCases {
+		// Twice, the second one from the cache.
+		for j := 0; j < 2; j++ {
+			signingKey := getSigningKey(testCase.secretKey, testCase.t, testCase.region)
+			expected := deriveSigningKey(testCase.secretKey, testCase.t, testCase.region)
+			if !bytes.Equal(signingKey, expected) {
+				t.Errorf("Test %d: Expected signing key %x, got %x", i+1, expected, signingKey)
+			}
+		}
+	}
+
+	// The cache is bounded.
+	for i := 0; i < 2*maxCachedSigningKeys; i++ {
+		getSigningKey(fmt.Sprintf("secret-%d", i), now, "us-east-1")
+	}
+	globalSigningKeyCache.mutex.RLock()
+	cached := len(globalSigningKeyCache.keys)
+	globalSigningKeyCache.mutex.RUnlock()
+	if cached > maxCachedSigningKeys {
+		t.Errorf("Expected at most %d cached signing keys, got %d", maxCachedSigningKeys, cached)
+	}
+}
+
+// Benchmarks verifying the signature of a request.
+func BenchmarkDoesSignatureMatch(b *testing.B) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	req, err := newTestSignedRequestV4("GET", "http://127.0.0.1:9000/bucket/object?versioning", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	hashedPayload := req.Header.Get("X-Amz-Content-Sha256")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if s3Error := doesSignatureMatch(hashedPayload, req, globalMinioDefaultRegion); s3Error != ErrNone {
+			b.Fatal(s3Error)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Environment variable designating this server as a standby when set
+// to "on". Standby servers of a distributed setup store data as any
+// other server, but only serve the S3 API requests not modifying data,
+// even while the other servers, the primaries, are under maintenance,
+// until promoted through the admin API.
+const standbyEnv = "MINIO_STANDBY"
+
+// Interval between two reloads of the bucket metadata by standby
+// servers, in case they missed some of the updates of the primaries.
+const standbySyncInterval = time.Minute
+
+// startStandbySync - periodically reloads the bucket metadata from
+// objAPI until this server is promoted.
+func startStandbySync(objAPI ObjectLayer, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if !globalServerModes.IsStandby() {
+				return
+			}
+			errorIf(initBucketMetadata(objAPI, true), "Unable to reload the bucket metadata of the standby.")
+		}
+	}()
+}
+
+// promoteStandby - promotes this server if it is a standby, once its
+// bucket metadata is reloaded. Returns false if it is not a standby.
+func promoteStandby() (bool, error) {
+	if !globalServerModes.IsStandby() {
+		return false, nil
+	}
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return false, errServerNotInitialized
+	}
+	if err := initBucketMetadata(objAPI, true); err != nil {
+		return false, err
+	}
+	globalServerModes.SetStandby(false)
+	return true, nil
+}
+
+// promoteStandbyOnPeers - promotes the standby servers of the cluster,
+// returns the addresses of the promoted servers along with the errors
+// of the servers failing, keyed by their addresses.
+func promoteStandbyOnPeers(peers adminPeers) ([]string, map[string]error) {
+	promoted := make([]bool, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			promoted[idx], errs[idx] = peer.cmdRunner.PromoteStandby()
+		}(i, peer)
+	}
+	wg.Wait()
+
+	addrs := []string{}
+	errMap := make(map[string]error)
+	for i, peer := range peers {
+		switch {
+		case errs[i] != nil:
+			errMap[peer.addr] = errs[i]
+		case promoted[i]:
+			addrs = append(addrs, peer.addr)
+		}
+	}
+	sort.Strings(addrs)
+	return addrs, errMap
+}
@@ -0,0 +1,260 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Environment variables configuring the StatsD metrics sink.
+const (
+	// StatsD or Datadog agent metrics are sent to, such as
+	// "localhost:8125".
+	statsdEndpointEnv = "MINIO_STATSD_ENDPOINT"
+
+	// Prefix of the metric names, "minio" by default.
+	statsdPrefixEnv = "MINIO_STATSD_PREFIX"
+
+	// "datadog" to tag the metrics with the API name the Datadog
+	// way, instead of naming a metric per API.
+	statsdFormatEnv = "MINIO_STATSD_FORMAT"
+)
+
+const (
+	// Prefix of the metric names by default.
+	defaultStatsdPrefix = "minio"
+
+	// Largest packet sent, fitting in an ethernet MTU.
+	maxStatsdPacketSize = 1432
+
+	// Maximum number of metrics waiting to be sent, further metrics
+	// are dropped.
+	maxPendingMetrics = 10000
+
+	// Interval between two flushes of the buffered metrics.
+	statsdFlushInterval = time.Second
+)
+
+// Global StatsD sink, nil unless enabled.
+var globalStatsd *statsdSink
+
+// statsdConfig - configuration of the StatsD metrics sink.
+type statsdConfig struct {
+	endpoint string
+	prefix   string
+	datadog  bool
+}
+
+// getStatsdConfig - returns the StatsD sink configuration set through
+// the environment, with an empty endpoint if disabled.
+func getStatsdConfig() (statsdConfig, error) {
+	config := statsdConfig{
+		endpoint: os.Getenv(statsdEndpointEnv),
+		prefix:   defaultStatsdPrefix,
+	}
+	if config.endpoint == "" {
+		return config, nil
+	}
+	if _, _, err := net.SplitHostPort(config.endpoint); err != nil {
+		return config, fmt.Errorf("Invalid %s ‘%s’, should be a host:port", statsdEndpointEnv, config.endpoint)
+	}
+	if prefix := os.Getenv(statsdPrefixEnv); prefix != "" {
+		config.prefix = prefix
+	}
+	switch format := os.Getenv(statsdFormatEnv); format {
+	case "", "statsd":
+	case "datadog":
+		config.datadog = true
+	default:
+		return config, fmt.Errorf("Invalid %s ‘%s’, should be “statsd” or “datadog”", statsdFormatEnv, format)
+	}
+	return config, nil
+}
+
+// statsdSink - sends metrics to a StatsD server over UDP, buffered
+// into packets of several metrics.
+type statsdSink struct {
+	conn    io.Writer
+	prefix  string
+	datadog bool
+	lineCh  chan string
+}
+
+// newStatsdSink - initialize a sink sending metrics to the endpoint of
+// config.
+func newStatsdSink(config statsdConfig) (*statsdSink, error) {
+	conn, err := net.Dial("udp", config.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s := newStatsdSinkWriter(conn, config)
+	go s.run()
+	return s, nil
+}
+
+// newStatsdSinkWriter - initialize a sink writing packets to conn, not
+// started yet.
+func newStatsdSinkWriter(conn io.Writer, config statsdConfig) *statsdSink {
+	return &statsdSink{
+		conn:    conn,
+		prefix:  config.prefix,
+		datadog: config.datadog,
+		lineCh:  make(chan string, maxPendingMetrics),
+	}
+}
+
+// run - sends the metrics, flushing packets when full or every
+// interval.
+func (s *statsdSink) run() {
+	ticker := time.NewTicker(statsdFlushInterval)
+	defer ticker.Stop()
+
+	var packet bytes.Buffer
+	flush := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		// Metrics are lost along with the packet on errors, as
+		// with any UDP packet.
+		s.conn.Write(packet.Bytes())
+		packet.Reset()
+	}
+	for {
+		select {
+		case line := <-s.lineCh:
+			if packet.Len() > 0 && packet.Len()+1+len(line) > maxStatsdPacketSize {
+				flush()
+			}
+			if packet.Len() > 0 {
+				packet.WriteByte('\n')
+			}
+			packet.WriteString(line)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send - queues a metric line, dropped if the sink is too slow.
+func (s *statsdSink) send(name, value, metricType, api string) {
+	var line string
+	if s.datadog {
+		line = fmt.Sprintf("%s.%s:%s|%s", s.prefix, name, value, metricType)
+		if api != "" {
+			line += "|#api:" + api
+		}
+	} else if api != "" {
+		line = fmt.Sprintf("%s.api.%s.%s:%s|%s", s.prefix, api, name, value, metricType)
+	} else {
+		line = fmt.Sprintf("%s.%s:%s|%s", s.prefix, name, value, metricType)
+	}
+	select {
+	case s.lineCh <- line:
+	default:
+	}
+}
+
+// observeRequest - sends the metrics of a request served by api.
+func (s *statsdSink) observeRequest(api string, status int, duration time.Duration, received, sent int64) {
+	if s == nil {
+		return
+	}
+	s.send("requests", "1", "c", api)
+	s.send("duration", fmt.Sprintf("%d", duration/time.Millisecond), "ms", api)
+	if status >= http.StatusBadRequest {
+		kind := "errors.client"
+		if status >= http.StatusInternalServerError {
+			kind = "errors.server"
+		}
+		s.send(kind, "1", "c", api)
+	}
+	if received > 0 {
+		s.send("bytes.received", fmt.Sprintf("%d", received), "c", "")
+	}
+	if sent > 0 {
+		s.send("bytes.sent", fmt.Sprintf("%d", sent), "c", "")
+	}
+}
+
+// metricsResponseWriter - records the status and the size of the
+// response of a request.
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	status int
+	sent   int64
+}
+
+func (w *metricsResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *metricsResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.sent += int64(n)
+	return n, err
+}
+
+// Flush - flushes the underlying response writer.
+func (w *metricsResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// metricsRequestBody - records the size of the body of a request.
+type metricsRequestBody struct {
+	io.ReadCloser
+	received int64
+}
+
+func (b *metricsRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.received += int64(n)
+	return n, err
+}
+
+// serveWithStatsd - serves r with h, sending the metrics of the
+// request as served by api if the StatsD sink is enabled.
+func serveWithStatsd(h http.Handler, w http.ResponseWriter, r *http.Request, api string) {
+	if globalStatsd == nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+	start := time.Now()
+	mw := &metricsResponseWriter{ResponseWriter: w}
+	body := &metricsRequestBody{ReadCloser: r.Body}
+	if r.Body != nil {
+		r.Body = body
+	}
+	h.ServeHTTP(mw, r)
+	if mw.status == 0 {
+		mw.status = http.StatusOK
+	}
+	globalStatsd.observeRequest(api, mw.status, time.Since(start), body.received, mw.sent)
+}
@@ -0,0 +1,125 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests parsing the StatsD sink configuration.
+func TestGetStatsdConfig(t *testing.T) {
+	defer os.Unsetenv(statsdEndpointEnv)
+	defer os.Unsetenv(statsdPrefixEnv)
+	defer os.Unsetenv(statsdFormatEnv)
+
+	testCases := []struct {
+		endpoint, prefix, format string
+		expected                 statsdConfig
+		shouldPass               bool
+	}{
+		{"", "", "", statsdConfig{prefix: defaultStatsdPrefix}, true},
+		{"localhost:8125", "", "", statsdConfig{"localhost:8125", defaultStatsdPrefix, false}, true},
+		{"localhost:8125", "s3", "datadog", statsdConfig{"localhost:8125", "s3", true}, true},
+		{"localhost", "", "", statsdConfig{}, false},
+		{"localhost:8125", "", "graphite", statsdConfig{}, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(statsdEndpointEnv, testCase.endpoint)
+		os.Setenv(statsdPrefixEnv, testCase.prefix)
+		os.Setenv(statsdFormatEnv, testCase.format)
+		config, err := getStatsdConfig()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if err == nil && config != testCase.expected {
+			t.Errorf("Test %d: Expected %+v, got %+v", i+1, testCase.expected, config)
+		}
+	}
+}
+
+// packetRecorder - records the packets written.
+type packetRecorder struct {
+	mutex   sync.Mutex
+	packets []string
+}
+
+func (p *packetRecorder) Write(b []byte) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.packets = append(p.packets, string(b))
+	return len(b), nil
+}
+
+func (p *packetRecorder) lines() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var lines []string
+	for _, packet := range p.packets {
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	return lines
+}
+
+// Tests the metrics of requests are sent.
+func TestServeWithStatsd(t *testing.T) {
+	defer func() { globalStatsd = nil }()
+
+	testCases := []struct {
+		datadog  bool
+		expected []string
+	}{
+		{false, []string{"minio.api.PutObject.requests:1|c", "minio.api.PutObject.errors.client:1|c", "minio.bytes.received:4|c", "minio.bytes.sent:5|c"}},
+		{true, []string{"minio.requests:1|c|#api:PutObject", "minio.errors.client:1|c|#api:PutObject", "minio.bytes.received:4|c", "minio.bytes.sent:5|c"}},
+	}
+	for i, testCase := range testCases {
+		recorder := &packetRecorder{}
+		globalStatsd = newStatsdSinkWriter(recorder, statsdConfig{prefix: defaultStatsdPrefix, datadog: testCase.datadog})
+		go globalStatsd.run()
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("error"))
+		})
+		r := httptest.NewRequest("PUT", "/bucket/object", bytes.NewReader([]byte("data")))
+		serveWithStatsd(handler, httptest.NewRecorder(), r, "PutObject")
+
+		for j := 0; len(recorder.lines()) < len(testCase.expected)+1; j++ {
+			if j == 100 {
+				t.Fatalf("Test %d: Expected the metrics to be sent, got %v", i+1, recorder.lines())
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		lines := strings.Join(recorder.lines(), "\n")
+		for _, expected := range testCase.expected {
+			if !strings.Contains(lines, expected) {
+				t.Errorf("Test %d: Expected metric %s, got %s", i+1, expected, lines)
+			}
+		}
+		if !strings.Contains(lines, "duration:") || !strings.Contains(lines, "|ms") {
+			t.Errorf("Test %d: Expected the duration timer, got %s", i+1, lines)
+		}
+	}
+}
@@ -16,7 +16,11 @@
 
 package cmd
 
-import "github.com/minio/minio/pkg/disk"
+import (
+	"context"
+
+	"github.com/minio/minio/pkg/disk"
+)
 
 // StorageAPI interface.
 type StorageAPI interface {
@@ -34,11 +38,12 @@ type StorageAPI interface {
 	StatVol(volume string) (vol VolInfo, err error)
 	DeleteVol(volume string) (err error)
 
-	// File operations.
+	// File operations, reads and appends of the erasure coded data
+	// are abandoned once ctx is done.
 	ListDir(volume, dirPath string) ([]string, error)
-	ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error)
+	ReadFile(ctx context.Context, volume string, path string, offset int64, buf []byte) (n int64, err error)
 	PrepareFile(volume string, path string, len int64) (err error)
-	AppendFile(volume string, path string, buf []byte) (err error)
+	AppendFile(ctx context.Context, volume string, path string, buf []byte) (err error)
 	RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error
 	StatFile(volume string, path string) (file FileInfo, err error)
 	DeleteFile(volume string, path string) (err error)
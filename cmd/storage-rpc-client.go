@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net"
 	"net/rpc"
@@ -210,10 +211,11 @@ func (n *networkStorage) PrepareFile(volume, path string, length int64) (err err
 	return nil
 }
 
-// AppendFile - append file writes buffer to a remote network path.
-func (n *networkStorage) AppendFile(volume, path string, buffer []byte) (err error) {
+// AppendFile - append file writes buffer to a remote network path,
+// stops waiting for the remote server once ctx is done.
+func (n *networkStorage) AppendFile(ctx context.Context, volume, path string, buffer []byte) (err error) {
 	reply := AuthRPCReply{}
-	if err = n.rpcClient.Call("Storage.AppendFileHandler", &AppendFileArgs{
+	if err = n.rpcClient.CallWithContext(ctx, "Storage.AppendFileHandler", &AppendFileArgs{
 		Vol:    volume,
 		Path:   path,
 		Buffer: buffer,
@@ -248,8 +250,9 @@ func (n *networkStorage) ReadAll(volume, path string) (buf []byte, err error) {
 	return buf, nil
 }
 
-// ReadFile - reads a file at remote path and fills the buffer.
-func (n *networkStorage) ReadFile(volume string, path string, offset int64, buffer []byte) (m int64, err error) {
+// ReadFile - reads a file at remote path and fills the buffer, stops
+// waiting for the remote server once ctx is done.
+func (n *networkStorage) ReadFile(ctx context.Context, volume string, path string, offset int64, buffer []byte) (m int64, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			// Recover any panic from allocation, and return error.
@@ -258,12 +261,16 @@ func (n *networkStorage) ReadFile(volume string, path string, offset int64, buff
 	}() // Do not crash the server.
 
 	var result []byte
-	err = n.rpcClient.Call("Storage.ReadFileHandler", &ReadFileArgs{
+	err = n.rpcClient.CallWithContext(ctx, "Storage.ReadFileHandler", &ReadFileArgs{
 		Vol:    volume,
 		Path:   path,
 		Offset: offset,
 		Buffer: buffer,
 	}, &result)
+	if ctxErr := ctx.Err(); ctxErr != nil && err == ctxErr {
+		// The reply may still be written to result.
+		return 0, err
+	}
 
 	// Copy results to buffer.
 	copy(buffer, result)
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -280,7 +281,7 @@ func (s *TestRPCStorageSuite) testRPCStorageFileOps(t *testing.T) {
 		if err != nil {
 			t.Error("Unable to initiate AppendFile", err)
 		}
-		err = storageDisk.AppendFile("myvol", "file1", []byte("Hello, world"))
+		err = storageDisk.AppendFile(context.Background(), "myvol", "file1", []byte("Hello, world"))
 		if err != nil {
 			t.Error("Unable to initiate AppendFile", err)
 		}
@@ -311,7 +312,7 @@ func (s *TestRPCStorageSuite) testRPCStorageFileOps(t *testing.T) {
 			t.Errorf("Expected `Hello, world`, got %s", string(buf))
 		}
 		buf1 := make([]byte, 5)
-		n, err := storageDisk.ReadFile("myvol", "file1", 4, buf1)
+		n, err := storageDisk.ReadFile(context.Background(), "myvol", "file1", 4, buf1)
 		if err != nil {
 			t.Error("Unable to initiate ReadFile", err)
 		}
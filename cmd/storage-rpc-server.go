@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"io"
 	"net/rpc"
 	"path"
@@ -148,7 +149,7 @@ func (s *storageServer) ReadFileHandler(args *ReadFileArgs, reply *[]byte) (err
 	}
 
 	var n int64
-	n, err = s.storage.ReadFile(args.Vol, args.Path, args.Offset, args.Buffer)
+	n, err = s.storage.ReadFile(context.Background(), args.Vol, args.Path, args.Offset, args.Buffer)
 	// Sending an error over the rpc layer, would cause unmarshalling to fail. In situations
 	// when we have short read i.e `io.ErrUnexpectedEOF` treat it as good condition and copy
 	// the buffer properly.
@@ -175,7 +176,7 @@ func (s *storageServer) AppendFileHandler(args *AppendFileArgs, reply *AuthRPCRe
 		return err
 	}
 
-	return s.storage.AppendFile(args.Vol, args.Path, args.Buffer)
+	return s.storage.AppendFile(context.Background(), args.Vol, args.Path, args.Buffer)
 }
 
 // DeleteFileHandler - delete file handler is rpc wrapper to delete file.
@@ -40,13 +40,7 @@ const (
 )
 
 // getChunkSignature - get chunk signature.
-func getChunkSignature(seedSignature string, date time.Time, hashedChunk string) string {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
-	// Server region.
-	region := serverConfig.GetRegion()
-
+func getChunkSignature(cred credential, seedSignature string, region string, date time.Time, hashedChunk string) string {
 	// Calculate string to sign.
 	stringToSign := signV4ChunkedAlgorithm + "\n" +
 		date.Format(iso8601Format) + "\n" +
@@ -66,14 +60,11 @@ func getChunkSignature(seedSignature string, date time.Time, hashedChunk string)
 
 // calculateSeedSignature - Calculate seed signature in accordance with
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html
-// returns signature, error otherwise if the signature mismatches or any other
-// error while parsing and validating.
-func calculateSeedSignature(r *http.Request) (signature string, date time.Time, errCode APIErrorCode) {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
+// returns signature and the region it is scoped to, error otherwise if the
+// signature mismatches or any other error while parsing and validating.
+func calculateSeedSignature(r *http.Request) (cred credential, signature string, region string, date time.Time, errCode APIErrorCode) {
 	// Server region.
-	region := serverConfig.GetRegion()
+	region = serverConfig.GetRegion()
 
 	// Copy request.
 	req := *r
@@ -84,7 +75,7 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 	// Parse signature version '4' header.
 	signV4Values, errCode := parseSignV4(v4Auth)
 	if errCode != ErrNone {
-		return "", time.Time{}, errCode
+		return cred, "", "", time.Time{}, errCode
 	}
 
 	// Payload streaming.
@@ -92,32 +83,34 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 
 	// Payload for STREAMING signature should be 'STREAMING-AWS4-HMAC-SHA256-PAYLOAD'
 	if payload != req.Header.Get("X-Amz-Content-Sha256") {
-		return "", time.Time{}, ErrContentSHA256Mismatch
+		return cred, "", "", time.Time{}, ErrContentSHA256Mismatch
 	}
 
 	// Extract all the signed headers along with its values.
 	extractedSignedHeaders, errCode := extractSignedHeaders(signV4Values.SignedHeaders, req.Header)
 	if errCode != ErrNone {
-		return "", time.Time{}, errCode
+		return cred, "", "", time.Time{}, errCode
 	}
 	// Verify if the access key id matches.
-	if signV4Values.Credential.accessKey != cred.AccessKey {
-		return "", time.Time{}, ErrInvalidAccessKeyID
+	cred, errCode = lookupCredential(signV4Values.Credential.accessKey)
+	if errCode != ErrNone {
+		return cred, "", "", time.Time{}, errCode
 	}
 
 	// Verify if region is valid.
 	sRegion := signV4Values.Credential.scope.region
 	// Should validate region, only if region is set. Some operations
 	// do not need region validated for example GetBucketLocation.
+	region = getSigningRegion(sRegion, region)
 	if !isValidRegion(sRegion, region) {
-		return "", time.Time{}, ErrInvalidRegion
+		return cred, "", "", time.Time{}, ErrInvalidRegion
 	}
 
 	// Extract date, if not present throw error.
 	var dateStr string
 	if dateStr = req.Header.Get(http.CanonicalHeaderKey("x-amz-date")); dateStr == "" {
 		if dateStr = r.Header.Get("Date"); dateStr == "" {
-			return "", time.Time{}, ErrMissingDateHeader
+			return cred, "", "", time.Time{}, ErrMissingDateHeader
 		}
 	}
 	// Parse date header.
@@ -125,7 +118,7 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 	date, err = time.Parse(iso8601Format, dateStr)
 	if err != nil {
 		errorIf(err, "Unable to parse date", dateStr)
-		return "", time.Time{}, ErrMalformedDate
+		return cred, "", "", time.Time{}, ErrMalformedDate
 	}
 
 	// Query string.
@@ -145,11 +138,11 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 
 	// Verify if signature match.
 	if newSignature != signV4Values.Signature {
-		return "", time.Time{}, ErrSignatureDoesNotMatch
+		return cred, "", "", time.Time{}, ErrSignatureDoesNotMatch
 	}
 
 	// Return caculated signature.
-	return newSignature, date, ErrNone
+	return cred, newSignature, region, date, ErrNone
 }
 
 const maxLineLength = 4 * humanize.KiByte // assumed <= bufio.defaultBufSize 4KiB
@@ -167,13 +160,15 @@ var errMalformedEncoding = errors.New("malformed chunked encoding")
 // NewChunkedReader is not needed by normal applications. The http package
 // automatically decodes chunking when reading response bodies.
 func newSignV4ChunkedReader(req *http.Request) (io.Reader, APIErrorCode) {
-	seedSignature, seedDate, errCode := calculateSeedSignature(req)
+	cred, seedSignature, seedRegion, seedDate, errCode := calculateSeedSignature(req)
 	if errCode != ErrNone {
 		return nil, errCode
 	}
 	return &s3ChunkedReader{
+		cred:              cred,
 		reader:            bufio.NewReader(req.Body),
 		seedSignature:     seedSignature,
+		seedRegion:        seedRegion,
 		seedDate:          seedDate,
 		chunkSHA256Writer: sha256.New(),
 		state:             readChunkHeader,
@@ -183,8 +178,10 @@ func newSignV4ChunkedReader(req *http.Request) (io.Reader, APIErrorCode) {
 // Represents the overall state that is required for decoding a
 // AWS Signature V4 chunked reader.
 type s3ChunkedReader struct {
+	cred              credential
 	reader            *bufio.Reader
 	seedSignature     string
+	seedRegion        string
 	seedDate          time.Time
 	state             chunkState
 	lastChunk         bool
@@ -299,7 +296,7 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 			// Calculate the hashed chunk.
 			hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
 			// Calculate the chunk signature.
-			newSignature := getChunkSignature(cr.seedSignature, cr.seedDate, hashedChunk)
+			newSignature := getChunkSignature(cr.cred, cr.seedSignature, cr.seedRegion, cr.seedDate, hashedChunk)
 			if cr.chunkSignature != newSignature {
 				// Chunk signature doesn't match we return signature does not match.
 				cr.err = errSignatureMismatch
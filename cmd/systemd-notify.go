@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// List of systemd notification states used by minio server, see
+// sd_notify(3) for details.
+const (
+	sdNotifyReady     = "READY=1"
+	sdNotifyStopping  = "STOPPING=1"
+	sdNotifyReloading = "RELOADING=1"
+	sdNotifyWatchdog  = "WATCHDOG=1"
+)
+
+// sdNotify - sends state to the service manager over the socket
+// advertised in NOTIFY_SOCKET. Nothing is sent when the server is not
+// started by systemd with `Type=notify`.
+func sdNotify(state string) error {
+	socketAddr := &net.UnixAddr{
+		Name: os.Getenv("NOTIFY_SOCKET"),
+		Net:  "unixgram",
+	}
+	if socketAddr.Name == "" {
+		return nil
+	}
+	// Abstract namespace sockets are advertised with a leading '@'.
+	if socketAddr.Name[0] == '@' {
+		socketAddr.Name = "\x00" + socketAddr.Name[1:]
+	}
+
+	conn, err := net.DialUnix(socketAddr.Net, nil, socketAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// sdNotifyStatus - sends a free-form status message to the service
+// manager, shown by `systemctl status minio`.
+func sdNotifyStatus(format string, args ...interface{}) {
+	errorIf(sdNotify("STATUS="+fmt.Sprintf(format, args...)), "Unable to notify systemd.")
+}
+
+// sdWatchdogInterval - returns the interval at which the service
+// manager expects keep-alive pings, zero if watchdog is not enabled
+// for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		// Watchdog is meant for some other process.
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// startSdWatchdog - pings the service manager watchdog at half the
+// configured interval as recommended by sd_watchdog_enabled(3), until
+// doneCh is closed.
+func startSdWatchdog(doneCh <-chan struct{}) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(sdNotify(sdNotifyWatchdog), "Unable to send watchdog ping to systemd.")
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Tests sending notifications over NOTIFY_SOCKET.
+func TestSdNotify(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip("unix domain sockets are not supported on windows")
+	}
+
+	// Not started by systemd, nothing is sent.
+	os.Unsetenv("NOTIFY_SOCKET")
+	if err := sdNotify(sdNotifyReady); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "minio-sdnotify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sockPath := filepath.Join(tmpDir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", sockPath)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	for i, state := range []string{sdNotifyReady, "STATUS=Formatting 4 disks.", sdNotifyStopping} {
+		if err = sdNotify(state); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		buf := make([]byte, 256)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if string(buf[:n]) != state {
+			t.Fatalf("Test %d: Expected %s, got %s", i+1, state, string(buf[:n]))
+		}
+	}
+}
+
+// Tests parsing watchdog environment.
+func TestSdWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	testCases := []struct {
+		usec     string
+		pid      string
+		interval time.Duration
+	}{
+		{"", "", 0},
+		{"invalid", "", 0},
+		{"-1", "", 0},
+		{"30000000", "", 30 * time.Second},
+		{"30000000", strconv.Itoa(os.Getpid()), 30 * time.Second},
+		{"30000000", strconv.Itoa(os.Getpid() + 1), 0},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("WATCHDOG_USEC", testCase.usec)
+		os.Setenv("WATCHDOG_PID", testCase.pid)
+		if interval := sdWatchdogInterval(); interval != testCase.interval {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.interval, interval)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// +build !linux
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+)
+
+// The TCP congestion control is set per socket on linux only.
+const tcpCongestionSupported = false
+
+// setTCPCongestion - not supported on this platform.
+func setTCPCongestion(listener *net.TCPListener, congestion string) error {
+	return errors.New("TCP congestion control is not supported on this platform")
+}
@@ -0,0 +1,44 @@
+// +build linux
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"syscall"
+)
+
+// Linux sets the TCP congestion control of each socket, such as bbr
+// or cubic, among those in net.ipv4.tcp_allowed_congestion_control.
+const tcpCongestionSupported = true
+
+// setTCPCongestion - sets the TCP congestion control of listener,
+// inherited by the connections it accepts.
+func setTCPCongestion(listener *net.TCPListener, congestion string) error {
+	rawConn, err := listener.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err = rawConn.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptString(int(fd), syscall.IPPROTO_TCP, syscall.TCP_CONGESTION, congestion)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/hmac"
 	crand "crypto/rand"
@@ -488,6 +489,31 @@ func resetGlobalEventnotify() {
 	globalEventNotifier = nil
 }
 
+// reset the read-only and maintenance modes to normal.
+func resetGlobalServerModes() {
+	globalServerModes = newServerModes()
+}
+
+// reset the users and policies of the IAM subsystem.
+func resetGlobalIAMSys() {
+	globalIAMSys = &iamSys{config: newIAMConfig()}
+}
+
+// reset the last use of the access keys.
+func resetGlobalAccessKeyUsage() {
+	globalAccessKeyUsage = &accessKeyTracker{keys: make(map[string]accessKeyUsage)}
+}
+
+// reset the requests being served.
+func resetGlobalInflightRequests() {
+	globalInflightRequests = &inflightRequests{requests: make(map[*inflightRequest]struct{})}
+}
+
+// reset the limits on the creation of buckets.
+func resetGlobalBucketLimits() {
+	globalBucketLimits = bucketLimits{}
+}
+
 // Resets all the globals used modified in tests.
 // Resetting ensures that the changes made to globals by one test doesn't affect others.
 func resetTestGlobals() {
@@ -501,6 +527,16 @@ func resetTestGlobals() {
 	resetGlobalNSLock()
 	// Reset global event notifier.
 	resetGlobalEventnotify()
+	// Reset read-only and maintenance modes.
+	resetGlobalServerModes()
+	// Reset IAM users and policies.
+	resetGlobalIAMSys()
+	// Reset the last use of the access keys.
+	resetGlobalAccessKeyUsage()
+	// Reset the requests being served.
+	resetGlobalInflightRequests()
+	// Reset the limits on the creation of buckets.
+	resetGlobalBucketLimits()
 }
 
 // Configure the server for the test run.
@@ -932,16 +968,20 @@ func preSignV2(req *http.Request, accessKeyID, secretAccessKey string, expires i
 	// url.RawPath will be valid if path has any encoded characters, if not it will
 	// be empty - in which case we need to consider url.Path (bug in net/http?)
 	encodedResource := req.URL.RawPath
-	encodedQuery := req.URL.RawQuery
 	if encodedResource == "" {
 		splits := strings.Split(req.URL.Path, "?")
 		if len(splits) > 0 {
 			encodedResource = splits[0]
 		}
 	}
+	// The values of the sub-resources are signed unescaped.
+	unescapedQuery, err := unescapeQueryV2(req.URL.RawQuery)
+	if err != nil {
+		return err
+	}
 
 	// Get presigned string to sign.
-	stringToSign := presignV2STS(req.Method, encodedResource, encodedQuery, req.Header, expiresStr)
+	stringToSign := presignV2STS(req.Method, encodedResource, unescapedQuery, req.Header, expiresStr)
 	hm := hmac.New(sha1.New, []byte(secretAccessKey))
 	hm.Write([]byte(stringToSign))
 
@@ -983,10 +1023,14 @@ func signRequestV2(req *http.Request, accessKey, secretKey string) error {
 			encodedResource = getURLEncodedName(splits[0])
 		}
 	}
-	encodedQuery := req.URL.Query().Encode()
+	// The values of the sub-resources are signed unescaped.
+	unescapedQuery, err := unescapeQueryV2(req.URL.RawQuery)
+	if err != nil {
+		return err
+	}
 
 	// Calculate HMAC for secretAccessKey.
-	stringToSign := signV2STS(req.Method, encodedResource, encodedQuery, req.Header)
+	stringToSign := signV2STS(req.Method, encodedResource, unescapedQuery, req.Header)
 	hm := hmac.New(sha1.New, []byte(secretKey))
 	hm.Write([]byte(stringToSign))
 
@@ -1497,6 +1541,61 @@ func getDeleteMultipleObjectsURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for copying multiple objects into the bucket.
+func getCopyMultipleObjectsURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("copy", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for renaming a prefix.
+func getRenamePrefixURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("rename", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for the integrity configuration of the bucket.
+func getBucketIntegrityURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("integrity", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for the bandwidth configuration of the bucket.
+func getBucketBandwidthURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("bandwidth", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for the logging configuration of the bucket.
+func getBucketLoggingURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("logging", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for the transform configuration of the bucket.
+func getBucketTransformURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("transform", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for the secure delete configuration of the bucket.
+func getBucketSecureDeleteURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("secure-delete", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for querying the objects of the bucket by metadata.
+func getMetadataQueryURL(endPoint, bucketName string, queryValue url.Values) string {
+	queryValue.Set("metadata-query", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL For fetching location of the bucket.
 func getBucketLocationURL(endPoint, bucketName string) string {
 	queryValue := url.Values{}
@@ -1586,6 +1685,13 @@ func getCompleteMultipartUploadURL(endPoint, bucketName, objectName, uploadID st
 	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
 }
 
+// return URL for composing an object.
+func getComposeObjectURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("compose", "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
 // return URL for put bucket notification.
 func getPutBucketNotificationURL(endPoint, bucketName string) string {
 	return getGetBucketNotificationURL(endPoint, bucketName)
@@ -1732,7 +1838,7 @@ func initAPIHandlerTest(obj ObjectLayer, endpoints []string) (bucketName string,
 	bucketName = getRandomBucketName()
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, return err.
 		return "", nil, err
@@ -2055,6 +2161,9 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "CopyObject":
 			// Register Copy Object  handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectHandler)
+		case "ComposeObject":
+			// Register Compose Object handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.ComposeObjectHandler).Queries("compose", "")
 		case "PutBucketPolicy":
 			// Register PutBucket Policy handler.
 			bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
@@ -2067,9 +2176,54 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "GetBucketLocation":
 			// Register GetBucketLocation handler.
 			bucket.Methods("GET").HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
+		case "PutBucket":
+			// Register PutBucket handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
+		case "DeleteBucket":
+			// Register DeleteBucket handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
 		case "HeadBucket":
 			// Register HeadBucket handler.
 			bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
+		case "PutBucketIntegrity":
+			// Register PutBucketIntegrity handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketIntegrityHandler).Queries("integrity", "")
+		case "GetBucketIntegrity":
+			// Register GetBucketIntegrity handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketIntegrityHandler).Queries("integrity", "")
+		case "PutBucketBandwidth":
+			// Register PutBucketBandwidth handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketBandwidthHandler).Queries("bandwidth", "")
+		case "GetBucketBandwidth":
+			// Register GetBucketBandwidth handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketBandwidthHandler).Queries("bandwidth", "")
+		case "PutBucketLogging":
+			// Register PutBucketLogging handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLoggingHandler).Queries("logging", "")
+		case "GetBucketLogging":
+			// Register GetBucketLogging handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLoggingHandler).Queries("logging", "")
+		case "PutBucketSecureDelete":
+			// Register PutBucketSecureDelete handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketSecureDeleteHandler).Queries("secure-delete", "")
+		case "MetadataQuery":
+			// Register MetadataQuery handler.
+			bucket.Methods("GET").HandlerFunc(api.MetadataQueryHandler).Queries("metadata-query", "")
+		case "GetBucketSecureDelete":
+			// Register GetBucketSecureDelete handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketSecureDeleteHandler).Queries("secure-delete", "")
+		case "PutBucketTransform":
+			// Register PutBucketTransform handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketTransformHandler).Queries("transform", "")
+		case "GetBucketTransform":
+			// Register GetBucketTransform handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketTransformHandler).Queries("transform", "")
+		case "RenamePrefix":
+			// Register RenamePrefix handler.
+			bucket.Methods("POST").HandlerFunc(api.RenamePrefixHandler).Queries("rename", "")
+		case "CopyMultipleObjects":
+			// Register CopyMultipleObjects handler.
+			bucket.Methods("POST").HandlerFunc(api.CopyMultipleObjectsHandler).Queries("copy", "")
 		case "DeleteMultipleObjects":
 			// Register DeleteMultipleObjects handler.
 			bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler).Queries("delete", "")
@@ -0,0 +1,243 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Environment variable setting the Zipkin compatible collector spans
+// are reported to, such as "http://jaeger:9411/api/v2/spans".
+const tracingEndpointEnv = "MINIO_TRACING_ENDPOINT"
+
+const (
+	// Service name of the reported spans.
+	tracingServiceName = "minio"
+
+	// Maximum number of spans waiting to be reported, further spans
+	// are dropped.
+	maxPendingSpans = 10000
+
+	// Spans are reported by batches of this many spans at most.
+	spansBatchSize = 100
+
+	// Interval between two reports of the batched spans.
+	spansReportInterval = time.Second
+)
+
+// B3 propagation headers of the trace a request is part of.
+const (
+	b3TraceIDHeader = "X-B3-TraceId"
+	b3SpanIDHeader  = "X-B3-SpanId"
+)
+
+// Global tracer, nil unless tracing is enabled.
+var globalTracer *tracer
+
+// getTracingEndpoint - returns the collector endpoint configured
+// through the environment, empty if tracing is disabled.
+func getTracingEndpoint() (string, error) {
+	value := os.Getenv(tracingEndpointEnv)
+	if value == "" {
+		return "", nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("Invalid %s ‘%s’, should be an http or https URL", tracingEndpointEnv, value)
+	}
+	return value, nil
+}
+
+// zipkinEndpoint - service a span is emitted by, in the Zipkin format.
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// zipkinSpan - span in the Zipkin v2 format, accepted by Zipkin and
+// Jaeger collectors. Times are in microseconds.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+// tracer - reports finished spans to a collector in the background.
+type tracer struct {
+	endpoint string
+	client   *http.Client
+	spansCh  chan zipkinSpan
+}
+
+// newTracer - initialize a tracer reporting spans to endpoint.
+func newTracer(endpoint string) *tracer {
+	t := &tracer{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		spansCh:  make(chan zipkinSpan, maxPendingSpans),
+	}
+	go t.report()
+	return t
+}
+
+// report - posts the finished spans to the collector by batches.
+func (t *tracer) report() {
+	ticker := time.NewTicker(spansReportInterval)
+	defer ticker.Stop()
+
+	var batch []zipkinSpan
+	for {
+		select {
+		case span := <-t.spansCh:
+			batch = append(batch, span)
+			if len(batch) < spansBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		errorIf(t.post(batch), "Unable to report %d spans to %s.", len(batch), t.endpoint)
+		batch = nil
+	}
+}
+
+// post - posts spans to the collector.
+func (t *tracer) post(spans []zipkinSpan) error {
+	buf, err := json.Marshal(spans)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector replied %s", resp.Status)
+	}
+	return nil
+}
+
+// traceSpan - span being timed, reported when finished.
+type traceSpan struct {
+	tracer *tracer
+	span   zipkinSpan
+	start  time.Time
+}
+
+// Key of the context value holding the current span.
+type traceSpanKey struct{}
+
+// newSpanID - returns a random 64 bit span or trace ID, hex encoded.
+func newSpanID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// newTraceSpan - starts a span of the trace traceID, child of the span
+// parentID if any.
+func newTraceSpan(traceID, parentID, name string) *traceSpan {
+	return &traceSpan{
+		tracer: globalTracer,
+		span: zipkinSpan{
+			TraceID:       traceID,
+			ID:            newSpanID(),
+			ParentID:      parentID,
+			Name:          name,
+			LocalEndpoint: zipkinEndpoint{ServiceName: tracingServiceName},
+			Tags:          make(map[string]string),
+		},
+		start: time.Now(),
+	}
+}
+
+// startSpan - starts a span, child of the span of ctx if any, returns
+// ctx carrying it. Returns a nil span if tracing is disabled, spans
+// being safe to use while nil.
+func startSpan(ctx context.Context, name string) (context.Context, *traceSpan) {
+	if globalTracer == nil {
+		return ctx, nil
+	}
+	var span *traceSpan
+	if parent, ok := ctx.Value(traceSpanKey{}).(*traceSpan); ok {
+		span = newTraceSpan(parent.span.TraceID, parent.span.ID, name)
+	} else {
+		span = newTraceSpan(newSpanID(), "", name)
+	}
+	return context.WithValue(ctx, traceSpanKey{}, span), span
+}
+
+// startRequestSpan - starts the span serving r, child of the span of
+// the client given by the B3 propagation headers if any, returns r
+// carrying it.
+func startRequestSpan(r *http.Request, name string) (*http.Request, *traceSpan) {
+	if globalTracer == nil {
+		return r, nil
+	}
+	traceID, parentID := r.Header.Get(b3TraceIDHeader), r.Header.Get(b3SpanIDHeader)
+	if traceID == "" {
+		traceID, parentID = newSpanID(), ""
+	}
+	span := newTraceSpan(traceID, parentID, name)
+	span.span.Kind = "SERVER"
+	span.SetTag("http.method", r.Method)
+	span.SetTag("http.path", r.URL.Path)
+	return r.WithContext(context.WithValue(r.Context(), traceSpanKey{}, span)), span
+}
+
+// SetTag - tags the span with key and value.
+func (s *traceSpan) SetTag(key, value string) {
+	if s == nil {
+		return
+	}
+	s.span.Tags[key] = value
+}
+
+// Finish - ends the span, tagged with err if not nil, and queues it
+// to be reported.
+func (s *traceSpan) Finish(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.SetTag("error", errorCause(err).Error())
+	}
+	s.span.Timestamp = s.start.UnixNano() / int64(time.Microsecond)
+	s.span.Duration = int64(time.Since(s.start) / time.Microsecond)
+	select {
+	case s.tracer.spansCh <- s.span:
+	default:
+		// Collector too slow, the span is dropped.
+	}
+}
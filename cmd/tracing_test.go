@@ -0,0 +1,134 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the tracing collector endpoint.
+func TestGetTracingEndpoint(t *testing.T) {
+	defer os.Unsetenv(tracingEndpointEnv)
+
+	testCases := []struct {
+		value      string
+		shouldPass bool
+	}{
+		{"", true},
+		{"http://jaeger:9411/api/v2/spans", true},
+		{"https://zipkin.example.com/api/v2/spans", true},
+		{"jaeger:9411", false},
+		{"ftp://jaeger/spans", false},
+		{"http://", false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(tracingEndpointEnv, testCase.value)
+		endpoint, err := getTracingEndpoint()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if err == nil && endpoint != testCase.value {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.value, endpoint)
+		}
+	}
+}
+
+// Tests spans are not created while tracing is disabled.
+func TestStartSpanDisabled(t *testing.T) {
+	ctx, span := startSpan(context.Background(), "disabled")
+	if span != nil || ctx != context.Background() {
+		t.Fatal("Expected no span while tracing is disabled")
+	}
+	// Nil spans are safe to use.
+	span.SetTag("key", "value")
+	span.Finish(errFaultyDisk)
+	if objAPI := traceObjectLayer(nil); objAPI != nil {
+		t.Fatal("Expected the object layer not to be traced while tracing is disabled")
+	}
+}
+
+// Tests spans are reported to the collector, with their parents.
+func TestTracing(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	spansCh := make(chan zipkinSpan, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var spans []zipkinSpan
+		if err := json.NewDecoder(r.Body).Decode(&spans); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, span := range spans {
+			spansCh <- span
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	defer func() { globalTracer = nil }()
+	globalTracer = newTracer(collector.URL)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+	obj = traceObjectLayer(obj)
+
+	// Request of a client propagating its trace.
+	r := httptest.NewRequest("PUT", "/bucket", nil)
+	r.Header.Set(b3TraceIDHeader, "463ac35c9f6413ad")
+	r.Header.Set(b3SpanIDHeader, "a2fb4a1d1a96d312")
+	r, requestSpan := startRequestSpan(r, "PutBucket")
+	if err = obj.MakeBucket(r.Context(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	requestSpan.Finish(nil)
+
+	spans := make(map[string]zipkinSpan)
+	for len(spans) < 2 {
+		select {
+		case span := <-spansCh:
+			// Skip the spans of the locks taken while initializing.
+			if span.Name != "lock" {
+				spans[span.Name] = span
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the spans to be reported, got %v", spans)
+		}
+	}
+	request, call := spans["PutBucket"], spans["ObjectLayer.MakeBucket"]
+	if request.TraceID != "463ac35c9f6413ad" || request.ParentID != "a2fb4a1d1a96d312" || request.Kind != "SERVER" {
+		t.Fatalf("Expected the request span to be part of the client trace, got %+v", request)
+	}
+	if call.TraceID != request.TraceID || call.ParentID != request.ID || call.Tags["bucket"] != "bucket" {
+		t.Fatalf("Expected the call span to be a child of the request span, got %+v", call)
+	}
+	if call.Duration > request.Duration {
+		t.Fatalf("Expected the call to last less than the request, got %d > %d", call.Duration, request.Duration)
+	}
+}
@@ -27,6 +27,10 @@ const (
 	globalLookupTimeout = time.Minute * 30 // 30minutes.
 )
 
+// Maximum number of treeWalk go-routines kept in a pool, each of them
+// holding up to maxObjectList buffered results.
+const globalMaxTreeWalks = 1000
+
 // listParams - list object params used for list object map
 type listParams struct {
 	bucket    string
@@ -47,6 +51,7 @@ type treeWalk struct {
 	resultCh   chan treeWalkResult
 	endWalkCh  chan struct{}   // To signal when treeWalk go-routine should end.
 	endTimerCh chan<- struct{} // To signal when timer go-routine should end.
+	added      time.Time       // When the treeWalk was added to the pool.
 }
 
 // treeWalkPool - pool of treeWalk go routines.
@@ -54,22 +59,63 @@ type treeWalk struct {
 // doing a Release() or if the concerned timer goes off.
 // treeWalkPool's purpose is to maintain active treeWalk go-routines in a map so that
 // it can be looked up across related list calls.
+// At most maxWalks treeWalks are kept, the oldest one is ended to make
+// room for a new one.
 type treeWalkPool struct {
-	pool    map[listParams][]treeWalk
-	timeOut time.Duration
-	lock    *sync.Mutex
+	pool     map[listParams][]treeWalk
+	timeOut  time.Duration
+	maxWalks int
+	lock     *sync.Mutex
 }
 
 // newTreeWalkPool - initialize new tree walk pool.
 func newTreeWalkPool(timeout time.Duration) *treeWalkPool {
 	tPool := &treeWalkPool{
-		pool:    make(map[listParams][]treeWalk),
-		timeOut: timeout,
-		lock:    &sync.Mutex{},
+		pool:     make(map[listParams][]treeWalk),
+		timeOut:  timeout,
+		maxWalks: globalMaxTreeWalks,
+		lock:     &sync.Mutex{},
 	}
 	return tPool
 }
 
+// evictOldest - removes the oldest treeWalk from the pool and ends
+// it, should be called with the pool lock held.
+func (t treeWalkPool) evictOldest() {
+	var oldestParams listParams
+	oldestIndex := -1
+	var oldest treeWalk
+	for params, walks := range t.pool {
+		for i, walk := range walks {
+			if oldestIndex == -1 || walk.added.Before(oldest.added) {
+				oldestParams, oldestIndex, oldest = params, i, walk
+			}
+		}
+	}
+	if oldestIndex == -1 {
+		return
+	}
+	walks := t.pool[oldestParams]
+	walks = append(walks[:oldestIndex], walks[oldestIndex+1:]...)
+	if len(walks) > 0 {
+		t.pool[oldestParams] = walks
+	} else {
+		delete(t.pool, oldestParams)
+	}
+	// End the timer go-routine and the treeWalk go-routine.
+	oldest.endTimerCh <- struct{}{}
+	close(oldest.endWalkCh)
+}
+
+// count - returns the number of treeWalks in the pool, should be
+// called with the pool lock held.
+func (t treeWalkPool) count() (walks int) {
+	for _, paramsWalks := range t.pool {
+		walks += len(paramsWalks)
+	}
+	return walks
+}
+
 // Release - selects a treeWalk from the pool based on the input
 // listParams, removes it from the pool, and returns the treeWalkResult
 // channel.
@@ -104,16 +150,23 @@ func (t treeWalkPool) Release(params listParams) (resultCh chan treeWalkResult,
 // 2) Relase() signals the timer go-routine to end on endTimerCh.
 //    During listing the timer should not timeout and end the treeWalk go-routine, hence the
 //    timer go-routine should be ended.
+// 3) the pool is full and the treeWalk is the oldest one in the pool.
 func (t treeWalkPool) Set(params listParams, resultCh chan treeWalkResult, endWalkCh chan struct{}) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	// Make room for the new treeWalk.
+	for t.maxWalks > 0 && t.count() >= t.maxWalks {
+		t.evictOldest()
+	}
+
 	// Should be a buffered channel so that Release() never blocks.
 	endTimerCh := make(chan struct{}, 1)
 	walkInfo := treeWalk{
 		resultCh:   resultCh,
 		endWalkCh:  endWalkCh,
 		endTimerCh: endTimerCh,
+		added:      time.Now(),
 	}
 	// Append new walk info.
 	t.pool[params] = append(t.pool[params], walkInfo)
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -101,3 +102,37 @@ func TestManyWalksSameParam(t *testing.T) {
 	}
 
 }
+
+// Test if the oldest tree walkers are ended when the pool is full.
+func TestTreeWalkPoolMaxWalks(t *testing.T) {
+	tw := newTreeWalkPool(time.Minute)
+	tw.maxWalks = 2
+
+	var endWalkChs []chan struct{}
+	for i := 0; i < 3; i++ {
+		params := listParams{bucket: "test-bucket", marker: fmt.Sprintf("marker-%d", i)}
+		endWalkCh := make(chan struct{})
+		tw.Set(params, make(chan treeWalkResult), endWalkCh)
+		endWalkChs = append(endWalkChs, endWalkCh)
+	}
+
+	// The first walk was ended to make room for the third one.
+	select {
+	case <-endWalkChs[0]:
+	default:
+		t.Fatal("Oldest treeWalk go-routine was not ended")
+	}
+	if resultCh, _ := tw.Release(listParams{bucket: "test-bucket", marker: "marker-0"}); resultCh != nil {
+		t.Fatal("Oldest treeWalk was not removed from the pool")
+	}
+	for i := 1; i < 3; i++ {
+		select {
+		case <-endWalkChs[i]:
+			t.Fatalf("treeWalk go-routine %d was ended", i)
+		default:
+		}
+		if resultCh, _ := tw.Release(listParams{bucket: "test-bucket", marker: fmt.Sprintf("marker-%d", i)}); resultCh == nil {
+			t.Fatalf("treeWalk %d was not found in the pool", i)
+		}
+	}
+}
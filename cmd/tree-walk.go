@@ -160,7 +160,10 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 			markerBase = markerSplit[1]
 		}
 	}
+	// Directory listings are bounded across all the tree walks.
+	globalListingLimiter.Acquire(bucket)
 	entries, delayIsLeaf, err := listDir(bucket, prefixDir, entryPrefixMatch)
+	globalListingLimiter.Release(bucket)
 	if err != nil {
 		select {
 		case <-endWalkCh:
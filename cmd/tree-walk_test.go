@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"reflect"
@@ -117,7 +118,7 @@ func createNamespace(disk StorageAPI, volume string, files []string) error {
 
 	// Create files.
 	for _, file := range files {
-		err = disk.AppendFile(volume, file, []byte{})
+		err = disk.AppendFile(context.Background(), volume, file, []byte{})
 		if err != nil {
 			return err
 		}
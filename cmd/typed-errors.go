@@ -45,5 +45,9 @@ var errServerNotInitialized = errors.New("Server not initialized, please try aga
 // errServerVersionMismatch - server versions do not match.
 var errServerVersionMismatch = errors.New("Server versions do not match")
 
+// errRPCUnsupported - the remote server does not serve the RPC, as
+// servers of older releases during a rolling upgrade.
+var errRPCUnsupported = errors.New("RPC not supported by the remote server, which may run an older release")
+
 // errServerTimeMismatch - server times are too far apart.
 var errServerTimeMismatch = errors.New("Server times are too far apart")
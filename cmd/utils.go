@@ -17,10 +17,12 @@
 package cmd
 
 import (
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -117,6 +119,46 @@ var portMap = map[string]string{
 // return true if the string includes a port.
 func hasPort(s string) bool { return strings.LastIndex(s, ":") > strings.LastIndex(s, "]") }
 
+// splitHostPort is net.SplitHostPort, accepting as well addresses
+// without a port such as "host", "[ipv6::address]" or
+// "[ipv6::address%zone]", for which the returned port is empty.
+func splitHostPort(hostport string) (host, port string, err error) {
+	if hasPort(hostport) {
+		return net.SplitHostPort(hostport)
+	}
+	if strings.HasPrefix(hostport, "[") {
+		if !strings.HasSuffix(hostport, "]") {
+			return "", "", &net.AddrError{Err: "missing ']' in address", Addr: hostport}
+		}
+		return hostport[1 : len(hostport)-1], "", nil
+	}
+	return hostport, "", nil
+}
+
+// hostIP returns the IP of a host which is an IP literal, with an
+// optional IPv6 zone such as "fe80::1%eth0", nil otherwise.
+func hostIP(host string) net.IP {
+	if i := strings.LastIndex(host, "%"); i >= 0 {
+		host = host[:i]
+	}
+	return net.ParseIP(host)
+}
+
+// canonicalHost returns the canonical form of a host which is an IP
+// literal, for example "::1" for "0:0::1", keeping its IPv6 zone. Host
+// names are returned as is.
+func canonicalHost(host string) string {
+	ip := hostIP(host)
+	if ip == nil {
+		return host
+	}
+	zone := ""
+	if i := strings.LastIndex(host, "%"); i >= 0 {
+		zone = host[i:]
+	}
+	return ip.String() + zone
+}
+
 // canonicalAddr returns url.Host but always with a ":port" suffix
 func canonicalAddr(u *url.URL) string {
 	addr := u.Host
@@ -161,9 +203,17 @@ func xmlDecoder(body io.Reader, v interface{}, size int64) error {
 	return d.Decode(v)
 }
 
-// checkValidMD5 - verify if valid md5, returns md5 in bytes.
-func checkValidMD5(md5 string) ([]byte, error) {
-	return base64.StdEncoding.DecodeString(strings.TrimSpace(md5))
+// checkValidMD5 - verify if valid base64 encoded md5, returns md5 in bytes,
+// empty if md5Base64 is empty.
+func checkValidMD5(md5Base64 string) ([]byte, error) {
+	md5Bytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(md5Base64))
+	if err != nil {
+		return nil, err
+	}
+	if len(md5Bytes) != 0 && len(md5Bytes) != md5.Size {
+		return nil, errInvalidArgument
+	}
+	return md5Bytes, nil
 }
 
 /// http://docs.aws.amazon.com/AmazonS3/latest/dev/UploadingObjects.html
@@ -178,6 +178,34 @@ func TestMaxPartID(t *testing.T) {
 	}
 }
 
+// Tests validating base64 encoded md5 sums.
+func TestCheckValidMD5(t *testing.T) {
+	testCases := []struct {
+		md5Base64  string
+		size       int
+		shouldPass bool
+	}{
+		// Test - 1 no md5 sum.
+		{"", 0, true},
+		// Test - 2 md5 sum of "hello".
+		{"XUFAKrxLKna5cZ2REBfFkg==", 16, true},
+		// Test - 3 not base64 encoded.
+		{"badmd5", 0, false},
+		// Test - 4 base64 encoded but not of md5 sum size.
+		{"aGVsbG8=", 0, false},
+	}
+
+	for i, testCase := range testCases {
+		md5Bytes, err := checkValidMD5(testCase.md5Base64)
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %t, got %v", i+1, testCase.shouldPass, err)
+		}
+		if len(md5Bytes) != testCase.size {
+			t.Errorf("Test %d: Expected %d bytes, got %d", i+1, testCase.size, len(md5Bytes))
+		}
+	}
+}
+
 // Tests extracting bucket and objectname from various types of URL paths.
 func TestURL2BucketObjectName(t *testing.T) {
 	testCases := []struct {
@@ -273,6 +301,55 @@ func TestStartProfiler(t *testing.T) {
 	}
 }
 
+// Tests splitting addresses with optional ports, including IPv6 ones.
+func TestSplitHostPort(t *testing.T) {
+	testCases := []struct {
+		hostport string
+		host     string
+		port     string
+		success  bool
+	}{
+		{"localhost", "localhost", "", true},
+		{"localhost:9000", "localhost", "9000", true},
+		{"1.2.3.4:9000", "1.2.3.4", "9000", true},
+		{"[::1]", "::1", "", true},
+		{"[::1]:9000", "::1", "9000", true},
+		{"[fe80::1%eth0]:9000", "fe80::1%eth0", "9000", true},
+		{"[::1", "", "", false},
+		{"::1", "", "", false},
+	}
+	for i, testCase := range testCases {
+		host, port, err := splitHostPort(testCase.hostport)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: Expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if host != testCase.host || port != testCase.port {
+			t.Errorf("Test %d: Expected %q and %q, got %q and %q", i+1, testCase.host, testCase.port, host, port)
+		}
+	}
+}
+
+// Tests the canonical form of IP literal hosts.
+func TestCanonicalHost(t *testing.T) {
+	testCases := []struct {
+		host      string
+		canonical string
+	}{
+		{"localhost", "localhost"},
+		{"1.2.3.4", "1.2.3.4"},
+		{"0:0:0:0:0:0:0:1", "::1"},
+		{"FE80:0::1%eth0", "fe80::1%eth0"},
+	}
+	for i, testCase := range testCases {
+		if canonical := canonicalHost(testCase.host); canonical != testCase.canonical {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.canonical, canonical)
+		}
+	}
+	if hostIP("fe80::1%eth0") == nil || hostIP("localhost") != nil {
+		t.Error("Unexpected IP of hosts")
+	}
+}
+
 // Tests fetch local address.
 func TestLocalAddress(t *testing.T) {
 	if runtime.GOOS == globalWindowsOSName {
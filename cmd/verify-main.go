@@ -0,0 +1,461 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var verifyFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Repair inconsistencies which are safe to fix offline, e.g remove orphaned metadata and files.",
+	},
+	cli.BoolFlag{
+		Name:  "deep",
+		Usage: "Additionally verify object data against the checksums in metadata, reads all the data.",
+	},
+}
+
+var verifyCmd = cli.Command{
+	Name:   "verify",
+	Usage:  "Verify consistency of the backend with the server stopped.",
+	Flags:  append(verifyFlags, globalFlags...),
+	Action: mainVerify,
+	CustomHelpTemplate: `NAME:
+  minio {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio {{.Name}} [FLAGS] PATH [PATH...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Walks the on-disk format of the local paths exported by a stopped minio server, a single
+  path is verified as FS backend, multiple paths as XL backend. Reports missing, corrupted
+  and orphaned metadata and data files, exits with non-zero status if any issues are left
+  unrepaired. Objects missing on some of the XL disks are only reported, they are healed
+  by a running server. Never run this command while minio server is using the paths.
+
+EXAMPLES:
+  1. Verify FS backend exported from "/home/shared".
+      $ minio {{.Name}} /home/shared
+
+  2. Verify all data and repair XL backend on a 4 disks server.
+      $ minio {{.Name}} --deep --repair /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/
+
+`,
+}
+
+// verifyIssue - an inconsistency found by `minio verify`.
+type verifyIssue struct {
+	disk     string // Path of the disk the issue was found on.
+	path     string // Path of the affected file relative to the disk.
+	problem  string // Description of the inconsistency.
+	repaired bool   // True if the inconsistency was repaired.
+}
+
+func (i verifyIssue) String() string {
+	s := fmt.Sprintf("%s: %s", path.Join(i.disk, i.path), i.problem)
+	if i.repaired {
+		s += " (repaired)"
+	}
+	return s
+}
+
+// backendVerifier - verifies consistency of FS or XL backend on
+// local disks, collecting all the issues found.
+type backendVerifier struct {
+	repair bool // Repair issues when possible.
+	deep   bool // Verify data checksums.
+	issues []verifyIssue
+}
+
+// report - records an issue found on disk at path, repairFn if not nil
+// fixes the issue and is called only when repair is requested.
+func (v *backendVerifier) report(disk StorageAPI, volume, filePath, problem string, repairFn func() error) {
+	issue := verifyIssue{
+		disk:    disk.String(),
+		path:    path.Join(volume, filePath),
+		problem: problem,
+	}
+	if v.repair && repairFn != nil {
+		if err := repairFn(); err != nil {
+			issue.problem += fmt.Sprintf(", unable to repair: %s", err)
+		} else {
+			issue.repaired = true
+		}
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// unrepaired - returns the number of issues left unrepaired.
+func (v *backendVerifier) unrepaired() (n int) {
+	for _, issue := range v.issues {
+		if !issue.repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// verifyFS - verifies `format.json` and `fs.json` of all the objects
+// exported from fsPath.
+func (v *backendVerifier) verifyFS(fsPath string) error {
+	disk, err := newPosix(fsPath)
+	if err != nil {
+		return err
+	}
+
+	buf, err := disk.ReadAll(minioMetaBucket, fsFormatJSONFile)
+	switch err {
+	case nil:
+		format := &formatConfigV1{}
+		if err = json.Unmarshal(buf, format); err != nil || format.Format != "fs" {
+			v.report(disk, minioMetaBucket, fsFormatJSONFile, "corrupted backend format", func() error {
+				return saveFormatFS(pathJoin(fsPath, minioMetaBucket, fsFormatJSONFile), newFSFormatV1())
+			})
+		}
+	case errFileNotFound, errVolumeNotFound:
+		// Fresh or never started backend, `format.json` is
+		// created by the server on startup.
+	default:
+		return err
+	}
+
+	vols, err := disk.ListVols()
+	if err != nil {
+		return err
+	}
+	buckets := make(map[string]bool)
+	for _, vol := range vols {
+		if !isMinioMetaBucketName(vol.Name) {
+			buckets[vol.Name] = true
+		}
+	}
+
+	metaBuckets, err := disk.ListDir(minioMetaBucket, bucketMetaPrefix)
+	if err != nil && err != errFileNotFound && err != errVolumeNotFound {
+		return err
+	}
+	for _, entry := range metaBuckets {
+		if !strings.HasSuffix(entry, slashSeparator) {
+			continue
+		}
+		bucket := strings.TrimSuffix(entry, slashSeparator)
+		bucketMetaDir := path.Join(bucketMetaPrefix, bucket)
+		if !buckets[bucket] {
+			v.report(disk, minioMetaBucket, bucketMetaDir, "metadata of non existent bucket", func() error {
+				return fsRemoveAll(pathJoin(fsPath, minioMetaBucket, bucketMetaDir))
+			})
+			continue
+		}
+		if err = v.walkFSMeta(disk, bucket, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkFSMeta - recursively verifies all `fs.json` of bucket under prefix.
+func (v *backendVerifier) walkFSMeta(disk StorageAPI, bucket, prefix string) error {
+	entries, err := disk.ListDir(minioMetaBucket, path.Join(bucketMetaPrefix, bucket, prefix))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry == fsMetaJSONFile {
+			v.verifyFSObject(disk, bucket, prefix)
+			continue
+		}
+		if strings.HasSuffix(entry, slashSeparator) {
+			if err = v.walkFSMeta(disk, bucket, path.Join(prefix, entry)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyFSObject - verifies `fs.json` of a single object against its data.
+func (v *backendVerifier) verifyFSObject(disk StorageAPI, bucket, object string) {
+	fsMetaPath := path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	removeFSMeta := func() error {
+		return disk.DeleteFile(minioMetaBucket, fsMetaPath)
+	}
+
+	if _, err := disk.StatFile(bucket, object); err != nil {
+		v.report(disk, minioMetaBucket, fsMetaPath, "metadata of non existent object", removeFSMeta)
+		return
+	}
+
+	buf, err := disk.ReadAll(minioMetaBucket, fsMetaPath)
+	if err != nil {
+		v.report(disk, minioMetaBucket, fsMetaPath, fmt.Sprintf("unreadable metadata, %s", err), nil)
+		return
+	}
+	var fsMeta fsMetaV1
+	if err = json.Unmarshal(buf, &fsMeta); err != nil || fsMeta.Version != fsMetaVersion || fsMeta.Format != fsMetaFormat {
+		// Object is still served without metadata, only
+		// content type and user defined metadata are lost.
+		v.report(disk, minioMetaBucket, fsMetaPath, "corrupted metadata", removeFSMeta)
+		return
+	}
+
+	// Only objects uploaded in a single part carry the md5sum of
+	// their content.
+	md5Sum := fsMeta.Meta["md5Sum"]
+	if !v.deep || md5Sum == "" || strings.Contains(md5Sum, "-") {
+		return
+	}
+	sum, err := hashSum(context.Background(), disk, bucket, object, md5.New())
+	if err != nil {
+		v.report(disk, bucket, object, fmt.Sprintf("unreadable data, %s", err), nil)
+		return
+	}
+	if hex.EncodeToString(sum) != md5Sum {
+		v.report(disk, bucket, object, "data does not match md5sum in metadata", nil)
+	}
+}
+
+// verifyXL - verifies `format.json` on all the disks and `xl.json`
+// along with the erasure coded parts of all the objects.
+func (v *backendVerifier) verifyXL(diskPaths []string) error {
+	disks := make([]StorageAPI, len(diskPaths))
+	formats := make([]*formatConfigV1, len(diskPaths))
+	for i, diskPath := range diskPaths {
+		disk, err := newPosix(diskPath)
+		if err != nil {
+			return err
+		}
+		disks[i] = disk
+		if formats[i], err = loadFormat(disk); err != nil {
+			// Not repaired here, formats are healed by the
+			// server on startup.
+			v.report(disk, minioMetaBucket, formatConfigFile, fmt.Sprintf("unable to load backend format, %s", err), nil)
+		}
+	}
+	if err := checkFormatXL(formats); err != nil {
+		return err
+	}
+
+	// Number of disks holding valid metadata for each object.
+	objects := make(map[string]int)
+	for _, disk := range disks {
+		vols, err := disk.ListVols()
+		if err != nil {
+			return err
+		}
+		for _, vol := range vols {
+			prefix := ""
+			if isMinioMetaBucketName(vol.Name) {
+				if vol.Name != minioMetaBucket {
+					continue
+				}
+				// Only bucket metadata is stored as XL
+				// objects, temporary files and multipart
+				// uploads are not verified.
+				prefix = bucketMetaPrefix
+			}
+			if err = v.walkXL(disk, vol.Name, prefix, objects); err != nil {
+				return err
+			}
+		}
+	}
+
+	for object, count := range objects {
+		if count < len(disks) {
+			v.issues = append(v.issues, verifyIssue{
+				path:    object,
+				problem: fmt.Sprintf("object missing on %d of %d disks, heal it with a running server", len(disks)-count, len(disks)),
+			})
+		}
+	}
+	return nil
+}
+
+// walkXL - recursively verifies all the objects of volume under prefix
+// on disk, counting the objects with valid metadata.
+func (v *backendVerifier) walkXL(disk StorageAPI, volume, prefix string, objects map[string]int) error {
+	entries, err := disk.ListDir(volume, prefix)
+	if err != nil {
+		if err == errFileNotFound {
+			return nil
+		}
+		return err
+	}
+
+	var isObject bool
+	for _, entry := range entries {
+		if entry == xlMetaJSONFile {
+			isObject = true
+			break
+		}
+	}
+	if isObject {
+		if v.verifyXLObject(disk, volume, prefix, entries) {
+			objects[path.Join(volume, prefix)]++
+		}
+		return nil
+	}
+
+	for _, entry := range entries {
+		entryPath := path.Join(prefix, entry)
+		if strings.HasSuffix(entry, slashSeparator) {
+			if err = v.walkXL(disk, volume, entryPath, objects); err != nil {
+				return err
+			}
+			continue
+		}
+		if prefix == "" {
+			// Only objects are supposed to be found inside
+			// buckets, bucket metadata is stored separately.
+			v.report(disk, volume, entryPath, "orphaned file", func() error {
+				return disk.DeleteFile(volume, entryPath)
+			})
+			continue
+		}
+		// Parts left behind without `xl.json` can not be
+		// read, they are rewritten upon healing.
+		v.report(disk, volume, entryPath, "orphaned file without metadata", func() error {
+			return disk.DeleteFile(volume, entryPath)
+		})
+	}
+	return nil
+}
+
+// verifyXLObject - verifies `xl.json` of a single object against its
+// erasure coded parts on disk, returns true if the metadata is valid.
+func (v *backendVerifier) verifyXLObject(disk StorageAPI, volume, object string, entries []string) bool {
+	xlMeta, err := readXLMeta(disk, volume, object)
+	if err != nil || !xlMeta.IsValid() {
+		// Removing metadata makes the object appear missing
+		// on this disk, it is then rewritten upon healing.
+		v.report(disk, volume, path.Join(object, xlMetaJSONFile), "corrupted metadata", nil)
+		return false
+	}
+
+	referenced := map[string]bool{xlMetaJSONFile: true}
+	for _, part := range xlMeta.Parts {
+		referenced[part.Name] = true
+		partPath := path.Join(object, part.Name)
+		fi, err := disk.StatFile(volume, partPath)
+		if err != nil {
+			v.report(disk, volume, partPath, "missing part", nil)
+			continue
+		}
+		expected := xlShardFileSize(part.Size, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
+		if fi.Size != expected {
+			v.report(disk, volume, partPath, fmt.Sprintf("size %d does not match expected size %d", fi.Size, expected), nil)
+			continue
+		}
+		if !v.deep {
+			continue
+		}
+		ckSum := xlMeta.Erasure.GetCheckSumInfo(part.Name)
+		sum, err := hashSum(context.Background(), disk, volume, partPath, newHash(ckSum.Algorithm))
+		if err != nil {
+			v.report(disk, volume, partPath, fmt.Sprintf("unreadable part, %s", err), nil)
+			continue
+		}
+		if hex.EncodeToString(sum) != ckSum.Hash {
+			v.report(disk, volume, partPath, "part does not match checksum in metadata", nil)
+		}
+	}
+
+	for _, entry := range entries {
+		if referenced[entry] {
+			continue
+		}
+		entryPath := path.Join(object, entry)
+		v.report(disk, volume, entryPath, "orphaned file not referenced by metadata", func() error {
+			if strings.HasSuffix(entry, slashSeparator) {
+				return fsRemoveAll(pathJoin(disk.String(), volume, entryPath))
+			}
+			return disk.DeleteFile(volume, entryPath)
+		})
+	}
+	return true
+}
+
+// xlShardFileSize - returns the size of an erasure coded part file on
+// each disk, every block of the part is split into dataBlocks chunks.
+func xlShardFileSize(partSize, blockSize int64, dataBlocks int) int64 {
+	if blockSize <= 0 || dataBlocks <= 0 {
+		return 0
+	}
+	size := (partSize / blockSize) * getChunkSize(blockSize, dataBlocks)
+	if lastBlock := partSize % blockSize; lastBlock > 0 {
+		size += getChunkSize(lastBlock, dataBlocks)
+	}
+	return size
+}
+
+// mainVerify handler called for 'minio verify' command.
+func mainVerify(ctx *cli.Context) {
+	if !ctx.Args().Present() || ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, "verify", 1)
+	}
+
+	// Initialization routine, such as config loading, enable logging, ..
+	minioInit(ctx)
+
+	diskPaths := ctx.Args()
+	endpoints, err := parseStorageEndpoints(diskPaths)
+	fatalIf(err, "Unable to parse storage endpoints %s", strings.Join(diskPaths, " "))
+	for _, ep := range endpoints {
+		if ep.Host != "" {
+			fatalIf(errInvalidArgument, "Only local paths can be verified, %s is remote.", ep)
+		}
+		// Verifying never creates the paths, unlike the server.
+		_, err = os.Stat(ep.Path)
+		fatalIf(err, "Unable to access %s.", ep.Path)
+	}
+
+	v := &backendVerifier{
+		repair: ctx.Bool("repair"),
+		deep:   ctx.Bool("deep"),
+	}
+	if len(diskPaths) == 1 {
+		err = v.verifyFS(diskPaths[0])
+	} else {
+		err = v.verifyXL(diskPaths)
+	}
+	fatalIf(err, "Unable to verify %s.", strings.Join(diskPaths, " "))
+
+	for _, issue := range v.issues {
+		console.Println(issue)
+	}
+	unrepaired := v.unrepaired()
+	if !globalQuiet {
+		console.Printf("Found %d issue(s), %d left unrepaired.\n", len(v.issues), unrepaired)
+	}
+	if unrepaired > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests computing the size of erasure coded part files.
+func TestXLShardFileSize(t *testing.T) {
+	testCases := []struct {
+		partSize   int64
+		blockSize  int64
+		dataBlocks int
+		expected   int64
+	}{
+		{0, blockSizeV1, 8, 0},
+		{1, blockSizeV1, 8, 1},
+		{blockSizeV1, blockSizeV1, 8, blockSizeV1 / 8},
+		{blockSizeV1 + 9, blockSizeV1, 8, blockSizeV1/8 + 2},
+		{10, 4, 3, 2 + 2 + 1},
+		{10, 0, 3, 0},
+	}
+	for i, testCase := range testCases {
+		if size := xlShardFileSize(testCase.partSize, testCase.blockSize, testCase.dataBlocks); size != testCase.expected {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expected, size)
+		}
+	}
+}
+
+// Tests verifying and repairing FS backend.
+func TestVerifyFS(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	for _, object := range []string{"ok", "dir/missing", "corrupt", "bitrot"} {
+		if _, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := &backendVerifier{deep: true}
+	if err = v.verifyFS(fsDir); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.issues) != 0 {
+		t.Fatalf("Expected no issues, got %v", v.issues)
+	}
+
+	metaDir := filepath.Join(fsDir, minioMetaBucket, bucketMetaPrefix, bucket)
+	if err = os.Remove(filepath.Join(fsDir, bucket, "dir", "missing")); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(metaDir, "corrupt", fsMetaJSONFile), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(fsDir, bucket, "bitrot"), []byte("hello, World"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Join(fsDir, minioMetaBucket, bucketMetaPrefix, "removed"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	v = &backendVerifier{deep: true, repair: true}
+	if err = v.verifyFS(fsDir); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %v", v.issues)
+	}
+	// Data corruption can not be repaired.
+	if v.unrepaired() != 1 {
+		t.Fatalf("Expected 1 unrepaired issue, got %v", v.issues)
+	}
+
+	v = &backendVerifier{}
+	if err = v.verifyFS(fsDir); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.issues) != 0 {
+		t.Fatalf("Expected no issues after repair, got %v", v.issues)
+	}
+}
+
+// Tests verifying and repairing XL backend.
+func TestVerifyXL(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1024)
+	for _, object := range []string{"ok", "missing", "nometa"} {
+		if _, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := &backendVerifier{deep: true}
+	if err = v.verifyXL(fsDirs); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.issues) != 0 {
+		t.Fatalf("Expected no issues, got %v", v.issues)
+	}
+
+	// Part missing on one disk.
+	if err = os.Remove(filepath.Join(fsDirs[0], bucket, "missing", "part.1")); err != nil {
+		t.Fatal(err)
+	}
+	// Metadata missing on one disk, leaving an orphaned part.
+	if err = os.Remove(filepath.Join(fsDirs[1], bucket, "nometa", xlMetaJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+	// Bit rot of a part on one disk.
+	if err = ioutil.WriteFile(filepath.Join(fsDirs[2], bucket, "ok", "part.1"), bytes.Repeat([]byte("b"), 64), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Stray file not referenced by metadata.
+	if err = ioutil.WriteFile(filepath.Join(fsDirs[3], bucket, "ok", "part.2"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v = &backendVerifier{deep: true, repair: true}
+	if err = v.verifyXL(fsDirs); err != nil {
+		t.Fatal(err)
+	}
+	// Missing part, orphaned part, object needing heal, bit rot and
+	// stray file.
+	if len(v.issues) != 5 {
+		t.Fatalf("Expected 5 issues, got %v", v.issues)
+	}
+	if v.unrepaired() != 3 {
+		t.Fatalf("Expected 3 unrepaired issues, got %v", v.issues)
+	}
+	if _, err = os.Stat(filepath.Join(fsDirs[3], bucket, "ok", "part.2")); !os.IsNotExist(err) {
+		t.Fatalf("Expected stray file to be removed, got %v", err)
+	}
+}
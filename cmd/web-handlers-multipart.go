@@ -0,0 +1,272 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/minio/miniobrowser"
+)
+
+// Browser uploads of large files are multipart uploads, uploading a
+// chunk of the file per part. An interrupted upload, even by a page
+// refresh, is resumed by listing its uploaded parts and uploading the
+// missing ones only.
+
+// NewMultipartUploadArgs - new multipart upload args.
+type NewMultipartUploadArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+	// Content-Type and X-Amz-Meta-* headers of the object.
+	Metadata map[string]string `json:"metadata"`
+}
+
+// NewMultipartUploadRep - new multipart upload reply.
+type NewMultipartUploadRep struct {
+	UIVersion string `json:"uiVersion"`
+	UploadID  string `json:"uploadId"`
+}
+
+// NewMultipartUpload - initiates a multipart upload of an object.
+func (web *webAPIHandlers) NewMultipartUpload(r *http.Request, args *NewMultipartUploadArgs, reply *NewMultipartUploadRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	accessKey, ok := isWebActionAllowed(r, "s3:PutObject", args.BucketName, args.ObjectName)
+	if !ok {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
+	// Browser uploads are never verified.
+	if checkBucketIntegrity(args.BucketName, nil) != ErrNone {
+		return toJSONError(errContentMD5Required)
+	}
+
+	header := make(http.Header)
+	for key, value := range args.Metadata {
+		header.Set(key, value)
+	}
+	metadata := extractMetadataFromHeader(header)
+	setObjectOwner(metadata, accessKey)
+	uploadID, err := objectAPI.NewMultipartUpload(r.Context(), args.BucketName, args.ObjectName, metadata)
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	reply.UploadID = uploadID
+	return nil
+}
+
+// UploadPart - file chunk upload handler, uploading the part
+// partNumber of the multipart upload uploadId.
+func (web *webAPIHandlers) UploadPart(w http.ResponseWriter, r *http.Request) {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		writeWebErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := r.URL.Query().Get("uploadId")
+
+	accessKey, ok := isWebActionAllowed(r, "s3:PutObject", bucket, object)
+	if !ok {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
+	if checkUserQuota(accessKey, r.ContentLength) != ErrNone {
+		writeWebErrorResponse(w, errUserQuotaExceeded)
+		return
+	}
+	if err := checkServerMode(bucket, true); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	partID, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil || partID < 1 || isMaxPartID(partID) {
+		writeWebErrorResponse(w, InvalidPart{})
+		return
+	}
+
+	// Uploads are limited to the upload bandwidth of the bucket and of the user.
+	reader := globalBucketBandwidth.ThrottleReader(bucket, accessKey, r.Body)
+	partMD5, err := objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, r.ContentLength, reader, "", "")
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", "\""+partMD5+"\"")
+}
+
+// webObjectPart - part uploaded by the browser.
+type webObjectPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// ListObjectPartsArgs - list object parts args.
+type ListObjectPartsArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+	UploadID   string `json:"uploadId"`
+}
+
+// ListObjectPartsRep - list object parts reply.
+type ListObjectPartsRep struct {
+	UIVersion string          `json:"uiVersion"`
+	Parts     []webObjectPart `json:"parts"`
+}
+
+// ListObjectParts - lists all the uploaded parts of a multipart
+// upload, to resume it.
+func (web *webAPIHandlers) ListObjectParts(r *http.Request, args *ListObjectPartsArgs, reply *ListObjectPartsRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if _, ok := isWebActionAllowed(r, "s3:ListMultipartUploadParts", args.BucketName, args.ObjectName); !ok {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, false); err != nil {
+		return toJSONError(err)
+	}
+
+	partNumberMarker := 0
+	for {
+		listPartsInfo, err := objectAPI.ListObjectParts(r.Context(), args.BucketName, args.ObjectName, args.UploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return toJSONError(err, args.BucketName)
+		}
+		for _, part := range listPartsInfo.Parts {
+			reply.Parts = append(reply.Parts, webObjectPart{
+				PartNumber: part.PartNumber,
+				ETag:       "\"" + part.ETag + "\"",
+				Size:       part.Size,
+			})
+		}
+		if !listPartsInfo.IsTruncated {
+			break
+		}
+		partNumberMarker = listPartsInfo.NextPartNumberMarker
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	return nil
+}
+
+// CompleteMultipartUploadArgs - complete multipart upload args.
+type CompleteMultipartUploadArgs struct {
+	BucketName string          `json:"bucketName"`
+	ObjectName string          `json:"objectName"`
+	UploadID   string          `json:"uploadId"`
+	Parts      []webObjectPart `json:"parts"`
+}
+
+// CompleteMultipartUpload - completes a multipart upload, assembling
+// its parts in the order of their part numbers.
+func (web *webAPIHandlers) CompleteMultipartUpload(r *http.Request, args *CompleteMultipartUploadArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if _, ok := isWebActionAllowed(r, "s3:PutObject", args.BucketName, args.ObjectName); !ok {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
+	if len(args.Parts) == 0 {
+		return &json2.Error{
+			Message: "Parts is a mandatory argument.",
+		}
+	}
+
+	var completeParts []completePart
+	for _, part := range args.Parts {
+		completeParts = append(completeParts, completePart{
+			PartNumber: part.PartNumber,
+			ETag:       strings.Trim(part.ETag, "\""),
+		})
+	}
+	if !sort.IsSorted(completedParts(completeParts)) {
+		return toJSONError(InvalidPart{})
+	}
+
+	// Hold write lock on the object.
+	destLock := globalNSMutex.NewNSLock(r.Context(), args.BucketName, args.ObjectName)
+	destLock.Lock()
+	defer destLock.Unlock()
+
+	objInfo, err := objectAPI.CompleteMultipartUpload(r.Context(), args.BucketName, args.ObjectName, args.UploadID, completeParts)
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+
+	// Notify object created event.
+	eventNotify(eventData{
+		Type:    ObjectCreatedCompleteMultipartUpload,
+		Bucket:  args.BucketName,
+		ObjInfo: objInfo,
+		ReqParams: map[string]string{
+			"sourceIPAddress": r.RemoteAddr,
+		},
+	})
+	return nil
+}
+
+// AbortMultipartUploadArgs - abort multipart upload args.
+type AbortMultipartUploadArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+	UploadID   string `json:"uploadId"`
+}
+
+// AbortMultipartUpload - aborts a multipart upload, removing its parts.
+func (web *webAPIHandlers) AbortMultipartUpload(r *http.Request, args *AbortMultipartUploadArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if _, ok := isWebActionAllowed(r, "s3:AbortMultipartUpload", args.BucketName, args.ObjectName); !ok {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
+
+	if err := objectAPI.AbortMultipartUpload(r.Context(), args.BucketName, args.ObjectName, args.UploadID); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	return nil
+}
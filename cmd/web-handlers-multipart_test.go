@@ -0,0 +1,167 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Wrapper for calling the browser multipart upload handlers tests for both XL multiple disks and single node setup.
+func TestWebHandlerMultipartUpload(t *testing.T) {
+	ExecObjectLayerTest(t, testWebMultipartUpload)
+}
+
+// testWebMultipartUpload - Test resuming a multipart upload from the browser.
+func testWebMultipartUpload(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	// initialize the server and obtain the credentials and root.
+	// credentials are necessary to sign the HTTP request.
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	credentials := serverConfig.GetCredential()
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	objectName := "videos/movie.mp4"
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	callWebRPC := func(method string, args interface{}, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, rerr := newTestWebRPCRequest(method, authorization, args)
+		if rerr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", rerr)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, &reply)
+	}
+	uploadPart := func(token, uploadID string, partNumber int, data []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		url := fmt.Sprintf("/minio/upload-part/%s/%s?uploadId=%s&partNumber=%d", bucketName, objectName, uploadID, partNumber)
+		req, rerr := http.NewRequest("PUT", url, bytes.NewReader(data))
+		if rerr != nil {
+			t.Fatalf("Cannot create upload request, %v", rerr)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	newUploadRep := &NewMultipartUploadRep{}
+	newUploadArgs := NewMultipartUploadArgs{
+		BucketName: bucketName,
+		ObjectName: objectName,
+		Metadata:   map[string]string{"Content-Type": "video/mp4"},
+	}
+	if err = callWebRPC("Web.NewMultipartUpload", newUploadArgs, newUploadRep); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	uploadID := newUploadRep.UploadID
+
+	parts := [][]byte{
+		bytes.Repeat([]byte("a"), 5*humanize.MiByte),
+		[]byte("end of the movie"),
+	}
+
+	// Parts are only uploaded with a valid token.
+	if rec := uploadPart("", uploadID, 1, parts[0]); rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be 403, but instead found `%d`", instanceType, rec.Code)
+	}
+	if rec := uploadPart(authorization, uploadID, 1, parts[0]); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be 200, but instead found `%d`", instanceType, rec.Code)
+	}
+
+	// The upload is resumed by listing the uploaded parts.
+	listPartsRep := &ListObjectPartsRep{}
+	listPartsArgs := ListObjectPartsArgs{BucketName: bucketName, ObjectName: objectName, UploadID: uploadID}
+	if err = callWebRPC("Web.ListObjectParts", listPartsArgs, listPartsRep); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	if len(listPartsRep.Parts) != 1 || listPartsRep.Parts[0].PartNumber != 1 || listPartsRep.Parts[0].Size != int64(len(parts[0])) {
+		t.Fatalf("%s: Unexpected uploaded parts %#v", instanceType, listPartsRep.Parts)
+	}
+	rec := uploadPart(authorization, uploadID, 2, parts[1])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be 200, but instead found `%d`", instanceType, rec.Code)
+	}
+	completeParts := append(listPartsRep.Parts, webObjectPart{PartNumber: 2, ETag: rec.Header().Get("ETag")})
+
+	// Parts should be in order.
+	completeArgs := CompleteMultipartUploadArgs{
+		BucketName: bucketName,
+		ObjectName: objectName,
+		UploadID:   uploadID,
+		Parts:      []webObjectPart{completeParts[1], completeParts[0]},
+	}
+	if err = callWebRPC("Web.CompleteMultipartUpload", completeArgs, &WebGenericRep{}); err == nil {
+		t.Fatalf("%s: Expected completing with unordered parts to fail", instanceType)
+	}
+	completeArgs.Parts = completeParts
+	if err = callWebRPC("Web.CompleteMultipartUpload", completeArgs, &WebGenericRep{}); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, objectName)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.ContentType != "video/mp4" {
+		t.Errorf("%s: Expected content type video/mp4, got %s", instanceType, objInfo.ContentType)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucketName, objectName, 0, objInfo.Size, &buffer); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), bytes.Join(parts, nil)) {
+		t.Fatalf("%s: Uploaded object is not equal to its parts", instanceType)
+	}
+
+	// Aborted uploads cannot be resumed.
+	if err = callWebRPC("Web.NewMultipartUpload", newUploadArgs, newUploadRep); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	abortArgs := AbortMultipartUploadArgs{BucketName: bucketName, ObjectName: objectName, UploadID: newUploadRep.UploadID}
+	if err = callWebRPC("Web.AbortMultipartUpload", abortArgs, &WebGenericRep{}); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	listPartsArgs.UploadID = newUploadRep.UploadID
+	if err = callWebRPC("Web.ListObjectParts", listPartsArgs, &ListObjectPartsRep{}); err == nil {
+		t.Fatalf("%s: Expected listing the parts of an aborted upload to fail", instanceType)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/minio/minio-go/pkg/policy"
+	"github.com/minio/minio-go/pkg/set"
 	"github.com/minio/miniobrowser"
 )
 
@@ -99,8 +100,8 @@ func (web *webAPIHandlers) StorageInfo(r *http.Request, args *AuthRPCArgs, reply
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, authErr := webReqestAuthenticate(r); authErr != nil {
+		return toJSONError(authErr)
 	}
 	reply.StorageInfo = objectAPI.StorageInfo()
 	reply.UIVersion = miniobrowser.UIVersion
@@ -118,15 +119,45 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
+	accessKey, ok := isWebActionAllowed(r, "s3:CreateBucket", args.BucketName, "")
+	if !ok {
 		return toJSONError(errAuthentication)
 	}
-	bucketLock := globalNSMutex.NewNSLock(args.BucketName, "")
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
+	bucketLock := globalNSMutex.NewNSLock(r.Context(), args.BucketName, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
-	if err := objectAPI.MakeBucket(args.BucketName); err != nil {
+	// Buckets are counted and created one at a time when limited.
+	if globalBucketLimits.isCounted(accessKey) {
+		countLock := globalNSMutex.NewNSLock(r.Context(), minioMetaBucket, bucketCountLockPath)
+		countLock.Lock()
+		defer countLock.Unlock()
+	}
+	if s3Error := checkBucketCreation(r.Context(), args.BucketName, accessKey, objectAPI); s3Error != ErrNone {
+		return &json2.Error{Message: getAPIError(s3Error).Description}
+	}
+	if s3Error := checkBucketDNS(args.BucketName); s3Error != ErrNone {
+		return &json2.Error{Message: getAPIError(s3Error).Description}
+	}
+	if err := objectAPI.MakeBucket(r.Context(), args.BucketName); err != nil {
 		return toJSONError(err, args.BucketName)
 	}
+	// Buckets of IAM users are counted against their own limit.
+	if isIAMUser(accessKey) {
+		if err := writeBucketOwner(args.BucketName, accessKey, objectAPI); err != nil {
+			undoPutBucket(r.Context(), args.BucketName, false, objectAPI)
+			return toJSONError(err, args.BucketName)
+		}
+	}
+	if globalBucketDNS != nil {
+		if err := globalBucketDNS.Put(args.BucketName); err != nil {
+			errorIf(err, "Unable to publish DNS records of bucket %s.", args.BucketName)
+			undoPutBucket(r.Context(), args.BucketName, false, objectAPI)
+			return toJSONError(err)
+		}
+	}
 	reply.UIVersion = miniobrowser.UIVersion
 	return nil
 }
@@ -151,14 +182,21 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	authErr := webReqestAuthenticate(r)
-	if authErr != nil {
-		return toJSONError(authErr)
+	accessKey, ok := isWebActionAllowed(r, "s3:ListAllMyBuckets", "", "")
+	if !ok {
+		return toJSONError(errAuthentication)
 	}
-	buckets, err := objectAPI.ListBuckets()
+	if err := checkServerMode("", false); err != nil {
+		return toJSONError(err)
+	}
+	buckets, err := objectAPI.ListBuckets(r.Context())
 	if err != nil {
 		return toJSONError(err)
 	}
+	// IAM users only see the buckets they have access to.
+	if isIAMUser(accessKey) {
+		buckets = filterVisibleBuckets(buckets, accessKey)
+	}
 	for _, bucket := range buckets {
 		if bucket.Name == path.Base(reservedBucket) {
 			continue
@@ -208,12 +246,21 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	prefix := args.Prefix + "test" // To test if GetObject/PutObject with the specified prefix is allowed.
 	readable := isBucketActionAllowed("s3:GetObject", args.BucketName, prefix)
 	writable := isBucketActionAllowed("s3:PutObject", args.BucketName, prefix)
-	authErr := webReqestAuthenticate(r)
+	accessKey, authErr := webReqestAuthenticate(r)
+	if authErr != nil && authErr != errAuthentication {
+		// Anonymous listings are allowed by the access to the objects.
+		auditAnonymousAccess(r, "s3:ListBucket", bucketARNPrefix+path.Join(args.BucketName, args.Prefix), readable || writable)
+	}
 	switch {
 	case authErr == errAuthentication:
 		return toJSONError(authErr)
 	case authErr == nil:
-		break
+		// IAM users list the objects their policies allow them to.
+		conditions := map[string]set.StringSet{"prefix": set.CreateStringSet(args.Prefix)}
+		if !isIAMActionAllowed(accessKey, "s3:ListBucket", bucketARNPrefix+args.BucketName, conditions) {
+			return toJSONError(errAuthentication)
+		}
+		reply.Writable = isIAMUser(accessKey) && isIAMActionAllowed(accessKey, "s3:PutObject", bucketARNPrefix+path.Join(args.BucketName, prefix), nil)
 	case readable && writable:
 		reply.Writable = true
 		break
@@ -225,9 +272,12 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	default:
 		return errAuthentication
 	}
+	if err := checkServerMode(args.BucketName, false); err != nil {
+		return toJSONError(err)
+	}
 	marker := ""
 	for {
-		lo, err := objectAPI.ListObjects(args.BucketName, args.Prefix, marker, "/", 1000)
+		lo, err := objectAPI.ListObjects(r.Context(), args.BucketName, args.Prefix, marker, "/", 1000)
 		if err != nil {
 			return &json2.Error{Message: err.Error()}
 		}
@@ -265,15 +315,18 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
+	if _, ok := isWebActionAllowed(r, "s3:DeleteObject", args.BucketName, args.ObjectName); !ok {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
 
-	objectLock := globalNSMutex.NewNSLock(args.BucketName, args.ObjectName)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), args.BucketName, args.ObjectName)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	if err := objectAPI.DeleteObject(args.BucketName, args.ObjectName); err != nil {
+	if err := objectAPI.DeleteObject(r.Context(), args.BucketName, args.ObjectName); err != nil {
 		if isErrObjectNotFound(err) {
 			// Ignore object not found error.
 			reply.UIVersion = miniobrowser.UIVersion
@@ -312,13 +365,14 @@ type LoginRep struct {
 
 // Login - user login handler.
 func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginRep) error {
-	token, err := authenticateWeb(args.Username, args.Password)
+	token, err := authenticateWebUser(args.Username, args.Password)
 	if err != nil {
 		// Make sure to log errors related to browser login,
 		// for security and auditing reasons.
 		errorIf(err, "Unable to login request from %s", r.RemoteAddr)
 		return toJSONError(err)
 	}
+	globalAccessKeyUsage.Record(args.Username, r.RemoteAddr)
 
 	reply.Token = token
 	reply.UIVersion = miniobrowser.UIVersion
@@ -444,26 +498,47 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	bucket := vars["bucket"]
 	object := vars["object"]
 
-	authErr := webReqestAuthenticate(r)
+	accessKey, authErr := webReqestAuthenticate(r)
 	if authErr == errAuthentication {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
-	if authErr != nil && !isBucketActionAllowed("s3:PutObject", bucket, object) {
+	if authErr != nil && !isAnonymousActionAllowed(r, "s3:PutObject", bucket, object) {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
+	if authErr == nil && !isIAMActionAllowed(accessKey, "s3:PutObject", bucketARNPrefix+pathJoin(bucket, object), nil) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
+	if checkUserQuota(accessKey, r.ContentLength) != ErrNone {
+		writeWebErrorResponse(w, errUserQuotaExceeded)
+		return
+	}
+	if err := checkServerMode(bucket, true); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	// Browser uploads are never verified.
+	if checkBucketIntegrity(bucket, nil) != ErrNone {
+		writeWebErrorResponse(w, errContentMD5Required)
+		return
+	}
 
 	// Extract incoming metadata if any.
 	metadata := extractMetadataFromHeader(r.Header)
+	// Browser uploads are owned by the IAM user logged in, if any.
+	setObjectOwner(metadata, accessKey)
 
 	// Lock the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
 	sha256sum := ""
-	objInfo, err := objectAPI.PutObject(bucket, object, -1, r.Body, metadata, sha256sum)
+	// Uploads are limited to the upload bandwidth of the bucket and of the user.
+	reader := globalBucketBandwidth.ThrottleReader(bucket, accessKey, r.Body)
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, -1, reader, metadata, sha256sum)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
@@ -493,26 +568,35 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	object := vars["object"]
 	token := r.URL.Query().Get("token")
 
-	if !isAuthTokenValid(token) && !isBucketActionAllowed("s3:GetObject", bucket, object) {
+	accessKey, authErr := getWebTokenAccessKey(token)
+	if authErr == nil && !isIAMActionAllowed(accessKey, "s3:GetObject", bucketARNPrefix+pathJoin(bucket, object), nil) {
+		authErr = errAuthentication
+	}
+	if authErr != nil && !isAnonymousActionAllowed(r, "s3:GetObject", bucket, object) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
+	if err := checkServerMode(bucket, false); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
 
 	// Add content disposition.
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", path.Base(object)))
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewNSLock(r.Context(), bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), bucket, object)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
 	offset := int64(0)
-	err = objectAPI.GetObject(bucket, object, offset, objInfo.Size, w)
+	// Downloads are limited to the download bandwidth of the bucket and of the user.
+	err = objectAPI.GetObject(r.Context(), bucket, object, offset, objInfo.Size, globalBucketBandwidth.ThrottleWriter(bucket, accessKey, w))
 	if err != nil {
 		/// No need to print error, response writer already written to.
 		return
@@ -565,6 +649,9 @@ func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerMode(args.BucketName, false); err != nil {
+		return toJSONError(err)
+	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
 	if err != nil {
@@ -572,7 +659,7 @@ func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolic
 	}
 
 	reply.UIVersion = miniobrowser.UIVersion
-	reply.Policy = policy.GetPolicy(policyInfo.Statements, args.BucketName, args.Prefix)
+	reply.Policy = getBucketAccessPolicy(policyInfo.Statements, args.BucketName, args.Prefix)
 
 	return nil
 }
@@ -604,6 +691,9 @@ func (web *webAPIHandlers) ListAllBucketPolicies(r *http.Request, args *ListAllB
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerMode(args.BucketName, false); err != nil {
+		return toJSONError(err)
+	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
 	if err != nil {
@@ -611,7 +701,7 @@ func (web *webAPIHandlers) ListAllBucketPolicies(r *http.Request, args *ListAllB
 	}
 
 	reply.UIVersion = miniobrowser.UIVersion
-	for prefix, policy := range policy.GetPolicies(policyInfo.Statements, args.BucketName) {
+	for prefix, policy := range getBucketAccessPolicies(policyInfo.Statements, args.BucketName) {
 		reply.Policies = append(reply.Policies, bucketAccessPolicy{
 			Prefix: prefix,
 			Policy: policy,
@@ -637,9 +727,12 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
 
 	bucketP := policy.BucketPolicy(args.Policy)
-	if !bucketP.IsValidBucketPolicy() {
+	if !isValidBucketAccessPolicy(bucketP) {
 		return &json2.Error{
 			Message: "Invalid policy type " + args.Policy,
 		}
@@ -649,7 +742,7 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	if err != nil {
 		return toJSONError(err, args.BucketName)
 	}
-	policyInfo.Statements = policy.SetPolicy(policyInfo.Statements, bucketP, args.BucketName, args.Prefix)
+	policyInfo.Statements = setBucketAccessPolicy(policyInfo.Statements, bucketP, args.BucketName, args.Prefix)
 	if len(policyInfo.Statements) == 0 {
 		err = persistAndNotifyBucketPolicyChange(args.BucketName, policyChange{true, nil}, objectAPI)
 		if err != nil {
@@ -678,6 +771,135 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	return nil
 }
 
+// bucketNotificationRule - notification of the events on the objects
+// matching a prefix and a suffix to an external target.
+type bucketNotificationRule struct {
+	ID     string   `json:"id"`
+	ARN    string   `json:"arn"`
+	Events []string `json:"events"`
+	Prefix string   `json:"prefix"`
+	Suffix string   `json:"suffix"`
+}
+
+// GetBucketNotificationArgs - get bucket notification args.
+type GetBucketNotificationArgs struct {
+	BucketName string `json:"bucketName"`
+}
+
+// GetBucketNotificationRep - get bucket notification reply.
+type GetBucketNotificationRep struct {
+	UIVersion string                   `json:"uiVersion"`
+	Rules     []bucketNotificationRule `json:"rules"`
+	// ARNs of the targets configured on the server.
+	Targets []string `json:"targets"`
+}
+
+// GetBucketNotification - get the notification rules of a bucket.
+func (web *webAPIHandlers) GetBucketNotification(r *http.Request, args *GetBucketNotificationArgs, reply *GetBucketNotificationRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, false); err != nil {
+		return toJSONError(err)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(r.Context(), args.BucketName); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	nConfig, err := loadNotificationConfig(args.BucketName, objectAPI)
+	if err != nil && err != errNoSuchNotifications {
+		return toJSONError(err, args.BucketName)
+	}
+
+	reply.UIVersion = miniobrowser.UIVersion
+	if globalEventNotifier != nil {
+		reply.Targets = globalEventNotifier.GetExternalTargetARNs()
+	}
+	if nConfig == nil {
+		return nil
+	}
+	for _, qConfig := range nConfig.QueueConfigs {
+		rule := bucketNotificationRule{
+			ID:     qConfig.ID,
+			ARN:    qConfig.QueueARN,
+			Events: qConfig.Events,
+		}
+		for _, filterRule := range qConfig.Filter.Key.FilterRules {
+			switch filterRule.Name {
+			case "prefix":
+				rule.Prefix = filterRule.Value
+			case "suffix":
+				rule.Suffix = filterRule.Value
+			}
+		}
+		reply.Rules = append(reply.Rules, rule)
+	}
+	return nil
+}
+
+// SetBucketNotificationArgs - set bucket notification args.
+type SetBucketNotificationArgs struct {
+	BucketName string                   `json:"bucketName"`
+	Rules      []bucketNotificationRule `json:"rules"`
+}
+
+// SetBucketNotification - replaces the notification rules of a bucket,
+// keeping its lambda configurations.
+func (web *webAPIHandlers) SetBucketNotification(r *http.Request, args *SetBucketNotificationArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if err := checkServerMode(args.BucketName, true); err != nil {
+		return toJSONError(err)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(r.Context(), args.BucketName); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	nConfig, err := loadNotificationConfig(args.BucketName, objectAPI)
+	if err != nil && err != errNoSuchNotifications {
+		return toJSONError(err, args.BucketName)
+	}
+	if nConfig == nil {
+		nConfig = &notificationConfig{}
+	}
+
+	nConfig.QueueConfigs = nil
+	for _, rule := range args.Rules {
+		qConfig := queueConfig{QueueARN: rule.ARN}
+		qConfig.ID = rule.ID
+		qConfig.Events = rule.Events
+		if rule.Prefix != "" {
+			qConfig.Filter.Key.FilterRules = append(qConfig.Filter.Key.FilterRules, filterRule{"prefix", rule.Prefix})
+		}
+		if rule.Suffix != "" {
+			qConfig.Filter.Key.FilterRules = append(qConfig.Filter.Key.FilterRules, filterRule{"suffix", rule.Suffix})
+		}
+		nConfig.QueueConfigs = append(nConfig.QueueConfigs, qConfig)
+	}
+
+	if s3Error := validateNotificationConfig(*nConfig); s3Error != ErrNone {
+		return &json2.Error{
+			Message: getAPIError(s3Error).Description,
+		}
+	}
+	if err = PutBucketNotificationConfig(args.BucketName, nConfig, objectAPI); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	return nil
+}
+
 // PresignedGetArgs - presigned-get API args.
 type PresignedGetArgs struct {
 	// Host header required for signed headers.
@@ -691,6 +913,10 @@ type PresignedGetArgs struct {
 
 	// Expiry in seconds.
 	Expiry int64 `json:"expiry"`
+
+	// Object is displayed inline by the browser, for previews,
+	// rather than downloaded.
+	Inline bool `json:"inline"`
 }
 
 // PresignedGetRep - presigned-get URL reply.
@@ -700,25 +926,68 @@ type PresignedGetRep struct {
 	URL string `json:"url"`
 }
 
-// PresignedGET - returns presigned-Get url.
+// PresignedGET - returns presigned-Get url, recorded along with its
+// token in the share links of the bucket until it expires or is revoked.
 func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs, reply *PresignedGetRep) error {
-	if !isHTTPRequestValid(r) {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	accessKey, ok := isWebActionAllowed(r, "s3:GetObject", args.BucketName, args.ObjectName)
+	if !ok {
 		return toJSONError(errAuthentication)
 	}
+	// Links of IAM users are signed by their own credential, so that
+	// they stop working with the access of the user.
+	cred, _ := lookupWebCredential(accessKey)
 
 	if args.BucketName == "" || args.ObjectName == "" {
 		return &json2.Error{
 			Message: "Bucket and Object are mandatory arguments.",
 		}
 	}
+
+	// Links expire after the longest expiry allowed, unless sooner.
+	maxExpiry := int64(globalShareMaxExpiry / time.Second)
+	expiry := args.Expiry
+	if expiry <= 0 || expiry > maxExpiry {
+		expiry = maxExpiry
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return toJSONError(err)
+	}
+	now := time.Now().UTC()
+	link := shareLink{
+		Token:   token,
+		Bucket:  args.BucketName,
+		Object:  args.ObjectName,
+		Inline:  args.Inline,
+		Created: now,
+		Expiry:  now.Add(time.Duration(expiry) * time.Second),
+	}
+	link.URL = presignedGet(cred, args.HostName, args.BucketName, args.ObjectName, expiry, args.Inline, link.Token)
+	err = updateShareLinks(args.BucketName, objectAPI, func(links []shareLink) []shareLink {
+		return append(links, link)
+	})
+	if err == errTooManyShareLinks {
+		return &json2.Error{Message: err.Error()}
+	}
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+
 	reply.UIVersion = miniobrowser.UIVersion
-	reply.URL = presignedGet(args.HostName, args.BucketName, args.ObjectName, args.Expiry)
+	reply.URL = link.URL
 	return nil
 }
 
-// Returns presigned url for GET method.
-func presignedGet(host, bucket, object string, expiry int64) string {
-	cred := serverConfig.GetCredential()
+// Returns presigned url for GET method signed by cred expiring after
+// expiry seconds, overriding the Content-Disposition of the response
+// to inline if requested and carrying the token of a share link if any.
+func presignedGet(cred credential, host, bucket, object string, expiry int64, inline bool, token string) string {
 	region := serverConfig.GetRegion()
 
 	accessKey := cred.AccessKey
@@ -728,17 +997,22 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	dateStr := date.Format(iso8601Format)
 	credential := fmt.Sprintf("%s/%s", accessKey, getScope(date, region))
 
-	var expiryStr = "604800" // Default set to be expire in 7days.
-	if expiry < 604800 && expiry > 0 {
-		expiryStr = strconv.FormatInt(expiry, 10)
-	}
-	query := strings.Join([]string{
+	queryParams := []string{
 		"X-Amz-Algorithm=" + signV4Algorithm,
 		"X-Amz-Credential=" + strings.Replace(credential, "/", "%2F", -1),
 		"X-Amz-Date=" + dateStr,
-		"X-Amz-Expires=" + expiryStr,
+		"X-Amz-Expires=" + strconv.FormatInt(expiry, 10),
 		"X-Amz-SignedHeaders=host",
-	}, "&")
+	}
+	// Sort after the X-Amz-* parameters, as the canonical query
+	// string requires.
+	if token != "" {
+		queryParams = append(queryParams, shareTokenQuery+"="+token)
+	}
+	if inline {
+		queryParams = append(queryParams, "response-content-disposition=inline")
+	}
+	query := strings.Join(queryParams, "&")
 
 	path := "/" + path.Join(bucket, object)
 
@@ -754,6 +1028,105 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	return host + path + "?" + query + "&" + "X-Amz-Signature=" + signature
 }
 
+// ListShareLinksArgs - list share links args.
+type ListShareLinksArgs struct {
+	// Bucket of the links, all the buckets if empty.
+	BucketName string `json:"bucketName"`
+}
+
+// ListShareLinksRep - list share links reply.
+type ListShareLinksRep struct {
+	UIVersion string      `json:"uiVersion"`
+	Links     []shareLink `json:"links"`
+	// Longest expiry of a link in seconds.
+	MaxExpiry int64 `json:"maxExpiry"`
+}
+
+// ListShareLinks - lists the links shared from the browser which have
+// not expired nor been revoked.
+func (web *webAPIHandlers) ListShareLinks(r *http.Request, args *ListShareLinksArgs, reply *ListShareLinksRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+
+	buckets := []string{args.BucketName}
+	if args.BucketName == "" {
+		bucketsInfo, err := objectAPI.ListBuckets(r.Context())
+		if err != nil {
+			return toJSONError(err)
+		}
+		buckets = nil
+		for _, bucket := range bucketsInfo {
+			buckets = append(buckets, bucket.Name)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, bucket := range buckets {
+		for _, link := range globalBucketShareLinks.Get(bucket) {
+			if link.Expiry.After(now) {
+				reply.Links = append(reply.Links, link)
+			}
+		}
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	reply.MaxExpiry = int64(globalShareMaxExpiry / time.Second)
+	return nil
+}
+
+// RevokeShareLinkArgs - revoke share link args.
+type RevokeShareLinkArgs struct {
+	BucketName string `json:"bucketName"`
+	Token      string `json:"token"`
+}
+
+// RevokeShareLink - revokes the token of a link shared from the
+// browser, the link is denied access from then on.
+func (web *webAPIHandlers) RevokeShareLink(r *http.Request, args *RevokeShareLinkArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+
+	if args.BucketName == "" || args.Token == "" {
+		return &json2.Error{
+			Message: "Bucket and Token are mandatory arguments.",
+		}
+	}
+
+	revoked := false
+	err := updateShareLinks(args.BucketName, objectAPI, func(links []shareLink) []shareLink {
+		var validLinks []shareLink
+		for _, link := range links {
+			if link.Token == args.Token {
+				revoked = true
+				continue
+			}
+			validLinks = append(validLinks, link)
+		}
+		return validLinks
+	})
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	if !revoked {
+		return &json2.Error{
+			Message: "Share link not found.",
+		}
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	return nil
+}
+
 // toJSONError converts regular errors into more user friendly
 // and consumable error message for the browser UI.
 func toJSONError(err error, params ...string) (jerr *json2.Error) {
@@ -821,11 +1194,17 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errContentMD5Required {
+		return getAPIError(ErrContentMD5Required)
+	} else if err == errUserQuotaExceeded {
+		return getAPIError(ErrUserQuotaExceeded)
 	}
 
 	// Convert error type to api error code.
 	var apiErrCode APIErrorCode
-	switch err.(type) {
+	switch e := err.(type) {
+	case serverModeErr:
+		apiErrCode = APIErrorCode(e)
 	case StorageFull:
 		apiErrCode = ErrStorageFull
 	case BucketNotFound:
@@ -844,6 +1223,14 @@ func toWebAPIError(err error) APIError {
 		apiErrCode = ErrNoSuchKey
 	case ObjectNameInvalid:
 		apiErrCode = ErrNoSuchKey
+	case ObjectNameUnsupported:
+		apiErrCode = ErrObjectNameUnsupported
+	case InvalidUploadID:
+		apiErrCode = ErrNoSuchUpload
+	case InvalidPart:
+		apiErrCode = ErrInvalidPart
+	case PartTooSmall:
+		apiErrCode = ErrEntityTooSmall
 	case InsufficientWriteQuorum:
 		apiErrCode = ErrWriteQuorum
 	case InsufficientReadQuorum:
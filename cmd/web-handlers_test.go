@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -27,7 +28,9 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Sirupsen/logrus"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio-go/pkg/policy"
 	"github.com/minio/minio-go/pkg/set"
@@ -349,7 +352,7 @@ func testListBucketsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 
 	bucketName := getRandomBucketName()
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -409,7 +412,7 @@ func testListObjectsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -417,7 +420,7 @@ func testListObjectsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 
 	data := bytes.Repeat([]byte("a"), objectSize)
 
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
@@ -512,7 +515,7 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -520,7 +523,7 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 
 	data := bytes.Repeat([]byte("a"), objectSize)
 
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data),
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data),
 		map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
@@ -758,7 +761,7 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 		return rec.Code
 	}
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -771,7 +774,7 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 	}
 
 	var byteBuffer bytes.Buffer
-	err = obj.GetObject(bucketName, objectName, 0, int64(len(content)), &byteBuffer)
+	err = obj.GetObject(context.Background(), bucketName, objectName, 0, int64(len(content)), &byteBuffer)
 	if err != nil {
 		t.Fatalf("Failed, %v", err)
 	}
@@ -780,6 +783,21 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 		t.Fatalf("The upload file is different from the download file")
 	}
 
+	// Files of uploaded folders keep their relative paths as prefixes.
+	objectName = "photos/2017/summer/beach.jpg"
+	code = test(authorization)
+	if code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+	result, err := obj.ListObjects(context.Background(), bucketName, "photos/2017/", "", "/", 10)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(result.Prefixes) != 1 || result.Prefixes[0] != "photos/2017/summer/" {
+		t.Fatalf("Expected the prefix photos/2017/summer/, found %v", result.Prefixes)
+	}
+	objectName = "test.file"
+
 	// Unauthenticated upload should fail.
 	code = test("")
 	if code != http.StatusForbidden {
@@ -846,14 +864,14 @@ func testDownloadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandl
 	}
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
 	}
 
 	content := []byte("temporary file's content")
-	_, err = obj.PutObject(bucketName, objectName, int64(len(content)), bytes.NewReader(content), map[string]string{"md5Sum": "01ce59706106fe5e02e7f55fffda7f34"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(content)), bytes.NewReader(content), map[string]string{"md5Sum": "01ce59706106fe5e02e7f55fffda7f34"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
 	}
@@ -910,6 +928,9 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 	// remove the root directory after the test ends.
 	defer removeAll(rootPath)
 
+	// Share links are sent to the local peer.
+	initGlobalS3Peers(nil)
+
 	credentials := serverConfig.GetCredential()
 
 	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
@@ -924,14 +945,14 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
 	}
 
 	data := bytes.Repeat([]byte("a"), objectSize)
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
 	}
@@ -979,8 +1000,39 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 		t.Fatal("Read data is not equal was what was expected")
 	}
 
+	// Presigned URLs for previews display the object inline.
+	webRouter := initTestWebRPCEndPoint(obj)
+	presignGetReq.Inline = true
+	rec = httptest.NewRecorder()
+	req, err = newTestWebRPCRequest("Web.PresignedGet", authorization, presignGetReq)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	webRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	presignGetRep = &PresignedGetRep{}
+	if err = getTestWebRPCResponse(rec, &presignGetRep); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	arec = httptest.NewRecorder()
+	req, err = newTestRequest("GET", presignGetRep.URL, 0, nil)
+	if err != nil {
+		t.Fatal("Failed to initialized a new request", err)
+	}
+	req.Header.Del("x-amz-content-sha256")
+	apiRouter.ServeHTTP(arec, req)
+	if arec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", arec.Code)
+	}
+	if disposition := arec.Header().Get("Content-Disposition"); disposition != "inline" {
+		t.Fatalf("Expected Content-Disposition inline, found `%s`", disposition)
+	}
+
 	// Register the API end points with XL/FS object layer.
 	apiRouter = initTestWebRPCEndPoint(obj)
+	rec = httptest.NewRecorder()
 
 	presignGetReq = PresignedGetArgs{
 		HostName:   "",
@@ -1033,7 +1085,7 @@ func testWebGetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 	rec := httptest.NewRecorder()
 
 	bucketName := getRandomBucketName()
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1116,7 +1168,7 @@ func testWebListAllBucketPoliciesHandler(obj ObjectLayer, instanceType string, t
 	rec := httptest.NewRecorder()
 
 	bucketName := getRandomBucketName()
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1223,7 +1275,7 @@ func testWebSetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 
 	// Create a bucket
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1269,6 +1321,350 @@ func testWebSetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 	}
 }
 
+// Wrapper for calling ListShareLinks and RevokeShareLink Web Handlers
+func TestWebHandlerShareLinksHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testWebShareLinksHandler)
+}
+
+// testWebShareLinksHandler - Test ListShareLinks and RevokeShareLink web handlers
+func testWebShareLinksHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	webRouter := initTestWebRPCEndPoint(obj)
+	apiRouter := initTestAPIEndPoints(obj, []string{"GetObject"})
+	// initialize the server and obtain the credentials and root.
+	// credentials are necessary to sign the HTTP request.
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	// Share links are sent to the local peer.
+	initGlobalS3Peers(nil)
+
+	credentials := serverConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(webRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	data := []byte("hello")
+	for _, objectName := range []string{"object-1", "object-2"} {
+		_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+		if err != nil {
+			t.Fatalf("Was not able to upload an object, %v", err)
+		}
+	}
+
+	callWebRPC := func(method string, args interface{}, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, rerr := newTestWebRPCRequest(method, authorization, args)
+		if rerr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", rerr)
+		}
+		webRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, &reply)
+	}
+	getObject := func(url string) int {
+		rec := httptest.NewRecorder()
+		req, rerr := newTestRequest("GET", url, 0, nil)
+		if rerr != nil {
+			t.Fatal("Failed to initialized a new request", rerr)
+		}
+		req.Header.Del("x-amz-content-sha256")
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Share both objects, the expiry is capped at the longest allowed.
+	var urls []string
+	for _, objectName := range []string{"object-1", "object-2"} {
+		presignGetRep := &PresignedGetRep{}
+		args := PresignedGetArgs{BucketName: bucketName, ObjectName: objectName, Expiry: 30 * 24 * 3600}
+		if err = callWebRPC("Web.PresignedGet", args, presignGetRep); err != nil {
+			t.Fatalf("Failed, %v", err)
+		}
+		if !strings.Contains(presignGetRep.URL, "X-Amz-Expires=604800&") {
+			t.Fatalf("Expected the expiry to be capped at a week, got %s", presignGetRep.URL)
+		}
+		urls = append(urls, presignGetRep.URL)
+	}
+
+	listRep := &ListShareLinksRep{}
+	if err = callWebRPC("Web.ListShareLinks", ListShareLinksArgs{}, listRep); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(listRep.Links) != 2 {
+		t.Fatalf("Expected 2 share links, got %d", len(listRep.Links))
+	}
+	if listRep.MaxExpiry != int64(globalShareMaxExpiry/time.Second) {
+		t.Fatalf("Expected the longest expiry to be %d, got %d", int64(globalShareMaxExpiry/time.Second), listRep.MaxExpiry)
+	}
+	for i, link := range listRep.Links {
+		if link.Bucket != bucketName || link.URL != urls[i] {
+			t.Fatalf("Unexpected share link %#v", link)
+		}
+		if code := getObject(link.URL); code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+		}
+	}
+
+	// Revoke the first link, the second one remains valid.
+	revokeArgs := RevokeShareLinkArgs{BucketName: bucketName, Token: listRep.Links[0].Token}
+	if err = callWebRPC("Web.RevokeShareLink", revokeArgs, &WebGenericRep{}); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if code := getObject(urls[0]); code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	}
+	if code := getObject(urls[1]); code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+
+	// Revoking again fails.
+	if err = callWebRPC("Web.RevokeShareLink", revokeArgs, &WebGenericRep{}); err == nil {
+		t.Fatal("Expected revoking a revoked link to fail")
+	}
+
+	listRep = &ListShareLinksRep{}
+	if err = callWebRPC("Web.ListShareLinks", ListShareLinksArgs{BucketName: bucketName}, listRep); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(listRep.Links) != 1 || listRep.Links[0].Object != "object-2" {
+		t.Fatalf("Unexpected share links %#v", listRep.Links)
+	}
+}
+
+// Wrapper for calling Get/SetBucketNotification Web Handler
+func TestWebHandlerBucketNotificationHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testWebBucketNotificationHandler)
+}
+
+// testWebBucketNotificationHandler - Test GetBucketNotification and SetBucketNotification web handlers
+func testWebBucketNotificationHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	// initialize the server and obtain the credentials and root.
+	// credentials are necessary to sign the HTTP request.
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	if err = initEventNotifier(obj); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	queueARN := "arn:minio:sqs:" + globalMinioDefaultRegion + ":1:webhook"
+	serverConfig.SetWebhookNotifyByID("1", webhookNotify{Enable: true, Endpoint: "http://127.0.0.1:1/"})
+	globalEventNotifier.SetExternalTargets(map[string]*logrus.Logger{queueARN: logrus.New()})
+
+	credentials := serverConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	// Create a bucket
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	rule := bucketNotificationRule{
+		ID:     "1",
+		ARN:    queueARN,
+		Events: []string{"s3:ObjectCreated:*"},
+		Prefix: "photos/",
+		Suffix: ".jpg",
+	}
+	testCases := []struct {
+		bucketName string
+		rules      []bucketNotificationRule
+		pass       bool
+	}{
+		// Invalid bucket name
+		{"", []bucketNotificationRule{rule}, false},
+		// Bucket not found
+		{"nonexistent-bucket", []bucketNotificationRule{rule}, false},
+		// Unknown target
+		{bucketName, []bucketNotificationRule{{ARN: "arn:minio:sqs:" + globalMinioDefaultRegion + ":2:webhook", Events: rule.Events}}, false},
+		// Invalid event name
+		{bucketName, []bucketNotificationRule{{ARN: queueARN, Events: []string{"s3:ObjectFoo"}}}, false},
+		// Valid parameters
+		{bucketName, []bucketNotificationRule{rule}, true},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		args := &SetBucketNotificationArgs{BucketName: testCase.bucketName, Rules: testCase.rules}
+		reply := &WebGenericRep{}
+		// Call SetBucketNotification RPC
+		req, err := newTestWebRPCRequest("Web.SetBucketNotification", authorization, args)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		// Check if we have 200 OK
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: Expected the response status to be 200, but instead found `%d`", i+1, rec.Code)
+		}
+		// Parse RPC response
+		err = getTestWebRPCResponse(rec, &reply)
+		if testCase.pass && err != nil {
+			t.Fatalf("Test %d: Should succeed but it didn't, %v", i+1, err)
+		}
+		if !testCase.pass && err == nil {
+			t.Fatalf("Test %d: Should fail it didn't", i+1)
+		}
+	}
+
+	// Read back the rules set by the last test case.
+	rec := httptest.NewRecorder()
+	args := &GetBucketNotificationArgs{BucketName: bucketName}
+	reply := &GetBucketNotificationRep{}
+	req, err := newTestWebRPCRequest("Web.GetBucketNotification", authorization, args)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	if err = getTestWebRPCResponse(rec, &reply); err != nil {
+		t.Fatalf("Should succeed but it didn't, %v", err)
+	}
+	if !reflect.DeepEqual(reply.Rules, []bucketNotificationRule{rule}) {
+		t.Errorf("Expected the rules %#v, got %#v", []bucketNotificationRule{rule}, reply.Rules)
+	}
+	if !reflect.DeepEqual(reply.Targets, []string{queueARN}) {
+		t.Errorf("Expected the targets %v, got %v", []string{queueARN}, reply.Targets)
+	}
+}
+
+// Wrapper for calling the web handlers as an IAM user
+func TestWebHandlerIAMUser(t *testing.T) {
+	defer resetGlobalIAMSys()
+	ExecObjectLayerTest(t, testWebIAMUserHandler)
+}
+
+// testWebIAMUserHandler - Test the web handlers authorize IAM users by their policies
+func testWebIAMUserHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	// initialize the server and obtain the credentials and root.
+	// credentials are necessary to sign the HTTP request.
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+	resetGlobalIAMSys()
+
+	bucketName := getRandomBucketName()
+	objectName := "object"
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	content := []byte("hello")
+	if _, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(content)), bytes.NewReader(content), nil, ""); err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+
+	accessKey, secretKey := "webuser", "webusersecret"
+	if err = globalIAMSys.SetUser(obj, accessKey, secretKey); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	authorization, err := getWebRPCToken(apiRouter, accessKey, secretKey)
+	if err != nil {
+		t.Fatalf("IAM user cannot authenticate, %v", err)
+	}
+	// Tokens of IAM users never authenticate the servers.
+	if isAuthTokenValid(authorization) {
+		t.Fatalf("Token of an IAM user authenticates the servers")
+	}
+
+	call := func(method string, args, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, rerr := newTestWebRPCRequest("Web."+method, authorization, args)
+		if rerr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", rerr)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			return fmt.Errorf("status %d", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
+	}
+	download := func() int {
+		rec := httptest.NewRecorder()
+		req, rerr := http.NewRequest("GET", "/minio/download/"+bucketName+"/"+objectName+"?token="+authorization, nil)
+		if rerr != nil {
+			t.Fatalf("Cannot create download request, %v", rerr)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	testCases := []struct {
+		method string
+		args   interface{}
+		reply  interface{}
+	}{
+		{"StorageInfo", &AuthRPCArgs{}, &StorageInfoRep{}},
+		{"ListBuckets", &WebGenericArgs{}, &ListBucketsRep{}},
+		{"ListObjects", &ListObjectsArgs{BucketName: bucketName}, &ListObjectsRep{}},
+		{"RemoveObject", &RemoveObjectArgs{BucketName: bucketName, ObjectName: "missing"}, &WebGenericRep{}},
+		{"ServerInfo", &WebGenericArgs{}, &ServerInfoRep{}},
+		{"GetAuth", &WebGenericArgs{}, &GetAuthReply{}},
+	}
+	check := func(policies string, success []bool, downloadCode int) {
+		for i, testCase := range testCases {
+			err := call(testCase.method, testCase.args, testCase.reply)
+			if success[i] && err != nil {
+				t.Errorf("%s with %s: Expected to succeed but it failed, %v", testCase.method, policies, err)
+			}
+			if !success[i] && err == nil {
+				t.Errorf("%s with %s: Expected to fail but it didn't", testCase.method, policies)
+			}
+		}
+		if code := download(); code != downloadCode {
+			t.Errorf("Download with %s: Expected the response status to be %d, but instead found `%d`", policies, downloadCode, code)
+		}
+	}
+
+	// Users have no access until policies are attached to them.
+	check("no policies", []bool{true, false, false, false, false, false}, http.StatusForbidden)
+
+	if err = globalIAMSys.AttachPolicy(obj, accessKey, "readonly"); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	check("readonly", []bool{true, true, true, false, false, false}, http.StatusOK)
+
+	// The server configuration is reserved to the server credentials.
+	if err = globalIAMSys.AttachPolicy(obj, accessKey, "readwrite"); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	check("readwrite", []bool{true, true, true, true, false, false}, http.StatusOK)
+
+	// Tokens of disabled users stop being valid.
+	if err = globalIAMSys.SetUserStatus(obj, accessKey, iamUserDisabled); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	check("disabled user", []bool{false, false, false, false, false, false}, http.StatusForbidden)
+}
+
 // TestWebCheckAuthorization - Test Authorization for all web handlers
 func TestWebCheckAuthorization(t *testing.T) {
 	// Prepare XL backend
@@ -1298,7 +1694,9 @@ func TestWebCheckAuthorization(t *testing.T) {
 		"ListBuckets", "ListObjects", "RemoveObject",
 		"GenerateAuth", "SetAuth", "GetAuth",
 		"GetBucketPolicy", "SetBucketPolicy", "ListAllBucketPolicies",
-		"PresignedGet",
+		"PresignedGet", "GetBucketNotification", "SetBucketNotification",
+		"ListShareLinks", "RevokeShareLink",
+		"NewMultipartUpload", "ListObjectParts", "CompleteMultipartUpload", "AbortMultipartUpload",
 	}
 	for _, rpcCall := range webRPCs {
 		args := &AuthRPCArgs{}
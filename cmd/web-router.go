@@ -83,6 +83,7 @@ func registerWebRouter(mux *router.Router) error {
 	// RPC handler at URI - /minio/webrpc
 	webBrowserRouter.Methods("POST").Path("/webrpc").Handler(webRPC)
 	webBrowserRouter.Methods("PUT").Path("/upload/{bucket}/{object:.+}").HandlerFunc(web.Upload)
+	webBrowserRouter.Methods("PUT").Path("/upload-part/{bucket}/{object:.+}").Queries("uploadId", "{uploadId:.*}", "partNumber", "{partNumber:[0-9]+}").HandlerFunc(web.UploadPart)
 	webBrowserRouter.Methods("GET").Path("/download/{bucket}/{object:.+}").Queries("token", "{token:.*}").HandlerFunc(web.Download)
 
 	// Add compression for assets.
@@ -0,0 +1,290 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// Share links config file, listing the presigned links generated
+	// by the browser for the objects of a bucket.
+	shareLinksConfig = "share-links.json"
+
+	// Query parameter carrying the token of a link shared from the
+	// browser, signed along with the rest of the presigned URL.
+	shareTokenQuery = "X-Minio-Share-Token"
+
+	// Environment variable setting the longest expiry of the links
+	// shared from the browser, such as "24h".
+	shareMaxExpiryEnv = "MINIO_BROWSER_SHARE_MAX_EXPIRY"
+
+	// Most links shared from the browser for the objects of a bucket
+	// which have not expired nor been revoked.
+	maxShareLinksPerBucket = 1000
+)
+
+// errTooManyShareLinks - the bucket has already maxShareLinksPerBucket
+// share links.
+var errTooManyShareLinks = errors.New("Too many share links for the bucket, please revoke some of them or wait for them to expire")
+
+// Links shared from the browser expire after at most a week by default,
+// which is also the longest expiry of a presigned URL.
+const defaultShareMaxExpiry = 7 * 24 * time.Hour
+
+// Longest expiry of the links shared from the browser.
+var globalShareMaxExpiry = defaultShareMaxExpiry
+
+// getShareMaxExpiry - returns the longest expiry of the links shared
+// from the browser configured in the environment.
+func getShareMaxExpiry() (time.Duration, error) {
+	value := os.Getenv(shareMaxExpiryEnv)
+	if value == "" {
+		return defaultShareMaxExpiry, nil
+	}
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", shareMaxExpiryEnv, value, err)
+	}
+	if expiry < time.Second || expiry > defaultShareMaxExpiry {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be between 1s and %s", shareMaxExpiryEnv, value, defaultShareMaxExpiry)
+	}
+	return expiry, nil
+}
+
+// shareLink - presigned link to an object, generated by the browser and
+// valid until it expires or its token is revoked.
+type shareLink struct {
+	Token   string    `json:"token"`
+	Bucket  string    `json:"bucket"`
+	Object  string    `json:"object"`
+	Inline  bool      `json:"inline"`
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+	Expiry  time.Time `json:"expiry"`
+}
+
+// shareLinksV1 - format of shareLinksConfig.
+type shareLinksV1 struct {
+	Version string      `json:"version"`
+	Links   []shareLink `json:"links"`
+}
+
+// newShareToken - returns a new random share link token.
+func newShareToken() (string, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// bucketShareLinks - in-memory share links of all the buckets.
+type bucketShareLinks struct {
+	rwMutex *sync.RWMutex
+	links   map[string][]shareLink
+}
+
+// Global share links, loaded at object layer initialization and kept
+// up to date by the peers on every change.
+var globalBucketShareLinks = &bucketShareLinks{
+	rwMutex: &sync.RWMutex{},
+	links:   make(map[string][]shareLink),
+}
+
+// Get - returns the share links of a bucket, the links are replaced on
+// every change and should not be modified.
+func (bs *bucketShareLinks) Get(bucket string) []shareLink {
+	bs.rwMutex.RLock()
+	defer bs.rwMutex.RUnlock()
+	return bs.links[bucket]
+}
+
+// IsShared - returns true if object is shared by the link of token.
+func (bs *bucketShareLinks) IsShared(bucket, object, token string) bool {
+	bs.rwMutex.RLock()
+	defer bs.rwMutex.RUnlock()
+	for _, link := range bs.links[bucket] {
+		if link.Token == token && link.Object == object {
+			return true
+		}
+	}
+	return false
+}
+
+// Set - sets the share links of a bucket, no links removes them.
+func (bs *bucketShareLinks) Set(bucket string, links []shareLink) {
+	bs.rwMutex.Lock()
+	defer bs.rwMutex.Unlock()
+	if len(links) == 0 {
+		delete(bs.links, bucket)
+		return
+	}
+	bs.links[bucket] = links
+}
+
+// Intialize share links of all buckets.
+func initBucketShareLinks(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	errorIf(err, "Unable to list buckets.")
+	if err != nil {
+		return errorCause(err)
+	}
+
+	bucketLinks := make(map[string][]shareLink)
+	for _, bucket := range buckets {
+		links, rErr := readShareLinks(bucket.Name, objAPI)
+		if rErr != nil {
+			// Continue to load other links if the disk is unavailable.
+			if isErrIgnored(rErr, errDiskNotFound) {
+				continue
+			}
+			return rErr
+		}
+		if len(links) > 0 {
+			bucketLinks[bucket.Name] = links
+		}
+	}
+
+	globalBucketShareLinks.rwMutex.Lock()
+	globalBucketShareLinks.links = bucketLinks
+	globalBucketShareLinks.rwMutex.Unlock()
+
+	// Success.
+	return nil
+}
+
+// checkShareLink - verifies that the token of a link shared from the
+// browser has not been revoked, requests without a token are not links
+// shared from the browser.
+func checkShareLink(r *http.Request) APIErrorCode {
+	token := r.URL.Query().Get(shareTokenQuery)
+	if token == "" {
+		return ErrNone
+	}
+	bucket, object := path2BucketAndObject(r.URL.Path)
+	if !globalBucketShareLinks.IsShared(bucket, object, token) {
+		return ErrShareLinkRevoked
+	}
+	return ErrNone
+}
+
+// loadShareLinks - reads the share links of a bucket, the caller holds
+// a lock on the config.
+func loadShareLinks(bucket string, objAPI ObjectLayer) ([]shareLink, error) {
+	linksPath := pathJoin(bucketConfigPrefix, bucket, shareLinksConfig)
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, linksPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, nil
+		}
+		errorIf(err, "Unable to load share links for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+
+	var config shareLinksV1
+	if err = json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse share links for the bucket %s.", bucket)
+		return nil, err
+	}
+	return config.Links, nil
+}
+
+// readShareLinks - returns the share links of a bucket.
+func readShareLinks(bucket string, objAPI ObjectLayer) ([]shareLink, error) {
+	linksPath := pathJoin(bucketConfigPrefix, bucket, shareLinksConfig)
+
+	// Acquire a read lock on share links before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, linksPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	return loadShareLinks(bucket, objAPI)
+}
+
+// updateShareLinks - replaces the share links of a bucket with the
+// result of update, dropping the expired links, and sends them to the
+// peers. Returns errTooManyShareLinks if the bucket would have more
+// than maxShareLinksPerBucket links.
+func updateShareLinks(bucket string, objAPI ObjectLayer, update func([]shareLink) []shareLink) error {
+	linksPath := pathJoin(bucketConfigPrefix, bucket, shareLinksConfig)
+
+	// Acquire a write lock on share links before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, linksPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	links, err := loadShareLinks(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	var validLinks []shareLink
+	for _, link := range links {
+		if link.Expiry.After(now) {
+			validLinks = append(validLinks, link)
+		}
+	}
+	validLinks = update(validLinks)
+	if len(validLinks) > maxShareLinksPerBucket {
+		return errTooManyShareLinks
+	}
+
+	if len(validLinks) == 0 {
+		if err = objAPI.DeleteObject(context.Background(), minioMetaBucket, linksPath); err != nil && !isErrObjectNotFound(err) {
+			errorIf(err, "Unable to remove share links for the bucket %s.", bucket)
+			return errorCause(err)
+		}
+	} else {
+		var buf []byte
+		if buf, err = json.Marshal(shareLinksV1{Version: "1", Links: validLinks}); err != nil {
+			return err
+		}
+		if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, linksPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+			errorIf(err, "Unable to set share links for the bucket %s.", bucket)
+			return errorCause(err)
+		}
+	}
+
+	// Peers are sent the links under the lock, for them to receive
+	// concurrent updates in order.
+	S3PeersUpdateBucketShareLinks(bucket, validLinks)
+	return nil
+}
+
+// removeShareLinks - removes the share links of a bucket, if any.
+func removeShareLinks(bucket string, objAPI ObjectLayer) error {
+	return updateShareLinks(bucket, objAPI, func([]shareLink) []shareLink {
+		return nil
+	})
+}
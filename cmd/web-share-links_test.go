@@ -0,0 +1,111 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the longest expiry of share links.
+func TestGetShareMaxExpiry(t *testing.T) {
+	defer os.Unsetenv(shareMaxExpiryEnv)
+
+	testCases := []struct {
+		value      string
+		expiry     time.Duration
+		shouldPass bool
+	}{
+		{"", defaultShareMaxExpiry, true},
+		{"24h", 24 * time.Hour, true},
+		{"168h", defaultShareMaxExpiry, true},
+		{"169h", 0, false},
+		{"0s", 0, false},
+		{"-1h", 0, false},
+		{"day", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(shareMaxExpiryEnv, testCase.value)
+		expiry, err := getShareMaxExpiry()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if expiry != testCase.expiry {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expiry, expiry)
+		}
+	}
+}
+
+// Wrapper for calling the share links updates tests for both XL multiple disks and single node setup.
+func TestUpdateShareLinks(t *testing.T) {
+	ExecObjectLayerTest(t, testUpdateShareLinks)
+}
+
+func testUpdateShareLinks(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Share links are sent to the local peer.
+	globalObjectAPI = obj
+	initGlobalS3Peers(nil)
+
+	bucket := getRandomBucketName()
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	defer globalBucketShareLinks.Set(bucket, nil)
+
+	link := shareLink{Token: "token", Bucket: bucket, Object: "object", Expiry: time.Now().UTC().Add(time.Hour)}
+	err := updateShareLinks(bucket, obj, func(links []shareLink) []shareLink {
+		return append(links, link)
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !globalBucketShareLinks.IsShared(bucket, "object", "token") {
+		t.Fatalf("%s: Expected the link to be shared", instanceType)
+	}
+
+	// Links are loaded back from the backend.
+	globalBucketShareLinks.Set(bucket, nil)
+	if err = initBucketShareLinks(obj); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !globalBucketShareLinks.IsShared(bucket, "object", "token") {
+		t.Fatalf("%s: Expected the link to be loaded", instanceType)
+	}
+
+	// Links are capped per bucket.
+	err = updateShareLinks(bucket, obj, func(links []shareLink) []shareLink {
+		for len(links) <= maxShareLinksPerBucket {
+			links = append(links, link)
+		}
+		return links
+	})
+	if err != errTooManyShareLinks {
+		t.Fatalf("%s: Expected %v, got %v", instanceType, errTooManyShareLinks, err)
+	}
+	if links := globalBucketShareLinks.Get(bucket); len(links) != 1 {
+		t.Fatalf("%s: Expected the links to be left unchanged, got %d links", instanceType, len(links))
+	}
+
+	if err = removeShareLinks(bucket, obj); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if globalBucketShareLinks.IsShared(bucket, "object", "token") {
+		t.Fatalf("%s: Expected the link to be removed", instanceType)
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"sort"
 	"sync"
 )
@@ -30,7 +31,7 @@ var bucketMetadataOpIgnoredErrs = append(bucketOpIgnoredErrs, errVolumeNotFound)
 /// Bucket operations
 
 // MakeBucket - make a bucket.
-func (xl xlObjects) MakeBucket(bucket string) error {
+func (xl xlObjects) MakeBucket(ctx context.Context, bucket string) error {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return traceError(BucketNameInvalid{Bucket: bucket})
@@ -143,7 +144,7 @@ func (xl xlObjects) getBucketInfo(bucketName string) (bucketInfo BucketInfo, err
 }
 
 // GetBucketInfo - returns BucketInfo for a bucket.
-func (xl xlObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
+func (xl xlObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return BucketInfo{}, BucketNameInvalid{Bucket: bucket}
@@ -202,7 +203,7 @@ func (xl xlObjects) listBuckets() (bucketsInfo []BucketInfo, err error) {
 }
 
 // ListBuckets - lists all the buckets, sorted by its name.
-func (xl xlObjects) ListBuckets() ([]BucketInfo, error) {
+func (xl xlObjects) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
 	bucketInfos, err := xl.listBuckets()
 	if err != nil {
 		return nil, toObjectErr(err)
@@ -213,7 +214,7 @@ func (xl xlObjects) ListBuckets() ([]BucketInfo, error) {
 }
 
 // DeleteBucket - deletes a bucket.
-func (xl xlObjects) DeleteBucket(bucket string) error {
+func (xl xlObjects) DeleteBucket(ctx context.Context, bucket string) error {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return BucketNameInvalid{Bucket: bucket}
@@ -265,3 +266,95 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 	// Success.
 	return nil
 }
+
+// renameBucketOnDisk - moves all the entries of srcBucket on disk to
+// a new dstBucket volume, along with its incomplete multipart uploads
+// and its bucket metadata. Every entry is renamed, no data is copied.
+func renameBucketOnDisk(disk StorageAPI, srcBucket, dstBucket string) error {
+	entries, err := disk.ListDir(srcBucket, "")
+	if err != nil {
+		return traceError(err)
+	}
+	if err = disk.MakeVol(dstBucket); err != nil {
+		return traceError(err)
+	}
+	for _, entry := range entries {
+		if err = disk.RenameFile(srcBucket, entry, dstBucket, entry); err != nil {
+			return traceError(err)
+		}
+	}
+	if err = disk.DeleteVol(srcBucket); err != nil {
+		return traceError(err)
+	}
+
+	// Move incomplete multiparts and bucket metadata, left over entries
+	// of a previously deleted dstBucket are removed first.
+	metaDirs := []struct{ volume, prefix string }{
+		{minioMetaMultipartBucket, ""},
+		{minioMetaBucket, bucketConfigPrefix},
+	}
+	for _, metaDir := range metaDirs {
+		srcDir := retainSlash(pathJoin(metaDir.prefix, srcBucket))
+		dstDir := retainSlash(pathJoin(metaDir.prefix, dstBucket))
+		if err = cleanupDir(disk, metaDir.volume, dstDir); err != nil {
+			return err
+		}
+		err = disk.RenameFile(metaDir.volume, srcDir, metaDir.volume, dstDir)
+		if err != nil && err != errFileNotFound && err != errVolumeNotFound {
+			return traceError(err)
+		}
+	}
+	return nil
+}
+
+// RenameBucket - renames srcBucket to dstBucket on all disks, which
+// should not exist yet.
+func (xl xlObjects) RenameBucket(ctx context.Context, srcBucket, dstBucket string) error {
+	// Verify if bucket names are valid.
+	if !IsValidBucketName(srcBucket) {
+		return traceError(BucketNameInvalid{Bucket: srcBucket})
+	}
+	if !IsValidBucketName(dstBucket) {
+		return traceError(BucketNameInvalid{Bucket: dstBucket})
+	}
+	if _, err := xl.GetBucketInfo(ctx, srcBucket); err != nil {
+		return err
+	}
+	if _, err := xl.GetBucketInfo(ctx, dstBucket); err == nil {
+		return traceError(BucketExists{Bucket: dstBucket})
+	} else if _, ok := errorCause(err).(BucketNotFound); !ok {
+		return err
+	}
+
+	// Initialize sync waitgroup.
+	var wg = &sync.WaitGroup{}
+
+	// Initialize list of errors.
+	var dErrs = make([]error, len(xl.storageDisks))
+
+	// Rename the volume entries on all underlying storage disks.
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			dErrs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			dErrs[index] = renameBucketOnDisk(disk, srcBucket, dstBucket)
+		}(index, disk)
+	}
+
+	// Wait for all the renames to finish.
+	wg.Wait()
+
+	// Disks which failed, or missed the rename while offline,
+	// are fixed up by healing the buckets.
+	if !isDiskQuorum(dErrs, xl.writeQuorum) {
+		return toObjectErr(traceError(errXLWriteQuorum), srcBucket)
+	}
+	if reducedErr := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.writeQuorum); reducedErr != nil {
+		return toObjectErr(reducedErr, srcBucket)
+	}
+	return nil
+}
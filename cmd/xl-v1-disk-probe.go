@@ -0,0 +1,156 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Environment variable setting the interval between two write probes
+// of all the disks, such as "30s", "off" probes only at startup.
+const diskProbeIntervalEnv = "MINIO_DISK_PROBE_INTERVAL"
+
+// Disks are probed every minute by default.
+const defaultDiskProbeInterval = time.Minute
+
+// Data written by the probes, read back for comparison.
+var diskProbeData = []byte("minio disk write probe")
+
+// errDiskProbeMismatch - probe read back did not match the data written.
+var errDiskProbeMismatch = errors.New("disk probe read back different data")
+
+// getDiskProbeInterval - returns the disk probe interval configured
+// through the environment, 0 if periodic probes are disabled.
+func getDiskProbeInterval() (time.Duration, error) {
+	value := os.Getenv(diskProbeIntervalEnv)
+	switch value {
+	case "":
+		return defaultDiskProbeInterval, nil
+	case "off":
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, %s", diskProbeIntervalEnv, value, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("Invalid %s ‘%s’, should be positive", diskProbeIntervalEnv, value)
+	}
+	return interval, nil
+}
+
+// probeDiskWrite - writes, reads back and deletes a small file on disk,
+// detecting disks remounted read-only or otherwise failing writes.
+func probeDiskWrite(disk StorageAPI) error {
+	probePath := "disk-probe-" + mustGetUUID()
+	if err := disk.AppendFile(context.Background(), minioMetaTmpBucket, probePath, diskProbeData); err != nil {
+		return err
+	}
+	buf, err := disk.ReadAll(minioMetaTmpBucket, probePath)
+	if err == nil && !bytes.Equal(buf, diskProbeData) {
+		err = errDiskProbeMismatch
+	}
+	if dErr := disk.DeleteFile(minioMetaTmpBucket, probePath); err == nil {
+		err = dErr
+	}
+	return err
+}
+
+// diskProbe - write probes of the disks of an XL object layer. Disks
+// failing writes are marked offline, removed from the object layer
+// until writable again.
+type diskProbe struct {
+	mutex      sync.Mutex
+	disks      []StorageAPI // Disks probed, including the ones marked offline.
+	unwritable []bool
+}
+
+// newDiskProbe - initialize a probe of disks.
+func newDiskProbe(disks []StorageAPI) *diskProbe {
+	return &diskProbe{
+		disks:      append([]StorageAPI(nil), disks...),
+		unwritable: make([]bool, len(disks)),
+	}
+}
+
+// unwritableCount - returns the number of disks marked offline.
+func (p *diskProbe) unwritableCount() int {
+	if p == nil {
+		return 0
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	count := 0
+	for _, unwritable := range p.unwritable {
+		if unwritable {
+			count++
+		}
+	}
+	return count
+}
+
+// probeDisks - probes all the disks, marking the ones failing writes
+// offline and the ones writable again back online. Disks found offline
+// are already handled as such.
+func (xl xlObjects) probeDisks() {
+	if xl.probe == nil {
+		return
+	}
+	xl.probe.mutex.Lock()
+	defer xl.probe.mutex.Unlock()
+
+	for index, disk := range xl.probe.disks {
+		if disk == nil {
+			continue
+		}
+		err := probeDiskWrite(disk)
+		if isErrIgnored(err, errDiskNotFound) {
+			continue
+		}
+		switch {
+		case err != nil && !xl.probe.unwritable[index]:
+			errorIf(err, "Disk %s is not writable, marked offline.", disk)
+			xl.probe.unwritable[index] = true
+			xl.storageDisks[index] = nil
+		case err == nil && xl.probe.unwritable[index]:
+			xl.probe.unwritable[index] = false
+			xl.storageDisks[index] = disk
+		}
+	}
+}
+
+// startDiskProbe - probes the disks of objAPI every interval in the
+// background, when objAPI is an XL object layer.
+func startDiskProbe(objAPI ObjectLayer, interval time.Duration) {
+	xl, ok := objAPI.(*xlObjects)
+	if !ok {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			xl.probeDisks()
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests parsing the disk probe interval.
+func TestGetDiskProbeInterval(t *testing.T) {
+	defer os.Unsetenv(diskProbeIntervalEnv)
+
+	testCases := []struct {
+		value      string
+		interval   time.Duration
+		shouldPass bool
+	}{
+		{"", defaultDiskProbeInterval, true},
+		{"off", 0, true},
+		{"30s", 30 * time.Second, true},
+		{"0s", 0, false},
+		{"-1m", 0, false},
+		{"minute", 0, false},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(diskProbeIntervalEnv, testCase.value)
+		interval, err := getDiskProbeInterval()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+		if interval != testCase.interval {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.interval, interval)
+		}
+	}
+}
+
+// Tests disks failing write probes are marked offline until writable
+// again.
+func TestXLProbeDisks(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	// All disks are writable.
+	xl.probeDisks()
+	if info := xl.StorageInfo(); info.Backend.UnwritableDisks != 0 || info.Backend.OfflineDisks != 0 {
+		t.Fatalf("Expected all disks online, got %d unwritable, %d offline", info.Backend.UnwritableDisks, info.Backend.OfflineDisks)
+	}
+
+	// Fail all the writes of the first disk.
+	disk := newNaughtyDisk(xl.storageDisks[0].(*retryStorage), nil, errFaultyDisk)
+	xl.probe.disks[0], xl.storageDisks[0] = disk, disk
+	xl.probeDisks()
+	if xl.storageDisks[0] != nil {
+		t.Fatal("Expected the unwritable disk to be marked offline")
+	}
+	if info := xl.StorageInfo(); info.Backend.UnwritableDisks != 1 || info.Backend.OfflineDisks != 1 {
+		t.Fatalf("Expected 1 disk unwritable and offline, got %d unwritable, %d offline", info.Backend.UnwritableDisks, info.Backend.OfflineDisks)
+	}
+
+	// Disk writable again.
+	disk.mu.Lock()
+	disk.defaultErr = nil
+	disk.mu.Unlock()
+	xl.probeDisks()
+	if xl.storageDisks[0] != disk {
+		t.Fatal("Expected the writable disk to be back online")
+	}
+	if info := xl.StorageInfo(); info.Backend.UnwritableDisks != 0 || info.Backend.OfflineDisks != 0 {
+		t.Fatalf("Expected all disks online, got %d unwritable, %d offline", info.Backend.UnwritableDisks, info.Backend.OfflineDisks)
+	}
+}
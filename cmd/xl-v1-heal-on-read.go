@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Response header set on GETs served from a degraded object, the
+// object having been queued for healing.
+const minioHealingHeader = "X-Minio-Healing"
+
+// Maximum number of objects waiting for heal-on-read, further objects
+// are left to the next heal pass.
+const maxHealOnReadQueue = 10000
+
+// Key of the context value reporting degraded reads.
+type readHealStatusKey struct{}
+
+// readHealStatus - reports if an object read from a context was
+// found degraded and queued for healing.
+type readHealStatus struct {
+	healing int32
+}
+
+// withReadHealStatus - returns ctx reporting degraded reads made from
+// it in the returned status.
+func withReadHealStatus(ctx context.Context) (context.Context, *readHealStatus) {
+	status := &readHealStatus{}
+	return context.WithValue(ctx, readHealStatusKey{}, status), status
+}
+
+// markReadHealing - marks the read made from ctx as degraded, if ctx
+// reports it.
+func markReadHealing(ctx context.Context) {
+	if status, ok := ctx.Value(readHealStatusKey{}).(*readHealStatus); ok {
+		atomic.StoreInt32(&status.healing, 1)
+	}
+}
+
+// isHealing - returns true if the read was found degraded.
+func (s *readHealStatus) isHealing() bool {
+	return atomic.LoadInt32(&s.healing) == 1
+}
+
+// setHealingHeader - sets the healing response header if the read
+// reported by status was found degraded.
+func setHealingHeader(w http.ResponseWriter, status *readHealStatus) {
+	if status.isHealing() {
+		w.Header().Set(minioHealingHeader, "true")
+	}
+}
+
+// healEntry - object queued for healing.
+type healEntry struct {
+	bucket, object string
+}
+
+// healQueue - objects found degraded on read, healed one at a time in
+// the background. Queued objects are healed once even if read again
+// meanwhile, the worker only running while objects are queued.
+type healQueue struct {
+	mutex   sync.Mutex
+	heal    func(bucket, object string) error
+	queue   []healEntry
+	pending map[healEntry]struct{}
+	running bool
+}
+
+// newHealQueue - initialize a heal queue healing objects with heal.
+func newHealQueue(heal func(bucket, object string) error) *healQueue {
+	return &healQueue{
+		heal:    heal,
+		pending: make(map[healEntry]struct{}),
+	}
+}
+
+// enqueue - queues bucket/object for healing, returns false if the
+// queue is full.
+func (q *healQueue) enqueue(bucket, object string) bool {
+	if q == nil {
+		return false
+	}
+	entry := healEntry{bucket, object}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if _, ok := q.pending[entry]; ok {
+		return true
+	}
+	if len(q.queue) >= maxHealOnReadQueue {
+		return false
+	}
+	q.pending[entry] = struct{}{}
+	q.queue = append(q.queue, entry)
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	return true
+}
+
+// run - heals queued objects until the queue is empty.
+func (q *healQueue) run() {
+	for {
+		q.mutex.Lock()
+		if len(q.queue) == 0 {
+			q.running = false
+			q.mutex.Unlock()
+			return
+		}
+		entry := q.queue[0]
+		q.queue = q.queue[1:]
+		q.mutex.Unlock()
+
+		err := globalBackgroundOps.Do(func() error {
+			return q.heal(entry.bucket, entry.object)
+		})
+		errorIf(err, "Unable to heal %s/%s on read.", entry.bucket, entry.object)
+
+		q.mutex.Lock()
+		delete(q.pending, entry)
+		q.mutex.Unlock()
+	}
+}
+
+// backlog - returns the count of objects queued or being healed.
+func (q *healQueue) backlog() int {
+	if q == nil {
+		return 0
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.pending)
+}
+
+// isPending - returns true if bucket/object is queued or being healed.
+func (q *healQueue) isPending(bucket, object string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	_, ok := q.pending[healEntry{bucket, object}]
+	return ok
+}
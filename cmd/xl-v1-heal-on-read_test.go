@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// Tests objects found degraded on read are reported and healed.
+func TestXLHealOnRead(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("heal on read")
+	if _, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Healthy objects are not reported.
+	ctx, status := withReadHealStatus(context.Background())
+	var buffer bytes.Buffer
+	if err = obj.GetObject(ctx, bucket, object, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if status.isHealing() || xl.healQueue.isPending(bucket, object) {
+		t.Fatal("Expected a healthy object not to be healed")
+	}
+
+	// Remove the object from a disk, as if the disk was down when
+	// the object was created.
+	if err = os.RemoveAll(path.Join(fsDirs[0], bucket, object)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, status = withReadHealStatus(context.Background())
+	buffer.Reset()
+	if err = obj.GetObject(ctx, bucket, object, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatalf("Expected %q, got %q", data, buffer.Bytes())
+	}
+	if !status.isHealing() {
+		t.Fatal("Expected the degraded read to be reported")
+	}
+
+	// Wait for the background heal.
+	for i := 0; xl.healQueue.isPending(bucket, object); i++ {
+		if i == 100 {
+			t.Fatal("Object was not healed")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if _, err = readXLMeta(xl.storageDisks[0], bucket, object); err != nil {
+		t.Fatalf("Expected the object to be healed on read, %v", err)
+	}
+}
+
+// Tests objects are queued for healing once.
+func TestHealQueue(t *testing.T) {
+	release := make(chan struct{})
+	healed := make(chan string, 2)
+	queue := newHealQueue(func(bucket, object string) error {
+		<-release
+		healed <- pathJoin(bucket, object)
+		return nil
+	})
+
+	if !queue.enqueue("bucket", "object") || !queue.enqueue("bucket", "object") {
+		t.Fatal("Expected the object to be queued")
+	}
+	if !queue.isPending("bucket", "object") {
+		t.Fatal("Expected the object to be pending")
+	}
+	close(release)
+	if object := <-healed; object != "bucket/object" {
+		t.Fatalf("Expected bucket/object to be healed, got %s", object)
+	}
+	for queue.isPending("bucket", "object") {
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case object := <-healed:
+		t.Fatalf("Expected the object to be healed once, %s healed again", object)
+	default:
+	}
+
+	// Queues of uninitialized object layers do not heal.
+	var nilQueue *healQueue
+	if nilQueue.enqueue("bucket", "object") {
+		t.Fatal("Expected a nil queue not to queue objects")
+	}
+}
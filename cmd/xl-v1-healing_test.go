@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -170,7 +171,7 @@ func TestHealFormatXL(t *testing.T) {
 	}
 	xl = obj.(*xlObjects)
 	for i := 0; i <= 15; i++ {
-		if err = xl.storageDisks[i].AppendFile(".minio.sys", "format.json", []byte("corrupted data")); err != nil {
+		if err = xl.storageDisks[i].AppendFile(context.Background(), ".minio.sys", "format.json", []byte("corrupted data")); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -255,7 +256,7 @@ func TestHealFormatXL(t *testing.T) {
 		t.Fatal(err)
 	}
 	xl = obj.(*xlObjects)
-	if err = obj.MakeBucket(getRandomBucketName()); err != nil {
+	if err = obj.MakeBucket(context.Background(), getRandomBucketName()); err != nil {
 		t.Fatal(err)
 	}
 	for i := 0; i <= 2; i++ {
@@ -296,14 +297,14 @@ func TestUndoMakeBucket(t *testing.T) {
 	}
 
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal(err)
 	}
 	xl := obj.(*xlObjects)
 	undoMakeBucket(xl.storageDisks, bucketName)
 
 	// Validate if bucket was deleted properly.
-	_, err = obj.GetBucketInfo(bucketName)
+	_, err = obj.GetBucketInfo(context.Background(), bucketName)
 	if err != nil {
 		err = errorCause(err)
 		switch err.(type) {
@@ -341,7 +342,7 @@ func TestQuickHeal(t *testing.T) {
 	}
 
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal(err)
 	}
 
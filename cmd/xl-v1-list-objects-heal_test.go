@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strconv"
 	"testing"
 )
@@ -44,21 +45,21 @@ func TestListObjectsHeal(t *testing.T) {
 	objName := "obj"
 
 	// Create test bucket
-	err = xl.MakeBucket(bucketName)
+	err = xl.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Put 500 objects under sane dir
 	for i := 0; i < 500; i++ {
-		_, err = xl.PutObject(bucketName, "sane/"+objName+strconv.Itoa(i), int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+		_, err = xl.PutObject(context.Background(), bucketName, "sane/"+objName+strconv.Itoa(i), int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 		if err != nil {
 			t.Fatalf("XL Object upload failed: <ERROR> %s", err)
 		}
 	}
 	// Put 500 objects under unsane/subdir dir
 	for i := 0; i < 500; i++ {
-		_, err = xl.PutObject(bucketName, "unsane/subdir/"+objName+strconv.Itoa(i), int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+		_, err = xl.PutObject(context.Background(), bucketName, "unsane/subdir/"+objName+strconv.Itoa(i), int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 		if err != nil {
 			t.Fatalf("XL Object upload failed: <ERROR> %s", err)
 		}
@@ -77,7 +78,7 @@ func TestListObjectsHeal(t *testing.T) {
 
 	// Generic function for testing ListObjectsHeal, needs testData as a parameter
 	testFunc := func(testCase testData, testRank int) {
-		objectsNeedHeal, foundErr := xl.ListObjectsHeal(testCase.bucket, testCase.object, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		objectsNeedHeal, foundErr := xl.ListObjectsHeal(context.Background(), testCase.bucket, testCase.object, testCase.marker, testCase.delimiter, testCase.maxKeys)
 		if testCase.expectedErr == nil && foundErr != nil {
 			t.Fatalf("Test %d: Expected nil error, found: %v", testRank, foundErr)
 		}
@@ -16,10 +16,13 @@
 
 package cmd
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 // listObjects - wrapper function implemented over file tree walk.
-func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (xl xlObjects) listObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	// Default is recursive, if delimiter is set then list non recursive.
 	recursive := true
 	if delimiter == slashSeparator {
@@ -39,7 +42,15 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 	var eof bool
 	var nextMarker string
 	for i := 0; i < maxKeys; {
-		walkResult, ok := <-walkResultCh
+		var walkResult treeWalkResult
+		var ok bool
+		select {
+		case walkResult, ok = <-walkResultCh:
+		case <-ctx.Done():
+			// Nobody waits for the listing anymore, end the walk.
+			close(endWalkCh)
+			return ListObjectsInfo{}, traceError(ctx.Err())
+		}
 		if !ok {
 			// Closed channel.
 			eof = true
@@ -99,7 +110,7 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 }
 
 // ListObjects - list all objects at prefix, delimited by '/'.
-func (xl xlObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (xl xlObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, xl); err != nil {
 		return ListObjectsInfo{}, err
 	}
@@ -123,7 +134,7 @@ func (xl xlObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	}
 
 	// Initiate a list operation, if successful filter and return quickly.
-	listObjInfo, err := xl.listObjects(bucket, prefix, marker, delimiter, maxKeys)
+	listObjInfo, err := xl.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
 	if err == nil {
 		// We got the entries successfully return.
 		return listObjInfo, nil
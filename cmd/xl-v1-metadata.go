@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"path"
@@ -186,6 +187,16 @@ func objectPartIndex(parts []objectPartInfo, partNumber int) int {
 	return -1
 }
 
+// objectPartIndexes - returns the index of every object part by part
+// number, for lookups of many parts such as on complete.
+func objectPartIndexes(parts []objectPartInfo) map[int]int {
+	partIndexes := make(map[int]int, len(parts))
+	for i, part := range parts {
+		partIndexes[part.Number] = i
+	}
+	return partIndexes
+}
+
 // AddObjectPart - add a new object part in order.
 func (m *xlMetaV1) AddObjectPart(partNumber int, partName string, partETag string, partSize int64) {
 	partInfo := objectPartInfo{
@@ -306,7 +317,7 @@ func writeXLMetadata(disk StorageAPI, bucket, prefix string, xlMeta xlMetaV1) er
 		return traceError(err)
 	}
 	// Persist marshalled data.
-	return traceError(disk.AppendFile(bucket, jsonFile, metadataBytes))
+	return traceError(disk.AppendFile(context.Background(), bucket, jsonFile, metadataBytes))
 }
 
 // deleteAllXLMetadata - deletes all partially written `xl.json` depending on errs.
@@ -331,7 +342,8 @@ func deleteAllXLMetadata(disks []StorageAPI, bucket, prefix string, errs []error
 }
 
 // Rename `xl.json` content to destination location for each disk in order.
-func renameXLMetadata(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, quorum int) error {
+// Returns the disks on which `xl.json` was renamed.
+func renameXLMetadata(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, quorum int) ([]StorageAPI, error) {
 	isDir := false
 	srcXLJSON := path.Join(srcEntry, xlMetaJSONFile)
 	dstXLJSON := path.Join(dstEntry, xlMetaJSONFile)
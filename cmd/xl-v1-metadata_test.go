@@ -103,6 +103,28 @@ func TestObjectPartIndex(t *testing.T) {
 	}
 }
 
+// Test objectPartIndexes() agrees with objectPartIndex() for every part.
+func TestObjectPartIndexes(t *testing.T) {
+	xlMeta := newXLMetaV1("test-object", 8, 8)
+	for _, partNum := range []int{2, 1, 5, 4, 7} {
+		partNumString := strconv.Itoa(partNum)
+		xlMeta.AddObjectPart(partNum, "part."+partNumString, "etag."+partNumString, int64(partNum+humanize.MiByte))
+	}
+
+	partIndexes := objectPartIndexes(xlMeta.Parts)
+	if len(partIndexes) != len(xlMeta.Parts) {
+		t.Fatalf("expected %d indexes, got %d", len(xlMeta.Parts), len(partIndexes))
+	}
+	for _, part := range xlMeta.Parts {
+		if index := objectPartIndex(xlMeta.Parts, part.Number); partIndexes[part.Number] != index {
+			t.Fatalf("part %d: expected = %d, got: %d", part.Number, index, partIndexes[part.Number])
+		}
+	}
+	if _, ok := partIndexes[6]; ok {
+		t.Fatal("expected no index for part 6")
+	}
+}
+
 // Test xlMetaV1.ObjectToPartOffset().
 func TestObjectToPartOffset(t *testing.T) {
 	// Setup.
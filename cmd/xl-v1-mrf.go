@@ -0,0 +1,247 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	// Objects written with reduced redundancy, saved under the meta
+	// bucket to outlive restarts.
+	mrfConfigFile = "mrf.json"
+
+	// Interval between attempts at healing objects written with
+	// reduced redundancy.
+	mrfHealInterval = 10 * time.Second
+
+	// Maximum number of objects tracked, writes beyond are left to
+	// the next heal pass.
+	maxMRFEntries = 100000
+)
+
+// mrfEntry - object written with reduced redundancy.
+type mrfEntry struct {
+	Bucket string    `json:"bucket"`
+	Object string    `json:"object"`
+	Since  time.Time `json:"since"`
+}
+
+// mrfQueue - most recently failed writes, objects written while some
+// of the disks were unavailable. Objects are healed oldest first as
+// soon as all the disks are back, before waiting for a heal pass. The
+// queue is saved by the worker, running only while objects are queued.
+type mrfQueue struct {
+	mutex    sync.Mutex
+	interval time.Duration
+
+	// Heals an object, returns true once written with full redundancy.
+	heal func(bucket, object string) bool
+	// Saves the queue.
+	save func(entries []mrfEntry) error
+
+	entries []mrfEntry
+	pending map[healEntry]struct{}
+	dirty   bool
+	running bool
+}
+
+// newMRFQueue - initialize a queue of objects written with reduced
+// redundancy.
+func newMRFQueue(heal func(bucket, object string) bool, save func(entries []mrfEntry) error) *mrfQueue {
+	return &mrfQueue{
+		interval: mrfHealInterval,
+		heal:     heal,
+		save:     save,
+		pending:  make(map[healEntry]struct{}),
+	}
+}
+
+// add - queues objects for healing, objects already queued keep
+// their place.
+func (q *mrfQueue) add(entries ...mrfEntry) {
+	if q == nil {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for _, entry := range entries {
+		key := healEntry{entry.Bucket, entry.Object}
+		if _, ok := q.pending[key]; ok {
+			continue
+		}
+		if len(q.entries) >= maxMRFEntries {
+			break
+		}
+		q.pending[key] = struct{}{}
+		q.entries = append(q.entries, entry)
+		q.dirty = true
+	}
+	if len(q.entries) > 0 && !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+// list - returns the queued objects, oldest first.
+func (q *mrfQueue) list() []mrfEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return append([]mrfEntry(nil), q.entries...)
+}
+
+// backlog - returns the count of queued objects.
+func (q *mrfQueue) backlog() int {
+	if q == nil {
+		return 0
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.entries)
+}
+
+// run - heals queued objects every interval until the queue is empty.
+func (q *mrfQueue) run() {
+	for {
+		time.Sleep(q.interval)
+
+		// Objects are queued by disk failures, and healed only
+		// after all disks are back. Stop at the first object
+		// not healed, the others waiting for the same disks.
+		healed := make(map[healEntry]struct{})
+		for _, entry := range q.list() {
+			var ok bool
+			globalBackgroundOps.Do(func() error {
+				ok = q.heal(entry.Bucket, entry.Object)
+				return nil
+			})
+			if !ok {
+				break
+			}
+			healed[healEntry{entry.Bucket, entry.Object}] = struct{}{}
+		}
+
+		q.mutex.Lock()
+		if len(healed) > 0 {
+			entries := q.entries[:0]
+			for _, entry := range q.entries {
+				key := healEntry{entry.Bucket, entry.Object}
+				if _, ok := healed[key]; ok {
+					delete(q.pending, key)
+					continue
+				}
+				entries = append(entries, entry)
+			}
+			q.entries = entries
+			q.dirty = true
+		}
+		dirty := q.dirty
+		entries := append([]mrfEntry(nil), q.entries...)
+		q.dirty = false
+		q.mutex.Unlock()
+
+		if dirty {
+			if err := q.save(entries); err != nil {
+				errorIf(err, "Unable to save objects written with reduced redundancy.")
+				q.mutex.Lock()
+				q.dirty = true
+				q.mutex.Unlock()
+			}
+		}
+
+		q.mutex.Lock()
+		if len(q.entries) == 0 && !q.dirty {
+			q.running = false
+			q.mutex.Unlock()
+			return
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// addPartialWrite - queues bucket/object for healing if it was not
+// written on all disks, onlineDisks being the disks it was written on.
+func (xl xlObjects) addPartialWrite(bucket, object string, onlineDisks []StorageAPI) {
+	if diskCount(onlineDisks) == len(xl.storageDisks) {
+		return
+	}
+	// Writes of the queue itself are not tracked.
+	if bucket == minioMetaBucket && object == mrfConfigFile {
+		return
+	}
+	xl.mrf.add(mrfEntry{Bucket: bucket, Object: object, Since: time.Now().UTC()})
+}
+
+// healPartialWrite - heals an object written with reduced redundancy,
+// returns true once the object is on all disks or was removed.
+func (xl xlObjects) healPartialWrite(bucket, object string) bool {
+	if err := xl.HealObject(context.Background(), bucket, object); err != nil {
+		return isErrObjectNotFound(err)
+	}
+	// Disks still unavailable are not healed.
+	_, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	for _, err := range errs {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// readMRFEntries - reads the saved queue of objects written with
+// reduced redundancy.
+func (xl xlObjects) readMRFEntries() ([]mrfEntry, error) {
+	// Acquire a read lock on the queue before reading.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, mrfConfigFile)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	if err := xl.GetObject(context.Background(), minioMetaBucket, mrfConfigFile, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) {
+			return nil, nil
+		}
+		return nil, errorCause(err)
+	}
+	var entries []mrfEntry
+	if err := json.Unmarshal(buffer.Bytes(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// writeMRFEntries - saves the queue of objects written with reduced
+// redundancy.
+func (xl xlObjects) writeMRFEntries(entries []mrfEntry) error {
+	buf, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	// Acquire a write lock on the queue before modifying.
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, mrfConfigFile)
+	objLock.Lock()
+	defer objLock.Unlock()
+	if _, err = xl.PutObject(context.Background(), minioMetaBucket, mrfConfigFile, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		return errorCause(err)
+	}
+	return nil
+}
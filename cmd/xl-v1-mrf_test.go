@@ -0,0 +1,153 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// Tests objects written while a disk is offline are healed once the
+// disk is back.
+func TestXLMRFHeal(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	xl.mrf.interval = 10 * time.Millisecond
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("written with reduced redundancy")
+
+	// Objects written on all disks are not tracked.
+	if _, err = obj.PutObject(context.Background(), bucket, "healthy", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if entries := xl.mrf.list(); len(entries) != 0 {
+		t.Fatalf("Expected no objects to be tracked, got %v", entries)
+	}
+
+	// Write with the first disk offline.
+	disk := xl.storageDisks[0]
+	xl.storageDisks[0] = nil
+	if _, err = obj.PutObject(context.Background(), bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	entries := xl.mrf.list()
+	if len(entries) != 1 || entries[0].Bucket != bucket || entries[0].Object != object {
+		t.Fatalf("Expected %s/%s to be tracked, got %v", bucket, object, entries)
+	}
+
+	// The queue is saved while the disk is offline.
+	for i := 0; ; i++ {
+		entries, err = xl.readMRFEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 1 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected the tracked objects to be saved")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Bring the disk back, the object is healed and untracked.
+	xl.storageDisks[0] = disk
+	for i := 0; len(xl.mrf.list()) != 0; i++ {
+		if i == 100 {
+			t.Fatal("Object written with reduced redundancy was not healed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err = readXLMeta(disk, bucket, object); err != nil {
+		t.Fatalf("Expected the object to be healed, %v", err)
+	}
+	// Wait for the worker to save the empty queue and stop.
+	for i := 0; ; i++ {
+		xl.mrf.mutex.Lock()
+		running := xl.mrf.running
+		xl.mrf.mutex.Unlock()
+		if !running {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected the worker to stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if entries, err = xl.readMRFEntries(); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected no saved objects, got %v, %v", entries, err)
+	}
+}
+
+// Tests queued objects keep their place and removed objects are
+// dropped.
+func TestMRFQueue(t *testing.T) {
+	healed := make(chan string, 3)
+	var saved []mrfEntry
+	queue := newMRFQueue(func(bucket, object string) bool {
+		healed <- pathJoin(bucket, object)
+		return true
+	}, func(entries []mrfEntry) error {
+		saved = entries
+		return nil
+	})
+	queue.interval = time.Millisecond
+
+	now := time.Now().UTC()
+	queue.add(mrfEntry{"bucket", "a", now}, mrfEntry{"bucket", "b", now}, mrfEntry{"bucket", "a", now})
+	for _, expected := range []string{"bucket/a", "bucket/b"} {
+		if object := <-healed; object != expected {
+			t.Fatalf("Expected %s to be healed, got %s", expected, object)
+		}
+	}
+	for i := 0; ; i++ {
+		queue.mutex.Lock()
+		running := queue.running
+		queue.mutex.Unlock()
+		if !running {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected the worker to stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(saved) != 0 || len(queue.list()) != 0 {
+		t.Fatalf("Expected an empty queue, got %v", queue.list())
+	}
+	select {
+	case object := <-healed:
+		t.Fatalf("Expected objects to be healed once, %s healed again", object)
+	default:
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -38,7 +39,7 @@ func TestUpdateUploadJSON(t *testing.T) {
 	defer removeRoots(fsDirs)
 
 	bucket, object := "bucket", "object"
-	err = obj.MakeBucket(bucket)
+	err = obj.MakeBucket(context.Background(), bucket)
 	if err != nil {
 		t.Fatal(err)
 	}
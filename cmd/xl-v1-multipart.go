@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -32,13 +33,14 @@ import (
 )
 
 // listMultipartUploads - lists all multipart uploads.
-func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+func (xl xlObjects) listMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
 	result := ListMultipartsInfo{
-		IsTruncated: true,
-		MaxUploads:  maxUploads,
-		KeyMarker:   keyMarker,
-		Prefix:      prefix,
-		Delimiter:   delimiter,
+		IsTruncated:    true,
+		MaxUploads:     maxUploads,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
 	}
 
 	recursive := true
@@ -64,7 +66,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 	// uploadIDMarker first.
 	if uploadIDMarker != "" {
 		// hold lock on keyMarker path
-		keyMarkerLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+		keyMarkerLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 			pathJoin(bucket, keyMarker))
 		keyMarkerLock.RLock()
 		for _, disk := range xl.getLoadBalancedDisks() {
@@ -82,7 +84,12 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		}
 		keyMarkerLock.RUnlock()
 		if err != nil {
-			return ListMultipartsInfo{}, err
+			// All the uploads of keyMarker were completed or
+			// aborted since listed, proceed with the next keys.
+			if !isErrIgnored(err, xlTreeWalkIgnoredErrs...) {
+				return ListMultipartsInfo{}, err
+			}
+			uploads, err = nil, nil
 		}
 		maxUploads = maxUploads - len(uploads)
 	}
@@ -112,7 +119,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 				if isErrIgnored(walkResult.err, xlTreeWalkIgnoredErrs...) {
 					continue
 				}
-				return ListMultipartsInfo{}, err
+				return ListMultipartsInfo{}, walkResult.err
 			}
 			entry := strings.TrimPrefix(walkResult.entry, retainSlash(bucket))
 			// For an entry looking like a directory, store and
@@ -123,7 +130,9 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 				})
 				maxUploads--
 				if maxUploads == 0 {
-					eof = true
+					if walkResult.end {
+						eof = true
+					}
 					break
 				}
 				continue
@@ -134,7 +143,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 
 			// For the new object entry we get all its
 			// pending uploadIDs.
-			entryLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+			entryLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 				pathJoin(bucket, entry))
 			entryLock.RLock()
 			var disk StorageAPI
@@ -184,10 +193,11 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		result.NextUploadIDMarker = uploadID
 	}
 
-	if !eof {
+	if !eof && walkerCh != nil {
 		// Save the go-routine state in the pool so that it can continue from where it left off on
-		// the next request.
-		xl.listPool.Set(listParams{bucket, recursive, result.NextKeyMarker, prefix, heal}, walkerCh, walkerDoneCh)
+		// the next request, which lists from the next key marker.
+		nextMarkerPath := pathJoin(bucket, result.NextKeyMarker)
+		xl.listPool.Set(listParams{minioMetaMultipartBucket, recursive, nextMarkerPath, multipartPrefixPath, heal}, walkerCh, walkerDoneCh)
 	}
 
 	result.IsTruncated = !eof
@@ -208,12 +218,12 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // Implements S3 compatible ListMultipartUploads API. The resulting
 // ListMultipartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (xl xlObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+func (xl xlObjects) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
 	if err := checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter, xl); err != nil {
 		return ListMultipartsInfo{}, err
 	}
 
-	return xl.listMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+	return xl.listMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 }
 
 // newMultipartUpload - wrapper for initializing a new multipart
@@ -224,7 +234,7 @@ func (xl xlObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // '.minio.sys/multipart/bucket/object/uploads.json' on all the
 // disks. `uploads.json` carries metadata regarding on-going multipart
 // operation(s) on the object.
-func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[string]string) (string, error) {
+func (xl xlObjects) newMultipartUpload(ctx context.Context, bucket string, object string, meta map[string]string) (string, error) {
 	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
 	// If not set default to "application/octet-stream"
 	if meta["content-type"] == "" {
@@ -242,7 +252,7 @@ func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[st
 
 	// This lock needs to be held for any changes to the directory
 	// contents of ".minio.sys/multipart/object/"
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
@@ -261,7 +271,7 @@ func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[st
 
 	// Attempt to rename temp upload object to actual upload path
 	// object
-	if rErr := renameObject(xl.storageDisks, minioMetaTmpBucket, tempUploadIDPath, minioMetaMultipartBucket, uploadIDPath, xl.writeQuorum); rErr != nil {
+	if _, rErr := renameObject(xl.storageDisks, minioMetaTmpBucket, tempUploadIDPath, minioMetaMultipartBucket, uploadIDPath, xl.writeQuorum); rErr != nil {
 		return "", toObjectErr(rErr, minioMetaMultipartBucket, uploadIDPath)
 	}
 
@@ -279,7 +289,7 @@ func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[st
 // subsequent request each UUID is unique.
 //
 // Implements S3 compatible initiate multipart API.
-func (xl xlObjects) NewMultipartUpload(bucket, object string, meta map[string]string) (string, error) {
+func (xl xlObjects) NewMultipartUpload(ctx context.Context, bucket, object string, meta map[string]string) (string, error) {
 	if err := checkNewMultipartArgs(bucket, object, xl); err != nil {
 		return "", err
 	}
@@ -287,7 +297,7 @@ func (xl xlObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 	if meta == nil {
 		meta = make(map[string]string)
 	}
-	return xl.newMultipartUpload(bucket, object, meta)
+	return xl.newMultipartUpload(ctx, bucket, object, meta)
 }
 
 // PutObjectPart - reads incoming stream and internally erasure codes
@@ -295,7 +305,7 @@ func (xl xlObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 // of the multipart transaction.
 //
 // Implements S3 compatible Upload Part API.
-func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+func (xl xlObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
 	if err := checkPutObjectPartArgs(bucket, object, xl); err != nil {
 		return "", err
 	}
@@ -305,7 +315,7 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	uploadIDPath := pathJoin(bucket, object, uploadID)
 
 	// pre-check upload id lock.
-	preUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	preUploadIDLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, uploadIDPath)
 	preUploadIDLock.RLock()
 	// Validates if upload ID exists.
 	if !xl.isUploadIDExists(bucket, object, uploadID) {
@@ -380,7 +390,7 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	}
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
+	sizeWritten, checkSums, err := erasureCreateFile(ctx, onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
 	if err != nil {
 		return "", toObjectErr(err, bucket, object)
 	}
@@ -414,7 +424,7 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	}
 
 	// post-upload check (write) lock
-	postUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	postUploadIDLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket, uploadIDPath)
 	postUploadIDLock.Lock()
 	defer postUploadIDLock.Unlock()
 
@@ -496,6 +506,7 @@ func (xl xlObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 	result.Bucket = bucket
 	result.Object = object
 	result.UploadID = uploadID
+	result.PartNumberMarker = partNumberMarker
 	result.MaxParts = maxParts
 
 	// For empty number of parts or maxParts as zero, return right here.
@@ -509,11 +520,7 @@ func (xl xlObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 	}
 
 	// Only parts with higher part numbers will be listed.
-	partIdx := objectPartIndex(xlParts, partNumberMarker)
-	parts := xlParts
-	if partIdx != -1 {
-		parts = xlParts[partIdx+1:]
-	}
+	parts := partsAfterMarker(xlParts, partNumberMarker)
 	count := maxParts
 	for _, part := range parts {
 		var fi FileInfo
@@ -550,14 +557,14 @@ func (xl xlObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 // Implements S3 compatible ListObjectParts API. The resulting
 // ListPartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (xl xlObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+func (xl xlObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
 	if err := checkListPartsArgs(bucket, object, xl); err != nil {
 		return ListPartsInfo{}, err
 	}
 
 	// Hold lock so that there is no competing
 	// abort-multipart-upload or complete-multipart-upload.
-	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	uploadIDLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object, uploadID))
 	uploadIDLock.Lock()
 	defer uploadIDLock.Unlock()
@@ -575,7 +582,7 @@ func (xl xlObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
 	if err := checkCompleteMultipartArgs(bucket, object, xl); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -586,7 +593,7 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	//
 	// 2) no one does a parallel complete-multipart-upload on this
 	// multipart upload
-	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	uploadIDLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object, uploadID))
 	uploadIDLock.Lock()
 	defer uploadIDLock.Unlock()
@@ -632,17 +639,21 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	// Allocate parts similar to incoming slice.
 	xlMeta.Parts = make([]objectPartInfo, len(parts))
 
+	// Parts are verified against their stored ETags and sizes,
+	// part files are not read again.
+	partIndexes := objectPartIndexes(currentXLMeta.Parts)
+
 	// Validate each part and then commit to disk.
 	for i, part := range parts {
-		partIdx := objectPartIndex(currentXLMeta.Parts, part.PartNumber)
+		partIdx, ok := partIndexes[part.PartNumber]
 		// All parts should have same part number.
-		if partIdx == -1 {
+		if !ok {
 			return ObjectInfo{}, traceError(InvalidPart{})
 		}
 
 		// All parts should have same ETag as previously generated.
 		if currentXLMeta.Parts[partIdx].ETag != part.ETag {
-			return ObjectInfo{}, traceError(BadDigest{})
+			return ObjectInfo{}, traceError(InvalidPart{})
 		}
 
 		// All parts except the last part has to be atleast 5MB.
@@ -713,25 +724,32 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 			// Prefetch the object from disk by triggering a fake GetObject call
 			// Unlike a regular single PutObject,  multipart PutObject is comes in
 			// stages and it is harder to cache.
-			go xl.GetObject(bucket, object, 0, objectSize, ioutil.Discard)
+			go xl.GetObject(ctx, bucket, object, 0, objectSize, ioutil.Discard)
 		}
 	}()
 
 	// Rename if an object already exists to temporary location.
 	uniqueID := mustGetUUID()
-	if xl.isObject(bucket, object) {
+	if globalBucketSecureDelete.Enabled(bucket) && xl.isObject(bucket, object) {
+		// A temporary copy would not be shredded, the existing object
+		// is shredded in place before the new one takes its place.
+		if err = xl.deleteObject(bucket, object); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	} else if xl.isObject(bucket, object) {
 		// NOTE: Do not use online disks slice here.
 		// The reason is that existing object should be purged
 		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, uniqueID, xl.writeQuorum)
+		_, err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, uniqueID, xl.writeQuorum)
 		if err != nil {
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
 	}
 
 	// Remove parts that weren't present in CompleteMultipartUpload request.
+	completedParts := objectPartIndexes(xlMeta.Parts)
 	for _, curpart := range currentXLMeta.Parts {
-		if objectPartIndex(xlMeta.Parts, curpart.Number) == -1 {
+		if _, ok := completedParts[curpart.Number]; !ok {
 			// Delete the missing part files. e.g,
 			// Request 1: NewMultipart
 			// Request 2: PutObjectPart 1
@@ -743,17 +761,21 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	}
 
 	// Rename the multipart object to final location.
-	if err = renameObject(onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, xl.writeQuorum); err != nil {
+	if onlineDisks, err = renameObject(onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, xl.writeQuorum); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
+	// Heal the object first once all disks are back, if written
+	// with reduced redundancy.
+	xl.addPartialWrite(bucket, object, onlineDisks)
+
 	// Delete the previously successfully renamed object.
 	xl.deleteObject(minioMetaTmpBucket, uniqueID)
 
 	// Hold the lock so that two parallel
 	// complete-multipart-uploads do not leave a stale
 	// uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
@@ -768,7 +790,7 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 		Bucket:          bucket,
 		Name:            object,
 		Size:            xlMeta.Stat.Size,
-		ModTime:         xlMeta.Stat.ModTime,
+		ModTime:         sourceModTime(xlMeta.Meta, xlMeta.Stat.ModTime),
 		MD5Sum:          xlMeta.Meta["md5Sum"],
 		ContentType:     xlMeta.Meta["content-type"],
 		ContentEncoding: xlMeta.Meta["content-encoding"],
@@ -783,7 +805,7 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 // transaction, deletes uploadID entry from `uploads.json` and purges
 // the directory at '.minio.sys/multipart/bucket/object/uploadID' holding
 // all the upload parts.
-func (xl xlObjects) abortMultipartUpload(bucket, object, uploadID string) (err error) {
+func (xl xlObjects) abortMultipartUpload(ctx context.Context, bucket, object, uploadID string) (err error) {
 	// Cleanup all uploaded parts.
 	if err = cleanupUploadedParts(bucket, object, uploadID, xl.storageDisks...); err != nil {
 		return toObjectErr(err, bucket, object)
@@ -791,7 +813,7 @@ func (xl xlObjects) abortMultipartUpload(bucket, object, uploadID string) (err e
 
 	// hold lock so we don't compete with a complete, or abort
 	// multipart request.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	objectMPartPathLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object))
 	objectMPartPathLock.Lock()
 	defer objectMPartPathLock.Unlock()
@@ -816,14 +838,14 @@ func (xl xlObjects) abortMultipartUpload(bucket, object, uploadID string) (err e
 // Implements S3 compatible Abort multipart API, slight difference is
 // that this is an atomic idempotent operation. Subsequent calls have
 // no affect and further requests to the same uploadID would not be honored.
-func (xl xlObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+func (xl xlObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
 	if err := checkAbortMultipartArgs(bucket, object, xl); err != nil {
 		return err
 	}
 
 	// Hold lock so that there is no competing
 	// complete-multipart-upload or put-object-part.
-	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+	uploadIDLock := globalNSMutex.NewNSLock(ctx, minioMetaMultipartBucket,
 		pathJoin(bucket, object, uploadID))
 	uploadIDLock.Lock()
 	defer uploadIDLock.Unlock()
@@ -831,6 +853,6 @@ func (xl xlObjects) AbortMultipartUpload(bucket, object, uploadID string) error
 	if !xl.isUploadIDExists(bucket, object, uploadID) {
 		return traceError(InvalidUploadID{UploadID: uploadID})
 	}
-	err := xl.abortMultipartUpload(bucket, object, uploadID)
+	err := xl.abortMultipartUpload(ctx, bucket, object, uploadID)
 	return err
 }
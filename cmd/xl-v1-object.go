@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"hash"
@@ -45,7 +46,7 @@ var objectOpIgnoredErrs = []error{
 // CopyObject - copy object source object to destination object.
 // if source object and destination object are same we only
 // update metadata.
-func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+func (xl xlObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, srcBucket, srcObject)
 	// Do we have read quorum?
@@ -89,16 +90,17 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 		// Rename atomically `xl.json` from tmp location to destination for each disk.
-		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
+		if onlineDisks, err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
+		xl.addPartialWrite(srcBucket, srcObject, onlineDisks)
 
 		objInfo := ObjectInfo{
 			IsDir:           false,
 			Bucket:          srcBucket,
 			Name:            srcObject,
 			Size:            xlMeta.Stat.Size,
-			ModTime:         xlMeta.Stat.ModTime,
+			ModTime:         sourceModTime(xlMeta.Meta, xlMeta.Stat.ModTime),
 			MD5Sum:          xlMeta.Meta["md5Sum"],
 			ContentType:     xlMeta.Meta["content-type"],
 			ContentEncoding: xlMeta.Meta["content-encoding"],
@@ -116,7 +118,7 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 
 	go func() {
 		startOffset := int64(0) // Read the whole file.
-		if gerr := xl.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+		if gerr := xl.GetObject(ctx, srcBucket, srcObject, startOffset, length, throttleCopyWriter(pipeWriter)); gerr != nil {
 			errorIf(gerr, "Unable to read %s of the object `%s/%s`.", srcBucket, srcObject)
 			pipeWriter.CloseWithError(toObjectErr(gerr, srcBucket, srcObject))
 			return
@@ -124,7 +126,7 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
 	}()
 
-	objInfo, err := xl.PutObject(dstBucket, dstObject, length, pipeReader, metadata, "")
+	objInfo, err := xl.PutObject(ctx, dstBucket, dstObject, length, pipeReader, metadata, "")
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -141,7 +143,7 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 //
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
-func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+func (xl xlObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer) error {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -176,6 +178,13 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		return err
 	}
 
+	// Objects missing or outdated on some disks are read from the
+	// others, and healed once read.
+	needsHeal := xlShouldHeal(metaArr, errs)
+	if needsHeal {
+		markReadHealing(ctx)
+	}
+
 	// Reorder online disks based on erasure distribution order.
 	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, onlineDisks)
 
@@ -228,8 +237,9 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 			return traceError(err)
 		} // Cache has not been found, fill the cache.
 
-		// Cache is only set if whole object is being read.
-		if startOffset == 0 && length == xlMeta.Stat.Size {
+		// Cache is only set if whole object is being read, under the
+		// lock for the data to be of a single version.
+		if startOffset == 0 && length == xlMeta.Stat.Size && !isLockFreeRead(ctx) {
 			// Proceed to set the cache.
 			var newBuffer io.WriteCloser
 			// Create a new entry in memory of length.
@@ -252,6 +262,9 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 	chunkSize := getChunkSize(xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
 	pool := bpool.NewBytePool(chunkSize, len(onlineDisks))
 
+	// Disks failing reads of a block are dropped from onlineDisks.
+	readableDisks := diskCount(onlineDisks)
+
 	// Read from all parts.
 	for ; partIndex <= lastPartIndex; partIndex++ {
 		if length == totalBytesRead {
@@ -287,7 +300,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 
 		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		n, err := erasureReadFile(ctx, mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
 		if err != nil {
 			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
@@ -301,12 +314,18 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		partOffset = 0
 	} // End of read all parts loop.
 
+	// Heal the object right away if it was read from fewer disks
+	// than it should.
+	if needsHeal || diskCount(onlineDisks) < readableDisks {
+		xl.healQueue.enqueue(bucket, object)
+	}
+
 	// Return success.
 	return nil
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
-func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+func (xl xlObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -332,7 +351,7 @@ func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, er
 		Bucket:          bucket,
 		Name:            object,
 		Size:            xlStat.Size,
-		ModTime:         xlStat.ModTime,
+		ModTime:         sourceModTime(xlMetaMap, xlStat.ModTime),
 		MD5Sum:          xlMetaMap["md5Sum"],
 		ContentType:     xlMetaMap["content-type"],
 		ContentEncoding: xlMetaMap["content-encoding"],
@@ -375,8 +394,9 @@ func undoRename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry str
 }
 
 // rename - common function that renamePart and renameObject use to rename
-// the respective underlying storage layer representations.
-func rename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, isDir bool, quorum int) error {
+// the respective underlying storage layer representations. Returns the
+// disks on which the rename succeeded.
+func rename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, isDir bool, quorum int) ([]StorageAPI, error) {
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
 
@@ -412,9 +432,9 @@ func rename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string,
 	if !isDiskQuorum(errs, quorum) {
 		// Undo all the partial rename operations.
 		undoRename(disks, srcBucket, srcEntry, dstBucket, dstEntry, isDir, errs)
-		return traceError(errXLWriteQuorum)
+		return nil, traceError(errXLWriteQuorum)
 	}
-	return reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, quorum)
+	return evalDisks(disks, errs), reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, quorum)
 }
 
 // renamePart - renames a part of the source object to the destination
@@ -423,14 +443,15 @@ func rename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string,
 // its proper location.
 func renamePart(disks []StorageAPI, srcBucket, srcPart, dstBucket, dstPart string, quorum int) error {
 	isDir := false
-	return rename(disks, srcBucket, srcPart, dstBucket, dstPart, isDir, quorum)
+	_, err := rename(disks, srcBucket, srcPart, dstBucket, dstPart, isDir, quorum)
+	return err
 }
 
 // renameObject - renames all source objects to destination object
 // across all disks in parallel. Additionally if we have errors and do
 // not have a readQuorum partially renamed files are renamed back to
-// its proper location.
-func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject string, quorum int) error {
+// its proper location. Returns the disks on which the object was renamed.
+func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject string, quorum int) ([]StorageAPI, error) {
 	isDir := true
 	return rename(disks, srcBucket, srcObject, dstBucket, dstObject, isDir, quorum)
 }
@@ -439,7 +460,7 @@ func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject
 // until EOF, erasure codes the data across all disk and additionally
 // writes `xl.json` which carries the necessary metadata for future
 // object operations.
-func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+func (xl xlObjects) PutObject(ctx context.Context, bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
 	if err = checkPutObjectArgs(bucket, object, xl); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -520,7 +541,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	}
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
+	sizeWritten, checkSums, err := erasureCreateFile(ctx, onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempErasureObj)
 	}
@@ -578,14 +599,20 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 
 	// Rename if an object already exists to temporary location.
 	newUniqueID := mustGetUUID()
-	if xl.isObject(bucket, object) {
+	if globalBucketSecureDelete.Enabled(bucket) && xl.isObject(bucket, object) {
+		// A temporary copy would not be shredded, the existing object
+		// is shredded in place before the new one takes its place.
+		if err = xl.deleteObject(bucket, object); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	} else if xl.isObject(bucket, object) {
 		// Delete the temporary copy of the object that existed before this PutObject request.
 		defer xl.deleteObject(minioMetaTmpBucket, newUniqueID)
 
 		// NOTE: Do not use online disks slice here.
 		// The reason is that existing object should be purged
 		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
+		_, err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
 		if err != nil {
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
@@ -616,11 +643,15 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	}
 
 	// Rename the successfully written temporary object to final location.
-	err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
+	onlineDisks, err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
+	// Heal the object first once all disks are back, if written
+	// with reduced redundancy.
+	xl.addPartialWrite(bucket, object, onlineDisks)
+
 	// Once we have successfully renamed the object, Close the buffer which would
 	// save the object on cache.
 	if size > 0 && xl.objCacheEnabled && newBuffer != nil {
@@ -632,7 +663,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		Bucket:          bucket,
 		Name:            object,
 		Size:            xlMeta.Stat.Size,
-		ModTime:         xlMeta.Stat.ModTime,
+		ModTime:         sourceModTime(xlMeta.Meta, xlMeta.Stat.ModTime),
 		MD5Sum:          xlMeta.Meta["md5Sum"],
 		ContentType:     xlMeta.Meta["content-type"],
 		ContentEncoding: xlMeta.Meta["content-encoding"],
@@ -680,10 +711,24 @@ func (xl xlObjects) deleteObject(bucket, object string) error {
 	return nil
 }
 
+// RenamePrefix - renames all the objects under prefix to newPrefix
+// by renaming the prefix directory on all disks.
+func (xl xlObjects) RenamePrefix(ctx context.Context, bucket, prefix, newPrefix string) error {
+	if err := checkRenamePrefixArgs(ctx, bucket, prefix, newPrefix, xl); err != nil {
+		return err
+	}
+
+	isDir := true
+	if _, err := rename(xl.storageDisks, bucket, prefix, bucket, newPrefix, isDir, xl.writeQuorum); err != nil {
+		return toObjectErr(err, bucket, prefix)
+	}
+	return nil
+}
+
 // DeleteObject - deletes an object, this call doesn't necessary reply
 // any error as it is not necessary for the handler to reply back a
 // response to the client request.
-func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
+func (xl xlObjects) DeleteObject(ctx context.Context, bucket, object string) (err error) {
 	if err = checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -42,28 +43,64 @@ func TestRepeatPutObjectPart(t *testing.T) {
 	// cleaning up of temporary test directories
 	defer removeRoots(disks)
 
-	err = objLayer.MakeBucket("bucket1")
+	err = objLayer.MakeBucket(context.Background(), "bucket1")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	uploadID, err := objLayer.NewMultipartUpload("bucket1", "mpartObj1", nil)
+	uploadID, err := objLayer.NewMultipartUpload(context.Background(), "bucket1", "mpartObj1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	fiveMBBytes := bytes.Repeat([]byte("a"), 5*humanize.MiByte)
 	md5Hex := getMD5Hash(fiveMBBytes)
-	_, err = objLayer.PutObjectPart("bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
+	_, err = objLayer.PutObjectPart(context.Background(), "bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
 	if err != nil {
 		t.Fatal(err)
 	}
 	// PutObjectPart should succeed even if part already exists. ref: https://github.com/minio/minio/issues/1930
-	_, err = objLayer.PutObjectPart("bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
+	_, err = objLayer.PutObjectPart(context.Background(), "bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
+// Tests that object reads and writes are abandoned once their context
+// is done.
+func TestXLObjectCanceledContext(t *testing.T) {
+	objLayer, disks, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+
+	if err = objLayer.MakeBucket(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), humanize.MiByte)
+	if _, err = objLayer.PutObject(context.Background(), "bucket", "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buffer bytes.Buffer
+	if err = objLayer.GetObject(ctx, "bucket", "object", 0, int64(len(data)), &buffer); errorCause(err) != context.Canceled {
+		t.Errorf("Expected GetObject to fail with %v, got %v", context.Canceled, err)
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no data to be read, got %d bytes", buffer.Len())
+	}
+
+	if _, err = objLayer.PutObject(ctx, "bucket", "canceled", int64(len(data)), bytes.NewReader(data), nil, ""); errorCause(err) != context.Canceled {
+		t.Errorf("Expected PutObject to fail with %v, got %v", context.Canceled, err)
+	}
+	if _, err = objLayer.GetObjectInfo(context.Background(), "bucket", "canceled"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected the canceled object not to be created, got %v", err)
+	}
+}
+
 func TestXLDeleteObjectBasic(t *testing.T) {
 	testCases := []struct {
 		bucket      string
@@ -86,18 +123,18 @@ func TestXLDeleteObjectBasic(t *testing.T) {
 	}
 
 	// Make bucket for Test 7 to pass
-	err = xl.MakeBucket("bucket")
+	err = xl.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Create object "obj" under bucket "bucket" for Test 7 to pass
-	_, err = xl.PutObject("bucket", "obj", int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = xl.PutObject(context.Background(), "bucket", "obj", int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatalf("XL Object upload failed: <ERROR> %s", err)
 	}
 	for i, test := range testCases {
-		actualErr := xl.DeleteObject(test.bucket, test.object)
+		actualErr := xl.DeleteObject(context.Background(), test.bucket, test.object)
 		actualErr = errorCause(actualErr)
 		if test.expectedErr != nil && actualErr != test.expectedErr {
 			t.Errorf("Test %d: Expected to fail with %s, but failed with %s", i+1, test.expectedErr, actualErr)
@@ -120,7 +157,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,7 +165,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create object "obj" under bucket "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -137,13 +174,13 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	for i := range xl.storageDisks[:7] {
 		xl.storageDisks[i] = newNaughtyDisk(xl.storageDisks[i].(*retryStorage), nil, errFaultyDisk)
 	}
-	err = obj.DeleteObject(bucket, object)
+	err = obj.DeleteObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Create "obj" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -151,7 +188,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	// Remove one more disk to 'lose' quorum, by setting it to nil.
 	xl.storageDisks[7] = nil
 	xl.storageDisks[8] = nil
-	err = obj.DeleteObject(bucket, object)
+	err = obj.DeleteObject(context.Background(), bucket, object)
 	err = errorCause(err)
 	if err != toObjectErr(errXLWriteQuorum, bucket, object) {
 		t.Errorf("Expected deleteObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -170,7 +207,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -178,7 +215,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create "object" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -202,7 +239,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 			}
 		}
 		// Fetch object from store.
-		err = xl.GetObject(bucket, object, 0, int64(len("abcd")), ioutil.Discard)
+		err = xl.GetObject(context.Background(), bucket, object, 0, int64(len("abcd")), ioutil.Discard)
 		err = errorCause(err)
 		if err != toObjectErr(errXLReadQuorum, bucket, object) {
 			t.Errorf("Expected putObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -222,7 +259,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -230,7 +267,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create "object" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -253,7 +290,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 			}
 		}
 		// Upload new content to same object "object"
-		_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+		_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 		err = errorCause(err)
 		if err != toObjectErr(errXLWriteQuorum, bucket, object) {
 			t.Errorf("Expected putObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -273,7 +310,7 @@ func TestHealing(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -288,7 +325,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = obj.PutObject(bucket, object, length, bytes.NewReader(data), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, length, bytes.NewReader(data), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -306,7 +343,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = xl.HealObject(bucket, object)
+	err = xl.HealObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -330,7 +367,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = xl.HealObject(bucket, object)
+	err = xl.HealObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -352,7 +389,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 	// This would create the bucket.
-	err = xl.HealBucket(bucket)
+	err = xl.HealBucket(context.Background(), bucket)
 	if err != nil {
 		t.Fatal(err)
 	}
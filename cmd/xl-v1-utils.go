@@ -112,6 +112,18 @@ func diskCount(disks []StorageAPI) int {
 	return diskCount
 }
 
+// evalDisks - returns the disks without an error, disks with an error
+// being nil.
+func evalDisks(disks []StorageAPI, errs []error) []StorageAPI {
+	newDisks := make([]StorageAPI, len(disks))
+	for index, disk := range disks {
+		if errs[index] == nil {
+			newDisks[index] = disk
+		}
+	}
+	return newDisks
+}
+
 // hashOrder - hashes input key to return returns consistent
 // hashed integer slice. Returned integer order is salted
 // with an input key. This results in consistent order.
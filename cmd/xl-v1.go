@@ -17,12 +17,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime/debug"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/disk"
@@ -71,6 +73,16 @@ type xlObjects struct {
 
 	// Object cache enabled.
 	objCacheEnabled bool
+
+	// Objects found degraded on read, waiting to be healed.
+	healQueue *healQueue
+
+	// Objects written with reduced redundancy, waiting for all the
+	// disks to be back.
+	mrf *mrfQueue
+
+	// Write probes of the disks.
+	probe *diskProbe
 }
 
 // list of all errors that can be ignored in tree walk operation in XL
@@ -82,13 +94,10 @@ func newXLObjectLayer(storageDisks []StorageAPI) (ObjectLayer, error) {
 	objAPI, err := newXLObjects(storageDisks)
 	fatalIf(err, "Unable to initialize XL object layer.")
 
-	// Initialize and load bucket policies.
-	err = initBucketPolicies(objAPI)
-	fatalIf(err, "Unable to load all bucket policies.")
-
-	// Initialize a new event notifier.
-	err = initEventNotifier(objAPI)
-	fatalIf(err, "Unable to initialize event notification.")
+	// Initialize and load the bucket metadata, IAM users and policies
+	// and the event notifier.
+	err = initBucketMetadata(objAPI, false)
+	fatalIf(err, "Unable to initialize the bucket metadata.")
 
 	// Success.
 	return objAPI, nil
@@ -127,6 +136,18 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		listPool:     listPool,
 	}
 
+	// Heal objects found degraded on read in the background.
+	xl.healQueue = newHealQueue(func(bucket, object string) error {
+		return xl.HealObject(context.Background(), bucket, object)
+	})
+
+	// Track objects written with reduced redundancy.
+	xl.mrf = newMRFQueue(func(bucket, object string) bool {
+		return xl.healPartialWrite(bucket, object)
+	}, func(entries []mrfEntry) error {
+		return xl.writeMRFEntries(entries)
+	})
+
 	// Object cache is enabled when _MINIO_CACHE env is missing.
 	// and cache size is > 0.
 	xl.objCacheEnabled = !objCacheDisabled && globalMaxCacheSize > 0
@@ -146,6 +167,10 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to initialize '.minio.sys' meta volume, %s", err)
 	}
 
+	// Mark disks failing writes offline, for ex. remounted read-only.
+	xl.probe = newDiskProbe(xl.storageDisks)
+	xl.probeDisks()
+
 	// Figure out read and write quorum based on number of storage disks.
 	// READ and WRITE quorum is always set to (N/2) number of disks.
 	xl.readQuorum = readQuorum
@@ -156,6 +181,12 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		return xl, err
 	}
 
+	// Resume healing objects written with reduced redundancy before
+	// the restart.
+	entries, err := xl.readMRFEntries()
+	errorIf(err, "Unable to load objects written with reduced redundancy.")
+	xl.mrf.add(entries...)
+
 	// Return successfully initialized object layer.
 	return xl, nil
 }
@@ -247,5 +278,24 @@ func (xl xlObjects) StorageInfo() StorageInfo {
 	storageInfo := getStorageInfo(xl.storageDisks)
 	storageInfo.Backend.ReadQuorum = xl.readQuorum
 	storageInfo.Backend.WriteQuorum = xl.writeQuorum
+	storageInfo.Backend.UnwritableDisks = xl.probe.unwritableCount()
 	return storageInfo
 }
+
+// cacheStats - returns the space accounting of the object cache.
+func (xl xlObjects) cacheStats() (objcache.Stats, bool) {
+	if !xl.objCacheEnabled {
+		return objcache.Stats{}, false
+	}
+	return xl.objCache.Stats(), true
+}
+
+// isCached - returns true if the object last modified at modTime is
+// in the object cache.
+func (xl xlObjects) isCached(bucket, object string, modTime time.Time) bool {
+	if !xl.objCacheEnabled {
+		return false
+	}
+	_, err := xl.objCache.Open(pathJoin(bucket, object), modTime)
+	return err == nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BucketThroughput - bytes per second sent from and received into a
+// bucket, averaged over the last seconds.
+type BucketThroughput struct {
+	SentPerSecond     float64 `json:"sentPerSecond"`
+	ReceivedPerSecond float64 `json:"receivedPerSecond"`
+}
+
+// BandwidthReport - throughput of the buckets transferring data.
+type BandwidthReport struct {
+	Time    time.Time                   `json:"time"`
+	Buckets map[string]BucketThroughput `json:"buckets"`
+	// Err is set on the last report if the stream failed.
+	Err error `json:"-"`
+}
+
+// MonitorBandwidth - Returns a channel of the throughput reports of the
+// buckets, or only of bucket when not empty, sent every second by the
+// server until doneCh is closed.
+func (adm *AdminClient) MonitorBandwidth(bucket string, doneCh <-chan struct{}) (<-chan BandwidthReport, error) {
+	queryVal := url.Values{}
+	queryVal.Set("bandwidth", "")
+	if bucket != "" {
+		queryVal.Set("bucket", bucket)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "monitor")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?bandwidth to stream the reports.
+	resp, err := adm.executeMethod("GET", reqData)
+	if err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer closeResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	reportCh := make(chan BandwidthReport)
+	go func() {
+		defer close(reportCh)
+		// The stream never ends, it is closed instead of drained.
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var report BandwidthReport
+			if err := decoder.Decode(&report); err != nil {
+				report = BandwidthReport{Err: err}
+			}
+			select {
+			case reportCh <- report:
+			case <-doneCh:
+				return
+			}
+			if report.Err != nil {
+				return
+			}
+		}
+	}()
+	return reportCh, nil
+}
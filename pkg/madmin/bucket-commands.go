@@ -0,0 +1,52 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RenameBucket - Renames bucket to newBucket along with its objects,
+// incomplete uploads and configs. newBucket should not exist.
+func (adm *AdminClient) RenameBucket(bucket, newBucket string) error {
+	queryVal := url.Values{}
+	queryVal.Set("rename", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("new-bucket", newBucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "bucket")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?rename to rename the bucket.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
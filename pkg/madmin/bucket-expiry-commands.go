@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// BucketExpiry - number of days after which the objects of a bucket
+// are removed, 0 when they are kept.
+type BucketExpiry struct {
+	Days int `json:"days"`
+}
+
+// GetBucketExpiry - Returns the expiry of the objects of bucket.
+func (adm *AdminClient) GetBucketExpiry(bucket string) (BucketExpiry, error) {
+	queryVal := url.Values{}
+	queryVal.Set("expiry", "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "get")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?expiry&bucket=bucket to fetch the bucket expiry.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketExpiry{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketExpiry{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketExpiry{}, err
+	}
+
+	var expiry BucketExpiry
+	if err = json.Unmarshal(respBytes, &expiry); err != nil {
+		return BucketExpiry{}, err
+	}
+	return expiry, nil
+}
+
+// SetBucketExpiry - Removes the objects of bucket once they are older
+// than days, 0 keeps them.
+func (adm *AdminClient) SetBucketExpiry(bucket string, days int) error {
+	queryVal := url.Values{}
+	queryVal.Set("expiry", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("days", strconv.Itoa(days))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?expiry&bucket=bucket&days=days to set the bucket expiry.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CachePrewarmStatus - progress of the last pre-warm of the object
+// cache of a server along with the space used in the cache.
+type CachePrewarmStatus struct {
+	Bucket    string    `json:"bucket"`
+	Prefixes  []string  `json:"prefixes"`
+	Running   bool      `json:"running"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Error     string    `json:"error,omitempty"`
+
+	// Objects listed under the prefixes so far.
+	Objects int64 `json:"objects"`
+	// Objects cached by the pre-warm or cached already.
+	Cached      int64 `json:"cached"`
+	CachedBytes int64 `json:"cachedBytes"`
+	// Objects empty, too large or not fitting in the space left.
+	Skipped      int64 `json:"skipped"`
+	SkippedBytes int64 `json:"skippedBytes"`
+	// Objects failing to be read.
+	Failed int64 `json:"failed"`
+
+	CacheSize         uint64 `json:"cacheSize"`
+	CacheMaxSize      uint64 `json:"cacheMaxSize"`
+	CacheMaxEntrySize uint64 `json:"cacheMaxEntrySize"`
+	CacheEntries      int    `json:"cacheEntries"`
+}
+
+// CachePrewarm - Starts caching the objects of bucket under prefixes,
+// all the objects of bucket when no prefix is given, in the background.
+func (adm *AdminClient) CachePrewarm(bucket string, prefixes ...string) (CachePrewarmStatus, error) {
+	queryVal := url.Values{}
+	queryVal.Set("cache", "")
+	queryVal.Set("bucket", bucket)
+	for _, prefix := range prefixes {
+		queryVal.Add("prefix", prefix)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "prewarm")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?cache&bucket=bucket&prefix=prefix to start the pre-warm.
+	resp, err := adm.executeMethod("POST", reqData)
+	return parseCachePrewarmStatus(resp, err)
+}
+
+// GetCachePrewarmStatus - Returns the progress of the last pre-warm of
+// the object cache.
+func (adm *AdminClient) GetCachePrewarmStatus() (CachePrewarmStatus, error) {
+	queryVal := url.Values{}
+	queryVal.Set("cache", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "status")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?cache to fetch the pre-warm status.
+	resp, err := adm.executeMethod("GET", reqData)
+	return parseCachePrewarmStatus(resp, err)
+}
+
+// parseCachePrewarmStatus - decodes the pre-warm status of resp.
+func parseCachePrewarmStatus(resp *http.Response, err error) (CachePrewarmStatus, error) {
+	defer closeResponse(resp)
+	if err != nil {
+		return CachePrewarmStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return CachePrewarmStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return CachePrewarmStatus{}, err
+	}
+
+	var status CachePrewarmStatus
+	if err = json.Unmarshal(respBytes, &status); err != nil {
+		return CachePrewarmStatus{}, err
+	}
+	return status, nil
+}
@@ -0,0 +1,53 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ReloadConfig - Re-reads and applies the config on all the servers
+// without restarting them. Credentials in effect are kept unless
+// reloadCredentials is set.
+func (adm *AdminClient) ReloadConfig(reloadCredentials bool) error {
+	queryVal := url.Values{}
+	queryVal.Set("config", "")
+	queryVal.Set("credentials", strconv.FormatBool(reloadCredentials))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "reload")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?config to reload the config.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
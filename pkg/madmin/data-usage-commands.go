@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DataUsageEntry - number, total size and size histogram of objects.
+type DataUsageEntry struct {
+	Objects   uint64            `json:"objects"`
+	Size      uint64            `json:"size"`
+	Histogram map[string]uint64 `json:"histogram,omitempty"`
+}
+
+// BucketUsageInfo - data usage of a bucket and of its first level
+// prefixes.
+type BucketUsageInfo struct {
+	DataUsageEntry
+	Prefixes map[string]DataUsageEntry `json:"prefixes,omitempty"`
+}
+
+// DataUsageInfo - data usage of the buckets, and of the owners of
+// their objects keyed by access key, as of the last crawl.
+type DataUsageInfo struct {
+	LastUpdate time.Time                  `json:"lastUpdate"`
+	Objects    uint64                     `json:"objects"`
+	Size       uint64                     `json:"size"`
+	Buckets    map[string]BucketUsageInfo `json:"buckets,omitempty"`
+	Owners     map[string]DataUsageEntry  `json:"owners,omitempty"`
+}
+
+// GetDataUsageInfo - Returns the data usage of all the buckets, or only
+// of bucket when not empty, and of their first level prefixes.
+func (adm *AdminClient) GetDataUsageInfo(bucket string) (DataUsageInfo, error) {
+	queryVal := url.Values{}
+	queryVal.Set("data-usage", "")
+	if bucket != "" {
+		queryVal.Set("bucket", bucket)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "info")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?data-usage to fetch the data usage.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return DataUsageInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return DataUsageInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return DataUsageInfo{}, err
+	}
+
+	var info DataUsageInfo
+	if err = json.Unmarshal(respBytes, &info); err != nil {
+		return DataUsageInfo{}, err
+	}
+	return info, nil
+}
@@ -0,0 +1,197 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Status of IAM users.
+const (
+	UserEnabled  = "enabled"
+	UserDisabled = "disabled"
+)
+
+// UserInfo - status, attached policies and storage quota of an IAM
+// user, along with the data usage of the objects it owns as of the
+// last data usage crawl.
+type UserInfo struct {
+	Status   string         `json:"status"`
+	Policies []string       `json:"policies,omitempty"`
+	Quota    int64          `json:"quota,omitempty"`
+	Usage    DataUsageEntry `json:"usage"`
+}
+
+// iamRequest - executes the IAM operation op on resource, user or
+// policy, failing on any response but 200 OK.
+func (adm *AdminClient) iamRequest(method, resource, op string, params url.Values, body []byte) ([]byte, error) {
+	queryVal := url.Values{}
+	queryVal.Set(resource, "")
+	for k, v := range params {
+		queryVal[k] = v
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, op)
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+	if body != nil {
+		reqData.contentBody = bytes.NewReader(body)
+		reqData.contentLength = int64(len(body))
+		reqData.contentMD5Bytes = sumMD5(body)
+		reqData.contentSHA256Bytes = sum256(body)
+	}
+
+	resp, err := adm.executeMethod(method, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// AddUser - Adds an enabled IAM user, or changes the secret key of an
+// existing user.
+func (adm *AdminClient) AddUser(accessKey, secretKey string) error {
+	// Disallow sending the secret key if the connection is not secure
+	if !adm.secure {
+		return errors.New("adding users requires HTTPS connection to the server")
+	}
+
+	body, err := json.Marshal(map[string]string{"secretKey": secretKey})
+	if err != nil {
+		return err
+	}
+	// Execute POST on /?user&accessKey=accessKey to add the user.
+	_, err = adm.iamRequest("POST", "user", "add", url.Values{"accessKey": {accessKey}}, body)
+	return err
+}
+
+// RemoveUser - Removes an IAM user.
+func (adm *AdminClient) RemoveUser(accessKey string) error {
+	_, err := adm.iamRequest("POST", "user", "remove", url.Values{"accessKey": {accessKey}}, nil)
+	return err
+}
+
+// SetUserStatus - Enables or disables an IAM user, status is one of
+// UserEnabled or UserDisabled.
+func (adm *AdminClient) SetUserStatus(accessKey, status string) error {
+	params := url.Values{"accessKey": {accessKey}, "state": {status}}
+	_, err := adm.iamRequest("POST", "user", "set-status", params, nil)
+	return err
+}
+
+// SetUserQuota - Sets the storage quota of an IAM user in bytes, 0 for
+// unlimited.
+func (adm *AdminClient) SetUserQuota(accessKey string, quota int64) error {
+	params := url.Values{"accessKey": {accessKey}, "quota": {strconv.FormatInt(quota, 10)}}
+	_, err := adm.iamRequest("POST", "user", "set-quota", params, nil)
+	return err
+}
+
+// ListUsers - Returns the IAM users keyed by their access keys.
+func (adm *AdminClient) ListUsers() (map[string]UserInfo, error) {
+	respBytes, err := adm.iamRequest("GET", "user", "list", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]UserInfo)
+	if err = json.Unmarshal(respBytes, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AccessKeyUsage - when and from which source IP an access key last
+// authenticated a request, along with the server it was sent to.
+type AccessKeyUsage struct {
+	LastUsed time.Time `json:"lastUsed"`
+	SourceIP string    `json:"sourceIP"`
+	Node     string    `json:"node,omitempty"`
+}
+
+// GetAccessKeysLastUsed - Returns the last use of the access keys of the
+// IAM users and of the server, keyed by access key. Access keys unused
+// since the servers started are not returned.
+func (adm *AdminClient) GetAccessKeysLastUsed() (map[string]AccessKeyUsage, error) {
+	respBytes, err := adm.iamRequest("GET", "user", "last-used", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	lastUsed := make(map[string]AccessKeyUsage)
+	if err = json.Unmarshal(respBytes, &lastUsed); err != nil {
+		return nil, err
+	}
+	return lastUsed, nil
+}
+
+// AddPolicy - Adds or replaces the IAM policy name, policy is a JSON
+// policy document in the format of bucket policies.
+func (adm *AdminClient) AddPolicy(name string, policy []byte) error {
+	_, err := adm.iamRequest("POST", "policy", "add", url.Values{"name": {name}}, policy)
+	return err
+}
+
+// RemovePolicy - Removes the IAM policy name, detaching it from all
+// the users.
+func (adm *AdminClient) RemovePolicy(name string) error {
+	_, err := adm.iamRequest("POST", "policy", "remove", url.Values{"name": {name}}, nil)
+	return err
+}
+
+// ListPolicies - Returns the JSON documents of the canned and added
+// IAM policies keyed by their names.
+func (adm *AdminClient) ListPolicies() (map[string]json.RawMessage, error) {
+	respBytes, err := adm.iamRequest("GET", "policy", "list", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(respBytes, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// AttachPolicy - Attaches the IAM policy name to a user.
+func (adm *AdminClient) AttachPolicy(accessKey, name string) error {
+	params := url.Values{"accessKey": {accessKey}, "name": {name}}
+	_, err := adm.iamRequest("POST", "policy", "attach", params, nil)
+	return err
+}
+
+// DetachPolicy - Detaches the IAM policy name from a user.
+func (adm *AdminClient) DetachPolicy(accessKey, name string) error {
+	params := url.Values{"accessKey": {accessKey}, "name": {name}}
+	_, err := adm.iamRequest("POST", "policy", "detach", params, nil)
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// BucketListingInfo - directory listings of a bucket.
+type BucketListingInfo struct {
+	Active int `json:"active"`
+	Queued int `json:"queued"`
+}
+
+// ListingInfo - limits and current directory listings of a server,
+// zero limits meaning unlimited.
+type ListingInfo struct {
+	MaxListings          int                          `json:"maxListings"`
+	MaxListingsPerBucket int                          `json:"maxListingsPerBucket"`
+	Active               int                          `json:"active"`
+	Queued               int                          `json:"queued"`
+	Buckets              map[string]BucketListingInfo `json:"buckets,omitempty"`
+}
+
+// GetListingInfo - Returns the directory listings limits of the server
+// along with the listings running and waiting to run.
+func (adm *AdminClient) GetListingInfo() (ListingInfo, error) {
+	queryVal := url.Values{}
+	queryVal.Set("listing", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "info")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?listing to fetch the listing info.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return ListingInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ListingInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ListingInfo{}, err
+	}
+
+	var info ListingInfo
+	if err = json.Unmarshal(respBytes, &info); err != nil {
+		return ListingInfo{}, err
+	}
+	return info, nil
+}
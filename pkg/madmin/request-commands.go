@@ -0,0 +1,109 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestInfo - an S3 API request being served.
+type RequestInfo struct {
+	RequestID     string        `json:"requestId"`
+	API           string        `json:"api"`    // S3 API serving the request (GetObject, PutObject...)
+	Bucket        string        `json:"bucket"` // Bucket of the request, if any.
+	Object        string        `json:"object"` // Object of the request, if any.
+	AccessKey     string        `json:"accessKey,omitempty"`
+	SourceIP      string        `json:"sourceIP"`
+	Node          string        `json:"node"`          // Server serving the request.
+	Since         time.Time     `json:"since"`         // Time when the request was received.
+	Duration      time.Duration `json:"duration"`      // Duration since the request was received.
+	BytesSent     int64         `json:"bytesSent"`     // Bytes of the response sent so far.
+	BytesReceived int64         `json:"bytesReceived"` // Bytes of the request body received so far.
+}
+
+// ListRequests - Returns the S3 API requests being served by all the
+// servers, oldest first.
+func (adm *AdminClient) ListRequests() ([]RequestInfo, error) {
+	queryVal := url.Values{}
+	queryVal.Set("request", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?request to list the requests.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []RequestInfo
+	if err = json.Unmarshal(respBytes, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// KillRequest - Kills the S3 API request with requestID, the rest of
+// its request body is not read and the rest of its response is not
+// sent.
+func (adm *AdminClient) KillRequest(requestID string) error {
+	queryVal := url.Values{}
+	queryVal.Set("request", "")
+	queryVal.Set("id", requestID)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "kill")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?request&id=requestID to kill the request.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// ServerMode - operating mode of a server or bucket.
+type ServerMode string
+
+// List of supported operating modes.
+const (
+	// All requests are served.
+	ServerModeNormal ServerMode = "normal"
+	// Requests modifying data are rejected.
+	ServerModeReadOnly ServerMode = "read-only"
+	// All object storage requests are rejected.
+	ServerModeMaintenance ServerMode = "maintenance"
+)
+
+// ServerModeInfo - operating modes of a server and of its buckets
+// having a mode other than normal.
+type ServerModeInfo struct {
+	Server  ServerMode            `json:"server"`
+	Buckets map[string]ServerMode `json:"buckets,omitempty"`
+	// Set on standby servers, serving reads only until promoted.
+	Standby bool `json:"standby,omitempty"`
+}
+
+// GetServerMode - Returns the operating modes in effect on the server.
+func (adm *AdminClient) GetServerMode() (ServerModeInfo, error) {
+	queryVal := url.Values{}
+	queryVal.Set("mode", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "get")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?mode to fetch the server modes.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return ServerModeInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ServerModeInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ServerModeInfo{}, err
+	}
+
+	var info ServerModeInfo
+	if err = json.Unmarshal(respBytes, &info); err != nil {
+		return ServerModeInfo{}, err
+	}
+	return info, nil
+}
+
+// SetServerMode - Sets the operating mode of bucket, or of the whole
+// server when bucket is empty. In a distributed setup, the mode is set
+// on all the servers.
+func (adm *AdminClient) SetServerMode(bucket string, mode ServerMode) error {
+	queryVal := url.Values{}
+	queryVal.Set("mode", "")
+	queryVal.Set("state", string(mode))
+	if bucket != "" {
+		queryVal.Set("bucket", bucket)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?mode&state=mode to set the server mode.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// PromoteStandby - Promotes the standby servers of the cluster to serve
+// all requests, returns the addresses of the promoted servers.
+func (adm *AdminClient) PromoteStandby() ([]string, error) {
+	queryVal := url.Values{}
+	queryVal.Set("mode", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "promote")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?mode to promote the standby servers.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted struct {
+		Promoted []string `json:"promoted"`
+	}
+	if err = json.Unmarshal(respBytes, &promoted); err != nil {
+		return nil, err
+	}
+	return promoted.Promoted, nil
+}
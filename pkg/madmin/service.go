@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // BackendType - represents different backend types.
@@ -57,6 +58,9 @@ type ServiceStatusMetadata struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+
+		// Disks failing write probes, counted as offline.
+		UnwritableDisks int
 	}
 }
 
@@ -97,6 +101,115 @@ func (adm *AdminClient) ServiceStatus() (ServiceStatusMetadata, error) {
 	return storageInfo, nil
 }
 
+// ServerHealth - health of a server of the cluster.
+type ServerHealth struct {
+	Addr   string `json:"addr"`
+	Online bool   `json:"online"`
+	Error  string `json:"error,omitempty"`
+	// Set for standby servers, serving reads only.
+	Standby bool `json:"standby,omitempty"`
+
+	// Time of the server, and its difference with the time of the
+	// server answering the health request.
+	Time         time.Time     `json:"time"`
+	ClockSkew    time.Duration `json:"clockSkew"`
+	ClockWarning string        `json:"clockWarning,omitempty"`
+
+	// Objects found degraded on read waiting to be healed.
+	HealOnRead int `json:"healOnRead"`
+	// Objects written with reduced redundancy waiting to be healed.
+	PartialWrites int `json:"partialWrites"`
+
+	ReadLocks    int `json:"readLocks"`
+	WriteLocks   int `json:"writeLocks"`
+	BlockedLocks int `json:"blockedLocks"`
+
+	// S3 API requests being served.
+	Requests int `json:"requests"`
+}
+
+// Health status of the cluster.
+const (
+	// All the servers and disks are online.
+	HealthStatusOK = "ok"
+	// Some servers or disks are offline, data is still readable and
+	// writable.
+	HealthStatusDegraded = "degraded"
+	// Not enough disks are online to write data.
+	HealthStatusUnavailable = "unavailable"
+)
+
+// ClusterHealth - health summary of all the servers, their disks and
+// the data they store.
+type ClusterHealth struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+
+	Servers      []ServerHealth `json:"servers"`
+	NodesOnline  int            `json:"nodesOnline"`
+	NodesOffline int            `json:"nodesOffline"`
+
+	// Largest clock difference of the servers with the server
+	// answering the health request.
+	MaxClockSkew time.Duration `json:"maxClockSkew"`
+
+	DisksOnline     int `json:"disksOnline"`
+	DisksOffline    int `json:"disksOffline"`
+	DisksUnwritable int `json:"disksUnwritable"`
+	ReadQuorum      int `json:"readQuorum"`
+	WriteQuorum     int `json:"writeQuorum"`
+
+	// Objects waiting to be healed on all the servers.
+	HealBacklog int `json:"healBacklog"`
+
+	ReadLocks    int `json:"readLocks"`
+	WriteLocks   int `json:"writeLocks"`
+	BlockedLocks int `json:"blockedLocks"`
+	Requests     int `json:"requests"`
+
+	// Disk space of the backend.
+	Total int64 `json:"total"`
+	Free  int64 `json:"free"`
+	// Objects and their size as of the last data usage crawl.
+	Objects         uint64    `json:"objects"`
+	Size            uint64    `json:"size"`
+	UsageLastUpdate time.Time `json:"usageLastUpdate"`
+}
+
+// ServiceHealth - Fetches the health summary of the cluster, the
+// servers and disks online, the heal backlog, the locks held, the
+// requests served and the data usage.
+func (adm *AdminClient) ServiceHealth() (ClusterHealth, error) {
+	reqData := requestData{}
+	reqData.queryValues = make(url.Values)
+	reqData.queryValues.Set("service", "")
+	reqData.customHeaders = make(http.Header)
+	reqData.customHeaders.Set(minioAdminOpHeader, "health")
+
+	// Execute GET on /?service to fetch the cluster health.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return ClusterHealth{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ClusterHealth{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ClusterHealth{}, err
+	}
+
+	var health ClusterHealth
+	if err = json.Unmarshal(respBytes, &health); err != nil {
+		return ClusterHealth{}, err
+	}
+	return health, nil
+}
+
 // ServiceRestart - Call Service Restart API to restart a specified Minio server
 func (adm *AdminClient) ServiceRestart() error {
 	//
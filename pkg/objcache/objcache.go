@@ -234,6 +234,30 @@ func (c *Cache) Open(key string, objModTime time.Time) (io.ReadSeeker, error) {
 	return bytes.NewReader(buf.value), nil
 }
 
+// Stats - space accounting of the cache.
+type Stats struct {
+	// Size of the cached entries.
+	Size uint64
+	// Maximum size of all the cached entries.
+	MaxSize uint64
+	// Maximum size of a single entry.
+	MaxEntrySize uint64
+	// Number of cached entries.
+	Entries int
+}
+
+// Stats - returns the space accounting of the cache.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Stats{
+		Size:         c.currentSize,
+		MaxSize:      c.maxSize,
+		MaxEntrySize: c.maxCacheEntrySize,
+		Entries:      len(c.entries),
+	}
+}
+
 // Delete - delete deletes an entry from the cache.
 func (c *Cache) Delete(key string) {
 	c.mutex.Lock()
@@ -284,3 +284,27 @@ func TestStaleEntryPurge(t *testing.T) {
 		t.Errorf("Test case expected to return ErrKeyNotFoundInCache, instead returned %s", err)
 	}
 }
+
+// TestStats - tests the space accounting of the cache.
+func TestStats(t *testing.T) {
+	cache := New(1024, NoExpiry)
+	stats := cache.Stats()
+	if stats.Size != 0 || stats.MaxSize != 1024 || stats.MaxEntrySize != 102 || stats.Entries != 0 {
+		t.Fatalf("Unexpected stats of an empty cache %+v", stats)
+	}
+	w, err := cache.Create("test", 5)
+	if err != nil {
+		t.Fatalf("Test case expected to pass, failed instead %s", err)
+	}
+	w.Write([]byte("Hello"))
+	if err = w.Close(); err != nil {
+		t.Fatalf("Test case expected to pass, failed instead %s", err)
+	}
+	if stats = cache.Stats(); stats.Size != 5 || stats.Entries != 1 {
+		t.Fatalf("Unexpected stats after caching an entry %+v", stats)
+	}
+	cache.Delete("test")
+	if stats = cache.Stats(); stats.Size != 0 || stats.Entries != 0 {
+		t.Fatalf("Unexpected stats after deleting an entry %+v", stats)
+	}
+}